@@ -2,47 +2,105 @@ package main
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 )
 
 const (
 	defaultAnkiConnectURL = "http://localhost:8765"
 	ankiConnectVersion    = 6
+	defaultTimeout        = 30 * time.Second
 )
 
+// defaultActionTimeouts gives actions that are known to run long more time
+// than the general timeout: a full sync, packaging a deck with its media, or
+// uploading a large media file
+var defaultActionTimeouts = map[string]time.Duration{
+	"sync":           5 * time.Minute,
+	"exportPackage":  10 * time.Minute,
+	"importPackage":  10 * time.Minute,
+	"storeMediaFile": 5 * time.Minute,
+}
+
 // AnkiConnect represents a client for communicating with AnkiConnect addon
 type AnkiConnect struct {
 	URL     string
 	Version int
-	client  *http.Client
+	// Key is sent with every request when AnkiConnect is set up to require
+	// an API key
+	Key string
+	// Retry controls how requests are retried when Anki is not reachable
+	Retry RetryPolicy
+	// Timeout is how long a request may take before it is abandoned
+	Timeout time.Duration
+	// ActionTimeouts allows the listed actions to take longer than Timeout
+	ActionTimeouts map[string]time.Duration
+	client         *http.Client
+	// Logger receives the requests and their failures; nil discards them
+	Logger *slog.Logger
+	// limiter queues requests over the rate limit; nil sends them right away
+	limiter *rateLimiter
+	// throttled adds up how long requests waited for the limiter when set
+	throttled *throttleLog
+	// history remembers the changes sent, for undo; nil remembers none
+	history *changeHistory
+	// launcher starts Anki when AnkiConnect refuses the connection; nil
+	// leaves that to the user
+	launcher *ankiLauncher
+	// dryRun records changes instead of sending them when set
+	dryRun *dryRunLog
+	// sanitizer cleans the field HTML of notes before they are sent; nil
+	// sends fields as they are
+	sanitizer *htmlSanitizer
 }
 
 // ankiRequest represents a request to AnkiConnect API
 type ankiRequest struct {
 	Action  string      `json:"action"`
 	Version int         `json:"version"`
+	Key     string      `json:"key,omitempty"`
 	Params  interface{} `json:"params,omitempty"`
 }
 
-// ankiResponse represents a response from AnkiConnect API
+// errAPIKey is returned when AnkiConnect rejects a request for a missing or
+// wrong API key
+var errAPIKey = errors.New("AnkiConnect requires an API key: set ANKI_CONNECT_API_KEY to the apiKey from AnkiConnect's config (Tools > Add-ons > AnkiConnect > Config)")
+
+// ankiResponse represents a response from AnkiConnect API. The result stays
+// raw until it is decoded into the type the caller expects.
 type ankiResponse struct {
-	Result interface{} `json:"result"`
-	Error  string      `json:"error"`
+	Result json.RawMessage `json:"result"`
+	Error  string          `json:"error"`
 }
 
 // NewAnkiConnect creates a new AnkiConnect client with default settings
 func NewAnkiConnect() *AnkiConnect {
+	actionTimeouts := make(map[string]time.Duration, len(defaultActionTimeouts))
+	for action, timeout := range defaultActionTimeouts {
+		actionTimeouts[action] = timeout
+	}
 	return &AnkiConnect{
-		URL:     defaultAnkiConnectURL,
-		Version: ankiConnectVersion,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		URL:            defaultAnkiConnectURL,
+		Version:        ankiConnectVersion,
+		Retry:          defaultRetryPolicy,
+		Timeout:        defaultTimeout,
+		ActionTimeouts: actionTimeouts,
+		client:         &http.Client{},
 	}
 }
 
@@ -53,11 +111,68 @@ func NewAnkiConnectWithURL(url string) *AnkiConnect {
 	return ac
 }
 
-// invoke makes a request to AnkiConnect API
-func (ac *AnkiConnect) invoke(action string, params interface{}) (interface{}, error) {
+// timeout returns how long a request for an action may take: its entry in
+// ActionTimeouts, but never less than the general Timeout
+func (ac *AnkiConnect) timeout(action string) time.Duration {
+	return max(ac.Timeout, ac.ActionTimeouts[action])
+}
+
+// httpClient returns the HTTP client for a request with the action's timeout
+func (ac *AnkiConnect) httpClient(action string) *http.Client {
+	client := *ac.client
+	client.Timeout = ac.timeout(action)
+	return &client
+}
+
+// invoke makes a request to AnkiConnect API and decodes its result into T
+func invoke[T any](ac *AnkiConnect, action string, params interface{}) (T, error) {
+	var result T
+	raw, err := ac.call(action, params)
+	if err != nil {
+		return result, err
+	}
+	if err := decodeResult(raw, &result); err != nil {
+		return result, fmt.Errorf("unexpected %s response: %w", action, err)
+	}
+	return result, nil
+}
+
+// decodeResult decodes a raw AnkiConnect result. A missing result leaves v
+// unchanged, like null does.
+func decodeResult(raw json.RawMessage, v interface{}) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// call makes a request to AnkiConnect API and returns the raw result. In a
+// dry run, changing actions are recorded instead of sent; otherwise the
+// changes made are added to the client's history.
+func (ac *AnkiConnect) call(action string, params interface{}) (json.RawMessage, error) {
+	if ac.dryRun != nil {
+		if changingActions[action] {
+			return ac.dryRun.record(action, params), nil
+		}
+		if action == "multi" {
+			return ac.dryRunMulti(params.(map[string]interface{})["actions"].([]ankiRequest))
+		}
+	}
+	result, err := ac.send(action, params)
+	if err == nil && ac.history != nil {
+		ac.history.record(action, params, result)
+	}
+	return result, err
+}
+
+// send makes a request to AnkiConnect, retrying it according to the retry
+// policy. If Anki is not running and the client has a launcher, Anki is
+// started and the request is sent once more.
+func (ac *AnkiConnect) send(action string, params interface{}) (json.RawMessage, error) {
 	req := ankiRequest{
 		Action:  action,
 		Version: ac.Version,
+		Key:     ac.Key,
 		Params:  params,
 	}
 
@@ -66,62 +181,133 @@ func (ac *AnkiConnect) invoke(action string, params interface{}) (interface{}, e
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	resp, err := ac.client.Post(ac.URL, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to AnkiConnect: %w", err)
+	return ac.sendBody(action, repeatable(action, params), func() (io.Reader, func()) {
+		return bytes.NewReader(jsonData), func() {}
+	})
+}
+
+// requestBody returns the body of one try of a request and a function to
+// call when the try is over. It returns a nil body when the request can't be
+// sent again.
+type requestBody func() (io.Reader, func())
+
+// sendBody is send for a request whose body is made anew for every try, so
+// large bodies can be streamed. canRepeat tells whether the request may be
+// sent again when it might have reached Anki.
+func (ac *AnkiConnect) sendBody(action string, canRepeat bool, body requestBody) (json.RawMessage, error) {
+	logger := ac.logger().With("action", action)
+	client := ac.httpClient(action)
+	var result json.RawMessage
+	var err error
+	post := func(r io.Reader, done func()) {
+		defer done()
+		result, err = ac.post(client, r)
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	body, err := io.ReadAll(resp.Body)
+	start := time.Now()
+	post(body())
+	for retry := 1; err != nil && retry < ac.Retry.Attempts && shouldRetry(canRepeat, err); retry++ {
+		r, done := body()
+		if r == nil {
+			break
+		}
+		wait := ac.Retry.wait(retry)
+		logger.Warn("AnkiConnect request failed, retrying", "url", ac.URL, "attempt", retry, "wait", wait, "error", err)
+		time.Sleep(wait)
+		post(r, done)
+	}
+	if err != nil && ac.launcher != nil && errors.Is(err, syscall.ECONNREFUSED) {
+		if launchErr := ac.launcher.ensure(ac); launchErr != nil {
+			err = fmt.Errorf("%w; %v", err, launchErr)
+		} else if r, done := body(); r != nil {
+			post(r, done)
+		}
+	}
+
+	var urlErr *url.Error
+	switch {
+	case err == nil:
+		logger.Debug("AnkiConnect request", "duration", time.Since(start))
+	case errors.Is(err, errAPIKey):
+		logger.Error("AnkiConnect rejected the API key", "url", ac.URL)
+	case errors.As(err, &urlErr):
+		logger.Error("AnkiConnect is not reachable", "url", ac.URL, "error", err)
+	default:
+		// Errors reported by AnkiConnect, e.g. for an unknown deck, are
+		// returned to the caller and not a problem of the server
+		logger.Debug("AnkiConnect request failed", "duration", time.Since(start), "error", err)
+	}
+	return result, err
+}
+
+// logger returns the logger for the client's requests
+func (ac *AnkiConnect) logger() *slog.Logger {
+	if ac.Logger != nil {
+		return ac.Logger
+	}
+	return slog.New(slog.DiscardHandler)
+}
+
+// post sends an encoded request body to AnkiConnect and returns the raw result
+func (ac *AnkiConnect) post(client *http.Client, body io.Reader) (json.RawMessage, error) {
+	if ac.limiter != nil {
+		waited, done := ac.limiter.wait()
+		defer done()
+		if ac.throttled != nil {
+			ac.throttled.add(waited)
+		}
+	}
+	resp, err := client.Post(ac.URL, "application/json", body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, connectionError(ac.URL, err)
 	}
+	defer func() { _ = resp.Body.Close() }()
 
+	// AnkiConnect answers every request with 200 and a JSON object, so
+	// anything else comes from another program on its port
 	var result ankiResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if resp.StatusCode != http.StatusOK {
+		return nil, &ankiError{kind: kindAddonMissing, err: fmt.Errorf("%s answered with %s instead of AnkiConnect", ac.URL, resp.Status)}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, &ankiError{kind: kindAddonMissing, err: fmt.Errorf("%s didn't answer like AnkiConnect: %w", ac.URL, err)}
 	}
 
 	if result.Error != "" {
-		return nil, fmt.Errorf("AnkiConnect error: %s", result.Error)
+		if isAPIKeyError(result.Error) {
+			return nil, errAPIKey
+		}
+		err := fmt.Errorf("AnkiConnect error: %s", result.Error)
+		if kind := classifyMessage(result.Error); kind != "" {
+			return nil, &ankiError{kind: kind, err: err}
+		}
+		return nil, err
 	}
 
 	return result.Result, nil
 }
 
+// isAPIKeyError reports whether an AnkiConnect error message is about a
+// missing or wrong API key
+func isAPIKeyError(message string) bool {
+	return strings.Contains(strings.ToLower(message), "valid api key must be provided")
+}
+
 // Ping checks if AnkiConnect is available
 func (ac *AnkiConnect) Ping() error {
-	_, err := ac.invoke("version", nil)
+	_, err := ac.call("version", nil)
 	return err
 }
 
 // GetDeckNames returns all deck names in Anki
 func (ac *AnkiConnect) GetDeckNames() ([]string, error) {
-	result, err := ac.invoke("deckNames", nil)
-	if err != nil {
-		return nil, err
-	}
-
-	names, ok := result.([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type")
-	}
-
-	deckNames := make([]string, len(names))
-	for i, name := range names {
-		deckNames[i], ok = name.(string)
-		if !ok {
-			return nil, fmt.Errorf("unexpected deck name type")
-		}
-	}
-
-	return deckNames, nil
+	return invoke[[]string](ac, "deckNames", nil)
 }
 
 // CreateDeck creates a new deck in Anki
 func (ac *AnkiConnect) CreateDeck(name string) error {
 	params := map[string]string{"deck": name}
-	_, err := ac.invoke("createDeck", params)
+	_, err := ac.call("createDeck", params)
 	return err
 }
 
@@ -131,10 +317,28 @@ func (ac *AnkiConnect) DeleteDeck(name string) error {
 		"decks":    []string{name},
 		"cardsToo": true,
 	}
-	_, err := ac.invoke("deleteDecks", params)
+	_, err := ac.call("deleteDecks", params)
 	return err
 }
 
+// ExportPackage exports a deck as an .apkg file. The path is on the machine
+// running Anki. Exporting large decks with media can take a while.
+func (ac *AnkiConnect) ExportPackage(deck, path string, includeSched bool) error {
+	params := map[string]interface{}{
+		"deck":         deck,
+		"path":         path,
+		"includeSched": includeSched,
+	}
+	ok, err := invoke[bool](ac, "exportPackage", params)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("export of deck %s failed", deck)
+	}
+	return nil
+}
+
 // Note represents a note in AnkiConnect format
 type Note struct {
 	DeckName  string                 `json:"deckName"`
@@ -157,43 +361,47 @@ type MediaFile struct {
 
 // AddNote adds a single note to Anki
 func (ac *AnkiConnect) AddNote(note Note) (int64, error) {
+	note.Fields = ac.sanitizeFields(note.Fields)
 	params := map[string]interface{}{"note": note}
-	result, err := ac.invoke("addNote", params)
+	id, err := invoke[int64](ac, "addNote", params)
 	if err != nil {
 		return 0, err
 	}
-
-	// AnkiConnect returns note ID as float64
-	if id, ok := result.(float64); ok {
-		return int64(id), nil
+	if id == 0 {
+		return 0, fmt.Errorf("no note ID returned")
 	}
-
-	return 0, fmt.Errorf("unexpected note ID type")
+	return id, nil
 }
 
-// FindNotes searches for notes matching a query
-func (ac *AnkiConnect) FindNotes(query string) ([]int64, error) {
-	params := map[string]string{"query": query}
-	result, err := ac.invoke("findNotes", params)
+// CanAddNotes checks whether notes could be added without adding them. It
+// returns one error per note, nil for the notes that can be added, e.g.
+// "cannot create note because it is a duplicate".
+func (ac *AnkiConnect) CanAddNotes(notes []Note) ([]error, error) {
+	type detail struct {
+		CanAdd bool   `json:"canAdd"`
+		Error  string `json:"error"`
+	}
+	details, err := invoke[[]detail](ac, "canAddNotesWithErrorDetail", map[string]interface{}{"notes": notes})
 	if err != nil {
 		return nil, err
 	}
-
-	ids, ok := result.([]interface{})
-	if !ok {
+	if len(details) != len(notes) {
 		return nil, fmt.Errorf("unexpected response type")
 	}
 
-	noteIDs := make([]int64, len(ids))
-	for i, id := range ids {
-		if fid, ok := id.(float64); ok {
-			noteIDs[i] = int64(fid)
-		} else {
-			return nil, fmt.Errorf("unexpected note ID type")
+	errs := make([]error, len(details))
+	for i, d := range details {
+		if !d.CanAdd {
+			errs[i] = fmt.Errorf("%s", d.Error)
 		}
 	}
+	return errs, nil
+}
 
-	return noteIDs, nil
+// FindNotes searches for notes matching a query
+func (ac *AnkiConnect) FindNotes(query string) ([]int64, error) {
+	params := map[string]string{"query": query}
+	return invoke[[]int64](ac, "findNotes", params)
 }
 
 // UpdateNoteFields updates fields of an existing note
@@ -201,99 +409,938 @@ func (ac *AnkiConnect) UpdateNoteFields(noteID int64, fields map[string]string)
 	params := map[string]interface{}{
 		"note": map[string]interface{}{
 			"id":     noteID,
-			"fields": fields,
+			"fields": ac.sanitizeFields(fields),
 		},
 	}
-	_, err := ac.invoke("updateNoteFields", params)
+	_, err := ac.call("updateNoteFields", params)
 	return err
 }
 
-// StoreMediaFile stores a media file in Anki's media folder
-func (ac *AnkiConnect) StoreMediaFile(filename string, data []byte) error {
-	// AnkiConnect expects base64 encoded data
-	encodedData := base64.StdEncoding.EncodeToString(data)
+// DeleteNotes deletes notes together with their cards
+func (ac *AnkiConnect) DeleteNotes(noteIDs []int64) error {
+	_, err := ac.call("deleteNotes", map[string]interface{}{"notes": noteIDs})
+	return err
+}
+
+// AddTags adds space-free tags to notes
+func (ac *AnkiConnect) AddTags(noteIDs []int64, tags []string) error {
 	params := map[string]interface{}{
-		"filename": filename,
-		"data":     encodedData,
+		"notes": noteIDs,
+		"tags":  strings.Join(tags, " "),
 	}
-	_, err := ac.invoke("storeMediaFile", params)
+	_, err := ac.call("addTags", params)
 	return err
 }
 
+// RemoveTags removes space-free tags from notes
+func (ac *AnkiConnect) RemoveTags(noteIDs []int64, tags []string) error {
+	params := map[string]interface{}{
+		"notes": noteIDs,
+		"tags":  strings.Join(tags, " "),
+	}
+	_, err := ac.call("removeTags", params)
+	return err
+}
+
+// ReplaceTags replaces a tag with another on notes
+func (ac *AnkiConnect) ReplaceTags(noteIDs []int64, tag, replacement string) error {
+	params := map[string]interface{}{
+		"notes":            noteIDs,
+		"tag_to_replace":   tag,
+		"replace_with_tag": replacement,
+	}
+	_, err := ac.call("replaceTags", params)
+	return err
+}
+
+// GetTags returns all tags in the collection
+func (ac *AnkiConnect) GetTags() ([]string, error) {
+	return invoke[[]string](ac, "getTags", nil)
+}
+
+// ClearUnusedTags removes tags no note uses from the collection's tag list
+func (ac *AnkiConnect) ClearUnusedTags() error {
+	_, err := ac.call("clearUnusedTags", nil)
+	return err
+}
+
+// GetCollectionStatsHTML returns Anki's statistics report for the whole collection as HTML
+func (ac *AnkiConnect) GetCollectionStatsHTML() (string, error) {
+	params := map[string]interface{}{
+		"wholeCollection": true,
+	}
+	return invoke[string](ac, "getCollectionStatsHTML", params)
+}
+
+// DayReviews is the number of reviews done on a day
+type DayReviews struct {
+	Date    string // YYYY-MM-DD
+	Reviews int
+}
+
+// UnmarshalJSON decodes the [date, reviews] pairs AnkiConnect returns
+func (d *DayReviews) UnmarshalJSON(data []byte) error {
+	pair := []interface{}{&d.Date, &d.Reviews}
+	return json.Unmarshal(data, &pair)
+}
+
+// GetNumCardsReviewedByDay returns the number of reviews per day for every
+// day with reviews, newest first
+func (ac *AnkiConnect) GetNumCardsReviewedByDay() ([]DayReviews, error) {
+	return invoke[[]DayReviews](ac, "getNumCardsReviewedByDay", nil)
+}
+
+// StoredMedia describes a media file after it was stored in Anki
+type StoredMedia struct {
+	// Filename is the name Anki stored the file under. It differs from the
+	// requested name when a different file with that name already existed.
+	Filename string
+	Size     int64
+	SHA256   string
+}
+
+// StoreMediaFile stores a media file in Anki's media folder
+func (ac *AnkiConnect) StoreMediaFile(filename string, data []byte) (StoredMedia, error) {
+	return ac.StoreMediaFileFrom(filename, bytes.NewReader(data))
+}
+
+// StoreMediaFileFrom stores media read from r in Anki's media folder. The data
+// is base64-encoded while it is streamed into the request body, so large files
+// are never held in memory, raw or encoded. Existing files are never
+// overwritten: Anki keeps both and renames the new one. The request is only
+// retried when r can seek back to the start of the data.
+func (ac *AnkiConnect) StoreMediaFileFrom(filename string, r io.Reader) (StoredMedia, error) {
+	if ac.dryRun != nil {
+		return ac.dryRunStoreMedia(filename, r)
+	}
+	name, err := json.Marshal(filename)
+	if err != nil {
+		return StoredMedia{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	key, _ := json.Marshal(ac.Key)
+
+	seeker, _ := r.(io.Seeker)
+	var offset int64
+	if seeker != nil {
+		if offset, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			seeker = nil
+		}
+	}
+
+	digest := sha256.New()
+	counter := &countingWriter{}
+	source := &readRecorder{}
+	tries := 0
+	body := func() (io.Reader, func()) {
+		if tries > 0 {
+			if seeker == nil {
+				return nil, nil
+			}
+			if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+				return nil, nil
+			}
+		}
+		tries++
+		digest.Reset()
+		counter.n = 0
+		*source = readRecorder{r: io.TeeReader(r, io.MultiWriter(digest, counter))}
+
+		pr, pw := io.Pipe()
+		finished := make(chan struct{})
+		go func() {
+			defer close(finished)
+			_, err := fmt.Fprintf(pw, `{"action":"storeMediaFile","version":%d,"key":%s,"params":{"filename":%s,"deleteExisting":false,"data":"`, ac.Version, key, name)
+			if err == nil {
+				// AnkiConnect expects base64 encoded data
+				enc := base64.NewEncoder(base64.StdEncoding, pw)
+				if _, err = io.Copy(enc, source); err == nil {
+					err = enc.Close()
+				}
+			}
+			if err == nil {
+				_, err = io.WriteString(pw, `"}}`)
+			}
+			_ = pw.CloseWithError(err)
+		}()
+		// Closing the pipe stops the encoding if the request ended early
+		return pr, func() {
+			_ = pr.Close()
+			<-finished
+		}
+	}
+
+	result, err := ac.sendBody("storeMediaFile", repeatable("storeMediaFile", nil), body)
+	// A failed read also fails the request, so report its cause first;
+	// errors of writing into the closed pipe only follow failed requests
+	if source.err != nil {
+		return StoredMedia{}, fmt.Errorf("failed to read media: %w", source.err)
+	}
+	if err != nil {
+		return StoredMedia{}, err
+	}
+	if ac.history != nil {
+		ac.history.record("storeMediaFile", map[string]interface{}{"filename": filename}, result)
+	}
+
+	var stored string
+	if err := decodeResult(result, &stored); err != nil || stored == "" {
+		return StoredMedia{}, fmt.Errorf("unexpected filename type")
+	}
+
+	return StoredMedia{
+		Filename: stored,
+		Size:     counter.n,
+		SHA256:   hex.EncodeToString(digest.Sum(nil)),
+	}, nil
+}
+
+// readRecorder reads from r and keeps the first error other than io.EOF, to
+// tell failed reads from failed writes
+type readRecorder struct {
+	r   io.Reader
+	err error
+}
+
+func (rr *readRecorder) Read(p []byte) (int, error) {
+	n, err := rr.r.Read(p)
+	if err != nil && err != io.EOF && rr.err == nil {
+		rr.err = err
+	}
+	return n, err
+}
+
+// countingWriter counts the bytes written to it
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// GetMediaDirPath returns the path of Anki's media folder
+func (ac *AnkiConnect) GetMediaDirPath() (string, error) {
+	return invoke[string](ac, "getMediaDirPath", nil)
+}
+
+// RetrieveMediaFile returns the base64-encoded contents of a media file and
+// whether the file exists
+func (ac *AnkiConnect) RetrieveMediaFile(filename string) (string, bool, error) {
+	result, err := invoke[interface{}](ac, "retrieveMediaFile", map[string]string{"filename": filename})
+	if err != nil {
+		return "", false, err
+	}
+
+	// AnkiConnect returns false for missing files
+	data, ok := result.(string)
+	return data, ok, nil
+}
+
+// GetMediaFilesNames returns the names of the media files matching a glob
+// pattern such as *.mp3
+func (ac *AnkiConnect) GetMediaFilesNames(pattern string) ([]string, error) {
+	names, err := invoke[[]string](ac, "getMediaFilesNames", map[string]string{"pattern": pattern})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// DeleteMediaFile deletes a file from Anki's media folder. Anki moves it to
+// the media trash, from where Tools > Check Media can restore it.
+func (ac *AnkiConnect) DeleteMediaFile(filename string) error {
+	_, err := ac.call("deleteMediaFile", map[string]string{"filename": filename})
+	return err
+}
+
+// DeleteMediaFiles deletes several files from Anki's media folder in one
+// request
+func (ac *AnkiConnect) DeleteMediaFiles(filenames []string) error {
+	if len(filenames) == 0 {
+		return nil
+	}
+	b := ac.newBatch()
+	results := make([]json.RawMessage, len(filenames))
+	for i, name := range filenames {
+		b.add("deleteMediaFile", map[string]string{"filename": name}, &results[i])
+	}
+	return b.send()
+}
+
+// VerifyMedia checks that a stored media file has the expected size and
+// content. The media folder is read directly when it is reachable from this
+// machine; otherwise the file is downloaded through AnkiConnect.
+func (ac *AnkiConnect) VerifyMedia(media StoredMedia) error {
+	// Media stored in a dry run never reaches the media folder
+	if ac.dryRun != nil {
+		return nil
+	}
+	var (
+		size int64
+		sum  string
+	)
+
+	dir, err := ac.GetMediaDirPath()
+	if err != nil {
+		return err
+	}
+
+	if file, err := os.Open(filepath.Join(dir, media.Filename)); err == nil {
+		hash := sha256.New()
+		size, err = io.Copy(hash, file)
+		_ = file.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", media.Filename, err)
+		}
+		sum = hex.EncodeToString(hash.Sum(nil))
+	} else {
+		data, found, err := ac.RetrieveMediaFile(media.Filename)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("%s is missing from the media folder", media.Filename)
+		}
+		hash := sha256.New()
+		size, err = io.Copy(hash, base64.NewDecoder(base64.StdEncoding, strings.NewReader(data)))
+		if err != nil {
+			return fmt.Errorf("failed to decode %s: %w", media.Filename, err)
+		}
+		sum = hex.EncodeToString(hash.Sum(nil))
+	}
+
+	if size != media.Size {
+		return fmt.Errorf("%s has %d bytes, expected %d", media.Filename, size, media.Size)
+	}
+	if sum != media.SHA256 {
+		return fmt.Errorf("%s content does not match the uploaded file", media.Filename)
+	}
+	return nil
+}
+
 // Sync triggers Anki to sync with AnkiWeb
 func (ac *AnkiConnect) Sync() error {
-	_, err := ac.invoke("sync", nil)
+	_, err := ac.call("sync", nil)
 	return err
 }
 
+// NoteField is the value of a note field with its position in the note type
+type NoteField struct {
+	Value string `json:"value"`
+	Order int    `json:"order"`
+}
+
+// NoteInfo is a note as AnkiConnect's notesInfo describes it. Unknown notes
+// are returned with a zero NoteID.
+type NoteInfo struct {
+	NoteID    int64                `json:"noteId"`
+	ModelName string               `json:"modelName"`
+	Tags      []string             `json:"tags"`
+	Fields    map[string]NoteField `json:"fields"`
+	Cards     []int64              `json:"cards"`
+	Mod       int64                `json:"mod"` // Last change, in seconds since the epoch
+}
+
 // GetNotesInfo retrieves detailed information about notes
-func (ac *AnkiConnect) GetNotesInfo(noteIDs []int64) ([]map[string]interface{}, error) {
+func (ac *AnkiConnect) GetNotesInfo(noteIDs []int64) ([]NoteInfo, error) {
 	params := map[string]interface{}{"notes": noteIDs}
-	result, err := ac.invoke("notesInfo", params)
-	if err != nil {
-		return nil, err
+	return invoke[[]NoteInfo](ac, "notesInfo", params)
+}
+
+// FindNotesInfo returns the notes matching a query in a single request,
+// instead of findNotes followed by notesInfo
+func (ac *AnkiConnect) FindNotesInfo(query string) ([]NoteInfo, error) {
+	return invoke[[]NoteInfo](ac, "notesInfo", map[string]string{"query": query})
+}
+
+// SearchNotes returns the notes matching a query and their matching cards.
+// The notes and the IDs of the matching cards come from one multi request,
+// so a search takes two round trips instead of four for findNotes,
+// notesInfo, findCards and cardsInfo. Cards are returned in search order.
+func (ac *AnkiConnect) SearchNotes(query string) ([]NoteInfo, []CardInfo, error) {
+	var (
+		notes   []NoteInfo
+		cardIDs []int64
+	)
+	b := ac.newBatch()
+	b.add("notesInfo", map[string]string{"query": query}, &notes)
+	b.add("findCards", map[string]string{"query": query}, &cardIDs)
+	if err := b.send(); err != nil {
+		return nil, nil, err
+	}
+	if len(cardIDs) == 0 {
+		return notes, nil, nil
 	}
 
-	notes, ok := result.([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type")
+	cards, err := ac.GetCardsInfo(cardIDs)
+	if err != nil {
+		return nil, nil, err
 	}
+	return notes, cards, nil
+}
 
-	notesInfo := make([]map[string]interface{}, len(notes))
-	for i, note := range notes {
-		noteMap, ok := note.(map[string]interface{})
-		if !ok {
-			return nil, fmt.Errorf("unexpected note type")
-		}
-		notesInfo[i] = noteMap
+// SearchNotesByID returns the notes with the given IDs and their cards that
+// match a query, e.g. for a page of the notes FindNotes found. Like
+// SearchNotes it takes two round trips, but only fetches the given notes.
+func (ac *AnkiConnect) SearchNotesByID(query string, noteIDs []int64) ([]NoteInfo, []CardInfo, error) {
+	ids := make([]string, len(noteIDs))
+	for i, id := range noteIDs {
+		ids[i] = strconv.FormatInt(id, 10)
+	}
+	var (
+		notes   []NoteInfo
+		cardIDs []int64
+	)
+	b := ac.newBatch()
+	b.add("notesInfo", map[string]interface{}{"notes": noteIDs}, &notes)
+	b.add("findCards", map[string]string{"query": fmt.Sprintf("(%s) nid:%s", query, strings.Join(ids, ","))}, &cardIDs)
+	if err := b.send(); err != nil {
+		return nil, nil, err
+	}
+	if len(cardIDs) == 0 {
+		return notes, nil, nil
 	}
 
-	return notesInfo, nil
+	cards, err := ac.GetCardsInfo(cardIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return notes, cards, nil
 }
 
 // GetModelNames returns all model names in Anki
 func (ac *AnkiConnect) GetModelNames() ([]string, error) {
-	result, err := ac.invoke("modelNames", nil)
+	return invoke[[]string](ac, "modelNames", nil)
+}
+
+// GetModelFieldNames returns field names for a given model
+func (ac *AnkiConnect) GetModelFieldNames(modelName string) ([]string, error) {
+	params := map[string]string{"modelName": modelName}
+	return invoke[[]string](ac, "modelFieldNames", params)
+}
+
+// CardTemplate is a card type of a note type (model)
+type CardTemplate struct {
+	Name  string `json:"Name"`
+	Front string `json:"Front"`
+	Back  string `json:"Back"`
+}
+
+// CreateModel creates a standard (non-cloze) note type
+func (ac *AnkiConnect) CreateModel(name string, fields []string, templates []CardTemplate, css string) error {
+	params := map[string]interface{}{
+		"modelName":     name,
+		"inOrderFields": fields,
+		"cardTemplates": templates,
+		"css":           css,
+	}
+	_, err := ac.call("createModel", params)
+	return err
+}
+
+// GetModelTemplates returns the card templates of a note type, sorted by name
+func (ac *AnkiConnect) GetModelTemplates(modelName string) ([]CardTemplate, error) {
+	params := map[string]string{"modelName": modelName}
+	templates, err := invoke[map[string]CardTemplate](ac, "modelTemplates", params)
 	if err != nil {
 		return nil, err
 	}
 
-	names, ok := result.([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type")
+	list := make([]CardTemplate, 0, len(templates))
+	for name, template := range templates {
+		template.Name = name
+		list = append(list, template)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// UpdateModelTemplates changes card templates of a note type. Each template
+// maps "Front" and/or "Back" to the new HTML; sides not given are kept.
+func (ac *AnkiConnect) UpdateModelTemplates(modelName string, templates map[string]map[string]string) error {
+	params := map[string]interface{}{
+		"model": map[string]interface{}{
+			"name":      modelName,
+			"templates": templates,
+		},
+	}
+	_, err := ac.call("updateModelTemplates", params)
+	return err
+}
+
+// GetModelStyling returns the CSS shared by the card templates of a note type
+func (ac *AnkiConnect) GetModelStyling(modelName string) (string, error) {
+	params := map[string]string{"modelName": modelName}
+	styling, err := invoke[struct {
+		CSS string `json:"css"`
+	}](ac, "modelStyling", params)
+	return styling.CSS, err
+}
+
+// UpdateModelStyling replaces the CSS of a note type
+func (ac *AnkiConnect) UpdateModelStyling(modelName, css string) error {
+	params := map[string]interface{}{
+		"model": map[string]interface{}{
+			"name": modelName,
+			"css":  css,
+		},
+	}
+	_, err := ac.call("updateModelStyling", params)
+	return err
+}
+
+// FindAndReplaceInModels replaces text in the card templates and styling of
+// a note type, or of every note type when modelName is empty. front, back and
+// css choose where to replace. It returns the number of note types changed.
+func (ac *AnkiConnect) FindAndReplaceInModels(modelName, find, replace string, front, back, css bool) (int, error) {
+	params := map[string]interface{}{
+		"modelName":   modelName,
+		"findText":    find,
+		"replaceText": replace,
+		"front":       front,
+		"back":        back,
+		"css":         css,
 	}
+	return invoke[int](ac, "findAndReplaceInModels", params)
+}
 
-	modelNames := make([]string, len(names))
-	for i, name := range names {
-		modelNames[i], ok = name.(string)
-		if !ok {
-			return nil, fmt.Errorf("unexpected model name type")
+// RenameModelField renames a field of a note type. This is a schema change
+// that forces a full sync.
+func (ac *AnkiConnect) RenameModelField(modelName, oldName, newName string) error {
+	params := map[string]string{
+		"modelName":    modelName,
+		"oldFieldName": oldName,
+		"newFieldName": newName,
+	}
+	_, err := ac.call("modelFieldRename", params)
+	return err
+}
+
+// UpdateNoteModel switches a note to another note type and sets its fields
+// and tags. Fields left out are empty afterwards and the tags replace the
+// note's tags. The cards keep their card type by position.
+func (ac *AnkiConnect) UpdateNoteModel(noteID int64, modelName string, fields map[string]string, tags []string) error {
+	_, err := ac.call("updateNoteModel", noteModelParams(noteID, modelName, ac.sanitizeFields(fields), tags))
+	return err
+}
+
+// noteModelParams builds the parameters of updateNoteModel
+func noteModelParams(noteID int64, modelName string, fields map[string]string, tags []string) map[string]interface{} {
+	if tags == nil {
+		tags = []string{}
+	}
+	return map[string]interface{}{
+		"note": map[string]interface{}{
+			"id":        noteID,
+			"modelName": modelName,
+			"fields":    fields,
+			"tags":      tags,
+		},
+	}
+}
+
+// CheckDatabase starts Anki's "Check Database" routine. Anki runs it in the
+// background and shows its report in its window, so this returns before the
+// check is done.
+func (ac *AnkiConnect) CheckDatabase() error {
+	_, err := ac.call("guiCheckDatabase", nil)
+	return err
+}
+
+// FindCards searches for cards matching a query
+func (ac *AnkiConnect) FindCards(query string) ([]int64, error) {
+	params := map[string]string{"query": query}
+	return invoke[[]int64](ac, "findCards", params)
+}
+
+// CardInfo is a card as AnkiConnect's cardsInfo describes it, including its
+// scheduling data. Unknown cards are returned with a zero CardID.
+type CardInfo struct {
+	CardID     int64                `json:"cardId"`
+	NoteID     int64                `json:"note"`
+	DeckName   string               `json:"deckName"`
+	ModelName  string               `json:"modelName"`
+	Question   string               `json:"question"`
+	Answer     string               `json:"answer"`
+	Fields     map[string]NoteField `json:"fields"`
+	FieldOrder int                  `json:"fieldOrder"`
+	Ord        int                  `json:"ord"` // Card template, counting from 0
+	CSS        string               `json:"css"`
+	Factor     int                  `json:"factor"`   // Ease factor in permille
+	Interval   int                  `json:"interval"` // Days
+	Type       int                  `json:"type"`     // 0 = new, 1 = learning, 2 = review, 3 = relearning
+	Queue      int                  `json:"queue"`    // -3/-2 = buried, -1 = suspended, 0 = new, 1/3 = learning, 2 = review, 4 = preview
+	// Due is the position of new cards, a timestamp in seconds for learning
+	// cards, and a day number counted from the collection's creation for
+	// review cards
+	Due    int64 `json:"due"`
+	Reps   int   `json:"reps"`
+	Lapses int   `json:"lapses"`
+	Left   int   `json:"left"` // Learning steps left today * 1000 + steps left in total
+	Mod    int64 `json:"mod"`  // Last change, in seconds since the epoch
+}
+
+// GetCardsInfo retrieves detailed information about cards, including scheduling data
+func (ac *AnkiConnect) GetCardsInfo(cardIDs []int64) ([]CardInfo, error) {
+	params := map[string]interface{}{"cards": cardIDs}
+	return invoke[[]CardInfo](ac, "cardsInfo", params)
+}
+
+// DeckStats holds the card counts of a deck, as shown in Anki's deck list
+type DeckStats struct {
+	DeckID      int64  `json:"deck_id"`
+	Name        string `json:"name"`
+	NewCount    int    `json:"new_count"`     // New cards to study today
+	LearnCount  int    `json:"learn_count"`   // Learning cards due today
+	ReviewCount int    `json:"review_count"`  // Review cards due today
+	TotalInDeck int    `json:"total_in_deck"` // Cards in the deck itself, without its subdecks
+}
+
+// GetDeckStats returns the card counts of decks, keyed by deck name. Today's
+// counts respect the decks' daily limits.
+func (ac *AnkiConnect) GetDeckStats(decks []string) (map[string]DeckStats, error) {
+	params := map[string]interface{}{"decks": decks}
+	byID, err := invoke[map[string]DeckStats](ac, "getDeckStats", params)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]DeckStats, len(byID))
+	for _, s := range byID {
+		stats[s.Name] = s
+	}
+	return stats, nil
+}
+
+// ReviewEntry represents a single entry of Anki's review log
+type ReviewEntry struct {
+	ReviewTime       int64 // Review timestamp in milliseconds, also the review ID
+	CardID           int64
+	USN              int64
+	ButtonPressed    int   // 1 = Again, 2 = Hard, 3 = Good, 4 = Easy
+	NewInterval      int64 // Days if positive, seconds if negative
+	PreviousInterval int64 // Days if positive, seconds if negative
+	NewFactor        int64 // Ease factor in permille
+	ReviewDuration   int64 // Milliseconds spent on the review
+	ReviewType       int   // 0 = Learn, 1 = Review, 2 = Relearn, 3 = Filtered, 4 = Manual
+}
+
+// CardReviews returns all review log entries of a deck with an ID (timestamp in
+// milliseconds) greater than startID. Subdecks are not included.
+func (ac *AnkiConnect) CardReviews(deck string, startID int64) ([]ReviewEntry, error) {
+	params := map[string]interface{}{
+		"deck":    deck,
+		"startID": startID,
+	}
+	rows, err := invoke[[][]int64](ac, "cardReviews", params)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]ReviewEntry, len(rows))
+	for i, nums := range rows {
+		if len(nums) < 9 {
+			return nil, fmt.Errorf("unexpected review entry")
+		}
+		entries[i] = ReviewEntry{
+			ReviewTime:       nums[0],
+			CardID:           nums[1],
+			USN:              nums[2],
+			ButtonPressed:    int(nums[3]),
+			NewInterval:      nums[4],
+			PreviousInterval: nums[5],
+			NewFactor:        nums[6],
+			ReviewDuration:   nums[7],
+			ReviewType:       int(nums[8]),
 		}
 	}
 
-	return modelNames, nil
+	return entries, nil
 }
 
-// GetModelFieldNames returns field names for a given model
-func (ac *AnkiConnect) GetModelFieldNames(modelName string) ([]string, error) {
-	params := map[string]string{"modelName": modelName}
-	result, err := ac.invoke("modelFieldNames", params)
+// GetReviewsOfCards returns the review log entries of the given cards, keyed by card ID
+func (ac *AnkiConnect) GetReviewsOfCards(cardIDs []int64) (map[int64][]ReviewEntry, error) {
+	type logEntry struct {
+		ID      int64 `json:"id"`
+		USN     int64 `json:"usn"`
+		Ease    int   `json:"ease"`
+		Ivl     int64 `json:"ivl"`
+		LastIvl int64 `json:"lastIvl"`
+		Factor  int64 `json:"factor"`
+		Time    int64 `json:"time"`
+		Type    int   `json:"type"`
+	}
+	params := map[string]interface{}{"cards": cardIDs}
+	byCard, err := invoke[map[int64][]logEntry](ac, "getReviewsOfCards", params)
 	if err != nil {
 		return nil, err
 	}
 
-	names, ok := result.([]interface{})
+	reviews := make(map[int64][]ReviewEntry, len(byCard))
+	for cardID, log := range byCard {
+		entries := make([]ReviewEntry, len(log))
+		for i, e := range log {
+			entries[i] = ReviewEntry{
+				ReviewTime:       e.ID,
+				CardID:           cardID,
+				USN:              e.USN,
+				ButtonPressed:    e.Ease,
+				NewInterval:      e.Ivl,
+				PreviousInterval: e.LastIvl,
+				NewFactor:        e.Factor,
+				ReviewDuration:   e.Time,
+				ReviewType:       e.Type,
+			}
+		}
+		reviews[cardID] = entries
+	}
+
+	return reviews, nil
+}
+
+// InsertReviews inserts entries into Anki's review log. Each entry's ReviewTime
+// is used as the review ID and must be unique.
+func (ac *AnkiConnect) InsertReviews(entries []ReviewEntry) error {
+	rows := make([][]int64, len(entries))
+	for i, e := range entries {
+		rows[i] = []int64{
+			e.ReviewTime,
+			e.CardID,
+			e.USN,
+			int64(e.ButtonPressed),
+			e.NewInterval,
+			e.PreviousInterval,
+			e.NewFactor,
+			e.ReviewDuration,
+			int64(e.ReviewType),
+		}
+	}
+	params := map[string]interface{}{"reviews": rows}
+	_, err := ac.call("insertReviews", params)
+	return err
+}
+
+// GetDeckConfig returns the options group (configuration) used by a deck. It
+// is kept as a generic object, so saving it back preserves every option.
+func (ac *AnkiConnect) GetDeckConfig(deck string) (map[string]interface{}, error) {
+	params := map[string]string{"deck": deck}
+	result, err := invoke[interface{}](ac, "getDeckConfig", params)
+	if err != nil {
+		return nil, err
+	}
+
+	// AnkiConnect returns false for unknown decks
+	if found, ok := result.(bool); ok && !found {
+		return nil, fmt.Errorf("deck %q not found", deck)
+	}
+
+	config, ok := result.(map[string]interface{})
 	if !ok {
 		return nil, fmt.Errorf("unexpected response type")
 	}
 
-	fieldNames := make([]string, len(names))
-	for i, name := range names {
-		fieldNames[i], ok = name.(string)
-		if !ok {
-			return nil, fmt.Errorf("unexpected field name type")
+	return config, nil
+}
+
+// GetDeckConfigs returns the options group of each given deck, fetched in a
+// single multi request. Unknown decks are left out of the result.
+func (ac *AnkiConnect) GetDeckConfigs(decks []string) (map[string]map[string]interface{}, error) {
+	actions := make([]ankiRequest, len(decks))
+	for i, deck := range decks {
+		actions[i] = ac.action("getDeckConfig", map[string]string{"deck": deck})
+	}
+
+	responses, err := ac.multi(actions)
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make(map[string]map[string]interface{}, len(decks))
+	for i, resp := range responses {
+		if resp.Error != "" {
+			return nil, fmt.Errorf("AnkiConnect error: %s", resp.Error)
+		}
+		// Unknown decks return false, which is not an object
+		var config map[string]interface{}
+		if decodeResult(resp.Result, &config) == nil && config != nil {
+			configs[decks[i]] = config
 		}
 	}
 
-	return fieldNames, nil
+	return configs, nil
+}
+
+// errDeckConfigTarget is returned by SetDeckConfigID and RemoveDeckConfigID
+// when AnkiConnect reports that the options group or a deck doesn't exist
+var errDeckConfigTarget = errors.New("does not exist")
+
+// SetDeckConfigID assigns the options group with the given ID to decks
+func (ac *AnkiConnect) SetDeckConfigID(decks []string, configID int64) error {
+	params := map[string]interface{}{
+		"decks":    decks,
+		"configId": configID,
+	}
+	ok, err := invoke[bool](ac, "setDeckConfigId", params)
+	if err != nil {
+		return err
+	}
+
+	// AnkiConnect returns false when the group or a deck doesn't exist
+	if !ok {
+		return fmt.Errorf("options group %d or one of the decks %w", configID, errDeckConfigTarget)
+	}
+	return nil
+}
+
+// CloneDeckConfigID creates a new options group copied from an existing one
+// and returns its ID
+func (ac *AnkiConnect) CloneDeckConfigID(name string, cloneFrom int64) (int64, error) {
+	params := map[string]interface{}{
+		"name":      name,
+		"cloneFrom": cloneFrom,
+	}
+	result, err := invoke[interface{}](ac, "cloneDeckConfigId", params)
+	if err != nil {
+		return 0, err
+	}
+
+	// AnkiConnect returns false when the source group doesn't exist
+	id, ok := result.(float64)
+	if !ok {
+		return 0, fmt.Errorf("options group %d does not exist", cloneFrom)
+	}
+	return int64(id), nil
+}
+
+// SaveDeckConfig saves changes to an options group
+func (ac *AnkiConnect) SaveDeckConfig(config map[string]interface{}) error {
+	ok, err := invoke[bool](ac, "saveDeckConfig", map[string]interface{}{"config": config})
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("options group %v does not exist", config["id"])
+	}
+	return nil
+}
+
+// RemoveDeckConfigID deletes an options group; decks using it fall back to
+// the default group
+func (ac *AnkiConnect) RemoveDeckConfigID(configID int64) error {
+	ok, err := invoke[bool](ac, "removeDeckConfigId", map[string]interface{}{"configId": configID})
+	if err != nil {
+		return err
+	}
+
+	// AnkiConnect returns false for unknown groups and the default group
+	if !ok {
+		return fmt.Errorf("options group %d %w", configID, errDeckConfigTarget)
+	}
+	return nil
+}
+
+// SuspendCards suspends cards and reports whether any card changed
+func (ac *AnkiConnect) SuspendCards(cardIDs []int64) (bool, error) {
+	return invoke[bool](ac, "suspend", map[string]interface{}{"cards": cardIDs})
+}
+
+// UnsuspendCards unsuspends cards and reports whether any card changed
+func (ac *AnkiConnect) UnsuspendCards(cardIDs []int64) (bool, error) {
+	return invoke[bool](ac, "unsuspend", map[string]interface{}{"cards": cardIDs})
+}
+
+// ForgetCards resets cards to new, discarding their scheduling
+func (ac *AnkiConnect) ForgetCards(cardIDs []int64) error {
+	_, err := ac.call("forgetCards", map[string]interface{}{"cards": cardIDs})
+	return err
+}
+
+// RelearnCards puts cards back into relearning, as if they had been forgotten
+// in a review
+func (ac *AnkiConnect) RelearnCards(cardIDs []int64) error {
+	_, err := ac.call("relearnCards", map[string]interface{}{"cards": cardIDs})
+	return err
+}
+
+// SetDueDate makes cards due in the given number of days. days is a number,
+// or a range such as "3-7" to spread the cards randomly over it; a trailing
+// "!" also sets the cards' intervals to match.
+func (ac *AnkiConnect) SetDueDate(cardIDs []int64, days string) error {
+	params := map[string]interface{}{
+		"cards": cardIDs,
+		"days":  days,
+	}
+	ok, err := invoke[bool](ac, "setDueDate", params)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("invalid due date %q", days)
+	}
+	return nil
+}
+
+// ChangeDeck moves cards to a deck, creating the deck if it doesn't exist
+func (ac *AnkiConnect) ChangeDeck(cardIDs []int64, deck string) error {
+	params := map[string]interface{}{
+		"cards": cardIDs,
+		"deck":  deck,
+	}
+	_, err := ac.call("changeDeck", params)
+	return err
+}
+
+// GuiDeckReview opens the review screen of a deck in the Anki window
+func (ac *AnkiConnect) GuiDeckReview(deck string) (bool, error) {
+	return invoke[bool](ac, "guiDeckReview", map[string]string{"name": deck})
+}
+
+// CurrentCard is the card shown in the Anki review screen
+type CurrentCard struct {
+	CardInfo
+	// Buttons are the answer buttons offered, from 1 (Again) to 4 (Easy)
+	Buttons []int `json:"buttons"`
+	// NextReviews are the button labels, such as "<10m" or "4d"
+	NextReviews []string `json:"nextReviews"`
+}
+
+// GuiCurrentCard returns the card shown in the Anki review screen, or nil when
+// no review is in progress
+func (ac *AnkiConnect) GuiCurrentCard() (*CurrentCard, error) {
+	return invoke[*CurrentCard](ac, "guiCurrentCard", nil)
+}
+
+// GuiShowAnswer reveals the answer of the card in the review screen
+func (ac *AnkiConnect) GuiShowAnswer() (bool, error) {
+	return invoke[bool](ac, "guiShowAnswer", nil)
+}
+
+// GuiAnswerCard answers the card in the review screen with an ease from 1
+// (Again) to 4 (Easy). The answer must be shown first.
+func (ac *AnkiConnect) GuiAnswerCard(ease int) (bool, error) {
+	return invoke[bool](ac, "guiAnswerCard", map[string]int{"ease": ease})
+}
+
+// GuiAddCards opens Anki's Add dialog filled in with a note, which is only
+// added once the user confirms it there. It returns the ID the note would
+// get.
+func (ac *AnkiConnect) GuiAddCards(note Note) (int64, error) {
+	note.Fields = ac.sanitizeFields(note.Fields)
+	return invoke[int64](ac, "guiAddCards", map[string]interface{}{"note": note})
+}
+
+// GuiUndo undoes the last operation in Anki, as with Edit > Undo. It reports
+// false when there is nothing to undo.
+func (ac *AnkiConnect) GuiUndo() (bool, error) {
+	return invoke[bool](ac, "guiUndo", nil)
+}
+
+// GuiBrowse opens Anki's card browser with a search and returns the IDs of
+// the cards it shows
+func (ac *AnkiConnect) GuiBrowse(query string) ([]int64, error) {
+	return invoke[[]int64](ac, "guiBrowse", map[string]string{"query": query})
 }
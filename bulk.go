@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	// bulkThreshold is the number of notes above which operations are batched;
+	// smaller operations send one request per note
+	bulkThreshold = 10
+	// bulkChunkSize is the number of notes sent in a single batched request
+	bulkChunkSize = 100
+	// bulkConcurrency is the maximum number of batched requests in flight, kept
+	// low so large imports don't freeze the Anki window
+	bulkConcurrency = 4
+)
+
+// errNotSent is the outcome of the items of a bulk operation that were left
+// out because the request was cancelled
+var errNotSent = errors.New("not sent: the request was cancelled")
+
+// NoteResult is the outcome of adding one note in a bulk operation
+type NoteResult struct {
+	ID  int64
+	Err error
+}
+
+// NoteUpdate is a field update for one existing note
+type NoteUpdate struct {
+	ID     int64
+	Fields map[string]string
+}
+
+// AddNotes adds several notes to Anki and reports the outcome of each one, in
+// input order. The notes are checked with CanAddNotes first, so duplicates are
+// reported without being sent; if the check itself fails every note is sent.
+// Up to bulkThreshold notes are added one request at a time; larger sets are
+// split into chunks sent as multi requests in parallel, so one failing note
+// (e.g. a duplicate) does not abort the rest of the import. Once ctx ends, no
+// further notes are sent and their outcome is errNotSent.
+func (ac *AnkiConnect) AddNotes(ctx context.Context, notes []Note) []NoteResult {
+	if ac.sanitizer != nil {
+		clean := make([]Note, len(notes))
+		for i, note := range notes {
+			note.Fields = ac.sanitizeFields(note.Fields)
+			clean[i] = note
+		}
+		notes = clean
+	}
+	results := make([]NoteResult, len(notes))
+	var pending []int
+	if errs, err := ac.CanAddNotes(notes); err == nil {
+		for i, err := range errs {
+			if err != nil {
+				results[i].Err = fmt.Errorf("AnkiConnect error: %w", err)
+			} else {
+				pending = append(pending, i)
+			}
+		}
+	} else {
+		for i := range notes {
+			pending = append(pending, i)
+		}
+	}
+
+	if len(pending) <= bulkThreshold {
+		for _, i := range pending {
+			if ctx.Err() != nil {
+				results[i].Err = errNotSent
+				continue
+			}
+			results[i].ID, results[i].Err = ac.AddNote(notes[i])
+		}
+		return results
+	}
+
+	sent := ac.forEachChunk(ctx, len(pending), func(start, end int) {
+		actions := make([]ankiRequest, 0, end-start)
+		for _, i := range pending[start:end] {
+			actions = append(actions, ac.action("addNote", map[string]interface{}{"note": notes[i]}))
+		}
+
+		responses, err := ac.multi(actions)
+		for j := range actions {
+			result := &results[pending[start+j]]
+			if err != nil {
+				result.Err = err
+				continue
+			}
+			if responses[j].Error != "" {
+				result.Err = fmt.Errorf("AnkiConnect error: %s", responses[j].Error)
+				continue
+			}
+			if err := decodeResult(responses[j].Result, &result.ID); err != nil || result.ID == 0 {
+				result.Err = fmt.Errorf("unexpected note ID type")
+			}
+		}
+	})
+	for _, i := range pending[sent:] {
+		results[i].Err = errNotSent
+	}
+
+	return results
+}
+
+// UpdateNotes updates the fields of several notes and returns one error per
+// update, in input order. Batching and cancellation follow the same rules as
+// AddNotes.
+func (ac *AnkiConnect) UpdateNotes(ctx context.Context, updates []NoteUpdate) []error {
+	errs := make([]error, len(updates))
+	if len(updates) <= bulkThreshold {
+		for i, update := range updates {
+			if ctx.Err() != nil {
+				errs[i] = errNotSent
+				continue
+			}
+			errs[i] = ac.UpdateNoteFields(update.ID, update.Fields)
+		}
+		return errs
+	}
+
+	sent := ac.forEachChunk(ctx, len(updates), func(start, end int) {
+		actions := make([]ankiRequest, 0, end-start)
+		for _, update := range updates[start:end] {
+			actions = append(actions, ac.action("updateNoteFields", map[string]interface{}{
+				"note": map[string]interface{}{
+					"id":     update.ID,
+					"fields": ac.sanitizeFields(update.Fields),
+				},
+			}))
+		}
+
+		responses, err := ac.multi(actions)
+		for i := range actions {
+			if err != nil {
+				errs[start+i] = err
+			} else if responses[i].Error != "" {
+				errs[start+i] = fmt.Errorf("AnkiConnect error: %s", responses[i].Error)
+			}
+		}
+	})
+	for i := sent; i < len(updates); i++ {
+		errs[i] = errNotSent
+	}
+
+	return errs
+}
+
+// NoteModelChange is the content of one note switched to another note type
+type NoteModelChange struct {
+	ID     int64
+	Fields map[string]string
+	Tags   []string
+}
+
+// ChangeNoteModels switches several notes to a note type and returns one
+// error per note, in input order. Batching and cancellation follow the same
+// rules as AddNotes.
+func (ac *AnkiConnect) ChangeNoteModels(ctx context.Context, modelName string, changes []NoteModelChange) []error {
+	errs := make([]error, len(changes))
+	if len(changes) <= bulkThreshold {
+		for i, change := range changes {
+			if ctx.Err() != nil {
+				errs[i] = errNotSent
+				continue
+			}
+			errs[i] = ac.UpdateNoteModel(change.ID, modelName, change.Fields, change.Tags)
+		}
+		return errs
+	}
+
+	sent := ac.forEachChunk(ctx, len(changes), func(start, end int) {
+		actions := make([]ankiRequest, 0, end-start)
+		for _, change := range changes[start:end] {
+			actions = append(actions, ac.action("updateNoteModel", noteModelParams(change.ID, modelName, ac.sanitizeFields(change.Fields), change.Tags)))
+		}
+
+		responses, err := ac.multi(actions)
+		for i := range actions {
+			if err != nil {
+				errs[start+i] = err
+			} else if responses[i].Error != "" {
+				errs[start+i] = fmt.Errorf("AnkiConnect error: %s", responses[i].Error)
+			}
+		}
+	})
+	for i := sent; i < len(changes); i++ {
+		errs[i] = errNotSent
+	}
+
+	return errs
+}
+
+// forEachChunk calls fn for consecutive [start, end) ranges of at most
+// bulkChunkSize items, running up to bulkConcurrency calls at once. Dry runs
+// go through the chunks in order, so changes are reported in input order.
+// Once ctx ends no further chunks are started; it returns the number of items
+// whose chunk was started.
+func (ac *AnkiConnect) forEachChunk(ctx context.Context, n int, fn func(start, end int)) int {
+	concurrency := bulkConcurrency
+	if ac.dryRun != nil {
+		concurrency = 1
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	sent := 0
+	for start := 0; start < n; start += bulkChunkSize {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		end := min(start+bulkChunkSize, n)
+		sent = end
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			fn(start, end)
+		}()
+	}
+	wg.Wait()
+	return sent
+}
+
+// action builds a request for use inside a multi request. AnkiConnect checks
+// the API key of each action, not just of the multi request.
+func (ac *AnkiConnect) action(action string, params interface{}) ankiRequest {
+	return ankiRequest{Action: action, Version: ac.Version, Key: ac.Key, Params: params}
+}
+
+// multi runs several actions in a single request. Each action carries its own
+// version, so AnkiConnect reports a separate result and error for each one.
+func (ac *AnkiConnect) multi(actions []ankiRequest) ([]ankiResponse, error) {
+	responses, err := invoke[[]ankiResponse](ac, "multi", map[string]interface{}{"actions": actions})
+	if err != nil {
+		return nil, err
+	}
+	if len(responses) != len(actions) {
+		return nil, fmt.Errorf("unexpected response type")
+	}
+
+	for _, resp := range responses {
+		if isAPIKeyError(resp.Error) {
+			return nil, errAPIKey
+		}
+	}
+
+	return responses, nil
+}
+
+// batch collects independent requests, such as the searches a tool needs
+// before it can fetch anything else, and sends them in one multi request
+type batch struct {
+	ac      *AnkiConnect
+	actions []ankiRequest
+	results []interface{}
+}
+
+// newBatch starts an empty batch of requests
+func (ac *AnkiConnect) newBatch() *batch {
+	return &batch{ac: ac}
+}
+
+// add queues an action whose result is decoded into result, a pointer
+func (b *batch) add(action string, params interface{}, result interface{}) {
+	b.actions = append(b.actions, b.ac.action(action, params))
+	b.results = append(b.results, result)
+}
+
+// send runs the queued actions in a single round trip and decodes their
+// results. It fails with the first action that failed.
+func (b *batch) send() error {
+	if len(b.actions) == 1 {
+		raw, err := b.ac.call(b.actions[0].Action, b.actions[0].Params)
+		if err != nil {
+			return err
+		}
+		return b.decode(0, raw)
+	}
+
+	responses, err := b.ac.multi(b.actions)
+	if err != nil {
+		return err
+	}
+	for i, resp := range responses {
+		if resp.Error != "" {
+			return fmt.Errorf("AnkiConnect error: %s", resp.Error)
+		}
+		if err := b.decode(i, resp.Result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decode decodes the raw result of the i-th action
+func (b *batch) decode(i int, raw json.RawMessage) error {
+	if err := decodeResult(raw, b.results[i]); err != nil {
+		return fmt.Errorf("unexpected %s response: %w", b.actions[i].Action, err)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// testNotes returns n distinct Basic notes for the given deck
+func testNotes(deck string, n int) []Note {
+	notes := make([]Note, n)
+	for i := range notes {
+		notes[i] = Note{
+			DeckName:  deck,
+			ModelName: "Basic",
+			Fields:    map[string]string{"Front": fmt.Sprintf("word %d", i), "Back": fmt.Sprintf("meaning %d", i)},
+		}
+	}
+	return notes
+}
+
+func TestAddNotesReportsPartialFailures(t *testing.T) {
+	for _, n := range []int{5, 250} {
+		mock := newMockAnkiConnect()
+		client := mockClient(t, mock)
+
+		notes := testNotes("Default", n)
+		notes[1].Fields["Front"] = notes[0].Fields["Front"]
+		notes[n-1].DeckName = "Missing"
+
+		results := client.AddNotes(context.Background(), notes)
+		if len(results) != n {
+			t.Fatalf("Expected %d results, got %d", n, len(results))
+		}
+		for i, r := range results {
+			wantErr := i == 1 || i == n-1
+			if (r.Err != nil) != wantErr {
+				t.Errorf("n=%d note %d: unexpected error %v", n, i, r.Err)
+			}
+			if !wantErr && r.ID == 0 {
+				t.Errorf("n=%d note %d: missing note ID", n, i)
+			}
+		}
+		if len(mock.notes) != n-2 {
+			t.Errorf("n=%d: expected %d notes in the collection, got %d", n, n-2, len(mock.notes))
+		}
+	}
+}
+
+func TestUpdateNotes(t *testing.T) {
+	mock := newMockAnkiConnect()
+	client := mockClient(t, mock)
+
+	results := client.AddNotes(context.Background(), testNotes("Default", 30))
+	updates := make([]NoteUpdate, len(results))
+	for i, r := range results {
+		updates[i] = NoteUpdate{ID: r.ID, Fields: map[string]string{"Back": "updated"}}
+	}
+	updates[3].ID = 42
+
+	for i, err := range client.UpdateNotes(context.Background(), updates) {
+		if (err != nil) != (i == 3) {
+			t.Errorf("update %d: unexpected error %v", i, err)
+		}
+	}
+	if back := mock.notes[results[0].ID].Fields["Back"]; back != "updated" {
+		t.Errorf("Expected updated field, got %q", back)
+	}
+}
+
+func TestAddNotesStopsWhenCancelled(t *testing.T) {
+	for _, n := range []int{5, 250} {
+		mock := newMockAnkiConnect()
+		client := mockClient(t, mock)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		for i, r := range client.AddNotes(ctx, testNotes("Default", n)) {
+			if !errors.Is(r.Err, errNotSent) {
+				t.Errorf("n=%d note %d: expected errNotSent, got %v", n, i, r.Err)
+			}
+		}
+		if len(mock.notes) != 0 {
+			t.Errorf("n=%d: expected no notes in the collection, got %d", n, len(mock.notes))
+		}
+	}
+}
+
+func TestForEachChunkStopsWhenCancelled(t *testing.T) {
+	// A dry run sends one chunk at a time, so the cancellation is seen before
+	// the second chunk
+	client := NewAnkiConnect().withDryRun(&dryRunLog{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var chunks int
+	sent := client.forEachChunk(ctx, 10*bulkChunkSize, func(start, end int) {
+		chunks++
+		cancel()
+	})
+	if chunks != 1 || sent != bulkChunkSize {
+		t.Errorf("Expected 1 chunk of %d items, got %d chunk(s) and %d items", bulkChunkSize, chunks, sent)
+	}
+}
+
+func benchmarkAddNotes(b *testing.B, n int) {
+	notes := testNotes("Default", n)
+	for i := range notes {
+		notes[i].Options = map[string]interface{}{"allowDuplicate": true}
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		client := mockClient(b, newMockAnkiConnect())
+		for _, r := range client.AddNotes(context.Background(), notes) {
+			if r.Err != nil {
+				b.Fatal(r.Err)
+			}
+		}
+	}
+	b.ReportMetric(float64(n*b.N)/b.Elapsed().Seconds(), "notes/s")
+}
+
+func BenchmarkAddNotes1k(b *testing.B)  { benchmarkAddNotes(b, 1000) }
+func BenchmarkAddNotes10k(b *testing.B) { benchmarkAddNotes(b, 10000) }
+
+func TestCanAddNotes(t *testing.T) {
+	mock := newMockAnkiConnect()
+	client := mockClient(t, mock)
+	if _, err := client.AddNote(testNotes("Default", 1)[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	notes := testNotes("Default", 3)
+	notes[2].Fields["Front"] = ""
+	errs, err := client.CanAddNotes(notes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs[0] == nil || !strings.Contains(errs[0].Error(), "duplicate") || errs[1] != nil || errs[2] == nil {
+		t.Errorf("Unexpected results: %v", errs)
+	}
+	if len(mock.notes) != 1 {
+		t.Errorf("Expected no notes to be added, got %d", len(mock.notes))
+	}
+
+	// AddNotes reports the duplicate from the check and adds the rest
+	results := client.AddNotes(context.Background(), notes[:2])
+	if results[0].Err == nil || !strings.Contains(results[0].Err.Error(), "duplicate") || results[1].Err != nil {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+}
+
+// countingTransport counts the HTTP requests sent to AnkiConnect
+type countingTransport struct {
+	next     http.RoundTripper
+	requests int
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests++
+	return c.next.RoundTrip(req)
+}
+
+func TestSearchNotesBatchesRequests(t *testing.T) {
+	mock := newMockAnkiConnect()
+	mock.seedDemo()
+	client := mockClient(t, mock)
+	transport := &countingTransport{next: http.DefaultTransport}
+	client.client.Transport = transport
+
+	notes, cards, err := client.SearchNotes("deck:Spanish::Grammar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 1 || len(cards) != 2 || cards[0].NoteID != notes[0].NoteID {
+		t.Errorf("Expected 1 note with 2 cards, got %d notes and %d cards", len(notes), len(cards))
+	}
+	if transport.requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", transport.requests)
+	}
+
+	transport.requests = 0
+	notes, cards, err = client.SearchNotes("deck:Missing")
+	if err != nil || len(notes) != 0 || len(cards) != 0 || transport.requests != 1 {
+		t.Errorf("Expected an empty result from 1 request, got %d notes, %d cards, %d requests, error %v", len(notes), len(cards), transport.requests, err)
+	}
+}
+
+func TestBatchReportsFailedAction(t *testing.T) {
+	mock := newMockAnkiConnect()
+	mock.seedDemo()
+	client := mockClient(t, mock)
+
+	var (
+		decks []string
+		info  interface{}
+	)
+	b := client.newBatch()
+	b.add("deckNames", nil, &decks)
+	b.add("noSuchAction", nil, &info)
+	if err := b.send(); err == nil || !strings.Contains(err.Error(), "AnkiConnect error") {
+		t.Errorf("Expected the failed action's error, got %v", err)
+	}
+
+	b = client.newBatch()
+	b.add("deckNames", nil, &decks)
+	if err := b.send(); err != nil || len(decks) != 4 {
+		t.Errorf("Expected 4 decks from a single action, got %v, error %v", decks, err)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// callMetaKey carries the key of a tool call in its _meta, from the hook
+// that sees the request ID to the tool handler, which doesn't
+const callMetaKey = "anki-mcp/call"
+
+// callTracker cancels the context of a tool call when the client sends
+// notifications/cancelled for it, which mcp-go doesn't do by itself
+type callTracker struct {
+	mu    sync.Mutex
+	calls map[string]*trackedCall
+}
+
+// trackedCall is a tool call that is being handled
+type trackedCall struct {
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+// newCallTracker creates a call tracker without calls
+func newCallTracker() *callTracker {
+	return &callTracker{calls: make(map[string]*trackedCall)}
+}
+
+// callKey identifies a request across the clients of the server
+func callKey(ctx context.Context, id any) string {
+	var session string
+	if s := server.ClientSessionFromContext(ctx); s != nil {
+		session = s.SessionID()
+	}
+	return fmt.Sprintf("%s/%v", session, id)
+}
+
+// attach makes the tracker follow the tool calls of an MCP server created
+// with the given hooks
+func (c *callTracker) attach(s *server.MCPServer, hooks *server.Hooks) {
+	hooks.AddBeforeCallTool(func(ctx context.Context, id any, message *mcp.CallToolRequest) {
+		key := callKey(ctx, id)
+		c.mu.Lock()
+		c.calls[key] = &trackedCall{}
+		c.mu.Unlock()
+
+		if message.Params.Meta == nil {
+			message.Params.Meta = &mcp.Meta{}
+		}
+		if message.Params.Meta.AdditionalFields == nil {
+			message.Params.Meta.AdditionalFields = make(map[string]any)
+		}
+		message.Params.Meta.AdditionalFields[callMetaKey] = key
+	})
+	hooks.AddAfterCallTool(func(ctx context.Context, id any, message *mcp.CallToolRequest, result *mcp.CallToolResult) {
+		c.done(callKey(ctx, id))
+	})
+	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+		if method == mcp.MethodToolsCall {
+			c.done(callKey(ctx, id))
+		}
+	})
+	s.AddNotificationHandler("notifications/cancelled", func(ctx context.Context, notification mcp.JSONRPCNotification) {
+		c.cancel(callKey(ctx, notification.Params.AdditionalFields["requestId"]))
+	})
+}
+
+// context returns the context a tool call is handled with, which ends when
+// the client cancels the call
+func (c *callTracker) context(ctx context.Context, request mcp.CallToolRequest) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	if request.Params.Meta == nil {
+		return ctx, cancel
+	}
+	key, _ := request.Params.Meta.AdditionalFields[callMetaKey].(string)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if call, ok := c.calls[key]; ok {
+		call.cancel = cancel
+		if call.cancelled {
+			cancel()
+		}
+	}
+	return ctx, cancel
+}
+
+// cancel cancels a tool call, or marks it cancelled if its handler hasn't
+// started yet
+func (c *callTracker) cancel(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	call, ok := c.calls[key]
+	if !ok {
+		return
+	}
+	call.cancelled = true
+	if call.cancel != nil {
+		call.cancel()
+	}
+}
+
+// done forgets a tool call that was answered
+func (c *callTracker) done(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.calls, key)
+}
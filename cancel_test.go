@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCancelledToolCall(t *testing.T) {
+	server, _ := newMockServer(t)
+	s := server.newMCPServer()
+	started := make(chan struct{})
+	server.addTool(s, mcp.NewTool("wait"), func(a *AnkiMCPServer, ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		select {
+		case <-ctx.Done():
+			return a.errorf("Cancelled"), nil
+		case <-time.After(5 * time.Second):
+			return a.errorf("Not cancelled"), nil
+		}
+	})
+
+	ctx := context.Background()
+	response := make(chan mcp.JSONRPCMessage, 1)
+	go func() {
+		response <- s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"wait","arguments":{}}}`))
+	}()
+	<-started
+	s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7}}`))
+
+	select {
+	case message := <-response:
+		result, ok := message.(mcp.JSONRPCResponse)
+		if !ok {
+			t.Fatalf("Unexpected response %#v", message)
+		}
+		text := result.Result.(mcp.CallToolResult).Content[0].(mcp.TextContent).Text
+		if text != "Error: Cancelled" {
+			t.Errorf("Expected the handler to see the cancellation, got %s", text)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("The tool call was not cancelled")
+	}
+	if len(server.calls.calls) != 0 {
+		t.Errorf("Expected no tracked calls after the response, got %d", len(server.calls.calls))
+	}
+}
+
+func TestCreateCardsBulkReportsCancellation(t *testing.T) {
+	server, mock := newMockServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]interface{}{
+		"deck": "Default",
+		"cards": []interface{}{
+			map[string]interface{}{"front": "uno", "back": "one"},
+			map[string]interface{}{"front": "dos", "back": "two"},
+		},
+	}
+	result, err := server.handleCreateCardsBulk(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "Created 0 of 2 card(s)") || !strings.Contains(text, "cancelled: 2") || strings.Contains(text, "#1") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	if len(mock.notes) != 0 {
+		t.Errorf("Expected no notes, got %d", len(mock.notes))
+	}
+}
@@ -0,0 +1,930 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// answerPrediction describes what answering a card with a given button would do
+type answerPrediction struct {
+	Button  string `json:"button"`
+	Outcome string `json:"outcome"`
+}
+
+// cardExplanation is the JSON representation of an explain_card result
+type cardExplanation struct {
+	CardID      int64              `json:"card_id"`
+	Deck        string             `json:"deck"`
+	Model       string             `json:"model"`
+	Type        int                `json:"type"`
+	Queue       int                `json:"queue"`
+	Interval    int                `json:"interval"`
+	Ease        float64            `json:"ease"`
+	DueInDays   *int               `json:"due_in_days,omitempty"`
+	Reps        int                `json:"reps"`
+	Lapses      int                `json:"lapses"`
+	Explanation []string           `json:"explanation"`
+	Predictions []answerPrediction `json:"predictions,omitempty"`
+}
+
+// cardDetails is the JSON representation of a card in a get_card_info result
+type cardDetails struct {
+	CardID    int64   `json:"card_id"`
+	NoteID    int64   `json:"note_id"`
+	Deck      string  `json:"deck"`
+	Model     string  `json:"model"`
+	Queue     string  `json:"queue"`
+	Interval  int     `json:"interval"`
+	Ease      float64 `json:"ease"`
+	Due       string  `json:"due,omitempty"`
+	DueInDays *int    `json:"due_in_days,omitempty"`
+	Position  *int    `json:"new_position,omitempty"`
+	Reps      int     `json:"reps"`
+	Lapses    int     `json:"lapses"`
+
+	dueAt time.Time
+}
+
+// registerCardTools registers card inspection tools with the MCP server
+func (a *AnkiMCPServer) registerCardTools(s *server.MCPServer) {
+	// Tool: Explain Card
+	explainCardTool := mcp.NewTool("explain_card",
+		mcp.WithDescription("Explain in plain language how Anki schedules a specific card: its state, current interval, ease, due date, queue, lapses, and what each answer button would do next. Predictions assume the SM-2 scheduler with the deck's options and ignore interval fuzz."),
+		mcp.WithNumber("card_id",
+			mcp.Required(),
+			mcp.Description("ID of the card to explain"),
+		),
+		withFormat(),
+	)
+	a.addTool(s, explainCardTool, (*AnkiMCPServer).handleExplainCard)
+
+	// Tool: Get Card Info
+	getCardInfoTool := mcp.NewTool("get_card_info",
+		mcp.WithDescription("Get the scheduling details of cards: queue, interval, ease, due date, reps and lapses. Use explain_card for a plain-language explanation of one card."),
+		mcp.WithArray("card_ids",
+			mcp.Required(),
+			mcp.Description("IDs of the cards"),
+			mcp.WithNumberItems(),
+		),
+		withFormat(),
+	)
+	a.addTool(s, getCardInfoTool, (*AnkiMCPServer).handleGetCardInfo)
+
+	// Tool: Suspend By Tag
+	suspendByTagTool := mcp.NewTool("suspend_by_tag",
+		mcp.WithDescription("Suspend or unsuspend all cards of notes carrying a tag (including its child tags), optionally only within one deck. Useful for pausing or resuming a whole topic."),
+		mcp.WithString("tag",
+			mcp.Required(),
+			mcp.Description("Tag whose cards should be suspended or unsuspended"),
+		),
+		mcp.WithString("action",
+			mcp.Description("Optional: suspend (default) or unsuspend"),
+			mcp.Enum("suspend", "unsuspend"),
+		),
+		mcp.WithString("deck",
+			mcp.Description("Optional: Only affect cards in this deck and its subdecks"),
+		),
+	)
+	a.addChangingTool(s, suspendByTagTool, (*AnkiMCPServer).handleSuspendByTag)
+
+	// Tool: Suspend Cards
+	suspendCardsTool := mcp.NewTool("suspend_cards",
+		mcp.WithDescription("Suspend cards so they no longer come up for review, selected by card ID or by an Anki search query (e.g. \"tag:leech\")."),
+		mcp.WithArray("card_ids",
+			mcp.Description("IDs of the cards to suspend"),
+			mcp.WithNumberItems(),
+		),
+		mcp.WithString("query",
+			mcp.Description("Anki search query selecting the cards, instead of card_ids"),
+		),
+	)
+	a.addChangingTool(s, suspendCardsTool, (*AnkiMCPServer).handleSuspendCards)
+
+	// Tool: Unsuspend Cards
+	unsuspendCardsTool := mcp.NewTool("unsuspend_cards",
+		mcp.WithDescription("Unsuspend cards so they come up for review again, selected by card ID or by an Anki search query."),
+		mcp.WithArray("card_ids",
+			mcp.Description("IDs of the cards to unsuspend"),
+			mcp.WithNumberItems(),
+		),
+		mcp.WithString("query",
+			mcp.Description("Anki search query selecting the cards, instead of card_ids"),
+		),
+	)
+	a.addChangingTool(s, unsuspendCardsTool, (*AnkiMCPServer).handleUnsuspendCards)
+
+	// Tool: Forget Cards
+	forgetCardsTool := mcp.NewTool("forget_cards",
+		mcp.WithDescription("Reset cards to new so the user learns them again from scratch, discarding their intervals and ease, selected by card ID or by an Anki search query. "+
+			"Their review history is kept. Use dry_run=true first when selecting by query."),
+		mcp.WithArray("card_ids",
+			mcp.Description("IDs of the cards to reset"),
+			mcp.WithNumberItems(),
+		),
+		mcp.WithString("query",
+			mcp.Description("Anki search query selecting the cards, instead of card_ids"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Optional: Only report how many cards would be reset"),
+		),
+	)
+	a.addChangingTool(s, forgetCardsTool, (*AnkiMCPServer).handleForgetCards)
+
+	// Tool: Relearn Cards
+	relearnCardsTool := mcp.NewTool("relearn_cards",
+		mcp.WithDescription("Put studied cards back into relearning, as if the user had answered Again, so they go through the relearning steps right away. "+
+			"Selected by card ID or by an Anki search query; new cards are left alone."),
+		mcp.WithArray("card_ids",
+			mcp.Description("IDs of the cards to relearn"),
+			mcp.WithNumberItems(),
+		),
+		mcp.WithString("query",
+			mcp.Description("Anki search query selecting the cards, instead of card_ids"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Optional: Only report how many cards would be relearned"),
+		),
+	)
+	a.addChangingTool(s, relearnCardsTool, (*AnkiMCPServer).handleRelearnCards)
+
+	// Tool: Reschedule Cards
+	rescheduleCardsTool := mcp.NewTool("reschedule_cards",
+		mcp.WithDescription("Set when cards are next due, selected by card ID or by an Anki search query. "+
+			"Give a single date, or a date range with until to spread the cards evenly at random over it, e.g. to review a whole deck before an exam. New cards become review cards."),
+		mcp.WithArray("card_ids",
+			mcp.Description("IDs of the cards to reschedule"),
+			mcp.WithNumberItems(),
+		),
+		mcp.WithString("query",
+			mcp.Description("Anki search query selecting the cards, instead of card_ids"),
+		),
+		mcp.WithString("date",
+			mcp.Required(),
+			mcp.Description("Due date as YYYY-MM-DD, or a number of days from today (0 is today)"),
+		),
+		mcp.WithString("until",
+			mcp.Description("Optional: Last due date of a range starting at date, as YYYY-MM-DD or a number of days from today"),
+		),
+		mcp.WithBoolean("reset_interval",
+			mcp.Description("Optional: Also set each card's interval to the days until its new due date, so later reviews continue from there"),
+		),
+	)
+	a.addChangingTool(s, rescheduleCardsTool, (*AnkiMCPServer).handleRescheduleCards)
+}
+
+// handleSuspendByTag suspends or unsuspends the cards carrying a tag
+func (a *AnkiMCPServer) handleSuspendByTag(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	tag, ok := args["tag"].(string)
+	if !ok || strings.TrimSpace(tag) == "" {
+		return a.errorf("tag is required"), nil
+	}
+	suspend := true
+	if action, _ := args["action"].(string); action == "unsuspend" {
+		suspend = false
+	}
+
+	// Only select cards whose state actually changes, so the count is accurate
+	query := tagQuery(tag)
+	if deckName, ok := args["deck"].(string); ok && deckName != "" {
+		query += " " + deckQuery(deckName)
+	}
+	if suspend {
+		query += " -is:suspended"
+	} else {
+		query += " is:suspended"
+	}
+
+	cardIDs, err := a.ankiClient.FindCards(query)
+	if err != nil {
+		return a.errorf("Failed to find cards: %v", err), nil
+	}
+
+	var text string
+	switch {
+	case len(cardIDs) == 0 && suspend:
+		text = a.t("No unsuspended cards found with tag %s", tag)
+	case len(cardIDs) == 0:
+		text = a.t("No suspended cards found with tag %s", tag)
+	case suspend:
+		if _, err := a.ankiClient.SuspendCards(cardIDs); err != nil {
+			return a.errorf("Failed to suspend cards: %v", err), nil
+		}
+		text = a.t("Suspended %d card(s) with tag %s", len(cardIDs), tag)
+	default:
+		if _, err := a.ankiClient.UnsuspendCards(cardIDs); err != nil {
+			return a.errorf("Failed to unsuspend cards: %v", err), nil
+		}
+		text = a.t("Unsuspended %d card(s) with tag %s", len(cardIDs), tag)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}, nil
+}
+
+// handleSuspendCards suspends cards selected by ID or query
+func (a *AnkiMCPServer) handleSuspendCards(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return a.setCardsSuspended(request, true), nil
+}
+
+// handleUnsuspendCards unsuspends cards selected by ID or query
+func (a *AnkiMCPServer) handleUnsuspendCards(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return a.setCardsSuspended(request, false), nil
+}
+
+// setCardsSuspended suspends or unsuspends the selected cards, counting only
+// the cards whose state changes
+func (a *AnkiMCPServer) setCardsSuspended(request mcp.CallToolRequest, suspend bool) *mcp.CallToolResult {
+	cardIDs, errResult := a.selectCards(request.GetArguments())
+	if errResult != nil {
+		return errResult
+	}
+	if len(cardIDs) == 0 {
+		return a.errorf("No cards found")
+	}
+
+	infos, err := a.ankiClient.GetCardsInfo(cardIDs)
+	if err != nil {
+		return a.errorf("Failed to get card info: %v", err)
+	}
+	var change []int64
+	missing := 0
+	for i, info := range infos {
+		switch {
+		case info.CardID == 0:
+			missing++
+		case (info.Queue == -1) != suspend:
+			change = append(change, cardIDs[i])
+		}
+	}
+
+	if len(change) > 0 {
+		if suspend {
+			if _, err := a.ankiClient.SuspendCards(change); err != nil {
+				return a.errorf("Failed to suspend cards: %v", err)
+			}
+		} else {
+			if _, err := a.ankiClient.UnsuspendCards(change); err != nil {
+				return a.errorf("Failed to unsuspend cards: %v", err)
+			}
+		}
+	}
+
+	out := a.newOutput()
+	unchanged := len(infos) - len(change) - missing
+	if suspend {
+		out.Heading(a.t("Suspended %d card(s)", len(change)))
+		if unchanged > 0 {
+			out.Item(a.t("Already suspended: %d", unchanged))
+		}
+	} else {
+		out.Heading(a.t("Unsuspended %d card(s)", len(change)))
+		if unchanged > 0 {
+			out.Item(a.t("Not suspended: %d", unchanged))
+		}
+	}
+	if missing > 0 {
+		out.Item(a.t("Cards not found: %d", missing))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}
+}
+
+// handleForgetCards resets cards selected by ID or query to new
+func (a *AnkiMCPServer) handleForgetCards(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return a.resetCards(request, false), nil
+}
+
+// handleRelearnCards puts cards selected by ID or query into relearning
+func (a *AnkiMCPServer) handleRelearnCards(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return a.resetCards(request, true), nil
+}
+
+// resetCards forgets or relearns the selected cards. Cards that are already
+// new are skipped, since there is nothing to forget or relearn.
+func (a *AnkiMCPServer) resetCards(request mcp.CallToolRequest, relearn bool) *mcp.CallToolResult {
+	cardIDs, errResult := a.selectCards(request.GetArguments())
+	if errResult != nil {
+		return errResult
+	}
+	if len(cardIDs) == 0 {
+		return a.errorf("No cards found")
+	}
+	dryRun, _ := request.GetArguments()["dry_run"].(bool)
+
+	infos, err := a.ankiClient.GetCardsInfo(cardIDs)
+	if err != nil {
+		return a.errorf("Failed to get card info: %v", err)
+	}
+	var change []int64
+	missing := 0
+	for i, info := range infos {
+		switch {
+		case info.CardID == 0:
+			missing++
+		case info.Type != 0:
+			change = append(change, cardIDs[i])
+		}
+	}
+
+	if len(change) > 0 && !dryRun {
+		if relearn {
+			if err := a.ankiClient.RelearnCards(change); err != nil {
+				return a.errorf("Failed to relearn cards: %v", err)
+			}
+		} else {
+			if err := a.ankiClient.ForgetCards(change); err != nil {
+				return a.errorf("Failed to reset cards: %v", err)
+			}
+		}
+	}
+
+	out := a.newOutput()
+	switch {
+	case dryRun && relearn:
+		out.Heading(a.t("Would relearn %d card(s)", len(change)))
+	case dryRun:
+		out.Heading(a.t("Would reset %d card(s) to new", len(change)))
+	case relearn:
+		out.Heading(a.t("Relearning %d card(s)", len(change)))
+	default:
+		out.Heading(a.t("Reset %d card(s) to new", len(change)))
+	}
+	if unchanged := len(infos) - len(change) - missing; unchanged > 0 {
+		out.Item(a.t("Already new: %d", unchanged))
+	}
+	if missing > 0 {
+		out.Item(a.t("Cards not found: %d", missing))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}
+}
+
+// handleRescheduleCards sets the due date of cards selected by ID or query
+func (a *AnkiMCPServer) handleRescheduleCards(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	now := time.Now()
+	dateArg, _ := args["date"].(string)
+	if strings.TrimSpace(dateArg) == "" {
+		return a.errorf("date is required"), nil
+	}
+	first, err := daysFromToday(dateArg, now, a.rolloverHour)
+	if err != nil {
+		return a.errorf("Invalid %s: %v", "date", err), nil
+	}
+	last := first
+	if untilArg, _ := args["until"].(string); strings.TrimSpace(untilArg) != "" {
+		if last, err = daysFromToday(untilArg, now, a.rolloverHour); err != nil {
+			return a.errorf("Invalid %s: %v", "until", err), nil
+		}
+		if last < first {
+			return a.errorf("until must not be before date"), nil
+		}
+	}
+
+	cardIDs, errResult := a.selectCards(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if len(cardIDs) == 0 {
+		return a.errorf("No cards found"), nil
+	}
+
+	days := strconv.Itoa(first)
+	if last > first {
+		days += "-" + strconv.Itoa(last)
+	}
+	if resetInterval, _ := args["reset_interval"].(bool); resetInterval {
+		days += "!"
+	}
+	if err := a.ankiClient.SetDueDate(cardIDs, days); err != nil {
+		return a.errorf("Failed to reschedule cards: %v", err), nil
+	}
+
+	today := dayStart(now, a.rolloverHour)
+	var text string
+	if last > first {
+		text = a.t("Spread %d card(s) between %s and %s", len(cardIDs), a.loc.FormatDate(today.AddDate(0, 0, first)), a.loc.FormatDate(today.AddDate(0, 0, last)))
+	} else {
+		text = a.t("Rescheduled %d card(s) to %s", len(cardIDs), a.loc.FormatDate(today.AddDate(0, 0, first)))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}, nil
+}
+
+// daysFromToday reads a due date given as YYYY-MM-DD or as a number of days
+// and returns the number of days from the current Anki day
+func daysFromToday(value string, now time.Time, rolloverHour int) (int, error) {
+	value = strings.TrimSpace(value)
+	if n, err := strconv.Atoi(value); err == nil {
+		if n < 0 {
+			return 0, fmt.Errorf("%d is in the past", n)
+		}
+		return n, nil
+	}
+
+	date, err := time.ParseInLocation(dateLayout, value, time.Local)
+	if err != nil {
+		return 0, fmt.Errorf("%q is neither YYYY-MM-DD nor a number of days", value)
+	}
+	days := int(math.Round(date.Sub(dayStart(now, rolloverHour)).Hours() / 24))
+	if days < 0 {
+		return 0, fmt.Errorf("%s is in the past", value)
+	}
+	return days, nil
+}
+
+// dayStart returns the midnight starting the Anki day a point in time belongs
+// to, which begins at the rollover hour
+func dayStart(t time.Time, rolloverHour int) time.Time {
+	t = t.Add(-time.Duration(rolloverHour) * time.Hour)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// handleExplainCard explains the scheduling state of a card
+func (a *AnkiMCPServer) handleExplainCard(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	id, ok := args["card_id"].(float64)
+	if !ok {
+		return a.errorf("card_id is required"), nil
+	}
+	cardID := int64(id)
+
+	cards, err := a.ankiClient.GetCardsInfo([]int64{cardID})
+	if err != nil {
+		return a.errorf("Failed to get card info: %v", err), nil
+	}
+	// AnkiConnect returns an empty object for unknown cards
+	if len(cards) == 0 || cards[0].CardID == 0 {
+		return a.errorf("Card %d not found", cardID), nil
+	}
+	card := cards[0]
+
+	deckName := card.DeckName
+	conf, err := a.ankiClient.GetDeckConfig(deckName)
+	if err != nil {
+		// Fall back to Anki's default options
+		conf = map[string]interface{}{}
+	}
+
+	// Review due dates are stored relative to the collection's creation, so
+	// they are resolved with a search instead
+	var dueIn *int
+	queue := card.Queue
+	if hasDayDue(card) {
+		if days, err := a.daysUntilDue(cardID); err == nil {
+			dueIn = &days
+		}
+	}
+
+	explanation := describeCardState(a.loc, card, conf, dueIn)
+
+	var predictions []answerPrediction
+	if queue >= 0 {
+		daysLate := 0
+		if dueIn != nil && *dueIn < 0 {
+			daysLate = -*dueIn
+		}
+		predictions = predictAnswers(a.loc, card, conf, daysLate)
+	}
+
+	if wantsJSON(request) {
+		return a.jsonResult(cardExplanation{
+			CardID:      cardID,
+			Deck:        deckName,
+			Model:       card.ModelName,
+			Type:        card.Type,
+			Queue:       queue,
+			Interval:    card.Interval,
+			Ease:        float64(card.Factor) / 10,
+			DueInDays:   dueIn,
+			Reps:        card.Reps,
+			Lapses:      card.Lapses,
+			Explanation: explanation,
+			Predictions: predictions,
+		}), nil
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Card %d in deck %q (note type %s)", cardID, deckName, card.ModelName))
+	for _, line := range explanation {
+		out.Line(line)
+	}
+	if len(predictions) > 0 {
+		out.Heading(a.t("If you answered it now"))
+		for _, p := range predictions {
+			out.Item(fmt.Sprintf("%s: %s", p.Button, p.Outcome))
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleGetCardInfo reports the scheduling details of cards
+func (a *AnkiMCPServer) handleGetCardInfo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	var cardIDs []int64
+	for _, id := range numberSliceValue(args, "card_ids") {
+		cardIDs = append(cardIDs, int64(id))
+	}
+	if len(cardIDs) == 0 {
+		return a.errorf("card_ids is required"), nil
+	}
+
+	infos, err := a.ankiClient.GetCardsInfo(cardIDs)
+	if err != nil {
+		return a.errorf("Failed to get card info: %v", err), nil
+	}
+
+	// Review due dates count days from the collection's creation. Resolving
+	// one card with a search gives today's day number for all of them.
+	today, todayKnown := 0, false
+	for _, info := range infos {
+		if hasDayDue(info) {
+			days, err := a.daysUntilDue(info.CardID)
+			if err != nil {
+				return a.errorf("Failed to find cards: %v", err), nil
+			}
+			today, todayKnown = int(info.Due)-days, true
+			break
+		}
+	}
+
+	var cards []cardDetails
+	var missing []int64
+	for i, info := range infos {
+		if info.CardID == 0 {
+			missing = append(missing, cardIDs[i])
+			continue
+		}
+		card := cardDetails{
+			CardID:   cardIDs[i],
+			NoteID:   info.NoteID,
+			Deck:     info.DeckName,
+			Model:    info.ModelName,
+			Queue:    queueName(info.Queue),
+			Interval: info.Interval,
+			Ease:     float64(info.Factor) / 10,
+			Reps:     info.Reps,
+			Lapses:   info.Lapses,
+		}
+		due := int(info.Due)
+		switch {
+		case hasDayDue(info) && todayKnown:
+			dueIn := due - today
+			card.DueInDays = &dueIn
+			card.dueAt = time.Now().AddDate(0, 0, dueIn)
+			card.Due = card.dueAt.Format(dateLayout)
+		case info.Queue == 1:
+			// Learning cards are due at a timestamp
+			card.dueAt = time.Unix(int64(due), 0)
+			card.Due = card.dueAt.Format(time.RFC3339)
+		case info.Type == 0:
+			card.Position = &due
+		}
+		cards = append(cards, card)
+	}
+
+	if wantsJSON(request) {
+		return a.jsonResult(map[string]interface{}{
+			"cards":   cards,
+			"missing": missing,
+		}), nil
+	}
+
+	out := a.newOutput()
+	for _, c := range cards {
+		out.Heading(a.t("Card %d (%s)", c.CardID, c.Deck))
+		out.Item(a.t("Note: %d, note type: %s", c.NoteID, c.Model))
+		out.Item(a.t("Queue: %s", a.queueLabel(c.Queue)))
+		out.Item(a.t("Interval: %d day(s), ease: %.0f%%", c.Interval, c.Ease))
+		switch {
+		case c.DueInDays != nil:
+			out.Item(a.t("Due: %s (in %d day(s))", a.loc.FormatDate(c.dueAt), *c.DueInDays))
+		case c.Due != "":
+			out.Item(a.t("Due: %s", a.loc.FormatDateTime(c.dueAt)))
+		case c.Position != nil:
+			out.Item(a.t("New card position: %d", *c.Position))
+		}
+		out.Item(a.t("Reps: %d, lapses: %d", c.Reps, c.Lapses))
+	}
+	if len(missing) > 0 {
+		ids := make([]string, len(missing))
+		for i, id := range missing {
+			ids[i] = strconv.FormatInt(id, 10)
+		}
+		out.Line(a.t("Cards not found: %s", strings.Join(ids, ", ")))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// hasDayDue reports whether a cardsInfo entry's due value is a day number
+func hasDayDue(card CardInfo) bool {
+	return card.Queue == 2 || card.Queue == 3 || (card.Queue < 0 && card.Type == 2)
+}
+
+// queueName returns the English name of a card queue
+func queueName(queue int) string {
+	switch queue {
+	case -3, -2:
+		return "buried"
+	case -1:
+		return "suspended"
+	case 0:
+		return "new"
+	case 1, 3:
+		return "learning"
+	case 4:
+		return "preview"
+	default:
+		return "review"
+	}
+}
+
+// queueLabel returns the localized name of a card queue
+func (a *AnkiMCPServer) queueLabel(queue string) string {
+	switch queue {
+	case "buried":
+		return a.t("buried")
+	case "suspended":
+		return a.t("suspended")
+	case "preview":
+		return a.t("preview")
+	default:
+		return a.cardStateLabel(queue)
+	}
+}
+
+// daysUntilDue finds how many days from today a review card is due (negative
+// when overdue) by bisecting over "prop:due" searches
+func (a *AnkiMCPServer) daysUntilDue(cardID int64) (int, error) {
+	low, high := -36500, 36500
+	for low < high {
+		mid := low + (high-low)/2
+		ids, err := a.ankiClient.FindCards(fmt.Sprintf("cid:%d prop:due<=%d", cardID, mid))
+		if err != nil {
+			return 0, err
+		}
+		if len(ids) > 0 {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	return low, nil
+}
+
+// describeCardState returns plain-language lines describing a card's scheduling state
+func describeCardState(loc *localizer, card CardInfo, conf map[string]interface{}, dueIn *int) []string {
+	var lines []string
+
+	queue := card.Queue
+	cardType := card.Type
+	interval := card.Interval
+	factor := float64(card.Factor)
+	lapses := card.Lapses
+	reps := card.Reps
+
+	switch queue {
+	case -1:
+		lines = append(lines, loc.Sprintf("State: suspended. It will not be shown until it is unsuspended."))
+	case -2, -3:
+		lines = append(lines, loc.Sprintf("State: buried. It will return to its queue tomorrow (or when unburied)."))
+	case 0:
+		lines = append(lines, loc.Sprintf("State: new. It has never been studied and is waiting in the new card queue."))
+	case 1, 3:
+		if cardType == 3 {
+			lines = append(lines, loc.Sprintf("State: relearning. It was forgotten and is going through the relearning steps."))
+		} else {
+			lines = append(lines, loc.Sprintf("State: learning. It is going through the initial learning steps."))
+		}
+	case 2:
+		lines = append(lines, loc.Sprintf("State: review. It has graduated from learning and is shown at growing intervals."))
+	case 4:
+		lines = append(lines, loc.Sprintf("State: preview in a filtered deck."))
+	}
+
+	if interval > 0 {
+		lines = append(lines, loc.Sprintf("Current interval: %s.", formatDays(loc, float64(interval))))
+	}
+	if factor > 0 && cardType != 0 {
+		lines = append(lines, loc.Sprintf("Ease: %.0f%%. Good multiplies the interval by roughly this factor.", factor/10))
+	}
+
+	switch {
+	case queue == 1:
+		due := time.Unix(card.Due, 0)
+		lines = append(lines, loc.Sprintf("Due: %s (learning step).", loc.FormatDateTime(due)))
+	case queue == 0:
+		lines = append(lines, loc.Sprintf("Position in the new card queue: %d.", card.Due))
+	case dueIn != nil:
+		dueDate := loc.FormatDate(time.Now().AddDate(0, 0, *dueIn))
+		switch {
+		case *dueIn == 0:
+			lines = append(lines, loc.Sprintf("Due: today (%s).", dueDate))
+		case *dueIn < 0:
+			lines = append(lines, loc.Sprintf("Due: overdue by %d day(s) (was due %s).", -*dueIn, dueDate))
+		default:
+			lines = append(lines, loc.Sprintf("Due: in %d day(s) (%s).", *dueIn, dueDate))
+		}
+	}
+
+	lines = append(lines, loc.Sprintf("Reviews: %d, lapses: %d.", reps, lapses))
+
+	leechFails := int(numberValue(objectValue(conf, "lapse"), "leechFails"))
+	if leechFails == 0 {
+		leechFails = 8
+	}
+	if lapses >= leechFails {
+		lines = append(lines, loc.Sprintf("It has reached the leech threshold of %d lapses; consider rewording or splitting it.", leechFails))
+	} else if lapses > 0 {
+		lines = append(lines, loc.Sprintf("It becomes a leech after %d more lapse(s).", leechFails-lapses))
+	}
+
+	return lines
+}
+
+// predictAnswers estimates the outcome of each answer button using SM-2 rules and
+// the deck's options, falling back to Anki's defaults for missing options
+func predictAnswers(loc *localizer, card CardInfo, conf map[string]interface{}, daysLate int) []answerPrediction {
+	newConf := objectValue(conf, "new")
+	lapseConf := objectValue(conf, "lapse")
+	revConf := objectValue(conf, "rev")
+
+	newSteps := numberSliceValue(newConf, "delays")
+	if len(newSteps) == 0 {
+		newSteps = []float64{1, 10}
+	}
+	graduating := numberSliceValue(newConf, "ints")
+	if len(graduating) < 2 {
+		graduating = []float64{1, 4}
+	}
+	lapseSteps := numberSliceValue(lapseConf, "delays")
+	if len(lapseSteps) == 0 {
+		lapseSteps = []float64{10}
+	}
+	minInt := numberOrDefault(lapseConf, "minInt", 1)
+	lapseMult := numberOrDefault(lapseConf, "mult", 0)
+	easyBonus := numberOrDefault(revConf, "ease4", 1.3)
+	hardFactor := numberOrDefault(revConf, "hardFactor", 1.2)
+	ivlFct := numberOrDefault(revConf, "ivlFct", 1)
+	maxIvl := numberOrDefault(revConf, "maxIvl", 36500)
+
+	cardType := card.Type
+	interval := float64(card.Interval)
+	ease := float64(card.Factor) / 1000
+	remaining := card.Left % 1000
+
+	graduate := func(days float64) string {
+		return loc.Sprintf("graduates to review, next shown in %s", formatDays(loc, days))
+	}
+	step := func(minutes float64) string {
+		return loc.Sprintf("shown again in %s", formatMinutes(loc, minutes))
+	}
+
+	switch cardType {
+	case 0, 1:
+		current := 0
+		if cardType == 1 {
+			current = len(newSteps) - remaining
+			if current < 0 || current >= len(newSteps) {
+				current = 0
+			}
+		}
+		hard := newSteps[current]
+		if current == 0 && len(newSteps) > 1 {
+			hard = (newSteps[0] + newSteps[1]) / 2
+		} else if current == 0 {
+			hard = newSteps[0] * 1.5
+		}
+		good := graduate(graduating[0])
+		if current+1 < len(newSteps) {
+			good = step(newSteps[current+1])
+		}
+		return []answerPrediction{
+			{loc.Sprintf("Again"), loc.Sprintf("%s (back to the first learning step)", step(newSteps[0]))},
+			{loc.Sprintf("Hard"), step(hard)},
+			{loc.Sprintf("Good"), good},
+			{loc.Sprintf("Easy"), graduate(graduating[1])},
+		}
+	case 3:
+		current := len(lapseSteps) - remaining
+		if current < 0 || current >= len(lapseSteps) {
+			current = 0
+		}
+		good := loc.Sprintf("returns to review, next shown in %s", formatDays(loc, math.Max(interval, minInt)))
+		if current+1 < len(lapseSteps) {
+			good = step(lapseSteps[current+1])
+		}
+		return []answerPrediction{
+			{loc.Sprintf("Again"), loc.Sprintf("%s (back to the first relearning step)", step(lapseSteps[0]))},
+			{loc.Sprintf("Hard"), step(lapseSteps[current])},
+			{loc.Sprintf("Good"), good},
+			{loc.Sprintf("Easy"), loc.Sprintf("returns to review, next shown in %s", formatDays(loc, math.Max(interval, minInt)+1))},
+		}
+	default:
+		if ease == 0 {
+			ease = 2.5
+		}
+		late := float64(daysLate)
+		clamp := func(days float64) float64 {
+			return math.Min(math.Round(days), maxIvl)
+		}
+		hard := clamp(math.Max(interval+1, interval*hardFactor*ivlFct))
+		good := clamp(math.Max(hard+1, (interval+late/2)*ease*ivlFct))
+		easy := clamp(math.Max(good+1, (interval+late)*ease*easyBonus*ivlFct))
+		lapsed := math.Max(minInt, math.Round(interval*lapseMult))
+		return []answerPrediction{
+			{loc.Sprintf("Again"), loc.Sprintf("counts as a lapse: ease drops to %.0f%%, %s, then the interval restarts at %s",
+				math.Max(130, ease*100-20), step(lapseSteps[0]), formatDays(loc, lapsed))},
+			{loc.Sprintf("Hard"), loc.Sprintf("next shown in %s, ease drops to %.0f%%", formatDays(loc, hard), math.Max(130, ease*100-15))},
+			{loc.Sprintf("Good"), loc.Sprintf("next shown in %s, ease unchanged", formatDays(loc, good))},
+			{loc.Sprintf("Easy"), loc.Sprintf("next shown in %s, ease rises to %.0f%%", formatDays(loc, easy), ease*100+15)},
+		}
+	}
+}
+
+// numberOrDefault extracts a numeric option, falling back to a default when it is missing
+func numberOrDefault(m map[string]interface{}, key string, def float64) float64 {
+	if v, ok := m[key].(float64); ok {
+		return v
+	}
+	return def
+}
+
+// formatMinutes formats a learning step given in minutes
+func formatMinutes(loc *localizer, minutes float64) string {
+	switch {
+	case minutes < 60:
+		return loc.Sprintf("%.0f minute(s)", minutes)
+	case minutes < 1440:
+		return loc.Sprintf("%.1f hour(s)", minutes/60)
+	default:
+		return formatDays(loc, minutes/1440)
+	}
+}
+
+// formatDays formats an interval given in days
+func formatDays(loc *localizer, days float64) string {
+	switch {
+	case days < 31:
+		return loc.Sprintf("%.0f day(s)", days)
+	case days < 365:
+		return loc.Sprintf("%.1f month(s)", days/30)
+	default:
+		return loc.Sprintf("%.1f year(s)", days/365)
+	}
+}
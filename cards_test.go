@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPredictAnswersReviewCard(t *testing.T) {
+	card := CardInfo{Type: 2, Queue: 2, Interval: 10, Factor: 2500}
+
+	predictions := predictAnswers(newLocalizer("en"), card, map[string]interface{}{}, 0)
+	if len(predictions) != 4 {
+		t.Fatalf("Expected 4 predictions, got %d", len(predictions))
+	}
+
+	expected := map[string]string{
+		"Hard": "12 day(s)",
+		"Good": "25 day(s)",
+		"Easy": "1.1 month(s)",
+	}
+	for _, p := range predictions {
+		if want, ok := expected[p.Button]; ok && !strings.Contains(p.Outcome, want) {
+			t.Errorf("%s: expected outcome to contain %q, got %q", p.Button, want, p.Outcome)
+		}
+	}
+}
+
+func TestPredictAnswersNewCardUsesDeckSteps(t *testing.T) {
+	card := CardInfo{Type: 0, Queue: 0}
+	conf := map[string]interface{}{
+		"new": map[string]interface{}{
+			"delays": []interface{}{float64(5)},
+			"ints":   []interface{}{float64(2), float64(6)},
+		},
+	}
+
+	predictions := predictAnswers(newLocalizer("en"), card, conf, 0)
+	if !strings.Contains(predictions[0].Outcome, "5 minute(s)") {
+		t.Errorf("Again: unexpected outcome %q", predictions[0].Outcome)
+	}
+	if !strings.Contains(predictions[2].Outcome, "graduates") || !strings.Contains(predictions[2].Outcome, "2 day(s)") {
+		t.Errorf("Good: unexpected outcome %q", predictions[2].Outcome)
+	}
+	if !strings.Contains(predictions[3].Outcome, "6 day(s)") {
+		t.Errorf("Easy: unexpected outcome %q", predictions[3].Outcome)
+	}
+}
+
+func TestSuspendByTag(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	args := map[string]interface{}{"tag": "grammar"}
+	if text, _ := callTool(t, server.handleSuspendByTag, args); text != "Suspended 2 card(s) with tag grammar" {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	if text, _ := callTool(t, server.handleSuspendByTag, args); text != "No unsuspended cards found with tag grammar" {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	if cards, _ := mock.search("is:suspended"); len(cards) != 2 {
+		t.Errorf("Expected 2 suspended cards, got %d", len(cards))
+	}
+
+	args["action"] = "unsuspend"
+	args["deck"] = "Spanish::Vocabulary"
+	if text, _ := callTool(t, server.handleSuspendByTag, args); text != "No suspended cards found with tag grammar" {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	delete(args, "deck")
+	if text, _ := callTool(t, server.handleSuspendByTag, args); text != "Unsuspended 2 card(s) with tag grammar" {
+		t.Errorf("Unexpected output: %s", text)
+	}
+}
+
+func TestSuspendCards(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	text, isErr := callTool(t, server.handleSuspendCards, map[string]interface{}{"query": "tag:grammar"})
+	if isErr || !strings.Contains(text, "Suspended 2 card(s)") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	text, isErr = callTool(t, server.handleSuspendCards, map[string]interface{}{"query": "deck:Spanish::Grammar"})
+	if isErr || !strings.Contains(text, "Suspended 0 card(s)") || !strings.Contains(text, "Already suspended: 2") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+
+	cards, _ := server.ankiClient.FindCards("is:suspended")
+	text, isErr = callTool(t, server.handleUnsuspendCards, map[string]interface{}{"card_ids": []interface{}{float64(cards[0])}})
+	if isErr || !strings.Contains(text, "Unsuspended 1 card(s)") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	if suspended, _ := server.ankiClient.FindCards("is:suspended"); len(suspended) != 1 {
+		t.Errorf("Expected one suspended card left, got %d", len(suspended))
+	}
+}
+
+func TestGetCardInfo(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	reviews, _ := server.ankiClient.FindCards("is:review")
+	newCards, _ := server.ankiClient.FindCards("is:new")
+	card := mock.cards[reviews[len(reviews)-1]]
+	ids := []interface{}{float64(reviews[0]), float64(card.ID), float64(newCards[0]), float64(42)}
+
+	text, isErr := callTool(t, server.handleGetCardInfo, map[string]interface{}{"card_ids": ids})
+	if isErr || !strings.Contains(text, "Queue: review") || !strings.Contains(text, "New card position:") || !strings.Contains(text, "Cards not found: 42") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+
+	text, _ = callTool(t, server.handleGetCardInfo, map[string]interface{}{"card_ids": ids[1:2], "format": "json"})
+	want := fmt.Sprintf(`"due_in_days":%d`, card.Due-mockToday)
+	if !strings.Contains(text, want) || !strings.Contains(text, `"ease":250`) {
+		t.Errorf("Expected %s and the ease in percent, got: %s", want, text)
+	}
+}
+
+func TestForgetAndRelearnCards(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	studied, fresh := 0, 0
+	var reviewID int64
+	for id, card := range mock.cards {
+		if card.Deck != "Spanish::Vocabulary" {
+			continue
+		}
+		if card.Type == 0 {
+			fresh++
+		} else {
+			studied++
+			if card.Type == 2 {
+				reviewID = id
+			}
+		}
+	}
+	if studied == 0 || fresh == 0 || reviewID == 0 {
+		t.Fatalf("Expected studied and new cards in the demo deck, got %d and %d", studied, fresh)
+	}
+
+	args := map[string]interface{}{"card_ids": []interface{}{float64(reviewID)}}
+	if text, isErr := callTool(t, server.handleRelearnCards, args); isErr || !strings.Contains(text, "Relearning 1 card(s)") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	if card := mock.cards[reviewID]; card.Type != 3 || card.Queue != 1 {
+		t.Errorf("Expected the card in relearning, got type %d queue %d", card.Type, card.Queue)
+	}
+
+	args = map[string]interface{}{"query": "deck:Spanish::Vocabulary", "dry_run": true}
+	want := fmt.Sprintf("Would reset %d card(s) to new\n- Already new: %d", studied, fresh)
+	if text, _ := callTool(t, server.handleForgetCards, args); !strings.Contains(text, want) {
+		t.Errorf("Expected %q, got: %s", want, text)
+	}
+	if mock.cards[reviewID].Type == 0 {
+		t.Fatal("dry_run reset a card")
+	}
+
+	delete(args, "dry_run")
+	if text, _ := callTool(t, server.handleForgetCards, args); !strings.Contains(text, fmt.Sprintf("Reset %d card(s) to new", studied)) {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	if card := mock.cards[reviewID]; card.Type != 0 || card.Interval != 0 || card.Reps != 0 {
+		t.Errorf("Expected a new card, got %+v", card)
+	}
+}
+
+func TestRescheduleCards(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	now := time.Now()
+	exam := dayStart(now, server.rolloverHour).AddDate(0, 0, 10).Format(dateLayout)
+	args := map[string]interface{}{"query": "deck:Spanish::Vocabulary", "date": "1", "until": exam}
+	text, isErr := callTool(t, server.handleRescheduleCards, args)
+	if isErr || !strings.Contains(text, "card(s) between") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	for _, card := range mock.cards {
+		if card.Deck == "Spanish::Vocabulary" && (card.Queue != 2 || card.Due < mockToday+1 || card.Due > mockToday+10) {
+			t.Errorf("Card %d due on day %d, expected between %d and %d", card.ID, card.Due, mockToday+1, mockToday+10)
+		}
+	}
+
+	var cardID int64
+	for id, card := range mock.cards {
+		if card.Deck == "Spanish::Grammar" {
+			cardID = id
+		}
+	}
+	args = map[string]interface{}{"card_ids": []interface{}{float64(cardID)}, "date": "5", "reset_interval": true}
+	if text, isErr := callTool(t, server.handleRescheduleCards, args); isErr || !strings.Contains(text, "Rescheduled 1 card(s)") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	if card := mock.cards[cardID]; card.Due != mockToday+5 || card.Interval != 5 {
+		t.Errorf("Expected due in 5 days with a 5 day interval, got %+v", card)
+	}
+
+	for _, bad := range []map[string]interface{}{
+		{"card_ids": []interface{}{float64(cardID)}, "date": "-1"},
+		{"card_ids": []interface{}{float64(cardID)}, "date": "next week"},
+		{"card_ids": []interface{}{float64(cardID)}, "date": "5", "until": "2"},
+	} {
+		if text, isErr := callTool(t, server.handleRescheduleCards, bad); !isErr {
+			t.Errorf("Expected %v to fail, got: %s", bad, text)
+		}
+	}
+}
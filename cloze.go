@@ -0,0 +1,433 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Cloze numbering modes
+const (
+	// clozeSequential numbers deletions c1, c2, ... in order of appearance;
+	// deletions that already share a number keep sharing one
+	clozeSequential = "sequential"
+	// clozeOverlapping gives every deletion its own number, so each card hides
+	// exactly one deletion and shows the others
+	clozeOverlapping = "overlapping"
+)
+
+// clozePattern matches a cloze deletion, numbered ({{c1::text}}) or plain
+// ({{text}}), with an optional ::hint
+var clozePattern = regexp.MustCompile(`(?s)\{\{(?:c(\d+)::)?(.*?)\}\}`)
+
+// numberClozes assigns sequential cloze numbers to the deletions in text,
+// fixing missing, duplicated or out-of-order indices. It returns the numbered
+// text and the number of cards it produces.
+func numberClozes(text, mode string) (string, int, error) {
+	if mode == "" {
+		mode = clozeSequential
+	}
+	if mode != clozeSequential && mode != clozeOverlapping {
+		return "", 0, fmt.Errorf("unknown numbering mode %q", mode)
+	}
+
+	next := 0
+	groups := make(map[int]int)
+	numbered := clozePattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := clozePattern.FindStringSubmatch(match)
+		num := 0
+		if old, err := strconv.Atoi(parts[1]); err == nil && mode == clozeSequential {
+			if n, ok := groups[old]; ok {
+				num = n
+			} else {
+				next++
+				num = next
+				groups[old] = num
+			}
+		} else {
+			next++
+			num = next
+		}
+		return fmt.Sprintf("{{c%d::%s}}", num, parts[2])
+	})
+
+	if next == 0 {
+		return "", 0, fmt.Errorf("no cloze deletions found; mark them with {{...}}")
+	}
+	return numbered, next, nil
+}
+
+// clozeWordPattern matches a word, including inner apostrophes and hyphens
+var clozeWordPattern = regexp.MustCompile(`[\p{L}\p{N}]+(?:['’-][\p{L}\p{N}]+)*`)
+
+// clozeKeyTerms picks up to max terms of a sentence worth hiding: numbers
+// such as years first, then capitalized names that don't start a sentence,
+// then the longest words. The terms are returned in order of appearance.
+func clozeKeyTerms(text string, max int) []string {
+	text = html.UnescapeString(htmlTagPattern.ReplaceAllString(text, " "))
+
+	type candidate struct {
+		term  string
+		score int
+		pos   int
+	}
+	var candidates []candidate
+	add := func(term string, score, pos int) {
+		for _, c := range candidates {
+			if strings.EqualFold(c.term, term) {
+				return
+			}
+		}
+		candidates = append(candidates, candidate{term, score, pos})
+	}
+
+	words := clozeWordPattern.FindAllStringIndex(text, -1)
+	for i := 0; i < len(words); i++ {
+		start, end := words[i][0], words[i][1]
+		word := text[start:end]
+		first, _ := utf8.DecodeRuneInString(word)
+		switch {
+		case strings.ContainsAny(word, "0123456789"):
+			add(word, 3, start)
+		case unicode.IsUpper(first) && !sentenceStart(text[:start]):
+			// Consecutive capitalized words form one name, e.g. New York
+			for i+1 < len(words) && strings.TrimSpace(text[end:words[i+1][0]]) == "" {
+				next, _ := utf8.DecodeRuneInString(text[words[i+1][0]:])
+				if !unicode.IsUpper(next) {
+					break
+				}
+				i++
+				end = words[i][1]
+			}
+			add(text[start:end], 2, start)
+		case utf8.RuneCountInString(word) >= 5:
+			add(word, 1, start)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return utf8.RuneCountInString(candidates[i].term) > utf8.RuneCountInString(candidates[j].term)
+	})
+	if len(candidates) > max {
+		candidates = candidates[:max]
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].pos < candidates[j].pos })
+
+	terms := make([]string, len(candidates))
+	for i, c := range candidates {
+		terms[i] = c.term
+	}
+	return terms
+}
+
+// sentenceStart reports whether a word following before starts a sentence
+func sentenceStart(before string) bool {
+	before = strings.TrimRightFunc(before, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune("\"'“‘(¡¿", r)
+	})
+	return before == "" || strings.ContainsAny(before[len(before)-1:], ".!?:")
+}
+
+// clozeTerms wraps every whole-word occurrence of the terms in cloze
+// deletions, giving each term its own card or all of them one card. A term
+// can carry a hint as "term::hint". Existing deletions and HTML tags are left
+// alone. It returns the numbered text, the number of cards and the terms that
+// weren't found.
+func clozeTerms(text string, terms []string, oneCard bool) (string, int, []string, error) {
+	var protected [][]int
+	protected = append(protected, clozePattern.FindAllStringIndex(text, -1)...)
+	protected = append(protected, htmlTagPattern.FindAllStringIndex(text, -1)...)
+	overlaps := func(start, end int) bool {
+		for _, r := range protected {
+			if start < r[1] && r[0] < end {
+				return true
+			}
+		}
+		return false
+	}
+
+	type deletion struct {
+		start, end int
+		group      int
+		hint       string
+	}
+	var deletions []deletion
+	var missing []string
+	for i, term := range terms {
+		term, hint, _ := strings.Cut(term, "::")
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		// Temporary numbers that can't clash with numbers already in the
+		// text; numberClozes renumbers them in order of appearance
+		group := 1000 + i
+		if oneCard {
+			group = 1000
+		}
+		found := false
+		pattern := regexp.MustCompile("(?i)" + regexp.QuoteMeta(term))
+		for _, m := range pattern.FindAllStringIndex(text, -1) {
+			before, _ := utf8.DecodeLastRuneInString(text[:m[0]])
+			after, _ := utf8.DecodeRuneInString(text[m[1]:])
+			if isWordRune(before) || isWordRune(after) || overlaps(m[0], m[1]) {
+				continue
+			}
+			deletions = append(deletions, deletion{m[0], m[1], group, hint})
+			protected = append(protected, m)
+			found = true
+		}
+		if !found {
+			missing = append(missing, term)
+		}
+	}
+
+	sort.Slice(deletions, func(i, j int) bool { return deletions[i].start < deletions[j].start })
+	var b strings.Builder
+	last := 0
+	for _, d := range deletions {
+		b.WriteString(text[last:d.start])
+		b.WriteString(fmt.Sprintf("{{c%d::%s", d.group, text[d.start:d.end]))
+		if d.hint != "" {
+			b.WriteString("::" + d.hint)
+		}
+		b.WriteString("}}")
+		last = d.end
+	}
+	b.WriteString(text[last:])
+
+	numbered, cards, err := numberClozes(b.String(), clozeSequential)
+	return numbered, cards, missing, err
+}
+
+// isWordRune reports whether r is part of a word
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// clozeFieldPattern finds the field a cloze template hides text in
+var clozeFieldPattern = regexp.MustCompile(`\{\{(?:[^{}]*:)?cloze:([^{}:]+)\}\}`)
+
+// registerClozeTools registers cloze deletion tools with the MCP server
+func (a *AnkiMCPServer) registerClozeTools(s *server.MCPServer) {
+	// Tool: Number Clozes
+	numberClozesTool := mcp.NewTool("number_clozes",
+		mcp.WithDescription("Turn text with cloze deletions marked as {{...}} (or with wrong or missing c1/c2 indices) into valid Anki cloze text with sequential numbers. "+
+			"Use it before creating Cloze notes instead of numbering deletions by hand. A hint can follow the text as {{text::hint}}."),
+		mcp.WithString("text",
+			mcp.Required(),
+			mcp.Description("Text with deletions marked as {{...}} or {{cN::...}}"),
+		),
+		mcp.WithString("mode",
+			mcp.Description("Optional: sequential (default) numbers deletions in order and keeps deletions that already share a number together; "+
+				"overlapping gives every deletion its own card"),
+			mcp.Enum(clozeSequential, clozeOverlapping),
+		),
+	)
+	a.addTool(s, numberClozesTool, (*AnkiMCPServer).handleNumberClozes)
+
+	// Tool: Create Cloze Card
+	createClozeTool := mcp.NewTool("create_cloze_card",
+		mcp.WithDescription("Create a Cloze note from a plain sentence by hiding the given words, without writing {{c1::...}} syntax by hand. "+
+			"Every occurrence of a target word is hidden; each target gets its own card unless one_card is true. "+
+			"Without targets, deletions already marked as {{...}} are used, and otherwise key terms (numbers, names, long words) are picked automatically."),
+		mcp.WithString("text",
+			mcp.Required(),
+			mcp.Description("Sentence to turn into a cloze note"),
+		),
+		mcp.WithString("deck",
+			mcp.Required(),
+			mcp.Description("Name of the deck to add the note to"),
+		),
+		mcp.WithArray("targets",
+			mcp.Description("Optional: Words or phrases to hide, matched as whole words ignoring case; add a hint as \"word::hint\""),
+			mcp.WithStringItems(),
+		),
+		mcp.WithNumber("max_terms",
+			mcp.Description("Optional: Number of key terms to pick when there are no targets or marked deletions (default: 3)"),
+		),
+		mcp.WithBoolean("one_card",
+			mcp.Description("Optional: Hide all targets on a single card instead of one card per target (default: false)"),
+		),
+		mcp.WithString("back_extra",
+			mcp.Description("Optional: Extra information shown on the back of the cards"),
+		),
+		mcp.WithArray("tags",
+			mcp.Description("Optional: Tags for the note"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("model_name",
+			mcp.Description("Optional: Cloze note type to use (default: Cloze)"),
+		),
+		withDuplicateOptions(),
+	)
+	a.addChangingTool(s, createClozeTool, (*AnkiMCPServer).handleCreateClozeCard)
+}
+
+// handleNumberClozes numbers the cloze deletions in a text
+func (a *AnkiMCPServer) handleNumberClozes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	text, ok := args["text"].(string)
+	if !ok || strings.TrimSpace(text) == "" {
+		return a.errorf("text is required"), nil
+	}
+	mode, _ := args["mode"].(string)
+
+	numbered, cards, err := numberClozes(text, mode)
+	if err != nil {
+		return a.errorf("Failed to number clozes: %v", err), nil
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Cloze text (%d card(s))", cards))
+	out.Line(numbered)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleCreateClozeCard hides target words or key terms of a sentence and
+// creates a Cloze note from it
+func (a *AnkiMCPServer) handleCreateClozeCard(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	text, ok := args["text"].(string)
+	if !ok || strings.TrimSpace(text) == "" {
+		return a.errorf("text is required"), nil
+	}
+	deckName, ok := args["deck"].(string)
+	if !ok || strings.TrimSpace(deckName) == "" {
+		return a.errorf("deck is required"), nil
+	}
+	tags := stringSliceValue(args, "tags")
+	for _, tag := range tags {
+		if !validTag(tag) {
+			return a.errorf("Invalid tag %q: tags can't be empty or contain spaces", tag), nil
+		}
+	}
+	options, errResult := a.noteOptions(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+	modelName := "Cloze"
+	if name, ok := args["model_name"].(string); ok && strings.TrimSpace(name) != "" {
+		modelName = name
+	}
+
+	// The note type must hide text with a cloze: template
+	if errResult := a.checkModel(modelName); errResult != nil {
+		return errResult, nil
+	}
+	templates, err := a.ankiClient.GetModelTemplates(modelName)
+	if err != nil {
+		return a.errorf("Failed to get templates: %v", err), nil
+	}
+	var textField string
+	for _, tmpl := range templates {
+		if m := clozeFieldPattern.FindStringSubmatch(tmpl.Front); m != nil {
+			textField = strings.TrimSpace(m[1])
+			break
+		}
+	}
+	if textField == "" {
+		return a.errorf("Note type %s is not a cloze note type", modelName), nil
+	}
+	modelFields, err := a.ankiClient.GetModelFieldNames(modelName)
+	if err != nil {
+		return a.errorf("Failed to get fields: %v", err), nil
+	}
+
+	targets := stringSliceValue(args, "targets")
+	var auto []string
+	if len(targets) == 0 && !clozePattern.MatchString(text) {
+		maxTerms := 3
+		if n := int(numberValue(args, "max_terms")); n > 0 {
+			maxTerms = n
+		}
+		auto = clozeKeyTerms(text, maxTerms)
+		if len(auto) == 0 {
+			return a.errorf("No key terms found; pass the words to hide as targets"), nil
+		}
+		targets = auto
+	}
+	oneCard, _ := args["one_card"].(bool)
+	clozeText, cards, missing, err := clozeTerms(text, targets, oneCard)
+	if len(missing) > 0 {
+		return a.errorf("Not found in the text: %s", strings.Join(missing, ", ")), nil
+	}
+	if err != nil {
+		return a.errorf("Failed to number clozes: %v", err), nil
+	}
+
+	fields := map[string]string{textField: clozeText}
+	if backExtra, _ := args["back_extra"].(string); backExtra != "" {
+		i := slices.IndexFunc(modelFields, func(f string) bool { return f != textField })
+		if i < 0 {
+			return a.errorf("Note type %s has no field for back_extra", modelName), nil
+		}
+		fields[modelFields[i]] = backExtra
+	}
+
+	// Send the card to the deck chosen by the tag routing rules
+	var route *RoutingRule
+	if a.routing.Auto {
+		if rule, ok := a.routing.deckFor(tags); ok && rule.Deck != deckName {
+			if err := a.ankiClient.CreateDeck(rule.Deck); err != nil {
+				return a.errorf("Failed to create deck: %v", err), nil
+			}
+			deckName = rule.Deck
+			route = &rule
+		}
+	}
+
+	noteID, err := a.ankiClient.AddNote(Note{
+		DeckName:  deckName,
+		ModelName: modelName,
+		Fields:    fields,
+		Tags:      tags,
+		Options:   options,
+	})
+	if err != nil {
+		return a.errorf("Failed to create card: %v", err), nil
+	}
+
+	out := a.newOutput()
+	out.Line(a.t("Created cloze note (ID: %d) with %d card(s)", noteID, cards))
+	if len(auto) > 0 {
+		out.Line(a.t("Key terms: %s", strings.Join(auto, ", ")))
+	}
+	if route != nil {
+		out.Line(a.t("Routed to deck %s by the tag rule %s", route.Deck, route.Tag))
+	}
+	out.Heading(a.t("Cloze text"))
+	out.Line(clozeText)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
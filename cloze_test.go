@@ -0,0 +1,111 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNumberClozes(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		mode  string
+		want  string
+		cards int
+	}{
+		{"plain", "{{Paris}} is the capital of {{France}}", "", "{{c1::Paris}} is the capital of {{c2::France}}", 2},
+		{"hint", "{{Paris::city}} is in {{France}}", "", "{{c1::Paris::city}} is in {{c2::France}}", 2},
+		{"renumber", "{{c3::a}} {{c3::b}} {{c7::c}} {{d}}", clozeSequential, "{{c1::a}} {{c1::b}} {{c2::c}} {{c3::d}}", 3},
+		{"overlapping", "{{c1::a}} {{c1::b}} {{c1::c}}", clozeOverlapping, "{{c1::a}} {{c2::b}} {{c3::c}}", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, cards, err := numberClozes(tt.text, tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want || cards != tt.cards {
+				t.Errorf("Got %q (%d cards), want %q (%d cards)", got, cards, tt.want, tt.cards)
+			}
+		})
+	}
+
+	if _, _, err := numberClozes("no deletions here", ""); err == nil {
+		t.Error("Expected an error for text without deletions")
+	}
+	if _, _, err := numberClozes("{{a}}", "random"); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+}
+
+func TestNumberClozesTool(t *testing.T) {
+	server, _ := newMockServer(t)
+
+	text, isErr := callTool(t, server.handleNumberClozes, map[string]interface{}{"text": "{{ser}} vs {{estar}}"})
+	if isErr || !strings.Contains(text, "2 card(s)") || !strings.Contains(text, "{{c1::ser}} vs {{c2::estar}}") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+}
+
+func TestClozeKeyTerms(t *testing.T) {
+	got := clozeKeyTerms("Columbus reached the Bahamas in 1492 after sailing from Palos.", 3)
+	want := []string{"Bahamas", "1492", "Palos"}
+	if !slices.Equal(got, want) {
+		t.Errorf("clozeKeyTerms() = %v, want %v", got, want)
+	}
+}
+
+func TestClozeTerms(t *testing.T) {
+	text, cards, missing, err := clozeTerms("Ser is permanent; estar is temporary. <b>Ser</b> vs {{c1::estar}}", []string{"ser::verb", "temporary", "mañana"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{{c1::Ser::verb}} is permanent; estar is {{c2::temporary}}. <b>{{c1::Ser::verb}}</b> vs {{c3::estar}}"
+	if text != want || cards != 3 {
+		t.Errorf("Got %q (%d cards), want %q", text, cards, want)
+	}
+	if !slices.Equal(missing, []string{"mañana"}) {
+		t.Errorf("Expected mañana to be missing, got %v", missing)
+	}
+
+	text, cards, _, _ = clozeTerms("París es la capital de Francia", []string{"París", "Francia"}, true)
+	if text != "{{c1::París}} es la capital de {{c1::Francia}}" || cards != 1 {
+		t.Errorf("Got %q (%d cards)", text, cards)
+	}
+}
+
+func TestCreateClozeCard(t *testing.T) {
+	server, mock := newMockServer(t)
+
+	args := map[string]interface{}{"text": "The mitochondria is the powerhouse of the cell", "deck": "Default", "model_name": "Basic"}
+	if text, isErr := callTool(t, server.handleCreateClozeCard, args); !isErr || !strings.Contains(text, "not a cloze note type") {
+		t.Errorf("Expected a note type error, got: %s", text)
+	}
+
+	delete(args, "model_name")
+	args["targets"] = []interface{}{"nucleus"}
+	if text, isErr := callTool(t, server.handleCreateClozeCard, args); !isErr || !strings.Contains(text, "Not found in the text: nucleus") {
+		t.Errorf("Expected a missing target error, got: %s", text)
+	}
+
+	args["targets"] = []interface{}{"mitochondria", "powerhouse"}
+	args["back_extra"] = "Biology 101"
+	text, isErr := callTool(t, server.handleCreateClozeCard, args)
+	if isErr || !strings.Contains(text, "with 2 card(s)") || !strings.Contains(text, "The {{c1::mitochondria}} is the {{c2::powerhouse}} of the cell") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	var note *mockNote
+	for _, n := range mock.notes {
+		note = n
+	}
+	if note.Fields["Back Extra"] != "Biology 101" || len(mock.cards) != 2 {
+		t.Errorf("Expected 2 cards and the back extra, got %d cards and note %+v", len(mock.cards), note)
+	}
+
+	args = map[string]interface{}{"text": "Berlin became the capital of Germany in 1990", "deck": "Default"}
+	text, isErr = callTool(t, server.handleCreateClozeCard, args)
+	if isErr || !strings.Contains(text, "Key terms: capital, Germany, 1990") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+}
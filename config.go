@@ -0,0 +1,403 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the server settings
+type Config struct {
+	// AnkiConnectURL is the address of the AnkiConnect addon
+	AnkiConnectURL string
+	// AnkiConnectAPIKey is sent with every request when AnkiConnect requires
+	// an API key
+	AnkiConnectAPIKey string
+	// Retry controls how AnkiConnect requests are retried when Anki is not
+	// reachable
+	Retry RetryPolicy
+	// Timeout is how long an AnkiConnect request may take; zero keeps the
+	// client default
+	Timeout time.Duration
+	// ActionTimeouts allows single AnkiConnect actions to take longer, on top
+	// of the client's defaults for long-running actions
+	ActionTimeouts map[string]time.Duration
+	// RateLimit caps the AnkiConnect requests in flight and per second
+	RateLimit RateLimit
+	// Language is the language used for tool output, e.g. "en" or "es"
+	Language string
+	// OutputStyle is either "markdown" (default) or "plain" for markup-free output
+	OutputStyle string
+	// Mock serves tools from an in-memory demo collection instead of AnkiConnect
+	Mock bool
+	// DryRun makes tools that change the collection report what they would
+	// change instead, as if every call passed dry_run
+	DryRun bool
+	// RolloverHour is the hour at which the Anki day starts, Anki's "Next
+	// day starts at" preference
+	RolloverHour int
+	// StateDir is where the server keeps state between runs, such as
+	// temporary deck limit changes that must be undone later
+	StateDir string
+	// ConfigFile is the optional JSON file holding settings that don't fit
+	// into environment variables
+	ConfigFile string
+	// Routing holds the tag-to-deck routing rules from the config file
+	Routing RoutingConfig
+	// TTS configures the text-to-speech backend from the config file
+	TTS TTSConfig
+	// Instances are further AnkiConnect endpoints from the config file
+	Instances []InstanceConfig
+	// Launch starts Anki when AnkiConnect is not running, set in the config
+	// file and by ANKI_MCP_AUTO_LAUNCH and ANKI_MCP_ANKI_PATH
+	Launch LaunchConfig
+	// Sanitize cleans field HTML before notes are sent, set in the config
+	// file and by ANKI_MCP_SANITIZE
+	Sanitize SanitizeConfig
+	// Recipes are the card presets from the config file
+	Recipes []RecipeConfig
+	// Vocab is the note type of create_vocab_card from the config file
+	Vocab VocabConfig
+	// Transport is how MCP clients connect: "stdio" (default), "http" for
+	// Streamable HTTP or "sse" for the older HTTP+SSE transport
+	Transport string
+	// HTTPAddr is the address the http and sse transports listen on
+	HTTPAddr string
+	// HTTPToken is the bearer token clients of the http and sse transports
+	// must send; it is required unless they listen on a loopback address
+	HTTPToken string
+	// LogLevel is the lowest level written to the log
+	LogLevel slog.Level
+	// LogFile is the file the log is appended to; empty logs to stderr
+	LogFile string
+	// LogOutput receives the log, opened from LogFile; nil discards it
+	LogOutput io.Writer
+}
+
+// Transports selectable with ANKI_MCP_TRANSPORT or --transport
+const (
+	transportStdio = "stdio"
+	transportHTTP  = "http"
+	transportSSE   = "sse"
+)
+
+// defaultHTTPAddr is the listen address of the HTTP transports
+const defaultHTTPAddr = "localhost:8080"
+
+// fileConfig is the layout of the JSON config file
+type fileConfig struct {
+	Routing   RoutingConfig    `json:"routing"`
+	TTS       TTSConfig        `json:"tts"`
+	Instances []InstanceConfig `json:"instances"`
+	Launch    LaunchConfig     `json:"launch"`
+	Sanitize  SanitizeConfig   `json:"sanitize"`
+	Recipes   []RecipeConfig   `json:"recipes"`
+	Vocab     VocabConfig      `json:"vocab"`
+}
+
+// loadConfig reads the server configuration from environment variables and
+// the config file. A broken config file is reported and otherwise ignored, so
+// the server still starts.
+func loadConfig() Config {
+	config := Config{
+		AnkiConnectURL:    os.Getenv("ANKI_CONNECT_URL"),
+		AnkiConnectAPIKey: os.Getenv("ANKI_CONNECT_API_KEY"),
+		Language:          os.Getenv("ANKI_MCP_LANG"),
+		OutputStyle:       strings.ToLower(os.Getenv("ANKI_MCP_OUTPUT")),
+		StateDir:          os.Getenv("ANKI_MCP_STATE_DIR"),
+		ConfigFile:        os.Getenv("ANKI_MCP_CONFIG"),
+		Transport:         strings.ToLower(os.Getenv("ANKI_MCP_TRANSPORT")),
+		HTTPAddr:          os.Getenv("ANKI_MCP_HTTP_ADDR"),
+		HTTPToken:         os.Getenv("ANKI_MCP_HTTP_TOKEN"),
+		LogFile:           os.Getenv("ANKI_MCP_LOG_FILE"),
+	}
+
+	if config.AnkiConnectURL == "" {
+		config.AnkiConnectURL = defaultAnkiConnectURL
+	}
+	if config.Language == "" {
+		config.Language = defaultLanguage
+	}
+	if config.OutputStyle != outputPlain {
+		config.OutputStyle = outputMarkdown
+	}
+	retry, err := retryPolicyFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "anki-mcp: ignoring retry settings: %v\n", err)
+		retry = defaultRetryPolicy
+	}
+	config.Retry = retry
+	if config.Timeout, err = durationFromEnv("ANKI_CONNECT_TIMEOUT"); err != nil {
+		fmt.Fprintf(os.Stderr, "anki-mcp: ignoring timeout setting: %v\n", err)
+	}
+	if config.ActionTimeouts, err = actionTimeoutsFromEnv(); err != nil {
+		fmt.Fprintf(os.Stderr, "anki-mcp: ignoring action timeout settings: %v\n", err)
+	}
+	if config.RateLimit, err = rateLimitFromEnv(); err != nil {
+		fmt.Fprintf(os.Stderr, "anki-mcp: ignoring rate limit settings: %v\n", err)
+		config.RateLimit = RateLimit{MaxConcurrent: defaultMaxConcurrent}
+	}
+	config.RolloverHour = defaultRolloverHour
+	if v := os.Getenv("ANKI_MCP_ROLLOVER_HOUR"); v != "" {
+		hour, err := strconv.Atoi(v)
+		if err != nil || hour < 0 || hour > 23 {
+			fmt.Fprintf(os.Stderr, "anki-mcp: ignoring ANKI_MCP_ROLLOVER_HOUR: expected an hour from 0 to 23, got %q\n", v)
+		} else {
+			config.RolloverHour = hour
+		}
+	}
+	if v := os.Getenv("ANKI_MCP_DRY_RUN"); v != "" {
+		if config.DryRun, err = strconv.ParseBool(v); err != nil {
+			fmt.Fprintf(os.Stderr, "anki-mcp: ignoring ANKI_MCP_DRY_RUN: expected true or false, got %q\n", v)
+		}
+	}
+	if config.LogLevel, err = parseLogLevel(os.Getenv("ANKI_MCP_LOG_LEVEL")); err != nil {
+		fmt.Fprintf(os.Stderr, "anki-mcp: ignoring ANKI_MCP_LOG_LEVEL: %v\n", err)
+	}
+
+	if config.Transport == "" {
+		config.Transport = transportStdio
+	}
+	if config.HTTPAddr == "" {
+		config.HTTPAddr = defaultHTTPAddr
+	}
+	if config.StateDir == "" {
+		config.StateDir = defaultConfigDir()
+	}
+	if config.ConfigFile == "" {
+		config.ConfigFile = filepath.Join(defaultConfigDir(), "config.json")
+	}
+
+	if err := loadConfigFile(config.ConfigFile, &config); err != nil {
+		fmt.Fprintf(os.Stderr, "anki-mcp: ignoring config file: %v\n", err)
+	}
+	// The environment takes precedence over the launch and sanitize sections
+	// of the file
+	if v := os.Getenv("ANKI_MCP_AUTO_LAUNCH"); v != "" {
+		if config.Launch.Enabled, err = strconv.ParseBool(v); err != nil {
+			fmt.Fprintf(os.Stderr, "anki-mcp: ignoring ANKI_MCP_AUTO_LAUNCH: expected true or false, got %q\n", v)
+		}
+	}
+	if v := os.Getenv("ANKI_MCP_ANKI_PATH"); v != "" {
+		paths := map[string]string{runtime.GOOS: v}
+		for goos, path := range config.Launch.Paths {
+			if goos != runtime.GOOS {
+				paths[goos] = path
+			}
+		}
+		config.Launch.Paths = paths
+	}
+	if v := os.Getenv("ANKI_MCP_SANITIZE"); v != "" {
+		if config.Sanitize.Enabled, err = strconv.ParseBool(v); err != nil {
+			fmt.Fprintf(os.Stderr, "anki-mcp: ignoring ANKI_MCP_SANITIZE: expected true or false, got %q\n", v)
+		}
+	}
+
+	return config
+}
+
+// retryPolicyFromEnv reads the retry policy from ANKI_CONNECT_RETRY_ATTEMPTS,
+// ANKI_CONNECT_RETRY_BACKOFF and ANKI_CONNECT_RETRY_JITTER, using the default
+// for unset variables
+func retryPolicyFromEnv() (RetryPolicy, error) {
+	policy := defaultRetryPolicy
+	if v := os.Getenv("ANKI_CONNECT_RETRY_ATTEMPTS"); v != "" {
+		attempts, err := strconv.Atoi(v)
+		if err != nil || attempts < 1 {
+			return policy, fmt.Errorf("ANKI_CONNECT_RETRY_ATTEMPTS must be a number of at least 1, got %q", v)
+		}
+		policy.Attempts = attempts
+	}
+	if v := os.Getenv("ANKI_CONNECT_RETRY_BACKOFF"); v != "" {
+		backoff, err := time.ParseDuration(v)
+		if err != nil || backoff < 0 {
+			return policy, fmt.Errorf("ANKI_CONNECT_RETRY_BACKOFF must be a duration such as 500ms, got %q", v)
+		}
+		policy.Backoff = backoff
+	}
+	if v := os.Getenv("ANKI_CONNECT_RETRY_JITTER"); v != "" {
+		jitter, err := strconv.ParseFloat(v, 64)
+		if err != nil || jitter < 0 || jitter > 1 {
+			return policy, fmt.Errorf("ANKI_CONNECT_RETRY_JITTER must be a number from 0 to 1, got %q", v)
+		}
+		policy.Jitter = jitter
+	}
+	return policy, nil
+}
+
+// rateLimitFromEnv reads the rate limit from ANKI_CONNECT_MAX_CONCURRENT and
+// ANKI_CONNECT_RATE_LIMIT, using the default for unset variables
+func rateLimitFromEnv() (RateLimit, error) {
+	limit := RateLimit{MaxConcurrent: defaultMaxConcurrent}
+	if v := os.Getenv("ANKI_CONNECT_MAX_CONCURRENT"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return limit, fmt.Errorf("ANKI_CONNECT_MAX_CONCURRENT must be a number of at least 0, got %q", v)
+		}
+		limit.MaxConcurrent = n
+	}
+	if v := os.Getenv("ANKI_CONNECT_RATE_LIMIT"); v != "" {
+		perSecond, err := strconv.ParseFloat(v, 64)
+		if err != nil || perSecond < 0 {
+			return limit, fmt.Errorf("ANKI_CONNECT_RATE_LIMIT must be a number of requests per second, got %q", v)
+		}
+		limit.PerSecond = perSecond
+	}
+	return limit, nil
+}
+
+// durationFromEnv reads a positive duration such as 45s from an environment
+// variable, returning zero if it is unset
+func durationFromEnv(name string) (time.Duration, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a duration such as 45s or 2m, got %q", name, v)
+	}
+	return d, nil
+}
+
+// actionTimeoutsFromEnv reads per-action timeouts from
+// ANKI_CONNECT_ACTION_TIMEOUTS, a comma-separated list such as
+// "sync=10m,exportPackage=30m"
+func actionTimeoutsFromEnv() (map[string]time.Duration, error) {
+	v := os.Getenv("ANKI_CONNECT_ACTION_TIMEOUTS")
+	if strings.TrimSpace(v) == "" {
+		return nil, nil
+	}
+	timeouts := make(map[string]time.Duration)
+	for _, entry := range strings.Split(v, ",") {
+		action, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if !ok || strings.TrimSpace(action) == "" || err != nil || d <= 0 {
+			return nil, fmt.Errorf("ANKI_CONNECT_ACTION_TIMEOUTS entries must look like sync=10m, got %q", entry)
+		}
+		timeouts[strings.TrimSpace(action)] = d
+	}
+	return timeouts, nil
+}
+
+// applyFlags applies command line flags on top of the environment settings:
+// --mock, --dry-run, --transport <stdio|http|sse>, --addr <host:port>,
+// --log-level <level> and --log-file <path>. Flag values may also be given as
+// --flag=value; the switches take an optional boolean, as in --mock=false.
+func applyFlags(config *Config, args []string) error {
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		switch name {
+		case "--mock", "--dry-run":
+			on := true
+			if hasValue {
+				var err error
+				if on, err = strconv.ParseBool(value); err != nil {
+					return fmt.Errorf("%s takes true or false, got %q", name, value)
+				}
+			}
+			if name == "--mock" {
+				config.Mock = on
+			} else {
+				config.DryRun = on
+			}
+			continue
+		case "--transport", "--addr", "--log-level", "--log-file":
+		default:
+			return fmt.Errorf("unknown flag %s", args[i])
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s needs a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		switch name {
+		case "--transport":
+			config.Transport = strings.ToLower(value)
+		case "--addr":
+			config.HTTPAddr = value
+		case "--log-level":
+			level, err := parseLogLevel(value)
+			if err != nil {
+				return err
+			}
+			config.LogLevel = level
+		case "--log-file":
+			config.LogFile = value
+		}
+	}
+
+	switch config.Transport {
+	case transportStdio, transportHTTP, transportSSE:
+		return nil
+	default:
+		return fmt.Errorf("unknown transport %q, expected stdio, http or sse", config.Transport)
+	}
+}
+
+// loadConfigFile applies the settings of a JSON config file. A missing file
+// is not an error.
+func loadConfigFile(path string, config *Config) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var file fileConfig
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := file.Routing.validate(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := file.TTS.validate(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := validateInstances(file.Instances); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := file.Launch.validate(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := file.Sanitize.validate(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := validateRecipes(file.Recipes); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := file.Vocab.validate(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	config.Routing = file.Routing
+	config.TTS = file.TTS
+	config.Instances = file.Instances
+	config.Launch = file.Launch
+	config.Sanitize = file.Sanitize
+	config.Recipes = file.Recipes
+	config.Vocab = file.Vocab
+	return nil
+}
+
+// defaultConfigDir returns the anki-mcp folder in the user's config directory
+func defaultConfigDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "anki-mcp")
+}
@@ -0,0 +1,691 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// defaultPresetID is the ID of Anki's Default options preset, which can't be
+// removed
+const defaultPresetID = 1
+
+// deckPreset is a deck options group ("preset" in Anki's UI)
+type deckPreset struct {
+	ID    int64
+	Name  string
+	Decks []string
+}
+
+// deckOptions holds the commonly tuned settings of a deck's options preset
+type deckOptions struct {
+	Deck               string   `json:"deck"`
+	PresetID           int64    `json:"preset_id"`
+	Preset             string   `json:"preset"`
+	SharedWith         []string `json:"shared_with"`
+	NewCardsPerDay     int      `json:"new_cards_per_day"`
+	MaxReviewsPerDay   int      `json:"max_reviews_per_day"`
+	LearningSteps      []string `json:"learning_steps"`
+	RelearningSteps    []string `json:"relearning_steps"`
+	GraduatingInterval int      `json:"graduating_interval"`
+	EasyInterval       int      `json:"easy_interval"`
+	MaximumInterval    int      `json:"maximum_interval"`
+	LeechThreshold     int      `json:"leech_threshold"`
+}
+
+// registerDeckConfigTools registers deck options tools with the MCP server
+func (a *AnkiMCPServer) registerDeckConfigTools(s *server.MCPServer) {
+	// Tool: Apply Deck Preset
+	applyPresetTool := mcp.NewTool("apply_deck_preset",
+		mcp.WithDescription("Apply a saved deck options preset (options group) to one or more decks, e.g. put all language decks on the \"FSRS aggressive\" preset. "+
+			"The preset must already be used by at least one deck, since Anki only exposes presets through the decks using them."),
+		mcp.WithString("preset",
+			mcp.Required(),
+			mcp.Description("Name of the preset (case-insensitive) or its numeric ID"),
+		),
+		mcp.WithArray("decks",
+			mcp.Required(),
+			mcp.Description("Names of the decks to apply the preset to"),
+			mcp.WithStringItems(),
+		),
+	)
+	a.addChangingTool(s, applyPresetTool, (*AnkiMCPServer).handleApplyDeckPreset)
+
+	// Tool: Clone Deck Preset
+	clonePresetTool := mcp.NewTool("clone_deck_preset",
+		mcp.WithDescription("Create a new deck options preset as a copy of an existing one, e.g. an \"Exam cram\" preset to tune with update_deck_config. "+
+			"Pass decks to switch them to the new preset right away; a preset no deck uses can only be found again by its ID."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the new preset"),
+		),
+		mcp.WithString("from",
+			mcp.Description("Optional: Name or ID of the preset to copy (default: Default)"),
+		),
+		mcp.WithArray("decks",
+			mcp.Description("Optional: Decks to switch to the new preset"),
+			mcp.WithStringItems(),
+		),
+	)
+	a.addChangingTool(s, clonePresetTool, (*AnkiMCPServer).handleCloneDeckPreset)
+
+	// Tool: Remove Deck Preset
+	removePresetTool := mcp.NewTool("remove_deck_preset",
+		mcp.WithDescription("Delete a deck options preset. Decks using it fall back to the Default preset. The Default preset can't be removed."),
+		mcp.WithString("preset",
+			mcp.Required(),
+			mcp.Description("Name of the preset (case-insensitive) or its numeric ID"),
+		),
+	)
+	a.addChangingTool(s, removePresetTool, (*AnkiMCPServer).handleRemoveDeckPreset)
+
+	// Tool: Get Deck Config
+	getDeckConfigTool := mcp.NewTool("get_deck_config",
+		mcp.WithDescription("Show the options of a deck: daily limits, learning and relearning steps, intervals and leech threshold, and which other decks share its preset."),
+		mcp.WithString("deck",
+			mcp.Required(),
+			mcp.Description("Name of the deck"),
+		),
+		withFormat(),
+	)
+	a.addTool(s, getDeckConfigTool, (*AnkiMCPServer).handleGetDeckConfig)
+
+	// Tool: Update Deck Config
+	updateDeckConfigTool := mcp.NewTool("update_deck_config",
+		mcp.WithDescription("Change the options of a deck, e.g. lower new cards per day when the user is falling behind on reviews. "+
+			"Options live in the deck's preset, so the change applies to every deck sharing it; use get_deck_config first to check. Only the given options change."),
+		mcp.WithString("deck",
+			mcp.Required(),
+			mcp.Description("Name of the deck"),
+		),
+		mcp.WithNumber("new_cards_per_day",
+			mcp.Description("Optional: Maximum number of new cards introduced per day"),
+		),
+		mcp.WithNumber("max_reviews_per_day",
+			mcp.Description("Optional: Maximum number of reviews shown per day"),
+		),
+		mcp.WithArray("learning_steps",
+			mcp.Description("Optional: Learning steps for new cards, e.g. [\"1m\", \"10m\", \"1d\"] (s, m, h or d; plain numbers are minutes)"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithArray("relearning_steps",
+			mcp.Description("Optional: Relearning steps for forgotten cards, in the same format as learning_steps"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithNumber("graduating_interval",
+			mcp.Description("Optional: Days until a card that finished its learning steps is shown again"),
+		),
+		mcp.WithNumber("easy_interval",
+			mcp.Description("Optional: Days until a new card answered Easy is shown again"),
+		),
+		mcp.WithNumber("maximum_interval",
+			mcp.Description("Optional: Longest interval in days between reviews"),
+		),
+		mcp.WithNumber("leech_threshold",
+			mcp.Description("Optional: Number of lapses after which a card is tagged as a leech"),
+		),
+	)
+	a.addChangingTool(s, updateDeckConfigTool, (*AnkiMCPServer).handleUpdateDeckConfig)
+}
+
+// handleApplyDeckPreset assigns a deck options preset to decks
+func (a *AnkiMCPServer) handleApplyDeckPreset(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	presetName, ok := args["preset"].(string)
+	if !ok || strings.TrimSpace(presetName) == "" {
+		return a.errorf("preset is required"), nil
+	}
+	decks := stringSliceValue(args, "decks")
+	if len(decks) == 0 {
+		return a.errorf("decks is required"), nil
+	}
+
+	allDecks, err := a.ankiClient.GetDeckNames()
+	if err != nil {
+		return a.errorf("Failed to get decks: %v", err), nil
+	}
+	configs, err := a.ankiClient.GetDeckConfigs(allDecks)
+	if err != nil {
+		return a.errorf("Failed to get deck options: %v", err), nil
+	}
+
+	for _, deck := range decks {
+		if _, ok := configs[deck]; !ok {
+			return a.errorf("Deck not found: %s", deck), nil
+		}
+	}
+
+	preset, errResult := a.lookupDeckPreset(configs, presetName)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	if err := a.ankiClient.SetDeckConfigID(decks, preset.ID); err != nil {
+		return a.errorf("Failed to apply preset: %v", err), nil
+	}
+	// Presets no deck used are only known by ID until they are applied
+	if preset.Name == "" {
+		if config, err := a.ankiClient.GetDeckConfig(decks[0]); err == nil {
+			preset.Name = stringValue(config, "name")
+		}
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Applied preset \"%s\" to %d deck(s)", preset.Name, len(decks)))
+	for _, deck := range decks {
+		previous := stringValue(configs[deck], "name")
+		if int64(numberValue(configs[deck], "id")) == preset.ID {
+			out.Item(a.t("%s (already using this preset)", deck))
+		} else {
+			out.Item(a.t("%s (was: %s)", deck, previous))
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// lookupDeckPreset finds a preset by name or ID among the presets used by
+// decks. A numeric ID no deck uses is passed through with an empty name, since
+// AnkiConnect can still act on it.
+func (a *AnkiMCPServer) lookupDeckPreset(configs map[string]map[string]interface{}, nameOrID string) (*deckPreset, *mcp.CallToolResult) {
+	presets := deckPresets(configs)
+	if preset := findDeckPreset(presets, nameOrID); preset != nil {
+		return preset, nil
+	}
+	if id, err := strconv.ParseInt(strings.TrimSpace(nameOrID), 10, 64); err == nil {
+		return &deckPreset{ID: id}, nil
+	}
+
+	names := make([]string, len(presets))
+	for i, p := range presets {
+		names[i] = p.Name
+	}
+	return nil, a.errorf("Preset not found: %s. Available presets: %s", nameOrID, strings.Join(names, ", "))
+}
+
+// deckPresets groups deck options by preset, sorted by name
+func deckPresets(configs map[string]map[string]interface{}) []deckPreset {
+	byID := make(map[int64]*deckPreset)
+	for deck, config := range configs {
+		id := int64(numberValue(config, "id"))
+		p, ok := byID[id]
+		if !ok {
+			p = &deckPreset{ID: id, Name: stringValue(config, "name")}
+			byID[id] = p
+		}
+		p.Decks = append(p.Decks, deck)
+	}
+
+	presets := make([]deckPreset, 0, len(byID))
+	for _, p := range byID {
+		sort.Strings(p.Decks)
+		presets = append(presets, *p)
+	}
+	sort.Slice(presets, func(i, j int) bool {
+		if presets[i].Name != presets[j].Name {
+			return presets[i].Name < presets[j].Name
+		}
+		return presets[i].ID < presets[j].ID
+	})
+	return presets
+}
+
+// findDeckPreset looks up a preset by name, ignoring case, or by numeric ID
+func findDeckPreset(presets []deckPreset, nameOrID string) *deckPreset {
+	nameOrID = strings.TrimSpace(nameOrID)
+	for i := range presets {
+		if strings.EqualFold(presets[i].Name, nameOrID) {
+			return &presets[i]
+		}
+	}
+	if id, err := strconv.ParseInt(nameOrID, 10, 64); err == nil {
+		for i := range presets {
+			if presets[i].ID == id {
+				return &presets[i]
+			}
+		}
+	}
+	return nil
+}
+
+// handleCloneDeckPreset copies a deck options preset
+func (a *AnkiMCPServer) handleCloneDeckPreset(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	name, _ := args["name"].(string)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return a.errorf("name is required"), nil
+	}
+	from, _ := args["from"].(string)
+	if strings.TrimSpace(from) == "" {
+		from = strconv.Itoa(defaultPresetID)
+	}
+	decks := stringSliceValue(args, "decks")
+
+	allDecks, err := a.ankiClient.GetDeckNames()
+	if err != nil {
+		return a.errorf("Failed to get decks: %v", err), nil
+	}
+	configs, err := a.ankiClient.GetDeckConfigs(allDecks)
+	if err != nil {
+		return a.errorf("Failed to get deck options: %v", err), nil
+	}
+	for _, deck := range decks {
+		if _, ok := configs[deck]; !ok {
+			return a.errorf("Deck not found: %s", deck), nil
+		}
+	}
+	if existing := findDeckPreset(deckPresets(configs), name); existing != nil && strings.EqualFold(existing.Name, name) {
+		return a.errorf("A preset named %s already exists (ID: %d)", existing.Name, existing.ID), nil
+	}
+	source, errResult := a.lookupDeckPreset(configs, from)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	id, err := a.ankiClient.CloneDeckConfigID(name, source.ID)
+	if err != nil {
+		return a.errorf("Failed to create preset: %v", err), nil
+	}
+
+	out := a.newOutput()
+	if source.Name != "" {
+		out.Heading(a.t("Created preset \"%s\" (ID: %d) as a copy of \"%s\"", name, id, source.Name))
+	} else {
+		out.Heading(a.t("Created preset \"%s\" (ID: %d) as a copy of preset %d", name, id, source.ID))
+	}
+	if len(decks) > 0 {
+		if err := a.ankiClient.SetDeckConfigID(decks, id); err != nil {
+			return a.errorf("Created preset %d, but failed to apply it: %v", id, err), nil
+		}
+		for _, deck := range decks {
+			out.Item(a.t("%s (was: %s)", deck, stringValue(configs[deck], "name")))
+		}
+	} else {
+		out.Line(a.t("No deck uses it yet. Apply it with apply_deck_preset using its ID."))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleRemoveDeckPreset deletes a deck options preset
+func (a *AnkiMCPServer) handleRemoveDeckPreset(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	presetName, ok := args["preset"].(string)
+	if !ok || strings.TrimSpace(presetName) == "" {
+		return a.errorf("preset is required"), nil
+	}
+
+	allDecks, err := a.ankiClient.GetDeckNames()
+	if err != nil {
+		return a.errorf("Failed to get decks: %v", err), nil
+	}
+	configs, err := a.ankiClient.GetDeckConfigs(allDecks)
+	if err != nil {
+		return a.errorf("Failed to get deck options: %v", err), nil
+	}
+	preset, errResult := a.lookupDeckPreset(configs, presetName)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if preset.ID == defaultPresetID {
+		return a.errorf("The Default preset can't be removed"), nil
+	}
+
+	if err := a.ankiClient.RemoveDeckConfigID(preset.ID); err != nil {
+		return a.errorf("Failed to remove preset: %v", err), nil
+	}
+
+	name := preset.Name
+	if name == "" {
+		name = strconv.FormatInt(preset.ID, 10)
+	}
+	out := a.newOutput()
+	out.Heading(a.t("Removed preset \"%s\"", name))
+	if len(preset.Decks) > 0 {
+		out.Line(a.t("%d deck(s) now use the Default preset: %s", len(preset.Decks), strings.Join(preset.Decks, ", ")))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleGetDeckConfig shows the options of a deck
+func (a *AnkiMCPServer) handleGetDeckConfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	deckName, ok := args["deck"].(string)
+	if !ok || deckName == "" {
+		return a.errorf("deck is required"), nil
+	}
+
+	config, err := a.ankiClient.GetDeckConfig(deckName)
+	if err != nil {
+		return a.errorf("Failed to get deck options: %v", err), nil
+	}
+	shared, err := a.presetDecks(int64(numberValue(config, "id")), deckName)
+	if err != nil {
+		return a.errorf("Failed to get deck options: %v", err), nil
+	}
+	opts := configOptions(deckName, config, shared)
+
+	if wantsJSON(request) {
+		return a.jsonResult(opts), nil
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Options of %s (preset \"%s\")", opts.Deck, opts.Preset))
+	a.writeDeckOptions(out, opts)
+	if len(opts.SharedWith) > 0 {
+		out.Line("")
+		out.Line(a.t("This preset is shared with: %s", strings.Join(opts.SharedWith, ", ")))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleUpdateDeckConfig changes the options of a deck's preset
+func (a *AnkiMCPServer) handleUpdateDeckConfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	deckName, ok := args["deck"].(string)
+	if !ok || deckName == "" {
+		return a.errorf("deck is required"), nil
+	}
+
+	numbers := []struct {
+		arg     string
+		section string
+		key     string
+		min     int
+	}{
+		{"new_cards_per_day", "new", "perDay", 0},
+		{"max_reviews_per_day", "rev", "perDay", 0},
+		{"maximum_interval", "rev", "maxIvl", 1},
+		{"leech_threshold", "lapse", "leechFails", 1},
+	}
+	steps := map[string][]float64{}
+	for _, arg := range []string{"learning_steps", "relearning_steps"} {
+		if _, ok := args[arg]; !ok {
+			continue
+		}
+		parsed, err := parseSteps(stringSliceValue(args, arg))
+		if err != nil {
+			return a.errorf("Invalid %s: %v", arg, err), nil
+		}
+		steps[arg] = parsed
+	}
+	changed := len(steps) > 0
+	for _, arg := range []string{"new_cards_per_day", "max_reviews_per_day", "graduating_interval", "easy_interval", "maximum_interval", "leech_threshold"} {
+		if _, ok := args[arg]; ok {
+			changed = true
+		}
+	}
+	if !changed {
+		return a.errorf("Pass at least one option to change"), nil
+	}
+
+	a.limitsMu.Lock()
+	defer a.limitsMu.Unlock()
+
+	// Limits changed for today live in a temporary copy of the preset, which
+	// is thrown away at the end of the day along with any change made here
+	overrides, err := a.loadLimitOverrides()
+	if err != nil {
+		return a.errorf("Failed to update deck options: %v", err), nil
+	}
+	for _, o := range overrides {
+		if o.Deck == deckName {
+			return a.errorf("%s has temporary limits for today. Run restore_deck_limits first so the change isn't lost at the end of the day.", deckName), nil
+		}
+	}
+
+	config, err := a.ankiClient.GetDeckConfig(deckName)
+	if err != nil {
+		return a.errorf("Failed to get deck options: %v", err), nil
+	}
+	shared, err := a.presetDecks(int64(numberValue(config, "id")), deckName)
+	if err != nil {
+		return a.errorf("Failed to get deck options: %v", err), nil
+	}
+	before := configOptions(deckName, config, shared)
+
+	for _, n := range numbers {
+		v, ok := args[n.arg].(float64)
+		if !ok {
+			continue
+		}
+		if v < float64(n.min) || v != float64(int(v)) {
+			return a.errorf("%s must be a whole number of at least %d", n.arg, n.min), nil
+		}
+		configSection(config, n.section)[n.key] = v
+	}
+	if v, ok := steps["learning_steps"]; ok {
+		configSection(config, "new")["delays"] = numberList(v)
+	}
+	if v, ok := steps["relearning_steps"]; ok {
+		configSection(config, "lapse")["delays"] = numberList(v)
+	}
+
+	// The graduating and easy intervals are the first two entries of "ints"
+	ints := numberSliceValue(configSection(config, "new"), "ints")
+	for len(ints) < 3 {
+		ints = append(ints, []float64{1, 4, 0}[len(ints)])
+	}
+	for i, arg := range []string{"graduating_interval", "easy_interval"} {
+		v, ok := args[arg].(float64)
+		if !ok {
+			continue
+		}
+		if v < 1 || v != float64(int(v)) {
+			return a.errorf("%s must be a whole number of at least %d", arg, 1), nil
+		}
+		ints[i] = v
+	}
+	if ints[1] < ints[0] {
+		return a.errorf("easy_interval (%.0f) must not be shorter than graduating_interval (%.0f)", ints[1], ints[0]), nil
+	}
+	configSection(config, "new")["ints"] = numberList(ints)
+
+	if err := a.ankiClient.SaveDeckConfig(config); err != nil {
+		return a.errorf("Failed to update deck options: %v", err), nil
+	}
+	after := configOptions(deckName, config, shared)
+
+	out := a.newOutput()
+	out.Heading(a.t("Updated preset \"%s\" of %s", after.Preset, deckName))
+	changes := []struct {
+		label         string
+		before, after string
+	}{
+		{a.t("New cards/day"), strconv.Itoa(before.NewCardsPerDay), strconv.Itoa(after.NewCardsPerDay)},
+		{a.t("Maximum reviews/day"), strconv.Itoa(before.MaxReviewsPerDay), strconv.Itoa(after.MaxReviewsPerDay)},
+		{a.t("Learning steps"), strings.Join(before.LearningSteps, " "), strings.Join(after.LearningSteps, " ")},
+		{a.t("Relearning steps"), strings.Join(before.RelearningSteps, " "), strings.Join(after.RelearningSteps, " ")},
+		{a.t("Graduating interval"), formatDays(a.loc, float64(before.GraduatingInterval)), formatDays(a.loc, float64(after.GraduatingInterval))},
+		{a.t("Easy interval"), formatDays(a.loc, float64(before.EasyInterval)), formatDays(a.loc, float64(after.EasyInterval))},
+		{a.t("Maximum interval"), formatDays(a.loc, float64(before.MaximumInterval)), formatDays(a.loc, float64(after.MaximumInterval))},
+		{a.t("Leech threshold"), strconv.Itoa(before.LeechThreshold), strconv.Itoa(after.LeechThreshold)},
+	}
+	unchanged := true
+	for _, c := range changes {
+		if c.before != c.after {
+			out.Item(fmt.Sprintf("%s: %s → %s", c.label, c.before, c.after))
+			unchanged = false
+		}
+	}
+	if unchanged {
+		out.Line(a.t("The options already had these values."))
+	}
+	if len(shared) > 0 {
+		out.Line("")
+		out.Line(a.t("The change also applies to decks sharing this preset: %s", strings.Join(shared, ", ")))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// writeDeckOptions lists the settings of a deck's preset
+func (a *AnkiMCPServer) writeDeckOptions(out *textOutput, opts deckOptions) {
+	out.Item(a.t("New cards/day: %d", opts.NewCardsPerDay))
+	out.Item(a.t("Maximum reviews/day: %d", opts.MaxReviewsPerDay))
+	out.Item(a.t("Learning steps: %s", strings.Join(opts.LearningSteps, " ")))
+	out.Item(a.t("Relearning steps: %s", strings.Join(opts.RelearningSteps, " ")))
+	out.Item(a.t("Graduating interval: %s", formatDays(a.loc, float64(opts.GraduatingInterval))))
+	out.Item(a.t("Easy interval: %s", formatDays(a.loc, float64(opts.EasyInterval))))
+	out.Item(a.t("Maximum interval: %s", formatDays(a.loc, float64(opts.MaximumInterval))))
+	out.Item(a.t("Leech threshold: %d lapses", opts.LeechThreshold))
+}
+
+// presetDecks returns the decks other than exclude using the preset with the
+// given ID
+func (a *AnkiMCPServer) presetDecks(id int64, exclude string) ([]string, error) {
+	allDecks, err := a.ankiClient.GetDeckNames()
+	if err != nil {
+		return nil, err
+	}
+	configs, err := a.ankiClient.GetDeckConfigs(allDecks)
+	if err != nil {
+		return nil, err
+	}
+
+	var decks []string
+	for _, p := range deckPresets(configs) {
+		if p.ID == id {
+			decks = slices.DeleteFunc(p.Decks, func(d string) bool { return d == exclude })
+		}
+	}
+	return decks, nil
+}
+
+// configOptions reads the commonly tuned settings of an options group,
+// falling back to Anki's defaults for missing ones
+func configOptions(deck string, config map[string]interface{}, shared []string) deckOptions {
+	newConf := objectValue(config, "new")
+	lapseConf := objectValue(config, "lapse")
+	revConf := objectValue(config, "rev")
+
+	ints := numberSliceValue(newConf, "ints")
+	if len(ints) < 2 {
+		ints = []float64{1, 4}
+	}
+	limits := configLimits(config)
+	return deckOptions{
+		Deck:               deck,
+		PresetID:           int64(numberValue(config, "id")),
+		Preset:             stringValue(config, "name"),
+		SharedWith:         shared,
+		NewCardsPerDay:     limits.NewCards,
+		MaxReviewsPerDay:   limits.Reviews,
+		LearningSteps:      formatSteps(numberSliceValue(newConf, "delays")),
+		RelearningSteps:    formatSteps(numberSliceValue(lapseConf, "delays")),
+		GraduatingInterval: int(ints[0]),
+		EasyInterval:       int(ints[1]),
+		MaximumInterval:    int(numberOrDefault(revConf, "maxIvl", 36500)),
+		LeechThreshold:     int(numberOrDefault(lapseConf, "leechFails", 8)),
+	}
+}
+
+// configSection returns a section of an options group, creating it if needed
+func configSection(config map[string]interface{}, section string) map[string]interface{} {
+	settings, ok := config[section].(map[string]interface{})
+	if !ok {
+		settings = map[string]interface{}{}
+		config[section] = settings
+	}
+	return settings
+}
+
+// numberList converts numbers to the JSON array type of AnkiConnect responses
+func numberList(numbers []float64) []interface{} {
+	list := make([]interface{}, len(numbers))
+	for i, n := range numbers {
+		list[i] = n
+	}
+	return list
+}
+
+// parseSteps parses learning steps written as in Anki's deck options, e.g.
+// "30s", "10m", "1h" or "2d", into minutes. Plain numbers are minutes.
+func parseSteps(steps []string) ([]float64, error) {
+	units := map[string]float64{"s": 1.0 / 60, "m": 1, "h": 60, "d": 1440}
+	minutes := make([]float64, 0, len(steps))
+	for _, step := range steps {
+		number := strings.ToLower(strings.TrimSpace(step))
+		unit := 1.0
+		if n := len(number); n > 0 {
+			if u, ok := units[number[n-1:]]; ok {
+				unit = u
+				number = number[:n-1]
+			}
+		}
+		value, err := strconv.ParseFloat(number, 64)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("%q is not a step like 10m, 1h or 1d", step)
+		}
+		minutes = append(minutes, value*unit)
+	}
+	return minutes, nil
+}
+
+// formatSteps formats learning steps in minutes the way Anki's deck options
+// show them
+func formatSteps(minutes []float64) []string {
+	steps := make([]string, len(minutes))
+	for i, m := range minutes {
+		switch {
+		case m < 1:
+			steps[i] = strconv.FormatFloat(m*60, 'f', -1, 64) + "s"
+		case m >= 1440 && m == float64(int(m/1440))*1440:
+			steps[i] = strconv.Itoa(int(m/1440)) + "d"
+		case m >= 60 && m == float64(int(m/60))*60:
+			steps[i] = strconv.Itoa(int(m/60)) + "h"
+		default:
+			steps[i] = strconv.FormatFloat(m, 'f', -1, 64) + "m"
+		}
+	}
+	return steps
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestApplyDeckPreset(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	args := map[string]interface{}{"preset": "exam CRAM", "decks": []interface{}{"Spanish::Vocabulary", "Spanish::Grammar"}}
+	text, isErr := callTool(t, server.handleApplyDeckPreset, args)
+	if isErr {
+		t.Fatalf("apply_deck_preset failed: %s", text)
+	}
+	if !strings.Contains(text, "Spanish::Vocabulary (was: Default)") || !strings.Contains(text, "Spanish::Grammar (already using this preset)") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	if id := mock.deckConfigID("Spanish::Vocabulary"); id != 2 {
+		t.Errorf("Expected preset 2 on Spanish::Vocabulary, got %d", id)
+	}
+
+	args["preset"] = "Missing"
+	if text, isErr := callTool(t, server.handleApplyDeckPreset, args); !isErr || !strings.Contains(text, "Available presets: Default, Exam cram") {
+		t.Errorf("Expected unknown preset error, got: %s", text)
+	}
+}
+
+func TestDeckConfigTools(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+	mock.createDeck("French")
+	mock.deckConfigIDs["French"] = 2
+
+	text, isErr := callTool(t, server.handleGetDeckConfig, map[string]interface{}{"deck": "Spanish::Grammar"})
+	if isErr || !strings.Contains(text, "New cards/day: 50") || !strings.Contains(text, "Learning steps: 1m 10m") || !strings.Contains(text, "shared with: French") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+
+	args := map[string]interface{}{
+		"deck":              "Spanish::Grammar",
+		"new_cards_per_day": float64(10),
+		"learning_steps":    []interface{}{"30s", "15", "1h", "2d"},
+		"easy_interval":     float64(6),
+	}
+	text, isErr = callTool(t, server.handleUpdateDeckConfig, args)
+	if isErr || !strings.Contains(text, "New cards/day: 50 → 10") || !strings.Contains(text, "Learning steps: 1m 10m → 30s 15m 1h 2d") ||
+		!strings.Contains(text, "sharing this preset: French") || strings.Contains(text, "Maximum reviews") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	config := mock.deckConfigs[2]
+	if limits := configLimits(config); limits.NewCards != 10 || limits.Reviews != 500 {
+		t.Errorf("Unexpected limits %+v", limits)
+	}
+	if ints := numberSliceValue(objectValue(config, "new"), "ints"); len(ints) < 2 || ints[1] != 6 {
+		t.Errorf("Unexpected intervals %v", ints)
+	}
+
+	for _, bad := range []map[string]interface{}{
+		{"deck": "Spanish::Grammar"},
+		{"deck": "Spanish::Grammar", "learning_steps": []interface{}{"soon"}},
+		{"deck": "Spanish::Grammar", "graduating_interval": float64(10)},
+		{"deck": "Spanish::Grammar", "new_cards_per_day": float64(-1)},
+		{"deck": "Missing", "new_cards_per_day": float64(5)},
+	} {
+		if text, isErr := callTool(t, server.handleUpdateDeckConfig, bad); !isErr {
+			t.Errorf("Expected %v to fail, got: %s", bad, text)
+		}
+	}
+}
+
+func TestParseSteps(t *testing.T) {
+	steps, err := parseSteps([]string{"30s", "10", "1.5h", "3D"})
+	if err != nil || !slices.Equal(formatSteps(steps), []string{"30s", "10m", "90m", "3d"}) {
+		t.Errorf("parseSteps() = %v, %v", steps, err)
+	}
+	if _, err := parseSteps([]string{"-1m"}); err == nil {
+		t.Error("Expected negative steps to be rejected")
+	}
+}
+
+func TestCloneAndRemoveDeckPreset(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	args := map[string]interface{}{"name": "Light", "from": "exam cram", "decks": []interface{}{"Spanish::Vocabulary"}}
+	text, isErr := callTool(t, server.handleCloneDeckPreset, args)
+	if isErr || !strings.Contains(text, `Created preset "Light"`) || !strings.Contains(text, `copy of "Exam cram"`) || !strings.Contains(text, "Spanish::Vocabulary (was: Default)") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	lightID := mock.deckConfigID("Spanish::Vocabulary")
+	if lightID == 1 || lightID == 2 || configLimits(mock.deckConfigs[lightID]).NewCards != 50 {
+		t.Fatalf("Expected Spanish::Vocabulary on a copy of Exam cram, got preset %d", lightID)
+	}
+	if text, isErr := callTool(t, server.handleCloneDeckPreset, args); !isErr || !strings.Contains(text, "already exists") {
+		t.Errorf("Expected a duplicate name error, got: %s", text)
+	}
+
+	// An unused preset is only reachable by ID
+	text, isErr = callTool(t, server.handleCloneDeckPreset, map[string]interface{}{"name": "Spare"})
+	if isErr || !strings.Contains(text, "No deck uses it yet") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	var spareID int64
+	for id, config := range mock.deckConfigs {
+		if stringValue(config, "name") == "Spare" {
+			spareID = id
+		}
+	}
+	args = map[string]interface{}{"preset": strconv.FormatInt(spareID, 10), "decks": []interface{}{"Spanish::Grammar"}}
+	if text, isErr := callTool(t, server.handleApplyDeckPreset, args); isErr || !strings.Contains(text, `Applied preset "Spare"`) {
+		t.Errorf("Unexpected output: %s", text)
+	}
+
+	text, isErr = callTool(t, server.handleRemoveDeckPreset, map[string]interface{}{"preset": "Light"})
+	if isErr || !strings.Contains(text, "1 deck(s) now use the Default preset: Spanish::Vocabulary") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	if id := mock.deckConfigID("Spanish::Vocabulary"); id != 1 {
+		t.Errorf("Expected Spanish::Vocabulary back on Default, got %d", id)
+	}
+	if text, isErr := callTool(t, server.handleRemoveDeckPreset, map[string]interface{}{"preset": "default"}); !isErr {
+		t.Errorf("Expected the Default preset to be kept, got: %s", text)
+	}
+}
@@ -0,0 +1,584 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// defaultDeck is the deck Anki keeps cards in when no other deck is chosen; it
+// can't be deleted
+const defaultDeck = "Default"
+
+// registerDeckTools registers deck management tools with the MCP server
+func (a *AnkiMCPServer) registerDeckTools(s *server.MCPServer) {
+	// Tool: Get Deck Tree
+	deckTreeTool := mcp.NewTool("get_deck_tree",
+		mcp.WithDescription("List the decks as a tree following the :: hierarchy, with each deck's cards and the new, learning and review cards due today. "+
+			"Counts include subdecks, as in Anki's deck list; the cards in the deck itself are shown separately for decks with subdecks."),
+		mcp.WithString("deck",
+			mcp.Description("Optional: Only show this deck and its subdecks"),
+		),
+		withFormat(),
+	)
+	a.addTool(s, deckTreeTool, (*AnkiMCPServer).handleGetDeckTree)
+
+	// Tool: Delete Deck
+	deleteDeckTool := mcp.NewTool("delete_deck",
+		mcp.WithDescription("Delete a deck and its subdecks. By default their cards are kept and moved to the Default deck; with cards_too=true the cards are deleted as well. "+
+			"Use dry_run=true first to see how many cards are affected."),
+		mcp.WithString("deck",
+			mcp.Required(),
+			mcp.Description("Name of the deck to delete"),
+		),
+		mcp.WithBoolean("cards_too",
+			mcp.Description("Optional: Also delete the cards in the deck instead of moving them to Default"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Optional: Only report what would be deleted"),
+		),
+	)
+	a.addChangingTool(s, deleteDeckTool, (*AnkiMCPServer).handleDeleteDeck)
+
+	// Tool: Rename Deck
+	renameDeckTool := mcp.NewTool("rename_deck",
+		mcp.WithDescription("Rename a deck or move it to another place in the deck hierarchy, together with its subdecks, cards and options presets. "+
+			"Use :: in new_name for the hierarchy, e.g. rename \"Spanish Verbs\" to \"Spanish::Grammar::Verbs\", or pass parent to keep the deck's name and only move it."),
+		mcp.WithString("deck",
+			mcp.Required(),
+			mcp.Description("Current full name of the deck"),
+		),
+		mcp.WithString("new_name",
+			mcp.Description("New full name of the deck"),
+		),
+		mcp.WithString("parent",
+			mcp.Description("Instead of new_name: deck to move the deck under, keeping its own name"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Optional: Only report which decks and how many cards would be moved"),
+		),
+	)
+	a.addChangingTool(s, renameDeckTool, (*AnkiMCPServer).handleRenameDeck)
+
+	// Tool: Change Deck
+	changeDeckTool := mcp.NewTool("change_deck",
+		mcp.WithDescription("Move cards to another deck, selected by card ID or by an Anki search query. The target deck is created if it doesn't exist."),
+		mcp.WithString("deck",
+			mcp.Required(),
+			mcp.Description("Name of the deck to move the cards to"),
+		),
+		mcp.WithArray("card_ids",
+			mcp.Description("IDs of the cards to move"),
+			mcp.WithNumberItems(),
+		),
+		mcp.WithString("query",
+			mcp.Description("Anki search query selecting the cards to move, instead of card_ids"),
+		),
+	)
+	a.addChangingTool(s, changeDeckTool, (*AnkiMCPServer).handleChangeDeck)
+
+	// Tool: Export Deck
+	exportDeckTool := mcp.NewTool("export_deck",
+		mcp.WithDescription("Export a deck with its subdecks, note types and media as an .apkg file that can be shared and imported into Anki. "+
+			"Without path the file is returned base64-encoded in a JSON document; this needs Anki to run on the same machine as this server."),
+		mcp.WithString("deck",
+			mcp.Required(),
+			mcp.Description("Name of the deck to export"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Optional: Where to write the .apkg file, on the machine running Anki"),
+		),
+		mcp.WithBoolean("include_scheduling",
+			mcp.Description("Optional: Include review history and scheduling (default: false, so the recipient starts fresh)"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Optional: Only report what would be exported and where to"),
+		),
+	)
+	a.addChangingTool(s, exportDeckTool, (*AnkiMCPServer).handleExportDeck)
+}
+
+// deckNode is a deck in the deck tree. Its counts include the subdecks;
+// OwnCards counts the cards in the deck itself.
+type deckNode struct {
+	Name     string      `json:"name"`
+	ID       int64       `json:"id"`
+	Cards    int         `json:"cards"`
+	OwnCards int         `json:"own_cards"`
+	New      int         `json:"new"`
+	Learning int         `json:"learning"`
+	Review   int         `json:"review"`
+	Subdecks []*deckNode `json:"subdecks,omitempty"`
+}
+
+// buildDeckTree arranges decks by their :: hierarchy. Parents missing from
+// names are added without counts.
+func buildDeckTree(names []string, stats map[string]DeckStats) []*deckNode {
+	sorted := slices.Clone(names)
+	slices.Sort(sorted)
+	nodes := make(map[string]*deckNode)
+	var roots []*deckNode
+	var add func(name string) *deckNode
+	add = func(name string) *deckNode {
+		if node, ok := nodes[name]; ok {
+			return node
+		}
+		s := stats[name]
+		node := &deckNode{Name: name, ID: s.DeckID, OwnCards: s.TotalInDeck, New: s.NewCount, Learning: s.LearnCount, Review: s.ReviewCount}
+		nodes[name] = node
+		if i := strings.LastIndex(name, "::"); i >= 0 {
+			parent := add(name[:i])
+			parent.Subdecks = append(parent.Subdecks, node)
+		} else {
+			roots = append(roots, node)
+		}
+		return node
+	}
+	for _, name := range sorted {
+		add(name)
+	}
+
+	// AnkiConnect counts the cards of a deck without its subdecks, while
+	// the due counts already include them
+	var sum func(node *deckNode) int
+	sum = func(node *deckNode) int {
+		node.Cards = node.OwnCards
+		for _, sub := range node.Subdecks {
+			node.Cards += sum(sub)
+		}
+		return node.Cards
+	}
+	for _, root := range roots {
+		sum(root)
+	}
+	return roots
+}
+
+// handleGetDeckTree lists the decks as a tree with their card counts
+func (a *AnkiMCPServer) handleGetDeckTree(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	root, _ := request.GetArguments()["deck"].(string)
+
+	decks, err := a.ankiClient.GetDeckNames()
+	if err != nil {
+		return a.errorf("Failed to get decks: %v", err), nil
+	}
+	if root != "" {
+		if !slices.Contains(decks, root) {
+			return a.errorf("Deck not found: %s", root), nil
+		}
+		decks = slices.DeleteFunc(decks, func(d string) bool {
+			return d != root && !strings.HasPrefix(d, root+"::")
+		})
+	}
+	stats, err := a.ankiClient.GetDeckStats(decks)
+	if err != nil {
+		return a.errorf("Failed to get deck stats: %v", err), nil
+	}
+	tree := buildDeckTree(decks, stats)
+	if root != "" {
+		// Leave out the parents of the chosen deck
+		for len(tree) == 1 && tree[0].Name != root {
+			tree = tree[0].Subdecks
+		}
+	}
+
+	if wantsJSON(request) {
+		return a.jsonResult(map[string]interface{}{
+			"count": len(decks),
+			"decks": tree,
+		}), nil
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Decks (%d)", len(decks)))
+	var walk func(nodes []*deckNode, depth int)
+	walk = func(nodes []*deckNode, depth int) {
+		for _, node := range nodes {
+			label := node.Name
+			if i := strings.LastIndex(label, "::"); i >= 0 {
+				label = label[i+2:]
+			}
+			if len(node.Subdecks) > 0 {
+				out.NestedItem(depth, a.t("%s: %d card(s), %d in the deck itself; due today: %d new, %d learning, %d review", label, node.Cards, node.OwnCards, node.New, node.Learning, node.Review))
+			} else {
+				out.NestedItem(depth, a.t("%s: %d card(s); due today: %d new, %d learning, %d review", label, node.Cards, node.New, node.Learning, node.Review))
+			}
+			walk(node.Subdecks, depth+1)
+		}
+	}
+	walk(tree, 0)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleDeleteDeck deletes a deck, keeping or deleting its cards
+func (a *AnkiMCPServer) handleDeleteDeck(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	deck, ok := args["deck"].(string)
+	if !ok || strings.TrimSpace(deck) == "" {
+		return a.errorf("deck is required"), nil
+	}
+	if deck == defaultDeck {
+		return a.errorf("The Default deck can't be deleted"), nil
+	}
+	cardsToo, _ := args["cards_too"].(bool)
+	dryRun, _ := args["dry_run"].(bool)
+
+	decks, err := a.ankiClient.GetDeckNames()
+	if err != nil {
+		return a.errorf("Failed to get decks: %v", err), nil
+	}
+	if !slices.Contains(decks, deck) {
+		return a.errorf("Deck not found: %s", deck), nil
+	}
+	subdecks := 0
+	for _, d := range decks {
+		if strings.HasPrefix(d, deck+"::") {
+			subdecks++
+		}
+	}
+
+	cards, err := a.ankiClient.FindCards(deckQuery(deck))
+	if err != nil {
+		return a.errorf("Failed to find cards: %v", err), nil
+	}
+
+	var text string
+	switch {
+	case dryRun && cardsToo:
+		text = a.t("Would delete deck %s with %d subdeck(s) and %d card(s)", deck, subdecks, len(cards))
+	case dryRun:
+		text = a.t("Would delete deck %s with %d subdeck(s) and move %d card(s) to %s", deck, subdecks, len(cards), defaultDeck)
+	default:
+		// Deleting a deck always deletes its cards, so keep them by moving
+		// them out first
+		if !cardsToo && len(cards) > 0 {
+			if err := a.ankiClient.ChangeDeck(cards, defaultDeck); err != nil {
+				return a.errorf("Failed to move cards to %s: %v", defaultDeck, err), nil
+			}
+		}
+		if err := a.ankiClient.DeleteDeck(deck); err != nil {
+			return a.errorf("Failed to delete deck: %v", err), nil
+		}
+		if cardsToo {
+			text = a.t("Deleted deck %s with %d subdeck(s) and %d card(s)", deck, subdecks, len(cards))
+		} else {
+			text = a.t("Deleted deck %s with %d subdeck(s) and moved %d card(s) to %s", deck, subdecks, len(cards), defaultDeck)
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}, nil
+}
+
+// handleRenameDeck renames or moves a deck with its subdecks. AnkiConnect has
+// no rename action, so the decks are recreated under the new name, the cards
+// moved over and the old decks deleted once empty.
+func (a *AnkiMCPServer) handleRenameDeck(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	deck, ok := args["deck"].(string)
+	if !ok || strings.TrimSpace(deck) == "" {
+		return a.errorf("deck is required"), nil
+	}
+	newName, _ := args["new_name"].(string)
+	parent, _ := args["parent"].(string)
+	switch {
+	case newName != "" && parent != "":
+		return a.errorf("Pass either new_name or parent, not both"), nil
+	case parent != "":
+		parts := strings.Split(deck, "::")
+		newName = parent + "::" + parts[len(parts)-1]
+	case strings.TrimSpace(newName) == "":
+		return a.errorf("new_name or parent is required"), nil
+	}
+	if deck == defaultDeck {
+		return a.errorf("The Default deck can't be renamed"), nil
+	}
+	if newName == deck {
+		return a.errorf("The deck is already named %s", deck), nil
+	}
+	if strings.HasPrefix(newName, deck+"::") {
+		return a.errorf("A deck can't be moved into its own subdeck"), nil
+	}
+	dryRun, _ := args["dry_run"].(bool)
+
+	decks, err := a.ankiClient.GetDeckNames()
+	if err != nil {
+		return a.errorf("Failed to get decks: %v", err), nil
+	}
+	if !slices.Contains(decks, deck) {
+		return a.errorf("Deck not found: %s", deck), nil
+	}
+	if slices.Contains(decks, newName) {
+		return a.errorf("Deck already exists: %s", newName), nil
+	}
+
+	var subtree []string
+	for _, d := range decks {
+		if d == deck || strings.HasPrefix(d, deck+"::") {
+			subtree = append(subtree, d)
+		}
+	}
+	slices.Sort(subtree)
+	configs, err := a.ankiClient.GetDeckConfigs(subtree)
+	if err != nil {
+		return a.errorf("Failed to get deck options: %v", err), nil
+	}
+
+	// Only the cards directly in each deck; subdecks are moved in turn
+	cards := make(map[string][]int64, len(subtree))
+	total := 0
+	for _, d := range subtree {
+		ids, err := a.ankiClient.FindCards(deckQuery(d) + " -" + deckQuery(d+"::*"))
+		if err != nil {
+			return a.errorf("Failed to find cards: %v", err), nil
+		}
+		cards[d] = ids
+		total += len(ids)
+	}
+	if dryRun {
+		out := a.newOutput()
+		out.Heading(a.t("Would rename deck %s to %s", deck, newName))
+		out.Item(a.t("Subdecks to move: %d", len(subtree)-1))
+		out.Item(a.t("Cards to move: %d", total))
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: out.String(),
+				},
+			},
+		}, nil
+	}
+
+	moved := 0
+	var moves []deckMove
+	for _, d := range subtree {
+		target := newName + strings.TrimPrefix(d, deck)
+		if err := a.ankiClient.CreateDeck(target); err != nil {
+			return a.renameFailed(a.t("Failed to create deck: %v", err), moves, newName), nil
+		}
+		if config, ok := configs[d]; ok {
+			if err := a.ankiClient.SetDeckConfigID([]string{target}, int64(numberValue(config, "id"))); err != nil {
+				return a.renameFailed(a.t("Failed to apply preset: %v", err), moves, newName), nil
+			}
+		}
+		if len(cards[d]) > 0 {
+			if err := a.ankiClient.ChangeDeck(cards[d], target); err != nil {
+				return a.renameFailed(a.t("Failed to move cards to %s: %v", target, err), moves, newName), nil
+			}
+			moves = append(moves, deckMove{From: d, To: target, Cards: cards[d]})
+			moved += len(cards[d])
+		}
+	}
+
+	// Deleting a deck deletes its cards, so make sure none were left behind
+	left, err := a.ankiClient.FindCards(deckQuery(deck))
+	if err != nil {
+		return a.errorf("Failed to find cards: %v", err), nil
+	}
+	if len(left) > 0 {
+		return a.errorf("Moved %d card(s) to %s, but %d card(s) remain in %s, so it was not deleted", moved, newName, len(left), deck), nil
+	}
+	if err := a.ankiClient.DeleteDeck(deck); err != nil {
+		return a.errorf("Failed to delete deck: %v", err), nil
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Renamed deck %s to %s", deck, newName))
+	out.Item(a.t("Subdecks moved: %d", len(subtree)-1))
+	out.Item(a.t("Cards moved: %d", moved))
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// deckMove is a step of renaming a deck: cards moved from one deck to another
+type deckMove struct {
+	From, To string
+	Cards    []int64
+}
+
+// renameFailed reports a rename that failed partway, after undoing its moves
+// so far: the cards go back to their decks and the new decks are removed. If
+// that fails too, the error lists which cards are where, so they can be
+// moved back by hand.
+func (a *AnkiMCPServer) renameFailed(reason string, moves []deckMove, newName string) *mcp.CallToolResult {
+	var left []string
+	for i := len(moves) - 1; i >= 0; i-- {
+		m := moves[i]
+		if err := a.ankiClient.ChangeDeck(m.Cards, m.From); err != nil {
+			left = append(left, a.t("%d card(s) of %s are in %s (card IDs: %s)", len(m.Cards), m.From, m.To, joinIDs(m.Cards)))
+		}
+	}
+	if len(left) > 0 {
+		slices.Reverse(left)
+		return a.errorf("%s. Moving the cards back failed as well, so the rename is half done: %s", reason, strings.Join(left, "; "))
+	}
+
+	// Deleting a deck deletes its cards, so only remove the new decks while
+	// they are empty
+	if remaining, err := a.ankiClient.FindCards(deckQuery(newName)); err == nil && len(remaining) == 0 {
+		_ = a.ankiClient.DeleteDeck(newName)
+	}
+	return a.errorf("%s. Nothing was renamed: the cards moved so far were moved back", reason)
+}
+
+// handleChangeDeck moves cards to a deck
+func (a *AnkiMCPServer) handleChangeDeck(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	deck, ok := args["deck"].(string)
+	if !ok || strings.TrimSpace(deck) == "" {
+		return a.errorf("deck is required"), nil
+	}
+	cardIDs, errResult := a.selectCards(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if len(cardIDs) == 0 {
+		return a.errorf("No cards found to move"), nil
+	}
+
+	infos, err := a.ankiClient.GetCardsInfo(cardIDs)
+	if err != nil {
+		return a.errorf("Failed to get card info: %v", err), nil
+	}
+	var move []int64
+	missing := 0
+	for i, info := range infos {
+		switch {
+		case info.CardID == 0:
+			missing++
+		case info.DeckName != deck:
+			move = append(move, cardIDs[i])
+		}
+	}
+
+	if len(move) > 0 {
+		if err := a.ankiClient.ChangeDeck(move, deck); err != nil {
+			return a.errorf("Failed to move cards to %s: %v", deck, err), nil
+		}
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Moved %d card(s) to %s", len(move), deck))
+	if already := len(infos) - len(move) - missing; already > 0 {
+		out.Item(a.t("Already in the deck: %d", already))
+	}
+	if missing > 0 {
+		out.Item(a.t("Cards not found: %d", missing))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleExportDeck exports a deck as an .apkg file
+func (a *AnkiMCPServer) handleExportDeck(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	deck, ok := args["deck"].(string)
+	if !ok || strings.TrimSpace(deck) == "" {
+		return a.errorf("deck is required"), nil
+	}
+	path, _ := args["path"].(string)
+	if path != "" && !strings.EqualFold(filepath.Ext(path), ".apkg") {
+		return a.errorf("path must end in .apkg"), nil
+	}
+	includeSched, _ := args["include_scheduling"].(bool)
+	dryRun, _ := args["dry_run"].(bool)
+
+	decks, err := a.ankiClient.GetDeckNames()
+	if err != nil {
+		return a.errorf("Failed to get decks: %v", err), nil
+	}
+	if !slices.Contains(decks, deck) {
+		return a.errorf("Deck not found: %s", deck), nil
+	}
+	filename := strings.NewReplacer("::", " - ", "/", "-").Replace(deck) + ".apkg"
+	if dryRun {
+		text := a.t("Would export deck %s and return it as %s", deck, filename)
+		if path != "" {
+			text = a.t("Would export deck %s to %s", deck, path)
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: text,
+				},
+			},
+		}, nil
+	}
+
+	if path != "" {
+		if err := a.ankiClient.ExportPackage(deck, path, includeSched); err != nil {
+			return a.errorf("Failed to export deck: %v", err), nil
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: a.t("Exported deck %s to %s", deck, path),
+				},
+			},
+		}, nil
+	}
+
+	// Anki writes the file itself, so let it write to a temporary directory
+	// and read the file back from there
+	dir, err := os.MkdirTemp("", "anki-mcp-export-")
+	if err != nil {
+		return a.errorf("Failed to create temporary directory: %v", err), nil
+	}
+	defer os.RemoveAll(dir)
+	tmpPath := filepath.Join(dir, "export.apkg")
+	if err := a.ankiClient.ExportPackage(deck, tmpPath, includeSched); err != nil {
+		return a.errorf("Failed to export deck: %v", err), nil
+	}
+	data, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return a.errorf("Failed to read the exported file; Anki has to run on the same machine to return the file, otherwise pass path: %v", err), nil
+	}
+
+	return a.jsonResult(map[string]interface{}{
+		"deck":     deck,
+		"filename": filename,
+		"size":     len(data),
+		"data":     base64.StdEncoding.EncodeToString(data),
+	}), nil
+}
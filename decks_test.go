@@ -0,0 +1,213 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDeleteDeck(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	args := map[string]interface{}{"deck": "Spanish", "dry_run": true}
+	text, isErr := callTool(t, server.handleDeleteDeck, args)
+	if isErr || !strings.Contains(text, "2 subdeck(s) and move 14 card(s) to Default") || len(mock.cards) != 14 {
+		t.Fatalf("Unexpected dry run: %s", text)
+	}
+
+	args = map[string]interface{}{"deck": "Spanish::Grammar", "cards_too": true}
+	if text, isErr := callTool(t, server.handleDeleteDeck, args); isErr || !strings.Contains(text, "2 card(s)") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	if len(mock.cards) != 12 {
+		t.Errorf("Expected the grammar cards to be deleted, %d cards left", len(mock.cards))
+	}
+
+	args = map[string]interface{}{"deck": "Spanish"}
+	if text, isErr := callTool(t, server.handleDeleteDeck, args); isErr {
+		t.Fatalf("delete_deck failed: %s", text)
+	}
+	if _, ok := mock.decks["Spanish::Vocabulary"]; ok || len(mock.cards) != 12 {
+		t.Errorf("Expected the deck to be deleted and its 12 cards kept, got %d cards", len(mock.cards))
+	}
+	if ids, _ := server.ankiClient.FindCards("deck:Default"); len(ids) != 12 {
+		t.Errorf("Expected the cards in Default, got %d", len(ids))
+	}
+}
+
+func TestRenameDeck(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	args := map[string]interface{}{"deck": "Spanish", "new_name": "Languages::Spanish"}
+	text, isErr := callTool(t, server.handleRenameDeck, args)
+	if isErr || !strings.Contains(text, "Subdecks moved: 2") || !strings.Contains(text, "Cards moved: 14") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	if _, ok := mock.decks["Spanish"]; ok {
+		t.Error("The old deck still exists")
+	}
+	if ids, _ := server.ankiClient.FindCards(`"deck:Languages::Spanish::Grammar"`); len(ids) != 2 {
+		t.Errorf("Expected 2 cards in the moved grammar deck, got %d", len(ids))
+	}
+	if id := mock.deckConfigID("Languages::Spanish::Grammar"); id != 2 {
+		t.Errorf("Expected the moved deck to keep its preset, got %d", id)
+	}
+
+	args = map[string]interface{}{"deck": "Languages::Spanish::Vocabulary", "parent": "Languages"}
+	if text, isErr := callTool(t, server.handleRenameDeck, args); isErr || !strings.Contains(text, "to Languages::Vocabulary") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	args = map[string]interface{}{"deck": "Languages", "parent": "Languages::Spanish"}
+	if text, isErr := callTool(t, server.handleRenameDeck, args); !isErr {
+		t.Errorf("Expected moving a deck into its subdeck to fail, got: %s", text)
+	}
+}
+
+func TestRenameDeckFailure(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+	grammar, _ := server.ankiClient.FindCards(`"deck:Spanish::Grammar"`)
+	args := map[string]interface{}{"deck": "Spanish", "new_name": "Languages::Spanish"}
+
+	// Moving the vocabulary fails after the grammar cards were moved, so
+	// they are moved back
+	server.ankiClient.client.Transport = &failingAction{next: http.DefaultTransport, action: "changeDeck", skip: 1, times: 1}
+	text, isErr := callTool(t, server.handleRenameDeck, args)
+	if !isErr || !strings.Contains(text, "Nothing was renamed") {
+		t.Fatalf("Expected the rename to be undone, got: %s", text)
+	}
+	if ids, _ := server.ankiClient.FindCards(`"deck:Spanish::Grammar"`); len(ids) != 2 {
+		t.Errorf("Expected the grammar cards back in their deck, got %d", len(ids))
+	}
+	if _, ok := mock.decks["Languages::Spanish"]; ok {
+		t.Error("Expected the new deck to be removed")
+	}
+
+	// When moving back fails too, the error says which cards are where
+	server.ankiClient.client.Transport = &failingAction{next: http.DefaultTransport, action: "changeDeck", skip: 1}
+	text, isErr = callTool(t, server.handleRenameDeck, args)
+	if !isErr || !strings.Contains(text, "half done") || !strings.Contains(text, "2 card(s) of Spanish::Grammar are in Languages::Spanish::Grammar") ||
+		!strings.Contains(text, joinIDs(grammar)) {
+		t.Errorf("Expected the moved cards to be listed, got: %s", text)
+	}
+	if _, ok := mock.decks["Spanish"]; !ok {
+		t.Error("The old deck was deleted")
+	}
+}
+
+func TestChangeDeck(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	args := map[string]interface{}{"deck": "Spanish::Grammar", "query": "deck:Spanish"}
+	text, isErr := callTool(t, server.handleChangeDeck, args)
+	if isErr || !strings.Contains(text, "Moved 12 card(s) to Spanish::Grammar") || !strings.Contains(text, "Already in the deck: 2") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+
+	cards, _ := server.ankiClient.FindCards("deck:Spanish::Grammar")
+	args = map[string]interface{}{"deck": "Archive", "card_ids": []interface{}{float64(cards[0]), float64(1)}}
+	text, isErr = callTool(t, server.handleChangeDeck, args)
+	if isErr || !strings.Contains(text, "Moved 1 card(s) to Archive") || !strings.Contains(text, "Cards not found: 1") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	if mock.cards[cards[0]].Deck != "Archive" {
+		t.Errorf("Card was not moved: %s", mock.cards[cards[0]].Deck)
+	}
+
+	args = map[string]interface{}{"deck": "Archive"}
+	if text, isErr := callTool(t, server.handleChangeDeck, args); !isErr || !strings.Contains(text, "card_ids or query is required") {
+		t.Errorf("Expected a missing selection error, got: %s", text)
+	}
+}
+
+func TestExportDeck(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	path := filepath.Join(t.TempDir(), "spanish.apkg")
+	text, isErr := callTool(t, server.handleExportDeck, map[string]interface{}{"deck": "Spanish", "path": path})
+	if isErr || text != "Exported deck Spanish to "+path {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	if _, err := zip.OpenReader(path); err != nil {
+		t.Errorf("Expected a zip archive: %v", err)
+	}
+
+	text, isErr = callTool(t, server.handleExportDeck, map[string]interface{}{"deck": "Spanish::Grammar"})
+	var result struct {
+		Filename string `json:"filename"`
+		Data     []byte `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(text), &result); isErr || err != nil {
+		t.Fatalf("Unexpected output %s: %v", text, err)
+	}
+	if result.Filename != "Spanish - Grammar.apkg" || !bytes.Contains(result.Data, []byte("collection.anki21")) {
+		t.Errorf("Unexpected export: %s", text)
+	}
+
+	if text, isErr := callTool(t, server.handleExportDeck, map[string]interface{}{"deck": "Spanish", "path": "spanish.zip"}); !isErr {
+		t.Errorf("Expected an error for a path without .apkg, got: %s", text)
+	}
+}
+
+func TestGetDeckTree(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	text, isErr := callTool(t, server.handleGetDeckTree, map[string]interface{}{})
+	for _, want := range []string{
+		"## Decks (4)",
+		"- Spanish: 14 card(s), 0 in the deck itself; due today: 7 new, 0 learning, 4 review",
+		"\n  - Grammar: 2 card(s); due today: 2 new",
+		"\n  - Vocabulary: 12 card(s)",
+	} {
+		if isErr || !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the output, got %s", want, text)
+		}
+	}
+
+	text, _ = callTool(t, server.handleGetDeckTree, map[string]interface{}{"deck": "Spanish::Vocabulary", "format": "json"})
+	var tree struct {
+		Count int         `json:"count"`
+		Decks []*deckNode `json:"decks"`
+	}
+	if err := json.Unmarshal([]byte(text), &tree); err != nil {
+		t.Fatal(err)
+	}
+	if tree.Count != 1 || len(tree.Decks) != 1 || tree.Decks[0].Name != "Spanish::Vocabulary" || tree.Decks[0].OwnCards != 12 {
+		t.Errorf("Unexpected tree %s", text)
+	}
+
+	if text, isErr := callTool(t, server.handleGetDeckTree, map[string]interface{}{"deck": "French"}); !isErr || !strings.Contains(text, "Deck not found") {
+		t.Errorf("Expected an error for a missing deck, got %s", text)
+	}
+}
+
+func TestBuildDeckTreeAddsMissingParents(t *testing.T) {
+	stats := map[string]DeckStats{"A::B::C": {TotalInDeck: 3}}
+	tree := buildDeckTree([]string{"A::B::C"}, stats)
+	if len(tree) != 1 || tree[0].Name != "A" || tree[0].Subdecks[0].Subdecks[0].Cards != 3 || tree[0].OwnCards != 0 || tree[0].Cards != 3 {
+		t.Errorf("Unexpected tree %+v", tree)
+	}
+}
+
+func TestBuildDeckTreeSumsSubdecks(t *testing.T) {
+	stats := map[string]DeckStats{
+		"A":       {TotalInDeck: 1},
+		"A::B":    {TotalInDeck: 2},
+		"A::B::C": {TotalInDeck: 3},
+		"A::D":    {TotalInDeck: 4},
+	}
+	tree := buildDeckTree([]string{"A", "A::B", "A::B::C", "A::D"}, stats)
+	a, b := tree[0], tree[0].Subdecks[0]
+	if a.Cards != 10 || a.OwnCards != 1 || b.Cards != 5 || b.OwnCards != 2 {
+		t.Errorf("Unexpected counts: A %d/%d, A::B %d/%d", a.Cards, a.OwnCards, b.Cards, b.OwnCards)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// errorKind classifies AnkiConnect failures the user can do something about
+type errorKind string
+
+const (
+	// kindAnkiNotRunning means nothing listens at the AnkiConnect URL
+	kindAnkiNotRunning errorKind = "anki_not_running"
+	// kindAnkiBusy means Anki took longer than the timeout to answer
+	kindAnkiBusy errorKind = "anki_busy"
+	// kindAddonMissing means something answers at the URL, but not AnkiConnect
+	kindAddonMissing errorKind = "ankiconnect_missing"
+	// kindWrongVersion means the installed AnkiConnect doesn't know an action
+	kindWrongVersion errorKind = "ankiconnect_version"
+	// kindDeckNotFound means a deck named in the request doesn't exist
+	kindDeckNotFound errorKind = "deck_not_found"
+	// kindModelField means a note type or one of its fields doesn't exist
+	kindModelField errorKind = "model_field"
+	// kindAPIKey means AnkiConnect rejected the API key
+	kindAPIKey errorKind = "api_key"
+)
+
+// ankiError is an AnkiConnect failure of a known kind
+type ankiError struct {
+	kind errorKind
+	err  error
+}
+
+func (e *ankiError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ankiError) Unwrap() error {
+	return e.err
+}
+
+// classifyError returns the kind of an AnkiConnect failure, or "" if it is
+// none of the known ones. Errors from the entries of multi requests only
+// carry AnkiConnect's message, so that is classified as well.
+func classifyError(err error) errorKind {
+	var ankiErr *ankiError
+	switch {
+	case errors.As(err, &ankiErr):
+		return ankiErr.kind
+	case errors.Is(err, errAPIKey):
+		return kindAPIKey
+	}
+	return classifyMessage(err.Error())
+}
+
+// classifyMessage returns the kind of an error message of AnkiConnect
+func classifyMessage(message string) errorKind {
+	message = strings.ToLower(message)
+	switch {
+	case strings.Contains(message, "unsupported action"):
+		return kindWrongVersion
+	case strings.Contains(message, "deck was not found"):
+		return kindDeckNotFound
+	case strings.Contains(message, "model was not found"),
+		strings.Contains(message, "field was not found"),
+		strings.Contains(message, "does not exist") && strings.Contains(message, "field"),
+		strings.Contains(message, "cannot create note because it is empty"):
+		return kindModelField
+	}
+	return ""
+}
+
+// connectionError classifies an error of the HTTP request to AnkiConnect
+func connectionError(url string, err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return &ankiError{kind: kindAnkiBusy, err: fmt.Errorf("Anki did not answer in time at %s: %w", url, err)}
+	}
+	return &ankiError{kind: kindAnkiNotRunning, err: fmt.Errorf("Anki is not running or AnkiConnect is not reachable at %s: %w", url, err)}
+}
+
+// errorHint returns what the user can do about a kind of failure, or "" if
+// the error says it already
+func (a *AnkiMCPServer) errorHint(kind errorKind) string {
+	switch kind {
+	case kindAnkiNotRunning:
+		return a.t("Hint: Start Anki and keep it open. If it is running, check that the AnkiConnect add-on (code 2055492159) is installed and enabled, and that ANKI_CONNECT_URL matches its address.")
+	case kindAnkiBusy:
+		return a.t("Hint: Anki is running but busy, e.g. with an open dialog, a sync or a large collection. Close open dialogs in Anki, or raise ANKI_CONNECT_TIMEOUT.")
+	case kindAddonMissing:
+		return a.t("Hint: Another program answers at the AnkiConnect address. Install the AnkiConnect add-on (code 2055492159) via Tools > Add-ons > Get Add-ons, restart Anki and check ANKI_CONNECT_URL.")
+	case kindWrongVersion:
+		return a.t("Hint: The installed AnkiConnect doesn't support this request. Update it via Tools > Add-ons > Check for Updates and restart Anki.")
+	case kindDeckNotFound:
+		return a.t("Hint: The deck doesn't exist. Use get_deck_tree to see the deck names, or create_deck to create it.")
+	case kindModelField:
+		return a.t("Hint: The note type or one of its fields doesn't exist, or the first field is empty. Check the names in Anki under Tools > Manage Note Types > Fields; they are case-sensitive.")
+	}
+	return ""
+}
+
+// diagnose adds the kind of the first classified error among args to an
+// error result, with a hint on what to do about it
+func (a *AnkiMCPServer) diagnose(result *mcp.CallToolResult, args []interface{}) {
+	for _, arg := range args {
+		err, ok := arg.(error)
+		if !ok {
+			continue
+		}
+		kind := classifyError(err)
+		if kind == "" {
+			continue
+		}
+		result.Meta = map[string]any{"error_kind": string(kind)}
+		if hint := a.errorHint(kind); hint != "" {
+			text := result.Content[0].(mcp.TextContent)
+			text.Text += "\n\n" + hint
+			result.Content[0] = text
+		}
+		return
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want errorKind
+	}{
+		{errors.New("AnkiConnect error: unsupported action"), kindWrongVersion},
+		{errors.New("AnkiConnect error: deck was not found: Nope"), kindDeckNotFound},
+		{errors.New("AnkiConnect error: model was not found: Nope"), kindModelField},
+		{errors.New(`AnkiConnect error: field "Nope" does not exist`), kindModelField},
+		{errors.New("AnkiConnect error: cannot create note because it is empty"), kindModelField},
+		{fmt.Errorf("failed to check: %w", errAPIKey), kindAPIKey},
+		{fmt.Errorf("wrapped: %w", &ankiError{kind: kindAnkiBusy, err: errors.New("timeout")}), kindAnkiBusy},
+		{errors.New("AnkiConnect error: cannot create note because it is a duplicate"), ""},
+	}
+	for _, tt := range tests {
+		if got := classifyError(tt.err); got != tt.want {
+			t.Errorf("classifyError(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestDiagnosedErrors(t *testing.T) {
+	down := httptest.NewServer(nil)
+	down.Close()
+	other := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(other.Close)
+
+	for _, tt := range []struct {
+		url, hint string
+		want      errorKind
+	}{
+		{down.URL, "Start Anki", kindAnkiNotRunning},
+		{other.URL, "Install the AnkiConnect add-on", kindAddonMissing},
+	} {
+		server := NewAnkiMCPServerWithConfig(Config{
+			AnkiConnectURL: tt.url,
+			Retry:          RetryPolicy{Attempts: 1},
+			Language:       defaultLanguage,
+			OutputStyle:    outputMarkdown,
+			StateDir:       t.TempDir(),
+		})
+		result, err := server.handleGetDeckTree(context.Background(), mcp.CallToolRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		text := result.Content[0].(mcp.TextContent).Text
+		if !result.IsError || !strings.Contains(text, tt.url) || !strings.Contains(text, tt.hint) {
+			t.Errorf("Unexpected output for %s: %s", tt.want, text)
+		}
+		if result.Meta["error_kind"] != string(tt.want) {
+			t.Errorf("Expected error_kind %s, got %v", tt.want, result.Meta)
+		}
+	}
+
+	server, _ := newMockServer(t)
+	text, isErr := callTool(t, server.handleCreateCard, map[string]interface{}{
+		"deck":       "Default",
+		"front":      "uno",
+		"back":       "one",
+		"model_name": "Nope",
+	})
+	if !isErr || !strings.Contains(text, "Hint: The note type or one of its fields doesn't exist") {
+		t.Errorf("Expected a hint about the note type, got %s", text)
+	}
+}
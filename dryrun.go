@@ -0,0 +1,426 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// changingActions are the AnkiConnect actions that change the collection, the
+// media folder or files on the Anki machine. A dry run records them instead
+// of sending them.
+var changingActions = map[string]bool{
+	"addNote":                true,
+	"addTags":                true,
+	"changeDeck":             true,
+	"clearUnusedTags":        true,
+	"cloneDeckConfigId":      true,
+	"createDeck":             true,
+	"createModel":            true,
+	"deleteDecks":            true,
+	"deleteMediaFile":        true,
+	"deleteNotes":            true,
+	"exportPackage":          true,
+	"findAndReplaceInModels": true,
+	"forgetCards":            true,
+	"guiAnswerCard":          true,
+	"guiCheckDatabase":       true,
+	"guiUndo":                true,
+	"insertReviews":          true,
+	"modelFieldRename":       true,
+	"relearnCards":           true,
+	"removeDeckConfigId":     true,
+	"removeTags":             true,
+	"replaceTags":            true,
+	"saveDeckConfig":         true,
+	"setDeckConfigId":        true,
+	"setDueDate":             true,
+	"storeMediaFile":         true,
+	"suspend":                true,
+	"sync":                   true,
+	"unsuspend":              true,
+	"updateModelStyling":     true,
+	"updateModelTemplates":   true,
+	"updateNoteFields":       true,
+	"updateNoteModel":        true,
+}
+
+// dryRunChange is a change a dry run would have made
+type dryRunChange struct {
+	Action string      `json:"action"`
+	Params interface{} `json:"params,omitempty"`
+}
+
+// dryRunLog collects the changes of a dry run in the order they were made
+type dryRunLog struct {
+	mu      sync.Mutex
+	changes []dryRunChange
+}
+
+// record adds a change to the log and returns a stand-in for AnkiConnect's
+// result, so the caller carries on as if the change had been made. New
+// objects get negative IDs, which Anki never uses.
+func (l *dryRunLog) record(action string, params interface{}) json.RawMessage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.changes = append(l.changes, dryRunChange{Action: action, Params: params})
+
+	switch action {
+	case "addNote", "createDeck", "cloneDeckConfigId":
+		return json.RawMessage(fmt.Sprintf("%d", -len(l.changes)))
+	case "storeMediaFile":
+		filename, _ := json.Marshal(params.(map[string]interface{})["filename"])
+		return filename
+	case "exportPackage", "guiAnswerCard", "guiUndo", "removeDeckConfigId", "saveDeckConfig", "setDeckConfigId", "setDueDate", "suspend", "unsuspend":
+		return json.RawMessage("true")
+	}
+	return json.RawMessage("null")
+}
+
+// Changes returns the recorded changes
+func (l *dryRunLog) Changes() []dryRunChange {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]dryRunChange(nil), l.changes...)
+}
+
+// withDryRun returns a copy of the client that records changes in log
+// instead of sending them. Requests that only read are still sent.
+func (ac *AnkiConnect) withDryRun(log *dryRunLog) *AnkiConnect {
+	client := *ac
+	client.dryRun = log
+	return &client
+}
+
+// dryRunMulti runs the actions of a multi request one by one when any of them
+// changes something, so those are recorded while the others are sent to
+// AnkiConnect
+func (ac *AnkiConnect) dryRunMulti(actions []ankiRequest) (json.RawMessage, error) {
+	if !slices.ContainsFunc(actions, func(action ankiRequest) bool { return changingActions[action.Action] }) {
+		return ac.send("multi", map[string]interface{}{"actions": actions})
+	}
+	responses := make([]ankiResponse, len(actions))
+	for i, action := range actions {
+		result, err := ac.call(action.Action, action.Params)
+		if err != nil {
+			responses[i].Error = strings.TrimPrefix(err.Error(), "AnkiConnect error: ")
+			continue
+		}
+		responses[i].Result = result
+	}
+	return json.Marshal(responses)
+}
+
+// dryRunStoreMedia records storing a media file, reading the data only to
+// report its size and checksum
+func (ac *AnkiConnect) dryRunStoreMedia(filename string, r io.Reader) (StoredMedia, error) {
+	hash := sha256.New()
+	size, err := io.Copy(hash, r)
+	if err != nil {
+		return StoredMedia{}, fmt.Errorf("failed to read media: %w", err)
+	}
+	ac.dryRun.record("storeMediaFile", map[string]interface{}{"filename": filename, "size": size})
+	return StoredMedia{Filename: filename, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+// withDryRun adds the dry_run parameter accepted by tools that change the
+// collection
+func withDryRun() mcp.ToolOption {
+	return mcp.WithBoolean("dry_run",
+		mcp.Description("Optional: Only report what this call would change (decks, fields, tags, media), without changing anything"),
+	)
+}
+
+// addChangingTool registers a tool that changes the collection. The tool gets
+// the dry_run parameter, which is implied for every call when the server runs
+// in dry-run mode. Tools that already declare dry_run handle it themselves.
+func (a *AnkiMCPServer) addChangingTool(s *server.MCPServer, tool mcp.Tool, handler toolHandler) {
+	_, handlesDryRun := tool.InputSchema.Properties["dry_run"]
+	if !handlesDryRun {
+		withDryRun()(&tool)
+	}
+
+	a.addTool(s, tool, func(a *AnkiMCPServer, ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		if dryRun, _ := args["dry_run"].(bool); !dryRun && !a.dryRun {
+			return handler(a, ctx, request)
+		}
+		if handlesDryRun {
+			forced := make(map[string]interface{}, len(args)+1)
+			for name, value := range args {
+				forced[name] = value
+			}
+			forced["dry_run"] = true
+			request.Params.Arguments = forced
+			return handler(a, ctx, request)
+		}
+		return a.dryRunTool(ctx, request, handler)
+	})
+}
+
+// dryRunTool calls a handler with a client that records changes instead of
+// making them, and reports the recorded changes in place of the handler's
+// output, which would describe results that don't exist. Calls that would
+// change nothing, or fail before changing anything, return the handler's
+// result unchanged. Calls that fail after recording changes return the error
+// too, since the recorded changes would only be part of the call.
+func (a *AnkiMCPServer) dryRunTool(ctx context.Context, request mcp.CallToolRequest, handler toolHandler) (*mcp.CallToolResult, error) {
+	log := &dryRunLog{}
+	result, err := handler(a.withDryRun(log), ctx, request)
+	changes := log.Changes()
+	if err != nil || len(changes) == 0 {
+		return result, err
+	}
+	if result != nil && result.IsError {
+		result.Content = append(result.Content, mcp.TextContent{
+			Type: "text",
+			Text: a.t("Dry run: nothing was changed. The call stopped with this error after planning %d change(s), so there is no complete list of its changes.", len(changes)),
+		})
+		return result, nil
+	}
+
+	descriptions := a.describeChanges(changes)
+	if wantsJSON(request) {
+		type change struct {
+			Action      string      `json:"action"`
+			Description string      `json:"description"`
+			Params      interface{} `json:"params,omitempty"`
+		}
+		report := struct {
+			DryRun  bool     `json:"dry_run"`
+			Changes []change `json:"changes"`
+		}{DryRun: true}
+		for i, c := range changes {
+			report.Changes = append(report.Changes, change{Action: c.Action, Description: descriptions[i], Params: c.Params})
+		}
+		return a.jsonResult(report), nil
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Dry run: this call would make %d change(s)", len(changes)))
+	for _, description := range descriptions {
+		out.Item(description)
+	}
+	out.Line("")
+	if a.dryRun {
+		out.Line(a.t("Nothing was changed: the server runs in dry-run mode (ANKI_MCP_DRY_RUN)."))
+	} else {
+		out.Line(a.t("Nothing was changed. Call the tool again without dry_run to make these changes."))
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// withDryRun returns a copy of the server whose AnkiConnect client records
+// changes in log, and which keeps no state of its own
+func (a *AnkiMCPServer) withDryRun(log *dryRunLog) *AnkiMCPServer {
+	dry := a.withClient(a.ankiClient.withDryRun(log))
+	dry.dryRun = true
+	return dry
+}
+
+// dryRunParams holds the parameters of every changing action, so they can be
+// described without a type per action
+type dryRunParams struct {
+	Note struct {
+		ID        int64             `json:"id"`
+		DeckName  string            `json:"deckName"`
+		ModelName string            `json:"modelName"`
+		Fields    map[string]string `json:"fields"`
+		Tags      []string          `json:"tags"`
+	} `json:"note"`
+	Notes          []int64  `json:"notes"`
+	Cards          []int64  `json:"cards"`
+	Tags           string   `json:"tags"`
+	TagToReplace   string   `json:"tag_to_replace"`
+	ReplaceWithTag string   `json:"replace_with_tag"`
+	Deck           string   `json:"deck"`
+	Decks          []string `json:"decks"`
+	Days           string   `json:"days"`
+	Path           string   `json:"path"`
+	Filename       string   `json:"filename"`
+	Size           int64    `json:"size"`
+	ModelName      string   `json:"modelName"`
+	InOrderFields  []string `json:"inOrderFields"`
+	OldFieldName   string   `json:"oldFieldName"`
+	FindText       string   `json:"findText"`
+	ReplaceText    string   `json:"replaceText"`
+	NewFieldName   string   `json:"newFieldName"`
+	Model          struct {
+		Name      string                       `json:"name"`
+		Templates map[string]map[string]string `json:"templates"`
+	} `json:"model"`
+	Config struct {
+		Name string `json:"name"`
+	} `json:"config"`
+	ConfigID  int64     `json:"configId"`
+	Name      string    `json:"name"`
+	CloneFrom int64     `json:"cloneFrom"`
+	Reviews   [][]int64 `json:"reviews"`
+	Ease      int       `json:"ease"`
+}
+
+// describeChanges describes recorded changes in the output language. Fields
+// of new notes are listed in the order of their note type.
+func (a *AnkiMCPServer) describeChanges(changes []dryRunChange) []string {
+	fieldOrder := make(map[string][]string)
+	descriptions := make([]string, len(changes))
+	for i, c := range changes {
+		var p dryRunParams
+		if data, err := json.Marshal(c.Params); err == nil {
+			_ = json.Unmarshal(data, &p)
+		}
+
+		switch c.Action {
+		case "addNote":
+			model := p.Note.ModelName
+			if _, ok := fieldOrder[model]; !ok {
+				fieldOrder[model], _ = a.ankiClient.GetModelFieldNames(model)
+			}
+			parts := describeFields(p.Note.Fields, fieldOrder[model])
+			if len(p.Note.Tags) > 0 {
+				parts = append(parts, a.t("Tags: %s", strings.Join(p.Note.Tags, ", ")))
+			}
+			descriptions[i] = a.t("Add a %s note to %s: %s", model, p.Note.DeckName, strings.Join(parts, "; "))
+		case "updateNoteFields":
+			descriptions[i] = a.t("Update note %d: %s", p.Note.ID, strings.Join(describeFields(p.Note.Fields, nil), "; "))
+		case "updateNoteModel":
+			model := p.Note.ModelName
+			if _, ok := fieldOrder[model]; !ok {
+				fieldOrder[model], _ = a.ankiClient.GetModelFieldNames(model)
+			}
+			descriptions[i] = a.t("Change note %d to note type %s: %s", p.Note.ID, model, strings.Join(describeFields(p.Note.Fields, fieldOrder[model]), "; "))
+		case "deleteNotes":
+			descriptions[i] = a.t("Delete %d note(s) with their cards: %s", len(p.Notes), joinIDs(p.Notes))
+		case "addTags":
+			descriptions[i] = a.t("Add tags %s to %d note(s)", p.Tags, len(p.Notes))
+		case "removeTags":
+			descriptions[i] = a.t("Remove tags %s from %d note(s)", p.Tags, len(p.Notes))
+		case "replaceTags":
+			descriptions[i] = a.t("Replace tag %s with %s on %d note(s)", p.TagToReplace, p.ReplaceWithTag, len(p.Notes))
+		case "clearUnusedTags":
+			descriptions[i] = a.t("Remove unused tags from the tag list")
+		case "createDeck":
+			descriptions[i] = a.t("Create deck %s", p.Deck)
+		case "deleteDecks":
+			descriptions[i] = a.t("Delete deck %s with its cards", strings.Join(p.Decks, ", "))
+		case "changeDeck":
+			descriptions[i] = a.t("Move %d card(s) to deck %s", len(p.Cards), p.Deck)
+		case "suspend":
+			descriptions[i] = a.t("Suspend %d card(s)", len(p.Cards))
+		case "unsuspend":
+			descriptions[i] = a.t("Unsuspend %d card(s)", len(p.Cards))
+		case "forgetCards":
+			descriptions[i] = a.t("Make %d card(s) new again", len(p.Cards))
+		case "relearnCards":
+			descriptions[i] = a.t("Put %d card(s) back into relearning", len(p.Cards))
+		case "setDueDate":
+			descriptions[i] = a.t("Make %d card(s) due in %s day(s)", len(p.Cards), p.Days)
+		case "storeMediaFile":
+			descriptions[i] = a.t("Store media file %s (%d bytes)", p.Filename, p.Size)
+		case "deleteMediaFile":
+			descriptions[i] = a.t("Delete media file %s", p.Filename)
+		case "createModel":
+			descriptions[i] = a.t("Create note type %s with fields %s", p.ModelName, strings.Join(p.InOrderFields, ", "))
+		case "updateModelTemplates":
+			names := make([]string, 0, len(p.Model.Templates))
+			for name := range p.Model.Templates {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			descriptions[i] = a.t("Update card templates %s of note type %s", strings.Join(names, ", "), p.Model.Name)
+		case "updateModelStyling":
+			descriptions[i] = a.t("Replace the styling of note type %s", p.Model.Name)
+		case "findAndReplaceInModels":
+			if p.ModelName == "" {
+				descriptions[i] = a.t("Replace %q with %q in the templates and styling of every note type", p.FindText, p.ReplaceText)
+			} else {
+				descriptions[i] = a.t("Replace %q with %q in the templates and styling of note type %s", p.FindText, p.ReplaceText, p.ModelName)
+			}
+		case "modelFieldRename":
+			descriptions[i] = a.t("Rename field %s of note type %s to %s", p.OldFieldName, p.ModelName, p.NewFieldName)
+		case "saveDeckConfig":
+			descriptions[i] = a.t("Save options preset %s", p.Config.Name)
+		case "setDeckConfigId":
+			if p.ConfigID < 0 {
+				// A preset created earlier in the same dry run
+				descriptions[i] = a.t("Switch deck %s to the new options preset", strings.Join(p.Decks, ", "))
+			} else {
+				descriptions[i] = a.t("Switch deck %s to options preset %d", strings.Join(p.Decks, ", "), p.ConfigID)
+			}
+		case "cloneDeckConfigId":
+			descriptions[i] = a.t("Create options preset %s as a copy of preset %d", p.Name, p.CloneFrom)
+		case "removeDeckConfigId":
+			descriptions[i] = a.t("Remove options preset %d", p.ConfigID)
+		case "insertReviews":
+			descriptions[i] = a.t("Add %d review(s) to the review history", len(p.Reviews))
+		case "exportPackage":
+			descriptions[i] = a.t("Export deck %s to %s", p.Deck, p.Path)
+		case "guiCheckDatabase":
+			descriptions[i] = a.t("Run Check Database")
+		case "guiUndo":
+			descriptions[i] = a.t("Undo the last operation in Anki")
+		case "sync":
+			descriptions[i] = a.t("Sync the collection with AnkiWeb")
+		case "guiAnswerCard":
+			descriptions[i] = a.t("Answer the current card with ease %d", p.Ease)
+		default:
+			params, _ := json.Marshal(c.Params)
+			descriptions[i] = fmt.Sprintf("%s %s", c.Action, params)
+		}
+	}
+	return descriptions
+}
+
+// describeFields lists field values as "Name: value", in the given order
+// followed by any other fields sorted by name
+func describeFields(fields map[string]string, order []string) []string {
+	var names []string
+	seen := make(map[string]bool, len(fields))
+	for _, name := range order {
+		if _, ok := fields[name]; ok {
+			names = append(names, name)
+			seen[name] = true
+		}
+	}
+	var rest []string
+	for name := range fields {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	names = append(names, rest...)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s: %s", name, fields[name])
+	}
+	return parts
+}
+
+// joinIDs lists IDs separated by commas
+func joinIDs(ids []int64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(parts, ", ")
+}
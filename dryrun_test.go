@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDryRunCreateCard(t *testing.T) {
+	c, mock := newIntegrationClient(t)
+
+	text, isErr := callMCPTool(t, c, "create_card", map[string]interface{}{
+		"deck":    "Spanish::Vocabulary",
+		"front":   "la mesa",
+		"back":    "the table",
+		"tags":    []interface{}{"furniture"},
+		"dry_run": true,
+	})
+	if isErr {
+		t.Fatalf("create_card: %s", text)
+	}
+	for _, want := range []string{
+		"Dry run: this call would make 1 change(s)",
+		"Add a Basic note to Spanish::Vocabulary: Front: la mesa; Back: the table; Tags: furniture",
+		"without dry_run",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in %s", want, text)
+		}
+	}
+	if cards, _ := mock.search(`"la mesa"`); len(cards) != 0 {
+		t.Errorf("Expected no card to be added, got %d", len(cards))
+	}
+}
+
+func TestDryRunMedia(t *testing.T) {
+	c, _ := newIntegrationClient(t)
+
+	text, isErr := callMCPTool(t, c, "add_media", map[string]interface{}{
+		"data":     base64.StdEncoding.EncodeToString([]byte("fake audio")),
+		"filename": "perro.mp3",
+		"dry_run":  true,
+		"format":   "json",
+	})
+	if isErr {
+		t.Fatalf("add_media: %s", text)
+	}
+	var report struct {
+		DryRun  bool `json:"dry_run"`
+		Changes []struct {
+			Action      string `json:"action"`
+			Description string `json:"description"`
+		} `json:"changes"`
+	}
+	if err := json.Unmarshal([]byte(text), &report); err != nil {
+		t.Fatalf("Expected a JSON report, got %s", text)
+	}
+	if !report.DryRun || len(report.Changes) != 1 || report.Changes[0].Action != "storeMediaFile" ||
+		report.Changes[0].Description != "Store media file perro.mp3 (10 bytes)" {
+		t.Errorf("Unexpected report: %s", text)
+	}
+}
+
+func TestDryRunMode(t *testing.T) {
+	c, mock := newIntegrationClientWith(t, func(config *Config) { config.DryRun = true })
+	cards, _ := mock.search("deck:Spanish::Vocabulary")
+	card := cards[0]
+	queue, ease := card.Queue, card.Factor
+
+	text, _ := callMCPTool(t, c, "suspend_cards", map[string]interface{}{"card_ids": []interface{}{float64(card.ID)}})
+	if !strings.Contains(text, "Suspend 1 card(s)") || !strings.Contains(text, "ANKI_MCP_DRY_RUN") || card.Queue != queue {
+		t.Errorf("suspend_cards: %s", text)
+	}
+
+	// Tools with their own dry run handle it themselves
+	text, _ = callMCPTool(t, c, "forget_cards", map[string]interface{}{"card_ids": []interface{}{float64(card.ID)}})
+	if !strings.Contains(text, "Would reset") || card.Factor != ease {
+		t.Errorf("forget_cards: %s", text)
+	}
+
+	text, _ = callMCPTool(t, c, "delete_notes", map[string]interface{}{"query": "deck:Spanish", "confirm": true})
+	if !strings.Contains(text, "Delete 7 note(s) with their cards") || len(mock.cards) != 14 {
+		t.Errorf("delete_notes: %s", text)
+	}
+
+	// Read tools are not affected
+	text, isErr := callMCPTool(t, c, "get_deck_tree", map[string]interface{}{})
+	if isErr || strings.Contains(text, "Dry run") {
+		t.Errorf("get_deck_tree: %s", text)
+	}
+}
+
+func TestDryRunDecks(t *testing.T) {
+	c, mock := newIntegrationClient(t)
+	decks := len(mock.decks)
+
+	text, isErr := callMCPTool(t, c, "rename_deck", map[string]interface{}{"deck": "Spanish", "new_name": "Español", "dry_run": true})
+	if isErr || !strings.Contains(text, "Would rename deck Spanish to Español") || !strings.Contains(text, "Subdecks to move: 2") || !strings.Contains(text, "Cards to move: 14") {
+		t.Errorf("rename_deck: %s", text)
+	}
+	text, isErr = callMCPTool(t, c, "export_deck", map[string]interface{}{"deck": "Spanish::Grammar", "dry_run": true})
+	if isErr || text != "Would export deck Spanish::Grammar and return it as Spanish - Grammar.apkg" {
+		t.Errorf("export_deck: %s", text)
+	}
+	if len(mock.decks) != decks || mock.decks["Español"] != 0 {
+		t.Errorf("Expected no deck to change, have %v", mock.decks)
+	}
+}
+
+func TestDryRunFailure(t *testing.T) {
+	server, mock := newMockServer(t)
+	handler := func(a *AnkiMCPServer, ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := a.ankiClient.CreateDeck("Half"); err != nil {
+			return nil, err
+		}
+		return a.errorf("Second step failed"), nil
+	}
+
+	result, err := server.dryRunTool(context.Background(), mcp.CallToolRequest{}, handler)
+	if err != nil || !result.IsError || len(result.Content) != 2 {
+		t.Fatalf("Expected the error with a note, got %+v (%v)", result, err)
+	}
+	if text := result.Content[1].(mcp.TextContent).Text; !strings.Contains(text, "after planning 1 change(s)") {
+		t.Errorf("Unexpected note: %s", text)
+	}
+	if _, ok := mock.decks["Half"]; ok {
+		t.Error("Expected no deck to be created")
+	}
+}
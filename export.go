@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerExportTools registers note export tools with the MCP server
+func (a *AnkiMCPServer) registerExportTools(s *server.MCPServer) {
+	// Tool: Export Markdown
+	exportMarkdownTool := mcp.NewTool("export_markdown",
+		mcp.WithDescription("Render notes as a Markdown document, e.g. to publish a deck to a notes app or a git repository. "+
+			"Each note becomes a section: the front field as a heading, the back as the body and the tags as a footer. "+
+			"Field formatting (bold, italic, code, lists, links, images) is converted to Markdown; images keep their media file names."),
+		mcp.WithArray("note_ids",
+			mcp.Description("Optional: IDs of the notes to export (either note_ids or query is required)"),
+			mcp.WithNumberItems(),
+		),
+		mcp.WithString("query",
+			mcp.Description("Optional: Anki search query selecting the notes, e.g. \"deck:Spanish\""),
+		),
+		mcp.WithString("front_field",
+			mcp.Description("Optional: Field used as the heading (default: the first field)"),
+		),
+		mcp.WithString("back_field",
+			mcp.Description("Optional: Field used as the body (default: all other non-empty fields, labeled by name when there are several)"),
+		),
+		mcp.WithString("title",
+			mcp.Description("Optional: Title of the document"),
+		),
+		mcp.WithString("output_path",
+			mcp.Description("Optional: Write the Markdown to this file instead of returning it"),
+		),
+	)
+	a.addTool(s, exportMarkdownTool, (*AnkiMCPServer).handleExportMarkdown)
+}
+
+// markdownNote renders a note as a Markdown section: the front field as a
+// heading, the back as the body and the tags as a footer. Without backField,
+// the body is made of the other non-empty fields, labeled by name when there
+// are several of them.
+func markdownNote(note NoteInfo, frontField, backField string) string {
+	names := noteFieldNames(note)
+	if frontField == "" && len(names) > 0 {
+		frontField = names[0]
+	}
+	front, _ := noteField(note, frontField)
+
+	var body []string
+	if backField != "" {
+		if back, _ := noteField(note, backField); strings.TrimSpace(back) != "" {
+			body = append(body, htmlToMarkdown(back))
+		}
+	} else {
+		var fields []string
+		for _, name := range names {
+			if value, _ := noteField(note, name); name != frontField && strings.TrimSpace(value) != "" {
+				fields = append(fields, name)
+			}
+		}
+		for _, name := range fields {
+			value, _ := noteField(note, name)
+			if len(fields) > 1 {
+				body = append(body, "**"+name+"**")
+			}
+			body = append(body, htmlToMarkdown(value))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("## " + strings.Join(strings.Fields(htmlToMarkdown(front)), " ") + "\n")
+	for _, part := range body {
+		b.WriteString("\n" + part + "\n")
+	}
+	if len(note.Tags) > 0 {
+		b.WriteString("\nTags: `" + strings.Join(note.Tags, "` `") + "`\n")
+	}
+	return b.String()
+}
+
+// handleExportMarkdown renders notes as a Markdown document
+func (a *AnkiMCPServer) handleExportMarkdown(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	noteIDs, errResult := a.selectNotes(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if len(noteIDs) == 0 {
+		return a.errorf("No notes found"), nil
+	}
+	frontField, _ := args["front_field"].(string)
+	backField, _ := args["back_field"].(string)
+
+	notes, err := a.ankiClient.GetNotesInfo(noteIDs)
+	if err != nil {
+		return a.errorf("Failed to get note info: %v", err), nil
+	}
+
+	var sections []string
+	exported := 0
+	if title, _ := args["title"].(string); strings.TrimSpace(title) != "" {
+		sections = append(sections, "# "+strings.TrimSpace(title)+"\n")
+	}
+	for _, note := range notes {
+		if note.NoteID == 0 {
+			continue
+		}
+		for _, field := range []string{frontField, backField} {
+			if _, ok := noteField(note, field); field != "" && !ok {
+				return a.errorf("Note %d has no field %s. Fields: %s", note.NoteID, field, strings.Join(noteFieldNames(note), ", ")), nil
+			}
+		}
+		sections = append(sections, markdownNote(note, frontField, backField))
+		exported++
+	}
+	if exported == 0 {
+		return a.errorf("No notes found"), nil
+	}
+
+	text := strings.Join(sections, "\n")
+	if outputPath, ok := args["output_path"].(string); ok && outputPath != "" {
+		if err := os.WriteFile(outputPath, []byte(text), 0644); err != nil {
+			return a.errorf("Failed to write %s: %v", outputPath, err), nil
+		}
+		text = a.t("Exported %d note(s) to %s", exported, outputPath)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportMarkdown(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	noteIDs, _ := server.ankiClient.FindNotes(`"Front:el perro"`)
+	if err := server.ankiClient.UpdateNoteFields(noteIDs[0], map[string]string{"Back": "the <b>dog</b><br>a pet"}); err != nil {
+		t.Fatal(err)
+	}
+
+	text, isErr := callTool(t, server.handleExportMarkdown, map[string]interface{}{"query": "deck:Spanish::Vocabulary", "title": "Spanish"})
+	for _, want := range []string{"# Spanish\n", "## el perro\n\nthe **dog**\na pet\n\nTags: `spanish` `vocabulary`\n", "## el agua\n"} {
+		if isErr || !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the output, got %s", want, text)
+		}
+	}
+	if strings.Count(text, "\n## ") != 6 {
+		t.Errorf("Expected 6 notes, got %s", text)
+	}
+
+	// Cloze notes have a single non-empty field besides the front
+	text, _ = callTool(t, server.handleExportMarkdown, map[string]interface{}{"query": "deck:Spanish::Grammar"})
+	if !strings.HasPrefix(text, "## Yo {{c1::soy}} estudiante") {
+		t.Errorf("Expected the cloze text as heading, got %s", text)
+	}
+
+	path := filepath.Join(t.TempDir(), "vocab.md")
+	text, isErr = callTool(t, server.handleExportMarkdown, map[string]interface{}{
+		"query": "deck:Spanish::Vocabulary", "front_field": "Back", "back_field": "Front", "output_path": path,
+	})
+	if isErr || !strings.Contains(text, "Exported 6 note(s)") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(content), "## the cat\n\nel gato\n") {
+		t.Errorf("Unexpected file content %q (%v)", content, err)
+	}
+
+	if text, isErr := callTool(t, server.handleExportMarkdown, map[string]interface{}{"query": "deck:Spanish", "front_field": "Front"}); !isErr || !strings.Contains(text, "has no field Front") {
+		t.Errorf("Expected an error for the cloze note, got %s", text)
+	}
+}
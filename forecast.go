@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// defaultForecastDays is how far review_forecast looks ahead by default
+	defaultForecastDays = 30
+	// maxForecastDays limits how far review_forecast looks ahead
+	maxForecastDays = 365
+)
+
+// registerForecastTools registers the review forecast tool
+func (a *AnkiMCPServer) registerForecastTools(s *server.MCPServer) {
+	// Tool: Review Forecast
+	reviewForecastTool := mcp.NewTool("review_forecast",
+		mcp.WithDescription("Forecast the review load day by day: how many cards of a deck fall due on each of the next days, plus the cards already overdue. "+
+			"Use it to plan around exams or holidays, e.g. to study ahead before a trip. New cards and daily limits are not taken into account."),
+		mcp.WithString("deck",
+			mcp.Description("Optional: Only include cards from this deck (and its subdecks)"),
+		),
+		mcp.WithNumber("days",
+			mcp.Description(fmt.Sprintf("Optional: Number of days to forecast, starting today (default: %d, at most %d)", defaultForecastDays, maxForecastDays)),
+		),
+		withFormat(),
+	)
+	a.addTool(s, reviewForecastTool, (*AnkiMCPServer).handleReviewForecast)
+}
+
+// forecastDay is the number of cards due on one day
+type forecastDay struct {
+	Date    string `json:"date"`
+	Reviews int    `json:"reviews"`
+}
+
+// handleReviewForecast counts the cards due on each of the next days. One
+// prop:due search per day, sent in a single multi request, leaves the day
+// boundaries to Anki.
+func (a *AnkiMCPServer) handleReviewForecast(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	days := defaultForecastDays
+	if v, ok := args["days"].(float64); ok {
+		days = int(v)
+	}
+	if days < 1 || days > maxForecastDays {
+		return a.errorf("days must be between 1 and %d", maxForecastDays), nil
+	}
+	deckName, _ := args["deck"].(string)
+	scope := "deck:*"
+	if deckName != "" {
+		scope = deckQuery(deckName)
+	}
+	scope += " -is:suspended -is:buried"
+
+	var overdue []int64
+	due := make([][]int64, days)
+	b := a.ankiClient.newBatch()
+	b.add("findCards", map[string]string{"query": scope + " prop:due<0"}, &overdue)
+	for day := range days {
+		b.add("findCards", map[string]string{"query": fmt.Sprintf("%s prop:due=%d", scope, day)}, &due[day])
+	}
+	if err := b.send(); err != nil {
+		return a.errorf("Failed to find cards: %v", err), nil
+	}
+
+	today := dayStart(time.Now(), a.rolloverHour)
+	forecast := make([]forecastDay, days)
+	total, busiest := 0, 0
+	for day, cards := range due {
+		forecast[day] = forecastDay{Date: today.AddDate(0, 0, day).Format(dateLayout), Reviews: len(cards)}
+		total += len(cards)
+		if len(cards) > len(due[busiest]) {
+			busiest = day
+		}
+	}
+
+	if wantsJSON(request) {
+		return a.jsonResult(map[string]interface{}{
+			"deck":    deckName,
+			"overdue": len(overdue),
+			"days":    forecast,
+			"total":   total,
+			"per_day": float64(total) / float64(days),
+			"busiest": forecast[busiest],
+		}), nil
+	}
+
+	title := a.t("all decks")
+	if deckName != "" {
+		title = deckName
+	}
+	out := a.newOutput()
+	out.Heading(a.t("Review forecast for %s, next %d day(s)", title, days))
+	if len(overdue) > 0 {
+		out.Item(a.t("Already overdue: %d", len(overdue)))
+	}
+	for day, f := range forecast {
+		date := a.loc.FormatDate(today.AddDate(0, 0, day))
+		if day == 0 {
+			out.Item(a.t("Today (%s): %d", date, f.Reviews))
+		} else {
+			out.Item(fmt.Sprintf("%s: %d", date, f.Reviews))
+		}
+	}
+	out.Line("")
+	out.Line(a.t("Total: %d review(s), %.1f per day on average", total, float64(total)/float64(days)))
+	if total > 0 {
+		out.Line(a.t("Busiest day: %s with %d review(s)", a.loc.FormatDate(today.AddDate(0, 0, busiest)), forecast[busiest].Reviews))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
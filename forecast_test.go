@@ -0,0 +1,36 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestReviewForecast(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	text, isErr := callTool(t, server.handleReviewForecast, map[string]interface{}{"deck": "Spanish", "days": float64(7)})
+	for _, want := range []string{"next 7 day(s)", "Already overdue: 2", "): 2\n", "Total: 5 review(s), 0.7 per day"} {
+		if isErr || !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the output, got %s", want, text)
+		}
+	}
+
+	text, _ = callTool(t, server.handleReviewForecast, map[string]interface{}{"days": float64(3), "format": "json"})
+	var forecast struct {
+		Overdue int           `json:"overdue"`
+		Days    []forecastDay `json:"days"`
+		Total   int           `json:"total"`
+	}
+	if err := json.Unmarshal([]byte(text), &forecast); err != nil {
+		t.Fatal(err)
+	}
+	if forecast.Overdue != 2 || len(forecast.Days) != 3 || forecast.Days[0].Reviews != 2 || forecast.Days[1].Reviews != 2 || forecast.Total != 5 {
+		t.Errorf("Unexpected forecast %s", text)
+	}
+
+	if text, isErr := callTool(t, server.handleReviewForecast, map[string]interface{}{"days": float64(1000)}); !isErr || !strings.Contains(text, "between 1 and 365") {
+		t.Errorf("Expected an error for too many days, got %s", text)
+	}
+}
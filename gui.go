@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerGUITools registers tools that open parts of the Anki window for the
+// user
+func (a *AnkiMCPServer) registerGUITools(s *server.MCPServer) {
+	// Tool: GUI Browse
+	guiBrowseTool := mcp.NewTool("gui_browse",
+		mcp.WithDescription("Open Anki's card browser showing the given cards, so the user can look them over and edit them in Anki, "+
+			"e.g. after finding leeches, duplicates or cards with formatting problems. Pass exactly one of card_ids, note_ids or query."),
+		mcp.WithArray("card_ids",
+			mcp.Description("IDs of the cards to show"),
+			mcp.WithNumberItems(),
+		),
+		mcp.WithArray("note_ids",
+			mcp.Description("IDs of the notes whose cards to show"),
+			mcp.WithNumberItems(),
+		),
+		mcp.WithString("query",
+			mcp.Description("Anki search query selecting the cards to show, e.g. \"deck:Spanish tag:leech\""),
+		),
+	)
+	a.addTool(s, guiBrowseTool, (*AnkiMCPServer).handleGuiBrowse)
+
+	// Tool: GUI Add Cards
+	guiAddCardsTool := mcp.NewTool("gui_add_cards",
+		mcp.WithDescription("Open Anki's Add dialog filled in with a drafted card, so the user can review and edit it and decides whether to add it. "+
+			"Nothing is added until the user clicks Add in Anki. Use create_card instead to add the card directly."),
+		mcp.WithString("deck",
+			mcp.Required(),
+			mcp.Description("Name of the deck the card goes to"),
+		),
+		mcp.WithString("front",
+			mcp.Description("Front side content, for the Basic note type"),
+		),
+		mcp.WithString("back",
+			mcp.Description("Back side content, for the Basic note type"),
+		),
+		mcp.WithString("model_name",
+			mcp.Description("Optional: Note type to use (default: Basic)"),
+		),
+		mcp.WithObject("fields",
+			mcp.Description("Optional: Field values by field name, instead of front and back, for other note types"),
+		),
+		mcp.WithArray("tags",
+			mcp.Description("Optional: Tags for the card"),
+			mcp.WithStringItems(),
+		),
+	)
+	a.addTool(s, guiAddCardsTool, (*AnkiMCPServer).handleGuiAddCards)
+}
+
+// handleGuiBrowse opens the card browser with a search for the selected cards
+func (a *AnkiMCPServer) handleGuiBrowse(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	cardIDs := numberSliceValue(args, "card_ids")
+	noteIDs := numberSliceValue(args, "note_ids")
+	query, _ := args["query"].(string)
+	query = strings.TrimSpace(query)
+
+	given := 0
+	for _, ok := range []bool{len(cardIDs) > 0, len(noteIDs) > 0, query != ""} {
+		if ok {
+			given++
+		}
+	}
+	if given != 1 {
+		return a.errorf("Pass exactly one of card_ids, note_ids or query"), nil
+	}
+	switch {
+	case len(cardIDs) > 0:
+		query = idSearch("cid", cardIDs)
+	case len(noteIDs) > 0:
+		query = idSearch("nid", noteIDs)
+	}
+
+	shown, err := a.ankiClient.GuiBrowse(query)
+	if err != nil {
+		return a.errorf("Failed to open the card browser: %v", err), nil
+	}
+
+	text := a.t("Opened Anki's card browser with %d card(s) for: %s", len(shown), query)
+	if len(shown) == 0 {
+		text = a.t("Opened Anki's card browser, but no cards match: %s", query)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}, nil
+}
+
+// idSearch returns an Anki search for cards or notes by ID, e.g. cid:1,2
+func idSearch(field string, ids []float64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatInt(int64(id), 10)
+	}
+	return field + ":" + strings.Join(parts, ",")
+}
+
+// handleGuiAddCards opens the Add dialog with a note after checking its
+// fields against the note type
+func (a *AnkiMCPServer) handleGuiAddCards(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	deck, _ := args["deck"].(string)
+	if strings.TrimSpace(deck) == "" {
+		return a.errorf("deck is required"), nil
+	}
+
+	fields := make(map[string]string)
+	for name, value := range objectValue(args, "fields") {
+		text, ok := value.(string)
+		if !ok {
+			return a.errorf("Field %s must be a string", name), nil
+		}
+		fields[name] = text
+	}
+	if len(fields) == 0 {
+		front, _ := args["front"].(string)
+		back, _ := args["back"].(string)
+		if front == "" && back == "" {
+			return a.errorf("front and back or fields are required"), nil
+		}
+		fields["Front"], fields["Back"] = front, back
+	}
+
+	model := "Basic"
+	if name, ok := args["model_name"].(string); ok && strings.TrimSpace(name) != "" {
+		model = name
+	}
+	modelFields, err := a.ankiClient.GetModelFieldNames(model)
+	if err != nil {
+		return a.errorf("Failed to get fields: %v", err), nil
+	}
+	var unknown []string
+	for name := range fields {
+		if !slices.Contains(modelFields, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return a.errorf("Unknown field(s) for note type %s: %s. Fields: %s", model, strings.Join(unknown, ", "), strings.Join(modelFields, ", ")), nil
+	}
+
+	note := Note{
+		DeckName:  deck,
+		ModelName: model,
+		Fields:    fields,
+		Tags:      stringSliceValue(args, "tags"),
+	}
+	if _, err := a.ankiClient.GuiAddCards(note); err != nil {
+		return a.errorf("Failed to open the Add dialog: %v", err), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: a.t("Opened Anki's Add dialog with a %s card for deck %s. The card is only added once the user clicks Add in Anki.", model, deck),
+			},
+		},
+	}, nil
+}
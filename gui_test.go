@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGuiBrowse(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	text, isErr := callTool(t, server.handleGuiBrowse, map[string]interface{}{"query": "deck:Spanish"})
+	if isErr || !strings.Contains(text, "with 14 card(s) for: deck:Spanish") || mock.guiBrowseQuery != "deck:Spanish" {
+		t.Errorf("Unexpected output: %s", text)
+	}
+
+	cards, _ := mock.search("deck:Spanish")
+	text, isErr = callTool(t, server.handleGuiBrowse, map[string]interface{}{"note_ids": []interface{}{float64(cards[0].NoteID)}})
+	if isErr || !strings.HasPrefix(mock.guiBrowseQuery, "nid:") || strings.Contains(text, " 0 card(s)") {
+		t.Errorf("Unexpected output for note_ids: %s (query %s)", text, mock.guiBrowseQuery)
+	}
+
+	text, isErr = callTool(t, server.handleGuiBrowse, map[string]interface{}{"card_ids": []interface{}{float64(1)}})
+	if isErr || !strings.Contains(text, "no cards match: cid:1") {
+		t.Errorf("Unexpected output for an unknown card: %s", text)
+	}
+
+	for _, args := range []map[string]interface{}{{}, {"query": "deck:Spanish", "card_ids": []interface{}{float64(1)}}} {
+		if text, isErr := callTool(t, server.handleGuiBrowse, args); !isErr || !strings.Contains(text, "exactly one") {
+			t.Errorf("Expected an error for %v, got %s", args, text)
+		}
+	}
+}
+
+func TestGuiAddCards(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	text, isErr := callTool(t, server.handleGuiAddCards, map[string]interface{}{
+		"deck":  "Spanish",
+		"front": "el ornitorrinco",
+		"back":  "the platypus",
+		"tags":  []interface{}{"animals"},
+	})
+	if isErr || !strings.Contains(text, "Add dialog with a Basic card for deck Spanish") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	note := mock.guiAddNote
+	if note == nil || note.Fields["Front"] != "el ornitorrinco" || note.Fields["Back"] != "the platypus" || len(note.Tags) != 1 {
+		t.Errorf("Unexpected note in the Add dialog: %+v", note)
+	}
+	if cards, _ := mock.search(`"el ornitorrinco"`); len(cards) != 0 {
+		t.Error("Expected no card to be added before the user confirms")
+	}
+
+	text, isErr = callTool(t, server.handleGuiAddCards, map[string]interface{}{
+		"deck":   "Spanish",
+		"fields": map[string]interface{}{"Frente": "el perro"},
+	})
+	if !isErr || !strings.Contains(text, "Unknown field(s) for note type Basic: Frente") {
+		t.Errorf("Expected an unknown field error, got %s", text)
+	}
+
+	text, isErr = callTool(t, server.handleGuiAddCards, map[string]interface{}{"deck": "Nope", "front": "uno", "back": "one"})
+	if !isErr || !strings.Contains(text, "deck was not found") || !strings.Contains(text, "Hint: The deck doesn't exist") {
+		t.Errorf("Expected a deck error with a hint, got %s", text)
+	}
+}
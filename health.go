@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerHealthTools registers the tool that checks the connection to Anki
+func (a *AnkiMCPServer) registerHealthTools(s *server.MCPServer) {
+	// Tool: Health Check
+	healthCheckTool := mcp.NewTool("health_check",
+		mcp.WithDescription("Check that Anki and AnkiConnect are running and set up: reports the AnkiConnect version, the active profile, "+
+			"the number of decks and note types, the media folder and how long AnkiConnect takes to answer. Call it first when other tools fail."),
+		withFormat(),
+	)
+	a.addTool(s, healthCheckTool, (*AnkiMCPServer).handleHealthCheck)
+}
+
+// healthReport is the outcome of health_check
+type healthReport struct {
+	URL       string   `json:"url"`
+	Version   int      `json:"version"`
+	LatencyMS int64    `json:"latency_ms"`
+	Profile   string   `json:"profile,omitempty"`
+	Decks     int      `json:"decks"`
+	Models    int      `json:"models"`
+	MediaDir  string   `json:"media_dir,omitempty"`
+	Problems  []string `json:"problems"`
+	Healthy   bool     `json:"healthy"`
+}
+
+// handleHealthCheck asks AnkiConnect for its version, timing the round trip,
+// and then for the rest of the report in one multi request. Parts that fail
+// are listed as problems instead of failing the check.
+func (a *AnkiMCPServer) handleHealthCheck(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	start := time.Now()
+	version, err := invoke[int](a.ankiClient, "version", nil)
+	latency := time.Since(start)
+	if err != nil {
+		return a.errorf("AnkiConnect at %s did not answer: %v", a.ankiClient.URL, err), nil
+	}
+
+	report := healthReport{
+		URL:       a.ankiClient.URL,
+		Version:   version,
+		LatencyMS: latency.Milliseconds(),
+		Problems:  []string{},
+	}
+	if version < ankiConnectVersion {
+		report.Problems = append(report.Problems, a.t("AnkiConnect supports API version %d, but this server needs version %d; update AnkiConnect", version, ankiConnectVersion))
+	}
+
+	var (
+		decks  []string
+		models []string
+	)
+	checks := []struct {
+		action string
+		result interface{}
+	}{
+		{"getActiveProfile", &report.Profile},
+		{"deckNames", &decks},
+		{"modelNames", &models},
+		{"getMediaDirPath", &report.MediaDir},
+	}
+	actions := make([]ankiRequest, len(checks))
+	for i, check := range checks {
+		actions[i] = a.ankiClient.action(check.action, nil)
+	}
+	responses, err := a.ankiClient.multi(actions)
+	if err != nil {
+		return a.errorf("Failed to check the collection: %v", err), nil
+	}
+	for i, resp := range responses {
+		problem := resp.Error
+		if problem == "" {
+			if err := decodeResult(resp.Result, checks[i].result); err != nil {
+				problem = err.Error()
+			}
+		}
+		if problem != "" {
+			report.Problems = append(report.Problems, a.healthProblem(checks[i].action, problem))
+		}
+	}
+	report.Decks = len(decks)
+	report.Models = len(models)
+	report.Healthy = len(report.Problems) == 0
+
+	if wantsJSON(request) {
+		return a.jsonResult(report), nil
+	}
+
+	out := a.newOutput()
+	if report.Healthy {
+		out.Heading(a.t("Health check: OK"))
+	} else {
+		out.Heading(a.t("Health check: %d problem(s)", len(report.Problems)))
+	}
+	out.Item(a.t("AnkiConnect: %s, API version %d", report.URL, report.Version))
+	out.Item(a.t("Latency: %s", latency.Round(time.Millisecond)))
+	if report.Profile != "" {
+		out.Item(a.t("Profile: %s", report.Profile))
+	}
+	out.Item(a.t("Decks: %d", report.Decks))
+	out.Item(a.t("Note types: %d", report.Models))
+	if report.MediaDir != "" {
+		out.Item(a.t("Media folder: %s", report.MediaDir))
+	}
+	for _, problem := range report.Problems {
+		out.Item(a.t("Problem: %s", problem))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// healthProblem describes a part of the health check that failed
+func (a *AnkiMCPServer) healthProblem(action, problem string) string {
+	switch action {
+	case "getActiveProfile":
+		return a.t("Could not get the active profile: %s", problem)
+	case "deckNames":
+		return a.t("Could not get the decks: %s", problem)
+	case "modelNames":
+		return a.t("Could not get the note types: %s", problem)
+	default:
+		return a.t("Could not get the media folder: %s", problem)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHealthCheck(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	text, isErr := callTool(t, server.handleHealthCheck, map[string]interface{}{})
+	for _, want := range []string{"Health check: OK", "API version 6", "Profile: User 1", "Decks: 4", "Media folder: "} {
+		if isErr || !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the output, got %s", want, text)
+		}
+	}
+
+	text, _ = callTool(t, server.handleHealthCheck, map[string]interface{}{"format": "json"})
+	var report healthReport
+	if err := json.Unmarshal([]byte(text), &report); err != nil {
+		t.Fatal(err)
+	}
+	if !report.Healthy || report.Decks != 4 || report.Models == 0 || len(report.Problems) != 0 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+}
+
+func TestHealthCheckProblems(t *testing.T) {
+	// An old AnkiConnect without getActiveProfile
+	anki := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ankiRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Action == "version" {
+			_, _ = w.Write([]byte(`{"result": 5, "error": null}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"result": [
+			{"result": null, "error": "unsupported action"},
+			{"result": ["Default"], "error": null},
+			{"result": ["Basic"], "error": null},
+			{"result": "/anki/collection.media", "error": null}
+		], "error": null}`))
+	}))
+	t.Cleanup(anki.Close)
+
+	server := NewAnkiMCPServerWithConfig(Config{
+		AnkiConnectURL: anki.URL,
+		Retry:          RetryPolicy{Attempts: 1},
+		Language:       defaultLanguage,
+		OutputStyle:    outputMarkdown,
+		StateDir:       t.TempDir(),
+	})
+	text, isErr := callTool(t, server.handleHealthCheck, map[string]interface{}{})
+	for _, want := range []string{"Health check: 2 problem(s)", "update AnkiConnect", "Could not get the active profile: unsupported action", "Decks: 1"} {
+		if isErr || !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the output, got %s", want, text)
+		}
+	}
+
+	anki.Close()
+	text, isErr = callTool(t, server.handleHealthCheck, map[string]interface{}{})
+	if !isErr || !strings.Contains(text, "did not answer") || !strings.Contains(text, "Hint: Start Anki") {
+		t.Errorf("Expected an error with a hint, got %s", text)
+	}
+}
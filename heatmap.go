@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// defaultHeatmapDays is how far back study_heatmap looks by default
+	defaultHeatmapDays = 365
+	// maxHeatmapDays limits how far back study_heatmap looks
+	maxHeatmapDays = 3650
+)
+
+// registerHeatmapTools registers the study heatmap tool
+func (a *AnkiMCPServer) registerHeatmapTools(s *server.MCPServer) {
+	// Tool: Study Heatmap
+	studyHeatmapTool := mcp.NewTool("study_heatmap",
+		mcp.WithDescription("Export study activity as JSON: the number of reviews and the study time in seconds for every day of the last year "+
+			"(days without reviews included), plus the days studied, the current and longest streak and the longest gap. "+
+			"Use it to render a heatmap or to analyze streaks and gaps."),
+		mcp.WithString("deck",
+			mcp.Description("Optional: Only include reviews of cards in this deck (and its subdecks)"),
+		),
+		mcp.WithNumber("days",
+			mcp.Description(fmt.Sprintf("Optional: Number of days to include, ending today (default: %d, at most %d)", defaultHeatmapDays, maxHeatmapDays)),
+		),
+	)
+	a.addTool(s, studyHeatmapTool, (*AnkiMCPServer).handleStudyHeatmap)
+}
+
+// heatmapDay is the study activity of one day
+type heatmapDay struct {
+	Date    string `json:"date"`
+	Reviews int    `json:"reviews"`
+	Seconds int    `json:"seconds"`
+}
+
+// heatmapSummary holds the streaks and gaps of a heatmap
+type heatmapSummary struct {
+	DaysStudied   int `json:"days_studied"`
+	TotalReviews  int `json:"total_reviews"`
+	TotalSeconds  int `json:"total_seconds"`
+	CurrentStreak int `json:"current_streak"`
+	LongestStreak int `json:"longest_streak"`
+	// LongestGap is the longest run of days without reviews after the first
+	// studied day of the range
+	LongestGap int `json:"longest_gap"`
+}
+
+// summarizeHeatmap computes streaks and gaps of days ordered from oldest to
+// today. The current streak still counts when today has no reviews yet.
+func summarizeHeatmap(days []heatmapDay) heatmapSummary {
+	var summary heatmapSummary
+	streak, gap, studied := 0, 0, false
+	for _, day := range days {
+		summary.TotalReviews += day.Reviews
+		summary.TotalSeconds += day.Seconds
+		if day.Reviews > 0 {
+			summary.DaysStudied++
+			streak++
+			gap = 0
+			studied = true
+			summary.LongestStreak = max(summary.LongestStreak, streak)
+			continue
+		}
+		streak = 0
+		if studied {
+			gap++
+			summary.LongestGap = max(summary.LongestGap, gap)
+		}
+	}
+
+	for i := len(days) - 1; i >= 0; i-- {
+		if days[i].Reviews == 0 {
+			if i == len(days)-1 {
+				continue
+			}
+			break
+		}
+		summary.CurrentStreak++
+	}
+	return summary
+}
+
+// handleStudyHeatmap exports the number of reviews and the study time per day,
+// bucketed by Anki day (which starts at the rollover hour)
+func (a *AnkiMCPServer) handleStudyHeatmap(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	numDays := defaultHeatmapDays
+	if v, ok := args["days"].(float64); ok {
+		numDays = int(v)
+	}
+	if numDays < 1 || numDays > maxHeatmapDays {
+		return a.errorf("days must be between 1 and %d", maxHeatmapDays), nil
+	}
+	deckName, _ := args["deck"].(string)
+
+	today := dayStart(time.Now(), a.rolloverHour)
+	first := today.AddDate(0, 0, -(numDays - 1))
+	startID := first.Add(time.Duration(a.rolloverHour)*time.Hour).UnixMilli() - 1
+	entries, err := a.collectReviews(deckName, startID)
+	if err != nil {
+		return a.errorf("Failed to get review log: %v", err), nil
+	}
+
+	days := make([]heatmapDay, numDays)
+	durations := make([]int64, numDays)
+	index := make(map[string]int, numDays)
+	for i := range days {
+		date := first.AddDate(0, 0, i).Format(dateLayout)
+		days[i].Date = date
+		index[date] = i
+	}
+	for _, e := range entries {
+		// Manual rescheduling isn't studying
+		if e.ReviewType == 4 || e.ButtonPressed == 0 {
+			continue
+		}
+		i, ok := index[dayStart(time.UnixMilli(e.ReviewTime), a.rolloverHour).Format(dateLayout)]
+		if !ok {
+			continue
+		}
+		days[i].Reviews++
+		durations[i] += e.ReviewDuration
+	}
+	for i, ms := range durations {
+		days[i].Seconds = int((ms + 500) / 1000)
+	}
+
+	return a.jsonResult(map[string]interface{}{
+		"deck":       deckName,
+		"start_date": days[0].Date,
+		"end_date":   days[len(days)-1].Date,
+		"days":       days,
+		"summary":    summarizeHeatmap(days),
+	}), nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestStudyHeatmap(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	text, isErr := callTool(t, server.handleStudyHeatmap, map[string]interface{}{"deck": "Spanish", "days": float64(60)})
+	if isErr {
+		t.Fatalf("Unexpected error: %s", text)
+	}
+	var heatmap struct {
+		Days    []heatmapDay   `json:"days"`
+		Summary heatmapSummary `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(text), &heatmap); err != nil {
+		t.Fatal(err)
+	}
+	s := heatmap.Summary
+	if len(heatmap.Days) != 60 || s.DaysStudied != 3 || s.TotalReviews != 21 || s.LongestStreak != 1 || s.LongestGap != 9 || s.TotalSeconds == 0 {
+		t.Errorf("Unexpected heatmap %+v", s)
+	}
+
+	text, _ = callTool(t, server.handleStudyHeatmap, map[string]interface{}{"deck": "Default"})
+	if err := json.Unmarshal([]byte(text), &heatmap); err != nil {
+		t.Fatal(err)
+	}
+	if len(heatmap.Days) != defaultHeatmapDays || heatmap.Summary.TotalReviews != 0 {
+		t.Errorf("Expected an empty year for a deck without reviews, got %+v", heatmap.Summary)
+	}
+
+	if text, isErr := callTool(t, server.handleStudyHeatmap, map[string]interface{}{"days": float64(0)}); !isErr || !strings.Contains(text, "between 1 and") {
+		t.Errorf("Expected an error for zero days, got %s", text)
+	}
+}
+
+func TestSummarizeHeatmap(t *testing.T) {
+	days := []heatmapDay{{Reviews: 0}, {Reviews: 3}, {Reviews: 0}, {Reviews: 0}, {Reviews: 1}, {Reviews: 2}, {Reviews: 0}}
+	s := summarizeHeatmap(days)
+	if s.DaysStudied != 3 || s.TotalReviews != 6 || s.LongestStreak != 2 || s.LongestGap != 2 || s.CurrentStreak != 2 {
+		t.Errorf("Unexpected summary %+v", s)
+	}
+
+	days[len(days)-2].Reviews = 0
+	if s := summarizeHeatmap(days); s.CurrentStreak != 0 {
+		t.Errorf("Expected a broken streak, got %+v", s)
+	}
+}
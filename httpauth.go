@@ -0,0 +1,63 @@
+package main
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// checkHTTPExposure refuses to serve the HTTP transports on an address other
+// machines can reach unless clients must send a token. The tools read and
+// write files on this machine, so an open server would hand that to anyone
+// on the network.
+func checkHTTPExposure(config Config) error {
+	if config.Transport == transportStdio || config.HTTPToken != "" || isLoopbackAddr(config.HTTPAddr) {
+		return nil
+	}
+	return fmt.Errorf("refusing to listen on %s without authentication: set ANKI_MCP_HTTP_TOKEN, or listen on localhost", config.HTTPAddr)
+}
+
+// isLoopbackAddr reports whether a listen address only accepts connections
+// from this machine
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// requireToken lets only requests with the bearer token through to next. An
+// empty token lets every request through.
+func requireToken(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheme, given, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !strings.EqualFold(scheme, "Bearer") || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(given)), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="anki-mcp"`)
+			http.Error(w, "missing or wrong bearer token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// serveHTTP serves an MCP transport handler on the configured address,
+// checking the bearer token of every request
+func serveHTTP(config Config, handler http.Handler) error {
+	srv := &http.Server{
+		Addr:              config.HTTPAddr,
+		Handler:           requireToken(config.HTTPToken, handler),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return srv.ListenAndServe()
+}
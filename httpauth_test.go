@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckHTTPExposure(t *testing.T) {
+	tests := []struct {
+		config Config
+		ok     bool
+	}{
+		{Config{Transport: transportStdio, HTTPAddr: "0.0.0.0:8080"}, true},
+		{Config{Transport: transportHTTP, HTTPAddr: "localhost:8080"}, true},
+		{Config{Transport: transportHTTP, HTTPAddr: "127.0.0.1:8080"}, true},
+		{Config{Transport: transportSSE, HTTPAddr: "[::1]:8080"}, true},
+		{Config{Transport: transportHTTP, HTTPAddr: "0.0.0.0:8080"}, false},
+		{Config{Transport: transportSSE, HTTPAddr: ":8080"}, false},
+		{Config{Transport: transportHTTP, HTTPAddr: "0.0.0.0:8080", HTTPToken: "secret"}, true},
+	}
+	for _, tt := range tests {
+		if err := checkHTTPExposure(tt.config); (err == nil) != tt.ok {
+			t.Errorf("checkHTTPExposure(%s, %q, token %t) = %v", tt.config.Transport, tt.config.HTTPAddr, tt.config.HTTPToken != "", err)
+		}
+	}
+}
+
+func TestRequireToken(t *testing.T) {
+	handler := requireToken("secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	tests := []struct {
+		header string
+		want   int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"Basic secret", http.StatusUnauthorized},
+		{"Bearer secret", http.StatusNoContent},
+		{"bearer secret", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("Authorization %q: status %d, want %d", tt.header, rec.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultLanguage is the language tool output is written in when none is configured
+const defaultLanguage = "en"
+
+// catalogs maps a language code to its translations. Messages are keyed by their
+// English format string; messages without a translation are shown in English.
+var catalogs = map[string]map[string]string{
+	"de": messagesDE,
+	"es": messagesES,
+	"fr": messagesFR,
+}
+
+// dateLayouts holds the date format used for each language
+var dateLayouts = map[string]string{
+	"en": "2006-01-02",
+	"de": "02.01.2006",
+	"es": "02/01/2006",
+	"fr": "02/01/2006",
+}
+
+// localizer translates tool output and formats dates for one language
+type localizer struct {
+	lang       string
+	messages   map[string]string
+	dateLayout string
+}
+
+// newLocalizer creates a localizer for a language code such as "es", "de-DE"
+// or "fr_FR.UTF-8". Unsupported languages fall back to English.
+func newLocalizer(lang string) *localizer {
+	code := strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(code, "-_."); i >= 0 {
+		code = code[:i]
+	}
+	if _, ok := dateLayouts[code]; !ok {
+		code = defaultLanguage
+	}
+
+	return &localizer{
+		lang:       code,
+		messages:   catalogs[code],
+		dateLayout: dateLayouts[code],
+	}
+}
+
+// Sprintf translates format and formats it with the given arguments
+func (l *localizer) Sprintf(format string, args ...interface{}) string {
+	if translated, ok := l.messages[format]; ok {
+		format = translated
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// FormatDate formats a date for the configured language
+func (l *localizer) FormatDate(t time.Time) string {
+	return t.Format(l.dateLayout)
+}
+
+// FormatDateTime formats a date and time of day for the configured language
+func (l *localizer) FormatDateTime(t time.Time) string {
+	return t.Format(l.dateLayout + " 15:04")
+}
@@ -0,0 +1,768 @@
+package main
+
+// messagesDE holds the German translations of tool output
+var messagesDE = map[string]string{
+	// Common
+	"Error: %s":                    "Fehler: %s",
+	"all decks":                    "alle Stapel",
+	"card_id is required":          "card_id ist erforderlich",
+	"data or path is required":     "data oder path ist erforderlich",
+	"unsupported format: %s":       "nicht unterstütztes Format: %s",
+	"Failed to find cards: %v":     "Karten konnten nicht gesucht werden: %v",
+	"Failed to get card info: %v":  "Karteninformationen konnten nicht abgerufen werden: %v",
+	"Failed to get note info: %v":  "Notizinformationen konnten nicht abgerufen werden: %v",
+	"Failed to read %s: %v":        "%s konnte nicht gelesen werden: %v",
+	"Failed to write %s: %v":       "%s konnte nicht geschrieben werden: %v",
+	"No cards found for query: %s": "Keine Karten für die Suche gefunden: %s",
+	"%.0f minute(s)":               "%.0f Minute(n)",
+	"%.1f hour(s)":                 "%.1f Stunde(n)",
+	"%.0f day(s)":                  "%.0f Tag(e)",
+	"%.1f month(s)":                "%.1f Monat(e)",
+	"%.1f year(s)":                 "%.1f Jahr(e)",
+
+	// Cards and decks
+	"deck is required":                  "deck ist erforderlich",
+	"front is required":                 "front ist erforderlich",
+	"back is required":                  "back ist erforderlich",
+	"name is required":                  "name ist erforderlich",
+	"Failed to read image file: %v":     "Bilddatei konnte nicht gelesen werden: %v",
+	"Failed to store image: %v":         "Bild konnte nicht gespeichert werden: %v",
+	"Failed to create card: %v":         "Karte konnte nicht erstellt werden: %v",
+	"Created card (ID: %d)":             "Karte erstellt (ID: %d)",
+	"Failed to get decks: %v":           "Stapel konnten nicht abgerufen werden: %v",
+	"Decks (%d)":                        "Stapel (%d)",
+	"Failed to create deck: %v":         "Stapel konnte nicht erstellt werden: %v",
+	"Created deck: %s":                  "Stapel erstellt: %s",
+	"Failed to verify stored image: %v": "Gespeichertes Bild konnte nicht überprüft werden: %v",
+	"Stored media":                      "Gespeicherte Medien",
+	"%s (%d bytes, verified)":           "%s (%d Bytes, überprüft)",
+	"%s (renamed from %s to avoid overwriting an existing file, %d bytes, verified)": "%s (umbenannt von %s, um eine vorhandene Datei nicht zu überschreiben, %d Bytes, überprüft)",
+	"tag is required":                                                            "tag ist erforderlich",
+	"Failed to suspend cards: %v":                                                "Karten konnten nicht ausgesetzt werden: %v",
+	"Failed to unsuspend cards: %v":                                              "Aussetzung der Karten konnte nicht aufgehoben werden: %v",
+	"No unsuspended cards found with tag %s":                                     "Keine nicht ausgesetzten Karten mit dem Schlagwort %s gefunden",
+	"No suspended cards found with tag %s":                                       "Keine ausgesetzten Karten mit dem Schlagwort %s gefunden",
+	"Suspended %d card(s) with tag %s":                                           "%d Karte(n) mit dem Schlagwort %s ausgesetzt",
+	"Unsuspended %d card(s) with tag %s":                                         "Aussetzung von %d Karte(n) mit dem Schlagwort %s aufgehoben",
+	"Failed to create note type %s: %v":                                          "Fehler beim Erstellen des Notiztyps %s: %v",
+	"Added a reverse card (back → front) with the note type %s":                  "Umgekehrte Karte (Rückseite → Vorderseite) mit dem Notiztyp %s hinzugefügt",
+	"Created the missing note type %s":                                           "Fehlenden Notiztyp %s erstellt",
+	"cards is required":                                                          "cards ist erforderlich",
+	"front and back are required":                                                "front und back sind erforderlich",
+	"Created %d of %d card(s)":                                                   "%d von %d Karte(n) erstellt",
+	"#%d failed: %s":                                                             "#%d fehlgeschlagen: %s",
+	"#%d: note %d in %s":                                                         "#%d: Notiz %d in %s",
+	"Field %s must be a string":                                                  "Das Feld %s muss ein Text sein",
+	"reversed can't be combined with the note type %s":                           "reversed kann nicht mit dem Notiztyp %s kombiniert werden",
+	"Unknown field(s) for note type %s: %s. Fields: %s":                          "Unbekannte(s) Feld(er) für den Notiztyp %s: %s. Felder: %s",
+	"The first field of note type %s must not be empty":                          "Das erste Feld des Notiztyps %s darf nicht leer sein",
+	"The Default deck can't be deleted":                                          "Der Stapel Default kann nicht gelöscht werden",
+	"Would delete deck %s with %d subdeck(s) and %d card(s)":                     "Würde den Stapel %s mit %d Unterstapel(n) und %d Karte(n) löschen",
+	"Would delete deck %s with %d subdeck(s) and move %d card(s) to %s":          "Würde den Stapel %s mit %d Unterstapel(n) löschen und %d Karte(n) nach %s verschieben",
+	"Failed to delete deck: %v":                                                  "Fehler beim Löschen des Stapels: %v",
+	"Deleted deck %s with %d subdeck(s) and %d card(s)":                          "Stapel %s mit %d Unterstapel(n) und %d Karte(n) gelöscht",
+	"Deleted deck %s with %d subdeck(s) and moved %d card(s) to %s":              "Stapel %s mit %d Unterstapel(n) gelöscht und %d Karte(n) nach %s verschoben",
+	"Pass either new_name or parent, not both":                                   "Gib entweder new_name oder parent an, nicht beides",
+	"new_name or parent is required":                                             "new_name oder parent ist erforderlich",
+	"The Default deck can't be renamed":                                          "Der Stapel Default kann nicht umbenannt werden",
+	"The deck is already named %s":                                               "Der Stapel heißt bereits %s",
+	"A deck can't be moved into its own subdeck":                                 "Ein Stapel kann nicht in seinen eigenen Unterstapel verschoben werden",
+	"Deck already exists: %s":                                                    "Stapel existiert bereits: %s",
+	"Moved %d card(s) to %s, but %d card(s) remain in %s, so it was not deleted": "%d Karte(n) nach %s verschoben, aber %d Karte(n) verbleiben in %s, daher wurde er nicht gelöscht",
+	"Renamed deck %s to %s":                                                      "Stapel %s in %s umbenannt",
+	"%d card(s) of %s are in %s (card IDs: %s)":                                  "%d Karte(n) aus %s liegen in %s (Karten-IDs: %s)",
+	"%s. Moving the cards back failed as well, so the rename is half done: %s":   "%s. Auch das Zurückverschieben der Karten ist fehlgeschlagen, die Umbenennung ist daher nur halb erledigt: %s",
+	"%s. Nothing was renamed: the cards moved so far were moved back":            "%s. Es wurde nichts umbenannt: die bisher verschobenen Karten wurden zurückverschoben",
+	"Would rename deck %s to %s":                                                 "Würde Stapel %s in %s umbenennen",
+	"Subdecks to move: %d":                                                       "Zu verschiebende Unterstapel: %d",
+	"Cards to move: %d":                                                          "Zu verschiebende Karten: %d",
+	"Subdecks moved: %d":                                                         "Verschobene Unterstapel: %d",
+	"Cards moved: %d":                                                            "Verschobene Karten: %d",
+	"Pass either card_ids or query, not both":                                    "Gib entweder card_ids oder query an, nicht beides",
+	"card_ids or query is required":                                              "card_ids oder query ist erforderlich",
+	"No cards found to move":                                                     "Keine Karten zum Verschieben gefunden",
+	"Moved %d card(s) to %s":                                                     "%d Karte(n) nach %s verschoben",
+	"Already in the deck: %d":                                                    "Bereits im Stapel: %d",
+	"Cards not found: %d":                                                        "Nicht gefundene Karten: %d",
+	"No cards found":                                                             "Keine Karten gefunden",
+	"Suspended %d card(s)":                                                       "%d Karte(n) ausgesetzt",
+	"Already suspended: %d":                                                      "Bereits ausgesetzt: %d",
+	"Unsuspended %d card(s)":                                                     "%d Karte(n) reaktiviert",
+	"Not suspended: %d":                                                          "Nicht ausgesetzt: %d",
+	"Exported deck %s to %s":                                                     "Stapel %s nach %s exportiert",
+	"Would export deck %s to %s":                                                 "Würde Stapel %s nach %s exportieren",
+	"Would export deck %s and return it as %s":                                   "Würde Stapel %s exportieren und als %s zurückgeben",
+	"Failed to create temporary directory: %v":                                   "Temporäres Verzeichnis konnte nicht erstellt werden: %v",
+	"Failed to export deck: %v":                                                  "Stapel konnte nicht exportiert werden: %v",
+	"Failed to read the exported file; Anki has to run on the same machine to return the file, otherwise pass path: %v": "Die exportierte Datei konnte nicht gelesen werden; Anki muss auf demselben Rechner laufen, um die Datei zurückzugeben, andernfalls path angeben: %v",
+	"path must end in .apkg":                             "path muss auf .apkg enden",
+	"Invalid duplicate_scope %q: use collection or deck": "Ungültiger duplicate_scope %q: verwende collection oder deck",
+	"duplicate_scope_deck needs duplicate_scope deck":    "duplicate_scope_deck erfordert duplicate_scope deck",
+	"Already new: %d":                                    "Bereits neu: %d",
+	"Failed to relearn cards: %v":                        "Karten konnten nicht neu gelernt werden: %v",
+	"Failed to reset cards: %v":                          "Karten konnten nicht zurückgesetzt werden: %v",
+	"Relearning %d card(s)":                              "%d Karte(n) werden neu gelernt",
+	"Reset %d card(s) to new":                            "%d Karte(n) auf neu zurückgesetzt",
+	"Would relearn %d card(s)":                           "%d Karte(n) würden neu gelernt",
+	"Would reset %d card(s) to new":                      "%d Karte(n) würden auf neu zurückgesetzt",
+	"Failed to reschedule cards: %v":                     "Karten konnten nicht neu geplant werden: %v",
+	"Rescheduled %d card(s) to %s":                       "%d Karte(n) auf %s verschoben",
+	"Spread %d card(s) between %s and %s":                "%d Karte(n) zwischen %s und %s verteilt",
+	"date is required":                                   "date ist erforderlich",
+	"until must not be before date":                      "until darf nicht vor date liegen",
+
+	// Maintenance
+	"check_database was not run. It can take several minutes on large collections and blocks Anki while running. Ask the user to explicitly confirm, then call again with confirm=true.": "check_database wurde nicht ausgeführt. Die Prüfung kann bei großen Sammlungen mehrere Minuten dauern und blockiert Anki währenddessen. Bitte den Benutzer um eine ausdrückliche Bestätigung und rufe das Werkzeug dann mit confirm=true erneut auf.",
+	"Failed to check database: %v":    "Datenbank konnte nicht geprüft werden: %v",
+	"Failed to clear unused tags: %v": "Unbenutzte Tags konnten nicht entfernt werden: %v",
+	"No unused tags found":            "Keine unbenutzten Tags gefunden",
+	"Removed %d unused tag(s)":        "%d unbenutzte(s) Tag(s) entfernt",
+	"When it is done, Anki shows in its window whether the collection needed repair and what it fixed.": "Wenn sie abgeschlossen ist, zeigt Anki in seinem Fenster, ob die Sammlung repariert werden musste und was behoben wurde.",
+	"Started Anki's database check. It runs in the background and may still be running.":                "Ankis Datenbankprüfung wurde gestartet. Sie läuft im Hintergrund und ist möglicherweise noch nicht abgeschlossen.",
+
+	// Statistics
+	"Tag statistics for %s (%d of %d tags, sorted by %s)":              "Schlagwort-Statistik für %s (%d von %d Schlagwörtern, sortiert nach %s)",
+	"%s: %d notes, %d cards, %d due, avg ease %.0f%%, avg lapses %.2f": "%s: %d Notizen, %d Karten, %d fällig, Ø Leichtigkeit %.0f%%, Ø Fehlschläge %.2f",
+	"Failed to get review log: %v":                                     "Wiederholungsprotokoll konnte nicht abgerufen werden: %v",
+	"Progress for %s from %s to %s":                                    "Fortschritt für %s vom %s bis %s",
+	"Reviews done: %d (%d distinct cards)":                             "Wiederholungen: %d (%d verschiedene Karten)",
+	"Cards learned: %d":                                                "Gelernte Karten: %d",
+	"Cards matured: %d":                                                "Ausgereifte Karten: %d",
+	"Mature cards: %d -> %d":                                           "Ausgereifte Karten gesamt: %d -> %d",
+	"Retention: %.1f%% of %d review answers":                           "Behaltensrate: %.1f%% von %d Wiederholungsantworten",
+	"Retention: no reviews of graduated cards in this period":          "Behaltensrate: keine Wiederholungen gelernter Karten in diesem Zeitraum",
+	"Study time: %s":                                                   "Lernzeit: %s",
+	"Average ease: %.0f%%":                                             "Durchschnittliche Leichtigkeit: %.0f%%",
+	"Average interval: %s":                                             "Durchschnittliches Intervall: %s",
+	"Cards: %d in %d note(s)":                                          "Karten: %d in %d Notiz(en)",
+	"Collection statistics":                                            "Sammlungsstatistik",
+	"Could not read any metrics from Anki's statistics report. They are only recognized when Anki's interface language is English.": "Aus dem Statistikbericht von Anki konnten keine Werte gelesen werden. Sie werden nur erkannt, wenn die Oberfläche von Anki auf Englisch eingestellt ist.",
+	"Failed to get collection statistics: %v":             "Sammlungsstatistik konnte nicht abgerufen werden: %v",
+	"Mature cards: %d (%.1f%%)":                           "Ausgereifte Karten: %d (%.1f%%)",
+	"Retention over the last month: %.1f%% of %d answers": "Behaltensquote im letzten Monat: %.1f%% von %d Antworten",
+	"Studied today: %d card(s) in %s":                     "Heute gelernt: %d Karte(n) in %s",
+	"%s: %d review(s)":                                    "%s: %d Wiederholung(en)",
+	"Average per day studied: %.1f reviews":               "Durchschnitt pro Lerntag: %.1f Wiederholungen",
+	"Days studied: %d of %d":                              "Lerntage: %d von %d",
+	"Failed to get review history: %v":                    "Wiederholungsverlauf konnte nicht abgerufen werden: %v",
+	"Review history from %s to %s":                        "Wiederholungsverlauf vom %s bis %s",
+	"Reviews: %d":                                         "Wiederholungen: %d",
+	"Reviews per day":                                     "Wiederholungen pro Tag",
+	"Reviews per month":                                   "Wiederholungen pro Monat",
+	"Reviews per week":                                    "Wiederholungen pro Woche",
+	"Week of %s":                                          "Woche vom %s",
+
+	// Review log
+	"Failed to get reviews: %v":                       "Wiederholungen konnten nicht abgerufen werden: %v",
+	"Failed to write CSV: %v":                         "CSV konnte nicht geschrieben werden: %v",
+	"Exported %d reviews of %d cards to %s":           "%d Wiederholungen von %d Karten nach %s exportiert",
+	"Failed to parse reviews: %v":                     "Wiederholungen konnten nicht gelesen werden: %v",
+	"no reviews found in input":                       "keine Wiederholungen in der Eingabe gefunden",
+	"Failed to import reviews: %v":                    "Wiederholungen konnten nicht importiert werden: %v",
+	"Imported %d reviews for %d cards":                "%d Wiederholungen für %d Karten importiert",
+	"%s: %s (%s), interval %s → %s, ease %d%%, %.0fs": "%s: %s (%s), Intervall %s → %s, Leichtigkeit %d%%, %.0fs",
+	"%s: rescheduled to %s":                           "%s: neu geplant auf %s",
+	"filtered deck":                                   "gefilterter Stapel",
+
+	// Card explanation
+	"Card %d not found":                 "Karte %d nicht gefunden",
+	"Card %d in deck %q (note type %s)": "Karte %d im Stapel %q (Notiztyp %s)",
+	"If you answered it now":            "Wenn du sie jetzt beantworten würdest",
+	"State: suspended. It will not be shown until it is unsuspended.":                      "Status: ausgesetzt. Sie wird erst wieder gezeigt, wenn sie reaktiviert wird.",
+	"State: buried. It will return to its queue tomorrow (or when unburied).":              "Status: zurückgestellt. Sie kehrt morgen (oder beim Aufheben) in ihre Warteschlange zurück.",
+	"State: new. It has never been studied and is waiting in the new card queue.":          "Status: neu. Sie wurde noch nie gelernt und wartet in der Warteschlange für neue Karten.",
+	"State: relearning. It was forgotten and is going through the relearning steps.":       "Status: wird neu gelernt. Sie wurde vergessen und durchläuft die Neulernschritte.",
+	"State: learning. It is going through the initial learning steps.":                     "Status: in Lernphase. Sie durchläuft die ersten Lernschritte.",
+	"State: review. It has graduated from learning and is shown at growing intervals.":     "Status: Wiederholung. Sie hat die Lernphase abgeschlossen und wird in wachsenden Abständen gezeigt.",
+	"State: preview in a filtered deck.":                                                   "Status: Vorschau in einem gefilterten Stapel.",
+	"Current interval: %s.":                                                                "Aktuelles Intervall: %s.",
+	"Ease: %.0f%%. Good multiplies the interval by roughly this factor.":                   "Leichtigkeit: %.0f%%. „Gut“ multipliziert das Intervall ungefähr mit diesem Faktor.",
+	"Due: %s (learning step).":                                                             "Fällig: %s (Lernschritt).",
+	"Position in the new card queue: %d.":                                                  "Position in der Warteschlange für neue Karten: %d.",
+	"Due: today (%s).":                                                                     "Fällig: heute (%s).",
+	"Due: overdue by %d day(s) (was due %s).":                                              "Fällig: %d Tag(e) überfällig (war fällig am %s).",
+	"Due: in %d day(s) (%s).":                                                              "Fällig: in %d Tag(en) (%s).",
+	"Reviews: %d, lapses: %d.":                                                             "Wiederholungen: %d, Fehlschläge: %d.",
+	"It has reached the leech threshold of %d lapses; consider rewording or splitting it.": "Sie hat die Lästig-Schwelle von %d Fehlschlägen erreicht; formuliere sie um oder teile sie auf.",
+	"It becomes a leech after %d more lapse(s).":                                           "Nach %d weiteren Fehlschlag/Fehlschlägen wird sie als lästig markiert.",
+	"Again":                                  "Nochmal",
+	"Hard":                                   "Schwer",
+	"Good":                                   "Gut",
+	"Easy":                                   "Einfach",
+	"graduates to review, next shown in %s":  "wechselt in die Wiederholung, nächste Anzeige in %s",
+	"shown again in %s":                      "erneute Anzeige in %s",
+	"%s (back to the first learning step)":   "%s (zurück zum ersten Lernschritt)",
+	"%s (back to the first relearning step)": "%s (zurück zum ersten Neulernschritt)",
+	"returns to review, next shown in %s":    "kehrt in die Wiederholung zurück, nächste Anzeige in %s",
+	"counts as a lapse: ease drops to %.0f%%, %s, then the interval restarts at %s": "zählt als Fehlschlag: Leichtigkeit sinkt auf %.0f%%, %s, danach beginnt das Intervall bei %s",
+	"next shown in %s, ease drops to %.0f%%":                                        "nächste Anzeige in %s, Leichtigkeit sinkt auf %.0f%%",
+	"next shown in %s, ease unchanged":                                              "nächste Anzeige in %s, Leichtigkeit unverändert",
+	"next shown in %s, ease rises to %.0f%%":                                        "nächste Anzeige in %s, Leichtigkeit steigt auf %.0f%%",
+	"Cards not found: %s":                                                           "Karten nicht gefunden: %s",
+	"Due: %s":                                                                       "Fällig: %s",
+	"Due: %s (in %d day(s))":                                                        "Fällig: %s (in %d Tag(en))",
+	"Interval: %d day(s), ease: %.0f%%":                                             "Intervall: %d Tag(e), Leichtigkeit: %.0f%%",
+	"New card position: %d":                                                         "Position der neuen Karte: %d",
+	"Note: %d, note type: %s":                                                       "Notiz: %d, Notiztyp: %s",
+	"Queue: %s":                                                                     "Warteschlange: %s",
+	"Reps: %d, lapses: %d":                                                          "Wiederholungen: %d, Fehler: %d",
+	"buried":                                                                        "zurückgestellt",
+	"card_ids is required":                                                          "card_ids ist erforderlich",
+	"preview":                                                                       "Vorschau",
+	"suspended":                                                                     "ausgesetzt",
+
+	// Output
+	"Failed to encode JSON: %v": "JSON konnte nicht kodiert werden: %v",
+
+	// Deck options
+	"preset is required":                          "preset ist erforderlich",
+	"decks is required":                           "decks ist erforderlich",
+	"Failed to get deck options: %v":              "Stapeloptionen konnten nicht abgerufen werden: %v",
+	"Deck not found: %s":                          "Stapel nicht gefunden: %s",
+	"Preset not found: %s. Available presets: %s": "Voreinstellung nicht gefunden: %s. Verfügbare Voreinstellungen: %s",
+	"Failed to apply preset: %v":                  "Voreinstellung konnte nicht angewendet werden: %v",
+	"Applied preset \"%s\" to %d deck(s)":         "Voreinstellung „%s“ auf %d Stapel angewendet",
+	"%s (already using this preset)":              "%s (verwendete diese Voreinstellung bereits)",
+	"%s (was: %s)":                                "%s (vorher: %s)",
+	"%s has temporary limits for today. Run restore_deck_limits first so the change isn't lost at the end of the day.": "%s hat für heute vorübergehende Limits. Führe zuerst restore_deck_limits aus, damit die Änderung am Ende des Tages nicht verloren geht.",
+	"%s must be a whole number of at least %d": "%s muss eine ganze Zahl von mindestens %d sein",
+	"Easy interval":                      "Einfach-Intervall",
+	"Easy interval: %s":                  "Einfach-Intervall: %s",
+	"Failed to update deck options: %v":  "Stapeloptionen konnten nicht aktualisiert werden: %v",
+	"Graduating interval":                "Abschlussintervall",
+	"Graduating interval: %s":            "Abschlussintervall: %s",
+	"Invalid %s: %v":                     "Ungültige %s: %v",
+	"Learning steps":                     "Lernschritte",
+	"Learning steps: %s":                 "Lernschritte: %s",
+	"Leech threshold":                    "Lästlingsschwelle",
+	"Leech threshold: %d lapses":         "Lästlingsschwelle: %d Fehlschläge",
+	"Maximum interval":                   "Maximales Intervall",
+	"Maximum interval: %s":               "Maximales Intervall: %s",
+	"Maximum reviews/day":                "Maximale Wiederholungen/Tag",
+	"Maximum reviews/day: %d":            "Maximale Wiederholungen/Tag: %d",
+	"New cards/day":                      "Neue Karten/Tag",
+	"New cards/day: %d":                  "Neue Karten/Tag: %d",
+	"Options of %s (preset \"%s\")":      "Optionen von %s (Voreinstellung „%s“)",
+	"Pass at least one option to change": "Gib mindestens eine zu ändernde Option an",
+	"Relearning steps":                   "Neulernschritte",
+	"Relearning steps: %s":               "Neulernschritte: %s",
+	"The change also applies to decks sharing this preset: %s":                 "Die Änderung gilt auch für Stapel mit dieser Voreinstellung: %s",
+	"The options already had these values.":                                    "Die Optionen hatten bereits diese Werte.",
+	"This preset is shared with: %s":                                           "Diese Voreinstellung wird geteilt mit: %s",
+	"Updated preset \"%s\" of %s":                                              "Voreinstellung „%s“ von %s aktualisiert",
+	"easy_interval (%.0f) must not be shorter than graduating_interval (%.0f)": "easy_interval (%.0f) darf nicht kürzer als graduating_interval (%.0f) sein",
+	"%d deck(s) now use the Default preset: %s":                                "%d Stapel verwenden jetzt die Voreinstellung Default: %s",
+	"A preset named %s already exists (ID: %d)":                                "Eine Voreinstellung namens %s existiert bereits (ID: %d)",
+	"Created preset \"%s\" (ID: %d) as a copy of \"%s\"":                       "Voreinstellung „%s“ (ID: %d) als Kopie von „%s“ erstellt",
+	"Created preset \"%s\" (ID: %d) as a copy of preset %d":                    "Voreinstellung „%s“ (ID: %d) als Kopie der Voreinstellung %d erstellt",
+	"Created preset %d, but failed to apply it: %v":                            "Voreinstellung %d wurde erstellt, konnte aber nicht angewendet werden: %v",
+	"Failed to create preset: %v":                                              "Voreinstellung konnte nicht erstellt werden: %v",
+	"Failed to remove preset: %v":                                              "Voreinstellung konnte nicht entfernt werden: %v",
+	"No deck uses it yet. Apply it with apply_deck_preset using its ID.":       "Noch verwendet kein Stapel sie. Wende sie mit apply_deck_preset über ihre ID an.",
+	"Removed preset \"%s\"":                                                    "Voreinstellung „%s“ entfernt",
+	"The Default preset can't be removed":                                      "Die Voreinstellung Default kann nicht entfernt werden",
+
+	// Deck limits
+	"new_cards or reviews must be a positive number": "new_cards oder reviews muss eine positive Zahl sein",
+	"Failed to extend limits: %v":                    "Limits konnten nicht erweitert werden: %v",
+	"Extended today's limits for %s":                 "Heutige Limits für %s erweitert",
+	"New cards: %d → %d":                             "Neue Karten: %d → %d",
+	"Reviews: %d → %d":                               "Wiederholungen: %d → %d",
+	"These limits apply today only: the deck uses a temporary copy of its \"%s\" preset, and the original preset is restored when the Anki day ends.": "Diese Limits gelten nur heute: Der Stapel verwendet eine temporäre Kopie seiner Voreinstellung „%s“, und die ursprüngliche Voreinstellung wird am Ende des Anki-Tages wiederhergestellt.",
+	"review_limit must be a number of at least 0":                    "review_limit muss eine Zahl von mindestens 0 sein",
+	"Failed to change review limit: %v":                              "Wiederholungslimit konnte nicht geändert werden: %v",
+	"Changed today's review limit for %s":                            "Heutiges Wiederholungslimit für %s geändert",
+	"Use restore_deck_limits to restore it earlier.":                 "Mit restore_deck_limits lässt sie sich früher wiederherstellen.",
+	"Failed to restore limits: %v":                                   "Limits konnten nicht wiederhergestellt werden: %v",
+	"No temporary deck limits to restore":                            "Keine temporären Stapellimits zum Wiederherstellen",
+	"Restored limits for %d deck(s)":                                 "Limits für %d Stapel wiederhergestellt",
+	"%s: back on preset \"%s\"":                                      "%s: wieder auf Voreinstellung „%s“",
+	"Some decks could not be restored and will be retried later: %v": "Einige Stapel konnten nicht wiederhergestellt werden; es wird später erneut versucht: %v",
+
+	// Deck routing
+	"No routing rules configured. Add rules to the config file: %s":  "Keine Routing-Regeln konfiguriert. Füge Regeln in der Konfigurationsdatei hinzu: %s",
+	"Failed to move cards to %s: %v":                                 "Karten konnten nicht nach %s verschoben werden: %v",
+	"All cards are already in the decks chosen by the routing rules": "Alle Karten befinden sich bereits in den von den Routing-Regeln gewählten Stapeln",
+	"Would move %d card(s)":                                          "%d Karte(n) würden verschoben",
+	"Moved %d card(s)":                                               "%d Karte(n) verschoben",
+	"tag %s → %s: %d card(s)":                                        "Schlagwort %s → %s: %d Karte(n)",
+	"Routed to deck %s by the tag rule %s":                           "In den Stapel %s einsortiert (Schlagwortregel %s)",
+
+	// Note links
+	"note_id is required": "note_id ist erforderlich",
+	"related_note_ids must contain at least one other note": "related_note_ids muss mindestens eine andere Notiz enthalten",
+	"Note not found: %d": "Notiz nicht gefunden: %d",
+	"Note %d (note type %s) has no %s field. Add a field named %s to this note type in Anki first.": "Notiz %d (Notiztyp %s) hat kein Feld %s. Füge diesem Notiztyp in Anki zuerst ein Feld namens %s hinzu.",
+	"Failed to update note %d: %v":                         "Notiz %d konnte nicht aktualisiert werden: %v",
+	"Linked note %d with %d note(s); %d note(s) changed":   "Notiz %d mit %d Notiz(en) verknüpft; %d Notiz(en) geändert",
+	"Unlinked note %d from %d note(s); %d note(s) changed": "Verknüpfung von Notiz %d mit %d Notiz(en) entfernt; %d Notiz(en) geändert",
+	"Note %d has no related notes":                         "Notiz %d hat keine verwandten Notizen",
+	"Notes related to %d (%d)":                             "Mit %d verwandte Notizen (%d)",
+	"%d: note no longer exists":                            "%d: Notiz existiert nicht mehr",
+	"%d [%s]: %s (tags: %s)":                               "%d [%s]: %s (Schlagwörter: %s)",
+	"%d [%s]: %s":                                          "%d [%s]: %s",
+
+	// Cloze deletions
+	"text is required":                                      "text ist erforderlich",
+	"Failed to number clozes: %v":                           "Fehler beim Nummerieren der Lückentexte: %v",
+	"Cloze text (%d card(s))":                               "Lückentext (%d Karte(n))",
+	"Cloze text":                                            "Lückentext",
+	"Created cloze note (ID: %d) with %d card(s)":           "Lückentext-Notiz erstellt (ID: %d) mit %d Karte(n)",
+	"Key terms: %s":                                         "Schlüsselbegriffe: %s",
+	"No key terms found; pass the words to hide as targets": "Keine Schlüsselbegriffe gefunden; gib die zu verbergenden Wörter in targets an",
+	"Not found in the text: %s":                             "Nicht im Text gefunden: %s",
+	"Note type %s has no field for back_extra":              "Der Notiztyp %s hat kein Feld für back_extra",
+	"Note type %s is not a cloze note type":                 "Der Notiztyp %s ist kein Lückentext-Notiztyp",
+
+	// Note types
+	"model is required":    "model ist erforderlich",
+	"old_name is required": "old_name ist erforderlich",
+	"new_name is required": "new_name ist erforderlich",
+	"rename_model_field was not run. Renaming a field forces a full upload on the next sync. Ask the user to confirm, then call again with confirm=true.": "rename_model_field wurde nicht ausgeführt. Das Umbenennen eines Feldes erzwingt beim nächsten Synchronisieren ein vollständiges Hochladen. Bitte den Benutzer um Bestätigung und rufe das Werkzeug erneut mit confirm=true auf.",
+	"Failed to get note types: %v":                                 "Fehler beim Abrufen der Notiztypen: %v",
+	"Note type not found: %s":                                      "Notiztyp nicht gefunden: %s",
+	"Failed to get fields: %v":                                     "Fehler beim Abrufen der Felder: %v",
+	"Note type %s has no field %s. Fields: %s":                     "Der Notiztyp %s hat kein Feld %s. Felder: %s",
+	"Note type %s already has a field named %s":                    "Der Notiztyp %s hat bereits ein Feld namens %s",
+	"Failed to find notes: %v":                                     "Fehler beim Suchen von Notizen: %v",
+	"Failed to rename field: %v":                                   "Fehler beim Umbenennen des Feldes: %v",
+	"Renamed field %s to %s in note type %s":                       "Feld %s in %s umbenannt (Notiztyp %s)",
+	"Notes checked: %d":                                            "Geprüfte Notizen: %d",
+	"Notes with content preserved: %d (%d non-empty)":              "Notizen mit erhaltenem Inhalt: %d (%d nicht leer)",
+	"Notes whose content did not carry over: %d (%s)":              "Notizen, deren Inhalt nicht übernommen wurde: %d (%s)",
+	"The next sync will require a full upload to AnkiWeb.":         "Die nächste Synchronisierung erfordert ein vollständiges Hochladen zu AnkiWeb.",
+	"%s of template %s must be a string":                           "%s der Vorlage %s muss ein Text sein",
+	"%s of template %s refers to unknown field(s): %s. Fields: %s": "%s der Vorlage %s verweist auf unbekannte(s) Feld(er): %s. Felder: %s",
+	"%s: back template":                                            "%s: Vorlage der Rückseite",
+	"%s: front template":                                           "%s: Vorlage der Vorderseite",
+	"Failed to get templates: %v":                                  "Vorlagen konnten nicht abgerufen werden: %v",
+	"Failed to update templates: %v":                               "Vorlagen konnten nicht aktualisiert werden: %v",
+	"Note type %s has no template %s. Templates: %s":               "Der Notiztyp %s hat keine Vorlage %s. Vorlagen: %s",
+	"Template %s needs Front or Back":                              "Vorlage %s braucht Front oder Back",
+	"Updated templates of %s: %s":                                  "Vorlagen von %s aktualisiert: %s",
+	"templates is required":                                        "templates ist erforderlich",
+	"Failed to get styling: %v":                                    "Stil konnte nicht abgerufen werden: %v",
+	"Failed to update styling: %v":                                 "Stil konnte nicht aktualisiert werden: %v",
+	"Styling of %s":                                                "Stil von %s",
+	"The CSS has unbalanced braces":                                "Das CSS hat unausgeglichene geschweifte Klammern",
+	"Updated styling of %s":                                        "Stil von %s aktualisiert",
+	"css is required":                                              "css ist erforderlich",
+
+	// Sync
+	"Failed to sync: %v": "Fehler beim Synchronisieren: %v",
+	"Sync with AnkiWeb started. Anki syncs in the background and shows in its window when it is done or if it failed.": "Synchronisierung mit AnkiWeb gestartet. Anki synchronisiert im Hintergrund und zeigt in seinem Fenster an, wann sie abgeschlossen ist oder ob sie fehlgeschlagen ist.",
+	"Warning: could not record the sync time: %v": "Warnung: Der Synchronisierungszeitpunkt konnte nicht gespeichert werden: %v",
+	"Failed to read sync state: %v":               "Fehler beim Lesen des Synchronisierungsstatus: %v",
+	"Sync status":                                 "Synchronisierungsstatus",
+	"Last sync started through this server: none recorded; showing changes from the last 24 hours": "Letzte über diesen Server gestartete Synchronisierung: keine gespeichert; Änderungen der letzten 24 Stunden werden angezeigt",
+	"Last sync started through this server: %s":                                                    "Letzte über diesen Server gestartete Synchronisierung: %s",
+	"Notes added or edited: %d":                                                                    "Hinzugefügte oder bearbeitete Notizen: %d",
+	"Cards reviewed: %d":                                                                           "Wiederholte Karten: %d",
+	"There are local changes; call sync to upload them to AnkiWeb.":                                "Es gibt lokale Änderungen; rufe sync auf, um sie zu AnkiWeb hochzuladen.",
+	"No local changes found.":                                                                      "Keine lokalen Änderungen gefunden.",
+	"Failed to find changes: %v":                                                                   "Änderungen konnten nicht gesucht werden: %v",
+
+	// Notes
+	"Nothing to update: pass fields, add_tags or remove_tags": "Nichts zu aktualisieren: gib fields, add_tags oder remove_tags an",
+	"Invalid tag %q: tags can't be empty or contain spaces":   "Ungültiges Schlagwort %q: Schlagwörter dürfen nicht leer sein oder Leerzeichen enthalten",
+	"Failed to add tags: %v":                                  "Fehler beim Hinzufügen der Schlagwörter: %v",
+	"Failed to remove tags: %v":                               "Fehler beim Entfernen der Schlagwörter: %v",
+	"Updated note %d":                                         "Notiz %d aktualisiert",
+	"Fields changed: %s":                                      "Geänderte Felder: %s",
+	"Fields already had these values":                         "Die Felder hatten bereits diese Werte",
+	"Tags added: %s":                                          "Hinzugefügte Schlagwörter: %s",
+	"Tags removed: %s":                                        "Entfernte Schlagwörter: %s",
+	"Pass either note_ids or query, not both":                 "Gib entweder note_ids oder query an, nicht beides",
+	"note_ids or query is required":                           "note_ids oder query ist erforderlich",
+	"No notes found to delete":                                "Keine Notizen zum Löschen gefunden",
+	"Failed to delete notes: %v":                              "Fehler beim Löschen der Notizen: %v",
+	"Deleted %d note(s) with %d card(s)":                      "%d Notiz(en) mit %d Karte(n) gelöscht",
+	"Would delete %d note(s) with %d card(s)":                 "Würde %d Notiz(en) mit %d Karte(n) löschen",
+	"... and %d more":                                         "... und %d weitere",
+	"Nothing was deleted. Show this list to the user and call again with confirm=true once they agree.": "Es wurde nichts gelöscht. Zeige dem Benutzer diese Liste und rufe erneut mit confirm=true auf, sobald er zustimmt.",
+
+	// Study
+	"No cards are due":            "Keine Karten fällig",
+	"Due cards: showing %d of %d": "Fällige Karten: %d von %d angezeigt",
+	"%d [%s, %s]: %s → %s (interval: %d day(s), reps: %d, lapses: %d)": "%d [%s, %s]: %s → %s (Intervall: %d Tag(e), Wiederholungen: %d, Fehler: %d)",
+	"new":                                "neu",
+	"learning":                           "im Lernen",
+	"relearning":                         "im Wiederlernen",
+	"review":                             "Wiederholung",
+	"Failed to start review: %v":         "Fehler beim Starten der Wiederholung: %v",
+	"Started reviewing %s":               "Wiederholung von %s gestartet",
+	"Failed to get the current card: %v": "Fehler beim Abrufen der aktuellen Karte: %v",
+	"Failed to show the answer: %v":      "Fehler beim Anzeigen der Antwort: %v",
+	"No card is being reviewed. Call start_review first.":                          "Es wird keine Karte wiederholt. Rufe zuerst start_review auf.",
+	"ease must be 1 (Again), 2 (Hard), 3 (Good) or 4 (Easy)":                       "ease muss 1 (Nochmal), 2 (Schwer), 3 (Gut) oder 4 (Einfach) sein",
+	"Failed to answer card: %v":                                                    "Fehler beim Beantworten der Karte: %v",
+	"Anki did not accept the answer %d for this card":                              "Anki hat die Antwort %d für diese Karte nicht akzeptiert",
+	"Answered card %d with %s":                                                     "Karte %d mit %s beantwortet",
+	"No card is being reviewed: the session is finished or no review was started.": "Es wird keine Karte wiederholt: Die Sitzung ist beendet oder es wurde keine Wiederholung gestartet.",
+	"Card %d (%s)": "Karte %d (%s)",
+	"Question: %s": "Frage: %s",
+	"Answer: %s":   "Antwort: %s",
+	"Buttons: %s":  "Tasten: %s",
+
+	// Tags
+	"Added %s to %d note(s)":                "%s zu %d Notiz(en) hinzugefügt",
+	"Failed to get tags: %v":                "Tags konnten nicht abgerufen werden: %v",
+	"Failed to replace tag: %v":             "Tag konnte nicht ersetzt werden: %v",
+	"No notes found":                        "Keine Notizen gefunden",
+	"Removed %s from %d note(s)":            "%s aus %d Notiz(en) entfernt",
+	"Replaced tag %s with %s on %d note(s)": "Tag %s durch %s ersetzt (%d Notiz(en))",
+	"Tags (%d)":                             "Tags (%d)",
+	"tags is required":                      "tags ist erforderlich",
+
+	// Import
+	"#%d is a duplicate":             "#%d ist ein Duplikat",
+	"Created %d of %d note(s)":       "%d von %d Notiz(en) erstellt",
+	"Duplicates skipped: %d":         "Übersprungene Duplikate: %d",
+	"Errors: %d":                     "Fehler: %d",
+	"Failed to parse CSV: %v":        "CSV konnte nicht gelesen werden: %v",
+	"No rows found in input":         "Keine Zeilen in der Eingabe gefunden",
+	"The first field (%s) is empty":  "Das erste Feld (%s) ist leer",
+	"Unknown column %s. Columns: %s": "Unbekannte Spalte %s. Spalten: %s",
+	"Failed to parse Markdown: %v":   "Markdown konnte nicht gelesen werden: %v",
+	"No cards found in the document": "Keine Karten im Dokument gefunden",
+	"Question without answer: %s":    "Frage ohne Antwort: %s",
+	"Failed to parse %s: %v":         "%s konnte nicht gelesen werden: %v",
+	"Failed to store %s: %v":         "%s konnte nicht gespeichert werden: %v",
+	"Note must be an object":         "Die Notiz muss ein Objekt sein",
+	"notes or path is required":      "notes oder path ist erforderlich",
+	"#%d (%s) can't be added: %s":    "#%d (%s) kann nicht hinzugefügt werden: %s",
+	"#%d (%s) is a duplicate":        "#%d (%s) ist ein Duplikat",
+	"%d of %d note(s) can be added":  "%d von %d Notiz(en) können hinzugefügt werden",
+	"Duplicates: %d":                 "Duplikate: %d",
+	"Failed to check notes: %v":      "Notizen konnten nicht geprüft werden: %v",
+	"notes is required":              "notes ist erforderlich",
+
+	// Media
+	"Failed to decode media file: %v":           "Mediendatei konnte nicht dekodiert werden: %v",
+	"Failed to retrieve media file: %v":         "Mediendatei konnte nicht abgerufen werden: %v",
+	"Failed to save media file: %v":             "Mediendatei konnte nicht gespeichert werden: %v",
+	"Media file not found: %s":                  "Mediendatei nicht gefunden: %s",
+	"Saved %s to %s (%d bytes)":                 "%s unter %s gespeichert (%d Bytes)",
+	"filename is required":                      "filename ist erforderlich",
+	"Failed to list media files: %v":            "Mediendateien konnten nicht aufgelistet werden: %v",
+	"Invalid pattern %q: %v":                    "Ungültiges Muster %q: %v",
+	"Media files matching %s: showing %d of %d": "Mediendateien passend zu %s: %d von %d angezeigt",
+	"No media files match %s":                   "Keine Mediendateien passen zu %s",
+	"%s is still used by %d note(s): %s. Remove it from the notes first, or pass force=true to delete it anyway.": "%s wird noch von %d Notiz(en) verwendet: %s. Entferne es zuerst aus den Notizen oder übergib force=true, um es trotzdem zu löschen.",
+	"Deleted media file %s":                                       "Mediendatei %s gelöscht",
+	"Failed to delete media file: %v":                             "Mediendatei konnte nicht gelöscht werden: %v",
+	"Still used by %d note(s), which will show it as missing: %s": "Wird noch von %d Notiz(en) verwendet, die es als fehlend anzeigen werden: %s",
+	"delete_media_file was not run. Ask the user to confirm deleting %s, then call again with confirm=true.": "delete_media_file wurde nicht ausgeführt. Bitte den Benutzer, das Löschen von %s zu bestätigen, und rufe das Werkzeug erneut mit confirm=true auf.",
+	"Failed to download %s: %v":         "%s konnte nicht heruntergeladen werden: %v",
+	"Failed to verify stored media: %v": "Gespeicherte Mediendatei konnte nicht überprüft werden: %v",
+	"Pass one of path, url or data":     "Gib genau eines von path, url oder data an",
+	"Reference: %s":                     "Verweis: %s",
+	"filename is required with data":    "filename ist mit data erforderlich",
+
+	// Text-to-speech
+	"Added to field %s of note %d":         "Zum Feld %s der Notiz %d hinzugefügt",
+	"Failed to generate speech: %v":        "Sprachausgabe konnte nicht erzeugt werden: %v",
+	"Field %s of note %d already plays it": "Feld %s der Notiz %d spielt es bereits ab",
+	"Generated %s (%d bytes)":              "%s erzeugt (%d Bytes)",
+	"Note %d has no field %s. Fields: %s":  "Die Notiz %d hat kein Feld %s. Felder: %s",
+	"Text-to-speech is not available: %v. Configure the tts section of the config file.": "Sprachausgabe ist nicht verfügbar: %v. Richte den Abschnitt tts der Konfigurationsdatei ein.",
+	"language is required":              "language ist erforderlich",
+	"text is longer than %d characters": "text ist länger als %d Zeichen",
+
+	// Leeches
+	"(suspended)": "(ausgesetzt)",
+	"Answering takes %.0fs on average. Add a hint, an example or a mnemonic to the question.":           "Die Antwort dauert im Schnitt %.0fs. Ergänze die Frage um einen Hinweis, ein Beispiel oder eine Eselsbrücke.",
+	"Invalid action %q: use none, suspend or reset":                                                     "Ungültige Aktion %q: verwende none, suspend oder reset",
+	"Its ease is at the minimum, so it keeps coming back. Reset it after rewording.":                    "Ihre Leichtigkeit ist auf dem Minimum, deshalb kommt sie immer wieder. Setze sie nach dem Umformulieren zurück.",
+	"Lapses: %d, reviews: %d, ease: %d%%, %.0fs per answer":                                             "Fehlschläge: %d, Wiederholungen: %d, Leichtigkeit: %d%%, %.0fs pro Antwort",
+	"Leeches: showing %d of %d":                                                                         "Lästlinge: %d von %d angezeigt",
+	"No leeches found":                                                                                  "Keine Lästlinge gefunden",
+	"Recent answers: %s":                                                                                "Letzte Antworten: %s",
+	"Reset %d card(s) to new and removed their leech tag":                                               "%d Karte(n) auf neu zurückgesetzt und ihr leech-Tag entfernt",
+	"Reset %d card(s), but failed to remove the leech tag: %v":                                          "%d Karte(n) zurückgesetzt, aber das leech-Tag konnte nicht entfernt werden: %v",
+	"Reword the question so it has one unambiguous answer, or add context such as an example sentence.": "Formuliere die Frage so um, dass sie eine eindeutige Antwort hat, oder ergänze Kontext wie einen Beispielsatz.",
+	"Suggestion: %s": "Vorschlag: %s",
+	"The answer has %d words. Split it into smaller cards or turn it into a cloze deletion.": "Die Antwort hat %d Wörter. Teile sie in kleinere Karten auf oder mache daraus einen Lückentext.",
+
+	// Dry run
+	"Dry run: this call would make %d change(s)": "Probelauf: dieser Aufruf würde %d Änderung(en) vornehmen",
+	"Dry run: nothing was changed. The call stopped with this error after planning %d change(s), so there is no complete list of its changes.": "Probelauf: es wurde nichts geändert. Der Aufruf brach nach %d geplanten Änderung(en) mit diesem Fehler ab, daher gibt es keine vollständige Liste seiner Änderungen.",
+	"Nothing was changed. Call the tool again without dry_run to make these changes.":                                                          "Es wurde nichts geändert. Rufe das Tool ohne dry_run erneut auf, um diese Änderungen vorzunehmen.",
+	"Nothing was changed: the server runs in dry-run mode (ANKI_MCP_DRY_RUN).":                                                                 "Es wurde nichts geändert: der Server läuft im Probelauf-Modus (ANKI_MCP_DRY_RUN).",
+	"Tags: %s":                                        "Tags: %s",
+	"Add a %s note to %s: %s":                         "Eine %s-Notiz zu %s hinzufügen: %s",
+	"Update note %d: %s":                              "Notiz %d aktualisieren: %s",
+	"Delete %d note(s) with their cards: %s":          "%d Notiz(en) mit ihren Karten löschen: %s",
+	"Add tags %s to %d note(s)":                       "Tags %s zu %d Notiz(en) hinzufügen",
+	"Remove tags %s from %d note(s)":                  "Tags %s von %d Notiz(en) entfernen",
+	"Replace tag %s with %s on %d note(s)":            "Tag %s durch %s in %d Notiz(en) ersetzen",
+	"Remove unused tags from the tag list":            "Unbenutzte Tags aus der Tag-Liste entfernen",
+	"Create deck %s":                                  "Stapel %s erstellen",
+	"Delete deck %s with its cards":                   "Stapel %s mit seinen Karten löschen",
+	"Move %d card(s) to deck %s":                      "%d Karte(n) in den Stapel %s verschieben",
+	"Suspend %d card(s)":                              "%d Karte(n) aussetzen",
+	"Unsuspend %d card(s)":                            "%d Karte(n) wieder aktivieren",
+	"Make %d card(s) new again":                       "%d Karte(n) auf neu zurücksetzen",
+	"Put %d card(s) back into relearning":             "%d Karte(n) zurück ins Wiederlernen setzen",
+	"Make %d card(s) due in %s day(s)":                "%d Karte(n) in %s Tag(en) fällig machen",
+	"Store media file %s (%d bytes)":                  "Mediendatei %s speichern (%d Bytes)",
+	"Delete media file %s":                            "Mediendatei %s löschen",
+	"Create note type %s with fields %s":              "Notiztyp %s mit den Feldern %s erstellen",
+	"Update card templates %s of note type %s":        "Kartenvorlagen %s des Notiztyps %s aktualisieren",
+	"Replace the styling of note type %s":             "Das Styling des Notiztyps %s ersetzen",
+	"Rename field %s of note type %s to %s":           "Feld %s des Notiztyps %s in %s umbenennen",
+	"Save options preset %s":                          "Optionsgruppe %s speichern",
+	"Switch deck %s to options preset %d":             "Stapel %s auf die Optionsgruppe %d umstellen",
+	"Create options preset %s as a copy of preset %d": "Optionsgruppe %s als Kopie der Gruppe %d erstellen",
+	"Remove options preset %d":                        "Optionsgruppe %d entfernen",
+	"Add %d review(s) to the review history":          "%d Wiederholung(en) zum Wiederholungsverlauf hinzufügen",
+	"Export deck %s to %s":                            "Stapel %s nach %s exportieren",
+	"Run Check Database":                              "Datenbank überprüfen ausführen",
+	"Sync the collection with AnkiWeb":                "Die Sammlung mit AnkiWeb synchronisieren",
+	"Answer the current card with ease %d":            "Die aktuelle Karte mit Leichtigkeit %d beantworten",
+	"Switch deck %s to the new options preset":        "Stapel %s auf die neue Optionsgruppe umstellen",
+
+	// Search
+	"%d [%s, %s]: %s (tags: %s)":                               "%d [%s, %s]: %s (Tags: %s)",
+	"%d [%s, %s]: %s":                                          "%d [%s, %s]: %s",
+	"Failed to search: %v":                                     "Suche fehlgeschlagen: %v",
+	"No notes match %s":                                        "Keine Notizen passen zu %s",
+	"query is required":                                        "query ist erforderlich",
+	"Notes matching %s: %d-%d of %d":                           "Notizen passend zu %s: %d-%d von %d",
+	"No more notes: %s matches %d note(s)":                     "Keine weiteren Notizen: %s passt zu %d Notiz(en)",
+	"More notes follow: pass cursor %s for the next page":      "Weitere Notizen folgen: übergib cursor %s für die nächste Seite",
+	"Pass either offset or cursor, not both":                   "Übergib entweder offset oder cursor, nicht beides",
+	"Invalid cursor; start again without one":                  "Ungültiger cursor; beginne erneut ohne cursor",
+	"The cursor belongs to the search %s; pass the same query": "Der cursor gehört zur Suche %s; übergib dieselbe Suchanfrage",
+	"The cursor belongs to a differently sorted search; pass the same sort_by and order": "Der cursor gehört zu einer anders sortierten Suche; übergib dieselben Werte für sort_by und order",
+	"order must be asc or desc":                          "order muss asc oder desc sein",
+	"order needs sort_by":                                "order erfordert sort_by",
+	"sort_by must be created, modified, due or interval": "sort_by muss created, modified, due oder interval sein",
+
+	// Instances
+	"AnkiConnect instances (%d)":                 "AnkiConnect-Instanzen (%d)",
+	"%s (%s): available, AnkiConnect version %d": "%s (%s): verfügbar, AnkiConnect-Version %d",
+	"%s (%s): not available: %s":                 "%s (%s): nicht verfügbar: %s",
+	"Unknown instance %s; the instances are %s":  "Unbekannte Instanz %s; die Instanzen sind %s",
+
+	// Cancellation
+	"Cancelled after changing %d of %d note(s)":                     "Abgebrochen, nachdem %d von %d Notiz(en) geändert wurden",
+	"Not sent, because the request was cancelled: %d":               "Nicht gesendet, weil die Anfrage abgebrochen wurde: %d",
+	"Cancelled after storing %d media file(s); no card was created": "Abgebrochen, nachdem %d Mediendatei(en) gespeichert wurden; es wurde keine Karte erstellt",
+
+	// Rate limit
+	"Throttled: this call waited %s for AnkiConnect, which takes only so many requests at once": "Gedrosselt: Dieser Aufruf hat %s auf AnkiConnect gewartet, das nur eine begrenzte Zahl von Anfragen gleichzeitig annimmt",
+
+	// Diagnostics
+	"Hint: Start Anki and keep it open. If it is running, check that the AnkiConnect add-on (code 2055492159) is installed and enabled, and that ANKI_CONNECT_URL matches its address.":      "Hinweis: Starte Anki und lass es geöffnet. Läuft es bereits, prüfe, ob das Add-on AnkiConnect (Code 2055492159) installiert und aktiviert ist und ANKI_CONNECT_URL zu seiner Adresse passt.",
+	"Hint: Anki is running but busy, e.g. with an open dialog, a sync or a large collection. Close open dialogs in Anki, or raise ANKI_CONNECT_TIMEOUT.":                                     "Hinweis: Anki läuft, ist aber beschäftigt, z. B. mit einem offenen Dialog, einer Synchronisierung oder einer großen Sammlung. Schließe offene Dialoge in Anki oder erhöhe ANKI_CONNECT_TIMEOUT.",
+	"Hint: Another program answers at the AnkiConnect address. Install the AnkiConnect add-on (code 2055492159) via Tools > Add-ons > Get Add-ons, restart Anki and check ANKI_CONNECT_URL.": "Hinweis: Unter der Adresse von AnkiConnect antwortet ein anderes Programm. Installiere das Add-on AnkiConnect (Code 2055492159) über Extras > Add-ons > Add-ons herunterladen, starte Anki neu und prüfe ANKI_CONNECT_URL.",
+	"Hint: The installed AnkiConnect doesn't support this request. Update it via Tools > Add-ons > Check for Updates and restart Anki.":                                                      "Hinweis: Das installierte AnkiConnect unterstützt diese Anfrage nicht. Aktualisiere es über Extras > Add-ons > Nach Updates suchen und starte Anki neu.",
+	"Hint: The deck doesn't exist. Use get_deck_tree to see the deck names, or create_deck to create it.":                                                                                    "Hinweis: Der Stapel existiert nicht. Mit get_deck_tree siehst du die Stapelnamen, mit create_deck legst du ihn an.",
+	"Hint: The note type or one of its fields doesn't exist, or the first field is empty. Check the names in Anki under Tools > Manage Note Types > Fields; they are case-sensitive.":        "Hinweis: Der Notiztyp oder eines seiner Felder existiert nicht, oder das erste Feld ist leer. Prüfe die Namen in Anki unter Extras > Notiztypen verwalten > Felder; die Groß- und Kleinschreibung zählt.",
+
+	// Health check
+	"AnkiConnect at %s did not answer: %v":                                                      "AnkiConnect unter %s hat nicht geantwortet: %v",
+	"AnkiConnect supports API version %d, but this server needs version %d; update AnkiConnect": "AnkiConnect unterstützt API-Version %d, dieser Server braucht aber Version %d; aktualisiere AnkiConnect",
+	"AnkiConnect: %s, API version %d":                                                           "AnkiConnect: %s, API-Version %d",
+	"Could not get the active profile: %s":                                                      "Das aktive Profil konnte nicht abgerufen werden: %s",
+	"Could not get the decks: %s":                                                               "Die Stapel konnten nicht abgerufen werden: %s",
+	"Could not get the media folder: %s":                                                        "Der Medienordner konnte nicht abgerufen werden: %s",
+	"Could not get the note types: %s":                                                          "Die Notiztypen konnten nicht abgerufen werden: %s",
+	"Decks: %d":                                                                                 "Stapel: %d",
+	"Failed to check the collection: %v":                                                        "Die Sammlung konnte nicht geprüft werden: %v",
+	"Health check: %d problem(s)":                                                               "Zustandsprüfung: %d Problem(e)",
+	"Health check: OK":                                                                          "Zustandsprüfung: OK",
+	"Latency: %s":                                                                               "Latenz: %s",
+	"Media folder: %s":                                                                          "Medienordner: %s",
+	"Note types: %d":                                                                            "Notiztypen: %d",
+	"Problem: %s":                                                                               "Problem: %s",
+	"Profile: %s":                                                                               "Profil: %s",
+
+	// GUI
+	"Failed to open the card browser: %v":                                                                           "Der Kartenbrowser konnte nicht geöffnet werden: %v",
+	"Opened Anki's card browser with %d card(s) for: %s":                                                            "Anki-Kartenbrowser mit %d Karte(n) geöffnet für: %s",
+	"Opened Anki's card browser, but no cards match: %s":                                                            "Anki-Kartenbrowser geöffnet, aber keine Karte passt zu: %s",
+	"Pass exactly one of card_ids, note_ids or query":                                                               "Gib genau eines von card_ids, note_ids oder query an",
+	"Failed to open the Add dialog: %v":                                                                             "Der Dialog Hinzufügen konnte nicht geöffnet werden: %v",
+	"Opened Anki's Add dialog with a %s card for deck %s. The card is only added once the user clicks Add in Anki.": "Der Dialog Hinzufügen von Anki wurde mit einer %s-Karte für den Stapel %s geöffnet. Die Karte wird erst hinzugefügt, wenn der Nutzer in Anki auf Hinzufügen klickt.",
+	"front and back or fields are required":                                                                         "front und back oder fields sind erforderlich",
+
+	// Undo
+	"Anki has nothing to undo": "Anki hat nichts rückgängig zu machen",
+	"Failed to undo: %v":       "Rückgängig machen fehlgeschlagen: %v",
+	"No change made through this server is left to undo, so the operation undone was made in Anki itself.": "Es ist keine über diesen Server vorgenommene Änderung mehr rückgängig zu machen, der rückgängig gemachte Vorgang wurde also in Anki selbst ausgeführt.",
+	"That change was undone, unless the user changed something in Anki after it.":                          "Diese Änderung wurde rückgängig gemacht, sofern der Nutzer danach nichts in Anki geändert hat.",
+	"The last change made through this server, %s ago, was: %s":                                            "Die letzte Änderung über diesen Server, vor %s, war: %s",
+	"Undid the last operation in Anki.":                                                                    "Der letzte Vorgang in Anki wurde rückgängig gemacht.",
+	"Undo the last operation in Anki":                                                                      "Den letzten Vorgang in Anki rückgängig machen",
+
+	// Change note type
+	"%s and %s are not both cloze or both standard note types, so their cards don't correspond":           "%s und %s sind nicht beide Lückentext- oder beide Standard-Notiztypen, daher entsprechen sich ihre Karten nicht",
+	"%s and %s have no field in common; pass field_map. Fields of %s: %s":                                 "%s und %s haben kein gemeinsames Feld; gib field_map an. Felder von %s: %s",
+	"%s has %d card types but %s only %d; cards of the other card types would be left without a template": "%s hat %d Kartentypen, %s aber nur %d; Karten der übrigen Kartentypen blieben ohne Vorlage",
+	"Change note %d to note type %s: %s":                                                                  "Notiz %d in Notiztyp %s ändern: %s",
+	"Changed %d of %d note(s) from %s to %s":                                                              "%d von %d Notiz(en) von %s zu %s geändert",
+	"Content discarded: %s":                                                                               "Verworfener Inhalt: %s",
+	"Fields: %s":                                                                                          "Felder: %s",
+	"Left empty: %s":                                                                                      "Leer gelassen: %s",
+	"Note %d failed: %v":                                                                                  "Notiz %d fehlgeschlagen: %v",
+	"The content of field(s) %s would be lost. Map them in field_map, or pass discard_unmapped=true if the user agrees to lose it.": "Der Inhalt der Felder %s ginge verloren. Ordne sie in field_map zu oder gib discard_unmapped=true an, wenn der Nutzer damit einverstanden ist.",
+	"The notes already use note type %s":                                                   "Die Notizen verwenden bereits den Notiztyp %s",
+	"The notes use different note types (%s); change the notes of one note type at a time": "Die Notizen verwenden verschiedene Notiztypen (%s); ändere die Notizen jeweils eines Notiztyps",
+	"field_map: %s all map to %s":                                                          "field_map: %s werden alle %s zugeordnet",
+	"field_map: %s must map to the name of a field of %s":                                  "field_map: %s muss einem Feldnamen von %s zugeordnet werden",
+	"field_map: note type %s has no field %s. Fields: %s":                                  "field_map: Notiztyp %s hat kein Feld %s. Felder: %s",
+	"new_model is required":                                                                "new_model ist erforderlich",
+
+	// Find and replace in models
+	"%q was not found in the chosen templates; nothing was changed": "%q wurde in den gewählten Vorlagen nicht gefunden; nichts wurde geändert",
+	"%s back":  "%s Rückseite",
+	"%s front": "%s Vorderseite",
+	"After the replacement, %s of note type %s would refer to unknown field(s): %s. Fields: %s": "Nach dem Ersetzen würde %s des Notiztyps %s auf unbekannte Felder verweisen: %s. Felder: %s",
+	"Failed to replace: %v": "Ersetzen fehlgeschlagen: %v",
+	"Replace %q with %q in the templates and styling of every note type": "%q durch %q ersetzen, in den Vorlagen und dem Stil aller Notiztypen",
+	"Replace %q with %q in the templates and styling of note type %s":    "%q durch %q ersetzen, in den Vorlagen und dem Stil des Notiztyps %s",
+	"Replaced %q with %q in %d note type(s)":                             "%q durch %q ersetzt, in %d Notiztyp(en)",
+	"find and replace are the same":                                      "find und replace sind gleich",
+	"find is required":                                                   "find ist erforderlich",
+	"front, back and css are all false, so there is nothing to search":   "front, back und css sind alle false, es gibt also nichts zu durchsuchen",
+	"replace is required":                                                "replace ist erforderlich",
+	"styling":                                                            "Stil",
+
+	// Deck tree
+	"%s: %d card(s), %d in the deck itself; due today: %d new, %d learning, %d review": "%s: %d Karte(n), %d im Stapel selbst; heute fällig: %d neu, %d im Lernen, %d Wiederholung",
+	"%s: %d card(s); due today: %d new, %d learning, %d review":                        "%s: %d Karte(n); heute fällig: %d neu, %d im Lernen, %d Wiederholung",
+	"Failed to get deck stats: %v":                                                     "Stapelstatistik konnte nicht abgerufen werden: %v",
+
+	// Retention
+	"Again rate, %s: %.1f%% of %d answers":                                        "Anteil „Nochmal“, %s: %.1f%% von %d Antworten",
+	"Interval growth: a passed review multiplied the interval by %.2f on average": "Intervallwachstum: Eine bestandene Wiederholung hat das Intervall im Schnitt mit %.2f multipliziert",
+	"No cards were studied in this period.":                                       "In diesem Zeitraum wurden keine Karten gelernt.",
+	"Retention for %s from %s to %s":                                              "Behaltensquote für %s vom %s bis %s",
+	"True retention: %.1f%% of %d review answers":                                 "Tatsächliche Behaltensquote: %.1f%% von %d Wiederholungsantworten",
+	"True retention: no reviews of graduated cards in this period":                "Tatsächliche Behaltensquote: keine Wiederholungen gelernter Karten in diesem Zeitraum",
+	"learning and relearning":                                                     "Lernen und Neulernen",
+	"mature cards":                                                                "ausgereifte Karten",
+	"tag %s":                                                                      "Schlagwort %s",
+	"young cards (interval under %d days)":                                        "junge Karten (Intervall unter %d Tagen)",
+
+	// Review forecast
+	"Already overdue: %d":                          "Bereits überfällig: %d",
+	"Busiest day: %s with %d review(s)":            "Vollster Tag: %s mit %d Wiederholung(en)",
+	"Review forecast for %s, next %d day(s)":       "Wiederholungsprognose für %s, nächste %d Tag(e)",
+	"Today (%s): %d":                               "Heute (%s): %d",
+	"Total: %d review(s), %.1f per day on average": "Gesamt: %d Wiederholung(en), im Schnitt %.1f pro Tag",
+	"days must be between 1 and %d":                "days muss zwischen 1 und %d liegen",
+
+	// Maturity report
+	"Maturity of %s: %d card(s)": "Reife von %s: %d Karte(n)",
+	"New":                        "Neu",
+	"Learning":                   "Lernen",
+	"Young":                      "Jung",
+	"Mature":                     "Ausgereift",
+	"Suspended":                  "Ausgesetzt",
+	"Young cards have an interval under %d days, mature cards one of %d days or more.": "Junge Karten haben ein Intervall unter %d Tagen, ausgereifte eines von %d Tagen oder mehr.",
+
+	// Markdown export
+	"Exported %d note(s) to %s": "%d Notiz(en) nach %s exportiert",
+
+	// Query validation
+	"A quotation mark is never closed":                                        "Ein Anführungszeichen wird nie geschlossen",
+	"The closing parenthesis at position %d has no opening one":               "Die schließende Klammer an Position %d hat keine öffnende",
+	"%d parenthesis(es) are never closed":                                     "%d Klammer(n) wird/werden nie geschlossen",
+	"Anki doesn't support %s; use %s":                                         "Anki unterstützt %s nicht; verwende %s",
+	"Anki doesn't support %s; put - before a term to exclude it":              "Anki unterstützt %s nicht; setze - vor einen Begriff, um ihn auszuschließen",
+	"%s: search keywords are followed by a colon, not =":                      "%s: auf Suchbegriffe folgt ein Doppelpunkt, kein =",
+	"Unknown state is:%s. Known states: %s":                                   "Unbekannter Zustand is:%s. Bekannte Zustände: %s",
+	"%s: flag: takes a number from 0 (no flag) to 7":                          "%s: flag: erwartet eine Zahl von 0 (keine Markierung) bis 7",
+	"%s: prop: needs a property, a comparison and a value, e.g. prop:ivl>=10": "%s: prop: braucht eine Eigenschaft, einen Vergleich und einen Wert, z. B. prop:ivl>=10",
+	"Unknown property %s. Known properties: %s":                               "Unbekannte Eigenschaft %s. Bekannte Eigenschaften: %s",
+	"%s: %s: takes a number of days, e.g. %s:7":                               "%s: %s: erwartet eine Anzahl von Tagen, z. B. %s:7",
+	"Deck names with spaces must be quoted: %s":                               "Stapelnamen mit Leerzeichen müssen in Anführungszeichen stehen: %s",
+	"No deck named %s; did you mean %s?":                                      "Es gibt keinen Stapel namens %s; meintest du %s?",
+	"No deck named %s":                                                        "Es gibt keinen Stapel namens %s",
+	"No note type named %s; did you mean %s?":                                 "Es gibt keinen Notiztyp namens %s; meintest du %s?",
+	"No note type named %s":                                                   "Es gibt keinen Notiztyp namens %s",
+	"Unknown prefix %s:; did you mean %s:?":                                   "Unbekanntes Präfix %s:; meintest du %s:?",
+	"%s: is neither a search keyword nor a field name, so the term matches nothing; write \\: to search for a colon": "%s: ist weder ein Suchbegriff noch ein Feldname, daher findet der Begriff nichts; schreibe \\:, um nach einem Doppelpunkt zu suchen",
+	"Query: %s":                                 "Suche: %s",
+	"No problems found.":                        "Keine Probleme gefunden.",
+	"Anki rejected the query: %s":               "Anki hat die Suche abgelehnt: %s",
+	"The query matches %d card(s).":             "Die Suche findet %d Karte(n).",
+	"Suggested query: %s":                       "Vorgeschlagene Suche: %s",
+	"Anki rejected the suggested query too: %s": "Anki hat auch die vorgeschlagene Suche abgelehnt: %s",
+	"The suggested query matches %d card(s).":   "Die vorgeschlagene Suche findet %d Karte(n).",
+
+	// Image occlusion
+	"Invalid hide %q: use all or one": "Ungültiges hide %q: verwende all oder one",
+	"No image occlusion note type found. Use Anki 23.10 or later, install the Image Occlusion Enhanced add-on, or pass model_name.": "Kein Bildverdeckungs-Notiztyp gefunden. Verwende Anki 23.10 oder neuer, installiere das Add-on Image Occlusion Enhanced oder gib model_name an.",
+	"Note type %s is not an image occlusion note type":                          "Der Notiztyp %s ist kein Bildverdeckungs-Notiztyp",
+	"Pass either image_path or image_url, not both":                             "Gib entweder image_path oder image_url an, nicht beides",
+	"image_path or image_url is required":                                       "image_path oder image_url ist erforderlich",
+	"Invalid occlusions: %v":                                                    "Ungültige Verdeckungen: %v",
+	"Image Occlusion Enhanced needs a PNG, JPEG or GIF image to draw its masks": "Image Occlusion Enhanced braucht ein PNG-, JPEG- oder GIF-Bild, um seine Masken zu zeichnen",
+	"Failed to store mask: %v":                                                  "Maske konnte nicht gespeichert werden: %v",
+	"Cancelled after creating %d of %d note(s)":                                 "Abgebrochen, nachdem %d von %d Notiz(en) erstellt wurden",
+	"Created image occlusion note (ID: %d) with %d card(s)":                     "Bildverdeckungs-Notiz erstellt (ID: %d) mit %d Karte(n)",
+	"Created %d image occlusion note(s) with %s: %s":                            "%d Bildverdeckungs-Notiz(en) mit %s erstellt: %s",
+	"Image: %s, %d rectangle(s)":                                                "Bild: %s, %d Rechteck(e)",
+
+	// Math cards
+	"Invalid formula on the front: %v":         "Ungültige Formel auf der Vorderseite: %v",
+	"Invalid formula on the back: %v":          "Ungültige Formel auf der Rückseite: %v",
+	"Preview: %d formula(s), not created":      "Vorschau: %d Formel(n), nicht erstellt",
+	"Front: %s":                                "Vorderseite: %s",
+	"Back: %s":                                 "Rückseite: %s",
+	"Note type %s needs at least two fields":   "Der Notiztyp %s braucht mindestens zwei Felder",
+	"Created card (ID: %d) with %d formula(s)": "Karte erstellt (ID: %d) mit %d Formel(n)",
+	"LaTeX formulas are rendered by Anki's LaTeX support, which needs LaTeX installed on the computer.": "LaTeX-Formeln werden von Ankis LaTeX-Unterstützung gerendert, die ein installiertes LaTeX auf dem Computer braucht.",
+	"Check these brackets; they are fine in intervals like [0, 1)":                                      "Prüfe diese Klammern; in Intervallen wie [0, 1) sind sie richtig",
+
+	// Image shrinking
+	"max_dimension must be between 1 and %d":              "max_dimension muss zwischen 1 und %d liegen",
+	"quality must be between 1 and 100":                   "quality muss zwischen 1 und 100 liegen",
+	"%s stored as it was: %s":                             "%s unverändert gespeichert: %s",
+	"%s shrunk from %dx%d (%d bytes) to %dx%d (%d bytes)": "%s von %dx%d (%d Bytes) auf %dx%d (%d Bytes) verkleinert",
+
+	// Unused media
+	"%d unused media file(s) of %d: showing %d":                                                 "%d ungenutzte Mediendatei(en) von %d: %d angezeigt",
+	"All %d media file(s) matching %s are used by notes":                                        "Alle %d Mediendatei(en) zu %s werden von Notizen verwendet",
+	"Anki moved the files to its media trash, from where Tools > Check Media can restore them.": "Anki hat die Dateien in den Medienpapierkorb verschoben, aus dem Extras > Medien überprüfen sie wiederherstellen kann.",
+	"Call again with delete=true and confirm=true to delete them, once the user has agreed.":    "Rufe das Tool erneut mit delete=true und confirm=true auf, um sie zu löschen, sobald der Benutzer zugestimmt hat.",
+	"Deleted %d unused media file(s) of %d: showing %d":                                         "%d ungenutzte Mediendatei(en) von %d gelöscht: %d angezeigt",
+	"Failed to delete media files: %v":                                                          "Mediendateien konnten nicht gelöscht werden: %v",
+	"Skipped %d file(s) starting with _, which Anki keeps for note types":                       "%d Datei(en) mit _ am Anfang übersprungen, die Anki für Notiztypen behält",
+	"find_unused_media deleted nothing. Ask the user to confirm deleting the unused media files, then call again with delete=true and confirm=true.": "find_unused_media hat nichts gelöscht. Bitte den Benutzer, das Löschen der ungenutzten Mediendateien zu bestätigen, und rufe das Tool dann erneut mit delete=true und confirm=true auf.",
+
+	// Media references
+	"%d missing media file(s) referred to by %d of %d note(s)": "%d fehlende Mediendatei(en), auf die %d von %d Notiz(en) verweisen",
+	"%s: note(s) %s": "%s: Notiz(en) %s",
+	"All media files referred to by the %d note(s) checked exist": "Alle Mediendateien, auf die die %d geprüften Notiz(en) verweisen, sind vorhanden",
+	"Failed to check media references: %v":                        "Medienverweise konnten nicht geprüft werden: %v",
+	"Search for the affected notes with: nid:%s":                  "Suche die betroffenen Notizen mit: nid:%s",
+
+	// Card recipes
+	"(given by each call)":                       "(wird bei jedem Aufruf angegeben)",
+	"Created card (ID: %d) from recipe %s in %s": "Karte (ID: %d) aus Rezept %s in %s erstellt",
+	"Deck: %s":                 "Stapel: %s",
+	"Image in %s, audio in %s": "Bild in %s, Audio in %s",
+	"No recipes configured. Add recipes to the config file: %s": "Keine Rezepte konfiguriert. Füge Rezepte zur Konfigurationsdatei hinzu: %s",
+	"Note type: %s":       "Notiztyp: %s",
+	"Optional inputs: %s": "Optionale Eingaben: %s",
+	"Recipe %s has no deck, so deck is required":                      "Rezept %s hat keinen Stapel, daher ist deck erforderlich",
+	"Recipe %s needs the input(s): %s":                                "Rezept %s benötigt die Eingabe(n): %s",
+	"Recipe %s uses unknown field(s) of note type %s: %s. Fields: %s": "Rezept %s verwendet unbekannte Felder des Notiztyps %s: %s. Felder: %s",
+	"Recipe not found: %s. Recipes: %s":                               "Rezept nicht gefunden: %s. Rezepte: %s",
+	"Required inputs: %s":                                             "Erforderliche Eingaben: %s",
+	"Unknown input(s) for recipe %s: %s. Inputs: %s":                  "Unbekannte Eingabe(n) für Rezept %s: %s. Eingaben: %s",
+	"Value %s must be a string":                                       "Wert %s muss ein String sein",
+	"the first field":                                                 "dem ersten Feld",
+
+	// Vocabulary cards
+	"%s → %s": "%s → %s",
+	"Created vocabulary note (ID: %d) with the note type %s":                                                                                    "Vokabelnotiz (ID: %d) mit dem Notiztyp %s erstellt",
+	"Note type %s decides itself which cards it makes. Set reverse_field in the vocab section of the config file to choose with bidirectional.": "Notiztyp %s entscheidet selbst, welche Karten er erzeugt. Setze reverse_field im Abschnitt vocab der Konfigurationsdatei, um mit bidirectional zu wählen.",
+	"The vocab note type %s has no field for %s":                                                                                                "Der Vokabel-Notiztyp %s hat kein Feld für %s",
+	"word and translation are required":                                                                                                         "word und translation sind erforderlich",
+}
@@ -0,0 +1,768 @@
+package main
+
+// messagesES holds the Spanish translations of tool output
+var messagesES = map[string]string{
+	// Common
+	"Error: %s":                    "Error: %s",
+	"all decks":                    "todos los mazos",
+	"card_id is required":          "card_id es obligatorio",
+	"data or path is required":     "se requiere data o path",
+	"unsupported format: %s":       "formato no compatible: %s",
+	"Failed to find cards: %v":     "No se pudieron buscar las tarjetas: %v",
+	"Failed to get card info: %v":  "No se pudo obtener la información de las tarjetas: %v",
+	"Failed to get note info: %v":  "No se pudo obtener la información de las notas: %v",
+	"Failed to read %s: %v":        "No se pudo leer %s: %v",
+	"Failed to write %s: %v":       "No se pudo escribir %s: %v",
+	"No cards found for query: %s": "No se encontraron tarjetas para la búsqueda: %s",
+	"%.0f minute(s)":               "%.0f minuto(s)",
+	"%.1f hour(s)":                 "%.1f hora(s)",
+	"%.0f day(s)":                  "%.0f día(s)",
+	"%.1f month(s)":                "%.1f mes(es)",
+	"%.1f year(s)":                 "%.1f año(s)",
+
+	// Cards and decks
+	"deck is required":                  "deck es obligatorio",
+	"front is required":                 "front es obligatorio",
+	"back is required":                  "back es obligatorio",
+	"name is required":                  "name es obligatorio",
+	"Failed to read image file: %v":     "No se pudo leer el archivo de imagen: %v",
+	"Failed to store image: %v":         "No se pudo guardar la imagen: %v",
+	"Failed to create card: %v":         "No se pudo crear la tarjeta: %v",
+	"Created card (ID: %d)":             "Tarjeta creada (ID: %d)",
+	"Failed to get decks: %v":           "No se pudieron obtener los mazos: %v",
+	"Decks (%d)":                        "Mazos (%d)",
+	"Failed to create deck: %v":         "No se pudo crear el mazo: %v",
+	"Created deck: %s":                  "Mazo creado: %s",
+	"Failed to verify stored image: %v": "No se pudo verificar la imagen guardada: %v",
+	"Stored media":                      "Multimedia guardada",
+	"%s (%d bytes, verified)":           "%s (%d bytes, verificado)",
+	"%s (renamed from %s to avoid overwriting an existing file, %d bytes, verified)": "%s (renombrado desde %s para no sobrescribir un archivo existente, %d bytes, verificado)",
+	"tag is required":                                                            "tag es obligatorio",
+	"Failed to suspend cards: %v":                                                "No se pudieron suspender las tarjetas: %v",
+	"Failed to unsuspend cards: %v":                                              "No se pudieron reactivar las tarjetas: %v",
+	"No unsuspended cards found with tag %s":                                     "No se encontraron tarjetas sin suspender con la etiqueta %s",
+	"No suspended cards found with tag %s":                                       "No se encontraron tarjetas suspendidas con la etiqueta %s",
+	"Suspended %d card(s) with tag %s":                                           "%d tarjeta(s) con la etiqueta %s suspendida(s)",
+	"Unsuspended %d card(s) with tag %s":                                         "%d tarjeta(s) con la etiqueta %s reactivada(s)",
+	"Failed to create note type %s: %v":                                          "Error al crear el tipo de nota %s: %v",
+	"Added a reverse card (back → front) with the note type %s":                  "Se añadió una tarjeta inversa (reverso → anverso) con el tipo de nota %s",
+	"Created the missing note type %s":                                           "Se creó el tipo de nota %s, que faltaba",
+	"cards is required":                                                          "cards es obligatorio",
+	"front and back are required":                                                "front y back son obligatorios",
+	"Created %d of %d card(s)":                                                   "Se crearon %d de %d tarjeta(s)",
+	"#%d failed: %s":                                                             "#%d falló: %s",
+	"#%d: note %d in %s":                                                         "#%d: nota %d en %s",
+	"Field %s must be a string":                                                  "El campo %s debe ser un texto",
+	"reversed can't be combined with the note type %s":                           "reversed no se puede combinar con el tipo de nota %s",
+	"Unknown field(s) for note type %s: %s. Fields: %s":                          "Campo(s) desconocido(s) para el tipo de nota %s: %s. Campos: %s",
+	"The first field of note type %s must not be empty":                          "El primer campo del tipo de nota %s no puede estar vacío",
+	"The Default deck can't be deleted":                                          "El mazo Default no se puede eliminar",
+	"Would delete deck %s with %d subdeck(s) and %d card(s)":                     "Se eliminaría el mazo %s con %d submazo(s) y %d tarjeta(s)",
+	"Would delete deck %s with %d subdeck(s) and move %d card(s) to %s":          "Se eliminaría el mazo %s con %d submazo(s) y se moverían %d tarjeta(s) a %s",
+	"Failed to delete deck: %v":                                                  "Error al eliminar el mazo: %v",
+	"Deleted deck %s with %d subdeck(s) and %d card(s)":                          "Se eliminó el mazo %s con %d submazo(s) y %d tarjeta(s)",
+	"Deleted deck %s with %d subdeck(s) and moved %d card(s) to %s":              "Se eliminó el mazo %s con %d submazo(s) y se movieron %d tarjeta(s) a %s",
+	"Pass either new_name or parent, not both":                                   "Indica new_name o parent, no ambos",
+	"new_name or parent is required":                                             "new_name o parent es obligatorio",
+	"The Default deck can't be renamed":                                          "El mazo Default no se puede renombrar",
+	"The deck is already named %s":                                               "El mazo ya se llama %s",
+	"A deck can't be moved into its own subdeck":                                 "Un mazo no se puede mover a su propio submazo",
+	"Deck already exists: %s":                                                    "El mazo ya existe: %s",
+	"Moved %d card(s) to %s, but %d card(s) remain in %s, so it was not deleted": "Se movieron %d tarjeta(s) a %s, pero quedan %d tarjeta(s) en %s, así que no se eliminó",
+	"Renamed deck %s to %s":                                                      "Mazo %s renombrado a %s",
+	"%d card(s) of %s are in %s (card IDs: %s)":                                  "%d tarjeta(s) de %s están en %s (IDs de tarjeta: %s)",
+	"%s. Moving the cards back failed as well, so the rename is half done: %s":   "%s. Tampoco se pudieron devolver las tarjetas, así que el cambio de nombre quedó a medias: %s",
+	"%s. Nothing was renamed: the cards moved so far were moved back":            "%s. No se renombró nada: las tarjetas movidas hasta ahora se devolvieron",
+	"Would rename deck %s to %s":                                                 "Se renombraría el mazo %s a %s",
+	"Subdecks to move: %d":                                                       "Submazos a mover: %d",
+	"Cards to move: %d":                                                          "Tarjetas a mover: %d",
+	"Subdecks moved: %d":                                                         "Submazos movidos: %d",
+	"Cards moved: %d":                                                            "Tarjetas movidas: %d",
+	"Pass either card_ids or query, not both":                                    "Indica card_ids o query, no ambos",
+	"card_ids or query is required":                                              "card_ids o query es obligatorio",
+	"No cards found to move":                                                     "No se encontraron tarjetas para mover",
+	"Moved %d card(s) to %s":                                                     "Se movieron %d tarjeta(s) a %s",
+	"Already in the deck: %d":                                                    "Ya estaban en el mazo: %d",
+	"Cards not found: %d":                                                        "Tarjetas no encontradas: %d",
+	"No cards found":                                                             "No se encontraron tarjetas",
+	"Suspended %d card(s)":                                                       "Se suspendieron %d tarjeta(s)",
+	"Already suspended: %d":                                                      "Ya estaban suspendidas: %d",
+	"Unsuspended %d card(s)":                                                     "Se reactivaron %d tarjeta(s)",
+	"Not suspended: %d":                                                          "No estaban suspendidas: %d",
+	"Exported deck %s to %s":                                                     "Mazo %s exportado a %s",
+	"Would export deck %s to %s":                                                 "Se exportaría el mazo %s a %s",
+	"Would export deck %s and return it as %s":                                   "Se exportaría el mazo %s y se devolvería como %s",
+	"Failed to create temporary directory: %v":                                   "No se pudo crear el directorio temporal: %v",
+	"Failed to export deck: %v":                                                  "No se pudo exportar el mazo: %v",
+	"Failed to read the exported file; Anki has to run on the same machine to return the file, otherwise pass path: %v": "No se pudo leer el archivo exportado; Anki debe ejecutarse en la misma máquina para devolver el archivo, si no, indica path: %v",
+	"path must end in .apkg":                             "path debe terminar en .apkg",
+	"Invalid duplicate_scope %q: use collection or deck": "duplicate_scope %q no válido: usa collection o deck",
+	"duplicate_scope_deck needs duplicate_scope deck":    "duplicate_scope_deck requiere duplicate_scope deck",
+	"Already new: %d":                                    "Ya nuevas: %d",
+	"Failed to relearn cards: %v":                        "No se pudieron volver a aprender las tarjetas: %v",
+	"Failed to reset cards: %v":                          "No se pudieron restablecer las tarjetas: %v",
+	"Relearning %d card(s)":                              "Reaprendiendo %d tarjeta(s)",
+	"Reset %d card(s) to new":                            "%d tarjeta(s) restablecida(s) como nuevas",
+	"Would relearn %d card(s)":                           "Se volverían a aprender %d tarjeta(s)",
+	"Would reset %d card(s) to new":                      "Se restablecerían %d tarjeta(s) como nuevas",
+	"Failed to reschedule cards: %v":                     "No se pudieron reprogramar las tarjetas: %v",
+	"Rescheduled %d card(s) to %s":                       "%d tarjeta(s) reprogramada(s) para el %s",
+	"Spread %d card(s) between %s and %s":                "%d tarjeta(s) repartida(s) entre el %s y el %s",
+	"date is required":                                   "date es obligatorio",
+	"until must not be before date":                      "until no debe ser anterior a date",
+
+	// Maintenance
+	"check_database was not run. It can take several minutes on large collections and blocks Anki while running. Ask the user to explicitly confirm, then call again with confirm=true.": "check_database no se ejecutó. Puede tardar varios minutos en colecciones grandes y bloquea Anki mientras se ejecuta. Pide al usuario una confirmación explícita y vuelve a llamar con confirm=true.",
+	"Failed to check database: %v":    "No se pudo comprobar la base de datos: %v",
+	"Failed to clear unused tags: %v": "No se pudieron eliminar las etiquetas sin usar: %v",
+	"No unused tags found":            "No se encontraron etiquetas sin usar",
+	"Removed %d unused tag(s)":        "Se eliminaron %d etiqueta(s) sin usar",
+	"When it is done, Anki shows in its window whether the collection needed repair and what it fixed.": "Cuando termine, Anki mostrará en su ventana si la colección necesitaba reparación y qué se corrigió.",
+	"Started Anki's database check. It runs in the background and may still be running.":                "Se ha iniciado la comprobación de la base de datos de Anki. Se ejecuta en segundo plano y puede que aún no haya terminado.",
+
+	// Statistics
+	"Tag statistics for %s (%d of %d tags, sorted by %s)":              "Estadísticas por etiqueta de %s (%d de %d etiquetas, ordenadas por %s)",
+	"%s: %d notes, %d cards, %d due, avg ease %.0f%%, avg lapses %.2f": "%s: %d notas, %d tarjetas, %d pendientes, facilidad media %.0f%%, fallos medios %.2f",
+	"Failed to get review log: %v":                                     "No se pudo obtener el historial de repasos: %v",
+	"Progress for %s from %s to %s":                                    "Progreso de %s del %s al %s",
+	"Reviews done: %d (%d distinct cards)":                             "Repasos hechos: %d (%d tarjetas distintas)",
+	"Cards learned: %d":                                                "Tarjetas aprendidas: %d",
+	"Cards matured: %d":                                                "Tarjetas maduradas: %d",
+	"Mature cards: %d -> %d":                                           "Tarjetas maduras: %d -> %d",
+	"Retention: %.1f%% of %d review answers":                           "Retención: %.1f%% de %d respuestas de repaso",
+	"Retention: no reviews of graduated cards in this period":          "Retención: no hubo repasos de tarjetas graduadas en este periodo",
+	"Study time: %s":                                                   "Tiempo de estudio: %s",
+	"Average ease: %.0f%%":                                             "Facilidad media: %.0f%%",
+	"Average interval: %s":                                             "Intervalo medio: %s",
+	"Cards: %d in %d note(s)":                                          "Tarjetas: %d en %d nota(s)",
+	"Collection statistics":                                            "Estadísticas de la colección",
+	"Could not read any metrics from Anki's statistics report. They are only recognized when Anki's interface language is English.": "No se pudo leer ninguna métrica del informe de estadísticas de Anki. Solo se reconocen cuando la interfaz de Anki está en inglés.",
+	"Failed to get collection statistics: %v":             "No se pudieron obtener las estadísticas de la colección: %v",
+	"Mature cards: %d (%.1f%%)":                           "Tarjetas maduras: %d (%.1f%%)",
+	"Retention over the last month: %.1f%% of %d answers": "Retención en el último mes: %.1f%% de %d respuestas",
+	"Studied today: %d card(s) in %s":                     "Estudiado hoy: %d tarjeta(s) en %s",
+	"%s: %d review(s)":                                    "%s: %d repaso(s)",
+	"Average per day studied: %.1f reviews":               "Media por día de estudio: %.1f repasos",
+	"Days studied: %d of %d":                              "Días de estudio: %d de %d",
+	"Failed to get review history: %v":                    "No se pudo obtener el historial de repasos: %v",
+	"Review history from %s to %s":                        "Historial de repasos del %s al %s",
+	"Reviews: %d":                                         "Repasos: %d",
+	"Reviews per day":                                     "Repasos por día",
+	"Reviews per month":                                   "Repasos por mes",
+	"Reviews per week":                                    "Repasos por semana",
+	"Week of %s":                                          "Semana del %s",
+
+	// Review log
+	"Failed to get reviews: %v":                       "No se pudieron obtener los repasos: %v",
+	"Failed to write CSV: %v":                         "No se pudo escribir el CSV: %v",
+	"Exported %d reviews of %d cards to %s":           "Se exportaron %d repasos de %d tarjetas a %s",
+	"Failed to parse reviews: %v":                     "No se pudieron interpretar los repasos: %v",
+	"no reviews found in input":                       "no se encontraron repasos en la entrada",
+	"Failed to import reviews: %v":                    "No se pudieron importar los repasos: %v",
+	"Imported %d reviews for %d cards":                "Se importaron %d repasos para %d tarjetas",
+	"%s: %s (%s), interval %s → %s, ease %d%%, %.0fs": "%s: %s (%s), intervalo %s → %s, facilidad %d%%, %.0fs",
+	"%s: rescheduled to %s":                           "%s: reprogramada a %s",
+	"filtered deck":                                   "mazo filtrado",
+
+	// Card explanation
+	"Card %d not found":                 "No se encontró la tarjeta %d",
+	"Card %d in deck %q (note type %s)": "Tarjeta %d en el mazo %q (tipo de nota %s)",
+	"If you answered it now":            "Si la respondieras ahora",
+	"State: suspended. It will not be shown until it is unsuspended.":                      "Estado: suspendida. No se mostrará hasta que se reactive.",
+	"State: buried. It will return to its queue tomorrow (or when unburied).":              "Estado: enterrada. Volverá a su cola mañana (o al desenterrarla).",
+	"State: new. It has never been studied and is waiting in the new card queue.":          "Estado: nueva. Nunca se ha estudiado y espera en la cola de tarjetas nuevas.",
+	"State: relearning. It was forgotten and is going through the relearning steps.":       "Estado: reaprendiendo. Se olvidó y está pasando por los pasos de reaprendizaje.",
+	"State: learning. It is going through the initial learning steps.":                     "Estado: aprendiendo. Está pasando por los pasos de aprendizaje iniciales.",
+	"State: review. It has graduated from learning and is shown at growing intervals.":     "Estado: repaso. Se graduó del aprendizaje y se muestra a intervalos crecientes.",
+	"State: preview in a filtered deck.":                                                   "Estado: vista previa en un mazo filtrado.",
+	"Current interval: %s.":                                                                "Intervalo actual: %s.",
+	"Ease: %.0f%%. Good multiplies the interval by roughly this factor.":                   "Facilidad: %.0f%%. «Bien» multiplica el intervalo aproximadamente por este factor.",
+	"Due: %s (learning step).":                                                             "Pendiente: %s (paso de aprendizaje).",
+	"Position in the new card queue: %d.":                                                  "Posición en la cola de tarjetas nuevas: %d.",
+	"Due: today (%s).":                                                                     "Pendiente: hoy (%s).",
+	"Due: overdue by %d day(s) (was due %s).":                                              "Pendiente: con %d día(s) de retraso (vencía el %s).",
+	"Due: in %d day(s) (%s).":                                                              "Pendiente: en %d día(s) (%s).",
+	"Reviews: %d, lapses: %d.":                                                             "Repasos: %d, fallos: %d.",
+	"It has reached the leech threshold of %d lapses; consider rewording or splitting it.": "Ha alcanzado el umbral de sanguijuela de %d fallos; considera reformularla o dividirla.",
+	"It becomes a leech after %d more lapse(s).":                                           "Se convertirá en sanguijuela tras %d fallo(s) más.",
+	"Again":                                  "Otra vez",
+	"Hard":                                   "Difícil",
+	"Good":                                   "Bien",
+	"Easy":                                   "Fácil",
+	"graduates to review, next shown in %s":  "se gradúa a repaso, se mostrará de nuevo en %s",
+	"shown again in %s":                      "se mostrará de nuevo en %s",
+	"%s (back to the first learning step)":   "%s (vuelve al primer paso de aprendizaje)",
+	"%s (back to the first relearning step)": "%s (vuelve al primer paso de reaprendizaje)",
+	"returns to review, next shown in %s":    "vuelve a repaso, se mostrará de nuevo en %s",
+	"counts as a lapse: ease drops to %.0f%%, %s, then the interval restarts at %s": "cuenta como fallo: la facilidad baja a %.0f%%, %s, después el intervalo se reinicia en %s",
+	"next shown in %s, ease drops to %.0f%%":                                        "se mostrará en %s, la facilidad baja a %.0f%%",
+	"next shown in %s, ease unchanged":                                              "se mostrará en %s, la facilidad no cambia",
+	"next shown in %s, ease rises to %.0f%%":                                        "se mostrará en %s, la facilidad sube a %.0f%%",
+	"Cards not found: %s":                                                           "Tarjetas no encontradas: %s",
+	"Due: %s":                                                                       "Vence: %s",
+	"Due: %s (in %d day(s))":                                                        "Vence: %s (en %d día(s))",
+	"Interval: %d day(s), ease: %.0f%%":                                             "Intervalo: %d día(s), facilidad: %.0f%%",
+	"New card position: %d":                                                         "Posición de la tarjeta nueva: %d",
+	"Note: %d, note type: %s":                                                       "Nota: %d, tipo de nota: %s",
+	"Queue: %s":                                                                     "Cola: %s",
+	"Reps: %d, lapses: %d":                                                          "Repasos: %d, fallos: %d",
+	"buried":                                                                        "enterrada",
+	"card_ids is required":                                                          "card_ids es obligatorio",
+	"preview":                                                                       "vista previa",
+	"suspended":                                                                     "suspendida",
+
+	// Output
+	"Failed to encode JSON: %v": "No se pudo codificar el JSON: %v",
+
+	// Deck options
+	"preset is required":                          "preset es obligatorio",
+	"decks is required":                           "decks es obligatorio",
+	"Failed to get deck options: %v":              "No se pudieron obtener las opciones del mazo: %v",
+	"Deck not found: %s":                          "Mazo no encontrado: %s",
+	"Preset not found: %s. Available presets: %s": "Configuración predefinida no encontrada: %s. Configuraciones disponibles: %s",
+	"Failed to apply preset: %v":                  "No se pudo aplicar la configuración predefinida: %v",
+	"Applied preset \"%s\" to %d deck(s)":         "Configuración predefinida \"%s\" aplicada a %d mazo(s)",
+	"%s (already using this preset)":              "%s (ya usaba esta configuración)",
+	"%s (was: %s)":                                "%s (antes: %s)",
+	"%s has temporary limits for today. Run restore_deck_limits first so the change isn't lost at the end of the day.": "%s tiene límites temporales para hoy. Ejecuta primero restore_deck_limits para que el cambio no se pierda al final del día.",
+	"%s must be a whole number of at least %d": "%s debe ser un número entero de al menos %d",
+	"Easy interval":                      "Intervalo fácil",
+	"Easy interval: %s":                  "Intervalo fácil: %s",
+	"Failed to update deck options: %v":  "No se pudieron actualizar las opciones del mazo: %v",
+	"Graduating interval":                "Intervalo de graduación",
+	"Graduating interval: %s":            "Intervalo de graduación: %s",
+	"Invalid %s: %v":                     "%s no válido: %v",
+	"Learning steps":                     "Pasos de aprendizaje",
+	"Learning steps: %s":                 "Pasos de aprendizaje: %s",
+	"Leech threshold":                    "Umbral de sanguijuela",
+	"Leech threshold: %d lapses":         "Umbral de sanguijuela: %d fallos",
+	"Maximum interval":                   "Intervalo máximo",
+	"Maximum interval: %s":               "Intervalo máximo: %s",
+	"Maximum reviews/day":                "Máximo de repasos/día",
+	"Maximum reviews/day: %d":            "Máximo de repasos/día: %d",
+	"New cards/day":                      "Tarjetas nuevas/día",
+	"New cards/day: %d":                  "Tarjetas nuevas/día: %d",
+	"Options of %s (preset \"%s\")":      "Opciones de %s (configuración predefinida \"%s\")",
+	"Pass at least one option to change": "Indica al menos una opción que cambiar",
+	"Relearning steps":                   "Pasos de reaprendizaje",
+	"Relearning steps: %s":               "Pasos de reaprendizaje: %s",
+	"The change also applies to decks sharing this preset: %s":                 "El cambio también se aplica a los mazos que comparten esta configuración predefinida: %s",
+	"The options already had these values.":                                    "Las opciones ya tenían estos valores.",
+	"This preset is shared with: %s":                                           "Esta configuración predefinida se comparte con: %s",
+	"Updated preset \"%s\" of %s":                                              "Configuración predefinida \"%s\" de %s actualizada",
+	"easy_interval (%.0f) must not be shorter than graduating_interval (%.0f)": "easy_interval (%.0f) no debe ser más corto que graduating_interval (%.0f)",
+	"%d deck(s) now use the Default preset: %s":                                "%d mazo(s) usan ahora la configuración predefinida Default: %s",
+	"A preset named %s already exists (ID: %d)":                                "Ya existe una configuración predefinida llamada %s (ID: %d)",
+	"Created preset \"%s\" (ID: %d) as a copy of \"%s\"":                       "Configuración predefinida \"%s\" (ID: %d) creada como copia de \"%s\"",
+	"Created preset \"%s\" (ID: %d) as a copy of preset %d":                    "Configuración predefinida \"%s\" (ID: %d) creada como copia de la configuración %d",
+	"Created preset %d, but failed to apply it: %v":                            "Se creó la configuración predefinida %d, pero no se pudo aplicar: %v",
+	"Failed to create preset: %v":                                              "No se pudo crear la configuración predefinida: %v",
+	"Failed to remove preset: %v":                                              "No se pudo eliminar la configuración predefinida: %v",
+	"No deck uses it yet. Apply it with apply_deck_preset using its ID.":       "Ningún mazo la usa todavía. Aplícala con apply_deck_preset usando su ID.",
+	"Removed preset \"%s\"":                                                    "Configuración predefinida \"%s\" eliminada",
+	"The Default preset can't be removed":                                      "La configuración predefinida Default no se puede eliminar",
+
+	// Deck limits
+	"new_cards or reviews must be a positive number": "new_cards o reviews debe ser un número positivo",
+	"Failed to extend limits: %v":                    "No se pudieron ampliar los límites: %v",
+	"Extended today's limits for %s":                 "Límites de hoy ampliados para %s",
+	"New cards: %d → %d":                             "Tarjetas nuevas: %d → %d",
+	"Reviews: %d → %d":                               "Repasos: %d → %d",
+	"These limits apply today only: the deck uses a temporary copy of its \"%s\" preset, and the original preset is restored when the Anki day ends.": "Estos límites solo se aplican hoy: el mazo usa una copia temporal de su configuración \"%s\", y la configuración original se restaura cuando termina el día de Anki.",
+	"review_limit must be a number of at least 0":                    "review_limit debe ser un número mayor o igual que 0",
+	"Failed to change review limit: %v":                              "No se pudo cambiar el límite de repasos: %v",
+	"Changed today's review limit for %s":                            "Límite de repasos de hoy cambiado para %s",
+	"Use restore_deck_limits to restore it earlier.":                 "Usa restore_deck_limits para restaurarla antes.",
+	"Failed to restore limits: %v":                                   "No se pudieron restaurar los límites: %v",
+	"No temporary deck limits to restore":                            "No hay límites temporales de mazos que restaurar",
+	"Restored limits for %d deck(s)":                                 "Límites restaurados para %d mazo(s)",
+	"%s: back on preset \"%s\"":                                      "%s: de vuelta a la configuración \"%s\"",
+	"Some decks could not be restored and will be retried later: %v": "Algunos mazos no se pudieron restaurar y se reintentará más tarde: %v",
+
+	// Deck routing
+	"No routing rules configured. Add rules to the config file: %s":  "No hay reglas de enrutamiento configuradas. Añade reglas al archivo de configuración: %s",
+	"Failed to move cards to %s: %v":                                 "No se pudieron mover las tarjetas a %s: %v",
+	"All cards are already in the decks chosen by the routing rules": "Todas las tarjetas ya están en los mazos elegidos por las reglas de enrutamiento",
+	"Would move %d card(s)":                                          "Se moverían %d tarjeta(s)",
+	"Moved %d card(s)":                                               "%d tarjeta(s) movida(s)",
+	"tag %s → %s: %d card(s)":                                        "etiqueta %s → %s: %d tarjeta(s)",
+	"Routed to deck %s by the tag rule %s":                           "Enviada al mazo %s por la regla de etiqueta %s",
+
+	// Note links
+	"note_id is required": "note_id es obligatorio",
+	"related_note_ids must contain at least one other note": "related_note_ids debe contener al menos otra nota",
+	"Note not found: %d": "Nota no encontrada: %d",
+	"Note %d (note type %s) has no %s field. Add a field named %s to this note type in Anki first.": "La nota %d (tipo de nota %s) no tiene el campo %s. Añade primero un campo llamado %s a este tipo de nota en Anki.",
+	"Failed to update note %d: %v":                         "No se pudo actualizar la nota %d: %v",
+	"Linked note %d with %d note(s); %d note(s) changed":   "Nota %d enlazada con %d nota(s); %d nota(s) modificada(s)",
+	"Unlinked note %d from %d note(s); %d note(s) changed": "Enlaces de la nota %d con %d nota(s) eliminados; %d nota(s) modificada(s)",
+	"Note %d has no related notes":                         "La nota %d no tiene notas relacionadas",
+	"Notes related to %d (%d)":                             "Notas relacionadas con %d (%d)",
+	"%d: note no longer exists":                            "%d: la nota ya no existe",
+	"%d [%s]: %s (tags: %s)":                               "%d [%s]: %s (etiquetas: %s)",
+	"%d [%s]: %s":                                          "%d [%s]: %s",
+
+	// Cloze deletions
+	"text is required":                                      "text es obligatorio",
+	"Failed to number clozes: %v":                           "Error al numerar los huecos: %v",
+	"Cloze text (%d card(s))":                               "Texto con huecos (%d tarjeta(s))",
+	"Cloze text":                                            "Texto con huecos",
+	"Created cloze note (ID: %d) with %d card(s)":           "Nota con huecos creada (ID: %d) con %d tarjeta(s)",
+	"Key terms: %s":                                         "Términos clave: %s",
+	"No key terms found; pass the words to hide as targets": "No se encontraron términos clave; indica las palabras a ocultar en targets",
+	"Not found in the text: %s":                             "No se encontró en el texto: %s",
+	"Note type %s has no field for back_extra":              "El tipo de nota %s no tiene ningún campo para back_extra",
+	"Note type %s is not a cloze note type":                 "El tipo de nota %s no es de tipo cloze",
+
+	// Note types
+	"model is required":    "model es obligatorio",
+	"old_name is required": "old_name es obligatorio",
+	"new_name is required": "new_name es obligatorio",
+	"rename_model_field was not run. Renaming a field forces a full upload on the next sync. Ask the user to confirm, then call again with confirm=true.": "rename_model_field no se ejecutó. Renombrar un campo obliga a una subida completa en la próxima sincronización. Pide confirmación al usuario y vuelve a llamar con confirm=true.",
+	"Failed to get note types: %v":                                 "Error al obtener los tipos de nota: %v",
+	"Note type not found: %s":                                      "Tipo de nota no encontrado: %s",
+	"Failed to get fields: %v":                                     "Error al obtener los campos: %v",
+	"Note type %s has no field %s. Fields: %s":                     "El tipo de nota %s no tiene el campo %s. Campos: %s",
+	"Note type %s already has a field named %s":                    "El tipo de nota %s ya tiene un campo llamado %s",
+	"Failed to find notes: %v":                                     "Error al buscar notas: %v",
+	"Failed to rename field: %v":                                   "Error al renombrar el campo: %v",
+	"Renamed field %s to %s in note type %s":                       "Campo %s renombrado a %s en el tipo de nota %s",
+	"Notes checked: %d":                                            "Notas comprobadas: %d",
+	"Notes with content preserved: %d (%d non-empty)":              "Notas con el contenido conservado: %d (%d no vacías)",
+	"Notes whose content did not carry over: %d (%s)":              "Notas cuyo contenido no se conservó: %d (%s)",
+	"The next sync will require a full upload to AnkiWeb.":         "La próxima sincronización requerirá una subida completa a AnkiWeb.",
+	"%s of template %s must be a string":                           "%s de la plantilla %s debe ser un texto",
+	"%s of template %s refers to unknown field(s): %s. Fields: %s": "%s de la plantilla %s usa campo(s) desconocido(s): %s. Campos: %s",
+	"%s: back template":                                            "%s: plantilla del reverso",
+	"%s: front template":                                           "%s: plantilla del anverso",
+	"Failed to get templates: %v":                                  "No se pudieron obtener las plantillas: %v",
+	"Failed to update templates: %v":                               "No se pudieron actualizar las plantillas: %v",
+	"Note type %s has no template %s. Templates: %s":               "El tipo de nota %s no tiene la plantilla %s. Plantillas: %s",
+	"Template %s needs Front or Back":                              "La plantilla %s necesita Front o Back",
+	"Updated templates of %s: %s":                                  "Plantillas de %s actualizadas: %s",
+	"templates is required":                                        "templates es obligatorio",
+	"Failed to get styling: %v":                                    "No se pudo obtener el estilo: %v",
+	"Failed to update styling: %v":                                 "No se pudo actualizar el estilo: %v",
+	"Styling of %s":                                                "Estilo de %s",
+	"The CSS has unbalanced braces":                                "El CSS tiene llaves desequilibradas",
+	"Updated styling of %s":                                        "Estilo de %s actualizado",
+	"css is required":                                              "css es obligatorio",
+
+	// Sync
+	"Failed to sync: %v": "Error al sincronizar: %v",
+	"Sync with AnkiWeb started. Anki syncs in the background and shows in its window when it is done or if it failed.": "Sincronización con AnkiWeb iniciada. Anki sincroniza en segundo plano y muestra en su ventana cuándo ha terminado o si ha fallado.",
+	"Warning: could not record the sync time: %v": "Advertencia: no se pudo registrar la hora de sincronización: %v",
+	"Failed to read sync state: %v":               "Error al leer el estado de sincronización: %v",
+	"Sync status":                                 "Estado de sincronización",
+	"Last sync started through this server: none recorded; showing changes from the last 24 hours": "Última sincronización iniciada a través de este servidor: ninguna registrada; se muestran los cambios de las últimas 24 horas",
+	"Last sync started through this server: %s":                                                    "Última sincronización iniciada a través de este servidor: %s",
+	"Notes added or edited: %d":                                                                    "Notas añadidas o editadas: %d",
+	"Cards reviewed: %d":                                                                           "Tarjetas repasadas: %d",
+	"There are local changes; call sync to upload them to AnkiWeb.":                                "Hay cambios locales; llama a sync para subirlos a AnkiWeb.",
+	"No local changes found.":                                                                      "No se encontraron cambios locales.",
+	"Failed to find changes: %v":                                                                   "No se pudieron buscar los cambios: %v",
+
+	// Notes
+	"Nothing to update: pass fields, add_tags or remove_tags": "Nada que actualizar: indica fields, add_tags o remove_tags",
+	"Invalid tag %q: tags can't be empty or contain spaces":   "Etiqueta no válida %q: las etiquetas no pueden estar vacías ni contener espacios",
+	"Failed to add tags: %v":                                  "Error al añadir las etiquetas: %v",
+	"Failed to remove tags: %v":                               "Error al quitar las etiquetas: %v",
+	"Updated note %d":                                         "Nota %d actualizada",
+	"Fields changed: %s":                                      "Campos modificados: %s",
+	"Fields already had these values":                         "Los campos ya tenían estos valores",
+	"Tags added: %s":                                          "Etiquetas añadidas: %s",
+	"Tags removed: %s":                                        "Etiquetas quitadas: %s",
+	"Pass either note_ids or query, not both":                 "Indica note_ids o query, no ambos",
+	"note_ids or query is required":                           "note_ids o query es obligatorio",
+	"No notes found to delete":                                "No se encontraron notas para eliminar",
+	"Failed to delete notes: %v":                              "Error al eliminar las notas: %v",
+	"Deleted %d note(s) with %d card(s)":                      "Se eliminaron %d nota(s) con %d tarjeta(s)",
+	"Would delete %d note(s) with %d card(s)":                 "Se eliminarían %d nota(s) con %d tarjeta(s)",
+	"... and %d more":                                         "... y %d más",
+	"Nothing was deleted. Show this list to the user and call again with confirm=true once they agree.": "No se eliminó nada. Muestra esta lista al usuario y vuelve a llamar con confirm=true cuando esté de acuerdo.",
+
+	// Study
+	"No cards are due":            "No hay tarjetas pendientes",
+	"Due cards: showing %d of %d": "Tarjetas pendientes: se muestran %d de %d",
+	"%d [%s, %s]: %s → %s (interval: %d day(s), reps: %d, lapses: %d)": "%d [%s, %s]: %s → %s (intervalo: %d día(s), repasos: %d, fallos: %d)",
+	"new":                                "nueva",
+	"learning":                           "en aprendizaje",
+	"relearning":                         "en reaprendizaje",
+	"review":                             "repaso",
+	"Failed to start review: %v":         "Error al iniciar el repaso: %v",
+	"Started reviewing %s":               "Se inició el repaso de %s",
+	"Failed to get the current card: %v": "Error al obtener la tarjeta actual: %v",
+	"Failed to show the answer: %v":      "Error al mostrar la respuesta: %v",
+	"No card is being reviewed. Call start_review first.":                          "No se está repasando ninguna tarjeta. Llama primero a start_review.",
+	"ease must be 1 (Again), 2 (Hard), 3 (Good) or 4 (Easy)":                       "ease debe ser 1 (Otra vez), 2 (Difícil), 3 (Bien) o 4 (Fácil)",
+	"Failed to answer card: %v":                                                    "Error al responder la tarjeta: %v",
+	"Anki did not accept the answer %d for this card":                              "Anki no aceptó la respuesta %d para esta tarjeta",
+	"Answered card %d with %s":                                                     "Tarjeta %d respondida con %s",
+	"No card is being reviewed: the session is finished or no review was started.": "No se está repasando ninguna tarjeta: la sesión terminó o no se inició ningún repaso.",
+	"Card %d (%s)": "Tarjeta %d (%s)",
+	"Question: %s": "Pregunta: %s",
+	"Answer: %s":   "Respuesta: %s",
+	"Buttons: %s":  "Botones: %s",
+
+	// Tags
+	"Added %s to %d note(s)":                "Se añadió %s a %d nota(s)",
+	"Failed to get tags: %v":                "No se pudieron obtener las etiquetas: %v",
+	"Failed to replace tag: %v":             "No se pudo reemplazar la etiqueta: %v",
+	"No notes found":                        "No se encontraron notas",
+	"Removed %s from %d note(s)":            "Se quitó %s de %d nota(s)",
+	"Replaced tag %s with %s on %d note(s)": "Se reemplazó la etiqueta %s por %s en %d nota(s)",
+	"Tags (%d)":                             "Etiquetas (%d)",
+	"tags is required":                      "tags es obligatorio",
+
+	// Import
+	"#%d is a duplicate":             "#%d es un duplicado",
+	"Created %d of %d note(s)":       "Se crearon %d de %d nota(s)",
+	"Duplicates skipped: %d":         "Duplicados omitidos: %d",
+	"Errors: %d":                     "Errores: %d",
+	"Failed to parse CSV: %v":        "No se pudo analizar el CSV: %v",
+	"No rows found in input":         "No se encontraron filas en la entrada",
+	"The first field (%s) is empty":  "El primer campo (%s) está vacío",
+	"Unknown column %s. Columns: %s": "Columna desconocida %s. Columnas: %s",
+	"Failed to parse Markdown: %v":   "No se pudo analizar el Markdown: %v",
+	"No cards found in the document": "No se encontraron tarjetas en el documento",
+	"Question without answer: %s":    "Pregunta sin respuesta: %s",
+	"Failed to parse %s: %v":         "No se pudo analizar %s: %v",
+	"Failed to store %s: %v":         "No se pudo guardar %s: %v",
+	"Note must be an object":         "La nota debe ser un objeto",
+	"notes or path is required":      "notes o path es obligatorio",
+	"#%d (%s) can't be added: %s":    "#%d (%s) no se puede añadir: %s",
+	"#%d (%s) is a duplicate":        "#%d (%s) es un duplicado",
+	"%d of %d note(s) can be added":  "Se pueden añadir %d de %d nota(s)",
+	"Duplicates: %d":                 "Duplicados: %d",
+	"Failed to check notes: %v":      "No se pudieron comprobar las notas: %v",
+	"notes is required":              "notes es obligatorio",
+
+	// Media
+	"Failed to decode media file: %v":           "No se pudo decodificar el archivo multimedia: %v",
+	"Failed to retrieve media file: %v":         "No se pudo obtener el archivo multimedia: %v",
+	"Failed to save media file: %v":             "No se pudo guardar el archivo multimedia: %v",
+	"Media file not found: %s":                  "Archivo multimedia no encontrado: %s",
+	"Saved %s to %s (%d bytes)":                 "%s guardado en %s (%d bytes)",
+	"filename is required":                      "filename es obligatorio",
+	"Failed to list media files: %v":            "No se pudieron listar los archivos multimedia: %v",
+	"Invalid pattern %q: %v":                    "Patrón no válido %q: %v",
+	"Media files matching %s: showing %d of %d": "Archivos multimedia que coinciden con %s: se muestran %d de %d",
+	"No media files match %s":                   "Ningún archivo multimedia coincide con %s",
+	"%s is still used by %d note(s): %s. Remove it from the notes first, or pass force=true to delete it anyway.": "%s todavía se usa en %d nota(s): %s. Quítalo primero de las notas o pasa force=true para eliminarlo de todos modos.",
+	"Deleted media file %s":                                       "Archivo multimedia %s eliminado",
+	"Failed to delete media file: %v":                             "No se pudo eliminar el archivo multimedia: %v",
+	"Still used by %d note(s), which will show it as missing: %s": "Todavía se usa en %d nota(s), que lo mostrarán como ausente: %s",
+	"delete_media_file was not run. Ask the user to confirm deleting %s, then call again with confirm=true.": "delete_media_file no se ejecutó. Pide al usuario que confirme la eliminación de %s y vuelve a llamar con confirm=true.",
+	"Failed to download %s: %v":         "No se pudo descargar %s: %v",
+	"Failed to verify stored media: %v": "No se pudo verificar el archivo multimedia guardado: %v",
+	"Pass one of path, url or data":     "Indica solo uno de path, url o data",
+	"Reference: %s":                     "Referencia: %s",
+	"filename is required with data":    "filename es obligatorio con data",
+
+	// Text-to-speech
+	"Added to field %s of note %d":         "Añadido al campo %s de la nota %d",
+	"Failed to generate speech: %v":        "No se pudo generar el audio: %v",
+	"Field %s of note %d already plays it": "El campo %s de la nota %d ya lo reproduce",
+	"Generated %s (%d bytes)":              "%s generado (%d bytes)",
+	"Note %d has no field %s. Fields: %s":  "La nota %d no tiene el campo %s. Campos: %s",
+	"Text-to-speech is not available: %v. Configure the tts section of the config file.": "La síntesis de voz no está disponible: %v. Configura la sección tts del archivo de configuración.",
+	"language is required":              "language es obligatorio",
+	"text is longer than %d characters": "text tiene más de %d caracteres",
+
+	// Leeches
+	"(suspended)": "(suspendida)",
+	"Answering takes %.0fs on average. Add a hint, an example or a mnemonic to the question.":           "Responder lleva %.0fs de media. Añade una pista, un ejemplo o una regla mnemotécnica a la pregunta.",
+	"Invalid action %q: use none, suspend or reset":                                                     "Acción %q no válida: usa none, suspend o reset",
+	"Its ease is at the minimum, so it keeps coming back. Reset it after rewording.":                    "Su facilidad está al mínimo, así que vuelve una y otra vez. Restablécela después de reformularla.",
+	"Lapses: %d, reviews: %d, ease: %d%%, %.0fs per answer":                                             "Fallos: %d, repasos: %d, facilidad: %d%%, %.0fs por respuesta",
+	"Leeches: showing %d of %d":                                                                         "Sanguijuelas: se muestran %d de %d",
+	"No leeches found":                                                                                  "No se encontraron sanguijuelas",
+	"Recent answers: %s":                                                                                "Respuestas recientes: %s",
+	"Reset %d card(s) to new and removed their leech tag":                                               "%d tarjeta(s) restablecida(s) como nuevas y etiqueta leech eliminada",
+	"Reset %d card(s), but failed to remove the leech tag: %v":                                          "Se restablecieron %d tarjeta(s), pero no se pudo eliminar la etiqueta leech: %v",
+	"Reword the question so it has one unambiguous answer, or add context such as an example sentence.": "Reformula la pregunta para que tenga una única respuesta inequívoca, o añade contexto como una frase de ejemplo.",
+	"Suggestion: %s": "Sugerencia: %s",
+	"The answer has %d words. Split it into smaller cards or turn it into a cloze deletion.": "La respuesta tiene %d palabras. Divídela en tarjetas más pequeñas o conviértela en un texto con huecos.",
+
+	// Dry run
+	"Dry run: this call would make %d change(s)": "Simulación: esta llamada haría %d cambio(s)",
+	"Dry run: nothing was changed. The call stopped with this error after planning %d change(s), so there is no complete list of its changes.": "Simulación: no se cambió nada. La llamada se detuvo con este error tras planificar %d cambio(s), así que no hay una lista completa de sus cambios.",
+	"Nothing was changed. Call the tool again without dry_run to make these changes.":                                                          "No se cambió nada. Vuelve a llamar a la herramienta sin dry_run para hacer estos cambios.",
+	"Nothing was changed: the server runs in dry-run mode (ANKI_MCP_DRY_RUN).":                                                                 "No se cambió nada: el servidor funciona en modo de simulación (ANKI_MCP_DRY_RUN).",
+	"Tags: %s":                                        "Etiquetas: %s",
+	"Add a %s note to %s: %s":                         "Añadir una nota %s a %s: %s",
+	"Update note %d: %s":                              "Actualizar la nota %d: %s",
+	"Delete %d note(s) with their cards: %s":          "Eliminar %d nota(s) con sus tarjetas: %s",
+	"Add tags %s to %d note(s)":                       "Añadir las etiquetas %s a %d nota(s)",
+	"Remove tags %s from %d note(s)":                  "Quitar las etiquetas %s de %d nota(s)",
+	"Replace tag %s with %s on %d note(s)":            "Reemplazar la etiqueta %s por %s en %d nota(s)",
+	"Remove unused tags from the tag list":            "Quitar las etiquetas sin usar de la lista de etiquetas",
+	"Create deck %s":                                  "Crear el mazo %s",
+	"Delete deck %s with its cards":                   "Eliminar el mazo %s con sus tarjetas",
+	"Move %d card(s) to deck %s":                      "Mover %d tarjeta(s) al mazo %s",
+	"Suspend %d card(s)":                              "Suspender %d tarjeta(s)",
+	"Unsuspend %d card(s)":                            "Reactivar %d tarjeta(s)",
+	"Make %d card(s) new again":                       "Restablecer %d tarjeta(s) como nuevas",
+	"Put %d card(s) back into relearning":             "Devolver %d tarjeta(s) al reaprendizaje",
+	"Make %d card(s) due in %s day(s)":                "Programar %d tarjeta(s) para dentro de %s día(s)",
+	"Store media file %s (%d bytes)":                  "Guardar el archivo multimedia %s (%d bytes)",
+	"Delete media file %s":                            "Eliminar el archivo multimedia %s",
+	"Create note type %s with fields %s":              "Crear el tipo de nota %s con los campos %s",
+	"Update card templates %s of note type %s":        "Actualizar las plantillas %s del tipo de nota %s",
+	"Replace the styling of note type %s":             "Reemplazar el estilo del tipo de nota %s",
+	"Rename field %s of note type %s to %s":           "Renombrar el campo %s del tipo de nota %s a %s",
+	"Save options preset %s":                          "Guardar el grupo de opciones %s",
+	"Switch deck %s to options preset %d":             "Cambiar el mazo %s al grupo de opciones %d",
+	"Create options preset %s as a copy of preset %d": "Crear el grupo de opciones %s como copia del grupo %d",
+	"Remove options preset %d":                        "Eliminar el grupo de opciones %d",
+	"Add %d review(s) to the review history":          "Añadir %d repaso(s) al historial de repasos",
+	"Export deck %s to %s":                            "Exportar el mazo %s a %s",
+	"Run Check Database":                              "Ejecutar Comprobar base de datos",
+	"Sync the collection with AnkiWeb":                "Sincronizar la colección con AnkiWeb",
+	"Answer the current card with ease %d":            "Responder la tarjeta actual con facilidad %d",
+	"Switch deck %s to the new options preset":        "Cambiar el mazo %s al nuevo grupo de opciones",
+
+	// Search
+	"%d [%s, %s]: %s (tags: %s)":                               "%d [%s, %s]: %s (etiquetas: %s)",
+	"%d [%s, %s]: %s":                                          "%d [%s, %s]: %s",
+	"Failed to search: %v":                                     "Error al buscar: %v",
+	"No notes match %s":                                        "Ninguna nota coincide con %s",
+	"query is required":                                        "query es obligatorio",
+	"Notes matching %s: %d-%d of %d":                           "Notas que coinciden con %s: %d-%d de %d",
+	"No more notes: %s matches %d note(s)":                     "No hay más notas: %s coincide con %d nota(s)",
+	"More notes follow: pass cursor %s for the next page":      "Hay más notas: pasa el cursor %s para la página siguiente",
+	"Pass either offset or cursor, not both":                   "Pasa offset o cursor, no ambos",
+	"Invalid cursor; start again without one":                  "Cursor no válido; empieza de nuevo sin cursor",
+	"The cursor belongs to the search %s; pass the same query": "El cursor pertenece a la búsqueda %s; pasa la misma consulta",
+	"The cursor belongs to a differently sorted search; pass the same sort_by and order": "El cursor pertenece a una búsqueda con otro orden; pasa los mismos sort_by y order",
+	"order must be asc or desc":                          "order debe ser asc o desc",
+	"order needs sort_by":                                "order requiere sort_by",
+	"sort_by must be created, modified, due or interval": "sort_by debe ser created, modified, due o interval",
+
+	// Instances
+	"AnkiConnect instances (%d)":                 "Instancias de AnkiConnect (%d)",
+	"%s (%s): available, AnkiConnect version %d": "%s (%s): disponible, AnkiConnect versión %d",
+	"%s (%s): not available: %s":                 "%s (%s): no disponible: %s",
+	"Unknown instance %s; the instances are %s":  "Instancia desconocida %s; las instancias son %s",
+
+	// Cancellation
+	"Cancelled after changing %d of %d note(s)":                     "Cancelado tras cambiar %d de %d nota(s)",
+	"Not sent, because the request was cancelled: %d":               "No enviadas porque se canceló la solicitud: %d",
+	"Cancelled after storing %d media file(s); no card was created": "Cancelado tras guardar %d archivo(s) multimedia; no se creó ninguna tarjeta",
+
+	// Rate limit
+	"Throttled: this call waited %s for AnkiConnect, which takes only so many requests at once": "Limitado: esta llamada esperó %s a AnkiConnect, que solo acepta cierto número de solicitudes a la vez",
+
+	// Diagnostics
+	"Hint: Start Anki and keep it open. If it is running, check that the AnkiConnect add-on (code 2055492159) is installed and enabled, and that ANKI_CONNECT_URL matches its address.":      "Sugerencia: Inicia Anki y mantenlo abierto. Si ya está en marcha, comprueba que el complemento AnkiConnect (código 2055492159) esté instalado y activado, y que ANKI_CONNECT_URL coincida con su dirección.",
+	"Hint: Anki is running but busy, e.g. with an open dialog, a sync or a large collection. Close open dialogs in Anki, or raise ANKI_CONNECT_TIMEOUT.":                                     "Sugerencia: Anki está en marcha pero ocupado, p. ej. con un diálogo abierto, una sincronización o una colección grande. Cierra los diálogos abiertos en Anki o aumenta ANKI_CONNECT_TIMEOUT.",
+	"Hint: Another program answers at the AnkiConnect address. Install the AnkiConnect add-on (code 2055492159) via Tools > Add-ons > Get Add-ons, restart Anki and check ANKI_CONNECT_URL.": "Sugerencia: Otro programa responde en la dirección de AnkiConnect. Instala el complemento AnkiConnect (código 2055492159) desde Herramientas > Complementos > Obtener complementos, reinicia Anki y comprueba ANKI_CONNECT_URL.",
+	"Hint: The installed AnkiConnect doesn't support this request. Update it via Tools > Add-ons > Check for Updates and restart Anki.":                                                      "Sugerencia: El AnkiConnect instalado no admite esta solicitud. Actualízalo desde Herramientas > Complementos > Buscar actualizaciones y reinicia Anki.",
+	"Hint: The deck doesn't exist. Use get_deck_tree to see the deck names, or create_deck to create it.":                                                                                    "Sugerencia: El mazo no existe. Usa get_deck_tree para ver los nombres de los mazos o create_deck para crearlo.",
+	"Hint: The note type or one of its fields doesn't exist, or the first field is empty. Check the names in Anki under Tools > Manage Note Types > Fields; they are case-sensitive.":        "Sugerencia: El tipo de nota o uno de sus campos no existe, o el primer campo está vacío. Comprueba los nombres en Anki en Herramientas > Administrar tipos de nota > Campos; distinguen mayúsculas y minúsculas.",
+
+	// Health check
+	"AnkiConnect at %s did not answer: %v":                                                      "AnkiConnect en %s no respondió: %v",
+	"AnkiConnect supports API version %d, but this server needs version %d; update AnkiConnect": "AnkiConnect admite la versión %d de la API, pero este servidor necesita la versión %d; actualiza AnkiConnect",
+	"AnkiConnect: %s, API version %d":                                                           "AnkiConnect: %s, versión de la API %d",
+	"Could not get the active profile: %s":                                                      "No se pudo obtener el perfil activo: %s",
+	"Could not get the decks: %s":                                                               "No se pudieron obtener los mazos: %s",
+	"Could not get the media folder: %s":                                                        "No se pudo obtener la carpeta multimedia: %s",
+	"Could not get the note types: %s":                                                          "No se pudieron obtener los tipos de nota: %s",
+	"Decks: %d":                                                                                 "Mazos: %d",
+	"Failed to check the collection: %v":                                                        "No se pudo comprobar la colección: %v",
+	"Health check: %d problem(s)":                                                               "Comprobación de estado: %d problema(s)",
+	"Health check: OK":                                                                          "Comprobación de estado: correcto",
+	"Latency: %s":                                                                               "Latencia: %s",
+	"Media folder: %s":                                                                          "Carpeta multimedia: %s",
+	"Note types: %d":                                                                            "Tipos de nota: %d",
+	"Problem: %s":                                                                               "Problema: %s",
+	"Profile: %s":                                                                               "Perfil: %s",
+
+	// GUI
+	"Failed to open the card browser: %v":                                                                           "No se pudo abrir el explorador de tarjetas: %v",
+	"Opened Anki's card browser with %d card(s) for: %s":                                                            "Se abrió el explorador de tarjetas de Anki con %d tarjeta(s) para: %s",
+	"Opened Anki's card browser, but no cards match: %s":                                                            "Se abrió el explorador de tarjetas de Anki, pero ninguna tarjeta coincide con: %s",
+	"Pass exactly one of card_ids, note_ids or query":                                                               "Indica exactamente uno de card_ids, note_ids o query",
+	"Failed to open the Add dialog: %v":                                                                             "No se pudo abrir el diálogo Añadir: %v",
+	"Opened Anki's Add dialog with a %s card for deck %s. The card is only added once the user clicks Add in Anki.": "Se abrió el diálogo Añadir de Anki con una tarjeta %s para el mazo %s. La tarjeta solo se añade cuando el usuario pulsa Añadir en Anki.",
+	"front and back or fields are required":                                                                         "se requieren front y back o fields",
+
+	// Undo
+	"Anki has nothing to undo": "Anki no tiene nada que deshacer",
+	"Failed to undo: %v":       "No se pudo deshacer: %v",
+	"No change made through this server is left to undo, so the operation undone was made in Anki itself.": "No queda ningún cambio hecho a través de este servidor por deshacer, así que la operación deshecha se hizo en el propio Anki.",
+	"That change was undone, unless the user changed something in Anki after it.":                          "Ese cambio se deshizo, salvo que el usuario haya cambiado algo en Anki después.",
+	"The last change made through this server, %s ago, was: %s":                                            "El último cambio hecho a través de este servidor, hace %s, fue: %s",
+	"Undid the last operation in Anki.":                                                                    "Se deshizo la última operación en Anki.",
+	"Undo the last operation in Anki":                                                                      "Deshacer la última operación en Anki",
+
+	// Change note type
+	"%s and %s are not both cloze or both standard note types, so their cards don't correspond":           "%s y %s no son ambos tipos de nota cloze o ambos estándar, así que sus tarjetas no se corresponden",
+	"%s and %s have no field in common; pass field_map. Fields of %s: %s":                                 "%s y %s no tienen ningún campo en común; indica field_map. Campos de %s: %s",
+	"%s has %d card types but %s only %d; cards of the other card types would be left without a template": "%s tiene %d tipos de tarjeta pero %s solo %d; las tarjetas de los demás tipos quedarían sin plantilla",
+	"Change note %d to note type %s: %s":                                                                  "Cambiar la nota %d al tipo de nota %s: %s",
+	"Changed %d of %d note(s) from %s to %s":                                                              "Se cambiaron %d de %d nota(s) de %s a %s",
+	"Content discarded: %s":                                                                               "Contenido descartado: %s",
+	"Fields: %s":                                                                                          "Campos: %s",
+	"Left empty: %s":                                                                                      "Quedan vacíos: %s",
+	"Note %d failed: %v":                                                                                  "Falló la nota %d: %v",
+	"The content of field(s) %s would be lost. Map them in field_map, or pass discard_unmapped=true if the user agrees to lose it.": "Se perdería el contenido de los campos %s. Asígnalos en field_map o indica discard_unmapped=true si el usuario acepta perderlo.",
+	"The notes already use note type %s":                                                   "Las notas ya usan el tipo de nota %s",
+	"The notes use different note types (%s); change the notes of one note type at a time": "Las notas usan tipos de nota distintos (%s); cambia las notas de un tipo de nota a la vez",
+	"field_map: %s all map to %s":                                                          "field_map: %s se asignan todos a %s",
+	"field_map: %s must map to the name of a field of %s":                                  "field_map: %s debe asignarse al nombre de un campo de %s",
+	"field_map: note type %s has no field %s. Fields: %s":                                  "field_map: el tipo de nota %s no tiene el campo %s. Campos: %s",
+	"new_model is required":                                                                "new_model es obligatorio",
+
+	// Find and replace in models
+	"%q was not found in the chosen templates; nothing was changed": "No se encontró %q en las plantillas elegidas; no se cambió nada",
+	"%s back":  "%s reverso",
+	"%s front": "%s anverso",
+	"After the replacement, %s of note type %s would refer to unknown field(s): %s. Fields: %s": "Tras el reemplazo, %s del tipo de nota %s haría referencia a campos desconocidos: %s. Campos: %s",
+	"Failed to replace: %v": "No se pudo reemplazar: %v",
+	"Replace %q with %q in the templates and styling of every note type": "Reemplazar %q por %q en las plantillas y el estilo de todos los tipos de nota",
+	"Replace %q with %q in the templates and styling of note type %s":    "Reemplazar %q por %q en las plantillas y el estilo del tipo de nota %s",
+	"Replaced %q with %q in %d note type(s)":                             "Se reemplazó %q por %q en %d tipo(s) de nota",
+	"find and replace are the same":                                      "find y replace son iguales",
+	"find is required":                                                   "find es obligatorio",
+	"front, back and css are all false, so there is nothing to search":   "front, back y css son todos false, así que no hay nada donde buscar",
+	"replace is required":                                                "replace es obligatorio",
+	"styling":                                                            "estilo",
+
+	// Deck tree
+	"%s: %d card(s), %d in the deck itself; due today: %d new, %d learning, %d review": "%s: %d tarjeta(s), %d en el propio mazo; para hoy: %d nuevas, %d en aprendizaje, %d de repaso",
+	"%s: %d card(s); due today: %d new, %d learning, %d review":                        "%s: %d tarjeta(s); para hoy: %d nuevas, %d en aprendizaje, %d de repaso",
+	"Failed to get deck stats: %v":                                                     "No se pudieron obtener las estadísticas del mazo: %v",
+
+	// Retention
+	"Again rate, %s: %.1f%% of %d answers":                                        "Tasa de «Otra vez», %s: %.1f%% de %d respuestas",
+	"Interval growth: a passed review multiplied the interval by %.2f on average": "Crecimiento del intervalo: un repaso superado multiplicó el intervalo por %.2f de media",
+	"No cards were studied in this period.":                                       "No se estudió ninguna tarjeta en este periodo.",
+	"Retention for %s from %s to %s":                                              "Retención de %s del %s al %s",
+	"True retention: %.1f%% of %d review answers":                                 "Retención real: %.1f%% de %d respuestas de repaso",
+	"True retention: no reviews of graduated cards in this period":                "Retención real: no hubo repasos de tarjetas graduadas en este periodo",
+	"learning and relearning":                                                     "aprendizaje y reaprendizaje",
+	"mature cards":                                                                "tarjetas maduras",
+	"tag %s":                                                                      "etiqueta %s",
+	"young cards (interval under %d days)":                                        "tarjetas jóvenes (intervalo de menos de %d días)",
+
+	// Review forecast
+	"Already overdue: %d":                          "Ya atrasadas: %d",
+	"Busiest day: %s with %d review(s)":            "Día con más carga: %s con %d repaso(s)",
+	"Review forecast for %s, next %d day(s)":       "Previsión de repasos de %s, próximos %d día(s)",
+	"Today (%s): %d":                               "Hoy (%s): %d",
+	"Total: %d review(s), %.1f per day on average": "Total: %d repaso(s), %.1f al día de media",
+	"days must be between 1 and %d":                "days debe estar entre 1 y %d",
+
+	// Maturity report
+	"Maturity of %s: %d card(s)": "Madurez de %s: %d tarjeta(s)",
+	"New":                        "Nuevas",
+	"Learning":                   "En aprendizaje",
+	"Young":                      "Jóvenes",
+	"Mature":                     "Maduras",
+	"Suspended":                  "Suspendidas",
+	"Young cards have an interval under %d days, mature cards one of %d days or more.": "Las tarjetas jóvenes tienen un intervalo de menos de %d días, las maduras uno de %d días o más.",
+
+	// Markdown export
+	"Exported %d note(s) to %s": "%d nota(s) exportada(s) a %s",
+
+	// Query validation
+	"A quotation mark is never closed":                                        "Unas comillas nunca se cierran",
+	"The closing parenthesis at position %d has no opening one":               "El paréntesis de cierre en la posición %d no tiene uno de apertura",
+	"%d parenthesis(es) are never closed":                                     "%d paréntesis nunca se cierra(n)",
+	"Anki doesn't support %s; use %s":                                         "Anki no admite %s; usa %s",
+	"Anki doesn't support %s; put - before a term to exclude it":              "Anki no admite %s; pon - delante de un término para excluirlo",
+	"%s: search keywords are followed by a colon, not =":                      "%s: las palabras clave de búsqueda van seguidas de dos puntos, no de =",
+	"Unknown state is:%s. Known states: %s":                                   "Estado desconocido is:%s. Estados conocidos: %s",
+	"%s: flag: takes a number from 0 (no flag) to 7":                          "%s: flag: admite un número de 0 (sin marca) a 7",
+	"%s: prop: needs a property, a comparison and a value, e.g. prop:ivl>=10": "%s: prop: necesita una propiedad, una comparación y un valor, p. ej. prop:ivl>=10",
+	"Unknown property %s. Known properties: %s":                               "Propiedad desconocida %s. Propiedades conocidas: %s",
+	"%s: %s: takes a number of days, e.g. %s:7":                               "%s: %s: admite un número de días, p. ej. %s:7",
+	"Deck names with spaces must be quoted: %s":                               "Los nombres de mazo con espacios deben ir entre comillas: %s",
+	"No deck named %s; did you mean %s?":                                      "No hay ningún mazo llamado %s; ¿quisiste decir %s?",
+	"No deck named %s":                                                        "No hay ningún mazo llamado %s",
+	"No note type named %s; did you mean %s?":                                 "No hay ningún tipo de nota llamado %s; ¿quisiste decir %s?",
+	"No note type named %s":                                                   "No hay ningún tipo de nota llamado %s",
+	"Unknown prefix %s:; did you mean %s:?":                                   "Prefijo desconocido %s:; ¿quisiste decir %s:?",
+	"%s: is neither a search keyword nor a field name, so the term matches nothing; write \\: to search for a colon": "%s: no es ni una palabra clave de búsqueda ni un nombre de campo, así que el término no encuentra nada; escribe \\: para buscar dos puntos",
+	"Query: %s":                                 "Consulta: %s",
+	"No problems found.":                        "No se encontraron problemas.",
+	"Anki rejected the query: %s":               "Anki rechazó la consulta: %s",
+	"The query matches %d card(s).":             "La consulta coincide con %d tarjeta(s).",
+	"Suggested query: %s":                       "Consulta sugerida: %s",
+	"Anki rejected the suggested query too: %s": "Anki también rechazó la consulta sugerida: %s",
+	"The suggested query matches %d card(s).":   "La consulta sugerida coincide con %d tarjeta(s).",
+
+	// Image occlusion
+	"Invalid hide %q: use all or one": "hide %q no válido: usa all o one",
+	"No image occlusion note type found. Use Anki 23.10 or later, install the Image Occlusion Enhanced add-on, or pass model_name.": "No se encontró ningún tipo de nota de oclusión de imagen. Usa Anki 23.10 o posterior, instala el complemento Image Occlusion Enhanced o indica model_name.",
+	"Note type %s is not an image occlusion note type":                          "El tipo de nota %s no es de oclusión de imagen",
+	"Pass either image_path or image_url, not both":                             "Indica image_path o image_url, no ambos",
+	"image_path or image_url is required":                                       "Se requiere image_path o image_url",
+	"Invalid occlusions: %v":                                                    "Oclusiones no válidas: %v",
+	"Image Occlusion Enhanced needs a PNG, JPEG or GIF image to draw its masks": "Image Occlusion Enhanced necesita una imagen PNG, JPEG o GIF para dibujar sus máscaras",
+	"Failed to store mask: %v":                                                  "No se pudo guardar la máscara: %v",
+	"Cancelled after creating %d of %d note(s)":                                 "Cancelado tras crear %d de %d nota(s)",
+	"Created image occlusion note (ID: %d) with %d card(s)":                     "Nota de oclusión de imagen creada (ID: %d) con %d tarjeta(s)",
+	"Created %d image occlusion note(s) with %s: %s":                            "%d nota(s) de oclusión de imagen creada(s) con %s: %s",
+	"Image: %s, %d rectangle(s)":                                                "Imagen: %s, %d rectángulo(s)",
+
+	// Math cards
+	"Invalid formula on the front: %v":         "Fórmula no válida en el anverso: %v",
+	"Invalid formula on the back: %v":          "Fórmula no válida en el reverso: %v",
+	"Preview: %d formula(s), not created":      "Vista previa: %d fórmula(s), no creada",
+	"Front: %s":                                "Anverso: %s",
+	"Back: %s":                                 "Reverso: %s",
+	"Note type %s needs at least two fields":   "El tipo de nota %s necesita al menos dos campos",
+	"Created card (ID: %d) with %d formula(s)": "Tarjeta creada (ID: %d) con %d fórmula(s)",
+	"LaTeX formulas are rendered by Anki's LaTeX support, which needs LaTeX installed on the computer.": "Las fórmulas LaTeX las genera el soporte LaTeX de Anki, que necesita LaTeX instalado en el ordenador.",
+	"Check these brackets; they are fine in intervals like [0, 1)":                                      "Revisa estos corchetes; son correctos en intervalos como [0, 1)",
+
+	// Image shrinking
+	"max_dimension must be between 1 and %d":              "max_dimension debe estar entre 1 y %d",
+	"quality must be between 1 and 100":                   "quality debe estar entre 1 y 100",
+	"%s stored as it was: %s":                             "%s guardado sin cambios: %s",
+	"%s shrunk from %dx%d (%d bytes) to %dx%d (%d bytes)": "%s reducido de %dx%d (%d bytes) a %dx%d (%d bytes)",
+
+	// Unused media
+	"%d unused media file(s) of %d: showing %d":                                                 "%d archivo(s) multimedia sin usar de %d: se muestran %d",
+	"All %d media file(s) matching %s are used by notes":                                        "Los %d archivo(s) multimedia que coinciden con %s se usan en notas",
+	"Anki moved the files to its media trash, from where Tools > Check Media can restore them.": "Anki movió los archivos a su papelera multimedia, desde donde Herramientas > Comprobar multimedia puede restaurarlos.",
+	"Call again with delete=true and confirm=true to delete them, once the user has agreed.":    "Vuelve a llamar con delete=true y confirm=true para eliminarlos, una vez que el usuario esté de acuerdo.",
+	"Deleted %d unused media file(s) of %d: showing %d":                                         "Se eliminaron %d archivo(s) multimedia sin usar de %d: se muestran %d",
+	"Failed to delete media files: %v":                                                          "No se pudieron eliminar los archivos multimedia: %v",
+	"Skipped %d file(s) starting with _, which Anki keeps for note types":                       "Se omitieron %d archivo(s) que empiezan por _, que Anki conserva para los tipos de nota",
+	"find_unused_media deleted nothing. Ask the user to confirm deleting the unused media files, then call again with delete=true and confirm=true.": "find_unused_media no eliminó nada. Pide al usuario que confirme la eliminación de los archivos multimedia sin usar y vuelve a llamar con delete=true y confirm=true.",
+
+	// Media references
+	"%d missing media file(s) referred to by %d of %d note(s)": "%d archivo(s) multimedia faltante(s) referidos por %d de %d nota(s)",
+	"%s: note(s) %s": "%s: nota(s) %s",
+	"All media files referred to by the %d note(s) checked exist": "Existen todos los archivos multimedia referidos por las %d nota(s) comprobadas",
+	"Failed to check media references: %v":                        "No se pudieron comprobar las referencias multimedia: %v",
+	"Search for the affected notes with: nid:%s":                  "Busca las notas afectadas con: nid:%s",
+
+	// Card recipes
+	"(given by each call)":                       "(lo indica cada llamada)",
+	"Created card (ID: %d) from recipe %s in %s": "Tarjeta creada (ID: %d) con la receta %s en %s",
+	"Deck: %s":                 "Mazo: %s",
+	"Image in %s, audio in %s": "Imagen en %s, audio en %s",
+	"No recipes configured. Add recipes to the config file: %s": "No hay recetas configuradas. Añade recetas al archivo de configuración: %s",
+	"Note type: %s":       "Tipo de nota: %s",
+	"Optional inputs: %s": "Entradas opcionales: %s",
+	"Recipe %s has no deck, so deck is required":                      "La receta %s no tiene mazo, así que deck es obligatorio",
+	"Recipe %s needs the input(s): %s":                                "La receta %s necesita la(s) entrada(s): %s",
+	"Recipe %s uses unknown field(s) of note type %s: %s. Fields: %s": "La receta %s usa campo(s) desconocido(s) del tipo de nota %s: %s. Campos: %s",
+	"Recipe not found: %s. Recipes: %s":                               "Receta no encontrada: %s. Recetas: %s",
+	"Required inputs: %s":                                             "Entradas obligatorias: %s",
+	"Unknown input(s) for recipe %s: %s. Inputs: %s":                  "Entrada(s) desconocida(s) para la receta %s: %s. Entradas: %s",
+	"Value %s must be a string":                                       "El valor %s debe ser una cadena",
+	"the first field":                                                 "el primer campo",
+
+	// Vocabulary cards
+	"%s → %s": "%s → %s",
+	"Created vocabulary note (ID: %d) with the note type %s":                                                                                    "Nota de vocabulario creada (ID: %d) con el tipo de nota %s",
+	"Note type %s decides itself which cards it makes. Set reverse_field in the vocab section of the config file to choose with bidirectional.": "El tipo de nota %s decide por sí mismo qué tarjetas crea. Define reverse_field en la sección vocab del archivo de configuración para elegir con bidirectional.",
+	"The vocab note type %s has no field for %s":                                                                                                "El tipo de nota de vocabulario %s no tiene campo para %s",
+	"word and translation are required":                                                                                                         "word y translation son obligatorios",
+}
@@ -0,0 +1,768 @@
+package main
+
+// messagesFR holds the French translations of tool output
+var messagesFR = map[string]string{
+	// Common
+	"Error: %s":                    "Erreur : %s",
+	"all decks":                    "tous les paquets",
+	"card_id is required":          "card_id est obligatoire",
+	"data or path is required":     "data ou path est obligatoire",
+	"unsupported format: %s":       "format non pris en charge : %s",
+	"Failed to find cards: %v":     "Impossible de rechercher les cartes : %v",
+	"Failed to get card info: %v":  "Impossible d'obtenir les informations des cartes : %v",
+	"Failed to get note info: %v":  "Impossible d'obtenir les informations des notes : %v",
+	"Failed to read %s: %v":        "Impossible de lire %s : %v",
+	"Failed to write %s: %v":       "Impossible d'écrire %s : %v",
+	"No cards found for query: %s": "Aucune carte trouvée pour la recherche : %s",
+	"%.0f minute(s)":               "%.0f minute(s)",
+	"%.1f hour(s)":                 "%.1f heure(s)",
+	"%.0f day(s)":                  "%.0f jour(s)",
+	"%.1f month(s)":                "%.1f mois",
+	"%.1f year(s)":                 "%.1f an(s)",
+
+	// Cards and decks
+	"deck is required":                  "deck est obligatoire",
+	"front is required":                 "front est obligatoire",
+	"back is required":                  "back est obligatoire",
+	"name is required":                  "name est obligatoire",
+	"Failed to read image file: %v":     "Impossible de lire le fichier image : %v",
+	"Failed to store image: %v":         "Impossible d'enregistrer l'image : %v",
+	"Failed to create card: %v":         "Impossible de créer la carte : %v",
+	"Created card (ID: %d)":             "Carte créée (ID : %d)",
+	"Failed to get decks: %v":           "Impossible d'obtenir les paquets : %v",
+	"Decks (%d)":                        "Paquets (%d)",
+	"Failed to create deck: %v":         "Impossible de créer le paquet : %v",
+	"Created deck: %s":                  "Paquet créé : %s",
+	"Failed to verify stored image: %v": "Impossible de vérifier l'image enregistrée : %v",
+	"Stored media":                      "Médias enregistrés",
+	"%s (%d bytes, verified)":           "%s (%d octets, vérifié)",
+	"%s (renamed from %s to avoid overwriting an existing file, %d bytes, verified)": "%s (renommé depuis %s pour ne pas écraser un fichier existant, %d octets, vérifié)",
+	"tag is required":                                                            "tag est obligatoire",
+	"Failed to suspend cards: %v":                                                "Impossible de suspendre les cartes : %v",
+	"Failed to unsuspend cards: %v":                                              "Impossible de réactiver les cartes : %v",
+	"No unsuspended cards found with tag %s":                                     "Aucune carte non suspendue trouvée avec l'étiquette %s",
+	"No suspended cards found with tag %s":                                       "Aucune carte suspendue trouvée avec l'étiquette %s",
+	"Suspended %d card(s) with tag %s":                                           "%d carte(s) avec l'étiquette %s suspendue(s)",
+	"Unsuspended %d card(s) with tag %s":                                         "%d carte(s) avec l'étiquette %s réactivée(s)",
+	"Failed to create note type %s: %v":                                          "Échec de la création du type de note %s : %v",
+	"Added a reverse card (back → front) with the note type %s":                  "Carte inverse (verso → recto) ajoutée avec le type de note %s",
+	"Created the missing note type %s":                                           "Type de note manquant %s créé",
+	"cards is required":                                                          "cards est obligatoire",
+	"front and back are required":                                                "front et back sont obligatoires",
+	"Created %d of %d card(s)":                                                   "%d carte(s) sur %d créée(s)",
+	"#%d failed: %s":                                                             "#%d a échoué : %s",
+	"#%d: note %d in %s":                                                         "#%d : note %d dans %s",
+	"Field %s must be a string":                                                  "Le champ %s doit être une chaîne",
+	"reversed can't be combined with the note type %s":                           "reversed ne peut pas être combiné avec le type de note %s",
+	"Unknown field(s) for note type %s: %s. Fields: %s":                          "Champ(s) inconnu(s) pour le type de note %s : %s. Champs : %s",
+	"The first field of note type %s must not be empty":                          "Le premier champ du type de note %s ne doit pas être vide",
+	"The Default deck can't be deleted":                                          "Le paquet Default ne peut pas être supprimé",
+	"Would delete deck %s with %d subdeck(s) and %d card(s)":                     "Supprimerait le paquet %s avec %d sous-paquet(s) et %d carte(s)",
+	"Would delete deck %s with %d subdeck(s) and move %d card(s) to %s":          "Supprimerait le paquet %s avec %d sous-paquet(s) et déplacerait %d carte(s) vers %s",
+	"Failed to delete deck: %v":                                                  "Échec de la suppression du paquet : %v",
+	"Deleted deck %s with %d subdeck(s) and %d card(s)":                          "Paquet %s supprimé avec %d sous-paquet(s) et %d carte(s)",
+	"Deleted deck %s with %d subdeck(s) and moved %d card(s) to %s":              "Paquet %s supprimé avec %d sous-paquet(s) et %d carte(s) déplacée(s) vers %s",
+	"Pass either new_name or parent, not both":                                   "Indiquez new_name ou parent, pas les deux",
+	"new_name or parent is required":                                             "new_name ou parent est obligatoire",
+	"The Default deck can't be renamed":                                          "Le paquet Default ne peut pas être renommé",
+	"The deck is already named %s":                                               "Le paquet s'appelle déjà %s",
+	"A deck can't be moved into its own subdeck":                                 "Un paquet ne peut pas être déplacé dans son propre sous-paquet",
+	"Deck already exists: %s":                                                    "Le paquet existe déjà : %s",
+	"Moved %d card(s) to %s, but %d card(s) remain in %s, so it was not deleted": "%d carte(s) déplacée(s) vers %s, mais %d carte(s) restent dans %s, il n'a donc pas été supprimé",
+	"Renamed deck %s to %s":                                                      "Paquet %s renommé en %s",
+	"%d card(s) of %s are in %s (card IDs: %s)":                                  "%d carte(s) de %s sont dans %s (ID de cartes : %s)",
+	"%s. Moving the cards back failed as well, so the rename is half done: %s":   "%s. Le retour des cartes a également échoué, le renommage n'est donc qu'à moitié fait : %s",
+	"%s. Nothing was renamed: the cards moved so far were moved back":            "%s. Rien n'a été renommé : les cartes déplacées jusqu'ici ont été remises en place",
+	"Would rename deck %s to %s":                                                 "Renommerait le paquet %s en %s",
+	"Subdecks to move: %d":                                                       "Sous-paquets à déplacer : %d",
+	"Cards to move: %d":                                                          "Cartes à déplacer : %d",
+	"Subdecks moved: %d":                                                         "Sous-paquets déplacés : %d",
+	"Cards moved: %d":                                                            "Cartes déplacées : %d",
+	"Pass either card_ids or query, not both":                                    "Indiquez card_ids ou query, pas les deux",
+	"card_ids or query is required":                                              "card_ids ou query est obligatoire",
+	"No cards found to move":                                                     "Aucune carte à déplacer trouvée",
+	"Moved %d card(s) to %s":                                                     "%d carte(s) déplacée(s) vers %s",
+	"Already in the deck: %d":                                                    "Déjà dans le paquet : %d",
+	"Cards not found: %d":                                                        "Cartes introuvables : %d",
+	"No cards found":                                                             "Aucune carte trouvée",
+	"Suspended %d card(s)":                                                       "%d carte(s) suspendue(s)",
+	"Already suspended: %d":                                                      "Déjà suspendues : %d",
+	"Unsuspended %d card(s)":                                                     "%d carte(s) réactivée(s)",
+	"Not suspended: %d":                                                          "Non suspendues : %d",
+	"Exported deck %s to %s":                                                     "Paquet %s exporté vers %s",
+	"Would export deck %s to %s":                                                 "Exporterait le paquet %s vers %s",
+	"Would export deck %s and return it as %s":                                   "Exporterait le paquet %s et le renverrait sous le nom %s",
+	"Failed to create temporary directory: %v":                                   "Impossible de créer le répertoire temporaire : %v",
+	"Failed to export deck: %v":                                                  "Impossible d'exporter le paquet : %v",
+	"Failed to read the exported file; Anki has to run on the same machine to return the file, otherwise pass path: %v": "Impossible de lire le fichier exporté ; Anki doit tourner sur la même machine pour renvoyer le fichier, sinon indiquez path : %v",
+	"path must end in .apkg":                             "path doit se terminer par .apkg",
+	"Invalid duplicate_scope %q: use collection or deck": "duplicate_scope %q non valide : utilisez collection ou deck",
+	"duplicate_scope_deck needs duplicate_scope deck":    "duplicate_scope_deck nécessite duplicate_scope deck",
+	"Already new: %d":                                    "Déjà nouvelles : %d",
+	"Failed to relearn cards: %v":                        "Impossible de remettre les cartes en réapprentissage : %v",
+	"Failed to reset cards: %v":                          "Impossible de réinitialiser les cartes : %v",
+	"Relearning %d card(s)":                              "%d carte(s) en réapprentissage",
+	"Reset %d card(s) to new":                            "%d carte(s) réinitialisée(s) comme nouvelles",
+	"Would relearn %d card(s)":                           "%d carte(s) seraient remises en réapprentissage",
+	"Would reset %d card(s) to new":                      "%d carte(s) seraient réinitialisée(s) comme nouvelles",
+	"Failed to reschedule cards: %v":                     "Impossible de replanifier les cartes : %v",
+	"Rescheduled %d card(s) to %s":                       "%d carte(s) replanifiée(s) au %s",
+	"Spread %d card(s) between %s and %s":                "%d carte(s) réparties entre le %s et le %s",
+	"date is required":                                   "date est obligatoire",
+	"until must not be before date":                      "until ne doit pas précéder date",
+
+	// Maintenance
+	"check_database was not run. It can take several minutes on large collections and blocks Anki while running. Ask the user to explicitly confirm, then call again with confirm=true.": "check_database n'a pas été exécuté. La vérification peut prendre plusieurs minutes sur les grandes collections et bloque Anki pendant son exécution. Demandez une confirmation explicite à l'utilisateur, puis rappelez l'outil avec confirm=true.",
+	"Failed to check database: %v":    "Impossible de vérifier la base de données : %v",
+	"Failed to clear unused tags: %v": "Impossible de supprimer les étiquettes inutilisées : %v",
+	"No unused tags found":            "Aucune étiquette inutilisée trouvée",
+	"Removed %d unused tag(s)":        "%d étiquette(s) inutilisée(s) supprimée(s)",
+	"When it is done, Anki shows in its window whether the collection needed repair and what it fixed.": "Une fois terminée, Anki indique dans sa fenêtre si la collection avait besoin d'être réparée et ce qui a été corrigé.",
+	"Started Anki's database check. It runs in the background and may still be running.":                "La vérification de la base de données d'Anki a démarré. Elle s'exécute en arrière-plan et n'est peut-être pas encore terminée.",
+
+	// Statistics
+	"Tag statistics for %s (%d of %d tags, sorted by %s)":              "Statistiques par étiquette pour %s (%d sur %d étiquettes, triées par %s)",
+	"%s: %d notes, %d cards, %d due, avg ease %.0f%%, avg lapses %.2f": "%s : %d notes, %d cartes, %d à réviser, facilité moyenne %.0f%%, oublis moyens %.2f",
+	"Failed to get review log: %v":                                     "Impossible d'obtenir l'historique des révisions : %v",
+	"Progress for %s from %s to %s":                                    "Progression pour %s du %s au %s",
+	"Reviews done: %d (%d distinct cards)":                             "Révisions effectuées : %d (%d cartes distinctes)",
+	"Cards learned: %d":                                                "Cartes apprises : %d",
+	"Cards matured: %d":                                                "Cartes devenues matures : %d",
+	"Mature cards: %d -> %d":                                           "Cartes matures : %d -> %d",
+	"Retention: %.1f%% of %d review answers":                           "Rétention : %.1f%% sur %d réponses de révision",
+	"Retention: no reviews of graduated cards in this period":          "Rétention : aucune révision de carte apprise sur cette période",
+	"Study time: %s":                                                   "Temps d'étude : %s",
+	"Average ease: %.0f%%":                                             "Facilité moyenne : %.0f%%",
+	"Average interval: %s":                                             "Intervalle moyen : %s",
+	"Cards: %d in %d note(s)":                                          "Cartes : %d dans %d note(s)",
+	"Collection statistics":                                            "Statistiques de la collection",
+	"Could not read any metrics from Anki's statistics report. They are only recognized when Anki's interface language is English.": "Impossible de lire les statistiques du rapport d'Anki. Elles ne sont reconnues que lorsque l'interface d'Anki est en anglais.",
+	"Failed to get collection statistics: %v":             "Impossible de récupérer les statistiques de la collection : %v",
+	"Mature cards: %d (%.1f%%)":                           "Cartes matures : %d (%.1f%%)",
+	"Retention over the last month: %.1f%% of %d answers": "Rétention sur le dernier mois : %.1f%% de %d réponses",
+	"Studied today: %d card(s) in %s":                     "Étudié aujourd'hui : %d carte(s) en %s",
+	"%s: %d review(s)":                                    "%s : %d révision(s)",
+	"Average per day studied: %.1f reviews":               "Moyenne par jour d'étude : %.1f révisions",
+	"Days studied: %d of %d":                              "Jours d'étude : %d sur %d",
+	"Failed to get review history: %v":                    "Impossible de récupérer l'historique des révisions : %v",
+	"Review history from %s to %s":                        "Historique des révisions du %s au %s",
+	"Reviews: %d":                                         "Révisions : %d",
+	"Reviews per day":                                     "Révisions par jour",
+	"Reviews per month":                                   "Révisions par mois",
+	"Reviews per week":                                    "Révisions par semaine",
+	"Week of %s":                                          "Semaine du %s",
+
+	// Review log
+	"Failed to get reviews: %v":                       "Impossible d'obtenir les révisions : %v",
+	"Failed to write CSV: %v":                         "Impossible d'écrire le CSV : %v",
+	"Exported %d reviews of %d cards to %s":           "%d révisions de %d cartes exportées vers %s",
+	"Failed to parse reviews: %v":                     "Impossible d'analyser les révisions : %v",
+	"no reviews found in input":                       "aucune révision trouvée dans l'entrée",
+	"Failed to import reviews: %v":                    "Impossible d'importer les révisions : %v",
+	"Imported %d reviews for %d cards":                "%d révisions importées pour %d cartes",
+	"%s: %s (%s), interval %s → %s, ease %d%%, %.0fs": "%s : %s (%s), intervalle %s → %s, facilité %d%%, %.0fs",
+	"%s: rescheduled to %s":                           "%s : reprogrammée à %s",
+	"filtered deck":                                   "paquet filtré",
+
+	// Card explanation
+	"Card %d not found":                 "Carte %d introuvable",
+	"Card %d in deck %q (note type %s)": "Carte %d du paquet %q (type de note %s)",
+	"If you answered it now":            "Si vous y répondiez maintenant",
+	"State: suspended. It will not be shown until it is unsuspended.":                      "État : suspendue. Elle ne sera plus présentée tant qu'elle ne sera pas réactivée.",
+	"State: buried. It will return to its queue tomorrow (or when unburied).":              "État : enfouie. Elle reviendra dans sa file demain (ou une fois déterrée).",
+	"State: new. It has never been studied and is waiting in the new card queue.":          "État : nouvelle. Elle n'a jamais été étudiée et attend dans la file des nouvelles cartes.",
+	"State: relearning. It was forgotten and is going through the relearning steps.":       "État : réapprentissage. Elle a été oubliée et passe par les étapes de réapprentissage.",
+	"State: learning. It is going through the initial learning steps.":                     "État : apprentissage. Elle passe par les premières étapes d'apprentissage.",
+	"State: review. It has graduated from learning and is shown at growing intervals.":     "État : révision. Elle a terminé l'apprentissage et revient à intervalles croissants.",
+	"State: preview in a filtered deck.":                                                   "État : aperçu dans un paquet filtré.",
+	"Current interval: %s.":                                                                "Intervalle actuel : %s.",
+	"Ease: %.0f%%. Good multiplies the interval by roughly this factor.":                   "Facilité : %.0f%%. « Correct » multiplie l'intervalle par environ ce facteur.",
+	"Due: %s (learning step).":                                                             "À réviser : %s (étape d'apprentissage).",
+	"Position in the new card queue: %d.":                                                  "Position dans la file des nouvelles cartes : %d.",
+	"Due: today (%s).":                                                                     "À réviser : aujourd'hui (%s).",
+	"Due: overdue by %d day(s) (was due %s).":                                              "À réviser : en retard de %d jour(s) (prévue le %s).",
+	"Due: in %d day(s) (%s).":                                                              "À réviser : dans %d jour(s) (%s).",
+	"Reviews: %d, lapses: %d.":                                                             "Révisions : %d, oublis : %d.",
+	"It has reached the leech threshold of %d lapses; consider rewording or splitting it.": "Elle a atteint le seuil de sangsue de %d oublis ; envisagez de la reformuler ou de la scinder.",
+	"It becomes a leech after %d more lapse(s).":                                           "Elle deviendra une sangsue après %d oubli(s) de plus.",
+	"Again":                                  "À revoir",
+	"Hard":                                   "Difficile",
+	"Good":                                   "Correct",
+	"Easy":                                   "Facile",
+	"graduates to review, next shown in %s":  "passe en révision, prochaine présentation dans %s",
+	"shown again in %s":                      "présentée à nouveau dans %s",
+	"%s (back to the first learning step)":   "%s (retour à la première étape d'apprentissage)",
+	"%s (back to the first relearning step)": "%s (retour à la première étape de réapprentissage)",
+	"returns to review, next shown in %s":    "revient en révision, prochaine présentation dans %s",
+	"counts as a lapse: ease drops to %.0f%%, %s, then the interval restarts at %s": "compte comme un oubli : la facilité tombe à %.0f%%, %s, puis l'intervalle repart à %s",
+	"next shown in %s, ease drops to %.0f%%":                                        "prochaine présentation dans %s, la facilité tombe à %.0f%%",
+	"next shown in %s, ease unchanged":                                              "prochaine présentation dans %s, facilité inchangée",
+	"next shown in %s, ease rises to %.0f%%":                                        "prochaine présentation dans %s, la facilité monte à %.0f%%",
+	"Cards not found: %s":                                                           "Cartes introuvables : %s",
+	"Due: %s":                                                                       "Échéance : %s",
+	"Due: %s (in %d day(s))":                                                        "Échéance : %s (dans %d jour(s))",
+	"Interval: %d day(s), ease: %.0f%%":                                             "Intervalle : %d jour(s), facilité : %.0f%%",
+	"New card position: %d":                                                         "Position de la nouvelle carte : %d",
+	"Note: %d, note type: %s":                                                       "Note : %d, type de note : %s",
+	"Queue: %s":                                                                     "File : %s",
+	"Reps: %d, lapses: %d":                                                          "Révisions : %d, oublis : %d",
+	"buried":                                                                        "enfouie",
+	"card_ids is required":                                                          "card_ids est obligatoire",
+	"preview":                                                                       "aperçu",
+	"suspended":                                                                     "suspendue",
+
+	// Output
+	"Failed to encode JSON: %v": "Impossible d'encoder le JSON : %v",
+
+	// Deck options
+	"preset is required":                          "preset est obligatoire",
+	"decks is required":                           "decks est obligatoire",
+	"Failed to get deck options: %v":              "Impossible d'obtenir les options du paquet : %v",
+	"Deck not found: %s":                          "Paquet introuvable : %s",
+	"Preset not found: %s. Available presets: %s": "Préréglage introuvable : %s. Préréglages disponibles : %s",
+	"Failed to apply preset: %v":                  "Impossible d'appliquer le préréglage : %v",
+	"Applied preset \"%s\" to %d deck(s)":         "Préréglage « %s » appliqué à %d paquet(s)",
+	"%s (already using this preset)":              "%s (utilisait déjà ce préréglage)",
+	"%s (was: %s)":                                "%s (auparavant : %s)",
+	"%s has temporary limits for today. Run restore_deck_limits first so the change isn't lost at the end of the day.": "%s a des limites temporaires pour aujourd'hui. Exécutez d'abord restore_deck_limits pour que la modification ne soit pas perdue à la fin de la journée.",
+	"%s must be a whole number of at least %d": "%s doit être un nombre entier d'au moins %d",
+	"Easy interval":                      "Intervalle facile",
+	"Easy interval: %s":                  "Intervalle facile : %s",
+	"Failed to update deck options: %v":  "Impossible de mettre à jour les options du paquet : %v",
+	"Graduating interval":                "Intervalle de graduation",
+	"Graduating interval: %s":            "Intervalle de graduation : %s",
+	"Invalid %s: %v":                     "%s non valide : %v",
+	"Learning steps":                     "Étapes d'apprentissage",
+	"Learning steps: %s":                 "Étapes d'apprentissage : %s",
+	"Leech threshold":                    "Seuil de sangsue",
+	"Leech threshold: %d lapses":         "Seuil de sangsue : %d oublis",
+	"Maximum interval":                   "Intervalle maximal",
+	"Maximum interval: %s":               "Intervalle maximal : %s",
+	"Maximum reviews/day":                "Révisions maximales/jour",
+	"Maximum reviews/day: %d":            "Révisions maximales/jour : %d",
+	"New cards/day":                      "Nouvelles cartes/jour",
+	"New cards/day: %d":                  "Nouvelles cartes/jour : %d",
+	"Options of %s (preset \"%s\")":      "Options de %s (préréglage « %s »)",
+	"Pass at least one option to change": "Indiquez au moins une option à modifier",
+	"Relearning steps":                   "Étapes de réapprentissage",
+	"Relearning steps: %s":               "Étapes de réapprentissage : %s",
+	"The change also applies to decks sharing this preset: %s":                 "La modification s'applique aussi aux paquets partageant ce préréglage : %s",
+	"The options already had these values.":                                    "Les options avaient déjà ces valeurs.",
+	"This preset is shared with: %s":                                           "Ce préréglage est partagé avec : %s",
+	"Updated preset \"%s\" of %s":                                              "Préréglage « %s » de %s mis à jour",
+	"easy_interval (%.0f) must not be shorter than graduating_interval (%.0f)": "easy_interval (%.0f) ne doit pas être plus court que graduating_interval (%.0f)",
+	"%d deck(s) now use the Default preset: %s":                                "%d paquet(s) utilisent maintenant le préréglage Default : %s",
+	"A preset named %s already exists (ID: %d)":                                "Un préréglage nommé %s existe déjà (ID : %d)",
+	"Created preset \"%s\" (ID: %d) as a copy of \"%s\"":                       "Préréglage « %s » (ID : %d) créé comme copie de « %s »",
+	"Created preset \"%s\" (ID: %d) as a copy of preset %d":                    "Préréglage « %s » (ID : %d) créé comme copie du préréglage %d",
+	"Created preset %d, but failed to apply it: %v":                            "Préréglage %d créé, mais impossible de l'appliquer : %v",
+	"Failed to create preset: %v":                                              "Impossible de créer le préréglage : %v",
+	"Failed to remove preset: %v":                                              "Impossible de supprimer le préréglage : %v",
+	"No deck uses it yet. Apply it with apply_deck_preset using its ID.":       "Aucun paquet ne l'utilise encore. Appliquez-le avec apply_deck_preset en utilisant son ID.",
+	"Removed preset \"%s\"":                                                    "Préréglage « %s » supprimé",
+	"The Default preset can't be removed":                                      "Le préréglage Default ne peut pas être supprimé",
+
+	// Deck limits
+	"new_cards or reviews must be a positive number": "new_cards ou reviews doit être un nombre positif",
+	"Failed to extend limits: %v":                    "Impossible d'augmenter les limites : %v",
+	"Extended today's limits for %s":                 "Limites du jour augmentées pour %s",
+	"New cards: %d → %d":                             "Nouvelles cartes : %d → %d",
+	"Reviews: %d → %d":                               "Révisions : %d → %d",
+	"These limits apply today only: the deck uses a temporary copy of its \"%s\" preset, and the original preset is restored when the Anki day ends.": "Ces limites ne s'appliquent qu'aujourd'hui : le paquet utilise une copie temporaire de son préréglage « %s », et le préréglage d'origine est rétabli à la fin de la journée Anki.",
+	"review_limit must be a number of at least 0":                    "review_limit doit être un nombre supérieur ou égal à 0",
+	"Failed to change review limit: %v":                              "Impossible de modifier la limite de révisions : %v",
+	"Changed today's review limit for %s":                            "Limite de révisions du jour modifiée pour %s",
+	"Use restore_deck_limits to restore it earlier.":                 "Utilisez restore_deck_limits pour le rétablir plus tôt.",
+	"Failed to restore limits: %v":                                   "Impossible de rétablir les limites : %v",
+	"No temporary deck limits to restore":                            "Aucune limite temporaire de paquet à rétablir",
+	"Restored limits for %d deck(s)":                                 "Limites rétablies pour %d paquet(s)",
+	"%s: back on preset \"%s\"":                                      "%s : de retour au préréglage « %s »",
+	"Some decks could not be restored and will be retried later: %v": "Certains paquets n'ont pas pu être rétablis et seront réessayés plus tard : %v",
+
+	// Deck routing
+	"No routing rules configured. Add rules to the config file: %s":  "Aucune règle de routage configurée. Ajoutez des règles au fichier de configuration : %s",
+	"Failed to move cards to %s: %v":                                 "Impossible de déplacer les cartes vers %s : %v",
+	"All cards are already in the decks chosen by the routing rules": "Toutes les cartes sont déjà dans les paquets choisis par les règles de routage",
+	"Would move %d card(s)":                                          "%d carte(s) seraient déplacée(s)",
+	"Moved %d card(s)":                                               "%d carte(s) déplacée(s)",
+	"tag %s → %s: %d card(s)":                                        "étiquette %s → %s : %d carte(s)",
+	"Routed to deck %s by the tag rule %s":                           "Envoyée dans le paquet %s par la règle d'étiquette %s",
+
+	// Note links
+	"note_id is required": "note_id est obligatoire",
+	"related_note_ids must contain at least one other note": "related_note_ids doit contenir au moins une autre note",
+	"Note not found: %d": "Note introuvable : %d",
+	"Note %d (note type %s) has no %s field. Add a field named %s to this note type in Anki first.": "La note %d (type de note %s) n'a pas de champ %s. Ajoutez d'abord un champ nommé %s à ce type de note dans Anki.",
+	"Failed to update note %d: %v":                         "Impossible de mettre à jour la note %d : %v",
+	"Linked note %d with %d note(s); %d note(s) changed":   "Note %d liée à %d note(s) ; %d note(s) modifiée(s)",
+	"Unlinked note %d from %d note(s); %d note(s) changed": "Liens de la note %d avec %d note(s) supprimés ; %d note(s) modifiée(s)",
+	"Note %d has no related notes":                         "La note %d n'a aucune note liée",
+	"Notes related to %d (%d)":                             "Notes liées à %d (%d)",
+	"%d: note no longer exists":                            "%d : la note n'existe plus",
+	"%d [%s]: %s (tags: %s)":                               "%d [%s] : %s (étiquettes : %s)",
+	"%d [%s]: %s":                                          "%d [%s] : %s",
+
+	// Cloze deletions
+	"text is required":                                      "text est obligatoire",
+	"Failed to number clozes: %v":                           "Échec de la numérotation des textes à trous : %v",
+	"Cloze text (%d card(s))":                               "Texte à trous (%d carte(s))",
+	"Cloze text":                                            "Texte à trous",
+	"Created cloze note (ID: %d) with %d card(s)":           "Note à trous créée (ID : %d) avec %d carte(s)",
+	"Key terms: %s":                                         "Termes clés : %s",
+	"No key terms found; pass the words to hide as targets": "Aucun terme clé trouvé ; indiquez les mots à masquer dans targets",
+	"Not found in the text: %s":                             "Introuvable dans le texte : %s",
+	"Note type %s has no field for back_extra":              "Le type de note %s n'a pas de champ pour back_extra",
+	"Note type %s is not a cloze note type":                 "Le type de note %s n'est pas un type de note à trous",
+
+	// Note types
+	"model is required":    "model est obligatoire",
+	"old_name is required": "old_name est obligatoire",
+	"new_name is required": "new_name est obligatoire",
+	"rename_model_field was not run. Renaming a field forces a full upload on the next sync. Ask the user to confirm, then call again with confirm=true.": "rename_model_field n'a pas été exécuté. Renommer un champ impose un envoi complet lors de la prochaine synchronisation. Demandez confirmation à l'utilisateur, puis rappelez avec confirm=true.",
+	"Failed to get note types: %v":                                 "Échec de la récupération des types de note : %v",
+	"Note type not found: %s":                                      "Type de note introuvable : %s",
+	"Failed to get fields: %v":                                     "Échec de la récupération des champs : %v",
+	"Note type %s has no field %s. Fields: %s":                     "Le type de note %s n'a pas de champ %s. Champs : %s",
+	"Note type %s already has a field named %s":                    "Le type de note %s a déjà un champ nommé %s",
+	"Failed to find notes: %v":                                     "Échec de la recherche de notes : %v",
+	"Failed to rename field: %v":                                   "Échec du renommage du champ : %v",
+	"Renamed field %s to %s in note type %s":                       "Champ %s renommé en %s dans le type de note %s",
+	"Notes checked: %d":                                            "Notes vérifiées : %d",
+	"Notes with content preserved: %d (%d non-empty)":              "Notes dont le contenu est conservé : %d (%d non vides)",
+	"Notes whose content did not carry over: %d (%s)":              "Notes dont le contenu n'a pas été repris : %d (%s)",
+	"The next sync will require a full upload to AnkiWeb.":         "La prochaine synchronisation nécessitera un envoi complet vers AnkiWeb.",
+	"%s of template %s must be a string":                           "%s du modèle %s doit être un texte",
+	"%s of template %s refers to unknown field(s): %s. Fields: %s": "%s du modèle %s fait référence à des champs inconnus : %s. Champs : %s",
+	"%s: back template":                                            "%s : modèle du verso",
+	"%s: front template":                                           "%s : modèle du recto",
+	"Failed to get templates: %v":                                  "Impossible de récupérer les modèles : %v",
+	"Failed to update templates: %v":                               "Impossible de mettre à jour les modèles : %v",
+	"Note type %s has no template %s. Templates: %s":               "Le type de note %s n'a pas de modèle %s. Modèles : %s",
+	"Template %s needs Front or Back":                              "Le modèle %s nécessite Front ou Back",
+	"Updated templates of %s: %s":                                  "Modèles de %s mis à jour : %s",
+	"templates is required":                                        "templates est obligatoire",
+	"Failed to get styling: %v":                                    "Impossible de récupérer le style : %v",
+	"Failed to update styling: %v":                                 "Impossible de mettre à jour le style : %v",
+	"Styling of %s":                                                "Style de %s",
+	"The CSS has unbalanced braces":                                "Le CSS contient des accolades non équilibrées",
+	"Updated styling of %s":                                        "Style de %s mis à jour",
+	"css is required":                                              "css est obligatoire",
+
+	// Sync
+	"Failed to sync: %v": "Échec de la synchronisation : %v",
+	"Sync with AnkiWeb started. Anki syncs in the background and shows in its window when it is done or if it failed.": "Synchronisation avec AnkiWeb lancée. Anki synchronise en arrière-plan et indique dans sa fenêtre quand elle est terminée ou si elle a échoué.",
+	"Warning: could not record the sync time: %v": "Avertissement : impossible d'enregistrer l'heure de synchronisation : %v",
+	"Failed to read sync state: %v":               "Échec de la lecture de l'état de synchronisation : %v",
+	"Sync status":                                 "État de la synchronisation",
+	"Last sync started through this server: none recorded; showing changes from the last 24 hours": "Dernière synchronisation lancée par ce serveur : aucune enregistrée ; affichage des modifications des dernières 24 heures",
+	"Last sync started through this server: %s":                                                    "Dernière synchronisation lancée par ce serveur : %s",
+	"Notes added or edited: %d":                                                                    "Notes ajoutées ou modifiées : %d",
+	"Cards reviewed: %d":                                                                           "Cartes révisées : %d",
+	"There are local changes; call sync to upload them to AnkiWeb.":                                "Il y a des modifications locales ; appelez sync pour les envoyer vers AnkiWeb.",
+	"No local changes found.":                                                                      "Aucune modification locale trouvée.",
+	"Failed to find changes: %v":                                                                   "Impossible de rechercher les modifications : %v",
+
+	// Notes
+	"Nothing to update: pass fields, add_tags or remove_tags": "Rien à mettre à jour : indiquez fields, add_tags ou remove_tags",
+	"Invalid tag %q: tags can't be empty or contain spaces":   "Étiquette invalide %q : les étiquettes ne peuvent pas être vides ni contenir d'espaces",
+	"Failed to add tags: %v":                                  "Échec de l'ajout des étiquettes : %v",
+	"Failed to remove tags: %v":                               "Échec de la suppression des étiquettes : %v",
+	"Updated note %d":                                         "Note %d mise à jour",
+	"Fields changed: %s":                                      "Champs modifiés : %s",
+	"Fields already had these values":                         "Les champs avaient déjà ces valeurs",
+	"Tags added: %s":                                          "Étiquettes ajoutées : %s",
+	"Tags removed: %s":                                        "Étiquettes supprimées : %s",
+	"Pass either note_ids or query, not both":                 "Indiquez note_ids ou query, pas les deux",
+	"note_ids or query is required":                           "note_ids ou query est obligatoire",
+	"No notes found to delete":                                "Aucune note à supprimer trouvée",
+	"Failed to delete notes: %v":                              "Échec de la suppression des notes : %v",
+	"Deleted %d note(s) with %d card(s)":                      "%d note(s) supprimée(s) avec %d carte(s)",
+	"Would delete %d note(s) with %d card(s)":                 "Supprimerait %d note(s) avec %d carte(s)",
+	"... and %d more":                                         "... et %d de plus",
+	"Nothing was deleted. Show this list to the user and call again with confirm=true once they agree.": "Rien n'a été supprimé. Montrez cette liste à l'utilisateur et rappelez avec confirm=true une fois qu'il est d'accord.",
+
+	// Study
+	"No cards are due":            "Aucune carte à réviser",
+	"Due cards: showing %d of %d": "Cartes à réviser : %d affichée(s) sur %d",
+	"%d [%s, %s]: %s → %s (interval: %d day(s), reps: %d, lapses: %d)": "%d [%s, %s] : %s → %s (intervalle : %d jour(s), révisions : %d, oublis : %d)",
+	"new":                                "nouvelle",
+	"learning":                           "en apprentissage",
+	"relearning":                         "en réapprentissage",
+	"review":                             "révision",
+	"Failed to start review: %v":         "Échec du démarrage de la révision : %v",
+	"Started reviewing %s":               "Révision de %s démarrée",
+	"Failed to get the current card: %v": "Échec de la récupération de la carte actuelle : %v",
+	"Failed to show the answer: %v":      "Échec de l'affichage de la réponse : %v",
+	"No card is being reviewed. Call start_review first.":                          "Aucune carte n'est en cours de révision. Appelez d'abord start_review.",
+	"ease must be 1 (Again), 2 (Hard), 3 (Good) or 4 (Easy)":                       "ease doit être 1 (À revoir), 2 (Difficile), 3 (Correct) ou 4 (Facile)",
+	"Failed to answer card: %v":                                                    "Échec de la réponse à la carte : %v",
+	"Anki did not accept the answer %d for this card":                              "Anki n'a pas accepté la réponse %d pour cette carte",
+	"Answered card %d with %s":                                                     "Carte %d répondue avec %s",
+	"No card is being reviewed: the session is finished or no review was started.": "Aucune carte n'est en cours de révision : la session est terminée ou aucune révision n'a été démarrée.",
+	"Card %d (%s)": "Carte %d (%s)",
+	"Question: %s": "Question : %s",
+	"Answer: %s":   "Réponse : %s",
+	"Buttons: %s":  "Boutons : %s",
+
+	// Tags
+	"Added %s to %d note(s)":                "%s ajouté à %d note(s)",
+	"Failed to get tags: %v":                "Impossible de récupérer les étiquettes : %v",
+	"Failed to replace tag: %v":             "Impossible de remplacer l'étiquette : %v",
+	"No notes found":                        "Aucune note trouvée",
+	"Removed %s from %d note(s)":            "%s retiré de %d note(s)",
+	"Replaced tag %s with %s on %d note(s)": "Étiquette %s remplacée par %s sur %d note(s)",
+	"Tags (%d)":                             "Étiquettes (%d)",
+	"tags is required":                      "tags est obligatoire",
+
+	// Import
+	"#%d is a duplicate":             "#%d est un doublon",
+	"Created %d of %d note(s)":       "%d note(s) sur %d créée(s)",
+	"Duplicates skipped: %d":         "Doublons ignorés : %d",
+	"Errors: %d":                     "Erreurs : %d",
+	"Failed to parse CSV: %v":        "Impossible d'analyser le CSV : %v",
+	"No rows found in input":         "Aucune ligne trouvée dans l'entrée",
+	"The first field (%s) is empty":  "Le premier champ (%s) est vide",
+	"Unknown column %s. Columns: %s": "Colonne inconnue %s. Colonnes : %s",
+	"Failed to parse Markdown: %v":   "Impossible d'analyser le Markdown : %v",
+	"No cards found in the document": "Aucune carte trouvée dans le document",
+	"Question without answer: %s":    "Question sans réponse : %s",
+	"Failed to parse %s: %v":         "Impossible d'analyser %s : %v",
+	"Failed to store %s: %v":         "Impossible d'enregistrer %s : %v",
+	"Note must be an object":         "La note doit être un objet",
+	"notes or path is required":      "notes ou path est obligatoire",
+	"#%d (%s) can't be added: %s":    "#%d (%s) ne peut pas être ajoutée : %s",
+	"#%d (%s) is a duplicate":        "#%d (%s) est un doublon",
+	"%d of %d note(s) can be added":  "%d note(s) sur %d peuvent être ajoutées",
+	"Duplicates: %d":                 "Doublons : %d",
+	"Failed to check notes: %v":      "Impossible de vérifier les notes : %v",
+	"notes is required":              "notes est obligatoire",
+
+	// Media
+	"Failed to decode media file: %v":           "Impossible de décoder le fichier multimédia : %v",
+	"Failed to retrieve media file: %v":         "Impossible de récupérer le fichier multimédia : %v",
+	"Failed to save media file: %v":             "Impossible d'enregistrer le fichier multimédia : %v",
+	"Media file not found: %s":                  "Fichier multimédia introuvable : %s",
+	"Saved %s to %s (%d bytes)":                 "%s enregistré dans %s (%d octets)",
+	"filename is required":                      "filename est obligatoire",
+	"Failed to list media files: %v":            "Impossible de lister les fichiers multimédias : %v",
+	"Invalid pattern %q: %v":                    "Motif non valide %q : %v",
+	"Media files matching %s: showing %d of %d": "Fichiers multimédias correspondant à %s : %d sur %d affichés",
+	"No media files match %s":                   "Aucun fichier multimédia ne correspond à %s",
+	"%s is still used by %d note(s): %s. Remove it from the notes first, or pass force=true to delete it anyway.": "%s est encore utilisé par %d note(s) : %s. Retirez-le d'abord des notes ou passez force=true pour le supprimer quand même.",
+	"Deleted media file %s":                                       "Fichier multimédia %s supprimé",
+	"Failed to delete media file: %v":                             "Impossible de supprimer le fichier multimédia : %v",
+	"Still used by %d note(s), which will show it as missing: %s": "Encore utilisé par %d note(s), qui l'afficheront comme manquant : %s",
+	"delete_media_file was not run. Ask the user to confirm deleting %s, then call again with confirm=true.": "delete_media_file n'a pas été exécuté. Demandez à l'utilisateur de confirmer la suppression de %s, puis rappelez avec confirm=true.",
+	"Failed to download %s: %v":         "Impossible de télécharger %s : %v",
+	"Failed to verify stored media: %v": "Impossible de vérifier le fichier multimédia enregistré : %v",
+	"Pass one of path, url or data":     "Indiquez un seul élément parmi path, url ou data",
+	"Reference: %s":                     "Référence : %s",
+	"filename is required with data":    "filename est obligatoire avec data",
+
+	// Text-to-speech
+	"Added to field %s of note %d":         "Ajouté au champ %s de la note %d",
+	"Failed to generate speech: %v":        "Impossible de générer l'audio : %v",
+	"Field %s of note %d already plays it": "Le champ %s de la note %d le lit déjà",
+	"Generated %s (%d bytes)":              "%s généré (%d octets)",
+	"Note %d has no field %s. Fields: %s":  "La note %d n'a pas de champ %s. Champs : %s",
+	"Text-to-speech is not available: %v. Configure the tts section of the config file.": "La synthèse vocale n'est pas disponible : %v. Configurez la section tts du fichier de configuration.",
+	"language is required":              "language est obligatoire",
+	"text is longer than %d characters": "text dépasse %d caractères",
+
+	// Leeches
+	"(suspended)": "(suspendue)",
+	"Answering takes %.0fs on average. Add a hint, an example or a mnemonic to the question.":           "Répondre prend %.0fs en moyenne. Ajoutez un indice, un exemple ou un moyen mnémotechnique à la question.",
+	"Invalid action %q: use none, suspend or reset":                                                     "Action %q non valide : utilisez none, suspend ou reset",
+	"Its ease is at the minimum, so it keeps coming back. Reset it after rewording.":                    "Sa facilité est au minimum, elle revient donc sans cesse. Réinitialisez-la après l'avoir reformulée.",
+	"Lapses: %d, reviews: %d, ease: %d%%, %.0fs per answer":                                             "Oublis : %d, révisions : %d, facilité : %d%%, %.0fs par réponse",
+	"Leeches: showing %d of %d":                                                                         "Sangsues : %d sur %d affichées",
+	"No leeches found":                                                                                  "Aucune sangsue trouvée",
+	"Recent answers: %s":                                                                                "Réponses récentes : %s",
+	"Reset %d card(s) to new and removed their leech tag":                                               "%d carte(s) réinitialisée(s) comme nouvelles et étiquette leech supprimée",
+	"Reset %d card(s), but failed to remove the leech tag: %v":                                          "%d carte(s) réinitialisée(s), mais impossible de supprimer l'étiquette leech : %v",
+	"Reword the question so it has one unambiguous answer, or add context such as an example sentence.": "Reformulez la question pour qu'elle ait une seule réponse sans ambiguïté, ou ajoutez du contexte comme une phrase d'exemple.",
+	"Suggestion: %s": "Suggestion : %s",
+	"The answer has %d words. Split it into smaller cards or turn it into a cloze deletion.": "La réponse compte %d mots. Divisez-la en cartes plus petites ou transformez-la en texte à trous.",
+
+	// Dry run
+	"Dry run: this call would make %d change(s)": "Simulation : cet appel effectuerait %d modification(s)",
+	"Dry run: nothing was changed. The call stopped with this error after planning %d change(s), so there is no complete list of its changes.": "Simulation : rien n'a été modifié. L'appel s'est arrêté avec cette erreur après avoir prévu %d modification(s), il n'y a donc pas de liste complète de ses modifications.",
+	"Nothing was changed. Call the tool again without dry_run to make these changes.":                                                          "Rien n'a été modifié. Rappelez l'outil sans dry_run pour effectuer ces modifications.",
+	"Nothing was changed: the server runs in dry-run mode (ANKI_MCP_DRY_RUN).":                                                                 "Rien n'a été modifié : le serveur fonctionne en mode simulation (ANKI_MCP_DRY_RUN).",
+	"Tags: %s":                                        "Étiquettes : %s",
+	"Add a %s note to %s: %s":                         "Ajouter une note %s à %s : %s",
+	"Update note %d: %s":                              "Mettre à jour la note %d : %s",
+	"Delete %d note(s) with their cards: %s":          "Supprimer %d note(s) avec leurs cartes : %s",
+	"Add tags %s to %d note(s)":                       "Ajouter les étiquettes %s à %d note(s)",
+	"Remove tags %s from %d note(s)":                  "Retirer les étiquettes %s de %d note(s)",
+	"Replace tag %s with %s on %d note(s)":            "Remplacer l'étiquette %s par %s sur %d note(s)",
+	"Remove unused tags from the tag list":            "Retirer les étiquettes inutilisées de la liste des étiquettes",
+	"Create deck %s":                                  "Créer le paquet %s",
+	"Delete deck %s with its cards":                   "Supprimer le paquet %s avec ses cartes",
+	"Move %d card(s) to deck %s":                      "Déplacer %d carte(s) vers le paquet %s",
+	"Suspend %d card(s)":                              "Suspendre %d carte(s)",
+	"Unsuspend %d card(s)":                            "Réactiver %d carte(s)",
+	"Make %d card(s) new again":                       "Remettre %d carte(s) à l'état nouveau",
+	"Put %d card(s) back into relearning":             "Remettre %d carte(s) en réapprentissage",
+	"Make %d card(s) due in %s day(s)":                "Rendre %d carte(s) à réviser dans %s jour(s)",
+	"Store media file %s (%d bytes)":                  "Enregistrer le fichier média %s (%d octets)",
+	"Delete media file %s":                            "Supprimer le fichier média %s",
+	"Create note type %s with fields %s":              "Créer le type de note %s avec les champs %s",
+	"Update card templates %s of note type %s":        "Mettre à jour les modèles de carte %s du type de note %s",
+	"Replace the styling of note type %s":             "Remplacer le style du type de note %s",
+	"Rename field %s of note type %s to %s":           "Renommer le champ %s du type de note %s en %s",
+	"Save options preset %s":                          "Enregistrer le groupe d'options %s",
+	"Switch deck %s to options preset %d":             "Passer le paquet %s au groupe d'options %d",
+	"Create options preset %s as a copy of preset %d": "Créer le groupe d'options %s comme copie du groupe %d",
+	"Remove options preset %d":                        "Supprimer le groupe d'options %d",
+	"Add %d review(s) to the review history":          "Ajouter %d révision(s) à l'historique des révisions",
+	"Export deck %s to %s":                            "Exporter le paquet %s vers %s",
+	"Run Check Database":                              "Lancer Vérifier la base de données",
+	"Sync the collection with AnkiWeb":                "Synchroniser la collection avec AnkiWeb",
+	"Answer the current card with ease %d":            "Répondre à la carte actuelle avec la facilité %d",
+	"Switch deck %s to the new options preset":        "Passer le paquet %s au nouveau groupe d'options",
+
+	// Search
+	"%d [%s, %s]: %s (tags: %s)":                               "%d [%s, %s] : %s (étiquettes : %s)",
+	"%d [%s, %s]: %s":                                          "%d [%s, %s] : %s",
+	"Failed to search: %v":                                     "Échec de la recherche : %v",
+	"No notes match %s":                                        "Aucune note ne correspond à %s",
+	"query is required":                                        "query est obligatoire",
+	"Notes matching %s: %d-%d of %d":                           "Notes correspondant à %s : %d-%d sur %d",
+	"No more notes: %s matches %d note(s)":                     "Plus de notes : %s correspond à %d note(s)",
+	"More notes follow: pass cursor %s for the next page":      "D'autres notes suivent : passez le curseur %s pour la page suivante",
+	"Pass either offset or cursor, not both":                   "Passez soit offset soit cursor, pas les deux",
+	"Invalid cursor; start again without one":                  "Curseur invalide ; recommencez sans curseur",
+	"The cursor belongs to the search %s; pass the same query": "Le curseur appartient à la recherche %s ; passez la même requête",
+	"The cursor belongs to a differently sorted search; pass the same sort_by and order": "Le curseur appartient à une recherche triée autrement ; passez les mêmes sort_by et order",
+	"order must be asc or desc":                          "order doit être asc ou desc",
+	"order needs sort_by":                                "order nécessite sort_by",
+	"sort_by must be created, modified, due or interval": "sort_by doit être created, modified, due ou interval",
+
+	// Instances
+	"AnkiConnect instances (%d)":                 "Instances AnkiConnect (%d)",
+	"%s (%s): available, AnkiConnect version %d": "%s (%s) : disponible, AnkiConnect version %d",
+	"%s (%s): not available: %s":                 "%s (%s) : indisponible : %s",
+	"Unknown instance %s; the instances are %s":  "Instance inconnue %s ; les instances sont %s",
+
+	// Cancellation
+	"Cancelled after changing %d of %d note(s)":                     "Annulé après la modification de %d note(s) sur %d",
+	"Not sent, because the request was cancelled: %d":               "Non envoyées, car la requête a été annulée : %d",
+	"Cancelled after storing %d media file(s); no card was created": "Annulé après l'enregistrement de %d fichier(s) multimédia ; aucune carte n'a été créée",
+
+	// Rate limit
+	"Throttled: this call waited %s for AnkiConnect, which takes only so many requests at once": "Ralenti : cet appel a attendu %s AnkiConnect, qui n'accepte qu'un nombre limité de requêtes à la fois",
+
+	// Diagnostics
+	"Hint: Start Anki and keep it open. If it is running, check that the AnkiConnect add-on (code 2055492159) is installed and enabled, and that ANKI_CONNECT_URL matches its address.":      "Conseil : démarrez Anki et laissez-le ouvert. S'il est déjà lancé, vérifiez que le module AnkiConnect (code 2055492159) est installé et activé, et que ANKI_CONNECT_URL correspond à son adresse.",
+	"Hint: Anki is running but busy, e.g. with an open dialog, a sync or a large collection. Close open dialogs in Anki, or raise ANKI_CONNECT_TIMEOUT.":                                     "Conseil : Anki est lancé mais occupé, par exemple par une boîte de dialogue ouverte, une synchronisation ou une grande collection. Fermez les boîtes de dialogue dans Anki ou augmentez ANKI_CONNECT_TIMEOUT.",
+	"Hint: Another program answers at the AnkiConnect address. Install the AnkiConnect add-on (code 2055492159) via Tools > Add-ons > Get Add-ons, restart Anki and check ANKI_CONNECT_URL.": "Conseil : un autre programme répond à l'adresse d'AnkiConnect. Installez le module AnkiConnect (code 2055492159) via Outils > Modules > Obtenir des modules, redémarrez Anki et vérifiez ANKI_CONNECT_URL.",
+	"Hint: The installed AnkiConnect doesn't support this request. Update it via Tools > Add-ons > Check for Updates and restart Anki.":                                                      "Conseil : la version installée d'AnkiConnect ne prend pas en charge cette requête. Mettez-la à jour via Outils > Modules > Rechercher des mises à jour et redémarrez Anki.",
+	"Hint: The deck doesn't exist. Use get_deck_tree to see the deck names, or create_deck to create it.":                                                                                    "Conseil : le paquet n'existe pas. Utilisez get_deck_tree pour voir les noms des paquets, ou create_deck pour le créer.",
+	"Hint: The note type or one of its fields doesn't exist, or the first field is empty. Check the names in Anki under Tools > Manage Note Types > Fields; they are case-sensitive.":        "Conseil : le type de note ou l'un de ses champs n'existe pas, ou le premier champ est vide. Vérifiez les noms dans Anki sous Outils > Gérer les types de notes > Champs ; ils sont sensibles à la casse.",
+
+	// Health check
+	"AnkiConnect at %s did not answer: %v":                                                      "AnkiConnect à l'adresse %s n'a pas répondu : %v",
+	"AnkiConnect supports API version %d, but this server needs version %d; update AnkiConnect": "AnkiConnect prend en charge la version %d de l'API, mais ce serveur a besoin de la version %d ; mettez AnkiConnect à jour",
+	"AnkiConnect: %s, API version %d":                                                           "AnkiConnect : %s, version de l'API %d",
+	"Could not get the active profile: %s":                                                      "Impossible d'obtenir le profil actif : %s",
+	"Could not get the decks: %s":                                                               "Impossible d'obtenir les paquets : %s",
+	"Could not get the media folder: %s":                                                        "Impossible d'obtenir le dossier des médias : %s",
+	"Could not get the note types: %s":                                                          "Impossible d'obtenir les types de notes : %s",
+	"Decks: %d":                                                                                 "Paquets : %d",
+	"Failed to check the collection: %v":                                                        "Impossible de vérifier la collection : %v",
+	"Health check: %d problem(s)":                                                               "Bilan de santé : %d problème(s)",
+	"Health check: OK":                                                                          "Bilan de santé : OK",
+	"Latency: %s":                                                                               "Latence : %s",
+	"Media folder: %s":                                                                          "Dossier des médias : %s",
+	"Note types: %d":                                                                            "Types de notes : %d",
+	"Problem: %s":                                                                               "Problème : %s",
+	"Profile: %s":                                                                               "Profil : %s",
+
+	// GUI
+	"Failed to open the card browser: %v":                                                                           "Impossible d'ouvrir le navigateur de cartes : %v",
+	"Opened Anki's card browser with %d card(s) for: %s":                                                            "Navigateur de cartes d'Anki ouvert avec %d carte(s) pour : %s",
+	"Opened Anki's card browser, but no cards match: %s":                                                            "Navigateur de cartes d'Anki ouvert, mais aucune carte ne correspond à : %s",
+	"Pass exactly one of card_ids, note_ids or query":                                                               "Indiquez exactement un seul parmi card_ids, note_ids ou query",
+	"Failed to open the Add dialog: %v":                                                                             "Impossible d'ouvrir la fenêtre Ajouter : %v",
+	"Opened Anki's Add dialog with a %s card for deck %s. The card is only added once the user clicks Add in Anki.": "La fenêtre Ajouter d'Anki est ouverte avec une carte %s pour le paquet %s. La carte n'est ajoutée que lorsque l'utilisateur clique sur Ajouter dans Anki.",
+	"front and back or fields are required":                                                                         "front et back ou fields sont requis",
+
+	// Undo
+	"Anki has nothing to undo": "Anki n'a rien à annuler",
+	"Failed to undo: %v":       "Impossible d'annuler : %v",
+	"No change made through this server is left to undo, so the operation undone was made in Anki itself.": "Il ne reste aucune modification faite par ce serveur à annuler ; l'opération annulée a donc été faite dans Anki lui-même.",
+	"That change was undone, unless the user changed something in Anki after it.":                          "Cette modification a été annulée, sauf si l'utilisateur a modifié quelque chose dans Anki après elle.",
+	"The last change made through this server, %s ago, was: %s":                                            "La dernière modification faite par ce serveur, il y a %s, était : %s",
+	"Undid the last operation in Anki.":                                                                    "La dernière opération dans Anki a été annulée.",
+	"Undo the last operation in Anki":                                                                      "Annuler la dernière opération dans Anki",
+
+	// Change note type
+	"%s and %s are not both cloze or both standard note types, so their cards don't correspond":           "%s et %s ne sont pas tous deux des types de note à trous ou tous deux standard, leurs cartes ne se correspondent donc pas",
+	"%s and %s have no field in common; pass field_map. Fields of %s: %s":                                 "%s et %s n'ont aucun champ en commun ; indiquez field_map. Champs de %s : %s",
+	"%s has %d card types but %s only %d; cards of the other card types would be left without a template": "%s a %d types de carte mais %s seulement %d ; les cartes des autres types resteraient sans modèle",
+	"Change note %d to note type %s: %s":                                                                  "Passer la note %d au type de note %s : %s",
+	"Changed %d of %d note(s) from %s to %s":                                                              "%d note(s) sur %d passée(s) de %s à %s",
+	"Content discarded: %s":                                                                               "Contenu abandonné : %s",
+	"Fields: %s":                                                                                          "Champs : %s",
+	"Left empty: %s":                                                                                      "Laissés vides : %s",
+	"Note %d failed: %v":                                                                                  "Échec de la note %d : %v",
+	"The content of field(s) %s would be lost. Map them in field_map, or pass discard_unmapped=true if the user agrees to lose it.": "Le contenu des champs %s serait perdu. Associez-les dans field_map ou passez discard_unmapped=true si l'utilisateur accepte de le perdre.",
+	"The notes already use note type %s":                                                   "Les notes utilisent déjà le type de note %s",
+	"The notes use different note types (%s); change the notes of one note type at a time": "Les notes utilisent des types de note différents (%s) ; changez les notes d'un seul type de note à la fois",
+	"field_map: %s all map to %s":                                                          "field_map : %s sont tous associés à %s",
+	"field_map: %s must map to the name of a field of %s":                                  "field_map : %s doit être associé au nom d'un champ de %s",
+	"field_map: note type %s has no field %s. Fields: %s":                                  "field_map : le type de note %s n'a pas de champ %s. Champs : %s",
+	"new_model is required":                                                                "new_model est obligatoire",
+
+	// Find and replace in models
+	"%q was not found in the chosen templates; nothing was changed": "%q est introuvable dans les modèles choisis ; rien n'a été modifié",
+	"%s back":  "%s verso",
+	"%s front": "%s recto",
+	"After the replacement, %s of note type %s would refer to unknown field(s): %s. Fields: %s": "Après le remplacement, %s du type de note %s ferait référence à des champs inconnus : %s. Champs : %s",
+	"Failed to replace: %v": "Échec du remplacement : %v",
+	"Replace %q with %q in the templates and styling of every note type": "Remplacer %q par %q dans les modèles et le style de tous les types de note",
+	"Replace %q with %q in the templates and styling of note type %s":    "Remplacer %q par %q dans les modèles et le style du type de note %s",
+	"Replaced %q with %q in %d note type(s)":                             "%q remplacé par %q dans %d type(s) de note",
+	"find and replace are the same":                                      "find et replace sont identiques",
+	"find is required":                                                   "find est obligatoire",
+	"front, back and css are all false, so there is nothing to search":   "front, back et css sont tous false, il n'y a donc rien à chercher",
+	"replace is required":                                                "replace est obligatoire",
+	"styling":                                                            "style",
+
+	// Deck tree
+	"%s: %d card(s), %d in the deck itself; due today: %d new, %d learning, %d review": "%s : %d carte(s), %d dans le paquet lui-même ; à étudier aujourd'hui : %d nouvelles, %d en apprentissage, %d à réviser",
+	"%s: %d card(s); due today: %d new, %d learning, %d review":                        "%s : %d carte(s) ; à étudier aujourd'hui : %d nouvelles, %d en apprentissage, %d à réviser",
+	"Failed to get deck stats: %v":                                                     "Impossible d'obtenir les statistiques du paquet : %v",
+
+	// Retention
+	"Again rate, %s: %.1f%% of %d answers":                                        "Taux de « À revoir », %s : %.1f%% sur %d réponses",
+	"Interval growth: a passed review multiplied the interval by %.2f on average": "Croissance de l'intervalle : une révision réussie a multiplié l'intervalle par %.2f en moyenne",
+	"No cards were studied in this period.":                                       "Aucune carte n'a été étudiée pendant cette période.",
+	"Retention for %s from %s to %s":                                              "Rétention pour %s du %s au %s",
+	"True retention: %.1f%% of %d review answers":                                 "Rétention réelle : %.1f%% sur %d réponses de révision",
+	"True retention: no reviews of graduated cards in this period":                "Rétention réelle : aucune révision de cartes acquises pendant cette période",
+	"learning and relearning":                                                     "apprentissage et réapprentissage",
+	"mature cards":                                                                "cartes matures",
+	"tag %s":                                                                      "étiquette %s",
+	"young cards (interval under %d days)":                                        "cartes jeunes (intervalle de moins de %d jours)",
+
+	// Review forecast
+	"Already overdue: %d":                          "Déjà en retard : %d",
+	"Busiest day: %s with %d review(s)":            "Jour le plus chargé : %s avec %d révision(s)",
+	"Review forecast for %s, next %d day(s)":       "Prévision des révisions pour %s, %d prochain(s) jour(s)",
+	"Today (%s): %d":                               "Aujourd'hui (%s) : %d",
+	"Total: %d review(s), %.1f per day on average": "Total : %d révision(s), %.1f par jour en moyenne",
+	"days must be between 1 and %d":                "days doit être compris entre 1 et %d",
+
+	// Maturity report
+	"Maturity of %s: %d card(s)": "Maturité de %s : %d carte(s)",
+	"New":                        "Nouvelles",
+	"Learning":                   "En apprentissage",
+	"Young":                      "Jeunes",
+	"Mature":                     "Matures",
+	"Suspended":                  "Suspendues",
+	"Young cards have an interval under %d days, mature cards one of %d days or more.": "Les cartes jeunes ont un intervalle de moins de %d jours, les cartes matures un intervalle de %d jours ou plus.",
+
+	// Markdown export
+	"Exported %d note(s) to %s": "%d note(s) exportée(s) vers %s",
+
+	// Query validation
+	"A quotation mark is never closed":                                        "Un guillemet n'est jamais fermé",
+	"The closing parenthesis at position %d has no opening one":               "La parenthèse fermante à la position %d n'a pas de parenthèse ouvrante",
+	"%d parenthesis(es) are never closed":                                     "%d parenthèse(s) jamais fermée(s)",
+	"Anki doesn't support %s; use %s":                                         "Anki ne prend pas en charge %s ; utilisez %s",
+	"Anki doesn't support %s; put - before a term to exclude it":              "Anki ne prend pas en charge %s ; mettez - devant un terme pour l'exclure",
+	"%s: search keywords are followed by a colon, not =":                      "%s : les mots-clés de recherche sont suivis de deux-points, pas de =",
+	"Unknown state is:%s. Known states: %s":                                   "État inconnu is:%s. États connus : %s",
+	"%s: flag: takes a number from 0 (no flag) to 7":                          "%s : flag: attend un nombre de 0 (aucun drapeau) à 7",
+	"%s: prop: needs a property, a comparison and a value, e.g. prop:ivl>=10": "%s : prop: nécessite une propriété, une comparaison et une valeur, par ex. prop:ivl>=10",
+	"Unknown property %s. Known properties: %s":                               "Propriété inconnue %s. Propriétés connues : %s",
+	"%s: %s: takes a number of days, e.g. %s:7":                               "%s : %s: attend un nombre de jours, par ex. %s:7",
+	"Deck names with spaces must be quoted: %s":                               "Les noms de paquet contenant des espaces doivent être entre guillemets : %s",
+	"No deck named %s; did you mean %s?":                                      "Aucun paquet nommé %s ; vouliez-vous dire %s ?",
+	"No deck named %s":                                                        "Aucun paquet nommé %s",
+	"No note type named %s; did you mean %s?":                                 "Aucun type de note nommé %s ; vouliez-vous dire %s ?",
+	"No note type named %s":                                                   "Aucun type de note nommé %s",
+	"Unknown prefix %s:; did you mean %s:?":                                   "Préfixe inconnu %s: ; vouliez-vous dire %s: ?",
+	"%s: is neither a search keyword nor a field name, so the term matches nothing; write \\: to search for a colon": "%s: n'est ni un mot-clé de recherche ni un nom de champ, le terme ne trouve donc rien ; écrivez \\: pour rechercher deux-points",
+	"Query: %s":                                 "Requête : %s",
+	"No problems found.":                        "Aucun problème trouvé.",
+	"Anki rejected the query: %s":               "Anki a rejeté la requête : %s",
+	"The query matches %d card(s).":             "La requête correspond à %d carte(s).",
+	"Suggested query: %s":                       "Requête suggérée : %s",
+	"Anki rejected the suggested query too: %s": "Anki a aussi rejeté la requête suggérée : %s",
+	"The suggested query matches %d card(s).":   "La requête suggérée correspond à %d carte(s).",
+
+	// Image occlusion
+	"Invalid hide %q: use all or one": "hide %q non valide : utilisez all ou one",
+	"No image occlusion note type found. Use Anki 23.10 or later, install the Image Occlusion Enhanced add-on, or pass model_name.": "Aucun type de note d'occlusion d'image trouvé. Utilisez Anki 23.10 ou plus récent, installez le module Image Occlusion Enhanced ou indiquez model_name.",
+	"Note type %s is not an image occlusion note type":                          "Le type de note %s n'est pas un type d'occlusion d'image",
+	"Pass either image_path or image_url, not both":                             "Indiquez image_path ou image_url, pas les deux",
+	"image_path or image_url is required":                                       "image_path ou image_url est requis",
+	"Invalid occlusions: %v":                                                    "Occlusions non valides : %v",
+	"Image Occlusion Enhanced needs a PNG, JPEG or GIF image to draw its masks": "Image Occlusion Enhanced a besoin d'une image PNG, JPEG ou GIF pour dessiner ses masques",
+	"Failed to store mask: %v":                                                  "Impossible d'enregistrer le masque : %v",
+	"Cancelled after creating %d of %d note(s)":                                 "Annulé après la création de %d note(s) sur %d",
+	"Created image occlusion note (ID: %d) with %d card(s)":                     "Note d'occlusion d'image créée (ID : %d) avec %d carte(s)",
+	"Created %d image occlusion note(s) with %s: %s":                            "%d note(s) d'occlusion d'image créée(s) avec %s : %s",
+	"Image: %s, %d rectangle(s)":                                                "Image : %s, %d rectangle(s)",
+
+	// Math cards
+	"Invalid formula on the front: %v":         "Formule non valide au recto : %v",
+	"Invalid formula on the back: %v":          "Formule non valide au verso : %v",
+	"Preview: %d formula(s), not created":      "Aperçu : %d formule(s), non créée",
+	"Front: %s":                                "Recto : %s",
+	"Back: %s":                                 "Verso : %s",
+	"Note type %s needs at least two fields":   "Le type de note %s a besoin d'au moins deux champs",
+	"Created card (ID: %d) with %d formula(s)": "Carte créée (ID : %d) avec %d formule(s)",
+	"LaTeX formulas are rendered by Anki's LaTeX support, which needs LaTeX installed on the computer.": "Les formules LaTeX sont rendues par la prise en charge LaTeX d'Anki, qui nécessite LaTeX installé sur l'ordinateur.",
+	"Check these brackets; they are fine in intervals like [0, 1)":                                      "Vérifiez ces crochets ; ils sont corrects dans des intervalles comme [0, 1)",
+
+	// Image shrinking
+	"max_dimension must be between 1 and %d":              "max_dimension doit être compris entre 1 et %d",
+	"quality must be between 1 and 100":                   "quality doit être compris entre 1 et 100",
+	"%s stored as it was: %s":                             "%s enregistré tel quel : %s",
+	"%s shrunk from %dx%d (%d bytes) to %dx%d (%d bytes)": "%s réduit de %dx%d (%d octets) à %dx%d (%d octets)",
+
+	// Unused media
+	"%d unused media file(s) of %d: showing %d":                                                 "%d fichier(s) multimédia inutilisé(s) sur %d : %d affiché(s)",
+	"All %d media file(s) matching %s are used by notes":                                        "Les %d fichier(s) multimédia correspondant à %s sont utilisés par des notes",
+	"Anki moved the files to its media trash, from where Tools > Check Media can restore them.": "Anki a déplacé les fichiers dans sa corbeille multimédia, d'où Outils > Vérifier les médias peut les restaurer.",
+	"Call again with delete=true and confirm=true to delete them, once the user has agreed.":    "Rappelez l'outil avec delete=true et confirm=true pour les supprimer, une fois que l'utilisateur a accepté.",
+	"Deleted %d unused media file(s) of %d: showing %d":                                         "%d fichier(s) multimédia inutilisé(s) sur %d supprimé(s) : %d affiché(s)",
+	"Failed to delete media files: %v":                                                          "Impossible de supprimer les fichiers multimédia : %v",
+	"Skipped %d file(s) starting with _, which Anki keeps for note types":                       "%d fichier(s) commençant par _ ignoré(s), qu'Anki conserve pour les types de notes",
+	"find_unused_media deleted nothing. Ask the user to confirm deleting the unused media files, then call again with delete=true and confirm=true.": "find_unused_media n'a rien supprimé. Demandez à l'utilisateur de confirmer la suppression des fichiers multimédia inutilisés, puis rappelez l'outil avec delete=true et confirm=true.",
+
+	// Media references
+	"%d missing media file(s) referred to by %d of %d note(s)": "%d fichier(s) multimédia manquant(s) référencé(s) par %d note(s) sur %d",
+	"%s: note(s) %s": "%s : note(s) %s",
+	"All media files referred to by the %d note(s) checked exist": "Tous les fichiers multimédia référencés par les %d note(s) vérifiée(s) existent",
+	"Failed to check media references: %v":                        "Impossible de vérifier les références multimédia : %v",
+	"Search for the affected notes with: nid:%s":                  "Recherchez les notes concernées avec : nid:%s",
+
+	// Card recipes
+	"(given by each call)":                       "(indiqué à chaque appel)",
+	"Created card (ID: %d) from recipe %s in %s": "Carte créée (ID : %d) à partir de la recette %s dans %s",
+	"Deck: %s":                 "Paquet : %s",
+	"Image in %s, audio in %s": "Image dans %s, audio dans %s",
+	"No recipes configured. Add recipes to the config file: %s": "Aucune recette configurée. Ajoutez des recettes au fichier de configuration : %s",
+	"Note type: %s":       "Type de note : %s",
+	"Optional inputs: %s": "Entrées facultatives : %s",
+	"Recipe %s has no deck, so deck is required":                      "La recette %s n'a pas de paquet, deck est donc obligatoire",
+	"Recipe %s needs the input(s): %s":                                "La recette %s nécessite la ou les entrées : %s",
+	"Recipe %s uses unknown field(s) of note type %s: %s. Fields: %s": "La recette %s utilise des champs inconnus du type de note %s : %s. Champs : %s",
+	"Recipe not found: %s. Recipes: %s":                               "Recette introuvable : %s. Recettes : %s",
+	"Required inputs: %s":                                             "Entrées obligatoires : %s",
+	"Unknown input(s) for recipe %s: %s. Inputs: %s":                  "Entrée(s) inconnue(s) pour la recette %s : %s. Entrées : %s",
+	"Value %s must be a string":                                       "La valeur %s doit être une chaîne",
+	"the first field":                                                 "le premier champ",
+
+	// Vocabulary cards
+	"%s → %s": "%s → %s",
+	"Created vocabulary note (ID: %d) with the note type %s":                                                                                    "Note de vocabulaire créée (ID : %d) avec le type de note %s",
+	"Note type %s decides itself which cards it makes. Set reverse_field in the vocab section of the config file to choose with bidirectional.": "Le type de note %s décide lui-même des cartes qu'il crée. Définissez reverse_field dans la section vocab du fichier de configuration pour choisir avec bidirectional.",
+	"The vocab note type %s has no field for %s":                                                                                                "Le type de note de vocabulaire %s n'a pas de champ pour %s",
+	"word and translation are required":                                                                                                         "word et translation sont obligatoires",
+}
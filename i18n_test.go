@@ -0,0 +1,169 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// messageKeys collects the message formats passed to the localization helpers in the package sources
+func messageKeys(t *testing.T) []string {
+	t.Helper()
+
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("Failed to list sources: %v", err)
+	}
+
+	keys := make(map[string]bool)
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", file, err)
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			switch sel.Sel.Name {
+			case "t", "errorf":
+			case "Sprintf":
+				// Only localizer calls, not fmt.Sprintf
+				if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == "fmt" {
+					return true
+				}
+			default:
+				return true
+			}
+			if key, ok := stringConstant(call.Args[0]); ok {
+				keys[key] = true
+			}
+			return true
+		})
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// stringConstant evaluates a string literal or a concatenation of string literals
+func stringConstant(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		s, err := strconv.Unquote(e.Value)
+		return s, err == nil
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		left, ok := stringConstant(e.X)
+		if !ok {
+			return "", false
+		}
+		right, ok := stringConstant(e.Y)
+		return left + right, ok
+	}
+	return "", false
+}
+
+var formatVerb = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestCatalogsAreComplete(t *testing.T) {
+	keys := messageKeys(t)
+	if len(keys) == 0 {
+		t.Fatal("No localized messages found")
+	}
+
+	for lang, messages := range catalogs {
+		for _, key := range keys {
+			// Messages made only of format verbs need no translation
+			if formatVerb.ReplaceAllString(key, "") == "" {
+				continue
+			}
+			if _, ok := messages[key]; !ok {
+				t.Errorf("%s: missing translation for %q", lang, key)
+			}
+		}
+	}
+}
+
+func TestCatalogsHaveNoStaleKeys(t *testing.T) {
+	used := make(map[string]bool)
+	for _, key := range messageKeys(t) {
+		used[key] = true
+	}
+
+	for lang, messages := range catalogs {
+		for key := range messages {
+			if !used[key] {
+				t.Errorf("%s: translation for unused message %q", lang, key)
+			}
+		}
+	}
+}
+
+func TestCatalogsKeepFormatVerbs(t *testing.T) {
+	for lang, messages := range catalogs {
+		for key, translated := range messages {
+			want := formatVerb.FindAllString(key, -1)
+			got := formatVerb.FindAllString(translated, -1)
+			if strings.Join(want, " ") != strings.Join(got, " ") {
+				t.Errorf("%s: translation of %q has verbs %v, expected %v", lang, key, got, want)
+			}
+		}
+	}
+}
+
+func TestNewLocalizer(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "en"},
+		{"es", "es"},
+		{"de-DE", "de"},
+		{"fr_FR.UTF-8", "fr"},
+		{"xx", "en"},
+	}
+	for _, tt := range tests {
+		if got := newLocalizer(tt.input).lang; got != tt.want {
+			t.Errorf("newLocalizer(%q).lang = %q, expected %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestLocalizerTranslatesAndFormatsDates(t *testing.T) {
+	loc := newLocalizer("de")
+	if got := loc.Sprintf("Created deck: %s", "Spanisch"); got != "Stapel erstellt: Spanisch" {
+		t.Errorf("Unexpected translation: %q", got)
+	}
+	if got := loc.FormatDate(time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)); got != "09.03.2024" {
+		t.Errorf("Unexpected date format: %q", got)
+	}
+	if got := newLocalizer("en").Sprintf("Created deck: %s", "Spanish"); got != "Created deck: Spanish" {
+		t.Errorf("Unexpected English output: %q", got)
+	}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultImageQuality is the JPEG quality used when only max_dimension is given
+	defaultImageQuality = 85
+	// maxImageDimension is the largest max_dimension accepted
+	maxImageDimension = 10000
+	// maxImagePixels is the largest image decoded for shrinking. A decoded
+	// image takes 4 bytes per pixel, held twice while it is converted, so
+	// this keeps a shrink below about 400 MB.
+	maxImagePixels = 50_000_000
+)
+
+// imageOptions controls how images are shrunk before they are stored; the
+// zero value stores them as they are
+type imageOptions struct {
+	// MaxDimension is the longest side an image may have, in pixels
+	MaxDimension int
+	// Quality is the JPEG quality from 1 to 100
+	Quality int
+}
+
+// active reports whether images are to be processed at all
+func (o imageOptions) active() bool {
+	return o.MaxDimension > 0 || o.Quality > 0
+}
+
+// withImageOptions adds the parameters shrinking images before they are stored
+func withImageOptions() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithNumber("max_dimension",
+			mcp.Description("Optional: Scale JPEG and PNG images down so that their longest side has at most this many pixels, keeping the aspect ratio. GIF and WebP images are not supported and are stored as they are"),
+		)(t)
+		mcp.WithNumber("quality",
+			mcp.Description(fmt.Sprintf("Optional: Recompress JPEG images with this quality from 1 to 100 (default with max_dimension: %d); PNG images are recompressed losslessly", defaultImageQuality)),
+		)(t)
+	}
+}
+
+// imageOptions reads the max_dimension and quality arguments
+func (a *AnkiMCPServer) imageOptions(args map[string]interface{}) (imageOptions, *mcp.CallToolResult) {
+	var opts imageOptions
+	if v, ok := args["max_dimension"].(float64); ok {
+		if v < 1 || v > maxImageDimension {
+			return opts, a.errorf("max_dimension must be between 1 and %d", maxImageDimension)
+		}
+		opts.MaxDimension = int(v)
+	}
+	if v, ok := args["quality"].(float64); ok {
+		if v < 1 || v > 100 {
+			return opts, a.errorf("quality must be between 1 and 100")
+		}
+		opts.Quality = int(v)
+	}
+	return opts, nil
+}
+
+// imageShrink reports what shrinking did to an image
+type imageShrink struct {
+	Format              string
+	OldWidth, OldHeight int
+	NewWidth, NewHeight int
+	OldSize, NewSize    int
+	// Skipped tells why the image was stored as it was, if it was
+	Skipped string
+}
+
+// shrinkImage scales a JPEG or PNG image down to fit opts.MaxDimension and
+// recompresses it in its own format. JPEG orientation from EXIF is applied
+// to the pixels, since re-encoding drops the EXIF data. Other formats, such
+// as animated GIFs or WebP, images above maxImagePixels and images that
+// would only grow are returned unchanged, with the reason in Skipped. The
+// size is read from the header before anything is decoded.
+func shrinkImage(data []byte, opts imageOptions) ([]byte, imageShrink, error) {
+	report := imageShrink{OldSize: len(data), NewSize: len(data)}
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		report.Skipped = "not a JPEG or PNG image"
+		// The standard library has no WebP decoder to read its size
+		if len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP" {
+			report.Skipped = "WebP images are stored as they are"
+		}
+		return data, report, nil
+	}
+	report.Format = format
+	report.OldWidth, report.OldHeight = config.Width, config.Height
+	report.NewWidth, report.NewHeight = config.Width, config.Height
+	if format != "jpeg" && format != "png" {
+		report.Skipped = fmt.Sprintf("%s images are stored as they are", format)
+		return data, report, nil
+	}
+	if config.Width*config.Height > maxImagePixels {
+		report.Skipped = fmt.Sprintf("%dx%d pixels are too many to process", config.Width, config.Height)
+		return data, report, nil
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, report, fmt.Errorf("failed to decode image: %w", err)
+	}
+	img := toRGBA(decoded)
+	if format == "jpeg" {
+		img = orientImage(img, jpegOrientation(data))
+	}
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	resized := false
+	if longest := max(width, height); opts.MaxDimension > 0 && longest > opts.MaxDimension {
+		scale := float64(opts.MaxDimension) / float64(longest)
+		width = max(1, int(float64(width)*scale+0.5))
+		height = max(1, int(float64(height)*scale+0.5))
+		img = scaleImage(img, width, height)
+		resized = true
+	}
+
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		quality := opts.Quality
+		if quality == 0 {
+			quality = defaultImageQuality
+		}
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	} else {
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, report, fmt.Errorf("failed to encode image: %w", err)
+	}
+	if !resized && buf.Len() >= len(data) {
+		report.Skipped = "recompressing would not make it smaller"
+		return data, report, nil
+	}
+
+	report.NewWidth, report.NewHeight = width, height
+	report.NewSize = buf.Len()
+	return buf.Bytes(), report, nil
+}
+
+// toRGBA converts an image to RGBA with its origin at 0,0
+func toRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+	return rgba
+}
+
+// scaleImage scales an image to the given size, averaging the source pixels
+// each target pixel covers. Premultiplied colors keep transparent edges
+// clean.
+func scaleImage(src *image.RGBA, width, height int) *image.RGBA {
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * sh / height
+		y1 := max(y0+1, (y+1)*sh/height)
+		for x := 0; x < width; x++ {
+			x0 := x * sw / width
+			x1 := max(x0+1, (x+1)*sw/width)
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride+x0*4 : sy*src.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					sum[0] += int(row[i])
+					sum[1] += int(row[i+1])
+					sum[2] += int(row[i+2])
+					sum[3] += int(row[i+3])
+				}
+			}
+			n := (x1 - x0) * (y1 - y0)
+			p := dst.Pix[y*dst.Stride+x*4:]
+			for i := range sum {
+				p[i] = uint8((sum[i] + n/2) / n)
+			}
+		}
+	}
+	return dst
+}
+
+// orientImage turns an image the way an EXIF orientation from 2 to 8 says,
+// so it looks upright without the EXIF data
+func orientImage(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			copy(dst.Pix[y*dst.Stride+x*4:y*dst.Stride+x*4+4], src.Pix[sy*src.Stride+sx*4:sy*src.Stride+sx*4+4])
+		}
+	}
+	return dst
+}
+
+// jpegOrientation reads the EXIF orientation of a JPEG image, or returns 1,
+// upright, when it has none
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data) && data[i] == 0xFF; {
+		marker := data[i+1]
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if marker == 0xDA || length < 2 || i+2+length > len(data) {
+			break
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// exifOrientation reads the orientation tag from the first IFD of EXIF data
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	offset := int(order.Uint32(tiff[4:]))
+	if offset+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < entries; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 1
+}
+
+// storeImage stores an image read from r, shrinking it first when opts ask
+// for it. The report is nil when the image was streamed as it was.
+func (a *AnkiMCPServer) storeImage(name string, r io.Reader, opts imageOptions) (StoredMedia, *imageShrink, error) {
+	if !opts.active() {
+		media, err := a.ankiClient.StoreMediaFileFrom(name, r)
+		return media, nil, err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return StoredMedia{}, nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	data, report, err := shrinkImage(data, opts)
+	if err != nil {
+		return StoredMedia{}, nil, err
+	}
+	media, err := a.ankiClient.StoreMediaFile(name, data)
+	return media, &report, err
+}
+
+// describeShrink returns a line telling what shrinking did to a stored image
+func (a *AnkiMCPServer) describeShrink(filename string, report *imageShrink) string {
+	if report.Skipped != "" {
+		return a.t("%s stored as it was: %s", filename, report.Skipped)
+	}
+	return a.t("%s shrunk from %dx%d (%d bytes) to %dx%d (%d bytes)", filename,
+		report.OldWidth, report.OldHeight, report.OldSize, report.NewWidth, report.NewHeight, report.NewSize)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// encodeTestImage draws a gradient of the given size and encodes it
+func encodeTestImage(t *testing.T, width, height int, format string) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+	var buf bytes.Buffer
+	var err error
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// withOrientation inserts an EXIF segment with the given orientation after
+// the start of a JPEG image
+func withOrientation(data []byte, orientation byte) []byte {
+	tiff := []byte{'M', 'M', 0, 42, 0, 0, 0, 8, 0, 1, 0x01, 0x12, 0, 3, 0, 0, 0, 1, 0, orientation, 0, 0, 0, 0, 0, 0}
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	length := len(segment) + 2
+	app1 := append([]byte{0xFF, 0xE1, byte(length >> 8), byte(length)}, segment...)
+	return append(append(append([]byte{}, data[:2]...), app1...), data[2:]...)
+}
+
+func TestShrinkImage(t *testing.T) {
+	data, report, err := shrinkImage(encodeTestImage(t, 400, 200, "png"), imageOptions{MaxDimension: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || format != "png" || config.Width != 100 || config.Height != 50 {
+		t.Errorf("Expected a 100x50 PNG, got %s %dx%d (%v)", format, config.Width, config.Height, err)
+	}
+	if report.Skipped != "" || report.OldWidth != 400 || report.NewWidth != 100 || report.NewSize != len(data) {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	// A photo taken with the phone turned: stored 200x100, shown 100x200
+	rotated := withOrientation(encodeTestImage(t, 200, 100, "jpeg"), 6)
+	if got := jpegOrientation(rotated); got != 6 {
+		t.Fatalf("jpegOrientation() = %d, want 6", got)
+	}
+	data, _, err = shrinkImage(rotated, imageOptions{MaxDimension: 50, Quality: 70})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config, _, _ := image.DecodeConfig(bytes.NewReader(data)); config.Width != 25 || config.Height != 50 {
+		t.Errorf("Expected the rotated photo to be 25x50, got %dx%d", config.Width, config.Height)
+	}
+
+	small := encodeTestImage(t, 20, 20, "png")
+	if data, report, _ := shrinkImage(small, imageOptions{MaxDimension: 100}); report.Skipped == "" || !bytes.Equal(data, small) {
+		t.Errorf("Expected a small image to stay as it was, got %+v", report)
+	}
+	if _, report, _ := shrinkImage([]byte("RIFF....WEBPVP8 "), imageOptions{MaxDimension: 100}); !strings.Contains(report.Skipped, "WebP") {
+		t.Errorf("Expected WebP images to be skipped, got %+v", report)
+	}
+
+	// A small file claiming to be huge is skipped without being decoded
+	huge := withPNGSize(encodeTestImage(t, 20, 20, "png"), 100000, 100000)
+	if data, report, err := shrinkImage(huge, imageOptions{MaxDimension: 100}); err != nil || !strings.Contains(report.Skipped, "too many") || !bytes.Equal(data, huge) {
+		t.Errorf("Expected a huge image to be skipped, got %+v (%v)", report, err)
+	}
+}
+
+// withPNGSize changes the size in the header of a PNG image, leaving the
+// pixel data as it is
+func withPNGSize(data []byte, width, height uint32) []byte {
+	data = bytes.Clone(data)
+	// Signature, chunk length and type come before the IHDR data
+	ihdr := data[16:29]
+	binary.BigEndian.PutUint32(ihdr[0:4], width)
+	binary.BigEndian.PutUint32(ihdr[4:8], height)
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func TestOrientImage(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	// Orientation 6 turns the image clockwise: the left pixel ends up on top
+	dst := orientImage(src, 6)
+	if dst.Bounds().Dx() != 1 || dst.Bounds().Dy() != 2 || dst.RGBAAt(0, 0).R != 255 {
+		t.Errorf("Unexpected rotation: %v", dst.Pix)
+	}
+	if dst := orientImage(src, 8); dst.RGBAAt(0, 1).R != 255 {
+		t.Errorf("Expected orientation 8 to move the left pixel to the bottom: %v", dst.Pix)
+	}
+}
+
+func TestAddMediaShrinksImages(t *testing.T) {
+	server, mock := newMockServer(t)
+
+	args := map[string]interface{}{
+		"data":          base64.StdEncoding.EncodeToString(encodeTestImage(t, 300, 150, "png")),
+		"filename":      "chart.png",
+		"max_dimension": float64(60),
+	}
+	text, isErr := callTool(t, server.handleAddMedia, args)
+	if isErr || !strings.Contains(text, "chart.png shrunk from 300x150") || !strings.Contains(text, "to 60x30") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	if config, _, _ := image.DecodeConfig(bytes.NewReader(mock.media["chart.png"])); config.Width != 60 {
+		t.Errorf("Expected the stored image to be 60 pixels wide, got %d", config.Width)
+	}
+
+	args["quality"] = float64(0)
+	if text, isErr := callTool(t, server.handleAddMedia, args); !isErr || !strings.Contains(text, "quality must be between 1 and 100") {
+		t.Errorf("Expected an error for quality 0, got %s", text)
+	}
+}
@@ -0,0 +1,783 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"mime"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Outcomes of importing a note
+const (
+	importCreated   = "created"
+	importDuplicate = "duplicate"
+	importFailed    = "error"
+	// importNotSent marks notes left out because the request was cancelled
+	importNotSent = "not_sent"
+)
+
+// importResult is the outcome of importing one note. Index is the row, card
+// or item number in the input.
+type importResult struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+	NoteID int64  `json:"note_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// registerImportTools registers note import tools with the MCP server
+func (a *AnkiMCPServer) registerImportTools(s *server.MCPServer) {
+	// Tool: Import CSV
+	importCSVTool := mcp.NewTool("import_csv",
+		mcp.WithDescription("Create notes from CSV or TSV content, one note per row. Columns are mapped to the note type's fields with mapping; "+
+			"without it, header names matching field names are used, or else the columns in order. Duplicates are skipped and reported."),
+		mcp.WithString("data",
+			mcp.Description("Optional: CSV or TSV content to import (either data or path is required)"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Optional: Path to a CSV or TSV file to import"),
+		),
+		mcp.WithString("deck",
+			mcp.Required(),
+			mcp.Description("Deck to add the notes to; it is created if it doesn't exist"),
+		),
+		mcp.WithString("model_name",
+			mcp.Description("Optional: Note type to use (default: Basic)"),
+		),
+		mcp.WithObject("mapping",
+			mcp.Description("Optional: Field for each column, by header name or 1-based column number, e.g. {\"word\": \"Front\", \"3\": \"Back\"}. Unmapped columns are ignored."),
+		),
+		mcp.WithString("delimiter",
+			mcp.Description("Optional: Column separator (default: detected from the first line)"),
+			mcp.Enum("comma", "tab", "semicolon"),
+		),
+		mcp.WithBoolean("has_header",
+			mcp.Description("Optional: Whether the first row holds column names (default: true)"),
+		),
+		mcp.WithArray("tags",
+			mcp.Description("Optional: Tags to add to every note"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("tags_column",
+			mcp.Description("Optional: Column holding space-separated tags for each note, by header name or 1-based number"),
+		),
+		withFormat(),
+	)
+	a.addChangingTool(s, importCSVTool, (*AnkiMCPServer).handleImportCSV)
+
+	// Tool: Import Markdown
+	importMarkdownTool := mcp.NewTool("import_markdown",
+		mcp.WithDescription("Create Basic notes from a Markdown document such as lecture notes. Cards are defined by \"Q:\"/\"A:\" pairs, "+
+			"by headings with the text below them as the answer, or by top-level list items with their indented sub-items as the answer. "+
+			"Markdown formatting (bold, italic, code, lists, links, images) is converted to HTML."),
+		mcp.WithString("data",
+			mcp.Description("Optional: Markdown content to import (either data or path is required)"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Optional: Path to a Markdown file to import"),
+		),
+		mcp.WithString("deck",
+			mcp.Required(),
+			mcp.Description("Deck to add the notes to; it is created if it doesn't exist"),
+		),
+		mcp.WithString("style",
+			mcp.Description("Optional: How cards are defined (default: auto, which uses qa when there are Q: lines, headings when there are headings, and bullets otherwise)"),
+			mcp.Enum(markdownAuto, markdownQA, markdownHeadings, markdownBullets),
+		),
+		mcp.WithBoolean("reversed",
+			mcp.Description("Optional: Also create a reverse card for every note, using the \"Basic (and reversed card)\" note type"),
+		),
+		mcp.WithArray("tags",
+			mcp.Description("Optional: Tags to add to every note"),
+			mcp.WithStringItems(),
+		),
+		withFormat(),
+	)
+	a.addChangingTool(s, importMarkdownTool, (*AnkiMCPServer).handleImportMarkdown)
+
+	// Tool: Import JSON
+	importJSONTool := mcp.NewTool("import_json",
+		mcp.WithDescription("Create notes of any note type from JSON note objects. Every note is checked against its note type's fields "+
+			"before anything is added; valid notes are then added in batches and invalid ones reported."),
+		mcp.WithArray("notes",
+			mcp.Description("Optional: Notes to create (either notes or path is required)"),
+			mcp.Items(map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"deck":       map[string]interface{}{"type": "string", "description": "Deck of this note, overriding the deck parameter"},
+					"model_name": map[string]interface{}{"type": "string", "description": "Note type (default: Basic)"},
+					"fields":     map[string]interface{}{"type": "object", "description": "Field values by field name"},
+					"tags":       map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Tags of this note, added to the tags parameter"},
+					"media": map[string]interface{}{
+						"type":        "array",
+						"description": "Image or audio files to attach, each added to the end of a field",
+						"items": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"path":  map[string]interface{}{"type": "string", "description": "Path to the file"},
+								"field": map[string]interface{}{"type": "string", "description": "Field to add the file to"},
+							},
+							"required": []string{"path", "field"},
+						},
+					},
+				},
+				"required": []string{"fields"},
+			}),
+		),
+		mcp.WithString("path",
+			mcp.Description("Optional: Path to a JSON file holding an array of such notes"),
+		),
+		mcp.WithString("deck",
+			mcp.Description("Optional: Deck for notes that don't name their own; decks are created if they don't exist"),
+		),
+		mcp.WithArray("tags",
+			mcp.Description("Optional: Tags to add to every note"),
+			mcp.WithStringItems(),
+		),
+		withFormat(),
+	)
+	a.addChangingTool(s, importJSONTool, (*AnkiMCPServer).handleImportJSON)
+
+	// Tool: Check Duplicates
+	checkDuplicatesTool := mcp.NewTool("check_duplicates",
+		mcp.WithDescription("Check which notes already exist in the collection, or can't be added for another reason, without adding anything. "+
+			"Use it before a bulk import to leave out cards the user already has."),
+		mcp.WithArray("notes",
+			mcp.Required(),
+			mcp.Description("Notes to check, each with front and back or with fields"),
+			mcp.Items(map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"front":      map[string]interface{}{"type": "string", "description": "Front of a Basic note"},
+					"back":       map[string]interface{}{"type": "string", "description": "Back of a Basic note"},
+					"fields":     map[string]interface{}{"type": "object", "description": "Field values by field name, instead of front and back"},
+					"model_name": map[string]interface{}{"type": "string", "description": "Note type, overriding the model_name parameter"},
+					"deck":       map[string]interface{}{"type": "string", "description": "Deck, overriding the deck parameter"},
+				},
+			}),
+		),
+		mcp.WithString("deck",
+			mcp.Description("Optional: Deck the notes would be added to (default: Default)"),
+		),
+		mcp.WithString("model_name",
+			mcp.Description("Optional: Note type of the notes (default: Basic)"),
+		),
+		withFormat(),
+	)
+	a.addTool(s, checkDuplicatesTool, (*AnkiMCPServer).handleCheckDuplicates)
+}
+
+// noteCheck is the JSON representation of one note of a check_duplicates call
+type noteCheck struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// handleCheckDuplicates reports which notes could not be added
+func (a *AnkiMCPServer) handleCheckDuplicates(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	items, ok := args["notes"].([]interface{})
+	if !ok || len(items) == 0 {
+		return a.errorf("notes is required"), nil
+	}
+	defaultDeck, _ := args["deck"].(string)
+	defaultModel, _ := args["model_name"].(string)
+	if defaultModel == "" {
+		defaultModel = "Basic"
+	}
+
+	// Duplicates are found across the collection, so notes for decks that
+	// don't exist yet are checked as if they went to the Default deck
+	decks, err := a.ankiClient.GetDeckNames()
+	if err != nil {
+		return a.errorf("Failed to get decks: %v", err), nil
+	}
+
+	notes := make([]Note, len(items))
+	previews := make([]string, len(items))
+	modelFields := make(map[string][]string)
+	for i, item := range items {
+		obj, _ := item.(map[string]interface{})
+		note := Note{
+			DeckName:  stringValue(obj, "deck"),
+			ModelName: stringValue(obj, "model_name"),
+			Fields:    make(map[string]string),
+		}
+		if note.DeckName == "" {
+			note.DeckName = defaultDeck
+		}
+		if !slices.Contains(decks, note.DeckName) {
+			note.DeckName = "Default"
+		}
+		if note.ModelName == "" {
+			note.ModelName = defaultModel
+		}
+		for name, value := range objectValue(obj, "fields") {
+			text, _ := value.(string)
+			note.Fields[name] = text
+		}
+		if len(note.Fields) == 0 {
+			note.Fields["Front"], note.Fields["Back"] = stringValue(obj, "front"), stringValue(obj, "back")
+		}
+		// Anki compares the first field, so that's the one to show
+		names, ok := modelFields[note.ModelName]
+		if !ok {
+			names, _ = a.ankiClient.GetModelFieldNames(note.ModelName)
+			modelFields[note.ModelName] = names
+		}
+		if len(names) > 0 {
+			previews[i] = note.Fields[names[0]]
+		}
+		notes[i] = note
+	}
+
+	errs, err := a.ankiClient.CanAddNotes(notes)
+	if err != nil {
+		return a.errorf("Failed to check notes: %v", err), nil
+	}
+
+	results := make([]noteCheck, len(notes))
+	counts := make(map[string]int)
+	for i, err := range errs {
+		results[i] = noteCheck{Index: i + 1, Status: "ok"}
+		switch {
+		case err == nil:
+		case strings.Contains(err.Error(), "duplicate"):
+			results[i].Status = importDuplicate
+		default:
+			results[i].Status = importFailed
+			results[i].Error = err.Error()
+		}
+		counts[results[i].Status]++
+	}
+
+	if wantsJSON(request) {
+		return a.jsonResult(map[string]interface{}{
+			"can_add":    counts["ok"],
+			"duplicates": counts[importDuplicate],
+			"errors":     counts[importFailed],
+			"results":    results,
+		}), nil
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("%d of %d note(s) can be added", counts["ok"], len(results)))
+	out.Item(a.t("Duplicates: %d", counts[importDuplicate]))
+	out.Item(a.t("Errors: %d", counts[importFailed]))
+	for _, r := range results {
+		preview := plainText(previews[r.Index-1], 40)
+		switch r.Status {
+		case importDuplicate:
+			out.Item(a.t("#%d (%s) is a duplicate", r.Index, preview))
+		case importFailed:
+			out.Item(a.t("#%d (%s) can't be added: %s", r.Index, preview, r.Error))
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleImportCSV creates notes from CSV or TSV rows
+func (a *AnkiMCPServer) handleImportCSV(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	data, _ := args["data"].(string)
+	if path, ok := args["path"].(string); ok && path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return a.errorf("Failed to read %s: %v", path, err), nil
+		}
+		data = string(content)
+	}
+	if strings.TrimSpace(data) == "" {
+		return a.errorf("data or path is required"), nil
+	}
+	deck, _ := args["deck"].(string)
+	if strings.TrimSpace(deck) == "" {
+		return a.errorf("deck is required"), nil
+	}
+	model, _ := args["model_name"].(string)
+	if model == "" {
+		model = "Basic"
+	}
+	hasHeader := true
+	if v, ok := args["has_header"].(bool); ok {
+		hasHeader = v
+	}
+	commonTags := stringSliceValue(args, "tags")
+
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.Comma = csvDelimiter(args, data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return a.errorf("Failed to parse CSV: %v", err), nil
+	}
+	var header []string
+	firstRow := 1
+	if hasHeader && len(records) > 0 {
+		header = records[0]
+		records = records[1:]
+		firstRow = 2
+	}
+	if len(records) == 0 {
+		return a.errorf("No rows found in input"), nil
+	}
+
+	fieldNames, err := a.ankiClient.GetModelFieldNames(model)
+	if err != nil {
+		return a.errorf("Failed to get fields: %v", err), nil
+	}
+	columns, errResult := a.csvColumns(objectValue(args, "mapping"), header, model, fieldNames)
+	if errResult != nil {
+		return errResult, nil
+	}
+	tagsColumn := -1
+	if key, _ := args["tags_column"].(string); key != "" {
+		col, ok := csvColumn(key, header)
+		if !ok {
+			return a.errorf("Unknown column %s. Columns: %s", key, strings.Join(header, ", ")), nil
+		}
+		tagsColumn = col
+	}
+
+	if err := a.ankiClient.CreateDeck(deck); err != nil {
+		return a.errorf("Failed to create deck: %v", err), nil
+	}
+
+	var results []importResult
+	var notes []Note
+	var indexes []int
+	for i, record := range records {
+		row := firstRow + i
+		fields := make(map[string]string, len(fieldNames))
+		for col, field := range columns {
+			if col < len(record) {
+				fields[field] = record[col]
+			}
+		}
+		if strings.TrimSpace(fields[fieldNames[0]]) == "" {
+			results = append(results, importResult{Index: row, Status: importFailed, Error: a.t("The first field (%s) is empty", fieldNames[0])})
+			continue
+		}
+		tags := slices.Clone(commonTags)
+		if tagsColumn >= 0 && tagsColumn < len(record) {
+			tags = append(tags, strings.Fields(record[tagsColumn])...)
+		}
+		notes = append(notes, Note{
+			DeckName:  deck,
+			ModelName: model,
+			Fields:    fields,
+			Tags:      tags,
+			Options: map[string]interface{}{
+				"allowDuplicate": false,
+			},
+		})
+		indexes = append(indexes, row)
+	}
+	results = append(results, a.addImportNotes(ctx, notes, indexes)...)
+
+	return a.importSummary(request, results), nil
+}
+
+// handleImportMarkdown creates notes from the cards in a Markdown document
+func (a *AnkiMCPServer) handleImportMarkdown(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	data, _ := args["data"].(string)
+	if path, ok := args["path"].(string); ok && path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return a.errorf("Failed to read %s: %v", path, err), nil
+		}
+		data = string(content)
+	}
+	if strings.TrimSpace(data) == "" {
+		return a.errorf("data or path is required"), nil
+	}
+	deck, _ := args["deck"].(string)
+	if strings.TrimSpace(deck) == "" {
+		return a.errorf("deck is required"), nil
+	}
+	style, _ := args["style"].(string)
+	tags := stringSliceValue(args, "tags")
+
+	cards, err := parseMarkdownCards(data, style)
+	if err != nil {
+		return a.errorf("Failed to parse Markdown: %v", err), nil
+	}
+	if len(cards) == 0 {
+		return a.errorf("No cards found in the document"), nil
+	}
+
+	model := "Basic"
+	if reversed, _ := args["reversed"].(bool); reversed {
+		if _, err := a.ensureReversedModel(); err != nil {
+			return a.errorf("Failed to create note type %s: %v", reversedModel, err), nil
+		}
+		model = reversedModel
+	}
+	if err := a.ankiClient.CreateDeck(deck); err != nil {
+		return a.errorf("Failed to create deck: %v", err), nil
+	}
+
+	var results []importResult
+	var notes []Note
+	var indexes []int
+	for i, card := range cards {
+		if card.Back == "" {
+			results = append(results, importResult{Index: i + 1, Status: importFailed, Error: a.t("Question without answer: %s", card.Front)})
+			continue
+		}
+		notes = append(notes, Note{
+			DeckName:  deck,
+			ModelName: model,
+			Fields: map[string]string{
+				"Front": markdownToHTML(card.Front),
+				"Back":  markdownToHTML(card.Back),
+			},
+			Tags: tags,
+			Options: map[string]interface{}{
+				"allowDuplicate": false,
+			},
+		})
+		indexes = append(indexes, i+1)
+	}
+	results = append(results, a.addImportNotes(ctx, notes, indexes)...)
+
+	return a.importSummary(request, results), nil
+}
+
+// handleImportJSON validates JSON note objects and creates the valid ones
+func (a *AnkiMCPServer) handleImportJSON(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	items, _ := args["notes"].([]interface{})
+	if path, ok := args["path"].(string); ok && path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return a.errorf("Failed to read %s: %v", path, err), nil
+		}
+		if err := json.Unmarshal(content, &items); err != nil {
+			return a.errorf("Failed to parse %s: %v", path, err), nil
+		}
+	}
+	if len(items) == 0 {
+		return a.errorf("notes or path is required"), nil
+	}
+	defaultDeck, _ := args["deck"].(string)
+	commonTags := stringSliceValue(args, "tags")
+
+	var results []importResult
+	var notes []Note
+	var indexes []int
+	modelFields := make(map[string][]string)
+	decks := make(map[string]bool)
+	for i, item := range items {
+		index := i + 1
+		fail := func(err string) {
+			results = append(results, importResult{Index: index, Status: importFailed, Error: err})
+		}
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			fail(a.t("Note must be an object"))
+			continue
+		}
+
+		deck := stringValue(obj, "deck")
+		if deck == "" {
+			deck = defaultDeck
+		}
+		if deck == "" {
+			fail(a.t("deck is required"))
+			continue
+		}
+		model := stringValue(obj, "model_name")
+		if model == "" {
+			model = "Basic"
+		}
+		fieldNames, ok := modelFields[model]
+		if !ok {
+			names, err := a.ankiClient.GetModelFieldNames(model)
+			if err != nil {
+				fail(a.t("Failed to get fields: %v", err))
+				continue
+			}
+			modelFields[model], fieldNames = names, names
+		}
+
+		fields := make(map[string]string)
+		var unknown []string
+		invalid := ""
+		for name, value := range objectValue(obj, "fields") {
+			text, ok := value.(string)
+			switch {
+			case !slices.Contains(fieldNames, name):
+				unknown = append(unknown, name)
+			case !ok:
+				invalid = name
+			default:
+				fields[name] = text
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			fail(a.t("Unknown field(s) for note type %s: %s. Fields: %s", model, strings.Join(unknown, ", "), strings.Join(fieldNames, ", ")))
+			continue
+		}
+		if invalid != "" {
+			fail(a.t("Field %s must be a string", invalid))
+			continue
+		}
+		if len(fieldNames) == 0 || strings.TrimSpace(fields[fieldNames[0]]) == "" {
+			fail(a.t("The first field of note type %s must not be empty", model))
+			continue
+		}
+
+		// Media is stored only once the note is known to be valid
+		mediaErr := ""
+		media, _ := obj["media"].([]interface{})
+		if len(media) > 0 && ctx.Err() != nil {
+			results = append(results, importResult{Index: index, Status: importNotSent})
+			continue
+		}
+		for _, m := range media {
+			file, _ := m.(map[string]interface{})
+			path, field := stringValue(file, "path"), stringValue(file, "field")
+			if !slices.Contains(fieldNames, field) {
+				mediaErr = a.t("Unknown field(s) for note type %s: %s. Fields: %s", model, field, strings.Join(fieldNames, ", "))
+				break
+			}
+			name, err := a.storeMediaPath(path)
+			if err != nil {
+				mediaErr = a.t("Failed to store %s: %v", path, err)
+				break
+			}
+			if strings.HasPrefix(mime.TypeByExtension(filepath.Ext(name)), "image/") {
+				fields[field] = formatContent(fields[field], name, "")
+			} else {
+				fields[field] = formatContent(fields[field], "", name)
+			}
+		}
+		if mediaErr != "" {
+			fail(mediaErr)
+			continue
+		}
+
+		decks[deck] = true
+		notes = append(notes, Note{
+			DeckName:  deck,
+			ModelName: model,
+			Fields:    fields,
+			Tags:      append(slices.Clone(commonTags), stringSliceValue(obj, "tags")...),
+			Options: map[string]interface{}{
+				"allowDuplicate": false,
+			},
+		})
+		indexes = append(indexes, index)
+	}
+
+	for deck := range decks {
+		if err := a.ankiClient.CreateDeck(deck); err != nil {
+			return a.errorf("Failed to create deck: %v", err), nil
+		}
+	}
+	results = append(results, a.addImportNotes(ctx, notes, indexes)...)
+
+	return a.importSummary(request, results), nil
+}
+
+// storeMediaPath stores a media file in Anki and returns the name it was stored under
+func (a *AnkiMCPServer) storeMediaPath(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	media, err := a.ankiClient.StoreMediaFileFrom(filepath.Base(path), file)
+	if err != nil {
+		return "", err
+	}
+	if err := a.ankiClient.VerifyMedia(media); err != nil {
+		return "", err
+	}
+	return media.Filename, nil
+}
+
+// csvDelimiter returns the requested column separator, or detects it from the
+// first line of the data
+func csvDelimiter(args map[string]interface{}, data string) rune {
+	switch delimiter, _ := args["delimiter"].(string); delimiter {
+	case "tab":
+		return '\t'
+	case "semicolon":
+		return ';'
+	case "comma":
+		return ','
+	}
+
+	firstLine, _, _ := strings.Cut(data, "\n")
+	switch {
+	case strings.Contains(firstLine, "\t"):
+		return '\t'
+	case strings.Contains(firstLine, ";") && !strings.Contains(firstLine, ","):
+		return ';'
+	default:
+		return ','
+	}
+}
+
+// csvColumn resolves a column given by header name or 1-based number to its
+// index
+func csvColumn(key string, header []string) (int, bool) {
+	if n, err := strconv.Atoi(key); err == nil {
+		return n - 1, n > 0
+	}
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), key) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// csvColumns maps column indexes to the model's fields. Without an explicit
+// mapping, header names matching field names are used, or else the columns in
+// field order.
+func (a *AnkiMCPServer) csvColumns(mapping map[string]interface{}, header []string, model string, fieldNames []string) (map[int]string, *mcp.CallToolResult) {
+	columns := make(map[int]string)
+	if len(mapping) > 0 {
+		var unknown []string
+		for key, value := range mapping {
+			field, _ := value.(string)
+			col, ok := csvColumn(key, header)
+			if !ok {
+				return nil, a.errorf("Unknown column %s. Columns: %s", key, strings.Join(header, ", "))
+			}
+			if !slices.Contains(fieldNames, field) {
+				unknown = append(unknown, field)
+				continue
+			}
+			columns[col] = field
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return nil, a.errorf("Unknown field(s) for note type %s: %s. Fields: %s", model, strings.Join(unknown, ", "), strings.Join(fieldNames, ", "))
+		}
+		return columns, nil
+	}
+
+	for i, name := range header {
+		for _, field := range fieldNames {
+			if strings.EqualFold(strings.TrimSpace(name), field) {
+				columns[i] = field
+			}
+		}
+	}
+	if len(columns) == 0 {
+		for i, field := range fieldNames {
+			columns[i] = field
+		}
+	}
+	return columns, nil
+}
+
+// addImportNotes adds notes with a batched request and returns the outcome
+// of each one; indexes holds the input position of each note
+func (a *AnkiMCPServer) addImportNotes(ctx context.Context, notes []Note, indexes []int) []importResult {
+	results := make([]importResult, len(notes))
+	for i, result := range a.ankiClient.AddNotes(ctx, notes) {
+		results[i].Index = indexes[i]
+		switch {
+		case result.Err == nil:
+			results[i].Status = importCreated
+			results[i].NoteID = result.ID
+		case errors.Is(result.Err, errNotSent):
+			results[i].Status = importNotSent
+		case strings.Contains(result.Err.Error(), "duplicate"):
+			results[i].Status = importDuplicate
+		default:
+			results[i].Status = importFailed
+			results[i].Error = result.Err.Error()
+		}
+	}
+	return results
+}
+
+// importSummary reports how many notes were created, skipped as duplicates or
+// failed, listing the ones that weren't created
+func (a *AnkiMCPServer) importSummary(request mcp.CallToolRequest, results []importResult) *mcp.CallToolResult {
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Index < results[j].Index
+	})
+	counts := make(map[string]int)
+	for _, r := range results {
+		counts[r.Status]++
+	}
+
+	if wantsJSON(request) {
+		result := a.jsonResult(map[string]interface{}{
+			"created":    counts[importCreated],
+			"duplicates": counts[importDuplicate],
+			"errors":     counts[importFailed],
+			"not_sent":   counts[importNotSent],
+			"results":    results,
+		})
+		result.IsError = counts[importCreated] == 0
+		return result
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Created %d of %d note(s)", counts[importCreated], len(results)))
+	out.Item(a.t("Duplicates skipped: %d", counts[importDuplicate]))
+	out.Item(a.t("Errors: %d", counts[importFailed]))
+	if counts[importNotSent] > 0 {
+		out.Item(a.t("Not sent, because the request was cancelled: %d", counts[importNotSent]))
+	}
+	listed := 0
+	for _, r := range results {
+		if r.Status == importCreated || r.Status == importNotSent {
+			continue
+		}
+		if listed == maxListedNotes {
+			out.Item(a.t("... and %d more", counts[importDuplicate]+counts[importFailed]-listed))
+			break
+		}
+		listed++
+		if r.Status == importDuplicate {
+			out.Item(a.t("#%d is a duplicate", r.Index))
+		} else {
+			out.Item(a.t("#%d failed: %s", r.Index, r.Error))
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+		IsError: counts[importCreated] == 0,
+	}
+}
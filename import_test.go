@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImportCSV(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	data := "word,meaning,topic\n" +
+		"la mesa,the table,furniture\n" +
+		"\"la silla, pequeña\",\"the small chair\",furniture home\n" +
+		",missing word,\n" +
+		"la mesa,the table again,\n"
+	args := map[string]interface{}{
+		"data":        data,
+		"deck":        "Spanish::Home",
+		"mapping":     map[string]interface{}{"word": "Front", "2": "Back"},
+		"tags":        []interface{}{"imported"},
+		"tags_column": "topic",
+	}
+	text, isErr := callTool(t, server.handleImportCSV, args)
+	if isErr || !strings.Contains(text, "Created 2 of 4 note(s)") || !strings.Contains(text, "#4 failed: The first field (Front) is empty") || !strings.Contains(text, "#5 is a duplicate") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	notes, _ := server.ankiClient.FindNotes("deck:Spanish::Home tag:home tag:imported")
+	if len(notes) != 1 {
+		t.Errorf("Expected the tags column and common tags on the chair note, got %d note(s)", len(notes))
+	}
+
+	// Without a header the columns map to the fields in order
+	args = map[string]interface{}{"data": "el sol\tthe sun\nla luna\tthe moon\n", "deck": "Spanish", "has_header": false}
+	if text, isErr := callTool(t, server.handleImportCSV, args); isErr || !strings.Contains(text, "Created 2 of 2 note(s)") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+
+	args = map[string]interface{}{"data": data, "deck": "Spanish", "mapping": map[string]interface{}{"word": "Vorderseite"}}
+	if text, isErr := callTool(t, server.handleImportCSV, args); !isErr || !strings.Contains(text, "Unknown field(s)") {
+		t.Errorf("Expected an unknown field error, got: %s", text)
+	}
+}
+
+func TestImportJSON(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	image := filepath.Join(t.TempDir(), "sun.png")
+	if err := os.WriteFile(image, []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	notes := []interface{}{
+		map[string]interface{}{
+			"fields": map[string]interface{}{"Front": "el sol", "Back": "the sun"},
+			"media":  []interface{}{map[string]interface{}{"path": image, "field": "Back"}},
+		},
+		map[string]interface{}{
+			"deck":       "Spanish::Grammar",
+			"model_name": "Cloze",
+			"fields":     map[string]interface{}{"Text": "{{c1::Hay}} un gato."},
+			"tags":       []interface{}{"haber"},
+		},
+		map[string]interface{}{"fields": map[string]interface{}{"Front": "la luna", "Reverso": "the moon"}},
+		map[string]interface{}{"fields": map[string]interface{}{"Back": "no front"}},
+		map[string]interface{}{"model_name": "Missing", "fields": map[string]interface{}{"Front": "x"}},
+	}
+	args := map[string]interface{}{"notes": notes, "deck": "Spanish::Astronomy", "tags": []interface{}{"json"}, "format": "json"}
+	text, isErr := callTool(t, server.handleImportJSON, args)
+	if isErr || !strings.Contains(text, `"created":2`) || !strings.Contains(text, `"errors":3`) {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	if !strings.Contains(text, "Unknown field(s) for note type Basic: Reverso") {
+		t.Errorf("Expected the unknown field to be reported, got: %s", text)
+	}
+
+	ids, _ := server.ankiClient.FindNotes("deck:Spanish::Astronomy tag:json")
+	infos, _ := server.ankiClient.GetNotesInfo(ids)
+	if back, _ := noteField(infos[0], "Back"); back != `<img src="sun.png"><br><br>the sun` {
+		t.Errorf("Expected the image in the back field, got %q", back)
+	}
+	if cloze, _ := server.ankiClient.FindNotes("deck:Spanish::Grammar tag:haber tag:json"); len(cloze) != 1 {
+		t.Errorf("Expected the cloze note with both tags, got %d", len(cloze))
+	}
+}
+
+func TestCheckDuplicates(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+	notes := len(mock.notes)
+
+	args := map[string]interface{}{
+		"deck":       "Spanish::Verbs",
+		"model_name": "Basic (and reversed card)",
+		"notes": []interface{}{
+			map[string]interface{}{"front": "el perro", "back": "the dog"},
+			map[string]interface{}{"front": "el pájaro", "back": "the bird"},
+			map[string]interface{}{"fields": map[string]interface{}{"Text": "{{c1::Ser}} is permanent"}, "model_name": "Cloze"},
+			map[string]interface{}{"front": "", "back": "nothing"},
+		},
+	}
+	text, isErr := callTool(t, server.handleCheckDuplicates, args)
+	if isErr || !strings.Contains(text, "2 of 4 note(s) can be added") || !strings.Contains(text, "#1 (el perro) is a duplicate") ||
+		!strings.Contains(text, "#4 () can't be added: cannot create note because it is empty") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	if len(mock.notes) != notes {
+		t.Errorf("Expected no notes to be added, got %d", len(mock.notes)-notes)
+	}
+}
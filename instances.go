@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// defaultInstance is the name of the AnkiConnect endpoint set with
+// ANKI_CONNECT_URL
+const defaultInstance = "default"
+
+// instancePingTimeout is how long list_instances waits for an endpoint
+const instancePingTimeout = 3 * time.Second
+
+// InstanceConfig is a further AnkiConnect endpoint, e.g. a headless Anki in
+// Docker next to the desktop app
+type InstanceConfig struct {
+	// Name selects the endpoint with the instance parameter of the tools
+	Name string `json:"name"`
+	// URL is the endpoint's AnkiConnect URL
+	URL string `json:"url"`
+	// APIKey is sent when the endpoint requires an API key
+	APIKey string `json:"api_key"`
+}
+
+// validateInstances checks that every instance has a unique name and a URL
+func validateInstances(instances []InstanceConfig) error {
+	seen := map[string]bool{defaultInstance: true}
+	for i, instance := range instances {
+		name := strings.TrimSpace(instance.Name)
+		if name == "" {
+			return fmt.Errorf("instance %d needs a name", i+1)
+		}
+		if seen[name] {
+			return fmt.Errorf("instance %d: the name %q is already taken", i+1, name)
+		}
+		seen[name] = true
+		if strings.TrimSpace(instance.URL) == "" {
+			return fmt.Errorf("instance %s needs a url", name)
+		}
+	}
+	return nil
+}
+
+// newAnkiClient creates an AnkiConnect client for an endpoint with the
+// configured retries, timeouts and rate limit
+func newAnkiClient(config Config, url, key string) *AnkiConnect {
+	ankiClient := NewAnkiConnectWithURL(url)
+	ankiClient.Key = key
+	if config.Retry.Attempts > 0 {
+		ankiClient.Retry = config.Retry
+	}
+	if config.Timeout > 0 {
+		ankiClient.Timeout = config.Timeout
+	}
+	for action, timeout := range config.ActionTimeouts {
+		ankiClient.ActionTimeouts[action] = timeout
+	}
+	ankiClient.limiter = newRateLimiter(config.RateLimit)
+	ankiClient.history = &changeHistory{}
+	ankiClient.sanitizer = newHTMLSanitizer(config.Sanitize)
+	return ankiClient
+}
+
+// withClient returns a copy of the server that talks to Anki through client.
+// The copy keeps every setting but not the list of instances, which only the
+// default server has.
+func (a *AnkiMCPServer) withClient(client *AnkiConnect) *AnkiMCPServer {
+	c := *a
+	c.ankiClient = client
+	c.instances = nil
+	return &c
+}
+
+// newInstance returns the server of a further endpoint. Its deck limits and
+// sync state are kept apart from those of the other endpoints.
+func (a *AnkiMCPServer) newInstance(name string, client *AnkiConnect) *AnkiMCPServer {
+	instance := a.withClient(client)
+	instance.instanceName = name
+	instance.stateDir = filepath.Join(a.stateDir, "instances", name)
+	instance.limitsMu = &sync.Mutex{}
+	return instance
+}
+
+// instance returns the server of the named endpoint; an empty name selects
+// the default one
+func (a *AnkiMCPServer) instance(name string) (*AnkiMCPServer, bool) {
+	if name == "" {
+		return a, true
+	}
+	for _, instance := range a.instances {
+		if instance.instanceName == name {
+			return instance, true
+		}
+	}
+	return nil, false
+}
+
+// instanceNames returns the names of all endpoints, starting with the default
+// one
+func (a *AnkiMCPServer) instanceNames() []string {
+	names := make([]string, len(a.instances))
+	for i, instance := range a.instances {
+		names[i] = instance.instanceName
+	}
+	return names
+}
+
+// toolHandler is a tool handler that is called on a given server, so it can
+// be run against the selected endpoint or a client that only records changes
+type toolHandler func(*AnkiMCPServer, context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+
+// addTool registers a tool that works on the collection. With more than one
+// endpoint configured, it gets an instance parameter selecting the endpoint.
+// The handler's context ends when the client cancels the call, and the
+// response notes when its requests were throttled by the rate limit.
+func (a *AnkiMCPServer) addTool(s *server.MCPServer, tool mcp.Tool, handler toolHandler) {
+	if len(a.instances) > 1 {
+		mcp.WithString("instance",
+			mcp.Description("Optional: Name of the AnkiConnect endpoint to use, see list_instances (default: default)"),
+			mcp.Enum(a.instanceNames()...),
+		)(&tool)
+	}
+
+	s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, _ := request.GetArguments()["instance"].(string)
+		instance, ok := a.instance(name)
+		if !ok {
+			return a.errorf("Unknown instance %s; the instances are %s", name, strings.Join(a.instanceNames(), ", ")), nil
+		}
+		ctx, cancel := a.calls.context(ctx, request)
+		defer cancel()
+		throttled := &throttleLog{}
+		result, err := handler(instance.withClient(instance.ankiClient.withThrottleLog(throttled)), ctx, request)
+		if waited := throttled.total(); result != nil && waited >= throttleNoteThreshold {
+			a.noteThrottled(request, result, waited)
+		}
+		return result, err
+	})
+}
+
+// registerInstanceTools registers the tool that lists the AnkiConnect
+// endpoints
+func (a *AnkiMCPServer) registerInstanceTools(s *server.MCPServer) {
+	// Tool: List Instances
+	listInstancesTool := mcp.NewTool("list_instances",
+		mcp.WithDescription("List the configured AnkiConnect endpoints and whether each one is reachable. Pass the name as instance to other tools to use that endpoint."),
+		withFormat(),
+	)
+	s.AddTool(listInstancesTool, a.handleListInstances)
+}
+
+// instanceStatus is an endpoint in the output of list_instances
+type instanceStatus struct {
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	Available bool   `json:"available"`
+	Version   int    `json:"version,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// handleListInstances checks every endpoint at once and lists whether it is
+// reachable
+func (a *AnkiMCPServer) handleListInstances(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	statuses := make([]instanceStatus, len(a.instances))
+	var wg sync.WaitGroup
+	for i, instance := range a.instances {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// A single short attempt, so an endpoint that is down doesn't
+			// hold up the list
+			client := *instance.ankiClient
+			client.Retry = RetryPolicy{Attempts: 1}
+			client.Timeout = instancePingTimeout
+			client.ActionTimeouts = nil
+			client.launcher = nil
+			status := instanceStatus{Name: instance.instanceName, URL: client.URL}
+			version, err := invoke[int](&client, "version", nil)
+			if err != nil {
+				status.Error = err.Error()
+			} else {
+				status.Available = true
+				status.Version = version
+			}
+			statuses[i] = status
+		}()
+	}
+	wg.Wait()
+
+	if wantsJSON(request) {
+		return a.jsonResult(map[string]interface{}{"instances": statuses}), nil
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("AnkiConnect instances (%d)", len(statuses)))
+	for _, status := range statuses {
+		if status.Available {
+			out.Item(a.t("%s (%s): available, AnkiConnect version %d", status.Name, status.URL, status.Version))
+		} else {
+			out.Item(a.t("%s (%s): not available: %s", status.Name, status.URL, status.Error))
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
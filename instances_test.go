@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestInstanceConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"instances": [{"name": "docker", "url": "http://localhost:8766", "api_key": "secret"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var config Config
+	if err := loadConfigFile(path, &config); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Instances) != 1 || config.Instances[0] != (InstanceConfig{Name: "docker", URL: "http://localhost:8766", APIKey: "secret"}) {
+		t.Errorf("Unexpected instances: %+v", config.Instances)
+	}
+
+	for _, instances := range [][]InstanceConfig{
+		{{URL: "http://localhost:8766"}},
+		{{Name: "docker"}},
+		{{Name: "default", URL: "http://localhost:8766"}},
+		{{Name: "docker", URL: "http://localhost:8766"}, {Name: "docker", URL: "http://localhost:8767"}},
+	} {
+		if err := validateInstances(instances); err == nil {
+			t.Errorf("Expected an error for %+v", instances)
+		}
+	}
+}
+
+func TestInstances(t *testing.T) {
+	headless := newMockAnkiConnect()
+	anki := httptest.NewServer(headless)
+	t.Cleanup(anki.Close)
+	down := httptest.NewServer(nil)
+	down.Close()
+
+	c, desktop := newIntegrationClientWith(t, func(config *Config) {
+		config.Instances = []InstanceConfig{
+			{Name: "docker", URL: anki.URL},
+			{Name: "down", URL: down.URL},
+		}
+	})
+
+	tools, err := c.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tool := range tools.Tools {
+		_, hasInstance := tool.InputSchema.Properties["instance"]
+		if hasInstance == (tool.Name == "list_instances") {
+			t.Errorf("Tool %s: instance parameter %v", tool.Name, hasInstance)
+		}
+	}
+
+	text, isErr := callMCPTool(t, c, "list_instances", map[string]interface{}{"format": "json"})
+	var list struct {
+		Instances []instanceStatus `json:"instances"`
+	}
+	if isErr || json.Unmarshal([]byte(text), &list) != nil || len(list.Instances) != 3 {
+		t.Fatalf("list_instances: %s", text)
+	}
+	for i, want := range []bool{true, true, false} {
+		if list.Instances[i].Available != want {
+			t.Errorf("Instance %s: available %v, want %v", list.Instances[i].Name, list.Instances[i].Available, want)
+		}
+	}
+	if list.Instances[0].Name != defaultInstance || list.Instances[1].URL != anki.URL {
+		t.Errorf("Unexpected instances: %+v", list.Instances)
+	}
+
+	text, isErr = callMCPTool(t, c, "create_deck", map[string]interface{}{"name": "Headless", "instance": "docker"})
+	if isErr {
+		t.Fatalf("create_deck: %s", text)
+	}
+	if _, ok := headless.decks["Headless"]; !ok {
+		t.Error("Expected the deck on the docker instance")
+	}
+	if _, ok := desktop.decks["Headless"]; ok {
+		t.Error("Expected no deck on the default instance")
+	}
+
+	text, _ = callMCPTool(t, c, "get_deck_tree", map[string]interface{}{"instance": "docker"})
+	if !strings.Contains(text, "Headless") || strings.Contains(text, "Spanish") {
+		t.Errorf("get_deck_tree on docker: %s", text)
+	}
+	text, _ = callMCPTool(t, c, "get_deck_tree", map[string]interface{}{})
+	if !strings.Contains(text, "Spanish") {
+		t.Errorf("get_deck_tree on the default instance: %s", text)
+	}
+
+	text, isErr = callMCPTool(t, c, "delete_deck", map[string]interface{}{"deck": "Headless", "confirm": true, "dry_run": true, "instance": "docker"})
+	if _, ok := headless.decks["Headless"]; isErr || !ok {
+		t.Errorf("Dry run on docker: %s", text)
+	}
+
+	text, isErr = callMCPTool(t, c, "get_deck_tree", map[string]interface{}{"instance": "nope"})
+	if !isErr || !strings.Contains(text, "default, docker, down") {
+		t.Errorf("Expected an error for an unknown instance, got %s", text)
+	}
+}
+
+func TestNoInstanceParameterWithOneEndpoint(t *testing.T) {
+	c, _ := newIntegrationClient(t)
+
+	tools, err := c.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tool := range tools.Tools {
+		if _, ok := tool.InputSchema.Properties["instance"]; ok {
+			t.Errorf("Tool %s has an instance parameter with a single endpoint", tool.Name)
+		}
+	}
+}
+
+func TestWithClientKeepsSettings(t *testing.T) {
+	server, _ := newMockServer(t)
+	server.recipes = []RecipeConfig{{Name: "vocab", Model: "Basic", Fields: map[string]string{"Front": "{{word}}"}}}
+	server.vocab = VocabConfig{Model: "Basic", Fields: map[string]string{"word": "Front", "translation": "Back"}}
+	server.dryRun = true
+	server.instances = []*AnkiMCPServer{server}
+
+	client := NewAnkiConnectWithURL("http://localhost:8766")
+	c := server.withClient(client)
+	if c.ankiClient != client || c.instances != nil {
+		t.Fatalf("Expected the copy to use the new client and no instances")
+	}
+	// Every other field must be carried over, including ones added later
+	c.ankiClient, c.instances = server.ankiClient, server.instances
+	if !reflect.DeepEqual(*c, *server) {
+		t.Errorf("withClient dropped settings: %+v", *c)
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// newIntegrationClient starts the demo collection as an AnkiConnect HTTP
+// server and returns an MCP client talking to a server configured against
+// it, so tool calls go through argument parsing, the handler and real HTTP
+// requests to AnkiConnect
+func newIntegrationClient(t *testing.T) (*client.Client, *mockAnkiConnect) {
+	t.Helper()
+	return newIntegrationClientWith(t, nil)
+}
+
+// newIntegrationClientWith is newIntegrationClient with configure applied to
+// the server configuration first
+func newIntegrationClientWith(t *testing.T, configure func(*Config)) (*client.Client, *mockAnkiConnect) {
+	t.Helper()
+	mock := newMockAnkiConnect()
+	mock.seedDemo()
+	anki := httptest.NewServer(mock)
+	t.Cleanup(anki.Close)
+
+	config := Config{
+		AnkiConnectURL: anki.URL,
+		Retry:          RetryPolicy{Attempts: 1},
+		Language:       defaultLanguage,
+		OutputStyle:    outputMarkdown,
+		StateDir:       t.TempDir(),
+	}
+	if configure != nil {
+		configure(&config)
+	}
+	server := NewAnkiMCPServerWithConfig(config)
+	c, err := client.NewInProcessClient(server.newMCPServer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	var init mcp.InitializeRequest
+	init.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	init.Params.ClientInfo = mcp.Implementation{Name: "integration-test", Version: "1"}
+	if _, err := c.Initialize(ctx, init); err != nil {
+		t.Fatal(err)
+	}
+	return c, mock
+}
+
+// callMCPTool calls a tool by name and returns its text output
+func callMCPTool(t *testing.T, c *client.Client, name string, args map[string]interface{}) (string, bool) {
+	t.Helper()
+	var request mcp.CallToolRequest
+	request.Params.Name = name
+	request.Params.Arguments = args
+	result, err := c.CallTool(context.Background(), request)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	var text strings.Builder
+	for _, content := range result.Content {
+		if tc, ok := content.(mcp.TextContent); ok {
+			text.WriteString(tc.Text)
+		}
+	}
+	return text.String(), result.IsError
+}
+
+func TestIntegrationToolList(t *testing.T) {
+	c, _ := newIntegrationClient(t)
+
+	tools, err := c.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make(map[string]bool, len(tools.Tools))
+	for _, tool := range tools.Tools {
+		if tool.Description == "" {
+			t.Errorf("Tool %s has no description", tool.Name)
+		}
+		names[tool.Name] = true
+	}
+	for _, name := range []string{"create_card", "get_deck_tree", "get_due_cards", "explain_card", "sync"} {
+		if !names[name] {
+			t.Errorf("Tool %s is not registered", name)
+		}
+	}
+}
+
+func TestIntegrationCardLifecycle(t *testing.T) {
+	c, mock := newIntegrationClient(t)
+
+	text, isErr := callMCPTool(t, c, "create_card", map[string]interface{}{
+		"deck":  "Spanish::Vocabulary",
+		"front": "la mesa",
+		"back":  "the table",
+		"tags":  []interface{}{"furniture"},
+	})
+	if isErr || !strings.Contains(text, "Created") {
+		t.Fatalf("create_card: %s", text)
+	}
+	cards, _ := mock.search(`"la mesa"`)
+	if len(cards) != 1 {
+		t.Fatalf("Expected 1 new card, got %d", len(cards))
+	}
+	cardID := float64(cards[0].ID)
+
+	text, _ = callMCPTool(t, c, "explain_card", map[string]interface{}{"card_id": cardID})
+	if !strings.Contains(text, "State: new") || !strings.Contains(text, "Spanish::Vocabulary") {
+		t.Errorf("explain_card: %s", text)
+	}
+	text, _ = callMCPTool(t, c, "explain_card", map[string]interface{}{"card_id": float64(1)})
+	if !strings.Contains(text, "Card 1 not found") {
+		t.Errorf("explain_card for an unknown card: %s", text)
+	}
+
+	text, isErr = callMCPTool(t, c, "suspend_cards", map[string]interface{}{"card_ids": []interface{}{cardID}})
+	if isErr || cards[0].Queue != -1 {
+		t.Errorf("suspend_cards: %s", text)
+	}
+	text, _ = callMCPTool(t, c, "get_card_info", map[string]interface{}{"card_ids": []interface{}{cardID}, "format": "json"})
+	var info struct {
+		Cards []cardDetails `json:"cards"`
+	}
+	if err := json.Unmarshal([]byte(text), &info); err != nil || len(info.Cards) != 1 || info.Cards[0].Queue != "suspended" {
+		t.Errorf("get_card_info: %s", text)
+	}
+
+	text, isErr = callMCPTool(t, c, "delete_notes", map[string]interface{}{"query": `"la mesa"`, "confirm": true})
+	if isErr || len(mock.cards) != 14 {
+		t.Errorf("delete_notes: %s", text)
+	}
+}
+
+func TestIntegrationReviewLogRoundTrip(t *testing.T) {
+	c, mock := newIntegrationClient(t)
+
+	text, isErr := callMCPTool(t, c, "export_review_log", map[string]interface{}{"query": "deck:Spanish::Vocabulary"})
+	if isErr || !strings.HasPrefix(text, strings.Join(reviewLogCSVHeader, ",")) {
+		t.Fatalf("export_review_log: %s", text)
+	}
+	exported := strings.Count(strings.TrimSpace(text), "\n")
+	if exported == 0 {
+		t.Fatalf("Expected reviews in the export, got %s", text)
+	}
+
+	cards, _ := mock.search(`"deck:Spanish::Grammar"`)
+	data := fmt.Sprintf("card_id,review_time,ease,interval\n%d,2024-05-01,3,4\n%d,2024-05-05,1,-600\n", cards[0].ID, cards[0].ID)
+	before := len(mock.reviews)
+	text, isErr = callMCPTool(t, c, "import_reviews", map[string]interface{}{"data": data})
+	if isErr || text != "Imported 2 reviews for 1 cards" || len(mock.reviews) != before+2 {
+		t.Errorf("import_reviews: %s", text)
+	}
+	text, isErr = callMCPTool(t, c, "import_reviews", map[string]interface{}{"data": "card_id,review_time\n"})
+	if !isErr {
+		t.Errorf("Expected an error for input without reviews, got %s", text)
+	}
+}
+
+func TestIntegrationCheckDatabase(t *testing.T) {
+	c, _ := newIntegrationClient(t)
+
+	text, isErr := callMCPTool(t, c, "check_database", map[string]interface{}{})
+	if !isErr || !strings.Contains(text, "confirm") {
+		t.Errorf("Expected check_database to require confirmation, got %s", text)
+	}
+	text, isErr = callMCPTool(t, c, "check_database", map[string]interface{}{"confirm": true})
+	if isErr || !strings.Contains(text, "Started Anki's database check") {
+		t.Errorf("check_database: %s", text)
+	}
+}
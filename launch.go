@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultLaunchWait is how long to wait for AnkiConnect after starting Anki
+const defaultLaunchWait = 60 * time.Second
+
+// launchPollInterval is how often AnkiConnect is checked while Anki starts
+const launchPollInterval = 500 * time.Millisecond
+
+// LaunchConfig starts Anki when AnkiConnect refuses the connection, so tools
+// work without opening Anki first
+type LaunchConfig struct {
+	// Enabled turns auto-launch on; ANKI_MCP_AUTO_LAUNCH overrides it
+	Enabled bool `json:"enabled"`
+	// Paths maps an operating system as named by Go (darwin, linux, windows)
+	// to the Anki program on it. ANKI_MCP_ANKI_PATH overrides the entry of
+	// the current system. On macOS the path may be an app bundle.
+	Paths map[string]string `json:"paths"`
+	// Wait is how long to wait for AnkiConnect after starting Anki, e.g. 90s
+	// (default: 60s)
+	Wait string `json:"wait"`
+}
+
+// validate checks the wait duration
+func (c LaunchConfig) validate() error {
+	if c.Wait == "" {
+		return nil
+	}
+	if d, err := time.ParseDuration(c.Wait); err != nil || d <= 0 {
+		return fmt.Errorf("launch: wait must be a duration such as 90s, got %q", c.Wait)
+	}
+	return nil
+}
+
+// waitDuration returns how long to wait for AnkiConnect after starting Anki
+func (c LaunchConfig) waitDuration() time.Duration {
+	if d, err := time.ParseDuration(c.Wait); err == nil && d > 0 {
+		return d
+	}
+	return defaultLaunchWait
+}
+
+// command returns the command line that starts Anki on the current system
+func (c LaunchConfig) command() []string {
+	path := c.Paths[runtime.GOOS]
+	if path == "" {
+		path = defaultAnkiPath(runtime.GOOS)
+	}
+	if runtime.GOOS == "darwin" && strings.HasSuffix(path, ".app") {
+		return []string{"open", "-a", path}
+	}
+	return []string{path}
+}
+
+// defaultAnkiPath returns where the Anki installer puts the program
+func defaultAnkiPath(goos string) string {
+	switch goos {
+	case "darwin":
+		return "/Applications/Anki.app"
+	case "windows":
+		return filepath.Join(os.Getenv("LOCALAPPDATA"), "Programs", "Anki", "anki.exe")
+	default:
+		return "anki"
+	}
+}
+
+// isLocalURL reports whether an AnkiConnect URL points to this machine, the
+// only place where starting Anki can help
+func isLocalURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ankiLauncher starts Anki and waits for AnkiConnect to come up. It is shared
+// by the copies of a client, so concurrent calls start Anki only once.
+type ankiLauncher struct {
+	command []string
+	wait    time.Duration
+	// start runs the command without waiting for it to exit
+	start func(command []string) error
+
+	mu sync.Mutex
+}
+
+// newAnkiLauncher creates a launcher for the configured Anki program
+func newAnkiLauncher(config LaunchConfig) *ankiLauncher {
+	return &ankiLauncher{
+		command: config.command(),
+		wait:    config.waitDuration(),
+		start:   startDetached,
+	}
+}
+
+// startDetached starts a program that keeps running after the server exits
+func startDetached(command []string) error {
+	cmd := exec.Command(command[0], command[1:]...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Reap the process once Anki is closed
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+// ensure starts Anki unless AnkiConnect answers already, e.g. because another
+// call started it meanwhile, and waits until AnkiConnect answers
+func (l *ankiLauncher) ensure(ac *AnkiConnect) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if ac.answers() {
+		return nil
+	}
+	logger := ac.logger()
+	logger.Info("Starting Anki", "command", strings.Join(l.command, " "))
+	if err := l.start(l.command); err != nil {
+		logger.Error("Anki could not be started", "command", strings.Join(l.command, " "), "error", err)
+		return fmt.Errorf("could not start %s: %w", l.command[0], err)
+	}
+
+	deadline := time.Now().Add(l.wait)
+	for !ac.answers() {
+		if time.Now().After(deadline) {
+			logger.Error("AnkiConnect did not come up after starting Anki", "wait", l.wait)
+			return fmt.Errorf("AnkiConnect did not answer within %s of starting Anki", l.wait)
+		}
+		time.Sleep(launchPollInterval)
+	}
+	logger.Info("Anki started")
+	return nil
+}
+
+// answers asks AnkiConnect for its version once, without retrying, and
+// reports whether it answered. An error from AnkiConnect, such as a wrong API
+// key, counts as an answer.
+func (ac *AnkiConnect) answers() bool {
+	body, err := json.Marshal(ankiRequest{Action: "version", Version: ac.Version, Key: ac.Key})
+	if err != nil {
+		return false
+	}
+	client := *ac.client
+	client.Timeout = 4 * launchPollInterval
+	_, err = ac.post(&client, bytes.NewReader(body))
+	if err == nil {
+		return true
+	}
+	kind := classifyError(err)
+	return kind != kindAnkiNotRunning && kind != kindAnkiBusy
+}
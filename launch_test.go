@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestIsLocalURL(t *testing.T) {
+	for rawURL, want := range map[string]bool{
+		"http://localhost:8765":    true,
+		"http://127.0.0.1:8765":    true,
+		"http://[::1]:8765":        true,
+		"http://192.168.1.20:8765": false,
+		"http://anki.example.com":  false,
+	} {
+		if got := isLocalURL(rawURL); got != want {
+			t.Errorf("isLocalURL(%q) = %v, want %v", rawURL, got, want)
+		}
+	}
+}
+
+func TestLaunchConfig(t *testing.T) {
+	if d := (LaunchConfig{}).waitDuration(); d != defaultLaunchWait {
+		t.Errorf("Expected the default wait, got %s", d)
+	}
+	if d := (LaunchConfig{Wait: "90s"}).waitDuration(); d != 90*time.Second {
+		t.Errorf("Expected 90s, got %s", d)
+	}
+	if err := (LaunchConfig{Wait: "soon"}).validate(); err == nil {
+		t.Error("Expected an error for a wait that is no duration")
+	}
+}
+
+func TestAutoLaunch(t *testing.T) {
+	// Reserve a port for the Anki that isn't running yet
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	mock := newMockAnkiConnect()
+	mock.seedDemo()
+	var starts atomic.Int32
+	server := NewAnkiMCPServerWithConfig(Config{
+		AnkiConnectURL: "http://" + addr,
+		Retry:          RetryPolicy{Attempts: 1},
+		Language:       defaultLanguage,
+		OutputStyle:    outputMarkdown,
+		StateDir:       t.TempDir(),
+		Launch:         LaunchConfig{Enabled: true, Wait: "5s"},
+	})
+	server.ankiClient.launcher.start = func(command []string) error {
+		starts.Add(1)
+		// Anki takes a moment until AnkiConnect listens
+		go func() {
+			time.Sleep(300 * time.Millisecond)
+			listener, err := net.Listen("tcp", addr)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			anki := &http.Server{Handler: mock}
+			t.Cleanup(func() { _ = anki.Close() })
+			_ = anki.Serve(listener)
+		}()
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			text, isErr := callTool(t, server.handleGetDeckTree, map[string]interface{}{})
+			if isErr || !strings.Contains(text, "Spanish") {
+				t.Errorf("Expected the decks once Anki is up, got %s", text)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := starts.Load(); n != 1 {
+		t.Errorf("Expected Anki to be started once, got %d", n)
+	}
+}
+
+func TestAutoLaunchFails(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	server := NewAnkiMCPServerWithConfig(Config{
+		AnkiConnectURL: "http://" + addr,
+		Retry:          RetryPolicy{Attempts: 1},
+		Language:       defaultLanguage,
+		OutputStyle:    outputMarkdown,
+		StateDir:       t.TempDir(),
+		Launch:         LaunchConfig{Enabled: true, Paths: map[string]string{"linux": "/nonexistent/anki", "darwin": "/nonexistent/anki", "windows": `C:\nonexistent\anki.exe`}},
+	})
+	result, err := server.handleGetDeckTree(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !result.IsError || !strings.Contains(text, "could not start") || result.Meta["error_kind"] != string(kindAnkiNotRunning) {
+		t.Errorf("Expected the launch failure with the diagnosis, got %s", text)
+	}
+
+	remote := NewAnkiMCPServerWithConfig(Config{AnkiConnectURL: "http://192.168.1.20:8765", Language: defaultLanguage, Launch: LaunchConfig{Enabled: true}})
+	if remote.ankiClient.launcher != nil {
+		t.Error("Expected no launcher for an AnkiConnect on another machine")
+	}
+}
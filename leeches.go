@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// leechTag is the tag Anki adds to notes whose cards reach the leech threshold
+const leechTag = "leech"
+
+// Actions find_leeches can apply to the leeches it lists
+const (
+	leechActionNone    = "none"
+	leechActionSuspend = "suspend"
+	leechActionReset   = "reset"
+)
+
+// defaultLeechLimit is the number of leeches listed when no limit is given
+const defaultLeechLimit = 20
+
+// leechCard describes a leech with the review pattern behind it
+type leechCard struct {
+	CardID         int64    `json:"card_id"`
+	NoteID         int64    `json:"note_id"`
+	Deck           string   `json:"deck"`
+	Question       string   `json:"question"`
+	Answer         string   `json:"answer"`
+	Lapses         int      `json:"lapses"`
+	Reviews        int      `json:"reviews"`
+	Ease           int      `json:"ease"`
+	Suspended      bool     `json:"suspended"`
+	AverageSeconds float64  `json:"average_seconds"`
+	RecentAnswers  []string `json:"recent_answers"`
+	Suggestions    []string `json:"suggestions"`
+}
+
+// registerLeechTools registers tools for finding and fixing leeches
+func (a *AnkiMCPServer) registerLeechTools(s *server.MCPServer) {
+	// Tool: Find Leeches
+	findLeechesTool := mcp.NewTool("find_leeches",
+		mcp.WithDescription("List leeches (cards the user keeps forgetting, tagged \"leech\" by Anki) with their lapse count, recent answers and suggestions for rewording them, worst first. "+
+			"Use it to help the user fix problem cards: reword them with update_note, or pass action=suspend to suspend the listed leeches or action=reset to make them new again and remove their leech tag."),
+		mcp.WithString("deck",
+			mcp.Description("Optional: Only leeches in this deck and its subdecks"),
+		),
+		mcp.WithString("query",
+			mcp.Description("Optional: Anki search query narrowing down the leeches"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Optional: Maximum number of leeches to list (default: %d)", defaultLeechLimit)),
+		),
+		mcp.WithString("action",
+			mcp.Description("Optional: What to do with the listed leeches: none (default), suspend, or reset"),
+			mcp.Enum(leechActionNone, leechActionSuspend, leechActionReset),
+		),
+		withFormat(),
+	)
+	a.addChangingTool(s, findLeechesTool, (*AnkiMCPServer).handleFindLeeches)
+}
+
+// handleFindLeeches lists leeches and optionally suspends or resets them
+func (a *AnkiMCPServer) handleFindLeeches(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	action, _ := args["action"].(string)
+	if action == "" {
+		action = leechActionNone
+	}
+	if action != leechActionNone && action != leechActionSuspend && action != leechActionReset {
+		return a.errorf("Invalid action %q: use none, suspend or reset", action), nil
+	}
+	limit := defaultLeechLimit
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	terms := []string{"tag:" + leechTag}
+	if deck, _ := args["deck"].(string); deck != "" {
+		terms = append(terms, deckQuery(deck))
+	}
+	if query, _ := args["query"].(string); strings.TrimSpace(query) != "" {
+		terms = append(terms, "("+query+")")
+	}
+	cardIDs, err := a.ankiClient.FindCards(strings.Join(terms, " "))
+	if err != nil {
+		return a.errorf("Failed to find cards: %v", err), nil
+	}
+
+	var infos []CardInfo
+	if len(cardIDs) > 0 {
+		infos, err = a.ankiClient.GetCardsInfo(cardIDs)
+		if err != nil {
+			return a.errorf("Failed to get card info: %v", err), nil
+		}
+	}
+
+	// The tag is on the note, so siblings that were never forgotten are not
+	// leeches themselves
+	var leeches []leechCard
+	for _, info := range infos {
+		if info.Lapses == 0 {
+			continue
+		}
+		leeches = append(leeches, leechCard{
+			CardID:    info.CardID,
+			NoteID:    info.NoteID,
+			Deck:      info.DeckName,
+			Question:  cardText(info.Question),
+			Answer:    answerText(info.Answer),
+			Lapses:    info.Lapses,
+			Reviews:   info.Reps,
+			Ease:      info.Factor / 10,
+			Suspended: info.Queue == -1,
+		})
+	}
+	sort.SliceStable(leeches, func(i, j int) bool {
+		if leeches[i].Lapses != leeches[j].Lapses {
+			return leeches[i].Lapses > leeches[j].Lapses
+		}
+		return leeches[i].CardID < leeches[j].CardID
+	})
+	total := len(leeches)
+	if len(leeches) > limit {
+		leeches = leeches[:limit]
+	}
+
+	ids := make([]int64, len(leeches))
+	for i, l := range leeches {
+		ids[i] = l.CardID
+	}
+	if len(ids) > 0 {
+		reviews, err := a.ankiClient.GetReviewsOfCards(ids)
+		if err != nil {
+			return a.errorf("Failed to get reviews: %v", err), nil
+		}
+		for i := range leeches {
+			a.describeLeech(&leeches[i], reviews[leeches[i].CardID])
+		}
+	}
+
+	var actionText string
+	switch {
+	case len(ids) == 0 || action == leechActionNone:
+	case action == leechActionSuspend:
+		var suspend []int64
+		for _, l := range leeches {
+			if !l.Suspended {
+				suspend = append(suspend, l.CardID)
+			}
+		}
+		if len(suspend) > 0 {
+			if _, err := a.ankiClient.SuspendCards(suspend); err != nil {
+				return a.errorf("Failed to suspend cards: %v", err), nil
+			}
+		}
+		for i := range leeches {
+			leeches[i].Suspended = true
+		}
+		actionText = a.t("Suspended %d card(s)", len(suspend))
+	case action == leechActionReset:
+		if err := a.ankiClient.ForgetCards(ids); err != nil {
+			return a.errorf("Failed to reset cards: %v", err), nil
+		}
+		noteIDs := make([]int64, 0, len(leeches))
+		for _, l := range leeches {
+			noteIDs = append(noteIDs, l.NoteID)
+		}
+		if err := a.ankiClient.RemoveTags(noteIDs, []string{leechTag}); err != nil {
+			return a.errorf("Reset %d card(s), but failed to remove the leech tag: %v", len(ids), err), nil
+		}
+		actionText = a.t("Reset %d card(s) to new and removed their leech tag", len(ids))
+	}
+
+	if wantsJSON(request) {
+		if leeches == nil {
+			leeches = []leechCard{}
+		}
+		return a.jsonResult(map[string]interface{}{
+			"total":   total,
+			"leeches": leeches,
+			"action":  action,
+		}), nil
+	}
+
+	out := a.newOutput()
+	if total == 0 {
+		out.Line(a.t("No leeches found"))
+	} else {
+		out.Heading(a.t("Leeches: showing %d of %d", len(leeches), total))
+	}
+	for _, l := range leeches {
+		out.Heading(a.t("Card %d (%s)", l.CardID, l.Deck))
+		out.Item(a.t("Question: %s", l.Question))
+		out.Item(a.t("Answer: %s", l.Answer))
+		status := a.t("Lapses: %d, reviews: %d, ease: %d%%, %.0fs per answer", l.Lapses, l.Reviews, l.Ease, l.AverageSeconds)
+		if l.Suspended {
+			status += " " + a.t("(suspended)")
+		}
+		out.Item(status)
+		if len(l.RecentAnswers) > 0 {
+			out.Item(a.t("Recent answers: %s", strings.Join(l.RecentAnswers, ", ")))
+		}
+		for _, s := range l.Suggestions {
+			out.Item(a.t("Suggestion: %s", s))
+		}
+	}
+	if actionText != "" {
+		out.Line("")
+		out.Line(actionText)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// describeLeech fills in a leech's answer pattern from its review history and
+// suggests how to fix it
+func (a *AnkiMCPServer) describeLeech(l *leechCard, reviews []ReviewEntry) {
+	sort.Slice(reviews, func(i, j int) bool {
+		return reviews[i].ReviewTime < reviews[j].ReviewTime
+	})
+
+	var answered []ReviewEntry
+	for _, r := range reviews {
+		// Manual rescheduling is not an answer
+		if r.ReviewType != 4 {
+			answered = append(answered, r)
+		}
+	}
+	if len(answered) > 0 {
+		var total int64
+		for _, r := range answered {
+			total += r.ReviewDuration
+		}
+		l.AverageSeconds = float64(total) / float64(len(answered)) / 1000
+	}
+	recent := answered[max(0, len(answered)-10):]
+	l.RecentAnswers = make([]string, len(recent))
+	for i, r := range recent {
+		l.RecentAnswers[i] = a.easeLabel(r.ButtonPressed)
+	}
+
+	l.Suggestions = []string{}
+	if words := len(strings.Fields(l.Answer)); words > 12 {
+		l.Suggestions = append(l.Suggestions, a.t("The answer has %d words. Split it into smaller cards or turn it into a cloze deletion.", words))
+	}
+	if l.AverageSeconds > 15 {
+		l.Suggestions = append(l.Suggestions, a.t("Answering takes %.0fs on average. Add a hint, an example or a mnemonic to the question.", l.AverageSeconds))
+	}
+	if l.Ease > 0 && l.Ease <= 150 {
+		l.Suggestions = append(l.Suggestions, a.t("Its ease is at the minimum, so it keeps coming back. Reset it after rewording."))
+	}
+	if len(l.Suggestions) == 0 {
+		l.Suggestions = append(l.Suggestions, a.t("Reword the question so it has one unambiguous answer, or add context such as an example sentence."))
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFindLeeches(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	if text, isErr := callTool(t, server.handleFindLeeches, map[string]interface{}{}); isErr || text != "No leeches found" {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+
+	// Turn the forward cards of two notes into leeches; their reverse cards were
+	// never forgotten
+	var leechIDs []int64
+	for _, note := range mock.notes {
+		if note.Fields["Front"] != "el perro" && note.Fields["Front"] != "el gato" {
+			continue
+		}
+		note.Tags = append(note.Tags, leechTag)
+		for id, card := range mock.cards {
+			if card.NoteID == note.ID && card.Ord == 0 {
+				card.Lapses, card.Factor = int64(8+len(leechIDs)), 1300
+				mock.reviews = append(mock.reviews, ReviewEntry{ReviewTime: int64(len(mock.reviews) + 1), CardID: id, ButtonPressed: 1, ReviewDuration: 30000, ReviewType: 1})
+				leechIDs = append(leechIDs, id)
+			}
+		}
+	}
+	if len(leechIDs) != 2 {
+		t.Fatalf("Expected 2 leech cards, got %d", len(leechIDs))
+	}
+
+	text, isErr := callTool(t, server.handleFindLeeches, map[string]interface{}{"deck": "Spanish"})
+	if isErr || !strings.Contains(text, "Leeches: showing 2 of 2") || !strings.Contains(text, "Lapses: 9") ||
+		!strings.Contains(text, "Again") || !strings.Contains(text, "Suggestion: Its ease is at the minimum") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	if strings.Index(text, "Lapses: 9") > strings.Index(text, "Lapses: 8") {
+		t.Errorf("Expected the worst leech first: %s", text)
+	}
+
+	args := map[string]interface{}{"limit": float64(1), "action": "suspend"}
+	if text, _ := callTool(t, server.handleFindLeeches, args); !strings.Contains(text, "showing 1 of 2") || !strings.Contains(text, "Suspended 1 card(s)") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	if mock.cards[leechIDs[1]].Queue != -1 || mock.cards[leechIDs[0]].Queue == -1 {
+		t.Errorf("Expected only the worst leech to be suspended")
+	}
+
+	args = map[string]interface{}{"action": "reset"}
+	if text, _ := callTool(t, server.handleFindLeeches, args); !strings.Contains(text, "Reset 2 card(s) to new and removed their leech tag") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	for _, id := range leechIDs {
+		card := mock.cards[id]
+		if card.Type != 0 || slices.Contains(mock.notes[card.NoteID].Tags, leechTag) {
+			t.Errorf("Expected card %d to be new without the leech tag", id)
+		}
+	}
+}
@@ -0,0 +1,399 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// defaultRolloverHour is the hour of the day at which Anki starts a new day by
+// default
+const defaultRolloverHour = 4
+
+// limitRestoreRetry is how soon restoring deck limits is tried again when
+// Anki couldn't be reached at rollover
+const limitRestoreRetry = 15 * time.Minute
+
+// limitOverride records a deck that was switched to a temporary copy of its
+// options preset to change its limits for one Anki day
+type limitOverride struct {
+	Deck             string `json:"deck"`
+	Day              string `json:"day"`
+	OriginalConfigID int64  `json:"original_config_id"`
+	OriginalPreset   string `json:"original_preset"`
+	TempConfigID     int64  `json:"temp_config_id"`
+}
+
+// deckLimits holds a deck's daily limits
+type deckLimits struct {
+	NewCards int `json:"new_cards"`
+	Reviews  int `json:"reviews"`
+}
+
+// registerLimitTools registers tools that change a deck's daily limits
+func (a *AnkiMCPServer) registerLimitTools(s *server.MCPServer) {
+	// Tool: Extend Daily Limits
+	extendLimitsTool := mcp.NewTool("extend_daily_limits",
+		mcp.WithDescription("Let the user study more in a deck today, like Anki's custom study \"increase today's limit\" options, e.g. \"give me 20 more new cards in Spanish today\". "+
+			"The deck temporarily uses a copy of its options preset with higher limits; the original preset is restored automatically when the Anki day ends, "+
+			"or when the server next starts if it isn't running then."),
+		mcp.WithString("deck",
+			mcp.Required(),
+			mcp.Description("Name of the deck"),
+		),
+		mcp.WithNumber("new_cards",
+			mcp.Description("Optional: Number of extra new cards to allow today"),
+		),
+		mcp.WithNumber("reviews",
+			mcp.Description("Optional: Number of extra reviews to allow today"),
+		),
+	)
+	a.addChangingTool(s, extendLimitsTool, (*AnkiMCPServer).handleExtendDailyLimits)
+
+	// Tool: Boost Review Limit
+	boostReviewLimitTool := mcp.NewTool("boost_review_limit",
+		mcp.WithDescription("Set a deck's maximum reviews per day to a specific value for today only, e.g. to cram before an exam. "+
+			"The original limit is recorded and restored automatically when the Anki day ends, or when the server next starts if it isn't running then; "+
+			"restore_deck_limits restores it earlier."),
+		mcp.WithString("deck",
+			mcp.Required(),
+			mcp.Description("Name of the deck"),
+		),
+		mcp.WithNumber("review_limit",
+			mcp.Required(),
+			mcp.Description("Maximum number of reviews allowed today"),
+		),
+	)
+	a.addChangingTool(s, boostReviewLimitTool, (*AnkiMCPServer).handleBoostReviewLimit)
+
+	// Tool: Restore Deck Limits
+	restoreLimitsTool := mcp.NewTool("restore_deck_limits",
+		mcp.WithDescription("Undo temporary limit changes made by extend_daily_limits or boost_review_limit right away, putting decks back on their original options preset."),
+		mcp.WithString("deck",
+			mcp.Description("Optional: Only restore this deck (default: all decks with temporary limits)"),
+		),
+	)
+	a.addChangingTool(s, restoreLimitsTool, (*AnkiMCPServer).handleRestoreDeckLimits)
+}
+
+// handleExtendDailyLimits raises a deck's new card and review limits for today
+func (a *AnkiMCPServer) handleExtendDailyLimits(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	deckName, ok := args["deck"].(string)
+	if !ok || deckName == "" {
+		return a.errorf("deck is required"), nil
+	}
+	extraNew, _ := args["new_cards"].(float64)
+	extraReviews, _ := args["reviews"].(float64)
+	if extraNew < 0 || extraReviews < 0 || extraNew+extraReviews == 0 {
+		return a.errorf("new_cards or reviews must be a positive number"), nil
+	}
+
+	before, after, override, err := a.overrideDeckLimits(deckName, func(limits *deckLimits) {
+		limits.NewCards += int(extraNew)
+		limits.Reviews += int(extraReviews)
+	})
+	if err != nil {
+		return a.errorf("Failed to extend limits: %v", err), nil
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Extended today's limits for %s", deckName))
+	out.Item(a.t("New cards: %d → %d", before.NewCards, after.NewCards))
+	out.Item(a.t("Reviews: %d → %d", before.Reviews, after.Reviews))
+	out.Line("")
+	out.Line(a.t("These limits apply today only: the deck uses a temporary copy of its \"%s\" preset, and the original preset is restored when the Anki day ends.", override.OriginalPreset))
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleBoostReviewLimit sets a deck's review limit for today
+func (a *AnkiMCPServer) handleBoostReviewLimit(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	deckName, ok := args["deck"].(string)
+	if !ok || deckName == "" {
+		return a.errorf("deck is required"), nil
+	}
+	limit, ok := args["review_limit"].(float64)
+	if !ok || limit < 0 {
+		return a.errorf("review_limit must be a number of at least 0"), nil
+	}
+
+	before, after, override, err := a.overrideDeckLimits(deckName, func(limits *deckLimits) {
+		limits.Reviews = int(limit)
+	})
+	if err != nil {
+		return a.errorf("Failed to change review limit: %v", err), nil
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Changed today's review limit for %s", deckName))
+	out.Item(a.t("Reviews: %d → %d", before.Reviews, after.Reviews))
+	out.Line("")
+	out.Line(a.t("These limits apply today only: the deck uses a temporary copy of its \"%s\" preset, and the original preset is restored when the Anki day ends.", override.OriginalPreset))
+	out.Line(a.t("Use restore_deck_limits to restore it earlier."))
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleRestoreDeckLimits undoes temporary limit changes
+func (a *AnkiMCPServer) handleRestoreDeckLimits(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	deckName, _ := args["deck"].(string)
+
+	a.limitsMu.Lock()
+	restored, err := a.restoreLimitOverrides(false, deckName)
+	a.limitsMu.Unlock()
+	if err != nil && len(restored) == 0 {
+		return a.errorf("Failed to restore limits: %v", err), nil
+	}
+
+	out := a.newOutput()
+	if len(restored) == 0 {
+		out.Line(a.t("No temporary deck limits to restore"))
+	} else {
+		out.Heading(a.t("Restored limits for %d deck(s)", len(restored)))
+		for _, o := range restored {
+			out.Item(a.t("%s: back on preset \"%s\"", o.Deck, o.OriginalPreset))
+		}
+	}
+	if err != nil {
+		out.Line("")
+		out.Line(a.t("Some decks could not be restored and will be retried later: %v", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// ankiDay returns the Anki day a point in time belongs to, as a date, for a
+// day starting at the rollover hour
+func ankiDay(t time.Time, rolloverHour int) string {
+	return t.Add(-time.Duration(rolloverHour) * time.Hour).Format(dateLayout)
+}
+
+// nextRollover returns when the Anki day after the one of t starts
+func nextRollover(t time.Time, rolloverHour int) time.Time {
+	next := time.Date(t.Year(), t.Month(), t.Day(), rolloverHour, 0, 0, 0, t.Location())
+	if !next.After(t) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// restoreLimitsAtRollover undoes the temporary limit changes of earlier Anki
+// days right away and then whenever a day ends, for as long as the server
+// runs. If Anki can't be reached it tries again a little later; the next deck
+// limit tool call also restores them.
+func (a *AnkiMCPServer) restoreLimitsAtRollover() {
+	for {
+		a.limitsMu.Lock()
+		_, err := a.restoreLimitOverrides(true, "")
+		a.limitsMu.Unlock()
+
+		wait := time.Until(nextRollover(time.Now(), a.rolloverHour))
+		if err != nil {
+			a.logger.Warn("Failed to restore temporary deck limits", "error", err)
+			wait = min(wait, limitRestoreRetry)
+		}
+		time.Sleep(wait)
+	}
+}
+
+// limitsStatePath returns the file recording temporary limit changes
+func (a *AnkiMCPServer) limitsStatePath() string {
+	return filepath.Join(a.stateDir, "deck_limits.json")
+}
+
+// loadLimitOverrides reads the recorded temporary limit changes
+func (a *AnkiMCPServer) loadLimitOverrides() ([]limitOverride, error) {
+	data, err := os.ReadFile(a.limitsStatePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var overrides []limitOverride
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %w", a.limitsStatePath(), err)
+	}
+	return overrides, nil
+}
+
+// saveLimitOverrides records the temporary limit changes, except in a dry run
+func (a *AnkiMCPServer) saveLimitOverrides(overrides []limitOverride) error {
+	if a.dryRun {
+		return nil
+	}
+	if err := os.MkdirAll(a.stateDir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(overrides, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(a.limitsStatePath(), data, 0o644)
+}
+
+// overrideDeckLimits changes a deck's daily limits for the current Anki day.
+// The first change of the day switches the deck to a copy of its preset, so
+// other decks sharing the preset are unaffected; later changes on the same day
+// modify that copy. It returns the limits before and after the change.
+func (a *AnkiMCPServer) overrideDeckLimits(deck string, update func(*deckLimits)) (deckLimits, deckLimits, limitOverride, error) {
+	a.limitsMu.Lock()
+	defer a.limitsMu.Unlock()
+
+	if _, err := a.restoreLimitOverrides(true, ""); err != nil {
+		return deckLimits{}, deckLimits{}, limitOverride{}, err
+	}
+
+	overrides, err := a.loadLimitOverrides()
+	if err != nil {
+		return deckLimits{}, deckLimits{}, limitOverride{}, err
+	}
+
+	config, err := a.ankiClient.GetDeckConfig(deck)
+	if err != nil {
+		return deckLimits{}, deckLimits{}, limitOverride{}, err
+	}
+
+	var override *limitOverride
+	for i := range overrides {
+		if overrides[i].Deck == deck {
+			override = &overrides[i]
+		}
+	}
+
+	if override == nil {
+		day := ankiDay(time.Now(), a.rolloverHour)
+		originalID := int64(numberValue(config, "id"))
+		originalName := stringValue(config, "name")
+
+		tempID, err := a.ankiClient.CloneDeckConfigID(fmt.Sprintf("%s (%s, %s only)", originalName, deck, day), originalID)
+		if err != nil {
+			return deckLimits{}, deckLimits{}, limitOverride{}, err
+		}
+		// Record the copy before using it, so it is cleaned up even if a later step fails
+		overrides = append(overrides, limitOverride{
+			Deck:             deck,
+			Day:              day,
+			OriginalConfigID: originalID,
+			OriginalPreset:   originalName,
+			TempConfigID:     tempID,
+		})
+		override = &overrides[len(overrides)-1]
+		if err := a.saveLimitOverrides(overrides); err != nil {
+			return deckLimits{}, deckLimits{}, limitOverride{}, err
+		}
+		if err := a.ankiClient.SetDeckConfigID([]string{deck}, tempID); err != nil {
+			return deckLimits{}, deckLimits{}, limitOverride{}, err
+		}
+
+		config["id"] = float64(tempID)
+		config["name"] = fmt.Sprintf("%s (%s, %s only)", originalName, deck, day)
+	}
+
+	before := configLimits(config)
+	after := before
+	update(&after)
+	setConfigLimits(config, after)
+	if err := a.ankiClient.SaveDeckConfig(config); err != nil {
+		return deckLimits{}, deckLimits{}, limitOverride{}, err
+	}
+
+	return before, after, *override, nil
+}
+
+// restoreLimitOverrides puts decks back on their original presets and deletes
+// the temporary copies. With expiredOnly set, only changes made on earlier
+// Anki days are undone; a non-empty deck restricts restoring to that deck.
+// Changes that cannot be undone stay recorded so they are retried later.
+func (a *AnkiMCPServer) restoreLimitOverrides(expiredOnly bool, deck string) ([]limitOverride, error) {
+	overrides, err := a.loadLimitOverrides()
+	if err != nil || len(overrides) == 0 {
+		return nil, err
+	}
+
+	today := ankiDay(time.Now(), a.rolloverHour)
+	var restored, kept []limitOverride
+	var errs []error
+	for _, o := range overrides {
+		if (expiredOnly && o.Day >= today) || (deck != "" && o.Deck != deck) {
+			kept = append(kept, o)
+			continue
+		}
+
+		// A deleted deck or preset leaves nothing to switch back, but the copy
+		// must still go; decks using a removed preset fall back to the default.
+		// Any other failure keeps the deck on the copy until the next try.
+		if err := a.ankiClient.SetDeckConfigID([]string{o.Deck}, o.OriginalConfigID); err != nil && !errors.Is(err, errDeckConfigTarget) {
+			kept = append(kept, o)
+			errs = append(errs, fmt.Errorf("%s: %w", o.Deck, err))
+			continue
+		}
+		if err := a.ankiClient.RemoveDeckConfigID(o.TempConfigID); err != nil && !errors.Is(err, errDeckConfigTarget) {
+			kept = append(kept, o)
+			errs = append(errs, fmt.Errorf("%s: %w", o.Deck, err))
+			continue
+		}
+		restored = append(restored, o)
+	}
+
+	if len(restored) > 0 {
+		if err := a.saveLimitOverrides(kept); err != nil {
+			return restored, err
+		}
+	}
+	return restored, errors.Join(errs...)
+}
+
+// configLimits reads the daily limits of an options group
+func configLimits(config map[string]interface{}) deckLimits {
+	return deckLimits{
+		NewCards: int(numberValue(objectValue(config, "new"), "perDay")),
+		Reviews:  int(numberValue(objectValue(config, "rev"), "perDay")),
+	}
+}
+
+// setConfigLimits changes the daily limits of an options group
+func setConfigLimits(config map[string]interface{}, limits deckLimits) {
+	for section, value := range map[string]int{"new": limits.NewCards, "rev": limits.Reviews} {
+		settings, ok := config[section].(map[string]interface{})
+		if !ok {
+			settings = map[string]interface{}{}
+			config[section] = settings
+		}
+		settings["perDay"] = value
+	}
+}
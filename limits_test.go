@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingAction makes AnkiConnect report an error for one action. The
+// first skip requests for it go through, and then times requests fail, or
+// all of them when times is 0.
+type failingAction struct {
+	next   http.RoundTripper
+	action string
+	skip   int
+	times  int
+
+	seen int
+}
+
+func (f *failingAction) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	var r struct {
+		Action string `json:"action"`
+	}
+	if json.Unmarshal(body, &r) == nil && r.Action == f.action {
+		f.seen++
+	}
+	if r.Action == f.action && f.seen > f.skip && (f.times == 0 || f.seen <= f.skip+f.times) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"result": null, "error": "collection is locked"}`)),
+			Request:    req,
+		}, nil
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return f.next.RoundTrip(req)
+}
+
+func TestExtendDailyLimits(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	args := map[string]interface{}{"deck": "Spanish::Vocabulary", "new_cards": float64(20)}
+	text, isErr := callTool(t, server.handleExtendDailyLimits, args)
+	if isErr || !strings.Contains(text, "New cards: 20 → 40") || !strings.Contains(text, "Reviews: 200 → 200") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+
+	// A second extension on the same day reuses the temporary preset
+	args = map[string]interface{}{"deck": "Spanish::Vocabulary", "new_cards": float64(5), "reviews": float64(50)}
+	text, isErr = callTool(t, server.handleExtendDailyLimits, args)
+	if isErr || !strings.Contains(text, "New cards: 40 → 45") || !strings.Contains(text, "Reviews: 200 → 250") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+
+	tempID := mock.deckConfigID("Spanish::Vocabulary")
+	if tempID == 1 || len(mock.deckConfigs) != 3 {
+		t.Fatalf("Expected one temporary preset, got deck preset %d and %d presets", tempID, len(mock.deckConfigs))
+	}
+	if limits := configLimits(mock.deckConfigs[1]); limits.NewCards != 20 {
+		t.Errorf("Shared Default preset was modified: %+v", limits)
+	}
+
+	// Once the Anki day is over the original preset comes back
+	overrides, err := server.loadLimitOverrides()
+	if err != nil || len(overrides) != 1 {
+		t.Fatalf("Expected one recorded override, got %v (%v)", overrides, err)
+	}
+	overrides[0].Day = ankiDay(time.Now().AddDate(0, 0, -1), server.rolloverHour)
+	if err := server.saveLimitOverrides(overrides); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := server.restoreLimitOverrides(true, "")
+	if err != nil || len(restored) != 1 {
+		t.Fatalf("Expected one restored override, got %v (%v)", restored, err)
+	}
+	if id := mock.deckConfigID("Spanish::Vocabulary"); id != 1 {
+		t.Errorf("Expected the Default preset back, got %d", id)
+	}
+	if _, ok := mock.deckConfigs[tempID]; ok {
+		t.Error("Temporary preset was not removed")
+	}
+}
+
+func TestAnkiDay(t *testing.T) {
+	if day := ankiDay(time.Date(2024, 3, 10, 3, 59, 0, 0, time.Local), 4); day != "2024-03-09" {
+		t.Errorf("Expected the previous day before rollover, got %s", day)
+	}
+	if day := ankiDay(time.Date(2024, 3, 10, 4, 0, 0, 0, time.Local), 4); day != "2024-03-10" {
+		t.Errorf("Expected the same day after rollover, got %s", day)
+	}
+}
+
+func TestNextRollover(t *testing.T) {
+	tests := []struct {
+		now  time.Time
+		hour int
+		want time.Time
+	}{
+		{time.Date(2024, 3, 10, 3, 59, 0, 0, time.Local), 4, time.Date(2024, 3, 10, 4, 0, 0, 0, time.Local)},
+		{time.Date(2024, 3, 10, 4, 0, 0, 0, time.Local), 4, time.Date(2024, 3, 11, 4, 0, 0, 0, time.Local)},
+		{time.Date(2024, 3, 10, 23, 30, 0, 0, time.Local), 0, time.Date(2024, 3, 11, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		if got := nextRollover(tt.now, tt.hour); !got.Equal(tt.want) {
+			t.Errorf("nextRollover(%s, %d) = %s, want %s", tt.now, tt.hour, got, tt.want)
+		}
+	}
+}
+
+func TestRolloverHourConfig(t *testing.T) {
+	t.Setenv("ANKI_MCP_CONFIG", filepath.Join(t.TempDir(), "missing.json"))
+	t.Setenv("ANKI_MCP_ROLLOVER_HOUR", "")
+	if hour := loadConfig().RolloverHour; hour != defaultRolloverHour {
+		t.Errorf("Expected the default rollover hour, got %d", hour)
+	}
+	t.Setenv("ANKI_MCP_ROLLOVER_HOUR", "0")
+	if hour := loadConfig().RolloverHour; hour != 0 {
+		t.Errorf("Expected rollover at midnight, got %d", hour)
+	}
+	t.Setenv("ANKI_MCP_ROLLOVER_HOUR", "24")
+	if hour := loadConfig().RolloverHour; hour != defaultRolloverHour {
+		t.Errorf("Expected an invalid hour to be ignored, got %d", hour)
+	}
+}
+
+func TestBoostAndRestoreReviewLimit(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	args := map[string]interface{}{"deck": "Spanish::Grammar", "review_limit": float64(1000)}
+	text, isErr := callTool(t, server.handleBoostReviewLimit, args)
+	if isErr || !strings.Contains(text, "Reviews: 500 → 1000") || !strings.Contains(text, `"Exam cram"`) {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+
+	text, isErr = callTool(t, server.handleRestoreDeckLimits, map[string]interface{}{})
+	if isErr || !strings.Contains(text, `Spanish::Grammar: back on preset "Exam cram"`) {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	if id := mock.deckConfigID("Spanish::Grammar"); id != 2 {
+		t.Errorf("Expected the Exam cram preset back, got %d", id)
+	}
+	if len(mock.deckConfigs) != 2 {
+		t.Errorf("Expected the temporary preset to be removed, have %d presets", len(mock.deckConfigs))
+	}
+
+	text, _ = callTool(t, server.handleRestoreDeckLimits, map[string]interface{}{})
+	if !strings.Contains(text, "No temporary deck limits to restore") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+}
+
+func TestRestoreLimitsKeepsFailedOverrides(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+	args := map[string]interface{}{"deck": "Spanish::Vocabulary", "new_cards": float64(20)}
+	if text, isErr := callTool(t, server.handleExtendDailyLimits, args); isErr {
+		t.Fatal(text)
+	}
+	tempID := mock.deckConfigID("Spanish::Vocabulary")
+
+	// A failed switch back leaves the deck on its temporary preset rather
+	// than on Default, and the change is retried later
+	server.ankiClient.client.Transport = &failingAction{next: http.DefaultTransport, action: "setDeckConfigId"}
+	restored, err := server.restoreLimitOverrides(false, "")
+	if err == nil || len(restored) != 0 {
+		t.Fatalf("Expected the restore to fail, got %v (%v)", restored, err)
+	}
+	if id := mock.deckConfigID("Spanish::Vocabulary"); id != tempID {
+		t.Errorf("Expected the deck to stay on preset %d, got %d", tempID, id)
+	}
+	if overrides, _ := server.loadLimitOverrides(); len(overrides) != 1 {
+		t.Errorf("Expected the override to stay recorded, got %v", overrides)
+	}
+
+	server.ankiClient.client.Transport = nil
+	if restored, err := server.restoreLimitOverrides(false, ""); err != nil || len(restored) != 1 || mock.deckConfigID("Spanish::Vocabulary") != 1 {
+		t.Errorf("Expected the retry to restore the preset, got %v (%v)", restored, err)
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// relatedField is the note field holding links to related notes
+const relatedField = "Related"
+
+var noteLinkPattern = regexp.MustCompile(`nid:(\d+)`)
+
+// relatedNote is the JSON representation of a linked note
+type relatedNote struct {
+	NoteID  int64    `json:"note_id"`
+	Exists  bool     `json:"exists"`
+	Model   string   `json:"model,omitempty"`
+	Summary string   `json:"summary,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// registerLinkTools registers note linking tools with the MCP server
+func (a *AnkiMCPServer) registerLinkTools(s *server.MCPServer) {
+	// Tool: Link Notes
+	linkNotesTool := mcp.NewTool("link_notes",
+		mcp.WithDescription("Add or remove cross-references between notes, e.g. to build a concept map across a deck. "+
+			"Links are stored as nid:<id> entries in a field named \"Related\", which the note types involved must have."),
+		mcp.WithNumber("note_id",
+			mcp.Required(),
+			mcp.Description("ID of the note to link from"),
+		),
+		mcp.WithArray("related_note_ids",
+			mcp.Required(),
+			mcp.Description("IDs of the notes to link to"),
+			mcp.WithNumberItems(),
+		),
+		mcp.WithBoolean("unlink",
+			mcp.Description("Optional: Remove the links instead of adding them"),
+		),
+		mcp.WithBoolean("bidirectional",
+			mcp.Description("Optional: Also update the related notes so the links point both ways (default: true)"),
+		),
+	)
+	a.addChangingTool(s, linkNotesTool, (*AnkiMCPServer).handleLinkNotes)
+
+	// Tool: Get Related Notes
+	getRelatedNotesTool := mcp.NewTool("get_related_notes",
+		mcp.WithDescription("List the notes linked from a note's \"Related\" field, with their note type, tags and a short summary of their content."),
+		mcp.WithNumber("note_id",
+			mcp.Required(),
+			mcp.Description("ID of the note"),
+		),
+		withFormat(),
+	)
+	a.addTool(s, getRelatedNotesTool, (*AnkiMCPServer).handleGetRelatedNotes)
+}
+
+// handleLinkNotes adds or removes links between notes
+func (a *AnkiMCPServer) handleLinkNotes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	id, ok := args["note_id"].(float64)
+	if !ok {
+		return a.errorf("note_id is required"), nil
+	}
+	noteID := int64(id)
+
+	var relatedIDs []int64
+	for _, v := range numberSliceValue(args, "related_note_ids") {
+		if related := int64(v); related != noteID && !slices.Contains(relatedIDs, related) {
+			relatedIDs = append(relatedIDs, related)
+		}
+	}
+	if len(relatedIDs) == 0 {
+		return a.errorf("related_note_ids must contain at least one other note"), nil
+	}
+	unlink, _ := args["unlink"].(bool)
+	bidirectional := true
+	if b, ok := args["bidirectional"].(bool); ok {
+		bidirectional = b
+	}
+
+	ids := append([]int64{noteID}, relatedIDs...)
+	infos, err := a.ankiClient.GetNotesInfo(ids)
+	if err != nil {
+		return a.errorf("Failed to get note info: %v", err), nil
+	}
+	notes := make(map[int64]NoteInfo, len(infos))
+	for i, info := range infos {
+		if info.NoteID == 0 {
+			return a.errorf("Note not found: %d", ids[i]), nil
+		}
+		notes[ids[i]] = info
+	}
+
+	// Work out the new Related field of every note that changes
+	links := map[int64][]int64{noteID: relatedIDs}
+	if bidirectional {
+		for _, related := range relatedIDs {
+			links[related] = []int64{noteID}
+		}
+	}
+
+	var updates []NoteUpdate
+	for _, id := range ids {
+		targets, ok := links[id]
+		if !ok {
+			continue
+		}
+		value, ok := noteField(notes[id], relatedField)
+		if !ok {
+			return a.errorf("Note %d (note type %s) has no %s field. Add a field named %s to this note type in Anki first.",
+				id, notes[id].ModelName, relatedField, relatedField), nil
+		}
+
+		current := parseNoteLinks(value)
+		updated := slices.Clone(current)
+		for _, target := range targets {
+			if unlink {
+				updated = slices.DeleteFunc(updated, func(l int64) bool { return l == target })
+			} else if !slices.Contains(updated, target) {
+				updated = append(updated, target)
+			}
+		}
+		if !slices.Equal(current, updated) {
+			updates = append(updates, NoteUpdate{ID: id, Fields: map[string]string{relatedField: formatNoteLinks(updated)}})
+		}
+	}
+
+	changed := 0
+	for i, err := range a.ankiClient.UpdateNotes(ctx, updates) {
+		switch {
+		case errors.Is(err, errNotSent):
+		case err != nil:
+			return a.errorf("Failed to update note %d: %v", updates[i].ID, err), nil
+		default:
+			changed++
+		}
+	}
+	if changed < len(updates) {
+		return a.errorf("Cancelled after changing %d of %d note(s)", changed, len(updates)), nil
+	}
+
+	var text string
+	if unlink {
+		text = a.t("Unlinked note %d from %d note(s); %d note(s) changed", noteID, len(relatedIDs), len(updates))
+	} else {
+		text = a.t("Linked note %d with %d note(s); %d note(s) changed", noteID, len(relatedIDs), len(updates))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}, nil
+}
+
+// handleGetRelatedNotes resolves the links stored on a note
+func (a *AnkiMCPServer) handleGetRelatedNotes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	id, ok := args["note_id"].(float64)
+	if !ok {
+		return a.errorf("note_id is required"), nil
+	}
+	noteID := int64(id)
+
+	infos, err := a.ankiClient.GetNotesInfo([]int64{noteID})
+	if err != nil {
+		return a.errorf("Failed to get note info: %v", err), nil
+	}
+	if len(infos) == 0 || infos[0].NoteID == 0 {
+		return a.errorf("Note not found: %d", noteID), nil
+	}
+	value, _ := noteField(infos[0], relatedField)
+	linked := parseNoteLinks(value)
+
+	related := make([]relatedNote, len(linked))
+	if len(linked) > 0 {
+		linkedInfos, err := a.ankiClient.GetNotesInfo(linked)
+		if err != nil {
+			return a.errorf("Failed to get note info: %v", err), nil
+		}
+		for i, info := range linkedInfos {
+			related[i].NoteID = linked[i]
+			if info.NoteID != 0 {
+				related[i].Exists = true
+				related[i].Model = info.ModelName
+				related[i].Summary = noteSummary(info)
+				related[i].Tags = info.Tags
+			}
+		}
+	}
+
+	if wantsJSON(request) {
+		return a.jsonResult(map[string]interface{}{
+			"note_id": noteID,
+			"related": related,
+		}), nil
+	}
+
+	out := a.newOutput()
+	if len(related) == 0 {
+		out.Line(a.t("Note %d has no related notes", noteID))
+	} else {
+		out.Heading(a.t("Notes related to %d (%d)", noteID, len(related)))
+		for _, r := range related {
+			switch {
+			case !r.Exists:
+				out.Item(a.t("%d: note no longer exists", r.NoteID))
+			case len(r.Tags) > 0:
+				out.Item(a.t("%d [%s]: %s (tags: %s)", r.NoteID, r.Model, r.Summary, strings.Join(r.Tags, ", ")))
+			default:
+				out.Item(a.t("%d [%s]: %s", r.NoteID, r.Model, r.Summary))
+			}
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// parseNoteLinks extracts the note IDs linked in a field value
+func parseNoteLinks(value string) []int64 {
+	var ids []int64
+	for _, match := range noteLinkPattern.FindAllStringSubmatch(value, -1) {
+		if id, err := strconv.ParseInt(match[1], 10, 64); err == nil && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// formatNoteLinks renders note IDs as the value of the Related field
+func formatNoteLinks(ids []int64) string {
+	links := make([]string, len(ids))
+	for i, id := range ids {
+		links[i] = fmt.Sprintf("nid:%d", id)
+	}
+	return strings.Join(links, " ")
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseNoteLinks(t *testing.T) {
+	ids := parseNoteLinks("nid:12 <br>nid:345 nid:12")
+	if !slices.Equal(ids, []int64{12, 345}) {
+		t.Errorf("Unexpected links: %v", ids)
+	}
+	if got := formatNoteLinks(ids); got != "nid:12 nid:345" {
+		t.Errorf("Unexpected field value: %s", got)
+	}
+}
+
+func TestLinkNotes(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.models["Basic"].Fields = append(mock.models["Basic"].Fields, relatedField)
+
+	var ids []int64
+	for _, word := range []string{"<b>photosynthesis</b>", "chlorophyll", "glucose"} {
+		id, err := mock.addNote(Note{DeckName: "Default", ModelName: "Basic", Fields: map[string]string{"Front": word, "Back": "-"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+
+	args := map[string]interface{}{"note_id": float64(ids[0]), "related_note_ids": []interface{}{float64(ids[1]), float64(ids[2])}}
+	text, isErr := callTool(t, server.handleLinkNotes, args)
+	if isErr || !strings.Contains(text, "3 note(s) changed") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+
+	text, _ = callTool(t, server.handleGetRelatedNotes, map[string]interface{}{"note_id": float64(ids[1])})
+	if !strings.Contains(text, "[Basic]: photosynthesis") {
+		t.Errorf("Expected a backlink to the first note, got: %s", text)
+	}
+
+	args["related_note_ids"] = []interface{}{float64(ids[2])}
+	args["unlink"] = true
+	if text, isErr := callTool(t, server.handleLinkNotes, args); isErr || !strings.Contains(text, "2 note(s) changed") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	if got := mock.notes[ids[0]].Fields[relatedField]; got != formatNoteLinks(ids[1:2]) {
+		t.Errorf("Unexpected Related field after unlinking: %q", got)
+	}
+
+	clozeID, _ := mock.addNote(Note{DeckName: "Default", ModelName: "Cloze", Fields: map[string]string{"Text": "{{c1::ATP}}"}})
+	args = map[string]interface{}{"note_id": float64(ids[0]), "related_note_ids": []interface{}{float64(clozeID)}}
+	if text, isErr := callTool(t, server.handleLinkNotes, args); !isErr || !strings.Contains(text, "has no Related field") {
+		t.Errorf("Expected a missing field error, got: %s", text)
+	}
+}
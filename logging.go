@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// logSource names this server in the MCP log messages sent to clients
+const logSource = "anki-mcp"
+
+// parseLogLevel reads a log level: debug, info, warn or error
+func parseLogLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", s)
+	}
+}
+
+// openLogOutput returns where log records are written: the log file, which is
+// appended to, or stderr. Stdout is never used, since it carries the stdio
+// transport.
+func openLogOutput(path string) (io.Writer, func() error, error) {
+	if path == "" {
+		return os.Stderr, func() error { return nil }, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	return f, f.Close, nil
+}
+
+// toMCPLevel maps a log level to the level of an MCP log message
+func toMCPLevel(level slog.Level) mcp.LoggingLevel {
+	switch {
+	case level >= slog.LevelError:
+		return mcp.LoggingLevelError
+	case level >= slog.LevelWarn:
+		return mcp.LoggingLevelWarning
+	case level >= slog.LevelInfo:
+		return mcp.LoggingLevelInfo
+	default:
+		return mcp.LoggingLevelDebug
+	}
+}
+
+// logForwarder sends log records to the connected MCP clients as
+// notifications/message. Each client gets warnings and errors unless it asks
+// for another level with logging/setLevel.
+type logForwarder struct {
+	mu     sync.Mutex
+	server *server.MCPServer
+	// levels holds the minimum level of every connected client by session ID
+	levels map[string]mcp.LoggingLevel
+}
+
+// newLogForwarder creates a log forwarder that has no clients yet
+func newLogForwarder() *logForwarder {
+	return &logForwarder{levels: make(map[string]mcp.LoggingLevel)}
+}
+
+// attach makes the forwarder follow the clients of an MCP server created with
+// the given hooks
+func (f *logForwarder) attach(s *server.MCPServer, hooks *server.Hooks) {
+	f.mu.Lock()
+	f.server = s
+	f.mu.Unlock()
+
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.levels[session.SessionID()] = mcp.LoggingLevelWarning
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.levels, session.SessionID())
+	})
+	hooks.AddAfterSetLevel(func(ctx context.Context, id any, message *mcp.SetLevelRequest, result *mcp.EmptyResult) {
+		session := server.ClientSessionFromContext(ctx)
+		if session == nil {
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.levels[session.SessionID()] = message.Params.Level
+	})
+}
+
+// wants reports whether any client takes log messages at the level
+func (f *logForwarder) wants(level slog.Level) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, minLevel := range f.levels {
+		if toMCPLevel(level).ShouldSendTo(minLevel) {
+			return true
+		}
+	}
+	return false
+}
+
+// send sends a log message to every client that takes its level. Clients
+// that can't keep up miss the message rather than holding up the server.
+func (f *logForwarder) send(level slog.Level, data map[string]any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.server == nil {
+		return
+	}
+	mcpLevel := toMCPLevel(level)
+	for id, minLevel := range f.levels {
+		if mcpLevel.ShouldSendTo(minLevel) {
+			_ = f.server.SendNotificationToSpecificClient(id, "notifications/message", map[string]any{
+				"level":  mcpLevel,
+				"logger": logSource,
+				"data":   data,
+			})
+		}
+	}
+}
+
+// logHandler writes log records to the log output and forwards them to the
+// MCP clients
+type logHandler struct {
+	out     slog.Handler
+	forward *logForwarder
+	// attrs are the attributes added with WithAttrs, for the forwarded
+	// messages
+	attrs []slog.Attr
+}
+
+// newLogHandler creates a log handler writing records at or above level to w
+func newLogHandler(w io.Writer, level slog.Level, forward *logForwarder) *logHandler {
+	return &logHandler{
+		out:     slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}),
+		forward: forward,
+	}
+}
+
+// Enabled implements slog.Handler
+func (h *logHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.out.Enabled(ctx, level) || h.forward.wants(level)
+}
+
+// Handle implements slog.Handler
+func (h *logHandler) Handle(ctx context.Context, record slog.Record) error {
+	var err error
+	if h.out.Enabled(ctx, record.Level) {
+		err = h.out.Handle(ctx, record)
+	}
+
+	data := map[string]any{"message": record.Message}
+	addAttr := func(attr slog.Attr) bool {
+		data[attr.Key] = attr.Value.Resolve().Any()
+		return true
+	}
+	for _, attr := range h.attrs {
+		addAttr(attr)
+	}
+	record.Attrs(addAttr)
+	// Errors don't marshal to JSON by themselves
+	for key, value := range data {
+		if e, ok := value.(error); ok {
+			data[key] = e.Error()
+		}
+	}
+	h.forward.send(record.Level, data)
+	return err
+}
+
+// WithAttrs implements slog.Handler
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &logHandler{
+		out:     h.out.WithAttrs(attrs),
+		forward: h.forward,
+		attrs:   append(slices.Clip(h.attrs), attrs...),
+	}
+}
+
+// WithGroup implements slog.Handler. Groups only show in the log output; the
+// forwarded messages keep their attributes flat.
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	return &logHandler{
+		out:     h.out.WithGroup(name),
+		forward: h.forward,
+		attrs:   h.attrs,
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	for s, want := range map[string]slog.Level{"": slog.LevelInfo, "DEBUG": slog.LevelDebug, "warning": slog.LevelWarn, "error": slog.LevelError} {
+		if got, err := parseLogLevel(s); err != nil || got != want {
+			t.Errorf("parseLogLevel(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+	if _, err := parseLogLevel("verbose"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+
+	config := Config{Transport: transportStdio}
+	if err := applyFlags(&config, []string{"--log-level", "debug", "--log-file=/tmp/anki-mcp.log"}); err != nil {
+		t.Fatal(err)
+	}
+	if config.LogLevel != slog.LevelDebug || config.LogFile != "/tmp/anki-mcp.log" {
+		t.Errorf("Unexpected config %+v", config)
+	}
+	if err := applyFlags(&config, []string{"--log-level", "verbose"}); err == nil {
+		t.Error("Expected an error for an unknown --log-level")
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes by the logger
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestLogForwarding(t *testing.T) {
+	anki := httptest.NewServer(nil)
+	anki.Close()
+	var log syncBuffer
+	server := NewAnkiMCPServerWithConfig(Config{
+		AnkiConnectURL: anki.URL,
+		Retry:          RetryPolicy{Attempts: 2, Backoff: time.Millisecond},
+		Language:       defaultLanguage,
+		OutputStyle:    outputMarkdown,
+		StateDir:       t.TempDir(),
+		LogLevel:       slog.LevelError,
+		LogOutput:      &log,
+	})
+
+	// The in-process transport doesn't pass on notifications, so connect
+	// over stdio
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() { _ = mcpserver.NewStdioServer(server.newMCPServer()).Listen(ctx, serverIn, serverOut) }()
+	c := client.NewClient(transport.NewIO(clientIn, clientOut, io.NopCloser(strings.NewReader(""))))
+	t.Cleanup(func() { _ = c.Close() })
+	messages := make(chan map[string]any, 10)
+	c.OnNotification(func(notification mcp.JSONRPCNotification) {
+		if notification.Method == "notifications/message" {
+			messages <- notification.Params.AdditionalFields
+		}
+	})
+	if err := c.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	var init mcp.InitializeRequest
+	init.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	init.Params.ClientInfo = mcp.Implementation{Name: "logging-test", Version: "1"}
+	if _, err := c.Initialize(ctx, init); err != nil {
+		t.Fatal(err)
+	}
+	// next waits for the next log message, or returns nil if none comes
+	next := func() map[string]any {
+		select {
+		case message := <-messages:
+			return message
+		case <-time.After(200 * time.Millisecond):
+			return nil
+		}
+	}
+
+	if _, isErr := callMCPTool(t, c, "get_deck_tree", map[string]interface{}{}); !isErr {
+		t.Fatal("Expected get_deck_tree to fail without AnkiConnect")
+	}
+	for _, want := range []mcp.LoggingLevel{mcp.LoggingLevelWarning, mcp.LoggingLevelError} {
+		message := next()
+		if message == nil || message["level"] != string(want) || message["logger"] != logSource {
+			t.Fatalf("Expected a %s message, got %v", want, message)
+		}
+		data, _ := message["data"].(map[string]any)
+		if data["action"] != "deckNames" || data["url"] != anki.URL {
+			t.Errorf("Unexpected message data: %v", data)
+		}
+	}
+	if message := next(); message != nil {
+		t.Errorf("Unexpected message: %v", message)
+	}
+	if out := log.String(); strings.Contains(out, "retrying") || !strings.Contains(out, "AnkiConnect is not reachable") {
+		t.Errorf("Expected only the error in the log, got %s", out)
+	}
+
+	var setLevel mcp.SetLevelRequest
+	setLevel.Params.Level = mcp.LoggingLevelError
+	if err := c.SetLevel(context.Background(), setLevel); err != nil {
+		t.Fatal(err)
+	}
+	callMCPTool(t, c, "get_deck_tree", map[string]interface{}{})
+	if message := next(); message == nil || message["level"] != string(mcp.LoggingLevelError) {
+		t.Errorf("Expected only the error after logging/setLevel, got %v", message)
+	}
+	if message := next(); message != nil {
+		t.Errorf("Unexpected message: %v", message)
+	}
+}
@@ -2,10 +2,22 @@ package main
 
 import (
 	"context"
-	"encoding/base64"
+	"errors"
 	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -20,20 +32,111 @@ var (
 
 // AnkiMCPServer wraps the AnkiConnect client and provides MCP tools
 type AnkiMCPServer struct {
-	ankiClient *AnkiConnect
+	ankiClient  *AnkiConnect
+	loc         *localizer
+	plainOutput bool
+	stateDir    string
+	configFile  string
+	routing     RoutingConfig
+	tts         TTSConfig
+	recipes     []RecipeConfig
+	vocab       VocabConfig
+	// rolloverHour is the hour at which the Anki day starts
+	rolloverHour int
+	// dryRun makes every changing tool report its changes instead of
+	// making them
+	dryRun bool
+	// instanceName is the name of the AnkiConnect endpoint this server
+	// talks to
+	instanceName string
+	// instances are the servers of every configured AnkiConnect endpoint,
+	// starting with the default one; only set on the default server
+	instances []*AnkiMCPServer
+	// logger writes the log and forwards it to the MCP clients through logs
+	logger *slog.Logger
+	logs   *logForwarder
+	// calls cancels tool calls the client gave up on
+	calls *callTracker
+
+	// limitsMu serializes changes to temporary deck limits; copies of the
+	// server share it
+	limitsMu *sync.Mutex
 }
 
-// NewAnkiMCPServer creates a new Anki MCP server
+// NewAnkiMCPServer creates a new Anki MCP server configured from the environment
 func NewAnkiMCPServer() *AnkiMCPServer {
-	// Get AnkiConnect URL from environment variable or use default
-	url := os.Getenv("ANKI_CONNECT_URL")
-	if url == "" {
-		url = defaultAnkiConnectURL
+	return NewAnkiMCPServerWithConfig(loadConfig())
+}
+
+// NewAnkiMCPServerWithConfig creates a new Anki MCP server with the given configuration
+func NewAnkiMCPServerWithConfig(config Config) *AnkiMCPServer {
+	ankiClient := newAnkiClient(config, config.AnkiConnectURL, config.AnkiConnectAPIKey)
+	stateDir := config.StateDir
+	if config.Mock {
+		mock := newMockAnkiConnect()
+		mock.seedDemo()
+		client, err := mock.Client()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "anki-mcp: %v\n", err)
+			os.Exit(1)
+		}
+		ankiClient = client
+		ankiClient.history = &changeHistory{}
+		ankiClient.sanitizer = newHTMLSanitizer(config.Sanitize)
+		// Keep state about the demo collection away from the real one
+		stateDir = filepath.Join(os.TempDir(), fmt.Sprintf("anki-mcp-mock-%d", os.Getpid()))
+	}
+
+	logOutput := config.LogOutput
+	if logOutput == nil {
+		logOutput = io.Discard
+	}
+	logs := newLogForwarder()
+	logger := slog.New(newLogHandler(logOutput, config.LogLevel, logs))
+	ankiClient.Logger = logger
+	// Only an Anki on this machine can be started; further instances are
+	// usually headless ones that run on their own
+	if config.Launch.Enabled && !config.Mock {
+		if isLocalURL(config.AnkiConnectURL) {
+			ankiClient.launcher = newAnkiLauncher(config.Launch)
+		} else {
+			logger.Warn("Not starting Anki automatically, AnkiConnect is on another machine", "url", config.AnkiConnectURL)
+		}
 	}
 
-	return &AnkiMCPServer{
-		ankiClient: NewAnkiConnectWithURL(url),
+	a := &AnkiMCPServer{
+		ankiClient:   ankiClient,
+		loc:          newLocalizer(config.Language),
+		plainOutput:  config.OutputStyle == outputPlain,
+		stateDir:     stateDir,
+		configFile:   config.ConfigFile,
+		routing:      config.Routing,
+		tts:          config.TTS,
+		recipes:      config.Recipes,
+		vocab:        config.Vocab,
+		rolloverHour: config.RolloverHour,
+		dryRun:       config.DryRun,
+		instanceName: defaultInstance,
+		logger:       logger,
+		logs:         logs,
+		calls:        newCallTracker(),
+		limitsMu:     &sync.Mutex{},
+	}
+	a.instances = []*AnkiMCPServer{a}
+	// The demo collection stands in for every endpoint, so there is only one
+	if !config.Mock {
+		for _, instance := range config.Instances {
+			client := newAnkiClient(config, instance.URL, instance.APIKey)
+			client.Logger = logger.With("instance", instance.Name)
+			a.instances = append(a.instances, a.newInstance(instance.Name, client))
+		}
 	}
+	return a
+}
+
+// t translates a message format into the configured output language and formats it
+func (a *AnkiMCPServer) t(format string, args ...interface{}) string {
+	return a.loc.Sprintf(format, args...)
 }
 
 func main() {
@@ -43,63 +146,160 @@ func main() {
 		return
 	}
 
+	// Flags override the environment, e.g. --mock uses the in-memory demo
+	// collection instead of AnkiConnect
+	config := loadConfig()
+	if err := applyFlags(&config, os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "anki-mcp: %v\n", err)
+		os.Exit(2)
+	}
+	if err := checkHTTPExposure(config); err != nil {
+		fmt.Fprintf(os.Stderr, "anki-mcp: %v\n", err)
+		os.Exit(2)
+	}
+	logOutput, closeLog, err := openLogOutput(config.LogFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "anki-mcp: %v\n", err)
+		os.Exit(2)
+	}
+	defer func() { _ = closeLog() }()
+	config.LogOutput = logOutput
+
 	// Create the Anki MCP server
-	ankiServer := NewAnkiMCPServer()
+	ankiServer := NewAnkiMCPServerWithConfig(config)
+
+	// Create a new MCP server with the Anki tools
+	s := ankiServer.newMCPServer()
+
+	// Undo deck limit changes left over from previous days, and those of
+	// today once the Anki day ends
+	for _, instance := range ankiServer.instances {
+		go instance.restoreLimitsAtRollover()
+	}
+
+	// Serve over stdio, or over HTTP so remote and multiple clients can connect
+	logger := ankiServer.logger
+	switch config.Transport {
+	case transportHTTP:
+		logger.Info("serving Streamable HTTP", "url", fmt.Sprintf("http://%s/mcp", config.HTTPAddr), "token", config.HTTPToken != "")
+		mux := http.NewServeMux()
+		mux.Handle("/mcp", server.NewStreamableHTTPServer(s))
+		err = serveHTTP(config, mux)
+	case transportSSE:
+		logger.Info("serving SSE", "url", fmt.Sprintf("http://%s/sse", config.HTTPAddr), "token", config.HTTPToken != "")
+		err = serveHTTP(config, server.NewSSEServer(s))
+	default:
+		logger.Debug("serving stdio")
+		err = server.ServeStdio(s)
+	}
+	if err != nil {
+		logger.Error("server error", "error", err)
+		_ = closeLog()
+		os.Exit(1)
+	}
+}
 
-	// Create a new MCP server
+// newMCPServer creates an MCP server offering the Anki tools, resources and
+// prompts
+func (a *AnkiMCPServer) newMCPServer() *server.MCPServer {
+	hooks := &server.Hooks{}
 	s := server.NewMCPServer(
 		"Simple Anki MCP Server",
 		version,
 		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, false),
+		server.WithPromptCapabilities(false),
+		server.WithLogging(),
+		server.WithHooks(hooks),
 	)
-
-	// Add simplified tools
-	ankiServer.registerTools(s)
-
-	// Start the stdio server
-	if err := server.ServeStdio(s); err != nil {
-		fmt.Printf("Server error: %v\n", err)
-		os.Exit(1)
-	}
+	a.logs.attach(s, hooks)
+	a.calls.attach(s, hooks)
+	a.registerTools(s)
+	a.registerResources(s)
+	a.registerPrompts(s)
+	return s
 }
 
 // registerTools registers all Anki tools with the MCP server
 func (a *AnkiMCPServer) registerTools(s *server.MCPServer) {
 	// Tool: Create Card
 	createCardTool := mcp.NewTool("create_card",
-		mcp.WithDescription("Create a Basic Anki card. Images appear above text, audio references below text. Supports separate audio for front and back."),
+		mcp.WithDescription("Create an Anki card: a Basic card from front and back, optionally with a reverse card, or a note of any note type from model_name and fields. "+
+			"Images appear above text, audio references below text. Supports separate audio for front and back."),
 		mcp.WithString("deck",
 			mcp.Required(),
 			mcp.Description("Name of the deck"),
 		),
 		mcp.WithString("front",
-			mcp.Required(),
-			mcp.Description("Front text content"),
+			mcp.Description("Front text content (required unless fields is given)"),
 		),
 		mcp.WithString("back",
-			mcp.Required(),
-			mcp.Description("Back text content"),
+			mcp.Description("Back text content (required unless fields is given)"),
+		),
+		mcp.WithString("model_name",
+			mcp.Description("Optional: Note type to use (default: Basic)"),
+		),
+		mcp.WithObject("fields",
+			mcp.Description("Optional: Field values by field name, e.g. {\"Word\": \"perro\", \"Meaning\": \"dog\"}. Checked against the note type's fields; replaces front and back"),
 		),
 		mcp.WithString("image_path",
 			mcp.Description("Optional: Path to an image file to include"),
 		),
+		mcp.WithString("image_url",
+			mcp.Description("Optional: URL of an image to download and include, instead of image_path"),
+		),
+		withImageOptions(),
 		mcp.WithString("front_audio_path",
 			mcp.Description("Optional: Path to an audio file for the front of the card"),
 		),
+		mcp.WithString("front_audio_url",
+			mcp.Description("Optional: URL of an audio file for the front of the card, instead of front_audio_path"),
+		),
 		mcp.WithString("back_audio_path",
 			mcp.Description("Optional: Path to an audio file for the back of the card"),
 		),
+		mcp.WithString("back_audio_url",
+			mcp.Description("Optional: URL of an audio file for the back of the card, instead of back_audio_path"),
+		),
 		mcp.WithArray("tags",
 			mcp.Description("Optional: Tags for the card"),
 		),
+		mcp.WithBoolean("reversed",
+			mcp.Description("Optional: Also create a reverse card (back → front) using the \"Basic (and reversed card)\" note type, which is created if missing"),
+		),
+		withDuplicateOptions(),
 	)
-	s.AddTool(createCardTool, a.handleCreateCard)
+	a.addChangingTool(s, createCardTool, (*AnkiMCPServer).handleCreateCard)
 
-	// Tool: List Decks
-	listDecksTool := mcp.NewTool("list_decks",
-		mcp.WithDescription("List all available Anki decks"),
+	// Tool: Create Cards Bulk
+	createCardsBulkTool := mcp.NewTool("create_cards_bulk",
+		mcp.WithDescription("Create many Basic cards in one call instead of calling create_card for each. "+
+			"Cards that fail (e.g. duplicates) are reported individually and don't stop the others."),
+		mcp.WithArray("cards",
+			mcp.Required(),
+			mcp.Description("Cards to create, each with front, back and optionally deck and tags"),
+			mcp.Items(map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"front": map[string]interface{}{"type": "string", "description": "Front text content"},
+					"back":  map[string]interface{}{"type": "string", "description": "Back text content"},
+					"deck":  map[string]interface{}{"type": "string", "description": "Deck of this card, overriding the deck parameter"},
+					"tags":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Tags of this card, added to the tags parameter"},
+				},
+				"required": []string{"front", "back"},
+			}),
+		),
+		mcp.WithString("deck",
+			mcp.Description("Optional: Deck for cards that don't name their own"),
+		),
+		mcp.WithArray("tags",
+			mcp.Description("Optional: Tags added to every card"),
+			mcp.WithStringItems(),
+		),
+		withFormat(),
+		withDuplicateOptions(),
 	)
-	s.AddTool(listDecksTool, a.handleListDecks)
+	a.addChangingTool(s, createCardsBulkTool, (*AnkiMCPServer).handleCreateCardsBulk)
 
 	// Tool: Create Deck
 	createDeckTool := mcp.NewTool("create_deck",
@@ -109,7 +309,42 @@ func (a *AnkiMCPServer) registerTools(s *server.MCPServer) {
 			mcp.Description("Name of the deck to create"),
 		),
 	)
-	s.AddTool(createDeckTool, a.handleCreateDeck)
+	a.addChangingTool(s, createDeckTool, (*AnkiMCPServer).handleCreateDeck)
+
+	a.registerInstanceTools(s)
+	a.registerHealthTools(s)
+	a.registerCardTools(s)
+	a.registerSearchTools(s)
+	a.registerStatsTools(s)
+	a.registerReviewLogTools(s)
+	a.registerRetentionTools(s)
+	a.registerForecastTools(s)
+	a.registerHeatmapTools(s)
+	a.registerMaturityTools(s)
+	a.registerMaintenanceTools(s)
+	a.registerDeckConfigTools(s)
+	a.registerLimitTools(s)
+	a.registerRoutingTools(s)
+	a.registerLinkTools(s)
+	a.registerClozeTools(s)
+	a.registerOcclusionTools(s)
+	a.registerMathTools(s)
+	a.registerRecipeTools(s)
+	a.registerVocabTools(s)
+	a.registerModelTools(s)
+	a.registerSyncTools(s)
+	a.registerNoteTools(s)
+	a.registerDeckTools(s)
+	a.registerStudyTools(s)
+	a.registerGUITools(s)
+	a.registerUndoTools(s)
+	a.registerTagTools(s)
+	a.registerImportTools(s)
+	a.registerExportTools(s)
+	a.registerMediaTools(s)
+	a.registerMediaCheckTools(s)
+	a.registerTTSTools(s)
+	a.registerLeechTools(s)
 }
 
 // handleCreateCard creates a new Anki card with standardized formatting
@@ -118,17 +353,37 @@ func (a *AnkiMCPServer) handleCreateCard(ctx context.Context, request mcp.CallTo
 
 	deckName, ok := args["deck"].(string)
 	if !ok {
-		return errorResult("deck is required"), nil
+		return a.errorf("deck is required"), nil
 	}
 
-	frontText, ok := args["front"].(string)
-	if !ok {
-		return errorResult("front is required"), nil
+	fields := make(map[string]string)
+	for name, value := range objectValue(args, "fields") {
+		text, ok := value.(string)
+		if !ok {
+			return a.errorf("Field %s must be a string", name), nil
+		}
+		fields[name] = text
 	}
+	if len(fields) == 0 {
+		frontText, ok := args["front"].(string)
+		if !ok {
+			return a.errorf("front is required"), nil
+		}
 
-	backText, ok := args["back"].(string)
-	if !ok {
-		return errorResult("back is required"), nil
+		backText, ok := args["back"].(string)
+		if !ok {
+			return a.errorf("back is required"), nil
+		}
+		fields["Front"], fields["Back"] = frontText, backText
+	}
+
+	options, errResult := a.noteOptions(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+	imageOpts, errResult := a.imageOptions(args)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	var tags []string
@@ -140,118 +395,331 @@ func (a *AnkiMCPServer) handleCreateCard(ctx context.Context, request mcp.CallTo
 		}
 	}
 
-	// Process optional image
-	var imageName string
-	if imagePath, ok := args["image_path"].(string); ok && imagePath != "" {
-		imageData, err := fileToBase64(imagePath)
+	modelName := "Basic"
+	if name, ok := args["model_name"].(string); ok && strings.TrimSpace(name) != "" {
+		modelName = name
+	}
+	createdModel := false
+	if reversed, _ := args["reversed"].(bool); reversed {
+		if modelName != "Basic" && modelName != reversedModel {
+			return a.errorf("reversed can't be combined with the note type %s", modelName), nil
+		}
+		modelName = reversedModel
+		created, err := a.ensureReversedModel()
 		if err != nil {
-			return errorResult(fmt.Sprintf("Failed to read image file: %v", err)), nil
+			return a.errorf("Failed to create note type %s: %v", reversedModel, err), nil
 		}
+		createdModel = created
+	}
 
-		// Generate filename from path
-		parts := strings.Split(imagePath, "/")
-		if len(parts) == 0 {
-			parts = strings.Split(imagePath, "\\")
+	// Check the fields before storing any media
+	modelFields, err := a.ankiClient.GetModelFieldNames(modelName)
+	if err != nil {
+		return a.errorf("Failed to get fields: %v", err), nil
+	}
+	var unknown []string
+	for name := range fields {
+		if !slices.Contains(modelFields, name) {
+			unknown = append(unknown, name)
 		}
-		imageName = parts[len(parts)-1]
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return a.errorf("Unknown field(s) for note type %s: %s. Fields: %s", modelName, strings.Join(unknown, ", "), strings.Join(modelFields, ", ")), nil
+	}
+	// The image and front audio go into the first field, and so does the
+	// back audio when it is the only one
+	firstFieldMedia := []string{"image_path", "image_url", "front_audio_path", "front_audio_url"}
+	if len(modelFields) == 1 {
+		firstFieldMedia = append(firstFieldMedia, "back_audio_path", "back_audio_url")
+	}
+	hasMedia := slices.ContainsFunc(firstFieldMedia, func(name string) bool {
+		value, _ := args[name].(string)
+		return value != ""
+	})
+	if len(modelFields) == 0 || (strings.TrimSpace(fields[modelFields[0]]) == "" && !hasMedia) {
+		return a.errorf("The first field of note type %s must not be empty", modelName), nil
+	}
 
-		// Store the image file
-		decodedData, err := base64.StdEncoding.DecodeString(imageData)
-		if err != nil {
-			return errorResult(fmt.Sprintf("Failed to decode image data: %v", err)), nil
+	// Send the card to the deck chosen by the tag routing rules
+	var route *RoutingRule
+	if a.routing.Auto {
+		if rule, ok := a.routing.deckFor(tags); ok && rule.Deck != deckName {
+			if err := a.ankiClient.CreateDeck(rule.Deck); err != nil {
+				return a.errorf("Failed to create deck: %v", err), nil
+			}
+			deckName = rule.Deck
+			route = &rule
 		}
+	}
 
-		err = a.ankiClient.StoreMediaFile(imageName, decodedData)
-		if err != nil {
-			return errorResult(fmt.Sprintf("Failed to store image: %v", err)), nil
-		}
+	names, storedMedia, errResult := a.storeCardMedia(ctx, []cardMedia{
+		{stringValue(args, "image_path"), stringValue(args, "image_url"), "image/", imageOpts},
+		{stringValue(args, "front_audio_path"), stringValue(args, "front_audio_url"), "audio/", imageOptions{}},
+		{stringValue(args, "back_audio_path"), stringValue(args, "back_audio_url"), "audio/", imageOptions{}},
+	}, "")
+	if errResult != nil {
+		return errResult, nil
 	}
+	imageName, frontAudioName, backAudioName := names[0], names[1], names[2]
 
-	// Process optional front audio
-	var frontAudioName string
-	if audioPath, ok := args["front_audio_path"].(string); ok && audioPath != "" {
-		audioData, err := fileToBase64(audioPath)
-		if err != nil {
-			return errorResult(fmt.Sprintf("Failed to read front audio file: %v", err)), nil
-		}
+	// Build formatted content; media goes into the first two fields, which are
+	// the front and back of most note types
+	frontField, backField := modelFields[0], modelFields[0]
+	if len(modelFields) > 1 {
+		backField = modelFields[1]
+	}
+	if imageName != "" || frontAudioName != "" {
+		fields[frontField] = formatContent(fields[frontField], imageName, frontAudioName)
+	}
+	if backAudioName != "" {
+		fields[backField] = formatContent(fields[backField], "", backAudioName)
+	}
+	note := Note{
+		DeckName:  deckName,
+		ModelName: modelName,
+		Fields:    fields,
+		Tags:      tags,
+		Options:   options,
+	}
 
-		// Generate filename from path
-		parts := strings.Split(audioPath, "/")
-		if len(parts) == 0 {
-			parts = strings.Split(audioPath, "\\")
-		}
-		frontAudioName = parts[len(parts)-1]
+	noteID, err := a.ankiClient.AddNote(note)
+	if err != nil {
+		return a.errorf("Failed to create card: %v", err), nil
+	}
 
-		// Store the audio file
-		decodedData, err := base64.StdEncoding.DecodeString(audioData)
-		if err != nil {
-			return errorResult(fmt.Sprintf("Failed to decode front audio data: %v", err)), nil
-		}
+	out := a.newOutput()
+	out.Line(a.t("Created card (ID: %d)", noteID))
+	if modelName == reversedModel {
+		out.Line(a.t("Added a reverse card (back → front) with the note type %s", reversedModel))
+	}
+	if createdModel {
+		out.Line(a.t("Created the missing note type %s", reversedModel))
+	}
+	if route != nil {
+		out.Line(a.t("Routed to deck %s by the tag rule %s", route.Deck, route.Tag))
+	}
+	a.describeStoredMedia(out, storedMedia)
 
-		err = a.ankiClient.StoreMediaFile(frontAudioName, decodedData)
-		if err != nil {
-			return errorResult(fmt.Sprintf("Failed to store front audio: %v", err)), nil
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// Duplicate scopes for new notes
+const (
+	duplicateScopeCollection = "collection"
+	duplicateScopeDeck       = "deck"
+)
+
+// withDuplicateOptions adds the parameters controlling how tools that create
+// notes treat duplicates
+func withDuplicateOptions() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithBoolean("allow_duplicate",
+			mcp.Description("Optional: Create the note even if a note of the same type with the same first field exists (default: false)"),
+		)(t)
+		mcp.WithString("duplicate_scope",
+			mcp.Description("Optional: Where to look for duplicates: collection (default) or deck"),
+			mcp.Enum(duplicateScopeCollection, duplicateScopeDeck),
+		)(t)
+		mcp.WithString("duplicate_scope_deck",
+			mcp.Description("Optional: Deck to look for duplicates in when duplicate_scope is deck, including its subdecks (default: the note's deck)"),
+		)(t)
+	}
+}
+
+// noteOptions returns the AnkiConnect options of new notes from the
+// duplicate parameters, or an error result when they don't fit together
+func (a *AnkiMCPServer) noteOptions(args map[string]interface{}) (map[string]interface{}, *mcp.CallToolResult) {
+	allowDuplicate, _ := args["allow_duplicate"].(bool)
+	scope, _ := args["duplicate_scope"].(string)
+	scopeDeck, _ := args["duplicate_scope_deck"].(string)
+	if scope == "" && scopeDeck != "" {
+		scope = duplicateScopeDeck
+	}
+
+	options := map[string]interface{}{
+		"allowDuplicate": allowDuplicate,
+	}
+	switch scope {
+	case "", duplicateScopeCollection:
+		if scopeDeck != "" {
+			return nil, a.errorf("duplicate_scope_deck needs duplicate_scope deck")
 		}
+	case duplicateScopeDeck:
+		options["duplicateScope"] = duplicateScopeDeck
+		if scopeDeck != "" {
+			options["duplicateScopeOptions"] = map[string]interface{}{
+				"deckName":      scopeDeck,
+				"checkChildren": true,
+			}
+		}
+	default:
+		return nil, a.errorf("Invalid duplicate_scope %q: use collection or deck", scope)
 	}
+	return options, nil
+}
 
-	// Process optional back audio
-	var backAudioName string
-	if audioPath, ok := args["back_audio_path"].(string); ok && audioPath != "" {
-		audioData, err := fileToBase64(audioPath)
-		if err != nil {
-			return errorResult(fmt.Sprintf("Failed to read back audio file: %v", err)), nil
+// reversedModel is Anki's stock note type producing a card in each direction
+const reversedModel = "Basic (and reversed card)"
+
+// bulkCardResult is the JSON representation of one card of a create_cards_bulk call
+type bulkCardResult struct {
+	Index  int    `json:"index"`
+	NoteID int64  `json:"note_id,omitempty"`
+	Deck   string `json:"deck,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// handleCreateCardsBulk creates many Basic cards with a single batched request
+func (a *AnkiMCPServer) handleCreateCardsBulk(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	items, ok := args["cards"].([]interface{})
+	if !ok || len(items) == 0 {
+		return a.errorf("cards is required"), nil
+	}
+	defaultDeck, _ := args["deck"].(string)
+	commonTags := stringSliceValue(args, "tags")
+	options, errResult := a.noteOptions(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	results := make([]bulkCardResult, len(items))
+	var notes []Note
+	var noteIndex []int
+	routedDecks := make(map[string]bool)
+	for i, item := range items {
+		results[i].Index = i + 1
+		card, _ := item.(map[string]interface{})
+		front, _ := card["front"].(string)
+		back, _ := card["back"].(string)
+		if strings.TrimSpace(front) == "" || strings.TrimSpace(back) == "" {
+			results[i].Error = a.t("front and back are required")
+			continue
 		}
+		deck := stringValue(card, "deck")
+		if deck == "" {
+			deck = defaultDeck
+		}
+		tags := append(slices.Clone(commonTags), stringSliceValue(card, "tags")...)
+		if a.routing.Auto {
+			if rule, ok := a.routing.deckFor(tags); ok {
+				deck = rule.Deck
+				routedDecks[deck] = true
+			}
+		}
+		if deck == "" {
+			results[i].Error = a.t("deck is required")
+			continue
+		}
+
+		results[i].Deck = deck
+		notes = append(notes, Note{
+			DeckName:  deck,
+			ModelName: "Basic",
+			Fields: map[string]string{
+				"Front": front,
+				"Back":  back,
+			},
+			Tags:    tags,
+			Options: options,
+		})
+		noteIndex = append(noteIndex, i)
+	}
 
-		// Generate filename from path
-		parts := strings.Split(audioPath, "/")
-		if len(parts) == 0 {
-			parts = strings.Split(audioPath, "\\")
+	for deck := range routedDecks {
+		if err := a.ankiClient.CreateDeck(deck); err != nil {
+			return a.errorf("Failed to create deck: %v", err), nil
 		}
-		backAudioName = parts[len(parts)-1]
+	}
 
-		// Store the audio file
-		decodedData, err := base64.StdEncoding.DecodeString(audioData)
-		if err != nil {
-			return errorResult(fmt.Sprintf("Failed to decode back audio data: %v", err)), nil
+	notSent := 0
+	for i, result := range a.ankiClient.AddNotes(ctx, notes) {
+		switch {
+		case errors.Is(result.Err, errNotSent):
+			results[noteIndex[i]].Error = result.Err.Error()
+			notSent++
+		case result.Err != nil:
+			results[noteIndex[i]].Error = result.Err.Error()
+		default:
+			results[noteIndex[i]].NoteID = result.ID
 		}
+	}
 
-		err = a.ankiClient.StoreMediaFile(backAudioName, decodedData)
-		if err != nil {
-			return errorResult(fmt.Sprintf("Failed to store back audio: %v", err)), nil
+	created := 0
+	for _, r := range results {
+		if r.Error == "" {
+			created++
 		}
 	}
 
-	// Build formatted content
-	frontContent := formatContent(frontText, imageName, frontAudioName)
-	backContent := formatContent(backText, "", backAudioName)
-	note := Note{
-		DeckName:  deckName,
-		ModelName: "Basic",
-		Fields: map[string]string{
-			"Front": frontContent,
-			"Back":  backContent,
-		},
-		Tags: tags,
-		Options: map[string]interface{}{
-			"allowDuplicate": false,
-		},
+	if wantsJSON(request) {
+		return a.jsonResult(map[string]interface{}{
+			"created":  created,
+			"failed":   len(results) - created - notSent,
+			"not_sent": notSent,
+			"results":  results,
+		}), nil
 	}
 
-	noteID, err := a.ankiClient.AddNote(note)
-	if err != nil {
-		return errorResult(fmt.Sprintf("Failed to create card: %v", err)), nil
+	out := a.newOutput()
+	out.Heading(a.t("Created %d of %d card(s)", created, len(results)))
+	if notSent > 0 {
+		out.Item(a.t("Not sent, because the request was cancelled: %d", notSent))
+	}
+	for _, r := range results {
+		if r.Error == errNotSent.Error() {
+			continue
+		}
+		if r.Error != "" {
+			out.Item(a.t("#%d failed: %s", r.Index, r.Error))
+		} else {
+			out.Item(a.t("#%d: note %d in %s", r.Index, r.NoteID, r.Deck))
+		}
 	}
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			mcp.TextContent{
 				Type: "text",
-				Text: fmt.Sprintf("Created card (ID: %d)", noteID),
+				Text: out.String(),
 			},
 		},
+		IsError: created == 0,
 	}, nil
 }
 
+// ensureReversedModel creates the "Basic (and reversed card)" note type with
+// Anki's stock templates if the collection doesn't have it, and reports
+// whether it had to be created
+func (a *AnkiMCPServer) ensureReversedModel() (bool, error) {
+	models, err := a.ankiClient.GetModelNames()
+	if err != nil {
+		return false, err
+	}
+	if slices.Contains(models, reversedModel) {
+		return false, nil
+	}
+
+	templates := []CardTemplate{
+		{Name: "Card 1", Front: "{{Front}}", Back: "{{FrontSide}}\n\n<hr id=answer>\n\n{{Back}}"},
+		{Name: "Card 2", Front: "{{Back}}", Back: "{{FrontSide}}\n\n<hr id=answer>\n\n{{Front}}"},
+	}
+	css := ".card {\n    font-family: arial;\n    font-size: 20px;\n    text-align: center;\n    color: black;\n    background-color: white;\n}\n"
+	if err := a.ankiClient.CreateModel(reversedModel, []string{"Front", "Back"}, templates, css); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // formatContent formats the card content with media in standardized positions
 func formatContent(text, imageName, audioName string) string {
 	var content strings.Builder
@@ -272,66 +740,226 @@ func formatContent(text, imageName, audioName string) string {
 	return content.String()
 }
 
-// handleListDecks lists all available Anki decks
-func (a *AnkiMCPServer) handleListDecks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	decks, err := a.ankiClient.GetDeckNames()
-	if err != nil {
-		return errorResult(fmt.Sprintf("Failed to get decks: %v", err)), nil
-	}
-
-	deckList := strings.Join(decks, "\n")
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: fmt.Sprintf("Decks (%d):\n%s", len(decks), deckList),
-			},
-		},
-	}, nil
-}
-
 // handleCreateDeck creates a new Anki deck
 func (a *AnkiMCPServer) handleCreateDeck(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
 	deckName, ok := args["name"].(string)
 	if !ok {
-		return errorResult("name is required"), nil
+		return a.errorf("name is required"), nil
 	}
 
 	err := a.ankiClient.CreateDeck(deckName)
 	if err != nil {
-		return errorResult(fmt.Sprintf("Failed to create deck: %v", err)), nil
+		return a.errorf("Failed to create deck: %v", err), nil
 	}
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			mcp.TextContent{
 				Type: "text",
-				Text: fmt.Sprintf("Created deck: %s", deckName),
+				Text: a.t("Created deck: %s", deckName),
 			},
 		},
 	}, nil
 }
 
-// fileToBase64 reads a file and returns its contents as a base64 string
-func fileToBase64(filePath string) (string, error) {
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to read file %s: %w", filePath, err)
-	}
-	return base64.StdEncoding.EncodeToString(data), nil
-}
-
-// errorResult creates an error result
-func errorResult(message string) *mcp.CallToolResult {
-	return &mcp.CallToolResult{
+// errorf creates a localized error result. Known AnkiConnect failures among
+// the arguments add a hint on how to fix them.
+func (a *AnkiMCPServer) errorf(format string, args ...interface{}) *mcp.CallToolResult {
+	result := &mcp.CallToolResult{
 		Content: []mcp.Content{
 			mcp.TextContent{
 				Type: "text",
-				Text: fmt.Sprintf("Error: %s", message),
+				Text: a.t("Error: %s", a.t(format, args...)),
 			},
 		},
 		IsError: true,
 	}
+	a.diagnose(result, args)
+	return result
+}
+
+// dateLayout is the date format accepted and produced by tools
+const dateLayout = "2006-01-02"
+
+// parseDateRange reads optional start_date/end_date arguments and returns the
+// range as [start, end) in local time. When start_date is missing the range
+// covers defaultDays days ending with end_date (default: today).
+func parseDateRange(args map[string]interface{}, defaultDays int) (time.Time, time.Time, error) {
+	now := time.Now()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if endStr, ok := args["end_date"].(string); ok && endStr != "" {
+		parsed, err := time.ParseInLocation(dateLayout, endStr, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end_date %q, expected YYYY-MM-DD", endStr)
+		}
+		end = parsed
+	}
+	end = end.AddDate(0, 0, 1)
+
+	start := end.AddDate(0, 0, -defaultDays)
+	if startStr, ok := args["start_date"].(string); ok && startStr != "" {
+		parsed, err := time.ParseInLocation(dateLayout, startStr, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start_date %q, expected YYYY-MM-DD", startStr)
+		}
+		start = parsed
+	}
+
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("start_date must not be after end_date")
+	}
+	return start, end, nil
+}
+
+// deckQuery builds an Anki search query matching a deck and its subdecks
+func deckQuery(deckName string) string {
+	return fmt.Sprintf(`deck:"%s"`, strings.ReplaceAll(deckName, `"`, `\"`))
+}
+
+// selectCards returns the cards chosen by a tool's card_ids or query
+// argument, or an error result when neither or both are given
+func (a *AnkiMCPServer) selectCards(args map[string]interface{}) ([]int64, *mcp.CallToolResult) {
+	var cardIDs []int64
+	for _, id := range numberSliceValue(args, "card_ids") {
+		cardIDs = append(cardIDs, int64(id))
+	}
+	query, _ := args["query"].(string)
+	switch {
+	case len(cardIDs) > 0 && strings.TrimSpace(query) != "":
+		return nil, a.errorf("Pass either card_ids or query, not both")
+	case len(cardIDs) > 0:
+		return cardIDs, nil
+	case strings.TrimSpace(query) == "":
+		return nil, a.errorf("card_ids or query is required")
+	}
+
+	cardIDs, err := a.ankiClient.FindCards(query)
+	if err != nil {
+		return nil, a.errorf("Failed to find cards: %v", err)
+	}
+	return cardIDs, nil
+}
+
+// selectNotes returns the notes chosen by the note_ids or query argument
+func (a *AnkiMCPServer) selectNotes(args map[string]interface{}) ([]int64, *mcp.CallToolResult) {
+	var noteIDs []int64
+	for _, id := range numberSliceValue(args, "note_ids") {
+		noteIDs = append(noteIDs, int64(id))
+	}
+	query, _ := args["query"].(string)
+	switch {
+	case len(noteIDs) > 0 && strings.TrimSpace(query) != "":
+		return nil, a.errorf("Pass either note_ids or query, not both")
+	case len(noteIDs) > 0:
+		return noteIDs, nil
+	case strings.TrimSpace(query) == "":
+		return nil, a.errorf("note_ids or query is required")
+	}
+
+	noteIDs, err := a.ankiClient.FindNotes(query)
+	if err != nil {
+		return nil, a.errorf("Failed to find notes: %v", err)
+	}
+	return noteIDs, nil
+}
+
+// validTag reports whether a tag can be stored as a single Anki tag
+func validTag(tag string) bool {
+	return tag != "" && !strings.ContainsFunc(tag, unicode.IsSpace)
+}
+
+// tagQuery builds an Anki search query matching a tag and its child tags
+func tagQuery(tag string) string {
+	return fmt.Sprintf(`tag:"%s"`, strings.ReplaceAll(tag, `"`, `\"`))
+}
+
+var (
+	htmlTagPattern    = regexp.MustCompile(`(?s)<[^>]*>`)
+	whitespacePattern = regexp.MustCompile(`\s+`)
+)
+
+// plainText converts field HTML into a single line of text, cut to at most
+// maxLen characters
+func plainText(fieldHTML string, maxLen int) string {
+	text := htmlTagPattern.ReplaceAllString(fieldHTML, " ")
+	text = strings.TrimSpace(whitespacePattern.ReplaceAllString(html.UnescapeString(text), " "))
+	if runes := []rune(text); len(runes) > maxLen {
+		text = strings.TrimSpace(string(runes[:maxLen-1])) + "…"
+	}
+	return text
+}
+
+// noteField returns the value of a field from a notesInfo entry and whether
+// the note has that field
+func noteField(note NoteInfo, name string) (string, bool) {
+	field, ok := note.Fields[name]
+	return field.Value, ok
+}
+
+// noteSummary returns a short plain-text summary of a notesInfo entry, taken
+// from its first field
+func noteSummary(note NoteInfo) string {
+	first, firstOrder := "", math.MaxInt
+	for _, field := range note.Fields {
+		if field.Order < firstOrder {
+			first, firstOrder = field.Value, field.Order
+		}
+	}
+	return plainText(first, 80)
+}
+
+// numberValue extracts a numeric value from an AnkiConnect response object
+func numberValue(m map[string]interface{}, key string) float64 {
+	if v, ok := m[key].(float64); ok {
+		return v
+	}
+	return 0
+}
+
+// stringValue extracts a string value from an AnkiConnect response object
+func stringValue(m map[string]interface{}, key string) string {
+	if v, ok := m[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// stringSliceValue extracts a list of strings from an AnkiConnect response object
+func stringSliceValue(m map[string]interface{}, key string) []string {
+	items, ok := m[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// objectValue extracts a nested object from an AnkiConnect response object
+func objectValue(m map[string]interface{}, key string) map[string]interface{} {
+	if v, ok := m[key].(map[string]interface{}); ok {
+		return v
+	}
+	return map[string]interface{}{}
+}
+
+// numberSliceValue extracts a list of numbers from an AnkiConnect response object
+func numberSliceValue(m map[string]interface{}, key string) []float64 {
+	items, ok := m[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	values := make([]float64, 0, len(items))
+	for _, item := range items {
+		if f, ok := item.(float64); ok {
+			values = append(values, f)
+		}
+	}
+	return values
 }
@@ -1,7 +1,14 @@
 package main
 
 import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	mcpserver "github.com/mark3labs/mcp-go/server"
 )
 
 func TestNewAnkiMCPServer(t *testing.T) {
@@ -37,3 +44,117 @@ func TestNewAnkiConnectWithURL(t *testing.T) {
 		t.Errorf("Expected URL %s, got %s", customURL, client.URL)
 	}
 }
+
+func TestNewAnkiMCPServerLanguage(t *testing.T) {
+	t.Setenv("ANKI_MCP_LANG", "es_ES.UTF-8")
+	server := NewAnkiMCPServer()
+	if server.loc.lang != "es" {
+		t.Errorf("Expected language es, got %s", server.loc.lang)
+	}
+	if got := server.t("Created deck: %s", "Vocabulario"); got != "Mazo creado: Vocabulario" {
+		t.Errorf("Unexpected localized output: %s", got)
+	}
+}
+
+func TestApplyFlags(t *testing.T) {
+	config := Config{Transport: transportStdio, HTTPAddr: defaultHTTPAddr}
+	if err := applyFlags(&config, []string{"--mock", "--transport", "HTTP", "--addr=:9000"}); err != nil {
+		t.Fatal(err)
+	}
+	if !config.Mock || config.Transport != transportHTTP || config.HTTPAddr != ":9000" {
+		t.Errorf("Unexpected config %+v", config)
+	}
+
+	// Switches take an optional boolean, so the environment can be overridden
+	config = Config{Transport: transportStdio, Mock: true, DryRun: true}
+	if err := applyFlags(&config, []string{"--mock=false", "--dry-run=0"}); err != nil {
+		t.Fatal(err)
+	}
+	if config.Mock || config.DryRun {
+		t.Errorf("Expected --mock=false and --dry-run=0 to turn the modes off, got %+v", config)
+	}
+
+	for _, args := range [][]string{{"--transport", "websocket"}, {"--addr"}, {"--verbose"}, {"--mock=no"}} {
+		config := Config{Transport: transportStdio}
+		if err := applyFlags(&config, args); err == nil {
+			t.Errorf("Expected %v to fail", args)
+		}
+	}
+}
+
+func TestStreamableHTTPTransport(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+	web := httptest.NewServer(mcpserver.NewStreamableHTTPServer(server.newMCPServer()))
+	defer web.Close()
+
+	post := func(session, body string) (*http.Response, string) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, web.URL+"/mcp", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if session != "" {
+			req.Header.Set("Mcp-Session-Id", session)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp, string(data)
+	}
+
+	resp, body := post("", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`)
+	session := resp.Header.Get("Mcp-Session-Id")
+	if resp.StatusCode != http.StatusOK || session == "" || !strings.Contains(body, "Simple Anki MCP Server") {
+		t.Fatalf("initialize failed: %d %s", resp.StatusCode, body)
+	}
+	_, body = post(session, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"get_deck_tree","arguments":{}}}`)
+	if !strings.Contains(body, "  - Vocabulary: 12 card(s)") {
+		t.Errorf("Unexpected tools/call response: %s", body)
+	}
+}
+
+func TestTimeouts(t *testing.T) {
+	t.Setenv("ANKI_CONNECT_TIMEOUT", "1m")
+	t.Setenv("ANKI_CONNECT_ACTION_TIMEOUTS", "exportPackage=30m, findCards=2m")
+	client := NewAnkiMCPServer().ankiClient
+
+	tests := map[string]time.Duration{
+		"deckNames":     time.Minute,
+		"findCards":     2 * time.Minute,
+		"exportPackage": 30 * time.Minute,
+		"sync":          defaultActionTimeouts["sync"],
+	}
+	for action, want := range tests {
+		if got := client.timeout(action); got != want {
+			t.Errorf("timeout(%q) = %v, want %v", action, got, want)
+		}
+	}
+
+	t.Setenv("ANKI_CONNECT_ACTION_TIMEOUTS", "sync")
+	if _, err := actionTimeoutsFromEnv(); err == nil {
+		t.Error("Expected an error for an entry without a duration")
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"result": 6, "error": null}`))
+	}))
+	defer slow.Close()
+
+	client := NewAnkiConnectWithURL(slow.URL)
+	client.Retry.Attempts = 1
+	client.Timeout = 50 * time.Millisecond
+	if err := client.Ping(); err == nil {
+		t.Error("Expected the request to time out")
+	}
+
+	client.ActionTimeouts["version"] = time.Second
+	if err := client.Ping(); err != nil {
+		t.Errorf("Ping() with a longer action timeout = %v", err)
+	}
+}
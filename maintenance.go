@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerMaintenanceTools registers collection maintenance tools with the MCP server
+func (a *AnkiMCPServer) registerMaintenanceTools(s *server.MCPServer) {
+	// Tool: Check Database
+	checkDatabaseTool := mcp.NewTool("check_database",
+		mcp.WithDescription("Run Anki's \"Check Database\" routine to detect and repair collection problems. "+
+			"The check runs in the background: the tool returns once it has started, and Anki shows its report in its window when done. "+
+			"AnkiConnect doesn't return that report, so this tool can't tell whether the collection needed repair; ask the user to read it in Anki. "+
+			"WARNING: it can take SEVERAL MINUTES on large collections, blocks the Anki window while it runs, "+
+			"and may modify the collection while repairing it. Only run it when the user has EXPLICITLY asked for "+
+			"a database check, and pass confirm=true to acknowledge this."),
+		mcp.WithBoolean("confirm",
+			mcp.Required(),
+			mcp.Description("Must be true to run the check. Do not set this unless the user explicitly requested a database check."),
+		),
+	)
+	a.addChangingTool(s, checkDatabaseTool, (*AnkiMCPServer).handleCheckDatabase)
+
+	// Tool: Clear Unused Tags
+	clearUnusedTagsTool := mcp.NewTool("clear_unused_tags",
+		mcp.WithDescription("Remove tags that no note uses anymore from the collection's tag list, e.g. after removing or replacing tags in bulk, and report which tags were removed"),
+	)
+	a.addChangingTool(s, clearUnusedTagsTool, (*AnkiMCPServer).handleClearUnusedTags)
+}
+
+// handleCheckDatabase runs Anki's database check after an explicit confirmation
+func (a *AnkiMCPServer) handleCheckDatabase(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	confirm, _ := args["confirm"].(bool)
+	if !confirm {
+		return a.errorf("check_database was not run. It can take several minutes on large collections and blocks Anki while running. " +
+			"Ask the user to explicitly confirm, then call again with confirm=true."), nil
+	}
+
+	if err := a.ankiClient.CheckDatabase(); err != nil {
+		return a.errorf("Failed to check database: %v", err), nil
+	}
+
+	out := a.newOutput()
+	out.Line(a.t("Started Anki's database check. It runs in the background and may still be running."))
+	out.Line(a.t("When it is done, Anki shows in its window whether the collection needed repair and what it fixed."))
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleClearUnusedTags clears unused tags and reports the ones removed
+func (a *AnkiMCPServer) handleClearUnusedTags(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// AnkiConnect doesn't report what it cleared, so compare the tag list
+	// before and after
+	before, err := a.ankiClient.GetTags()
+	if err != nil {
+		return a.errorf("Failed to get tags: %v", err), nil
+	}
+	if err := a.ankiClient.ClearUnusedTags(); err != nil {
+		return a.errorf("Failed to clear unused tags: %v", err), nil
+	}
+	after, err := a.ankiClient.GetTags()
+	if err != nil {
+		return a.errorf("Failed to get tags: %v", err), nil
+	}
+
+	var removed []string
+	for _, tag := range before {
+		if !slices.Contains(after, tag) {
+			removed = append(removed, tag)
+		}
+	}
+
+	out := a.newOutput()
+	if len(removed) == 0 {
+		out.Line(a.t("No unused tags found"))
+	} else {
+		out.Heading(a.t("Removed %d unused tag(s)", len(removed)))
+		for _, tag := range removed {
+			out.Item(tag)
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckDatabase(t *testing.T) {
+	server, _ := newMockServer(t)
+
+	text, isErr := callTool(t, server.handleCheckDatabase, map[string]interface{}{})
+	if !isErr || !strings.Contains(text, "confirm=true") {
+		t.Fatalf("Expected check_database to require confirmation, got %s", text)
+	}
+	text, isErr = callTool(t, server.handleCheckDatabase, map[string]interface{}{"confirm": true})
+	if isErr || !strings.Contains(text, "Started Anki's database check") || strings.Contains(text, "completed") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+
+	// guiCheckDatabase returns once the check has started, so it needs no
+	// longer timeout
+	if got := NewAnkiConnect().timeout("guiCheckDatabase"); got != defaultTimeout {
+		t.Errorf("timeout(guiCheckDatabase) = %s, want %s", got, defaultTimeout)
+	}
+}
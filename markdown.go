@@ -0,0 +1,435 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Ways a Markdown document can define cards
+const (
+	markdownAuto     = "auto"
+	markdownQA       = "qa"
+	markdownHeadings = "headings"
+	markdownBullets  = "bullets"
+)
+
+// markdownCard is a card found in a Markdown document, with both sides still
+// in Markdown
+type markdownCard struct {
+	Front string
+	Back  string
+}
+
+var (
+	mdHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdBulletPattern  = regexp.MustCompile(`^(\s*)(?:[-*+]|(\d+)[.)])\s+(.*)$`)
+	mdQuestionPrefix = regexp.MustCompile(`(?i)^Q:\s*`)
+	mdAnswerPrefix   = regexp.MustCompile(`(?i)^A:\s*`)
+	mdImagePattern   = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	mdLinkPattern    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdBoldPattern    = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	mdItalicPattern  = regexp.MustCompile(`\*([^*]+?)\*|\b_([^_]+?)_\b`)
+
+	htmlElementPattern   = regexp.MustCompile(`(?s)<(/?)([a-zA-Z][a-zA-Z0-9]*)([^>]*)>|<!--.*?-->`)
+	htmlAttributePattern = regexp.MustCompile(`(?i)\b([a-z-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	blankLinesPattern    = regexp.MustCompile(`\n{3,}`)
+)
+
+// parseMarkdownCards finds the cards in a Markdown document:
+//   - qa: "Q:" starts the front and "A:" the back; both may span lines
+//   - headings: each heading is a front and the text below it the back;
+//     headings without text are section titles and are skipped
+//   - bullets: each top-level list item is a front and its indented
+//     sub-items or lines the back
+//
+// auto picks qa when the document has a "Q:" line, headings when it has
+// headings and bullets otherwise. In qa style cards without an answer are
+// returned with an empty back so they can be reported.
+func parseMarkdownCards(md, style string) ([]markdownCard, error) {
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+
+	if style == "" || style == markdownAuto {
+		style = markdownBullets
+		for _, line := range lines {
+			trimmed := strings.TrimSpace(line)
+			if mdQuestionPrefix.MatchString(trimmed) {
+				style = markdownQA
+				break
+			}
+			if mdHeadingPattern.MatchString(trimmed) {
+				style = markdownHeadings
+			}
+		}
+	}
+
+	var cards []markdownCard
+	var current *markdownCard
+	var back []string
+	inAnswer, inFence := false, false
+	flush := func(keepEmpty bool) {
+		if current != nil {
+			current.Back = dedent(back)
+			if current.Back != "" || keepEmpty {
+				cards = append(cards, *current)
+			}
+		}
+		current, back, inAnswer = nil, nil, false
+	}
+
+	switch style {
+	case markdownQA:
+		for _, line := range lines {
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "```") {
+				inFence = !inFence
+			}
+			switch {
+			case !inFence && mdQuestionPrefix.MatchString(trimmed):
+				flush(true)
+				current = &markdownCard{Front: mdQuestionPrefix.ReplaceAllString(trimmed, "")}
+			case current == nil:
+			case !inFence && !inAnswer && mdAnswerPrefix.MatchString(trimmed):
+				inAnswer = true
+				back = append(back, mdAnswerPrefix.ReplaceAllString(trimmed, ""))
+			case inAnswer:
+				back = append(back, line)
+			default:
+				current.Front += "\n" + line
+			}
+		}
+		flush(true)
+
+	case markdownHeadings:
+		for _, line := range lines {
+			if strings.HasPrefix(strings.TrimSpace(line), "```") {
+				inFence = !inFence
+			}
+			if m := mdHeadingPattern.FindStringSubmatch(line); m != nil && !inFence {
+				flush(false)
+				current = &markdownCard{Front: m[2]}
+			} else if current != nil {
+				back = append(back, line)
+			}
+		}
+		flush(false)
+
+	case markdownBullets:
+		for _, line := range lines {
+			if strings.TrimSpace(line) == "" {
+				if current != nil {
+					back = append(back, line)
+				}
+				continue
+			}
+			if m := mdBulletPattern.FindStringSubmatch(line); m != nil && m[1] == "" {
+				flush(false)
+				current = &markdownCard{Front: m[3]}
+			} else if line[0] == ' ' || line[0] == '\t' {
+				if current != nil {
+					back = append(back, line)
+				}
+			} else {
+				flush(false)
+			}
+		}
+		flush(false)
+
+	default:
+		return nil, fmt.Errorf("unknown style %q", style)
+	}
+
+	for i := range cards {
+		cards[i].Front = strings.TrimSpace(cards[i].Front)
+	}
+	return cards, nil
+}
+
+// dedent joins lines after removing the indentation they have in common and
+// surrounding blank lines
+func dedent(lines []string) string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			line = line[indent:]
+		}
+		out[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.Trim(strings.Join(out, "\n"), "\n")
+}
+
+// markdownToHTML converts the Markdown of a card side to HTML for an Anki
+// field. It handles paragraphs and line breaks, nested lists, headings,
+// fenced code blocks and inline emphasis, code, links and images.
+func markdownToHTML(md string) string {
+	var b strings.Builder
+	type list struct {
+		indent  int
+		ordered bool
+	}
+	var lists []list
+	closeLists := func(indent int) {
+		for len(lists) > 0 && lists[len(lists)-1].indent > indent {
+			if lists[len(lists)-1].ordered {
+				b.WriteString("</ol>")
+			} else {
+				b.WriteString("</ul>")
+			}
+			lists = lists[:len(lists)-1]
+		}
+	}
+
+	inFence, pendingBreak, lastText := false, false, false
+	for _, line := range strings.Split(md, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			if inFence {
+				b.WriteString("</code></pre>")
+			} else {
+				closeLists(-1)
+				b.WriteString("<pre><code>")
+			}
+			inFence, lastText = !inFence, false
+			continue
+		}
+		if inFence {
+			b.WriteString(html.EscapeString(line) + "\n")
+			continue
+		}
+		if trimmed == "" {
+			pendingBreak = lastText
+			continue
+		}
+
+		if m := mdBulletPattern.FindStringSubmatch(line); m != nil {
+			indent := len(m[1])
+			closeLists(indent)
+			if len(lists) == 0 || lists[len(lists)-1].indent < indent {
+				ordered := m[2] != ""
+				lists = append(lists, list{indent: indent, ordered: ordered})
+				if ordered {
+					b.WriteString("<ol>")
+				} else {
+					b.WriteString("<ul>")
+				}
+			}
+			b.WriteString("<li>" + markdownInline(m[3]) + "</li>")
+			pendingBreak, lastText = false, false
+			continue
+		}
+		closeLists(-1)
+
+		if lastText {
+			b.WriteString("<br>")
+			if pendingBreak {
+				b.WriteString("<br>")
+			}
+		}
+		if m := mdHeadingPattern.FindStringSubmatch(trimmed); m != nil {
+			b.WriteString("<b>" + markdownInline(m[2]) + "</b>")
+		} else {
+			b.WriteString(markdownInline(trimmed))
+		}
+		pendingBreak, lastText = false, true
+	}
+	closeLists(-1)
+	if inFence {
+		b.WriteString("</code></pre>")
+	}
+	return b.String()
+}
+
+// markdownInline converts inline Markdown: code spans, images, links, bold and
+// italic text
+func markdownInline(text string) string {
+	parts := strings.Split(text, "`")
+	for i, part := range parts {
+		part = html.EscapeString(part)
+		// Odd parts are inside a code span, unless the last backtick is unmatched
+		if i%2 == 1 && i < len(parts)-1 {
+			parts[i] = "<code>" + part + "</code>"
+			continue
+		}
+		part = mdImagePattern.ReplaceAllString(part, `<img src="$2" alt="$1">`)
+		part = mdLinkPattern.ReplaceAllString(part, `<a href="$2">$1</a>`)
+		part = mdBoldPattern.ReplaceAllString(part, "<b>$1$2</b>")
+		part = mdItalicPattern.ReplaceAllString(part, "<i>$1$2</i>")
+		if i%2 == 1 {
+			part = "`" + part
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, "")
+}
+
+// htmlAttributes returns the attributes of an HTML start tag by lowercase name
+func htmlAttributes(tag string) map[string]string {
+	attrs := make(map[string]string)
+	for _, m := range htmlAttributePattern.FindAllStringSubmatch(tag, -1) {
+		attrs[strings.ToLower(m[1])] = html.UnescapeString(m[2] + m[3] + m[4])
+	}
+	return attrs
+}
+
+// htmlToMarkdown converts the HTML of an Anki field to Markdown, the reverse
+// of markdownToHTML: line breaks and divs become lines, paragraphs and
+// headings blocks, and lists, code, emphasis, links and images their Markdown
+// syntax. Other tags are dropped and their text is kept.
+func htmlToMarkdown(fieldHTML string) string {
+	var b strings.Builder
+	type list struct {
+		ordered bool
+		items   int
+	}
+	var lists []list
+	var links []string
+	inPre := false
+
+	atLineStart := func() bool {
+		return b.Len() == 0 || strings.HasSuffix(b.String(), "\n")
+	}
+	newline := func() {
+		if !atLineStart() {
+			b.WriteString("\n")
+		}
+	}
+	paragraph := func() {
+		newline()
+		if b.Len() > 0 && !strings.HasSuffix(b.String(), "\n\n") {
+			b.WriteString("\n")
+		}
+	}
+	trimSpace := func() {
+		trimmed := strings.TrimRight(b.String(), " ")
+		b.Reset()
+		b.WriteString(trimmed)
+	}
+	writeText := func(text string) {
+		text = html.UnescapeString(text)
+		if !inPre {
+			text = whitespacePattern.ReplaceAllString(text, " ")
+			if atLineStart() || strings.HasSuffix(b.String(), " ") {
+				text = strings.TrimLeft(text, " ")
+			}
+		}
+		b.WriteString(text)
+	}
+
+	last := 0
+	for _, m := range htmlElementPattern.FindAllStringSubmatchIndex(fieldHTML, -1) {
+		writeText(fieldHTML[last:m[0]])
+		last = m[1]
+		if m[4] < 0 {
+			// Comment
+			continue
+		}
+		closing := m[3] > m[2]
+		name := strings.ToLower(fieldHTML[m[4]:m[5]])
+		attrs := fieldHTML[m[6]:m[7]]
+
+		switch name {
+		case "br":
+			b.WriteString("\n")
+		case "div":
+			newline()
+		case "li":
+			newline()
+			if !closing && len(lists) > 0 {
+				l := &lists[len(lists)-1]
+				l.items++
+				b.WriteString(strings.Repeat("  ", len(lists)-1))
+				if l.ordered {
+					b.WriteString(fmt.Sprintf("%d. ", l.items))
+				} else {
+					b.WriteString("- ")
+				}
+			}
+		case "p", "blockquote", "table":
+			paragraph()
+		case "h1", "h2", "h3", "h4", "h5", "h6":
+			// Headings inside a field become bold lines, as markdownToHTML
+			// turns headings into bold text
+			if closing {
+				trimSpace()
+				b.WriteString("**")
+				paragraph()
+			} else {
+				paragraph()
+				b.WriteString("**")
+			}
+		case "ul", "ol":
+			newline()
+			if closing {
+				if len(lists) > 0 {
+					lists = lists[:len(lists)-1]
+				}
+				if len(lists) == 0 {
+					paragraph()
+				}
+			} else {
+				lists = append(lists, list{ordered: name == "ol"})
+			}
+		case "pre":
+			if closing {
+				newline()
+				b.WriteString("```")
+				paragraph()
+			} else {
+				paragraph()
+				b.WriteString("```\n")
+			}
+			inPre = !closing
+		case "code":
+			if !inPre {
+				b.WriteString("`")
+			}
+		case "b", "strong":
+			b.WriteString("**")
+		case "i", "em":
+			b.WriteString("*")
+		case "a":
+			if closing {
+				if len(links) > 0 {
+					href := links[len(links)-1]
+					links = links[:len(links)-1]
+					if href != "" {
+						b.WriteString("](" + href + ")")
+					}
+				}
+				continue
+			}
+			href := htmlAttributes(attrs)["href"]
+			links = append(links, href)
+			if href != "" {
+				b.WriteString("[")
+			}
+		case "img":
+			img := htmlAttributes(attrs)
+			if img["src"] != "" {
+				b.WriteString("![" + img["alt"] + "](" + strings.ReplaceAll(img["src"], " ", "%20") + ")")
+			}
+		case "hr":
+			paragraph()
+			b.WriteString("---")
+			paragraph()
+		}
+	}
+	writeText(fieldHTML[last:])
+
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Trim(blankLinesPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"), "\n")
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMarkdownCards(t *testing.T) {
+	tests := []struct {
+		name  string
+		md    string
+		style string
+		want  []markdownCard
+	}{
+		{
+			name: "qa",
+			md:   "Intro text\n\nQ: What is **mitosis**?\nA: Cell division\ninto two cells\n\nq: Unanswered?\n",
+			want: []markdownCard{
+				{Front: "What is **mitosis**?", Back: "Cell division\ninto two cells"},
+				{Front: "Unanswered?", Back: ""},
+			},
+		},
+		{
+			name: "headings",
+			md:   "# Biology\n\n## Mitosis\n\nCell division.\n\n## Meiosis\n- Two divisions\n- Four cells\n",
+			want: []markdownCard{
+				{Front: "Mitosis", Back: "Cell division."},
+				{Front: "Meiosis", Back: "- Two divisions\n- Four cells"},
+			},
+		},
+		{
+			name: "bullets",
+			md:   "Vocabulary\n\n- el perro\n  - the dog\n- no answer\n- la casa\n    the house\n",
+			want: []markdownCard{
+				{Front: "el perro", Back: "- the dog"},
+				{Front: "la casa", Back: "the house"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cards, err := parseMarkdownCards(tt.md, tt.style)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(cards) != len(tt.want) {
+				t.Fatalf("Expected %d cards, got %+v", len(tt.want), cards)
+			}
+			for i, want := range tt.want {
+				if cards[i] != want {
+					t.Errorf("Card %d: expected %+v, got %+v", i, want, cards[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMarkdownToHTML(t *testing.T) {
+	tests := map[string]string{
+		"**bold** and *italic* and `a < b`":          "<b>bold</b> and <i>italic</i> and <code>a &lt; b</code>",
+		"line one\nline two\n\nnew paragraph":        "line one<br>line two<br><br>new paragraph",
+		"- one\n  - nested\n- two":                   "<ul><li>one</li><ul><li>nested</li></ul><li>two</li></ul>",
+		"1. first\n2. second":                        "<ol><li>first</li><li>second</li></ol>",
+		"[docs](https://example.com) ![x](cell.png)": `<a href="https://example.com">docs</a> <img src="cell.png" alt="x">`,
+		"```\nif a < b {\n```":                       "<pre><code>if a &lt; b {\n</code></pre>",
+		"snake_case_name stays":                      "snake_case_name stays",
+	}
+	for md, want := range tests {
+		if got := markdownToHTML(md); got != want {
+			t.Errorf("markdownToHTML(%q) = %q, want %q", md, got, want)
+		}
+	}
+}
+
+func TestHTMLToMarkdown(t *testing.T) {
+	tests := map[string]string{
+		"<b>bold</b> and <i>italic</i> and <code>a &lt; b</code>":                "**bold** and *italic* and `a < b`",
+		"line one<br>line two<div>line three</div>":                              "line one\nline two\nline three",
+		"<p>first</p><p>second</p>":                                              "first\n\nsecond",
+		"<ul><li>one</li><ul><li>nested</li></ul><li>two</li></ul>":              "- one\n  - nested\n- two",
+		"<ol><li> first</li><li>second</li></ol>after":                           "1. first\n2. second\n\nafter",
+		`<a href="https://example.com">docs</a> <img src="my cell.png" alt="x">`: "[docs](https://example.com) ![x](my%20cell.png)",
+		"<pre><code>if a &lt; b {\n}</code></pre>":                               "```\nif a < b {\n}\n```",
+		"<h2>Title </h2>text":                                                    "**Title**\n\ntext",
+		`<span style="color: red">red</span><!-- note -->`:                       "red",
+	}
+	for fieldHTML, want := range tests {
+		if got := htmlToMarkdown(fieldHTML); got != want {
+			t.Errorf("htmlToMarkdown(%q) = %q, want %q", fieldHTML, got, want)
+		}
+	}
+
+	// What markdownToHTML produces converts back to the same Markdown
+	for _, md := range []string{"**bold** and *italic*", "- one\n  - nested\n- two", "[docs](https://example.com)"} {
+		if got := htmlToMarkdown(markdownToHTML(md)); got != md {
+			t.Errorf("Round trip of %q gave %q", md, got)
+		}
+	}
+}
+
+func TestImportMarkdown(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	md := "Q: el perro\nA: the dog\n\nQ: la **mesa**\nA: the table\n\nQ: el árbol\n"
+	text, isErr := callTool(t, server.handleImportMarkdown, map[string]interface{}{"data": md, "deck": "Spanish::Vocabulary", "reversed": true})
+	if isErr || !strings.Contains(text, "Created 1 of 3 note(s)") || !strings.Contains(text, "#1 is a duplicate") || !strings.Contains(text, "#3 failed: Question without answer: el árbol") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	notes, _ := server.ankiClient.FindNotes("deck:Spanish::Vocabulary Back:\"the table\"")
+	infos, _ := server.ankiClient.GetNotesInfo(notes)
+	if front, _ := noteField(infos[0], "Front"); front != "la <b>mesa</b>" {
+		t.Errorf("Expected the front converted to HTML, got %q", front)
+	}
+}
@@ -0,0 +1,383 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Math syntaxes of Anki fields
+const (
+	// mathJax is rendered by Anki 2.1 and later, which is every version
+	// AnkiConnect's API version 6 runs on, as \(...\) and \[...\]
+	mathJax = "mathjax"
+	// mathLaTeX is rendered to images by a LaTeX installation on the
+	// computer, as [$]...[/$] and [$$]...[/$$]
+	mathLaTeX = "latex"
+)
+
+// mathDelimiters lists the formula delimiters recognized in incoming text,
+// longest first so that $$ wins over $
+var mathDelimiters = []struct {
+	open, close string
+	display     bool
+}{
+	{"[$$]", "[/$$]", true},
+	{"[$]", "[/$]", false},
+	{"$$", "$$", true},
+	{`\[`, `\]`, true},
+	{`\(`, `\)`, false},
+	{"$", "$", false},
+}
+
+// convertMath rewrites the formulas of a text, marked with $...$, $$...$$,
+// \(...\), \[...\], [$]...[/$] or [$$]...[/$$], in the delimiters of the given
+// syntax, and escapes < and > in them so the field HTML stays valid. \$ stands
+// for a dollar sign, and a $ that can't open or close a formula, like in
+// amounts, is left alone. [latex] blocks are copied unchanged. It
+// returns the text, the formulas and warnings about brackets that don't pair
+// up, which can be intended, as in the interval [0, 1).
+func convertMath(text, syntax string) (string, []string, []string, error) {
+	if syntax == "" {
+		syntax = mathJax
+	}
+	if syntax != mathJax && syntax != mathLaTeX {
+		return "", nil, nil, fmt.Errorf("unknown syntax %q: use mathjax or latex", syntax)
+	}
+
+	var b strings.Builder
+	var formulas, warnings []string
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		if strings.HasPrefix(rest, `\$`) {
+			b.WriteString("$")
+			i += 2
+			continue
+		}
+		if strings.HasPrefix(rest, "[latex]") {
+			end := strings.Index(rest, "[/latex]")
+			if end < 0 {
+				return "", nil, nil, fmt.Errorf("[latex] is never closed")
+			}
+			end += len("[/latex]")
+			b.WriteString(rest[:end])
+			i += end
+			continue
+		}
+
+		matched := false
+		for _, d := range mathDelimiters {
+			if !strings.HasPrefix(rest, d.open) {
+				continue
+			}
+			body := rest[len(d.open):]
+			end := mathClose(body, d.close)
+			if d.open == "$" {
+				// A lone $ only opens a formula when it is directly followed by
+				// one, so amounts like $5 and $10 stay text
+				if r, _ := utf8.DecodeRuneInString(body); unicode.IsSpace(r) || end < 0 {
+					break
+				}
+			}
+			if end < 0 {
+				return "", nil, nil, fmt.Errorf("formula starting with %s is never closed with %s", d.open, d.close)
+			}
+			formula := strings.TrimSpace(body[:end])
+			if formula == "" {
+				return "", nil, nil, fmt.Errorf("empty formula %s%s", d.open, d.close)
+			}
+			if err := checkMathBalance(formula); err != nil {
+				return "", nil, nil, fmt.Errorf("%s: %v", formula, err)
+			}
+			warnings = append(warnings, mathBracketWarnings(formula)...)
+			formulas = append(formulas, formula)
+			b.WriteString(wrapMath(formula, syntax, d.display))
+			i += len(d.open) + end + len(d.close)
+			matched = true
+			break
+		}
+		if !matched {
+			_, size := utf8.DecodeRuneInString(rest)
+			b.WriteString(rest[:size])
+			i += size
+		}
+	}
+	return b.String(), formulas, warnings, nil
+}
+
+// mathClose returns the position of the delimiter closing a formula in body,
+// skipping escaped dollar signs, or -1. A formula opened by $ ends at the next
+// $, which must follow a non-space and not be followed by a digit.
+func mathClose(body, close string) int {
+	for i := 0; i < len(body); i++ {
+		if strings.HasPrefix(body[i:], `\$`) {
+			i++
+			continue
+		}
+		if !strings.HasPrefix(body[i:], close) {
+			continue
+		}
+		if close == "$" {
+			before, _ := utf8.DecodeLastRuneInString(body[:i])
+			after, _ := utf8.DecodeRuneInString(body[i+1:])
+			if unicode.IsSpace(before) || unicode.IsDigit(after) {
+				return -1
+			}
+		}
+		return i
+	}
+	return -1
+}
+
+// wrapMath puts a formula between the delimiters of a syntax
+func wrapMath(formula, syntax string, display bool) string {
+	formula = strings.NewReplacer("<", "&lt;", ">", "&gt;").Replace(formula)
+	switch {
+	case syntax == mathLaTeX && display:
+		return "[$$]" + formula + "[/$$]"
+	case syntax == mathLaTeX:
+		return "[$]" + formula + "[/$]"
+	case display:
+		return `\[` + formula + `\]`
+	default:
+		return `\(` + formula + `\)`
+	}
+}
+
+var (
+	mathEnvPattern   = regexp.MustCompile(`\\(begin|end)\s*\{([^{}]*)\}`)
+	mathLeftRight    = regexp.MustCompile(`\\(left|right)\b`)
+	mathEscapedBrace = regexp.MustCompile(`\\[{}]`)
+)
+
+// checkMathBalance reports braces, \left/\right pairs and environments that
+// don't pair up, which break the formula
+func checkMathBalance(formula string) error {
+	depth := 0
+	for _, r := range mathEscapedBrace.ReplaceAllString(formula, "") {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("} without a matching {")
+			}
+		}
+	}
+	if depth > 0 {
+		return fmt.Errorf("%d { without a matching }", depth)
+	}
+
+	lefts, rights := 0, 0
+	for _, m := range mathLeftRight.FindAllStringSubmatch(formula, -1) {
+		if m[1] == "left" {
+			lefts++
+		} else {
+			rights++
+		}
+	}
+	if lefts != rights {
+		return fmt.Errorf("%d \\left but %d \\right", lefts, rights)
+	}
+
+	var envs []string
+	for _, m := range mathEnvPattern.FindAllStringSubmatch(formula, -1) {
+		if m[1] == "begin" {
+			envs = append(envs, m[2])
+			continue
+		}
+		if len(envs) == 0 || envs[len(envs)-1] != m[2] {
+			return fmt.Errorf("\\end{%s} without a matching \\begin{%s}", m[2], m[2])
+		}
+		envs = envs[:len(envs)-1]
+	}
+	if len(envs) > 0 {
+		return fmt.Errorf("\\begin{%s} is never ended", envs[len(envs)-1])
+	}
+	return nil
+}
+
+// mathBracketWarnings reports round and square brackets that don't pair up
+func mathBracketWarnings(formula string) []string {
+	var warnings []string
+	for _, pair := range []string{"()", "[]"} {
+		opened := strings.Count(formula, pair[:1])
+		closed := strings.Count(formula, pair[1:])
+		if opened != closed {
+			warnings = append(warnings, fmt.Sprintf("%s: %d %s but %d %s", formula, opened, pair[:1], closed, pair[1:]))
+		}
+	}
+	return warnings
+}
+
+// registerMathTools registers tools for cards with formulas
+func (a *AnkiMCPServer) registerMathTools(s *server.MCPServer) {
+	// Tool: Create Math Card
+	createMathCardTool := mcp.NewTool("create_math_card",
+		mcp.WithDescription("Create a card with formulas. Write formulas as $...$ (inline) or $$...$$ (display); they are converted to the delimiters Anki renders, "+
+			"\\(...\\) and \\[...\\] for MathJax or [$]...[/$] and [$$]...[/$$] for LaTeX, and checked for unbalanced braces, \\left/\\right and environments. "+
+			"Write \\$ for a dollar sign. Use preview to check the converted fields without creating the card."),
+		mcp.WithString("deck",
+			mcp.Required(),
+			mcp.Description("Name of the deck"),
+		),
+		mcp.WithString("front",
+			mcp.Required(),
+			mcp.Description("Front text with formulas"),
+		),
+		mcp.WithString("back",
+			mcp.Required(),
+			mcp.Description("Back text with formulas"),
+		),
+		mcp.WithString("syntax",
+			mcp.Description("Optional: mathjax (default), rendered by every Anki 2.1, or latex, rendered to images by a LaTeX installation"),
+			mcp.Enum(mathJax, mathLaTeX),
+		),
+		mcp.WithString("model_name",
+			mcp.Description("Optional: Note type to use; front and back go into its first two fields (default: Basic)"),
+		),
+		mcp.WithArray("tags",
+			mcp.Description("Optional: Tags for the card"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithBoolean("preview",
+			mcp.Description("Optional: Only show the converted fields, without creating the card (default: false)"),
+		),
+		withDuplicateOptions(),
+	)
+	a.addChangingTool(s, createMathCardTool, (*AnkiMCPServer).handleCreateMathCard)
+}
+
+// handleCreateMathCard converts the formulas of a card to Anki's delimiters
+// and creates it
+func (a *AnkiMCPServer) handleCreateMathCard(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	deckName, ok := args["deck"].(string)
+	if !ok || strings.TrimSpace(deckName) == "" {
+		return a.errorf("deck is required"), nil
+	}
+	front, _ := args["front"].(string)
+	back, _ := args["back"].(string)
+	if strings.TrimSpace(front) == "" || strings.TrimSpace(back) == "" {
+		return a.errorf("front and back are required"), nil
+	}
+	tags := stringSliceValue(args, "tags")
+	for _, tag := range tags {
+		if !validTag(tag) {
+			return a.errorf("Invalid tag %q: tags can't be empty or contain spaces", tag), nil
+		}
+	}
+	options, errResult := a.noteOptions(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+	syntax, _ := args["syntax"].(string)
+
+	convertedFront, frontFormulas, frontWarnings, err := convertMath(front, syntax)
+	if err != nil {
+		return a.errorf("Invalid formula on the front: %v", err), nil
+	}
+	convertedBack, backFormulas, backWarnings, err := convertMath(back, syntax)
+	if err != nil {
+		return a.errorf("Invalid formula on the back: %v", err), nil
+	}
+	formulas := len(frontFormulas) + len(backFormulas)
+	warnings := append(frontWarnings, backWarnings...)
+
+	out := a.newOutput()
+	if preview, _ := args["preview"].(bool); preview {
+		out.Heading(a.t("Preview: %d formula(s), not created", formulas))
+		out.Item(a.t("Front: %s", convertedFront))
+		out.Item(a.t("Back: %s", convertedBack))
+		a.writeMathWarnings(out, warnings)
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: out.String(),
+				},
+			},
+		}, nil
+	}
+
+	modelName := "Basic"
+	if name, ok := args["model_name"].(string); ok && strings.TrimSpace(name) != "" {
+		modelName = name
+	}
+	if errResult := a.checkModel(modelName); errResult != nil {
+		return errResult, nil
+	}
+	modelFields, err := a.ankiClient.GetModelFieldNames(modelName)
+	if err != nil {
+		return a.errorf("Failed to get fields: %v", err), nil
+	}
+	if len(modelFields) < 2 {
+		return a.errorf("Note type %s needs at least two fields", modelName), nil
+	}
+
+	// Send the card to the deck chosen by the tag routing rules
+	var route *RoutingRule
+	if a.routing.Auto {
+		if rule, ok := a.routing.deckFor(tags); ok && rule.Deck != deckName {
+			if err := a.ankiClient.CreateDeck(rule.Deck); err != nil {
+				return a.errorf("Failed to create deck: %v", err), nil
+			}
+			deckName = rule.Deck
+			route = &rule
+		}
+	}
+
+	noteID, err := a.ankiClient.AddNote(Note{
+		DeckName:  deckName,
+		ModelName: modelName,
+		Fields: map[string]string{
+			modelFields[0]: convertedFront,
+			modelFields[1]: convertedBack,
+		},
+		Tags:    tags,
+		Options: options,
+	})
+	if err != nil {
+		return a.errorf("Failed to create card: %v", err), nil
+	}
+
+	out.Line(a.t("Created card (ID: %d) with %d formula(s)", noteID, formulas))
+	if route != nil {
+		out.Line(a.t("Routed to deck %s by the tag rule %s", route.Deck, route.Tag))
+	}
+	if syntax == mathLaTeX {
+		out.Line(a.t("LaTeX formulas are rendered by Anki's LaTeX support, which needs LaTeX installed on the computer."))
+	}
+	out.Item(a.t("Front: %s", convertedFront))
+	out.Item(a.t("Back: %s", convertedBack))
+	a.writeMathWarnings(out, warnings)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// writeMathWarnings lists the bracket warnings of the formulas, if any
+func (a *AnkiMCPServer) writeMathWarnings(out *textOutput, warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	out.Heading(a.t("Check these brackets; they are fine in intervals like [0, 1)"))
+	for _, w := range slices.Compact(warnings) {
+		out.Item(w)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConvertMath(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		syntax string
+		want   string
+	}{
+		{"inline", "Euler: $e^{i\\pi} + 1 = 0$", "", `Euler: \(e^{i\pi} + 1 = 0\)`},
+		{"display", "$$\\int_0^1 x\\,dx$$", mathJax, `\[\int_0^1 x\,dx\]`},
+		{"latex", "$a<b$ and \\[c\\]", mathLaTeX, "[$]a&lt;b[/$] and [$$]c[/$$]"},
+		{"already mathjax", `\(x^2\)`, "", `\(x^2\)`},
+		{"anki latex to mathjax", "[$$]x[/$$] [$]y[/$]", "", `\[x\] \(y\)`},
+		{"amounts", "It costs $5 or $10, not $x$", "", `It costs $5 or $10, not \(x\)`},
+		{"escaped dollar", `\$3 and $\$x$`, "", `$3 and \(\$x\)`},
+		{"latex block", "[latex]$x$[/latex]", "", "[latex]$x$[/latex]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, _, err := convertMath(tt.text, tt.syntax)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("convertMath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, text := range []string{"$\\frac{1}{2$", "$\\left( x$", "$\\begin{matrix} a \\end{pmatrix}$", "$$x", "$x}$"} {
+		if _, _, _, err := convertMath(text, ""); err == nil {
+			t.Errorf("Expected an error for %q", text)
+		}
+	}
+
+	_, formulas, warnings, err := convertMath("$x \\in [0, 1)$ and $\\{a\\}$", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(formulas) != 2 || len(warnings) != 2 {
+		t.Errorf("Expected 2 formulas and 2 bracket warnings, got %v and %v", formulas, warnings)
+	}
+}
+
+func TestCreateMathCard(t *testing.T) {
+	server, mock := newMockServer(t)
+
+	args := map[string]interface{}{"deck": "Default", "front": "Derivative of $x^2$?", "back": "$$2x$$", "preview": true}
+	text, isErr := callTool(t, server.handleCreateMathCard, args)
+	if isErr || !strings.Contains(text, "2 formula(s), not created") || !strings.Contains(text, `Derivative of \(x^2\)?`) {
+		t.Errorf("Unexpected preview: %s", text)
+	}
+	if len(mock.notes) != 0 {
+		t.Fatal("Expected preview not to create a note")
+	}
+
+	delete(args, "preview")
+	text, isErr = callTool(t, server.handleCreateMathCard, args)
+	if isErr || !strings.Contains(text, "with 2 formula(s)") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	for _, note := range mock.notes {
+		if note.Fields["Front"] != `Derivative of \(x^2\)?` || note.Fields["Back"] != `\[2x\]` {
+			t.Errorf("Unexpected fields: %v", note.Fields)
+		}
+	}
+
+	args["front"] = "$\\sqrt{x$"
+	if text, isErr := callTool(t, server.handleCreateMathCard, args); !isErr || !strings.Contains(text, "Invalid formula on the front") {
+		t.Errorf("Expected an error for an unbalanced formula, got %s", text)
+	}
+}
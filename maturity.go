@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerMaturityTools registers the maturity report tool
+func (a *AnkiMCPServer) registerMaturityTools(s *server.MCPServer) {
+	// Tool: Maturity Report
+	maturityReportTool := mcp.NewTool("maturity_report",
+		mcp.WithDescription(fmt.Sprintf("Break down the cards of a deck by maturity: new, learning (including relearning), young (interval under %d days), "+
+			"mature (interval of %d days or more) and suspended, with the share of each. Use it to track how far a deck has progressed.", matureInterval, matureInterval)),
+		mcp.WithString("deck",
+			mcp.Required(),
+			mcp.Description("Deck to analyze (subdecks included)"),
+		),
+		withFormat(),
+	)
+	a.addTool(s, maturityReportTool, (*AnkiMCPServer).handleMaturityReport)
+}
+
+// maturityBucket counts the cards of one maturity
+type maturityBucket struct {
+	Cards   int     `json:"cards"`
+	Percent float64 `json:"percent"`
+}
+
+// maturityReport holds the maturity breakdown of a set of cards
+type maturityReport struct {
+	Total     int            `json:"total"`
+	New       maturityBucket `json:"new"`
+	Learning  maturityBucket `json:"learning"`
+	Young     maturityBucket `json:"young"`
+	Mature    maturityBucket `json:"mature"`
+	Suspended maturityBucket `json:"suspended"`
+}
+
+// computeMaturity classifies cards by their scheduling state. Suspended cards
+// form their own bucket whatever their state; buried cards count by their
+// state, since they return the next day.
+func computeMaturity(cards []CardInfo) maturityReport {
+	var report maturityReport
+	for _, card := range cards {
+		if card.CardID == 0 {
+			continue
+		}
+		switch {
+		case card.Queue == -1:
+			report.Suspended.Cards++
+		case card.Type == 0:
+			report.New.Cards++
+		case card.Type == 1 || card.Type == 3:
+			report.Learning.Cards++
+		case card.Interval >= matureInterval:
+			report.Mature.Cards++
+		default:
+			report.Young.Cards++
+		}
+		report.Total++
+	}
+
+	if report.Total > 0 {
+		for _, b := range []*maturityBucket{&report.New, &report.Learning, &report.Young, &report.Mature, &report.Suspended} {
+			b.Percent = float64(b.Cards) * 100 / float64(report.Total)
+		}
+	}
+	return report
+}
+
+// handleMaturityReport reports how the cards of a deck are spread over the
+// maturity buckets
+func (a *AnkiMCPServer) handleMaturityReport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	deckName, ok := args["deck"].(string)
+	if !ok || strings.TrimSpace(deckName) == "" {
+		return a.errorf("deck is required"), nil
+	}
+
+	query := deckQuery(deckName)
+	cardIDs, err := a.ankiClient.FindCards(query)
+	if err != nil {
+		return a.errorf("Failed to find cards: %v", err), nil
+	}
+	if len(cardIDs) == 0 {
+		return a.errorf("No cards found for query: %s", query), nil
+	}
+	cards, err := a.ankiClient.GetCardsInfo(cardIDs)
+	if err != nil {
+		return a.errorf("Failed to get card info: %v", err), nil
+	}
+	report := computeMaturity(cards)
+
+	if wantsJSON(request) {
+		return a.jsonResult(map[string]interface{}{
+			"deck":            deckName,
+			"mature_interval": matureInterval,
+			"maturity":        report,
+		}), nil
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Maturity of %s: %d card(s)", deckName, report.Total))
+	buckets := []struct {
+		label  string
+		bucket maturityBucket
+	}{
+		{a.t("New"), report.New},
+		{a.t("Learning"), report.Learning},
+		{a.t("Young"), report.Young},
+		{a.t("Mature"), report.Mature},
+		{a.t("Suspended"), report.Suspended},
+	}
+	for _, b := range buckets {
+		out.Item(fmt.Sprintf("%s: %d (%.1f%%)", b.label, b.bucket.Cards, b.bucket.Percent))
+	}
+	out.Line("")
+	out.Line(a.t("Young cards have an interval under %d days, mature cards one of %d days or more.", matureInterval, matureInterval))
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestComputeMaturity(t *testing.T) {
+	cards := []CardInfo{
+		{CardID: 1, Type: 0, Queue: 0},
+		{CardID: 2, Type: 1, Queue: 1},
+		{CardID: 3, Type: 3, Queue: 3, Interval: 30},
+		{CardID: 4, Type: 2, Queue: 2, Interval: 20},
+		{CardID: 5, Type: 2, Queue: -2, Interval: 21},
+		{CardID: 6, Type: 2, Queue: -1, Interval: 40},
+		// Missing cards are skipped
+		{},
+	}
+	report := computeMaturity(cards)
+	if report.Total != 6 || report.New.Cards != 1 || report.Learning.Cards != 2 || report.Young.Cards != 1 ||
+		report.Mature.Cards != 1 || report.Suspended.Cards != 1 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	if report.Learning.Percent < 33.3 || report.Learning.Percent > 33.4 {
+		t.Errorf("Expected a third of the cards to be learning, got %.2f%%", report.Learning.Percent)
+	}
+}
+
+func TestMaturityReport(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	cardIDs, _ := server.ankiClient.FindCards("is:new")
+	if _, err := server.ankiClient.SuspendCards(cardIDs[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	text, isErr := callTool(t, server.handleMaturityReport, map[string]interface{}{"deck": "Spanish"})
+	for _, want := range []string{"Maturity of Spanish: 14 card(s)", "New: 6 (42.9%)", "Young: 5 (35.7%)", "Mature: 2 (14.3%)", "Suspended: 1 (7.1%)"} {
+		if isErr || !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the output, got %s", want, text)
+		}
+	}
+
+	text, _ = callTool(t, server.handleMaturityReport, map[string]interface{}{"deck": "Spanish::Grammar", "format": "json"})
+	var result struct {
+		Maturity maturityReport `json:"maturity"`
+	}
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Maturity.Total != 2 {
+		t.Errorf("Expected the 2 grammar cards, got %s", text)
+	}
+
+	if text, isErr := callTool(t, server.handleMaturityReport, map[string]interface{}{"deck": "Default"}); !isErr || !strings.Contains(text, "No cards found") {
+		t.Errorf("Expected an error for an empty deck, got %s", text)
+	}
+}
@@ -0,0 +1,504 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// defaultMediaLimit is the number of media files listed when no limit is given
+	defaultMediaLimit = 100
+	// maxMediaDownload is the largest media file downloaded from a URL
+	maxMediaDownload = 20 << 20
+)
+
+// mediaHTTPClient downloads media files from URLs
+var mediaHTTPClient = &http.Client{Timeout: 60 * time.Second}
+
+// registerMediaTools registers media file tools with the MCP server
+func (a *AnkiMCPServer) registerMediaTools(s *server.MCPServer) {
+	// Tool: Add Media
+	addMediaTool := mcp.NewTool("add_media",
+		mcp.WithDescription("Store an image, audio or video file in Anki's media folder from a local path, a URL or base64 data, and return the reference to put in a field. "+
+			"With url this server downloads the file, so it never has to pass through the conversation as base64."),
+		mcp.WithString("path",
+			mcp.Description("Path to the file, on the machine running this server"),
+		),
+		mcp.WithString("url",
+			mcp.Description("http(s) URL to download the file from, instead of path (up to 20 MB)"),
+		),
+		mcp.WithString("data",
+			mcp.Description("Base64-encoded file contents, instead of path or url; needs filename"),
+		),
+		mcp.WithString("filename",
+			mcp.Description("Name to store the file under; optional with path or url (default: the name from the path or URL)"),
+		),
+		withImageOptions(),
+	)
+	a.addChangingTool(s, addMediaTool, (*AnkiMCPServer).handleAddMedia)
+
+	// Tool: Get Media File
+	getMediaTool := mcp.NewTool("get_media_file",
+		mcp.WithDescription("Get a file from Anki's media folder, e.g. to inspect or reuse pronunciation audio or an image. "+
+			"With path the file is saved there; without path it is returned base64-encoded in a JSON document."),
+		mcp.WithString("filename",
+			mcp.Required(),
+			mcp.Description("Name of the file in the media folder, as used in fields (e.g. hola.mp3 from [sound:hola.mp3])"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Optional: Where to save the file, on the machine running this server"),
+		),
+	)
+	a.addTool(s, getMediaTool, (*AnkiMCPServer).handleGetMediaFile)
+
+	// Tool: List Media
+	listMediaTool := mcp.NewTool("list_media",
+		mcp.WithDescription("List the files in Anki's media folder matching a glob pattern, e.g. to find existing audio or images before uploading duplicates"),
+		mcp.WithString("pattern",
+			mcp.Description("Optional: Glob pattern for the file names, e.g. *.mp3 or hola* (default: *)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Optional: Maximum number of file names to return (default: 100)"),
+		),
+		withFormat(),
+	)
+	a.addTool(s, listMediaTool, (*AnkiMCPServer).handleListMedia)
+
+	// Tool: Delete Media File
+	deleteMediaTool := mcp.NewTool("delete_media_file",
+		mcp.WithDescription("Delete a file from Anki's media folder. Refuses when notes still refer to the file unless force is true. "+
+			"Only run it when the user has asked for the deletion, and pass confirm=true to acknowledge it."),
+		mcp.WithString("filename",
+			mcp.Required(),
+			mcp.Description("Name of the file in the media folder"),
+		),
+		mcp.WithBoolean("confirm",
+			mcp.Required(),
+			mcp.Description("Must be true to delete the file"),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Optional: Delete the file even if notes still refer to it (default: false)"),
+		),
+	)
+	a.addChangingTool(s, deleteMediaTool, (*AnkiMCPServer).handleDeleteMediaFile)
+}
+
+// handleGetMediaFile returns a media file or saves it to a path
+func (a *AnkiMCPServer) handleGetMediaFile(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	filename, ok := args["filename"].(string)
+	if !ok || strings.TrimSpace(filename) == "" {
+		return a.errorf("filename is required"), nil
+	}
+	path, _ := args["path"].(string)
+
+	encoded, found, err := a.ankiClient.RetrieveMediaFile(filename)
+	if err != nil {
+		return a.errorf("Failed to retrieve media file: %v", err), nil
+	}
+	if !found {
+		return a.errorf("Media file not found: %s", filename), nil
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return a.errorf("Failed to decode media file: %v", err), nil
+	}
+
+	if path != "" {
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return a.errorf("Failed to save media file: %v", err), nil
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: a.t("Saved %s to %s (%d bytes)", filename, path, len(data)),
+				},
+			},
+		}, nil
+	}
+
+	return a.jsonResult(map[string]interface{}{
+		"filename":  filename,
+		"mime_type": mime.TypeByExtension(filepath.Ext(filename)),
+		"size":      len(data),
+		"data":      encoded,
+	}), nil
+}
+
+// handleListMedia lists the media files matching a pattern
+func (a *AnkiMCPServer) handleListMedia(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	pattern, _ := args["pattern"].(string)
+	if strings.TrimSpace(pattern) == "" {
+		pattern = "*"
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return a.errorf("Invalid pattern %q: %v", pattern, err), nil
+	}
+	limit := defaultMediaLimit
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	names, err := a.ankiClient.GetMediaFilesNames(pattern)
+	if err != nil {
+		return a.errorf("Failed to list media files: %v", err), nil
+	}
+	total := len(names)
+	names = names[:min(total, limit)]
+
+	if wantsJSON(request) {
+		return a.jsonResult(map[string]interface{}{
+			"pattern": pattern,
+			"total":   total,
+			"files":   names,
+		}), nil
+	}
+
+	out := a.newOutput()
+	if total == 0 {
+		out.Line(a.t("No media files match %s", pattern))
+	} else {
+		out.Heading(a.t("Media files matching %s: showing %d of %d", pattern, len(names), total))
+		for _, name := range names {
+			out.Item(name)
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// globEscape escapes the glob metacharacters in a file name so that a
+// pattern matches only that name
+func globEscape(name string) string {
+	return strings.NewReplacer("*", "[*]", "?", "[?]", "[", "[[]").Replace(name)
+}
+
+// mediaReferences returns the notes whose fields refer to a media file
+func (a *AnkiMCPServer) mediaReferences(filename string) ([]int64, error) {
+	query := `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "*", `\*`, "_", `\_`).Replace(filename) + `"`
+	infos, err := a.ankiClient.FindNotesInfo(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// The search also matches longer names containing this one, so check
+	// the references themselves
+	refs := []string{"[sound:" + filename + "]", `src="` + filename + `"`, "src='" + filename + "'", "src=" + filename + ">", "src=" + filename + " "}
+	var found []int64
+	for _, info := range infos {
+		for _, name := range noteFieldNames(info) {
+			value, _ := noteField(info, name)
+			if slices.ContainsFunc(refs, func(ref string) bool { return strings.Contains(value, ref) }) {
+				found = append(found, info.NoteID)
+				break
+			}
+		}
+	}
+	return found, nil
+}
+
+// handleDeleteMediaFile deletes a media file after checking that no note
+// refers to it
+func (a *AnkiMCPServer) handleDeleteMediaFile(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	filename, ok := args["filename"].(string)
+	if !ok || strings.TrimSpace(filename) == "" {
+		return a.errorf("filename is required"), nil
+	}
+	force, _ := args["force"].(bool)
+
+	names, err := a.ankiClient.GetMediaFilesNames(globEscape(filename))
+	if err != nil {
+		return a.errorf("Failed to list media files: %v", err), nil
+	}
+	if !slices.Contains(names, filename) {
+		return a.errorf("Media file not found: %s", filename), nil
+	}
+
+	refs, err := a.mediaReferences(filename)
+	if err != nil {
+		return a.errorf("Failed to find notes: %v", err), nil
+	}
+	listed := refs[:min(len(refs), maxListedNotes)]
+	ids := make([]string, len(listed))
+	for i, id := range listed {
+		ids[i] = strconv.FormatInt(id, 10)
+	}
+	if len(refs) > 0 && !force {
+		return a.errorf("%s is still used by %d note(s): %s. Remove it from the notes first, or pass force=true to delete it anyway.",
+			filename, len(refs), strings.Join(ids, ", ")), nil
+	}
+	if confirm, _ := args["confirm"].(bool); !confirm {
+		return a.errorf("delete_media_file was not run. Ask the user to confirm deleting %s, then call again with confirm=true.", filename), nil
+	}
+
+	if err := a.ankiClient.DeleteMediaFile(filename); err != nil {
+		return a.errorf("Failed to delete media file: %v", err), nil
+	}
+
+	out := a.newOutput()
+	out.Line(a.t("Deleted media file %s", filename))
+	if len(refs) > 0 {
+		out.Line(a.t("Still used by %d note(s), which will show it as missing: %s", len(refs), strings.Join(ids, ", ")))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// openMediaURL starts downloading a media file. The content type must start
+// with one of the allowed prefixes, such as "image/". It returns the file name
+// taken from the URL, with an extension matching the content type, and the
+// body, which fails once it grows past maxMediaDownload.
+func openMediaURL(rawURL string, allowed ...string) (string, io.ReadCloser, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", nil, fmt.Errorf("not an http(s) URL: %s", rawURL)
+	}
+
+	resp, err := mediaHTTPClient.Get(u.String())
+	if err != nil {
+		return "", nil, err
+	}
+	fail := func(format string, args ...interface{}) (string, io.ReadCloser, error) {
+		_ = resp.Body.Close()
+		return "", nil, fmt.Errorf(format, args...)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fail("download failed: %s", resp.Status)
+	}
+	if resp.ContentLength > maxMediaDownload {
+		return fail("file is larger than %d MB", maxMediaDownload>>20)
+	}
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !slices.ContainsFunc(allowed, func(prefix string) bool { return strings.HasPrefix(contentType, prefix) }) {
+		return fail("unsupported content type %q", contentType)
+	}
+
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		name = "download"
+	}
+	if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 && !slices.Contains(exts, strings.ToLower(path.Ext(name))) {
+		name += exts[0]
+	}
+	return name, http.MaxBytesReader(nil, resp.Body, maxMediaDownload), nil
+}
+
+// storeMediaSource stores a media file from a path or URL, with an optional
+// prefix in front of its name, and verifies it. It returns the name the file
+// was requested under.
+func (a *AnkiMCPServer) storeMediaSource(filePath, fileURL, prefix, allowed string, opts imageOptions) (StoredMedia, string, *imageShrink, error) {
+	var name string
+	var body io.ReadCloser
+	if filePath != "" {
+		file, err := os.Open(filePath)
+		if err != nil {
+			return StoredMedia{}, "", nil, err
+		}
+		name, body = filepath.Base(filePath), file
+	} else {
+		var err error
+		if name, body, err = openMediaURL(fileURL, allowed); err != nil {
+			return StoredMedia{}, "", nil, err
+		}
+	}
+	defer body.Close()
+	if !strings.HasPrefix(name, prefix) {
+		name = prefix + name
+	}
+
+	media, shrink, err := a.storeImage(name, body, opts)
+	if err != nil {
+		return StoredMedia{}, "", nil, err
+	}
+	if err := a.ankiClient.VerifyMedia(media); err != nil {
+		return StoredMedia{}, "", nil, err
+	}
+	return media, name, shrink, nil
+}
+
+// cardMedia is a media file for a new card, given by a local path or a URL.
+// Downloads must have a content type starting with allowed, and opts shrink
+// images before they are stored.
+type cardMedia struct {
+	path, url string
+	allowed   string
+	opts      imageOptions
+}
+
+// storedFile is a media file stored for a new card
+type storedFile struct {
+	requested string
+	media     StoredMedia
+	shrink    *imageShrink
+}
+
+// storeCardMedia stores the media files of a new card, skipping those with
+// neither a path nor a URL. The files are stored before the note so their
+// final names can be used in the fields; Anki renames a file when a different
+// one has the same name. It returns the stored name of each file, empty when
+// it was skipped, and the stored files, or an error result when storing fails
+// or the call is cancelled.
+func (a *AnkiMCPServer) storeCardMedia(ctx context.Context, files []cardMedia, prefix string) ([]string, []storedFile, *mcp.CallToolResult) {
+	names := make([]string, len(files))
+	var stored []storedFile
+	for i, f := range files {
+		if f.path == "" && f.url == "" {
+			continue
+		}
+		if ctx.Err() != nil {
+			return nil, nil, a.errorf("Cancelled after storing %d media file(s); no card was created", len(stored))
+		}
+		media, requested, shrink, err := a.storeMediaSource(f.path, f.url, prefix, f.allowed, f.opts)
+		if err != nil {
+			return nil, nil, a.errorf("Failed to store %s: %v", f.path+f.url, err)
+		}
+		names[i] = media.Filename
+		stored = append(stored, storedFile{requested, media, shrink})
+	}
+	return names, stored, nil
+}
+
+// describeStoredMedia lists the media files stored for a new card
+func (a *AnkiMCPServer) describeStoredMedia(out *textOutput, stored []storedFile) {
+	if len(stored) == 0 {
+		return
+	}
+	out.Heading(a.t("Stored media"))
+	for _, f := range stored {
+		if f.media.Filename != f.requested {
+			out.Item(a.t("%s (renamed from %s to avoid overwriting an existing file, %d bytes, verified)", f.media.Filename, f.requested, f.media.Size))
+		} else {
+			out.Item(a.t("%s (%d bytes, verified)", f.media.Filename, f.media.Size))
+		}
+		if f.shrink != nil {
+			out.Item(a.describeShrink(f.media.Filename, f.shrink))
+		}
+	}
+}
+
+// mediaReference returns the field markup showing a media file
+func mediaReference(filename string) string {
+	if strings.HasPrefix(mime.TypeByExtension(path.Ext(filename)), "image/") {
+		return fmt.Sprintf(`<img src="%s">`, filename)
+	}
+	return fmt.Sprintf("[sound:%s]", filename)
+}
+
+// handleAddMedia stores a media file from a path or URL
+func (a *AnkiMCPServer) handleAddMedia(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	filePath, _ := args["path"].(string)
+	fileURL, _ := args["url"].(string)
+	data, _ := args["data"].(string)
+	sources := 0
+	for _, source := range []string{filePath, fileURL, data} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return a.errorf("Pass one of path, url or data"), nil
+	}
+	filename, _ := args["filename"].(string)
+	opts, errResult := a.imageOptions(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	var body io.ReadCloser
+	requested := filename
+	switch {
+	case fileURL != "":
+		name, download, err := openMediaURL(fileURL, "image/", "audio/", "video/")
+		if err != nil {
+			return a.errorf("Failed to download %s: %v", fileURL, err), nil
+		}
+		body = download
+		if requested == "" {
+			requested = name
+		}
+	case filePath != "":
+		file, err := os.Open(filePath)
+		if err != nil {
+			return a.errorf("Failed to read %s: %v", filePath, err), nil
+		}
+		body = file
+		if requested == "" {
+			requested = filepath.Base(filePath)
+		}
+	default:
+		if requested == "" {
+			return a.errorf("filename is required with data"), nil
+		}
+		body = io.NopCloser(base64.NewDecoder(base64.StdEncoding, strings.NewReader(data)))
+	}
+	defer body.Close()
+
+	// Only images are shrunk; other files are streamed as they are
+	if !strings.HasPrefix(mime.TypeByExtension(path.Ext(requested)), "image/") {
+		opts = imageOptions{}
+	}
+	media, shrink, err := a.storeImage(requested, body, opts)
+	if err != nil {
+		return a.errorf("Failed to store %s: %v", requested, err), nil
+	}
+	if err := a.ankiClient.VerifyMedia(media); err != nil {
+		return a.errorf("Failed to verify stored media: %v", err), nil
+	}
+
+	out := a.newOutput()
+	if media.Filename != requested {
+		out.Line(a.t("%s (renamed from %s to avoid overwriting an existing file, %d bytes, verified)", media.Filename, requested, media.Size))
+	} else {
+		out.Line(a.t("%s (%d bytes, verified)", media.Filename, media.Size))
+	}
+	if shrink != nil {
+		out.Line(a.describeShrink(media.Filename, shrink))
+	}
+	out.Line(a.t("Reference: %s", mediaReference(media.Filename)))
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
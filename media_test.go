@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetMediaFile(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.media["hola.mp3"] = []byte("ID3 hola")
+
+	text, isErr := callTool(t, server.handleGetMediaFile, map[string]interface{}{"filename": "hola.mp3"})
+	var result struct {
+		MimeType string `json:"mime_type"`
+		Size     int    `json:"size"`
+		Data     []byte `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(text), &result); isErr || err != nil {
+		t.Fatalf("Unexpected output %s: %v", text, err)
+	}
+	if result.MimeType != "audio/mpeg" || result.Size != 8 || string(result.Data) != "ID3 hola" {
+		t.Errorf("Unexpected file: %s", text)
+	}
+
+	path := filepath.Join(t.TempDir(), "hola.mp3")
+	text, isErr = callTool(t, server.handleGetMediaFile, map[string]interface{}{"filename": "hola.mp3", "path": path})
+	if isErr || text != "Saved hola.mp3 to "+path+" (8 bytes)" {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "ID3 hola" {
+		t.Errorf("Unexpected saved file %q: %v", data, err)
+	}
+
+	if text, isErr := callTool(t, server.handleGetMediaFile, map[string]interface{}{"filename": "adios.mp3"}); !isErr || text != "Error: Media file not found: adios.mp3" {
+		t.Errorf("Expected a not found error, got: %s", text)
+	}
+}
+
+func TestListMedia(t *testing.T) {
+	server, mock := newMockServer(t)
+	for _, name := range []string{"hola.mp3", "adios.mp3", "casa.jpg"} {
+		mock.media[name] = []byte(name)
+	}
+
+	text, isErr := callTool(t, server.handleListMedia, map[string]interface{}{"pattern": "*.mp3"})
+	if isErr || text != "## Media files matching *.mp3: showing 2 of 2\n- adios.mp3\n- hola.mp3" {
+		t.Errorf("Unexpected output: %q", text)
+	}
+
+	text, _ = callTool(t, server.handleListMedia, map[string]interface{}{"limit": float64(1), "format": "json"})
+	var result struct {
+		Total int      `json:"total"`
+		Files []string `json:"files"`
+	}
+	if err := json.Unmarshal([]byte(text), &result); err != nil || result.Total != 3 || len(result.Files) != 1 || result.Files[0] != "adios.mp3" {
+		t.Errorf("Unexpected output %s: %v", text, err)
+	}
+
+	if text, isErr := callTool(t, server.handleListMedia, map[string]interface{}{"pattern": "*.wav"}); isErr || text != "No media files match *.wav" {
+		t.Errorf("Unexpected output: %s", text)
+	}
+}
+
+func TestDeleteMediaFile(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.media["hola.mp3"] = []byte("hola")
+	mock.media["hola.mp3.bak"] = []byte("hola")
+	noteID, err := mock.addNote(Note{DeckName: "Default", ModelName: "Basic", Fields: map[string]string{"Front": "hola [sound:hola.mp3]", "Back": "hello"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := map[string]interface{}{"filename": "hola.mp3", "confirm": true}
+	if text, isErr := callTool(t, server.handleDeleteMediaFile, args); !isErr || !strings.Contains(text, fmt.Sprintf("still used by 1 note(s): %d", noteID)) {
+		t.Errorf("Expected a reference error, got: %s", text)
+	}
+
+	args["force"] = true
+	text, isErr := callTool(t, server.handleDeleteMediaFile, args)
+	if isErr || !strings.Contains(text, "Deleted media file hola.mp3") || !strings.Contains(text, "Still used by 1 note(s)") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	if _, ok := mock.media["hola.mp3"]; ok {
+		t.Error("Expected hola.mp3 to be deleted")
+	}
+
+	args = map[string]interface{}{"filename": "hola.mp3.bak"}
+	if text, isErr := callTool(t, server.handleDeleteMediaFile, args); !isErr || !strings.Contains(text, "confirm=true") {
+		t.Errorf("Expected the deletion to require confirmation, got: %s", text)
+	}
+	args["confirm"] = true
+	if text, isErr := callTool(t, server.handleDeleteMediaFile, args); isErr || text != "Deleted media file hola.mp3.bak" {
+		t.Errorf("Unexpected output: %s", text)
+	}
+}
+
+func TestAddMedia(t *testing.T) {
+	server, mock := newMockServer(t)
+	web := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/img/gato.png":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("PNG gato"))
+		case "/page":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer web.Close()
+
+	text, isErr := callTool(t, server.handleAddMedia, map[string]interface{}{"url": web.URL + "/img/gato.png"})
+	if isErr || text != "gato.png (8 bytes, verified)\nReference: <img src=\"gato.png\">" {
+		t.Errorf("Unexpected output: %q", text)
+	}
+	if string(mock.media["gato.png"]) != "PNG gato" {
+		t.Errorf("Unexpected stored media: %q", mock.media["gato.png"])
+	}
+
+	if text, isErr := callTool(t, server.handleAddMedia, map[string]interface{}{"url": web.URL + "/page"}); !isErr || !strings.Contains(text, `unsupported content type "text/html"`) {
+		t.Errorf("Expected a content type error, got: %s", text)
+	}
+	if text, isErr := callTool(t, server.handleAddMedia, map[string]interface{}{"url": web.URL + "/missing.mp3"}); !isErr || !strings.Contains(text, "404") {
+		t.Errorf("Expected a download error, got: %s", text)
+	}
+	if text, isErr := callTool(t, server.handleAddMedia, map[string]interface{}{"url": "file:///etc/passwd"}); !isErr || !strings.Contains(text, "not an http(s) URL") {
+		t.Errorf("Expected a URL error, got: %s", text)
+	}
+
+	audio := filepath.Join(t.TempDir(), "hola.mp3")
+	if err := os.WriteFile(audio, []byte("ID3 hola"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	text, isErr = callTool(t, server.handleAddMedia, map[string]interface{}{"path": audio, "filename": "es_hola.mp3"})
+	if isErr || !strings.Contains(text, "Reference: [sound:es_hola.mp3]") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+
+	text, isErr = callTool(t, server.handleAddMedia, map[string]interface{}{"data": "SUQzIGFkaW9z", "filename": "adios.mp3"})
+	if isErr || !strings.Contains(text, "adios.mp3 (9 bytes, verified)") || string(mock.media["adios.mp3"]) != "ID3 adios" {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	if text, isErr := callTool(t, server.handleAddMedia, map[string]interface{}{"data": "SUQzIGFkaW9z"}); !isErr || !strings.Contains(text, "filename is required") {
+		t.Errorf("Expected a filename error, got: %s", text)
+	}
+
+	// create_card downloads media from URLs too
+	args := map[string]interface{}{"deck": "Default", "front": "gato", "back": "cat", "image_url": web.URL + "/img/gato.png"}
+	if text, isErr := callTool(t, server.handleCreateCard, args); isErr || !strings.Contains(text, "gato.png (8 bytes, verified)") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	args = map[string]interface{}{"deck": "Default", "front": "perro", "back": "dog", "front_audio_url": web.URL + "/img/gato.png"}
+	if text, isErr := callTool(t, server.handleCreateCard, args); !isErr || !strings.Contains(text, "Failed to store http") {
+		t.Errorf("Expected a content type error, got: %s", text)
+	}
+}
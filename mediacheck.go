@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerMediaCheckTools registers tools auditing the media folder against
+// the notes with the MCP server
+func (a *AnkiMCPServer) registerMediaCheckTools(s *server.MCPServer) {
+	// Tool: Find Unused Media
+	findUnusedMediaTool := mcp.NewTool("find_unused_media",
+		mcp.WithDescription("Find files in Anki's media folder that no note field refers to, e.g. leftovers from deleted notes or abandoned uploads. "+
+			"Files starting with _ are skipped, since Anki keeps them for note types. "+
+			"With delete=true the unused files are deleted; only do that when the user has asked for it, and pass confirm=true to acknowledge it."),
+		mcp.WithString("pattern",
+			mcp.Description("Optional: Glob pattern limiting the files checked, e.g. *.mp3 (default: *)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Optional: Maximum number of file names to list (default: 100); all unused files are deleted regardless"),
+		),
+		mcp.WithBoolean("delete",
+			mcp.Description("Optional: Delete the unused files; Anki moves them to its media trash (default: false)"),
+		),
+		mcp.WithBoolean("confirm",
+			mcp.Description("Must be true together with delete"),
+		),
+		withFormat(),
+	)
+	a.addChangingTool(s, findUnusedMediaTool, (*AnkiMCPServer).handleFindUnusedMedia)
+
+	// Tool: Check Media References
+	checkMediaReferencesTool := mcp.NewTool("check_media_references",
+		mcp.WithDescription("Find note fields referring to media files that are missing from Anki's media folder, as [sound:...] or <img src=\"...\">, "+
+			"and return the IDs of the affected notes so the references can be fixed or the files added again"),
+		mcp.WithString("query",
+			mcp.Description("Optional: Anki search limiting the notes checked, e.g. deck:Spanish (default: all notes)"),
+		),
+		withFormat(),
+	)
+	a.addTool(s, checkMediaReferencesTool, (*AnkiMCPServer).handleCheckMediaReferences)
+}
+
+var (
+	// soundRefPattern matches a [sound:...] reference
+	soundRefPattern = regexp.MustCompile(`\[sound:([^\]]+)\]`)
+	// htmlRefPattern matches the file attribute of elements showing media,
+	// the way Anki's Check Media finds them
+	htmlRefPattern = regexp.MustCompile(`(?i)<(?:img|audio|video|source)\b[^>]*?\ssrc\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))|<object\b[^>]*?\sdata\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+)
+
+// fieldMediaRefs returns the names of the media files a field refers to with
+// [sound:...] or an element such as <img src="...">. Remote and data URLs are
+// not media files and are left out; HTML and URL escapes are decoded.
+func fieldMediaRefs(value string) []string {
+	var refs []string
+	for _, m := range soundRefPattern.FindAllStringSubmatch(value, -1) {
+		refs = append(refs, m[1])
+	}
+	for _, m := range htmlRefPattern.FindAllStringSubmatch(value, -1) {
+		ref := strings.TrimSpace(html.UnescapeString(strings.Join(m[1:], "")))
+		if ref == "" || strings.HasPrefix(ref, "//") || strings.HasPrefix(strings.ToLower(ref), "data:") ||
+			sanitizeSchemePattern.MatchString(strings.ToLower(ref)) {
+			continue
+		}
+		if unescaped, err := url.PathUnescape(ref); err == nil {
+			ref = unescaped
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// referencedMedia returns the media files the fields of notes refer to
+func referencedMedia(notes []NoteInfo) map[string]bool {
+	used := make(map[string]bool)
+	for _, note := range notes {
+		for _, field := range note.Fields {
+			for _, ref := range fieldMediaRefs(field.Value) {
+				used[ref] = true
+			}
+		}
+	}
+	return used
+}
+
+// handleFindUnusedMedia lists the media files no note refers to, deleting
+// them when asked
+func (a *AnkiMCPServer) handleFindUnusedMedia(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	pattern, _ := args["pattern"].(string)
+	if strings.TrimSpace(pattern) == "" {
+		pattern = "*"
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return a.errorf("Invalid pattern %q: %v", pattern, err), nil
+	}
+	limit := defaultMediaLimit
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+	del, _ := args["delete"].(bool)
+	if confirm, _ := args["confirm"].(bool); del && !confirm {
+		return a.errorf("find_unused_media deleted nothing. Ask the user to confirm deleting the unused media files, then call again with delete=true and confirm=true."), nil
+	}
+
+	names, err := a.ankiClient.GetMediaFilesNames(pattern)
+	if err != nil {
+		return a.errorf("Failed to list media files: %v", err), nil
+	}
+	notes, err := a.ankiClient.FindNotesInfo("deck:*")
+	if err != nil {
+		return a.errorf("Failed to find notes: %v", err), nil
+	}
+	used := referencedMedia(notes)
+
+	var unused []string
+	skipped := 0
+	for _, name := range names {
+		switch {
+		case strings.HasPrefix(name, "_"):
+			// Anki keeps these for templates and styling
+			skipped++
+		case !used[name]:
+			unused = append(unused, name)
+		}
+	}
+
+	deleted := 0
+	if del && len(unused) > 0 {
+		if err := a.ankiClient.DeleteMediaFiles(unused); err != nil {
+			return a.errorf("Failed to delete media files: %v", err), nil
+		}
+		deleted = len(unused)
+	}
+	listed := unused[:min(len(unused), limit)]
+
+	if wantsJSON(request) {
+		if listed == nil {
+			listed = []string{}
+		}
+		return a.jsonResult(map[string]interface{}{
+			"checked": len(names),
+			"skipped": skipped,
+			"unused":  len(unused),
+			"deleted": deleted,
+			"files":   listed,
+		}), nil
+	}
+
+	out := a.newOutput()
+	switch {
+	case len(unused) == 0:
+		out.Line(a.t("All %d media file(s) matching %s are used by notes", len(names)-skipped, pattern))
+	case deleted > 0:
+		out.Heading(a.t("Deleted %d unused media file(s) of %d: showing %d", deleted, len(names), len(listed)))
+	default:
+		out.Heading(a.t("%d unused media file(s) of %d: showing %d", len(unused), len(names), len(listed)))
+	}
+	for _, name := range listed {
+		out.Item(name)
+	}
+	if skipped > 0 {
+		out.Line(a.t("Skipped %d file(s) starting with _, which Anki keeps for note types", skipped))
+	}
+	if deleted > 0 {
+		out.Line(a.t("Anki moved the files to its media trash, from where Tools > Check Media can restore them."))
+	} else if len(unused) > 0 {
+		out.Line(a.t("Call again with delete=true and confirm=true to delete them, once the user has agreed."))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// brokenMediaRef is a media file that is missing but referred to by notes
+type brokenMediaRef struct {
+	Filename string  `json:"filename"`
+	Notes    []int64 `json:"notes"`
+}
+
+// handleCheckMediaReferences lists the references to missing media files and
+// the notes containing them
+func (a *AnkiMCPServer) handleCheckMediaReferences(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	query, _ := args["query"].(string)
+	if strings.TrimSpace(query) == "" {
+		query = "deck:*"
+	}
+
+	var notes []NoteInfo
+	var names []string
+	b := a.ankiClient.newBatch()
+	b.add("notesInfo", map[string]string{"query": query}, &notes)
+	b.add("getMediaFilesNames", map[string]string{"pattern": "*"}, &names)
+	if err := b.send(); err != nil {
+		return a.errorf("Failed to check media references: %v", err), nil
+	}
+	present := make(map[string]bool, len(names))
+	for _, name := range names {
+		present[name] = true
+	}
+
+	missing := make(map[string][]int64)
+	var affected []int64
+	for _, note := range notes {
+		broken := false
+		for _, field := range note.Fields {
+			for _, ref := range fieldMediaRefs(field.Value) {
+				if present[ref] || slices.Contains(missing[ref], note.NoteID) {
+					continue
+				}
+				missing[ref] = append(missing[ref], note.NoteID)
+				broken = true
+			}
+		}
+		if broken {
+			affected = append(affected, note.NoteID)
+		}
+	}
+	refs := make([]brokenMediaRef, 0, len(missing))
+	for name, ids := range missing {
+		refs = append(refs, brokenMediaRef{Filename: name, Notes: ids})
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Filename < refs[j].Filename })
+	slices.Sort(affected)
+
+	if wantsJSON(request) {
+		if affected == nil {
+			affected = []int64{}
+		}
+		return a.jsonResult(map[string]interface{}{
+			"checked": len(notes),
+			"missing": refs,
+			"notes":   affected,
+		}), nil
+	}
+
+	out := a.newOutput()
+	if len(refs) == 0 {
+		out.Line(a.t("All media files referred to by the %d note(s) checked exist", len(notes)))
+	} else {
+		out.Heading(a.t("%d missing media file(s) referred to by %d of %d note(s)", len(refs), len(affected), len(notes)))
+		for _, ref := range refs {
+			listed := ref.Notes[:min(len(ref.Notes), maxListedNotes)]
+			out.Item(a.t("%s: note(s) %s", ref.Filename, joinIDs(listed)))
+		}
+		ids := make([]string, len(affected))
+		for i, id := range affected {
+			ids[i] = strconv.FormatInt(id, 10)
+		}
+		out.Line(a.t("Search for the affected notes with: nid:%s", strings.Join(ids, ",")))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
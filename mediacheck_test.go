@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFieldMediaRefs(t *testing.T) {
+	value := `hola [sound:hola.mp3]<img src="gato%20negro.jpg"> <IMG alt=x SRC='perro&amp;gato.png'>` +
+		`<img src=sol.gif><img src="https://example.com/a.png"><img src="data:image/png;base64,AAAA"><object data="mapa.svg"></object>`
+	want := []string{"hola.mp3", "gato negro.jpg", "perro&gato.png", "sol.gif", "mapa.svg"}
+	if got := fieldMediaRefs(value); !reflect.DeepEqual(got, want) {
+		t.Errorf("fieldMediaRefs() = %q, want %q", got, want)
+	}
+}
+
+func TestFindUnusedMedia(t *testing.T) {
+	server, mock := newMockServer(t)
+	for _, name := range []string{"hola.mp3", "gato.jpg", "viejo.mp3", "huérfano.png", "_fuente.ttf"} {
+		mock.media[name] = []byte(name)
+	}
+	if _, err := mock.addNote(Note{DeckName: "Default", ModelName: "Basic", Fields: map[string]string{"Front": "hola [sound:hola.mp3]", "Back": `<img src="gato.jpg">`}}); err != nil {
+		t.Fatal(err)
+	}
+
+	text, isErr := callTool(t, server.handleFindUnusedMedia, map[string]interface{}{})
+	if isErr || !strings.Contains(text, "2 unused media file(s) of 5") || !strings.Contains(text, "huérfano.png") ||
+		!strings.Contains(text, "viejo.mp3") || strings.Contains(text, "hola.mp3") || !strings.Contains(text, "Skipped 1 file(s)") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+
+	if text, isErr := callTool(t, server.handleFindUnusedMedia, map[string]interface{}{"delete": true}); !isErr || len(mock.media) != 5 {
+		t.Fatalf("Expected deleting without confirm to fail, got %s", text)
+	}
+	text, isErr = callTool(t, server.handleFindUnusedMedia, map[string]interface{}{"pattern": "*.mp3", "delete": true, "confirm": true})
+	if isErr || !strings.Contains(text, "Deleted 1 unused media file(s) of 2") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	if _, ok := mock.media["viejo.mp3"]; ok || len(mock.media) != 4 {
+		t.Errorf("Expected only viejo.mp3 to be deleted, media is now %d files", len(mock.media))
+	}
+}
+
+func TestCheckMediaReferences(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.media["hola.mp3"] = []byte("ID3 hola")
+	ok, _ := mock.addNote(Note{DeckName: "Default", ModelName: "Basic", Fields: map[string]string{"Front": "hola [sound:hola.mp3]", "Back": "hello"}})
+	broken, _ := mock.addNote(Note{DeckName: "Default", ModelName: "Basic", Fields: map[string]string{"Front": "gato [sound:gato.mp3]", "Back": `<img src="gato.jpg"><img src="https://example.com/gato.png">`}})
+
+	text, isErr := callTool(t, server.handleCheckMediaReferences, map[string]interface{}{})
+	if isErr || !strings.Contains(text, "2 missing media file(s) referred to by 1 of 2 note(s)") ||
+		!strings.Contains(text, fmt.Sprintf("gato.jpg: note(s) %d", broken)) || strings.Contains(text, "example.com") ||
+		!strings.Contains(text, fmt.Sprintf("nid:%d", broken)) {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+
+	text, _ = callTool(t, server.handleCheckMediaReferences, map[string]interface{}{"query": fmt.Sprintf("nid:%d", ok)})
+	if !strings.Contains(text, "All media files referred to by the 1 note(s) checked exist") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+}
@@ -0,0 +1,2004 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// mockToday is the day number (days since collection creation) the mock collection treats as today
+const mockToday = 1000
+
+// mockModel describes a note type of the mock collection
+type mockModel struct {
+	Fields    []string
+	Templates []CardTemplate
+	CSS       string
+	Cloze     bool
+}
+
+// mockCSS is the styling Anki gives new note types
+const mockCSS = ".card {\n    font-family: arial;\n    font-size: 20px;\n    text-align: center;\n    color: black;\n    background-color: white;\n}\n"
+
+// mockNote is a note stored in the mock collection
+type mockNote struct {
+	ID     int64
+	Model  string
+	Fields map[string]string
+	Tags   []string
+	Mod    int64
+}
+
+// mockCard is a card stored in the mock collection
+type mockCard struct {
+	ID       int64
+	NoteID   int64
+	Deck     string
+	Ord      int
+	Type     int
+	Queue    int
+	Due      int64
+	Interval int64
+	Factor   int64
+	Reps     int64
+	Lapses   int64
+	Left     int64
+	Mod      int64
+}
+
+// mockAnkiConnect is an in-memory fake of the AnkiConnect addon holding decks,
+// notes, cards, models, media and a review log. It serves the AnkiConnect HTTP
+// API as an http.Handler, which Client serves on a loopback port. It is used
+// by the --mock flag for demos and by the test suite.
+type mockAnkiConnect struct {
+	mu      sync.Mutex
+	nextID  int64
+	decks   map[string]int64
+	models  map[string]*mockModel
+	notes   map[int64]*mockNote
+	cards   map[int64]*mockCard
+	media   map[string][]byte
+	reviews []ReviewEntry
+
+	// deckConfigs holds the options groups by ID; decks missing from
+	// deckConfigIDs use the default group 1
+	deckConfigs   map[int64]map[string]interface{}
+	deckConfigIDs map[string]int64
+
+	// mediaDir, when set, is reported as the media folder and receives a copy
+	// of every stored media file
+	mediaDir string
+
+	// The review screen: the deck being reviewed, its current card and
+	// whether the answer is shown
+	guiDeck        string
+	guiCard        int64
+	guiAnswerShown bool
+	// guiBrowseQuery is the search the card browser was last opened with
+	guiBrowseQuery string
+	// guiAddNote is the note the Add dialog was last opened with
+	guiAddNote *Note
+
+	// staleTags holds tags removed from notes. Like Anki, the mock keeps them
+	// in the tag list until clearUnusedTags runs.
+	staleTags map[string]bool
+
+	// apiKey, when set, must be sent with every action
+	apiKey string
+
+	// server serves the mock for clients from Client
+	server *http.Server
+}
+
+// mockAPIKeyError is the error AnkiConnect reports for a missing or wrong API key
+const mockAPIKeyError = "valid api key must be provided"
+
+// newMockAnkiConnect creates an empty mock collection with the default deck and
+// the standard Basic, reversed and Cloze note types
+func newMockAnkiConnect() *mockAnkiConnect {
+	m := &mockAnkiConnect{
+		nextID: 1700000000000,
+		decks:  map[string]int64{"Default": 1},
+		models: map[string]*mockModel{
+			"Basic": {
+				Fields: []string{"Front", "Back"},
+				Templates: []CardTemplate{
+					{Name: "Card 1", Front: "{{Front}}", Back: "{{FrontSide}}\n\n<hr id=answer>\n\n{{Back}}"},
+				},
+				CSS: mockCSS,
+			},
+			"Basic (and reversed card)": {
+				Fields: []string{"Front", "Back"},
+				Templates: []CardTemplate{
+					{Name: "Card 1", Front: "{{Front}}", Back: "{{FrontSide}}\n\n<hr id=answer>\n\n{{Back}}"},
+					{Name: "Card 2", Front: "{{Back}}", Back: "{{FrontSide}}\n\n<hr id=answer>\n\n{{Front}}"},
+				},
+				CSS: mockCSS,
+			},
+			"Cloze": {
+				Fields: []string{"Text", "Back Extra"},
+				Templates: []CardTemplate{
+					{Name: "Cloze", Front: "{{cloze:Text}}", Back: "{{cloze:Text}}<br>\n{{Back Extra}}"},
+				},
+				CSS:   mockCSS + ".cloze {\n    font-weight: bold;\n    color: blue;\n}\n",
+				Cloze: true,
+			},
+		},
+		notes: make(map[int64]*mockNote),
+		cards: make(map[int64]*mockCard),
+		media: make(map[string][]byte),
+		deckConfigs: map[int64]map[string]interface{}{
+			1: mockDeckConfig(1, "Default"),
+		},
+		deckConfigIDs: make(map[string]int64),
+	}
+	return m
+}
+
+// Client returns an AnkiConnect client talking to the mock. The first call
+// starts serving the mock on a free loopback port, until Close.
+func (m *mockAnkiConnect) Client() (*AnkiConnect, error) {
+	if m.server == nil {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return nil, fmt.Errorf("failed to serve the mock collection: %w", err)
+		}
+		m.server = &http.Server{Addr: l.Addr().String(), Handler: m, ReadHeaderTimeout: 10 * time.Second}
+		go func() { _ = m.server.Serve(l) }()
+	}
+	return NewAnkiConnectWithURL("http://" + m.server.Addr), nil
+}
+
+// Close stops serving the mock to clients from Client
+func (m *mockAnkiConnect) Close() error {
+	if m.server == nil {
+		return nil
+	}
+	return m.server.Close()
+}
+
+// mockResponse is a response in the AnkiConnect format
+type mockResponse struct {
+	Result interface{} `json:"result"`
+	Error  string      `json:"error"`
+}
+
+// ServeHTTP serves the AnkiConnect HTTP API
+func (m *mockAnkiConnect) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Action string          `json:"action"`
+		Key    string          `json:"key"`
+		Params json.RawMessage `json:"params"`
+	}
+
+	var resp mockResponse
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error = fmt.Sprintf("invalid request: %v", err)
+	} else if m.apiKey != "" && req.Key != m.apiKey {
+		resp.Error = mockAPIKeyError
+	} else {
+		m.mu.Lock()
+		result, err := m.handle(req.Action, req.Params)
+		m.mu.Unlock()
+		if err != nil {
+			resp.Error = err.Error()
+		} else {
+			resp.Result = result
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// handle dispatches a single AnkiConnect action
+func (m *mockAnkiConnect) handle(action string, raw json.RawMessage) (interface{}, error) {
+	decode := func(v interface{}) error {
+		if len(raw) == 0 {
+			return nil
+		}
+		return json.Unmarshal(raw, v)
+	}
+
+	switch action {
+	case "version":
+		return ankiConnectVersion, nil
+
+	case "getActiveProfile":
+		return "User 1", nil
+
+	case "sync", "guiCheckDatabase":
+		return true, nil
+
+	case "deckNames":
+		names := make([]string, 0, len(m.decks))
+		for name := range m.decks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, nil
+
+	case "getDeckStats":
+		var p struct {
+			Decks []string `json:"decks"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		stats := make(map[string]interface{}, len(p.Decks))
+		for _, name := range p.Decks {
+			id, ok := m.decks[name]
+			if !ok {
+				continue
+			}
+			deckStats, err := m.deckStats(name)
+			if err != nil {
+				return nil, err
+			}
+			deckStats["deck_id"] = id
+			stats[strconv.FormatInt(id, 10)] = deckStats
+		}
+		return stats, nil
+
+	case "createDeck":
+		var p struct {
+			Deck string `json:"deck"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		return m.createDeck(p.Deck), nil
+
+	case "deleteDecks":
+		var p struct {
+			Decks    []string `json:"decks"`
+			CardsToo bool     `json:"cardsToo"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		if !p.CardsToo {
+			return nil, fmt.Errorf("since AnkiConnect v6, cardsToo must be set to true")
+		}
+		m.deleteDecks(p.Decks)
+		return nil, nil
+
+	case "getDeckConfig":
+		var p struct {
+			Deck string `json:"deck"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		if _, ok := m.decks[p.Deck]; !ok {
+			return false, nil
+		}
+		return m.deckConfigs[m.deckConfigID(p.Deck)], nil
+
+	case "setDeckConfigId":
+		var p struct {
+			Decks    []string `json:"decks"`
+			ConfigID int64    `json:"configId"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		if _, ok := m.deckConfigs[p.ConfigID]; !ok {
+			return false, nil
+		}
+		for _, deck := range p.Decks {
+			if _, ok := m.decks[deck]; !ok {
+				return false, nil
+			}
+		}
+		for _, deck := range p.Decks {
+			m.deckConfigIDs[deck] = p.ConfigID
+		}
+		return true, nil
+
+	case "cloneDeckConfigId":
+		var p struct {
+			Name      string `json:"name"`
+			CloneFrom int64  `json:"cloneFrom"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		source, ok := m.deckConfigs[p.CloneFrom]
+		if !ok {
+			return false, nil
+		}
+		id := m.newID()
+		clone := copyMockConfig(source)
+		clone["id"] = id
+		clone["name"] = p.Name
+		m.deckConfigs[id] = clone
+		return id, nil
+
+	case "saveDeckConfig":
+		var p struct {
+			Config map[string]interface{} `json:"config"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		id, _ := p.Config["id"].(float64)
+		if _, ok := m.deckConfigs[int64(id)]; !ok {
+			return false, nil
+		}
+		m.deckConfigs[int64(id)] = p.Config
+		return true, nil
+
+	case "removeDeckConfigId":
+		var p struct {
+			ConfigID int64 `json:"configId"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		if _, ok := m.deckConfigs[p.ConfigID]; !ok || p.ConfigID == 1 {
+			return false, nil
+		}
+		delete(m.deckConfigs, p.ConfigID)
+		for deck, id := range m.deckConfigIDs {
+			if id == p.ConfigID {
+				delete(m.deckConfigIDs, deck)
+			}
+		}
+		return true, nil
+
+	case "modelNames":
+		names := make([]string, 0, len(m.models))
+		for name := range m.models {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, nil
+
+	case "createModel":
+		var p struct {
+			ModelName     string         `json:"modelName"`
+			InOrderFields []string       `json:"inOrderFields"`
+			CardTemplates []CardTemplate `json:"cardTemplates"`
+			IsCloze       bool           `json:"isCloze"`
+			CSS           string         `json:"css"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		if _, ok := m.models[p.ModelName]; ok {
+			return nil, fmt.Errorf("Model name already exists")
+		}
+		m.models[p.ModelName] = &mockModel{Fields: p.InOrderFields, Templates: p.CardTemplates, CSS: p.CSS, Cloze: p.IsCloze}
+		return map[string]interface{}{"name": p.ModelName}, nil
+
+	case "modelTemplates":
+		var p struct {
+			ModelName string `json:"modelName"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		model, ok := m.models[p.ModelName]
+		if !ok {
+			return nil, fmt.Errorf("model was not found: %s", p.ModelName)
+		}
+		templates := make(map[string]interface{}, len(model.Templates))
+		for _, tmpl := range model.Templates {
+			templates[tmpl.Name] = map[string]string{"Front": tmpl.Front, "Back": tmpl.Back}
+		}
+		return templates, nil
+
+	case "updateModelTemplates":
+		var p struct {
+			Model struct {
+				Name      string                       `json:"name"`
+				Templates map[string]map[string]string `json:"templates"`
+			} `json:"model"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		model, ok := m.models[p.Model.Name]
+		if !ok {
+			return nil, fmt.Errorf("model was not found: %s", p.Model.Name)
+		}
+		for i, tmpl := range model.Templates {
+			update, ok := p.Model.Templates[tmpl.Name]
+			if !ok {
+				continue
+			}
+			if front, ok := update["Front"]; ok {
+				model.Templates[i].Front = front
+			}
+			if back, ok := update["Back"]; ok {
+				model.Templates[i].Back = back
+			}
+		}
+		return nil, nil
+
+	case "modelStyling":
+		var p struct {
+			ModelName string `json:"modelName"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		model, ok := m.models[p.ModelName]
+		if !ok {
+			return nil, fmt.Errorf("model was not found: %s", p.ModelName)
+		}
+		return map[string]string{"css": model.CSS}, nil
+
+	case "updateModelStyling":
+		var p struct {
+			Model struct {
+				Name string `json:"name"`
+				CSS  string `json:"css"`
+			} `json:"model"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		model, ok := m.models[p.Model.Name]
+		if !ok {
+			return nil, fmt.Errorf("model was not found: %s", p.Model.Name)
+		}
+		model.CSS = p.Model.CSS
+		return nil, nil
+
+	case "findAndReplaceInModels":
+		p := struct {
+			ModelName   string `json:"modelName"`
+			FindText    string `json:"findText"`
+			ReplaceText string `json:"replaceText"`
+			Front       bool   `json:"front"`
+			Back        bool   `json:"back"`
+			CSS         bool   `json:"css"`
+		}{Front: true, Back: true, CSS: true}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		names := []string{p.ModelName}
+		if p.ModelName == "" {
+			names = slices.Sorted(maps.Keys(m.models))
+		} else if _, ok := m.models[p.ModelName]; !ok {
+			return nil, fmt.Errorf("model was not found: %s", p.ModelName)
+		}
+		updated := 0
+		for _, name := range names {
+			model := m.models[name]
+			changed := false
+			replace := func(text *string) {
+				if strings.Contains(*text, p.FindText) {
+					*text = strings.ReplaceAll(*text, p.FindText, p.ReplaceText)
+					changed = true
+				}
+			}
+			if p.CSS {
+				replace(&model.CSS)
+			}
+			for i := range model.Templates {
+				if p.Front {
+					replace(&model.Templates[i].Front)
+				}
+				if p.Back {
+					replace(&model.Templates[i].Back)
+				}
+			}
+			if changed {
+				updated++
+			}
+		}
+		return updated, nil
+
+	case "modelFieldRename":
+		var p struct {
+			ModelName    string `json:"modelName"`
+			OldFieldName string `json:"oldFieldName"`
+			NewFieldName string `json:"newFieldName"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		model, ok := m.models[p.ModelName]
+		if !ok {
+			return nil, fmt.Errorf("model was not found: %s", p.ModelName)
+		}
+		i := slices.Index(model.Fields, p.OldFieldName)
+		if i < 0 {
+			return nil, fmt.Errorf("field was not found in %s: %s", p.ModelName, p.OldFieldName)
+		}
+		model.Fields[i] = p.NewFieldName
+		for _, note := range m.notes {
+			if note.Model == p.ModelName {
+				note.Fields[p.NewFieldName] = note.Fields[p.OldFieldName]
+				delete(note.Fields, p.OldFieldName)
+			}
+		}
+		return nil, nil
+
+	case "modelFieldNames":
+		var p struct {
+			ModelName string `json:"modelName"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		model, ok := m.models[p.ModelName]
+		if !ok {
+			return nil, fmt.Errorf("model was not found: %s", p.ModelName)
+		}
+		return model.Fields, nil
+
+	case "multi":
+		var p struct {
+			Actions []struct {
+				Action string          `json:"action"`
+				Key    string          `json:"key"`
+				Params json.RawMessage `json:"params"`
+			} `json:"actions"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		results := make([]mockResponse, len(p.Actions))
+		for i, a := range p.Actions {
+			if m.apiKey != "" && a.Key != m.apiKey {
+				results[i].Error = mockAPIKeyError
+				continue
+			}
+			result, err := m.handle(a.Action, a.Params)
+			if err != nil {
+				results[i].Error = err.Error()
+			} else {
+				results[i].Result = result
+			}
+		}
+		return results, nil
+
+	case "addNote":
+		var p struct {
+			Note Note `json:"note"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		return m.addNote(p.Note)
+
+	case "canAddNotesWithErrorDetail":
+		var p struct {
+			Notes []Note `json:"notes"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		details := make([]map[string]interface{}, len(p.Notes))
+		for i, note := range p.Notes {
+			if _, _, err := m.checkNote(note); err != nil {
+				details[i] = map[string]interface{}{"canAdd": false, "error": err.Error()}
+			} else {
+				details[i] = map[string]interface{}{"canAdd": true}
+			}
+		}
+		return details, nil
+
+	case "updateNoteFields":
+		var p struct {
+			Note struct {
+				ID     int64             `json:"id"`
+				Fields map[string]string `json:"fields"`
+			} `json:"note"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		note, ok := m.notes[p.Note.ID]
+		if !ok {
+			return nil, fmt.Errorf("note was not found: %d", p.Note.ID)
+		}
+		for name, value := range p.Note.Fields {
+			if _, ok := note.Fields[name]; !ok {
+				return nil, fmt.Errorf("field %q does not exist", name)
+			}
+			note.Fields[name] = value
+		}
+		note.Mod = time.Now().Unix()
+		return nil, nil
+
+	case "updateNoteModel":
+		var p struct {
+			Note struct {
+				ID        int64             `json:"id"`
+				ModelName string            `json:"modelName"`
+				Fields    map[string]string `json:"fields"`
+				Tags      []string          `json:"tags"`
+			} `json:"note"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		note, ok := m.notes[p.Note.ID]
+		if !ok {
+			return nil, fmt.Errorf("note was not found: %d", p.Note.ID)
+		}
+		model, ok := m.models[p.Note.ModelName]
+		if !ok {
+			return nil, fmt.Errorf("model was not found: %s", p.Note.ModelName)
+		}
+		if len(p.Note.Fields) == 0 {
+			return nil, fmt.Errorf("must provide a 'fields' dictionary")
+		}
+		// Like AnkiConnect, field names match regardless of case and unknown
+		// fields are ignored
+		fields := make(map[string]string, len(model.Fields))
+		for _, field := range model.Fields {
+			fields[field] = ""
+			for name, value := range p.Note.Fields {
+				if strings.EqualFold(name, field) {
+					fields[field] = value
+				}
+			}
+		}
+		m.keepTags(note.Tags)
+		note.Model = p.Note.ModelName
+		note.Fields = fields
+		note.Tags = p.Note.Tags
+		note.Mod = time.Now().Unix()
+		return nil, nil
+
+	case "deleteNotes":
+		var p struct {
+			Notes []int64 `json:"notes"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		for _, id := range p.Notes {
+			if note, ok := m.notes[id]; ok {
+				m.keepTags(note.Tags)
+			}
+			delete(m.notes, id)
+		}
+		for id, card := range m.cards {
+			if _, ok := m.notes[card.NoteID]; !ok {
+				delete(m.cards, id)
+			}
+		}
+		return nil, nil
+
+	case "addTags", "removeTags":
+		var p struct {
+			Notes []int64 `json:"notes"`
+			Tags  string  `json:"tags"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		for _, id := range p.Notes {
+			note, ok := m.notes[id]
+			if !ok {
+				continue
+			}
+			for _, tag := range strings.Fields(p.Tags) {
+				has := slices.ContainsFunc(note.Tags, func(t string) bool { return strings.EqualFold(t, tag) })
+				if action == "addTags" && !has {
+					note.Tags = append(note.Tags, tag)
+				} else if action == "removeTags" && has {
+					m.keepTags([]string{tag})
+					note.Tags = slices.DeleteFunc(note.Tags, func(t string) bool { return strings.EqualFold(t, tag) })
+				}
+			}
+			note.Mod = time.Now().Unix()
+		}
+		return nil, nil
+
+	case "replaceTags":
+		var p struct {
+			Notes       []int64 `json:"notes"`
+			Tag         string  `json:"tag_to_replace"`
+			Replacement string  `json:"replace_with_tag"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		for _, id := range p.Notes {
+			note, ok := m.notes[id]
+			if !ok {
+				continue
+			}
+			for i, t := range note.Tags {
+				if strings.EqualFold(t, p.Tag) {
+					m.keepTags([]string{t})
+					note.Tags[i] = p.Replacement
+					note.Mod = time.Now().Unix()
+				}
+			}
+		}
+		return nil, nil
+
+	case "getTags":
+		tags := m.usedTags()
+		for tag := range m.staleTags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		sort.Strings(tags)
+		return tags, nil
+
+	case "clearUnusedTags":
+		m.staleTags = nil
+		return nil, nil
+
+	case "findNotes", "findCards":
+		var p struct {
+			Query string `json:"query"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		cards, err := m.search(p.Query)
+		if err != nil {
+			return nil, err
+		}
+		if action == "findCards" {
+			ids := make([]int64, len(cards))
+			for i, card := range cards {
+				ids[i] = card.ID
+			}
+			return ids, nil
+		}
+		var ids []int64
+		seen := make(map[int64]bool)
+		for _, card := range cards {
+			if !seen[card.NoteID] {
+				seen[card.NoteID] = true
+				ids = append(ids, card.NoteID)
+			}
+		}
+		if ids == nil {
+			ids = []int64{}
+		}
+		return ids, nil
+
+	case "notesInfo":
+		var p struct {
+			Notes []int64 `json:"notes"`
+			Query *string `json:"query"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		if p.Query != nil {
+			// Like findNotes followed by notesInfo
+			cards, err := m.search(*p.Query)
+			if err != nil {
+				return nil, err
+			}
+			seen := make(map[int64]bool)
+			for _, card := range cards {
+				if !seen[card.NoteID] {
+					seen[card.NoteID] = true
+					p.Notes = append(p.Notes, card.NoteID)
+				}
+			}
+		}
+		infos := make([]interface{}, len(p.Notes))
+		for i, id := range p.Notes {
+			if note, ok := m.notes[id]; ok {
+				infos[i] = m.noteInfo(note)
+			} else {
+				infos[i] = map[string]interface{}{}
+			}
+		}
+		return infos, nil
+
+	case "cardsInfo":
+		var p struct {
+			Cards []int64 `json:"cards"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		infos := make([]interface{}, len(p.Cards))
+		for i, id := range p.Cards {
+			if card, ok := m.cards[id]; ok {
+				infos[i] = m.cardInfo(card)
+			} else {
+				infos[i] = map[string]interface{}{}
+			}
+		}
+		return infos, nil
+
+	case "changeDeck":
+		var p struct {
+			Cards []int64 `json:"cards"`
+			Deck  string  `json:"deck"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		m.createDeck(p.Deck)
+		for _, id := range p.Cards {
+			if card, ok := m.cards[id]; ok {
+				card.Deck = p.Deck
+				card.Mod = time.Now().Unix()
+			}
+		}
+		return nil, nil
+
+	case "suspend", "unsuspend":
+		var p struct {
+			Cards []int64 `json:"cards"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		changed := false
+		for _, id := range p.Cards {
+			card, ok := m.cards[id]
+			if !ok {
+				continue
+			}
+			if action == "suspend" && card.Queue != -1 {
+				card.Queue = -1
+				changed = true
+			} else if action == "unsuspend" && card.Queue == -1 {
+				card.Queue = card.Type
+				changed = true
+			}
+		}
+		return changed, nil
+
+	case "forgetCards", "relearnCards":
+		var p struct {
+			Cards []int64 `json:"cards"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		for _, id := range p.Cards {
+			card, ok := m.cards[id]
+			if !ok {
+				continue
+			}
+			if action == "forgetCards" {
+				card.Type, card.Queue, card.Due = 0, 0, int64(len(m.cards)+1)
+				card.Interval, card.Factor, card.Reps, card.Lapses, card.Left = 0, 0, 0, 0, 0
+			} else {
+				card.Type, card.Queue, card.Due = 3, 1, time.Now().Unix()
+			}
+			card.Mod = time.Now().Unix()
+		}
+		return nil, nil
+
+	case "setDueDate":
+		var p struct {
+			Cards []int64 `json:"cards"`
+			Days  string  `json:"days"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		days, setInterval := strings.CutSuffix(p.Days, "!")
+		first, last, isRange := strings.Cut(days, "-")
+		low, err := strconv.ParseInt(first, 10, 64)
+		if err != nil {
+			return false, nil
+		}
+		high := low
+		if isRange {
+			if high, err = strconv.ParseInt(last, 10, 64); err != nil || high < low {
+				return false, nil
+			}
+		}
+		for _, id := range p.Cards {
+			card, ok := m.cards[id]
+			if !ok {
+				continue
+			}
+			n := low + rand.Int64N(high-low+1)
+			if setInterval || card.Type == 0 {
+				card.Interval = max(n, 1)
+			}
+			if card.Factor == 0 {
+				card.Factor = 2500
+			}
+			card.Type, card.Queue, card.Due, card.Left = 2, 2, mockToday+n, 0
+			card.Mod = time.Now().Unix()
+		}
+		return true, nil
+
+	case "storeMediaFile":
+		p := struct {
+			Filename       string `json:"filename"`
+			Data           string `json:"data"`
+			DeleteExisting bool   `json:"deleteExisting"`
+		}{DeleteExisting: true}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		data, err := base64.StdEncoding.DecodeString(p.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid media data: %v", err)
+		}
+		name := p.Filename
+		if existing, ok := m.media[name]; ok && !p.DeleteExisting && !bytes.Equal(existing, data) {
+			// Anki keeps both files and appends a content hash to the new one
+			ext := path.Ext(name)
+			name = fmt.Sprintf("%s-%x%s", strings.TrimSuffix(name, ext), sha1.Sum(data), ext)
+		}
+		m.media[name] = data
+		if m.mediaDir != "" {
+			if err := os.WriteFile(filepath.Join(m.mediaDir, name), data, 0o644); err != nil {
+				return nil, err
+			}
+		}
+		return name, nil
+
+	case "retrieveMediaFile":
+		var p struct {
+			Filename string `json:"filename"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		data, ok := m.media[p.Filename]
+		if !ok {
+			return false, nil
+		}
+		return base64.StdEncoding.EncodeToString(data), nil
+
+	case "getMediaFilesNames":
+		var p struct {
+			Pattern string `json:"pattern"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		names := []string{}
+		for name := range m.media {
+			if ok, err := path.Match(p.Pattern, name); err != nil {
+				return nil, err
+			} else if ok {
+				names = append(names, name)
+			}
+		}
+		return names, nil
+
+	case "deleteMediaFile":
+		var p struct {
+			Filename string `json:"filename"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		delete(m.media, p.Filename)
+		if m.mediaDir != "" {
+			_ = os.Remove(filepath.Join(m.mediaDir, p.Filename))
+		}
+		return nil, nil
+
+	case "getMediaDirPath":
+		if m.mediaDir != "" {
+			return m.mediaDir, nil
+		}
+		return "/nonexistent/anki-mock/collection.media", nil
+
+	case "cardReviews":
+		var p struct {
+			Deck    string `json:"deck"`
+			StartID int64  `json:"startID"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		rows := [][]int64{}
+		for _, e := range m.reviews {
+			card, ok := m.cards[e.CardID]
+			if !ok || card.Deck != p.Deck || e.ReviewTime <= p.StartID {
+				continue
+			}
+			rows = append(rows, []int64{e.ReviewTime, e.CardID, e.USN, int64(e.ButtonPressed),
+				e.NewInterval, e.PreviousInterval, e.NewFactor, e.ReviewDuration, int64(e.ReviewType)})
+		}
+		return rows, nil
+
+	case "getNumCardsReviewedByDay":
+		counts := make(map[string]int)
+		for _, e := range m.reviews {
+			counts[time.UnixMilli(e.ReviewTime).Format("2006-01-02")]++
+		}
+		days := make([]string, 0, len(counts))
+		for day := range counts {
+			days = append(days, day)
+		}
+		sort.Sort(sort.Reverse(sort.StringSlice(days)))
+		rows := make([][]interface{}, len(days))
+		for i, day := range days {
+			rows[i] = []interface{}{day, counts[day]}
+		}
+		return rows, nil
+
+	case "exportPackage":
+		var p struct {
+			Deck         string `json:"deck"`
+			Path         string `json:"path"`
+			IncludeSched bool   `json:"includeSched"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		if _, ok := m.decks[p.Deck]; !ok {
+			return false, nil
+		}
+		return m.exportPackage(p.Deck, p.Path, p.IncludeSched) == nil, nil
+
+	case "getCollectionStatsHTML":
+		return m.collectionStatsHTML(), nil
+
+	case "getReviewsOfCards":
+		var p struct {
+			Cards []int64 `json:"cards"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		result := make(map[string]interface{}, len(p.Cards))
+		for _, id := range p.Cards {
+			entries := []interface{}{}
+			for _, e := range m.reviews {
+				if e.CardID == id {
+					entries = append(entries, map[string]interface{}{
+						"id": e.ReviewTime, "usn": e.USN, "ease": e.ButtonPressed, "ivl": e.NewInterval,
+						"lastIvl": e.PreviousInterval, "factor": e.NewFactor, "time": e.ReviewDuration, "type": e.ReviewType,
+					})
+				}
+			}
+			result[strconv.FormatInt(id, 10)] = entries
+		}
+		return result, nil
+
+	case "guiDeckReview":
+		var p struct {
+			Name string `json:"name"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		if _, ok := m.decks[p.Name]; !ok {
+			return false, nil
+		}
+		m.guiDeck, m.guiCard, m.guiAnswerShown = p.Name, 0, false
+		return true, nil
+
+	case "guiUndo":
+		// The mock keeps no undo stack; it only reports that Anki undid
+		// something
+		return true, nil
+
+	case "guiBrowse":
+		var p struct {
+			Query string `json:"query"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		cards, err := m.search(p.Query)
+		if err != nil {
+			return nil, err
+		}
+		m.guiBrowseQuery = p.Query
+		ids := make([]int64, len(cards))
+		for i, card := range cards {
+			ids[i] = card.ID
+		}
+		return ids, nil
+
+	case "guiAddCards":
+		var p struct {
+			Note Note `json:"note"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		if _, ok := m.decks[p.Note.DeckName]; !ok {
+			return nil, fmt.Errorf("deck was not found: %s", p.Note.DeckName)
+		}
+		if _, ok := m.models[p.Note.ModelName]; !ok {
+			return nil, fmt.Errorf("model was not found: %s", p.Note.ModelName)
+		}
+		m.guiAddNote = &p.Note
+		return m.newID(), nil
+
+	case "guiCurrentCard":
+		card := m.guiCurrentCard()
+		if card == nil {
+			return nil, nil
+		}
+		info := m.cardInfo(card)
+		info["buttons"] = []int{1, 2, 3, 4}
+		info["nextReviews"] = []string{"<1m", "<6m", fmt.Sprintf("%dd", max(card.Interval, 1)), fmt.Sprintf("%dd", max(card.Interval*2, 4))}
+		return info, nil
+
+	case "guiShowAnswer":
+		if m.guiCurrentCard() == nil {
+			return false, nil
+		}
+		m.guiAnswerShown = true
+		return true, nil
+
+	case "guiAnswerCard":
+		var p struct {
+			Ease int `json:"ease"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		card := m.guiCurrentCard()
+		if card == nil || !m.guiAnswerShown || p.Ease < 1 || p.Ease > 4 {
+			return false, nil
+		}
+		m.answerCard(card, p.Ease)
+		m.guiCard, m.guiAnswerShown = 0, false
+		return true, nil
+
+	case "insertReviews":
+		var p struct {
+			Reviews [][]int64 `json:"reviews"`
+		}
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		existing := make(map[int64]bool, len(m.reviews))
+		for _, e := range m.reviews {
+			existing[e.ReviewTime] = true
+		}
+		for _, r := range p.Reviews {
+			if len(r) != 9 {
+				return nil, fmt.Errorf("review must have 9 values")
+			}
+			if existing[r[0]] {
+				return nil, fmt.Errorf("UNIQUE constraint failed: revlog.id")
+			}
+			existing[r[0]] = true
+			m.reviews = append(m.reviews, ReviewEntry{
+				ReviewTime: r[0], CardID: r[1], USN: r[2], ButtonPressed: int(r[3]), NewInterval: r[4],
+				PreviousInterval: r[5], NewFactor: r[6], ReviewDuration: r[7], ReviewType: int(r[8]),
+			})
+		}
+		sort.Slice(m.reviews, func(i, j int) bool {
+			return m.reviews[i].ReviewTime < m.reviews[j].ReviewTime
+		})
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("unsupported action")
+}
+
+// newID returns a new unique ID
+func (m *mockAnkiConnect) newID() int64 {
+	m.nextID++
+	return m.nextID
+}
+
+// createDeck creates a deck and its parents if they don't exist and returns its ID
+func (m *mockAnkiConnect) createDeck(name string) int64 {
+	parts := strings.Split(name, "::")
+	for i := 1; i < len(parts); i++ {
+		parent := strings.Join(parts[:i], "::")
+		if _, ok := m.decks[parent]; !ok {
+			m.decks[parent] = m.newID()
+		}
+	}
+	if id, ok := m.decks[name]; ok {
+		return id
+	}
+	m.decks[name] = m.newID()
+	return m.decks[name]
+}
+
+// deleteDecks deletes decks with their subdecks and cards
+func (m *mockAnkiConnect) deleteDecks(names []string) {
+	for _, name := range names {
+		for deck := range m.decks {
+			if deck == name || strings.HasPrefix(deck, name+"::") {
+				delete(m.decks, deck)
+				delete(m.deckConfigIDs, deck)
+			}
+		}
+		for id, card := range m.cards {
+			if card.Deck == name || strings.HasPrefix(card.Deck, name+"::") {
+				delete(m.cards, id)
+			}
+		}
+	}
+	m.removeOrphanNotes()
+}
+
+// removeOrphanNotes deletes notes that no longer have any cards
+func (m *mockAnkiConnect) removeOrphanNotes() {
+	used := make(map[int64]bool)
+	for _, card := range m.cards {
+		used[card.NoteID] = true
+	}
+	for id := range m.notes {
+		if !used[id] {
+			delete(m.notes, id)
+		}
+	}
+}
+
+var mockClozePattern = regexp.MustCompile(`\{\{c(\d+)::`)
+
+// mockConditionalFront matches a card template front that is shown only when
+// a field isn't empty, like the reverse card of "Basic (optional reversed card)"
+var mockConditionalFront = regexp.MustCompile(`(?s)^\{\{#([^}]+)\}\}.*\{\{/([^}]+)\}\}$`)
+
+// checkNote returns the model and complete fields of a note, or the error
+// AnkiConnect reports when the note can't be added
+func (m *mockAnkiConnect) checkNote(n Note) (*mockModel, map[string]string, error) {
+	if _, ok := m.decks[n.DeckName]; !ok {
+		return nil, nil, fmt.Errorf("deck was not found: %s", n.DeckName)
+	}
+	model, ok := m.models[n.ModelName]
+	if !ok {
+		return nil, nil, fmt.Errorf("model was not found: %s", n.ModelName)
+	}
+
+	fields := make(map[string]string, len(model.Fields))
+	for _, name := range model.Fields {
+		fields[name] = ""
+	}
+	for name, value := range n.Fields {
+		if _, ok := fields[name]; !ok {
+			continue
+		}
+		fields[name] = value
+	}
+	first := fields[model.Fields[0]]
+	if strings.TrimSpace(first) == "" {
+		return nil, nil, fmt.Errorf("cannot create note because it is empty")
+	}
+
+	allowDuplicate, _ := n.Options["allowDuplicate"].(bool)
+	if !allowDuplicate {
+		// With the deck scope only notes with a card in that deck count
+		inScope := func(noteID int64) bool { return true }
+		if scope, _ := n.Options["duplicateScope"].(string); scope == "deck" {
+			scopeOptions, _ := n.Options["duplicateScopeOptions"].(map[string]interface{})
+			deck := stringValue(scopeOptions, "deckName")
+			if deck == "" {
+				deck = n.DeckName
+			}
+			children, _ := scopeOptions["checkChildren"].(bool)
+			inScope = func(noteID int64) bool {
+				for _, card := range m.cards {
+					if card.NoteID == noteID && (card.Deck == deck || (children && strings.HasPrefix(card.Deck, deck+"::"))) {
+						return true
+					}
+				}
+				return false
+			}
+		}
+		for _, other := range m.notes {
+			if other.Model == n.ModelName && other.Fields[model.Fields[0]] == first && inScope(other.ID) {
+				return nil, nil, fmt.Errorf("cannot create note because it is a duplicate")
+			}
+		}
+	}
+
+	return model, fields, nil
+}
+
+// addNote adds a note and generates its cards like Anki would
+func (m *mockAnkiConnect) addNote(n Note) (int64, error) {
+	model, fields, err := m.checkNote(n)
+	if err != nil {
+		return 0, err
+	}
+	first := fields[model.Fields[0]]
+
+	var ords []int
+	if model.Cloze {
+		seen := make(map[int]bool)
+		for _, match := range mockClozePattern.FindAllStringSubmatch(first, -1) {
+			num, _ := strconv.Atoi(match[1])
+			if num > 0 && !seen[num] {
+				seen[num] = true
+				ords = append(ords, num-1)
+			}
+		}
+		if len(ords) == 0 {
+			return 0, fmt.Errorf("cannot create note because it has no cloze deletions")
+		}
+		sort.Ints(ords)
+	} else {
+		// Anki makes no card from a template whose front is empty
+		for i, template := range model.Templates {
+			if m := mockConditionalFront.FindStringSubmatch(template.Front); m != nil && m[1] == m[2] && strings.TrimSpace(fields[m[1]]) == "" {
+				continue
+			}
+			ords = append(ords, i)
+		}
+	}
+
+	note := &mockNote{
+		ID:     m.newID(),
+		Model:  n.ModelName,
+		Fields: fields,
+		Tags:   append([]string{}, n.Tags...),
+		Mod:    time.Now().Unix(),
+	}
+	m.notes[note.ID] = note
+
+	for _, ord := range ords {
+		id := m.newID()
+		m.cards[id] = &mockCard{
+			ID:     id,
+			NoteID: note.ID,
+			Deck:   n.DeckName,
+			Ord:    ord,
+			Due:    int64(len(m.cards) + 1),
+			Mod:    note.Mod,
+		}
+	}
+
+	return note.ID, nil
+}
+
+// noteCards returns the IDs of a note's cards in template order
+func (m *mockAnkiConnect) noteCards(noteID int64) []int64 {
+	var cards []*mockCard
+	for _, card := range m.cards {
+		if card.NoteID == noteID {
+			cards = append(cards, card)
+		}
+	}
+	sort.Slice(cards, func(i, j int) bool { return cards[i].Ord < cards[j].Ord })
+	ids := make([]int64, len(cards))
+	for i, card := range cards {
+		ids[i] = card.ID
+	}
+	return ids
+}
+
+// noteFields renders note fields in the AnkiConnect {name: {value, order}} format
+func (m *mockAnkiConnect) noteFields(note *mockNote) map[string]interface{} {
+	fields := make(map[string]interface{}, len(note.Fields))
+	for i, name := range m.models[note.Model].Fields {
+		fields[name] = map[string]interface{}{"value": note.Fields[name], "order": i}
+	}
+	return fields
+}
+
+// noteInfo renders a note in the AnkiConnect notesInfo format
+func (m *mockAnkiConnect) noteInfo(note *mockNote) map[string]interface{} {
+	tags := note.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return map[string]interface{}{
+		"noteId":    note.ID,
+		"modelName": note.Model,
+		"tags":      tags,
+		"fields":    m.noteFields(note),
+		"cards":     m.noteCards(note.ID),
+		"mod":       note.Mod,
+	}
+}
+
+// cardInfo renders a card in the AnkiConnect cardsInfo format
+func (m *mockAnkiConnect) cardInfo(card *mockCard) map[string]interface{} {
+	note := m.notes[card.NoteID]
+	model := m.models[note.Model]
+
+	question, answer := note.Fields[model.Fields[0]], note.Fields[model.Fields[1]]
+	if model.Cloze {
+		question = mockClozePattern.ReplaceAllString(question, "[...]{{")
+	} else if card.Ord == 1 {
+		question, answer = answer, question
+	}
+
+	return map[string]interface{}{
+		"cardId":     card.ID,
+		"note":       card.NoteID,
+		"deckName":   card.Deck,
+		"modelName":  note.Model,
+		"fieldOrder": card.Ord,
+		"ord":        card.Ord,
+		"fields":     m.noteFields(note),
+		"question":   question,
+		"answer":     question + "<hr id=answer>" + answer,
+		"css":        ".card { font-family: arial; }",
+		"factor":     card.Factor,
+		"interval":   card.Interval,
+		"type":       card.Type,
+		"queue":      card.Queue,
+		"due":        card.Due,
+		"reps":       card.Reps,
+		"lapses":     card.Lapses,
+		"left":       card.Left,
+		"mod":        card.Mod,
+	}
+}
+
+// guiCurrentCard returns the card shown in the review screen, picking the next
+// due card of the reviewed deck when needed
+func (m *mockAnkiConnect) guiCurrentCard() *mockCard {
+	if m.guiDeck == "" {
+		return nil
+	}
+	if card, ok := m.cards[m.guiCard]; ok {
+		return card
+	}
+	cards, err := m.search(fmt.Sprintf(`"deck:%s" is:due -is:suspended`, m.guiDeck))
+	if err != nil || len(cards) == 0 {
+		m.guiCard = 0
+		return nil
+	}
+	m.guiCard, m.guiAnswerShown = cards[0].ID, false
+	return cards[0]
+}
+
+// answerCard applies a simplified review to a card and logs it
+func (m *mockAnkiConnect) answerCard(card *mockCard, ease int) {
+	previous := card.Interval
+	switch {
+	case ease == 1:
+		card.Interval = 1
+		if card.Type == 2 {
+			card.Lapses++
+		}
+	case card.Interval == 0:
+		card.Interval = int64(ease - 1)
+	default:
+		card.Interval = card.Interval * int64(ease) / 2
+	}
+	card.Interval = max(card.Interval, 1)
+	if card.Factor == 0 {
+		card.Factor = 2500
+	}
+	card.Type, card.Queue = 2, 2
+	card.Due = mockToday + card.Interval
+	card.Reps++
+	card.Mod = time.Now().Unix()
+
+	reviewType := 1
+	if previous == 0 {
+		reviewType = 0
+	}
+	m.reviews = append(m.reviews, ReviewEntry{
+		ReviewTime: time.Now().UnixMilli(), CardID: card.ID, ButtonPressed: ease, NewInterval: card.Interval,
+		PreviousInterval: previous, NewFactor: card.Factor, ReviewDuration: 5000, ReviewType: reviewType,
+	})
+}
+
+// deckStats counts the cards of a deck and its subdecks the way Anki's deck
+// list does, capping today's new and review cards at the deck's limits
+func (m *mockAnkiConnect) deckStats(deck string) (map[string]interface{}, error) {
+	scope := fmt.Sprintf(`"deck:%s"`, deck)
+	count := func(query string) (int, error) {
+		cards, err := m.search(scope + " " + query)
+		return len(cards), err
+	}
+
+	limits := configLimits(copyMockConfig(m.deckConfigs[m.deckConfigID(deck)]))
+	newCount, err := count("is:new -is:suspended -is:buried")
+	if err != nil {
+		return nil, err
+	}
+	learnCount, err := count("is:learn is:due -is:suspended")
+	if err != nil {
+		return nil, err
+	}
+	reviewCount, err := count("is:review is:due -is:learn -is:suspended -is:buried")
+	if err != nil {
+		return nil, err
+	}
+	// Like AnkiConnect, the total leaves out the cards of subdecks
+	total := 0
+	for _, card := range m.cards {
+		if card.Deck == deck {
+			total++
+		}
+	}
+
+	return map[string]interface{}{
+		"name":          deck,
+		"new_count":     min(newCount, limits.NewCards),
+		"learn_count":   learnCount,
+		"review_count":  min(reviewCount, limits.Reviews),
+		"total_in_deck": total,
+	}, nil
+}
+
+// deckConfigID returns the ID of the options group used by a deck
+func (m *mockAnkiConnect) deckConfigID(deck string) int64 {
+	if id, ok := m.deckConfigIDs[deck]; ok {
+		return id
+	}
+	return 1
+}
+
+// copyMockConfig deep-copies an options group
+func copyMockConfig(config map[string]interface{}) map[string]interface{} {
+	data, _ := json.Marshal(config)
+	var clone map[string]interface{}
+	_ = json.Unmarshal(data, &clone)
+	return clone
+}
+
+// mockDeckConfig returns an options group with Anki's default settings,
+// holding the same JSON types AnkiConnect clients see
+func mockDeckConfig(id int64, name string) map[string]interface{} {
+	return copyMockConfig(map[string]interface{}{
+		"id":   id,
+		"name": name,
+		"new": map[string]interface{}{
+			"delays":        []float64{1, 10},
+			"ints":          []int{1, 4, 0},
+			"initialFactor": 2500,
+			"perDay":        20,
+		},
+		"lapse": map[string]interface{}{
+			"delays":     []float64{10},
+			"leechFails": 8,
+			"minInt":     1,
+			"mult":       0,
+		},
+		"rev": map[string]interface{}{
+			"perDay":     200,
+			"ease4":      1.3,
+			"hardFactor": 1.2,
+			"ivlFct":     1,
+			"maxIvl":     36500,
+		},
+	})
+}
+
+// search returns the cards matching an Anki search query. Only a subset of the
+// search syntax is supported: plain text, deck:, tag:, note:, nid:, cid:, is:,
+// prop:, added:, rated:, edited: and field:value terms, optionally negated with
+// a leading "-". All terms must match.
+func (m *mockAnkiConnect) search(query string) ([]*mockCard, error) {
+	terms := splitSearchTerms(query)
+
+	var matches []*mockCard
+	for _, card := range m.cards {
+		ok := true
+		for _, term := range terms {
+			negate := strings.HasPrefix(term, "-") && len(term) > 1
+			if negate {
+				term = term[1:]
+			}
+			matched, err := m.matchTerm(card, term)
+			if err != nil {
+				return nil, err
+			}
+			if matched == negate {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matches = append(matches, card)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+	return matches, nil
+}
+
+// splitSearchTerms splits a search query on whitespace, keeping quoted text
+// together. Parentheses are dropped, which is only correct for groups of terms
+// that are all required.
+func splitSearchTerms(query string) []string {
+	var terms []string
+	var current strings.Builder
+	inQuotes := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\\' && i+1 < len(query):
+			i++
+			current.WriteByte(query[i])
+		case c == '"':
+			inQuotes = !inQuotes
+		case (c == ' ' || c == '(' || c == ')') && !inQuotes:
+			if current.Len() > 0 {
+				terms = append(terms, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if current.Len() > 0 {
+		terms = append(terms, current.String())
+	}
+	return terms
+}
+
+// matchTerm reports whether a card matches a single search term
+func (m *mockAnkiConnect) matchTerm(card *mockCard, term string) (bool, error) {
+	note := m.notes[card.NoteID]
+	key, value, hasKey := strings.Cut(term, ":")
+	if !hasKey {
+		needle := strings.ToLower(strings.Trim(term, "*"))
+		for _, v := range note.Fields {
+			if strings.Contains(strings.ToLower(v), needle) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	now := time.Now()
+	withinDays := func(ts time.Time) (bool, error) {
+		days, err := strconv.Atoi(value)
+		if err != nil {
+			return false, fmt.Errorf("invalid search: %s", term)
+		}
+		return ts.After(now.AddDate(0, 0, -days)), nil
+	}
+
+	switch strings.ToLower(key) {
+	case "deck":
+		if value == "*" {
+			return true, nil
+		}
+		return globMatch(value, card.Deck) || globMatch(value+"::*", card.Deck), nil
+	case "tag":
+		for _, tag := range note.Tags {
+			if globMatch(value, tag) || globMatch(value+"::*", tag) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "note":
+		return globMatch(value, note.Model), nil
+	case "nid", "cid":
+		id := card.ID
+		if key == "nid" {
+			id = note.ID
+		}
+		for _, s := range strings.Split(value, ",") {
+			if n, err := strconv.ParseInt(s, 10, 64); err == nil && n == id {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "is":
+		switch value {
+		case "new":
+			return card.Type == 0, nil
+		case "learn":
+			return card.Queue == 1 || card.Queue == 3, nil
+		case "review":
+			return card.Type == 2 || card.Type == 3, nil
+		case "suspended":
+			return card.Queue == -1, nil
+		case "buried":
+			return card.Queue == -2 || card.Queue == -3, nil
+		case "due":
+			return ((card.Queue == 2 || card.Queue == 3) && card.Due <= mockToday) ||
+				(card.Queue == 1 && card.Due <= now.Unix()), nil
+		}
+		return false, fmt.Errorf("invalid search: %s", term)
+	case "prop":
+		return m.matchProp(card, value)
+	case "added":
+		return withinDays(time.UnixMilli(note.ID))
+	case "edited":
+		return withinDays(time.Unix(note.Mod, 0))
+	case "rated":
+		for _, e := range m.reviews {
+			if e.CardID == card.ID {
+				if ok, err := withinDays(time.UnixMilli(e.ReviewTime)); ok || err != nil {
+					return ok, err
+				}
+			}
+		}
+		return false, nil
+	}
+
+	// field:value search
+	for name, v := range note.Fields {
+		if strings.EqualFold(name, key) {
+			return globMatch(value, v), nil
+		}
+	}
+	return false, nil
+}
+
+var mockPropPattern = regexp.MustCompile(`^(\w+)(<=|>=|!=|<|>|=)(-?[\d.]+)$`)
+
+// matchProp evaluates a prop: search such as prop:ivl>=21 or prop:due<=0
+func (m *mockAnkiConnect) matchProp(card *mockCard, expr string) (bool, error) {
+	match := mockPropPattern.FindStringSubmatch(expr)
+	if match == nil {
+		return false, fmt.Errorf("invalid search: prop:%s", expr)
+	}
+	target, err := strconv.ParseFloat(match[3], 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid search: prop:%s", expr)
+	}
+
+	var actual float64
+	switch match[1] {
+	case "ivl":
+		actual = float64(card.Interval)
+	case "due":
+		if card.Queue != 2 && card.Queue != 3 && !(card.Queue < 0 && card.Type == 2) {
+			return false, nil
+		}
+		actual = float64(card.Due - mockToday)
+	case "reps":
+		actual = float64(card.Reps)
+	case "lapses":
+		actual = float64(card.Lapses)
+	case "ease":
+		actual = float64(card.Factor) / 1000
+	default:
+		return false, fmt.Errorf("invalid search: prop:%s", expr)
+	}
+
+	switch match[2] {
+	case "<=":
+		return actual <= target, nil
+	case ">=":
+		return actual >= target, nil
+	case "!=":
+		return actual != target, nil
+	case "<":
+		return actual < target, nil
+	case ">":
+		return actual > target, nil
+	}
+	return actual == target, nil
+}
+
+// collectionStatsHTML renders the parts of Anki's statistics report that
+// get_collection_stats reads, in the markup Anki uses for them
+func (m *mockAnkiConnect) collectionStatsHTML() string {
+	var b strings.Builder
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local).UnixMilli()
+
+	b.WriteString("<center><h1>Today</h1>")
+	cards, millis, matureTotal, matureCorrect := 0, int64(0), 0, 0
+	for _, e := range m.reviews {
+		if e.ReviewTime < today {
+			continue
+		}
+		cards++
+		millis += e.ReviewDuration
+		if e.PreviousInterval >= 21 {
+			matureTotal++
+			if e.ButtonPressed > 1 {
+				matureCorrect++
+			}
+		}
+	}
+	if cards == 0 {
+		b.WriteString("No cards have been studied today.")
+	} else {
+		fmt.Fprintf(&b, "Studied <b>%d cards</b> in <b>%.2f minutes</b> today (%.2fs/card)",
+			cards, float64(millis)/60000, float64(millis)/1000/float64(cards))
+		if matureTotal > 0 {
+			fmt.Fprintf(&b, "<br>Correct answers on mature cards: %d/%d (%.1f%%)",
+				matureCorrect, matureTotal, float64(matureCorrect)*100/float64(matureTotal))
+		}
+	}
+	b.WriteString("</center>")
+
+	// Answer buttons: correct answers by learning, young and mature cards
+	b.WriteString("<h1>Answer Buttons</h1><table><tr>")
+	var good, total [3]int
+	for _, e := range m.reviews {
+		kind := 0
+		switch {
+		case e.ReviewType == 0 || e.ReviewType == 2:
+		case e.PreviousInterval < 21:
+			kind = 1
+		default:
+			kind = 2
+		}
+		total[kind]++
+		if e.ButtonPressed > 1 {
+			good[kind]++
+		}
+	}
+	for kind := range total {
+		pct := 0.0
+		if total[kind] > 0 {
+			pct = float64(good[kind]) * 100 / float64(total[kind])
+		}
+		fmt.Fprintf(&b, "<td>Correct: <b>%.2f%%</b><br>(%d of %d)</td>", pct, good[kind], total[kind])
+	}
+	b.WriteString("</tr></table>")
+
+	// Card counts and ease
+	mature, young, unseen, suspended := 0, 0, 0, 0
+	factorSum, factorCount, ivlSum := int64(0), int64(0), int64(0)
+	for _, card := range m.cards {
+		switch {
+		case card.Queue < 0:
+			suspended++
+		case card.Type == 0:
+			unseen++
+		case card.Interval >= 21:
+			mature++
+		default:
+			young++
+		}
+		if card.Type == 2 {
+			factorSum += card.Factor
+			ivlSum += card.Interval
+			factorCount++
+		}
+	}
+	b.WriteString("<h1>Card Counts</h1><script>var data = [")
+	fmt.Fprintf(&b, `{"data": [[0, %d]], "label": "Mature: %d"}, {"data": [[0, %d]], "label": "Young+Learn: %d"}, `, mature, mature, young, young)
+	fmt.Fprintf(&b, `{"data": [[0, %d]], "label": "Unseen: %d"}, {"data": [[0, %d]], "label": "Suspended+Buried: %d"}`, unseen, unseen, suspended, suspended)
+	b.WriteString("];</script><table width=100%>")
+	row := func(label, value string) {
+		fmt.Fprintf(&b, "<tr><td width=200 align=end style='padding-right: 3px;'>%s:</td><td align=start><b>%s</b></td></tr>", label, value)
+	}
+	row("Total cards", strconv.Itoa(len(m.cards)))
+	row("Total notes", strconv.Itoa(len(m.notes)))
+	if factorCount > 0 {
+		row("Average ease", fmt.Sprintf("%d%%", factorSum/factorCount/10))
+		row("Average interval", fmt.Sprintf("%.1f days", float64(ivlSum)/float64(factorCount)))
+	}
+	b.WriteString("</table>")
+	return b.String()
+}
+
+// exportPackage writes an .apkg stand-in: a zip archive holding the deck's
+// notes as JSON and an empty media map
+func (m *mockAnkiConnect) exportPackage(deck, path string, includeSched bool) error {
+	cards, err := m.search(deckQuery(deck))
+	if err != nil {
+		return err
+	}
+	var notes []*mockNote
+	for _, card := range cards {
+		if note := m.notes[card.NoteID]; !slices.Contains(notes, note) {
+			notes = append(notes, note)
+		}
+	}
+	collection, err := json.Marshal(map[string]interface{}{
+		"deck":         deck,
+		"notes":        notes,
+		"includeSched": includeSched,
+	})
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range map[string][]byte{"collection.anki21": collection, "media": []byte("{}")} {
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// keepTags records tags that are about to be removed from a note
+func (m *mockAnkiConnect) keepTags(tags []string) {
+	if m.staleTags == nil {
+		m.staleTags = make(map[string]bool)
+	}
+	for _, tag := range tags {
+		m.staleTags[tag] = true
+	}
+}
+
+// usedTags returns the tags of all notes
+func (m *mockAnkiConnect) usedTags() []string {
+	var tags []string
+	for _, note := range m.notes {
+		for _, tag := range note.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+// seedDemo fills the mock collection with a small Spanish vocabulary deck, some
+// of it already studied, so every tool has something to show in demos
+func (m *mockAnkiConnect) seedDemo() {
+	m.createDeck("Spanish::Vocabulary")
+	m.createDeck("Spanish::Grammar")
+
+	cram := mockDeckConfig(2, "Exam cram")
+	cram["new"].(map[string]interface{})["perDay"] = float64(50)
+	cram["rev"].(map[string]interface{})["perDay"] = float64(500)
+	m.deckConfigs[2] = cram
+	m.deckConfigIDs["Spanish::Grammar"] = 2
+
+	words := [][2]string{
+		{"el perro", "the dog"}, {"el gato", "the cat"}, {"la casa", "the house"},
+		{"el libro", "the book"}, {"la manzana", "the apple"}, {"el agua", "the water"},
+	}
+	for _, w := range words {
+		_, _ = m.addNote(Note{
+			DeckName:  "Spanish::Vocabulary",
+			ModelName: "Basic (and reversed card)",
+			Fields:    map[string]string{"Front": w[0], "Back": w[1]},
+			Tags:      []string{"spanish", "vocabulary"},
+		})
+	}
+	_, _ = m.addNote(Note{
+		DeckName:  "Spanish::Grammar",
+		ModelName: "Cloze",
+		Fields:    map[string]string{"Text": "Yo {{c1::soy}} estudiante y {{c2::estoy}} cansado."},
+		Tags:      []string{"spanish", "grammar", "ser-estar"},
+	})
+
+	// Give the first half of the cards a review history
+	ids := make([]int64, 0, len(m.cards))
+	for id := range m.cards {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	now := time.Now()
+	for i, id := range ids[:len(ids)/2] {
+		card := m.cards[id]
+		ivl := int64(0)
+		for day := 1; day <= 3; day++ {
+			lastIvl := ivl
+			ivl = ivl*2 + int64(i%3) + 1
+			m.reviews = append(m.reviews, ReviewEntry{
+				ReviewTime:       now.AddDate(0, 0, -30+day*7).UnixMilli() + id%1000,
+				CardID:           id,
+				USN:              -1,
+				ButtonPressed:    3,
+				NewInterval:      ivl,
+				PreviousInterval: lastIvl,
+				NewFactor:        2500,
+				ReviewDuration:   int64(4000 + 1000*(i%5)),
+				ReviewType:       1,
+			})
+		}
+		card.Type, card.Queue = 2, 2
+		card.Interval, card.Factor = ivl, 2500
+		card.Reps = 3
+		card.Due = mockToday + int64(i%4) - 1
+	}
+	sort.Slice(m.reviews, func(i, j int) bool {
+		return m.reviews[i].ReviewTime < m.reviews[j].ReviewTime
+	})
+}
@@ -0,0 +1,390 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// newMockServer creates a server backed by an empty mock collection
+func newMockServer(t *testing.T) (*AnkiMCPServer, *mockAnkiConnect) {
+	t.Helper()
+	mock := newMockAnkiConnect()
+	server := NewAnkiMCPServerWithConfig(Config{Language: defaultLanguage, OutputStyle: outputMarkdown, RolloverHour: defaultRolloverHour, StateDir: t.TempDir()})
+	server.ankiClient = mockClient(t, mock)
+	return server, mock
+}
+
+// mockClient returns a client talking to the mock, which is served until the
+// test ends
+func mockClient(t testing.TB, mock *mockAnkiConnect) *AnkiConnect {
+	t.Helper()
+	client, err := mock.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = mock.Close() })
+	return client
+}
+
+// callTool calls a handler with the given arguments and returns its text output
+func callTool(t *testing.T, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), args map[string]interface{}) (string, bool) {
+	t.Helper()
+	var request mcp.CallToolRequest
+	request.Params.Arguments = args
+	result, err := handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	var text strings.Builder
+	for _, content := range result.Content {
+		if tc, ok := content.(mcp.TextContent); ok {
+			text.WriteString(tc.Text)
+		}
+	}
+	return text.String(), result.IsError
+}
+
+func TestMockCreateDeckAndCard(t *testing.T) {
+	server, mock := newMockServer(t)
+
+	if text, isErr := callTool(t, server.handleCreateDeck, map[string]interface{}{"name": "Spanish"}); isErr {
+		t.Fatalf("create_deck failed: %s", text)
+	}
+	args := map[string]interface{}{"deck": "Spanish", "front": "hola", "back": "hello", "tags": []interface{}{"greeting"}}
+	if text, isErr := callTool(t, server.handleCreateCard, args); isErr {
+		t.Fatalf("create_card failed: %s", text)
+	}
+	if text, isErr := callTool(t, server.handleCreateCard, args); !isErr {
+		t.Errorf("Expected duplicate card to fail, got: %s", text)
+	}
+
+	text, isErr := callTool(t, server.handleGetDeckTree, map[string]interface{}{})
+	if isErr || !strings.Contains(text, "Spanish") {
+		t.Errorf("get_deck_tree did not list the new deck: %s", text)
+	}
+
+	ids, err := server.ankiClient.FindNotes(`deck:Spanish tag:greeting`)
+	if err != nil || len(ids) != 1 {
+		t.Fatalf("Expected one note, got %v (%v)", ids, err)
+	}
+	if len(mock.cards) != 1 {
+		t.Errorf("Expected one card, got %d", len(mock.cards))
+	}
+}
+
+func TestCreateReversedCard(t *testing.T) {
+	server, mock := newMockServer(t)
+	delete(mock.models, reversedModel)
+
+	args := map[string]interface{}{"deck": "Default", "front": "perro", "back": "dog", "reversed": true}
+	text, isErr := callTool(t, server.handleCreateCard, args)
+	if isErr || !strings.Contains(text, "Created the missing note type") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	if len(mock.cards) != 2 {
+		t.Errorf("Expected a card in each direction, got %d card(s)", len(mock.cards))
+	}
+
+	args["front"], args["back"] = "gato", "cat"
+	text, _ = callTool(t, server.handleCreateCard, args)
+	if strings.Contains(text, "Created the missing note type") {
+		t.Errorf("The note type should only be created once: %s", text)
+	}
+}
+
+func TestCreateCardWithFields(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.models["Vocabulary"] = &mockModel{Fields: []string{"Word", "Meaning", "Example"}, Templates: []CardTemplate{{Name: "Card 1", Front: "{{Word}}", Back: "{{Meaning}}"}}}
+
+	args := map[string]interface{}{"deck": "Default", "model_name": "Vocabulary", "fields": map[string]interface{}{"Word": "perro", "Meaning": "dog"}}
+	if text, isErr := callTool(t, server.handleCreateCard, args); isErr {
+		t.Fatalf("create_card failed: %s", text)
+	}
+	for _, note := range mock.notes {
+		if note.Model != "Vocabulary" || note.Fields["Meaning"] != "dog" {
+			t.Errorf("Unexpected note: %+v", note)
+		}
+	}
+
+	args["fields"] = map[string]interface{}{"Word": "gato", "Front": "cat"}
+	if text, isErr := callTool(t, server.handleCreateCard, args); !isErr || !strings.Contains(text, "Unknown field(s) for note type Vocabulary: Front") {
+		t.Errorf("Expected an unknown field error, got: %s", text)
+	}
+	args["fields"] = map[string]interface{}{"Meaning": "cat"}
+	if text, isErr := callTool(t, server.handleCreateCard, args); !isErr || !strings.Contains(text, "first field") {
+		t.Errorf("Expected an empty first field error, got: %s", text)
+	}
+}
+
+func TestCreateCardDuplicateOptions(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+	mock.createDeck("French")
+
+	args := map[string]interface{}{"deck": "French", "front": "el perro", "back": "le chien", "reversed": true}
+	if text, isErr := callTool(t, server.handleCreateCard, args); !isErr || !strings.Contains(text, "duplicate") {
+		t.Errorf("Expected a duplicate error, got: %s", text)
+	}
+
+	// Only notes in French count as duplicates with the deck scope
+	args["duplicate_scope"] = "deck"
+	if text, isErr := callTool(t, server.handleCreateCard, args); isErr {
+		t.Errorf("Expected the card to be created, got: %s", text)
+	}
+	delete(args, "duplicate_scope")
+	args["duplicate_scope_deck"] = "Spanish"
+	args["front"] = "el gato"
+	if text, isErr := callTool(t, server.handleCreateCard, args); !isErr || !strings.Contains(text, "duplicate") {
+		t.Errorf("Expected a duplicate error in Spanish and its subdecks, got: %s", text)
+	}
+
+	args["allow_duplicate"] = true
+	if text, isErr := callTool(t, server.handleCreateCard, args); isErr {
+		t.Errorf("Expected the duplicate to be allowed, got: %s", text)
+	}
+
+	args["duplicate_scope"] = "collection"
+	if text, isErr := callTool(t, server.handleCreateCard, args); !isErr || !strings.Contains(text, "duplicate_scope_deck needs duplicate_scope deck") {
+		t.Errorf("Expected an option error, got: %s", text)
+	}
+}
+
+func TestCreateCardsBulk(t *testing.T) {
+	server, mock := newMockServer(t)
+
+	cards := []interface{}{
+		map[string]interface{}{"front": "uno", "back": "one"},
+		map[string]interface{}{"front": "dos", "back": "two", "tags": []interface{}{"numbers"}},
+		map[string]interface{}{"front": "uno", "back": "one"},
+		map[string]interface{}{"front": "tres"},
+	}
+	text, isErr := callTool(t, server.handleCreateCardsBulk, map[string]interface{}{"deck": "Default", "cards": cards, "tags": []interface{}{"spanish"}})
+	if isErr || !strings.Contains(text, "Created 2 of 4 card(s)") || !strings.Contains(text, "#3 failed") || !strings.Contains(text, "#4 failed") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	if len(mock.notes) != 2 {
+		t.Errorf("Expected 2 notes, got %d", len(mock.notes))
+	}
+	ids, _ := server.ankiClient.FindNotes("tag:spanish tag:numbers")
+	if len(ids) != 1 {
+		t.Errorf("Expected card and common tags to be merged, got %v", ids)
+	}
+}
+
+func TestMockSearch(t *testing.T) {
+	mock := newMockAnkiConnect()
+	mock.seedDemo()
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"deck:Spanish", 14},
+		{`"deck:Spanish::Vocabulary"`, 12},
+		{"deck:Spanish -deck:Spanish::Grammar", 12},
+		{"tag:grammar", 2},
+		{"is:new", 7},
+		{"is:review prop:ivl>=1", 7},
+		{"perro", 2},
+		{"front:el*", 8},
+		{"note:Cloze", 2},
+	}
+	for _, tt := range tests {
+		cards, err := mock.search(tt.query)
+		if err != nil {
+			t.Errorf("search(%q) returned error: %v", tt.query, err)
+			continue
+		}
+		if len(cards) != tt.want {
+			t.Errorf("search(%q) = %d cards, want %d", tt.query, len(cards), tt.want)
+		}
+	}
+}
+
+func TestMockReviewTools(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	text, isErr := callTool(t, server.handleProgressReport, map[string]interface{}{"deck": "Spanish", "format": "json"})
+	if isErr || !strings.Contains(text, `"reviews":21`) {
+		t.Errorf("Unexpected progress_report output: %s", text)
+	}
+
+	text, isErr = callTool(t, server.handleTagStats, map[string]interface{}{"deck": "Spanish"})
+	if isErr || !strings.Contains(text, "vocabulary") {
+		t.Errorf("Unexpected tag_stats output: %s", text)
+	}
+}
+
+func TestMockCreateCardWithMedia(t *testing.T) {
+	server, mock := newMockServer(t)
+
+	image := filepath.Join(t.TempDir(), "cat.png")
+	data := bytes.Repeat([]byte{0x89, 'P', 'N', 'G', 0}, 1000)
+	if err := os.WriteFile(image, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	args := map[string]interface{}{"deck": "Default", "front": "gato", "back": "cat", "image_path": image}
+	if text, isErr := callTool(t, server.handleCreateCard, args); isErr {
+		t.Fatalf("create_card failed: %s", text)
+	}
+	if !bytes.Equal(mock.media["cat.png"], data) {
+		t.Errorf("Stored media does not match the file (%d bytes stored)", len(mock.media["cat.png"]))
+	}
+
+	// A different image with the same name is stored under a new name
+	if err := os.WriteFile(image, []byte("another cat"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	args["front"] = "otro gato"
+	text, isErr := callTool(t, server.handleCreateCard, args)
+	if isErr || !strings.Contains(text, "renamed from cat.png") {
+		t.Errorf("Expected renamed media in result, got: %s", text)
+	}
+
+	// An image is enough for the front
+	args["front"] = ""
+	text, isErr = callTool(t, server.handleCreateCard, args)
+	if isErr || !strings.Contains(text, "Created card") {
+		t.Errorf("Expected a card with only an image on the front, got: %s", text)
+	}
+	delete(args, "image_path")
+	if text, isErr := callTool(t, server.handleCreateCard, args); !isErr || !strings.Contains(text, "must not be empty") {
+		t.Errorf("Expected an error for an empty front, got: %s", text)
+	}
+
+	args["front"] = "gato perdido"
+	missing := filepath.Join(t.TempDir(), "missing.png")
+	args["image_path"] = missing
+	if text, isErr := callTool(t, server.handleCreateCard, args); !isErr || !strings.Contains(text, "Failed to store "+missing) {
+		t.Errorf("Expected read error, got: %s", text)
+	}
+}
+
+func TestStoreMediaFileFromReadError(t *testing.T) {
+	client := mockClient(t, newMockAnkiConnect())
+	_, err := client.StoreMediaFileFrom("broken.mp3", iotest.ErrReader(errors.New("disk gone")))
+	if err == nil || !strings.Contains(err.Error(), "disk gone") {
+		t.Errorf("Expected read error, got %v", err)
+	}
+}
+
+func TestStoreAndVerifyMedia(t *testing.T) {
+	for _, local := range []bool{false, true} {
+		mock := newMockAnkiConnect()
+		if local {
+			mock.mediaDir = t.TempDir()
+		}
+		client := mockClient(t, mock)
+
+		first, err := client.StoreMediaFile("hola.mp3", []byte("first recording"))
+		if err != nil || first.Filename != "hola.mp3" || first.Size != 15 {
+			t.Fatalf("local=%v: unexpected result %+v (%v)", local, first, err)
+		}
+		second, err := client.StoreMediaFile("hola.mp3", []byte("second recording"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if second.Filename == "hola.mp3" || !strings.HasSuffix(second.Filename, ".mp3") {
+			t.Errorf("local=%v: expected a renamed file, got %s", local, second.Filename)
+		}
+		if string(mock.media["hola.mp3"]) != "first recording" {
+			t.Errorf("local=%v: existing file was overwritten", local)
+		}
+
+		for _, media := range []StoredMedia{first, second} {
+			if err := client.VerifyMedia(media); err != nil {
+				t.Errorf("local=%v: verification failed: %v", local, err)
+			}
+		}
+		first.SHA256 = second.SHA256
+		if err := client.VerifyMedia(first); err == nil {
+			t.Errorf("local=%v: expected verification to detect different content", local)
+		}
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+	mock.apiKey = "secret"
+
+	if err := server.ankiClient.Ping(); !errors.Is(err, errAPIKey) {
+		t.Errorf("Expected errAPIKey without a key, got %v", err)
+	}
+	if text, isErr := callTool(t, server.handleGetDeckTree, map[string]interface{}{}); !isErr || !strings.Contains(text, "ANKI_CONNECT_API_KEY") {
+		t.Errorf("Expected a hint about ANKI_CONNECT_API_KEY, got: %s", text)
+	}
+
+	server.ankiClient.Key = "secret"
+	if err := server.ankiClient.Ping(); err != nil {
+		t.Errorf("Ping() with key = %v", err)
+	}
+	// Actions inside a multi request carry the key too
+	if configs, err := server.ankiClient.GetDeckConfigs([]string{"Spanish::Grammar"}); err != nil || len(configs) != 1 {
+		t.Errorf("GetDeckConfigs() = %v, %v", configs, err)
+	}
+	if _, err := server.ankiClient.StoreMediaFileFrom("key.txt", strings.NewReader("data")); err != nil {
+		t.Errorf("StoreMediaFileFrom() = %v", err)
+	}
+
+	multi := mockClient(t, mock)
+	multi.Key = "secret"
+	actions := []ankiRequest{{Action: "version", Version: ankiConnectVersion, Key: "wrong"}}
+	if _, err := multi.multi(actions); !errors.Is(err, errAPIKey) {
+		t.Errorf("Expected errAPIKey for a multi action with a wrong key, got %v", err)
+	}
+}
+
+func TestTypedResponses(t *testing.T) {
+	mock := newMockAnkiConnect()
+	mock.seedDemo()
+	client := mockClient(t, mock)
+
+	stats, err := client.GetDeckStats([]string{"Spanish", "Spanish::Grammar", "Missing"})
+	if err != nil {
+		t.Fatalf("GetDeckStats: %v", err)
+	}
+	if len(stats) != 2 {
+		t.Errorf("Expected stats of 2 decks, got %v", stats)
+	}
+	if s := stats["Spanish"]; s.DeckID != mock.decks["Spanish"] || s.TotalInDeck != 0 || s.NewCount != 7 || s.ReviewCount != 4 {
+		t.Errorf("Unexpected Spanish stats: %+v", s)
+	}
+	// The total leaves out subdecks, the due counts don't
+	if s := stats["Spanish::Grammar"]; s.TotalInDeck != 2 {
+		t.Errorf("Unexpected Spanish::Grammar stats: %+v", s)
+	}
+
+	cardIDs, _ := client.FindCards(`"deck:Spanish::Vocabulary" is:review`)
+	cards, err := client.GetCardsInfo(cardIDs[:1])
+	if err != nil {
+		t.Fatalf("GetCardsInfo: %v", err)
+	}
+	card := cards[0]
+	if card.CardID != cardIDs[0] || card.Type != 2 || card.Factor != 2500 || card.Fields["Front"].Value == "" {
+		t.Errorf("Unexpected card info: %+v", card)
+	}
+	notes, err := client.GetNotesInfo([]int64{card.NoteID, 1})
+	if err != nil {
+		t.Fatalf("GetNotesInfo: %v", err)
+	}
+	if notes[0].NoteID != card.NoteID || !slices.Contains(notes[0].Cards, card.CardID) || notes[1].NoteID != 0 {
+		t.Errorf("Unexpected note info: %+v", notes)
+	}
+
+	// A result of an unexpected shape is an error, not a zero value
+	if _, err := invoke[[]int64](client, "deckNames", nil); err == nil || !strings.Contains(err.Error(), "unexpected deckNames response") {
+		t.Errorf("Expected a decoding error, got %v", err)
+	}
+}
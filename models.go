@@ -0,0 +1,829 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxListedNotes limits how many note IDs are listed in tool output
+const maxListedNotes = 20
+
+// registerModelTools registers note type (model) tools with the MCP server
+func (a *AnkiMCPServer) registerModelTools(s *server.MCPServer) {
+	// Tool: Rename Model Field
+	renameFieldTool := mcp.NewTool("rename_model_field",
+		mcp.WithDescription("Rename a field of a note type and check that every note using it kept its content under the new name. "+
+			"WARNING: changing a note type's fields is a schema change, so the next sync to AnkiWeb must be a full upload. "+
+			"Only run it when the user has asked for the rename, and pass confirm=true to acknowledge this."),
+		mcp.WithString("model",
+			mcp.Required(),
+			mcp.Description("Name of the note type"),
+		),
+		mcp.WithString("old_name",
+			mcp.Required(),
+			mcp.Description("Current name of the field"),
+		),
+		mcp.WithString("new_name",
+			mcp.Required(),
+			mcp.Description("New name of the field"),
+		),
+		mcp.WithBoolean("confirm",
+			mcp.Required(),
+			mcp.Description("Must be true to rename the field, acknowledging that the next sync will be a full upload"),
+		),
+	)
+	a.addChangingTool(s, renameFieldTool, (*AnkiMCPServer).handleRenameModelField)
+
+	// Tool: Change Note Type
+	changeNoteTypeTool := mcp.NewTool("change_note_type",
+		mcp.WithDescription("Switch notes to another note type, e.g. from Basic to Basic (and reversed card), carrying their content over by a field mapping. "+
+			"All selected notes must use the same note type. Both note types are checked first: every mapped field must exist, and the new note type "+
+			"must be of the same kind (cloze or standard) with at least as many card types, so every card keeps its review history. "+
+			"Fields of the new note type that nothing maps to are left empty. Use dry_run to preview the new field contents."),
+		mcp.WithArray("note_ids",
+			mcp.Description("IDs of the notes to change"),
+			mcp.WithNumberItems(),
+		),
+		mcp.WithString("query",
+			mcp.Description("Anki search query selecting the notes, instead of note_ids"),
+		),
+		mcp.WithString("new_model",
+			mcp.Required(),
+			mcp.Description("Name of the note type to switch to"),
+		),
+		mcp.WithObject("field_map",
+			mcp.Description("Optional: New field by old field, e.g. {\"Front\": \"Text\", \"Back\": \"Back Extra\"} (default: fields with the same name)"),
+		),
+		mcp.WithBoolean("discard_unmapped",
+			mcp.Description("Optional: Allow the content of old fields that field_map leaves out to be lost (default: false)"),
+		),
+	)
+	a.addChangingTool(s, changeNoteTypeTool, (*AnkiMCPServer).handleChangeNoteType)
+
+	// Tool: Get Model Templates
+	getTemplatesTool := mcp.NewTool("get_model_templates",
+		mcp.WithDescription("Get the HTML card templates (front and back) of a note type"),
+		mcp.WithString("model",
+			mcp.Required(),
+			mcp.Description("Name of the note type"),
+		),
+		withFormat(),
+	)
+	a.addTool(s, getTemplatesTool, (*AnkiMCPServer).handleGetModelTemplates)
+
+	// Tool: Update Model Templates
+	updateTemplatesTool := mcp.NewTool("update_model_templates",
+		mcp.WithDescription("Change the HTML card templates of a note type, e.g. to show a hint field. Read them with get_model_templates first. "+
+			"Sides that are not given keep their template. Field references such as {{Hint}} or {{hint:Hint}} are checked against the note type's fields."),
+		mcp.WithString("model",
+			mcp.Required(),
+			mcp.Description("Name of the note type"),
+		),
+		mcp.WithObject("templates",
+			mcp.Required(),
+			mcp.Description("New templates by template name, each with Front and/or Back, e.g. {\"Card 1\": {\"Front\": \"{{Front}}{{hint:Hint}}\"}}"),
+		),
+	)
+	a.addChangingTool(s, updateTemplatesTool, (*AnkiMCPServer).handleUpdateModelTemplates)
+
+	// Tool: Get Model Styling
+	getStylingTool := mcp.NewTool("get_model_styling",
+		mcp.WithDescription("Get the CSS styling shared by the card templates of a note type"),
+		mcp.WithString("model",
+			mcp.Required(),
+			mcp.Description("Name of the note type"),
+		),
+		withFormat(),
+	)
+	a.addTool(s, getStylingTool, (*AnkiMCPServer).handleGetModelStyling)
+
+	// Tool: Update Model Styling
+	updateStylingTool := mcp.NewTool("update_model_styling",
+		mcp.WithDescription("Change the CSS styling of a note type, e.g. to make its cards dark-mode friendly. Read it with get_model_styling first. "+
+			"Anki adds the classes .nightMode and .night_mode to the card in night mode."),
+		mcp.WithString("model",
+			mcp.Required(),
+			mcp.Description("Name of the note type"),
+		),
+		mcp.WithString("css",
+			mcp.Required(),
+			mcp.Description("New CSS, replacing the current styling unless append is true"),
+		),
+		mcp.WithBoolean("append",
+			mcp.Description("Optional: Add the CSS after the current styling instead of replacing it (default: false)"),
+		),
+	)
+	a.addChangingTool(s, updateStylingTool, (*AnkiMCPServer).handleUpdateModelStyling)
+
+	// Tool: Find and Replace in Models
+	findReplaceTool := mcp.NewTool("find_replace_in_models",
+		mcp.WithDescription("Replace text in the card templates and styling of one note type or of all of them in one call, e.g. to rename a CSS class "+
+			"used by every template. The replacement is plain text, not a regular expression. Field references the replacement creates in templates "+
+			"are checked against each note type's fields before anything changes. Use dry_run to see where the text occurs."),
+		mcp.WithString("find",
+			mcp.Required(),
+			mcp.Description("Text to find"),
+		),
+		mcp.WithString("replace",
+			mcp.Required(),
+			mcp.Description("Text to put in its place; may be empty to remove it"),
+		),
+		mcp.WithString("model",
+			mcp.Description("Optional: Name of the note type (default: all note types)"),
+		),
+		mcp.WithBoolean("front",
+			mcp.Description("Optional: Replace in front templates (default: true)"),
+		),
+		mcp.WithBoolean("back",
+			mcp.Description("Optional: Replace in back templates (default: true)"),
+		),
+		mcp.WithBoolean("css",
+			mcp.Description("Optional: Replace in the styling (default: true)"),
+		),
+	)
+	a.addChangingTool(s, findReplaceTool, (*AnkiMCPServer).handleFindReplaceInModels)
+}
+
+// handleRenameModelField renames a note type's field and verifies that the
+// notes' content followed it
+func (a *AnkiMCPServer) handleRenameModelField(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	model, ok := args["model"].(string)
+	if !ok || strings.TrimSpace(model) == "" {
+		return a.errorf("model is required"), nil
+	}
+	oldName, ok := args["old_name"].(string)
+	if !ok || oldName == "" {
+		return a.errorf("old_name is required"), nil
+	}
+	newName, ok := args["new_name"].(string)
+	if !ok || strings.TrimSpace(newName) == "" {
+		return a.errorf("new_name is required"), nil
+	}
+	if confirm, _ := args["confirm"].(bool); !confirm {
+		return a.errorf("rename_model_field was not run. Renaming a field forces a full upload on the next sync. " +
+			"Ask the user to confirm, then call again with confirm=true."), nil
+	}
+
+	models, err := a.ankiClient.GetModelNames()
+	if err != nil {
+		return a.errorf("Failed to get note types: %v", err), nil
+	}
+	if !slices.Contains(models, model) {
+		return a.errorf("Note type not found: %s", model), nil
+	}
+	fields, err := a.ankiClient.GetModelFieldNames(model)
+	if err != nil {
+		return a.errorf("Failed to get fields: %v", err), nil
+	}
+	if !slices.Contains(fields, oldName) {
+		return a.errorf("Note type %s has no field %s. Fields: %s", model, oldName, strings.Join(fields, ", ")), nil
+	}
+	if slices.Contains(fields, newName) {
+		return a.errorf("Note type %s already has a field named %s", model, newName), nil
+	}
+
+	// Snapshot the field's content so it can be compared after the rename
+	before, err := a.ankiClient.FindNotesInfo(`note:"` + model + `"`)
+	if err != nil {
+		return a.errorf("Failed to get note info: %v", err), nil
+	}
+	noteIDs := make([]int64, len(before))
+	values := make(map[int64]string, len(before))
+	for i, info := range before {
+		noteIDs[i] = info.NoteID
+		values[info.NoteID], _ = noteField(info, oldName)
+	}
+
+	if err := a.ankiClient.RenameModelField(model, oldName, newName); err != nil {
+		return a.errorf("Failed to rename field: %v", err), nil
+	}
+
+	after, err := a.ankiClient.GetNotesInfo(noteIDs)
+	if err != nil {
+		return a.errorf("Failed to get note info: %v", err), nil
+	}
+	var mismatched []int64
+	nonEmpty := 0
+	for i, info := range after {
+		value, ok := noteField(info, newName)
+		if !ok || value != values[noteIDs[i]] {
+			mismatched = append(mismatched, noteIDs[i])
+		} else if value != "" {
+			nonEmpty++
+		}
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Renamed field %s to %s in note type %s", oldName, newName, model))
+	out.Item(a.t("Notes checked: %d", len(noteIDs)))
+	out.Item(a.t("Notes with content preserved: %d (%d non-empty)", len(noteIDs)-len(mismatched), nonEmpty))
+	if len(mismatched) > 0 {
+		listed := mismatched[:min(len(mismatched), maxListedNotes)]
+		ids := make([]string, len(listed))
+		for i, id := range listed {
+			ids[i] = strconv.FormatInt(id, 10)
+		}
+		out.Item(a.t("Notes whose content did not carry over: %d (%s)", len(mismatched), strings.Join(ids, ", ")))
+	}
+	out.Line(a.t("The next sync will require a full upload to AnkiWeb."))
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+		IsError: len(mismatched) > 0,
+	}, nil
+}
+
+// handleChangeNoteType switches notes of one note type to another, mapping
+// old fields to new ones, after checking that the note types are compatible
+// and that no content is lost unless discard_unmapped allows it
+func (a *AnkiMCPServer) handleChangeNoteType(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	newModel, _ := args["new_model"].(string)
+	if strings.TrimSpace(newModel) == "" {
+		return a.errorf("new_model is required"), nil
+	}
+	noteIDs, errResult := a.selectNotes(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if len(noteIDs) == 0 {
+		return a.errorf("No notes found"), nil
+	}
+	if errResult := a.checkModel(newModel); errResult != nil {
+		return errResult, nil
+	}
+
+	notes, err := a.ankiClient.GetNotesInfo(noteIDs)
+	if err != nil {
+		return a.errorf("Failed to get note info: %v", err), nil
+	}
+	var oldModels []string
+	for i, info := range notes {
+		if info.NoteID == 0 {
+			return a.errorf("Note not found: %d", noteIDs[i]), nil
+		}
+		if !slices.Contains(oldModels, info.ModelName) {
+			oldModels = append(oldModels, info.ModelName)
+		}
+	}
+	if len(oldModels) > 1 {
+		sort.Strings(oldModels)
+		return a.errorf("The notes use different note types (%s); change the notes of one note type at a time", strings.Join(oldModels, ", ")), nil
+	}
+	oldModel := oldModels[0]
+	if oldModel == newModel {
+		return a.errorf("The notes already use note type %s", newModel), nil
+	}
+
+	oldFields := noteFieldNames(notes[0])
+	newFields, err := a.ankiClient.GetModelFieldNames(newModel)
+	if err != nil {
+		return a.errorf("Failed to get fields: %v", err), nil
+	}
+	if errResult := a.checkCardTypes(oldModel, newModel); errResult != nil {
+		return errResult, nil
+	}
+
+	// Map the fields, by default those with the same name
+	fieldMap := make(map[string]string)
+	if requested := objectValue(args, "field_map"); len(requested) > 0 {
+		for oldField, value := range requested {
+			newField, ok := value.(string)
+			if !ok {
+				return a.errorf("field_map: %s must map to the name of a field of %s", oldField, newModel), nil
+			}
+			if !slices.Contains(oldFields, oldField) {
+				return a.errorf("field_map: note type %s has no field %s. Fields: %s", oldModel, oldField, strings.Join(oldFields, ", ")), nil
+			}
+			if !slices.Contains(newFields, newField) {
+				return a.errorf("field_map: note type %s has no field %s. Fields: %s", newModel, newField, strings.Join(newFields, ", ")), nil
+			}
+			fieldMap[oldField] = newField
+		}
+	} else {
+		for _, field := range oldFields {
+			if slices.Contains(newFields, field) {
+				fieldMap[field] = field
+			}
+		}
+		if len(fieldMap) == 0 {
+			return a.errorf("%s and %s have no field in common; pass field_map. Fields of %s: %s", oldModel, newModel, newModel, strings.Join(newFields, ", ")), nil
+		}
+	}
+	sources := make(map[string][]string)
+	for _, oldField := range oldFields {
+		if newField, ok := fieldMap[oldField]; ok {
+			sources[newField] = append(sources[newField], oldField)
+		}
+	}
+	for _, newField := range newFields {
+		if len(sources[newField]) > 1 {
+			return a.errorf("field_map: %s all map to %s", strings.Join(sources[newField], ", "), newField), nil
+		}
+	}
+
+	// Content of fields left out of the mapping is lost
+	var dropped []string
+	for _, oldField := range oldFields {
+		if _, ok := fieldMap[oldField]; ok {
+			continue
+		}
+		for _, info := range notes {
+			if value, _ := noteField(info, oldField); strings.TrimSpace(value) != "" {
+				dropped = append(dropped, oldField)
+				break
+			}
+		}
+	}
+	if discard, _ := args["discard_unmapped"].(bool); len(dropped) > 0 && !discard {
+		return a.errorf("The content of field(s) %s would be lost. Map them in field_map, or pass discard_unmapped=true if the user agrees to lose it.", strings.Join(dropped, ", ")), nil
+	}
+
+	changes := make([]NoteModelChange, len(notes))
+	for i, info := range notes {
+		fields := make(map[string]string, len(newFields))
+		for _, newField := range newFields {
+			fields[newField] = ""
+		}
+		for oldField, newField := range fieldMap {
+			fields[newField], _ = noteField(info, oldField)
+		}
+		changes[i] = NoteModelChange{ID: info.NoteID, Fields: fields, Tags: info.Tags}
+	}
+	errs := a.ankiClient.ChangeNoteModels(ctx, newModel, changes)
+
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, a.t("Note %d failed: %v", changes[i].ID, err))
+		}
+	}
+
+	var mapping, empty []string
+	for _, oldField := range oldFields {
+		if newField, ok := fieldMap[oldField]; ok {
+			mapping = append(mapping, oldField+" → "+newField)
+		}
+	}
+	for _, newField := range newFields {
+		if len(sources[newField]) == 0 {
+			empty = append(empty, newField)
+		}
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Changed %d of %d note(s) from %s to %s", len(notes)-len(failed), len(notes), oldModel, newModel))
+	out.Item(a.t("Fields: %s", strings.Join(mapping, ", ")))
+	if len(empty) > 0 {
+		out.Item(a.t("Left empty: %s", strings.Join(empty, ", ")))
+	}
+	if len(dropped) > 0 {
+		out.Item(a.t("Content discarded: %s", strings.Join(dropped, ", ")))
+	}
+	for _, failure := range failed[:min(len(failed), maxListedNotes)] {
+		out.Item(failure)
+	}
+	if len(failed) > maxListedNotes {
+		out.Item(a.t("... and %d more", len(failed)-maxListedNotes))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+		IsError: len(failed) > 0,
+	}, nil
+}
+
+// checkCardTypes returns an error result unless the cards of notes of one
+// note type keep a card type of the other. Cards keep their card type by
+// position, and cloze numbers don't correspond to card types.
+func (a *AnkiMCPServer) checkCardTypes(oldModel, newModel string) *mcp.CallToolResult {
+	oldTemplates, err := a.ankiClient.GetModelTemplates(oldModel)
+	if err != nil {
+		return a.errorf("Failed to get templates: %v", err)
+	}
+	newTemplates, err := a.ankiClient.GetModelTemplates(newModel)
+	if err != nil {
+		return a.errorf("Failed to get templates: %v", err)
+	}
+
+	isCloze := func(templates []CardTemplate) bool {
+		return len(templates) > 0 && clozeFieldPattern.MatchString(templates[0].Front)
+	}
+	switch {
+	case isCloze(oldTemplates) != isCloze(newTemplates):
+		return a.errorf("%s and %s are not both cloze or both standard note types, so their cards don't correspond", oldModel, newModel)
+	case len(newTemplates) < len(oldTemplates):
+		return a.errorf("%s has %d card types but %s only %d; cards of the other card types would be left without a template", oldModel, len(oldTemplates), newModel, len(newTemplates))
+	}
+	return nil
+}
+
+// templateFieldPattern matches a field reference in a card template
+var templateFieldPattern = regexp.MustCompile(`\{\{([^{}]+)\}\}`)
+
+// templateSpecialFields are the names a template can refer to besides the
+// note type's fields
+var templateSpecialFields = []string{"FrontSide", "Tags", "Type", "Deck", "Subdeck", "Card", "CardFlag", "CardID"}
+
+// templateFields returns the field names referenced by a card template,
+// without filters such as cloze: or hint: and conditional markers
+func templateFields(template string) []string {
+	var fields []string
+	for _, m := range templateFieldPattern.FindAllStringSubmatch(template, -1) {
+		ref := strings.TrimSpace(m[1])
+		if strings.HasPrefix(ref, "!") {
+			continue
+		}
+		ref = strings.TrimLeft(ref, "#^/")
+		if i := strings.LastIndex(ref, ":"); i >= 0 {
+			ref = ref[i+1:]
+		}
+		ref = strings.TrimSpace(ref)
+		if ref != "" && !slices.Contains(fields, ref) {
+			fields = append(fields, ref)
+		}
+	}
+	return fields
+}
+
+// checkModel returns an error result when a note type doesn't exist
+func (a *AnkiMCPServer) checkModel(model string) *mcp.CallToolResult {
+	if strings.TrimSpace(model) == "" {
+		return a.errorf("model is required")
+	}
+	models, err := a.ankiClient.GetModelNames()
+	if err != nil {
+		return a.errorf("Failed to get note types: %v", err)
+	}
+	if !slices.Contains(models, model) {
+		return a.errorf("Note type not found: %s", model)
+	}
+	return nil
+}
+
+// handleGetModelTemplates returns the card templates of a note type
+func (a *AnkiMCPServer) handleGetModelTemplates(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	model, _ := request.GetArguments()["model"].(string)
+	if errResult := a.checkModel(model); errResult != nil {
+		return errResult, nil
+	}
+
+	templates, err := a.ankiClient.GetModelTemplates(model)
+	if err != nil {
+		return a.errorf("Failed to get templates: %v", err), nil
+	}
+
+	if wantsJSON(request) {
+		list := make([]map[string]string, len(templates))
+		for i, tmpl := range templates {
+			list[i] = map[string]string{"name": tmpl.Name, "front": tmpl.Front, "back": tmpl.Back}
+		}
+		return a.jsonResult(map[string]interface{}{
+			"model":     model,
+			"templates": list,
+		}), nil
+	}
+
+	out := a.newOutput()
+	for _, tmpl := range templates {
+		out.Heading(a.t("%s: front template", tmpl.Name))
+		out.Line(tmpl.Front)
+		out.Heading(a.t("%s: back template", tmpl.Name))
+		out.Line(tmpl.Back)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleUpdateModelTemplates changes card templates of a note type after
+// checking their names and field references
+func (a *AnkiMCPServer) handleUpdateModelTemplates(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	model, _ := args["model"].(string)
+	if errResult := a.checkModel(model); errResult != nil {
+		return errResult, nil
+	}
+	requested := objectValue(args, "templates")
+	if len(requested) == 0 {
+		return a.errorf("templates is required"), nil
+	}
+
+	current, err := a.ankiClient.GetModelTemplates(model)
+	if err != nil {
+		return a.errorf("Failed to get templates: %v", err), nil
+	}
+	names := make([]string, len(current))
+	for i, tmpl := range current {
+		names[i] = tmpl.Name
+	}
+	fields, err := a.ankiClient.GetModelFieldNames(model)
+	if err != nil {
+		return a.errorf("Failed to get fields: %v", err), nil
+	}
+
+	updates := make(map[string]map[string]string)
+	var changed []string
+	for name, value := range requested {
+		if !slices.Contains(names, name) {
+			return a.errorf("Note type %s has no template %s. Templates: %s", model, name, strings.Join(names, ", ")), nil
+		}
+		sides, _ := value.(map[string]interface{})
+		update := make(map[string]string)
+		for _, side := range []string{"Front", "Back"} {
+			raw, ok := sides[side]
+			if !ok {
+				continue
+			}
+			html, ok := raw.(string)
+			if !ok {
+				return a.errorf("%s of template %s must be a string", side, name), nil
+			}
+			var unknown []string
+			for _, field := range templateFields(html) {
+				if !slices.Contains(fields, field) && !slices.Contains(templateSpecialFields, field) {
+					unknown = append(unknown, field)
+				}
+			}
+			if len(unknown) > 0 {
+				return a.errorf("%s of template %s refers to unknown field(s): %s. Fields: %s", side, name, strings.Join(unknown, ", "), strings.Join(fields, ", ")), nil
+			}
+			update[side] = html
+			changed = append(changed, name+" "+side)
+		}
+		if len(update) == 0 {
+			return a.errorf("Template %s needs Front or Back", name), nil
+		}
+		updates[name] = update
+	}
+	sort.Strings(changed)
+
+	if err := a.ankiClient.UpdateModelTemplates(model, updates); err != nil {
+		return a.errorf("Failed to update templates: %v", err), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: a.t("Updated templates of %s: %s", model, strings.Join(changed, ", ")),
+			},
+		},
+	}, nil
+}
+
+// handleGetModelStyling returns the CSS of a note type
+func (a *AnkiMCPServer) handleGetModelStyling(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	model, _ := request.GetArguments()["model"].(string)
+	if errResult := a.checkModel(model); errResult != nil {
+		return errResult, nil
+	}
+
+	css, err := a.ankiClient.GetModelStyling(model)
+	if err != nil {
+		return a.errorf("Failed to get styling: %v", err), nil
+	}
+
+	if wantsJSON(request) {
+		return a.jsonResult(map[string]interface{}{
+			"model": model,
+			"css":   css,
+		}), nil
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Styling of %s", model))
+	out.Line(css)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleUpdateModelStyling replaces or extends the CSS of a note type
+func (a *AnkiMCPServer) handleUpdateModelStyling(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	model, _ := args["model"].(string)
+	if errResult := a.checkModel(model); errResult != nil {
+		return errResult, nil
+	}
+	css, _ := args["css"].(string)
+	if strings.TrimSpace(css) == "" {
+		return a.errorf("css is required"), nil
+	}
+	// Unbalanced braces make Anki ignore the rest of the styling
+	if strings.Count(css, "{") != strings.Count(css, "}") {
+		return a.errorf("The CSS has unbalanced braces"), nil
+	}
+
+	if appendCSS, _ := args["append"].(bool); appendCSS {
+		current, err := a.ankiClient.GetModelStyling(model)
+		if err != nil {
+			return a.errorf("Failed to get styling: %v", err), nil
+		}
+		if current != "" && !strings.HasSuffix(current, "\n") {
+			current += "\n"
+		}
+		css = current + css
+	}
+
+	if err := a.ankiClient.UpdateModelStyling(model, css); err != nil {
+		return a.errorf("Failed to update styling: %v", err), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: a.t("Updated styling of %s", model),
+			},
+		},
+	}, nil
+}
+
+// handleFindReplaceInModels replaces text in card templates and styling,
+// reporting beforehand where it occurs and refusing replacements that would
+// make a template refer to a field its note type doesn't have
+func (a *AnkiMCPServer) handleFindReplaceInModels(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	find, _ := args["find"].(string)
+	if find == "" {
+		return a.errorf("find is required"), nil
+	}
+	replace, ok := args["replace"].(string)
+	if !ok {
+		return a.errorf("replace is required"), nil
+	}
+	if find == replace {
+		return a.errorf("find and replace are the same"), nil
+	}
+	inFront, inBack, inCSS := true, true, true
+	if v, ok := args["front"].(bool); ok {
+		inFront = v
+	}
+	if v, ok := args["back"].(bool); ok {
+		inBack = v
+	}
+	if v, ok := args["css"].(bool); ok {
+		inCSS = v
+	}
+	if !inFront && !inBack && !inCSS {
+		return a.errorf("front, back and css are all false, so there is nothing to search"), nil
+	}
+
+	model, _ := args["model"].(string)
+	var models []string
+	if model != "" {
+		if errResult := a.checkModel(model); errResult != nil {
+			return errResult, nil
+		}
+		models = []string{model}
+	} else {
+		var err error
+		models, err = a.ankiClient.GetModelNames()
+		if err != nil {
+			return a.errorf("Failed to get note types: %v", err), nil
+		}
+		sort.Strings(models)
+	}
+
+	// Fetch every note type's templates, styling and fields at once
+	type modelContent struct {
+		templates map[string]CardTemplate
+		styling   struct {
+			CSS string `json:"css"`
+		}
+		fields []string
+	}
+	contents := make([]modelContent, len(models))
+	b := a.ankiClient.newBatch()
+	for i, name := range models {
+		params := map[string]string{"modelName": name}
+		b.add("modelTemplates", params, &contents[i].templates)
+		b.add("modelStyling", params, &contents[i].styling)
+		b.add("modelFieldNames", params, &contents[i].fields)
+	}
+	if err := b.send(); err != nil {
+		return a.errorf("Failed to get templates: %v", err), nil
+	}
+
+	// Find the occurrences and check the field references they leave
+	matches := make(map[string][]string)
+	var matched []string
+	for i, name := range models {
+		content := contents[i]
+		var places []string
+		check := func(text, place string) *mcp.CallToolResult {
+			n := strings.Count(text, find)
+			if n == 0 {
+				return nil
+			}
+			places = append(places, fmt.Sprintf("%s (%d)", place, n))
+			var unknown []string
+			for _, field := range templateFields(strings.ReplaceAll(text, find, replace)) {
+				if !slices.Contains(content.fields, field) && !slices.Contains(templateSpecialFields, field) && !slices.Contains(unknown, field) {
+					unknown = append(unknown, field)
+				}
+			}
+			if len(unknown) > 0 {
+				return a.errorf("After the replacement, %s of note type %s would refer to unknown field(s): %s. Fields: %s", place, name, strings.Join(unknown, ", "), strings.Join(content.fields, ", "))
+			}
+			return nil
+		}
+
+		names := slices.Sorted(maps.Keys(content.templates))
+		for _, tmplName := range names {
+			tmpl := content.templates[tmplName]
+			if inFront {
+				if errResult := check(tmpl.Front, a.t("%s front", tmplName)); errResult != nil {
+					return errResult, nil
+				}
+			}
+			if inBack {
+				if errResult := check(tmpl.Back, a.t("%s back", tmplName)); errResult != nil {
+					return errResult, nil
+				}
+			}
+		}
+		if inCSS {
+			// Field references in the styling mean nothing to Anki
+			if n := strings.Count(content.styling.CSS, find); n > 0 {
+				places = append(places, fmt.Sprintf("%s (%d)", a.t("styling"), n))
+			}
+		}
+		if len(places) > 0 {
+			matches[name] = places
+			matched = append(matched, name)
+		}
+	}
+	if len(matched) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: a.t("%q was not found in the chosen templates; nothing was changed", find),
+				},
+			},
+		}, nil
+	}
+
+	// Replace in a single note type when only one contains the text
+	target := model
+	if len(matched) == 1 {
+		target = matched[0]
+	}
+	if _, err := a.ankiClient.FindAndReplaceInModels(target, find, replace, inFront, inBack, inCSS); err != nil {
+		return a.errorf("Failed to replace: %v", err), nil
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Replaced %q with %q in %d note type(s)", find, replace, len(matched)))
+	for _, name := range matched {
+		out.Item(fmt.Sprintf("%s: %s", name, strings.Join(matches[name], ", ")))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRenameModelField(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	args := map[string]interface{}{"model": "Cloze", "old_name": "Back Extra", "new_name": "Notes"}
+	if text, isErr := callTool(t, server.handleRenameModelField, args); !isErr || !strings.Contains(text, "confirm=true") {
+		t.Errorf("Expected the rename to require confirmation, got: %s", text)
+	}
+
+	args["confirm"] = true
+	text, isErr := callTool(t, server.handleRenameModelField, args)
+	if isErr || !strings.Contains(text, "Notes checked: 1") || !strings.Contains(text, "content preserved: 1") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	for _, note := range mock.notes {
+		if _, ok := note.Fields["Back Extra"]; ok {
+			t.Errorf("Note %d still has the old field", note.ID)
+		}
+	}
+
+	if text, isErr := callTool(t, server.handleRenameModelField, args); !isErr || !strings.Contains(text, "has no field Back Extra") {
+		t.Errorf("Expected an error for a missing field, got: %s", text)
+	}
+}
+
+func TestTemplateFields(t *testing.T) {
+	got := templateFields("{{#Hint}}{{hint:Hint}}{{/Hint}} {{cloze:Text}} {{type:cloze:Text}} {{! comment }} {{ FrontSide }}")
+	want := []string{"Hint", "Text", "FrontSide"}
+	if !slices.Equal(got, want) {
+		t.Errorf("templateFields() = %v, want %v", got, want)
+	}
+}
+
+func TestModelTemplates(t *testing.T) {
+	server, _ := newMockServer(t)
+
+	text, isErr := callTool(t, server.handleGetModelTemplates, map[string]interface{}{"model": "Basic (and reversed card)"})
+	if isErr || !strings.Contains(text, "Card 2: front template") || !strings.Contains(text, "{{Back}}") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+
+	args := map[string]interface{}{
+		"model":     "Basic",
+		"templates": map[string]interface{}{"Card 1": map[string]interface{}{"Front": "{{Front}}{{hint:Hint}}"}},
+	}
+	if text, isErr := callTool(t, server.handleUpdateModelTemplates, args); !isErr || !strings.Contains(text, "unknown field(s): Hint") {
+		t.Errorf("Expected an unknown field error, got: %s", text)
+	}
+
+	args["templates"] = map[string]interface{}{"Card 1": map[string]interface{}{"Front": "<div class=big>{{Front}}</div>"}}
+	if text, isErr := callTool(t, server.handleUpdateModelTemplates, args); isErr || text != "Updated templates of Basic: Card 1 Front" {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	text, _ = callTool(t, server.handleGetModelTemplates, map[string]interface{}{"model": "Basic", "format": "json"})
+	var result struct {
+		Templates []map[string]string `json:"templates"`
+	}
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", text, err)
+	}
+	if tmpl := result.Templates[0]; tmpl["front"] != "<div class=big>{{Front}}</div>" || !strings.Contains(tmpl["back"], "<hr id=answer>") {
+		t.Errorf("Expected the new front and the old back, got: %v", tmpl)
+	}
+}
+
+func TestModelStyling(t *testing.T) {
+	server, mock := newMockServer(t)
+
+	text, isErr := callTool(t, server.handleGetModelStyling, map[string]interface{}{"model": "Basic"})
+	if isErr || !strings.Contains(text, "Styling of Basic") || !strings.Contains(text, "font-family: arial;") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+
+	args := map[string]interface{}{"model": "Basic", "css": ".nightMode .card { color: white;"}
+	if text, isErr := callTool(t, server.handleUpdateModelStyling, args); !isErr || !strings.Contains(text, "unbalanced braces") {
+		t.Errorf("Expected an unbalanced braces error, got: %s", text)
+	}
+
+	args["css"] = ".nightMode .card { color: white; background-color: #222; }"
+	args["append"] = true
+	if text, isErr := callTool(t, server.handleUpdateModelStyling, args); isErr || text != "Updated styling of Basic" {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	if css := mock.models["Basic"].CSS; css != mockCSS+args["css"].(string) {
+		t.Errorf("Expected the CSS to be appended, got: %s", css)
+	}
+
+	args["append"] = false
+	callTool(t, server.handleUpdateModelStyling, args)
+	if css := mock.models["Basic"].CSS; css != args["css"] {
+		t.Errorf("Expected the CSS to be replaced, got: %s", css)
+	}
+}
+
+func TestChangeNoteType(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+	id, err := mock.addNote(Note{
+		DeckName:  "Default",
+		ModelName: "Basic",
+		Fields:    map[string]string{"Front": "el ornitorrinco", "Back": "the platypus"},
+		Tags:      []string{"animals"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for query, want := range map[string]string{
+		`note:"Basic (and reversed card)"`: "has 2 card types but Basic only 1",
+		"note:Cloze":                       "not both cloze or both standard",
+	} {
+		text, isErr := callTool(t, server.handleChangeNoteType, map[string]interface{}{"query": query, "new_model": "Basic"})
+		if !isErr || !strings.Contains(text, want) {
+			t.Errorf("Expected %q for %s, got %s", want, query, text)
+		}
+	}
+
+	args := map[string]interface{}{
+		"note_ids":  []interface{}{float64(id)},
+		"new_model": "Basic (and reversed card)",
+		"field_map": map[string]interface{}{"Front": "Front", "Back": "Reverse"},
+	}
+	if text, isErr := callTool(t, server.handleChangeNoteType, args); !isErr || !strings.Contains(text, "has no field Reverse") {
+		t.Errorf("Expected an error for an unknown field, got %s", text)
+	}
+
+	args["field_map"] = map[string]interface{}{"Front": "Front"}
+	if text, isErr := callTool(t, server.handleChangeNoteType, args); !isErr || !strings.Contains(text, "field(s) Back would be lost") {
+		t.Errorf("Expected lost content to be refused, got %s", text)
+	}
+
+	args["field_map"] = map[string]interface{}{"Front": "Back", "Back": "Front"}
+	text, isErr := callTool(t, server.handleChangeNoteType, args)
+	if isErr || !strings.Contains(text, "Changed 1 of 1 note(s) from Basic to Basic (and reversed card)") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	note := mock.notes[id]
+	if note.Model != "Basic (and reversed card)" || note.Fields["Front"] != "the platypus" || note.Fields["Back"] != "el ornitorrinco" || !slices.Equal(note.Tags, []string{"animals"}) {
+		t.Errorf("Unexpected note %+v", note)
+	}
+}
+
+func TestFindReplaceInModels(t *testing.T) {
+	server, mock := newMockServer(t)
+
+	text, isErr := callTool(t, server.handleFindReplaceInModels, map[string]interface{}{"find": "{{Back}}", "replace": "{{Reverse}}"})
+	if !isErr || !strings.Contains(text, "unknown field(s): Reverse") {
+		t.Errorf("Expected an unknown field to be refused, got %s", text)
+	}
+
+	text, isErr = callTool(t, server.handleFindReplaceInModels, map[string]interface{}{"find": "<hr id=answer>", "replace": `<hr id=answer class="divider">`, "model": "Basic", "front": false})
+	if isErr || !strings.Contains(text, "in 1 note type(s)") || !strings.Contains(text, "Basic: Card 1 back (1)") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	if !strings.Contains(mock.models["Basic"].Templates[0].Back, "divider") || strings.Contains(mock.models["Basic (and reversed card)"].Templates[0].Back, "divider") {
+		t.Error("Expected only the Basic back template to change")
+	}
+
+	text, isErr = callTool(t, server.handleFindReplaceInModels, map[string]interface{}{"find": ".card {", "replace": ".card, .flashcard {"})
+	if isErr || !strings.Contains(text, "in 3 note type(s)") || !strings.Contains(text, "Cloze: styling (1)") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+
+	text, isErr = callTool(t, server.handleFindReplaceInModels, map[string]interface{}{"find": ".nightMode", "replace": ".night_mode"})
+	if isErr || !strings.Contains(text, "was not found") {
+		t.Errorf("Expected nothing to be found, got %s", text)
+	}
+}
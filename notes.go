@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerNoteTools registers note editing tools with the MCP server
+func (a *AnkiMCPServer) registerNoteTools(s *server.MCPServer) {
+	// Tool: Update Note
+	updateNoteTool := mcp.NewTool("update_note",
+		mcp.WithDescription("Change the fields and tags of an existing note, e.g. to correct a card created earlier. Fields not mentioned keep their content."),
+		mcp.WithNumber("note_id",
+			mcp.Required(),
+			mcp.Description("ID of the note to update"),
+		),
+		mcp.WithObject("fields",
+			mcp.Description("Optional: New field values by field name, e.g. {\"Back\": \"dog\"}"),
+		),
+		mcp.WithArray("add_tags",
+			mcp.Description("Optional: Tags to add"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithArray("remove_tags",
+			mcp.Description("Optional: Tags to remove"),
+			mcp.WithStringItems(),
+		),
+	)
+	a.addChangingTool(s, updateNoteTool, (*AnkiMCPServer).handleUpdateNote)
+
+	// Tool: Delete Notes
+	deleteNotesTool := mcp.NewTool("delete_notes",
+		mcp.WithDescription("Delete notes and all their cards, selected by ID or by an Anki search query. "+
+			"Without confirm=true it only previews what would be deleted. Deleting cannot be undone through this server, "+
+			"so show the preview to the user and only confirm when they have agreed."),
+		mcp.WithArray("note_ids",
+			mcp.Description("IDs of the notes to delete"),
+			mcp.WithNumberItems(),
+		),
+		mcp.WithString("query",
+			mcp.Description("Anki search query selecting the notes to delete, instead of note_ids"),
+		),
+		mcp.WithBoolean("confirm",
+			mcp.Description("Must be true to delete the notes; otherwise only a preview is returned"),
+		),
+	)
+	a.addChangingTool(s, deleteNotesTool, (*AnkiMCPServer).handleDeleteNotes)
+}
+
+// handleUpdateNote updates the fields and tags of a note
+func (a *AnkiMCPServer) handleUpdateNote(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	id, ok := args["note_id"].(float64)
+	if !ok {
+		return a.errorf("note_id is required"), nil
+	}
+	noteID := int64(id)
+
+	fields := make(map[string]string)
+	for name, value := range objectValue(args, "fields") {
+		text, ok := value.(string)
+		if !ok {
+			return a.errorf("Field %s must be a string", name), nil
+		}
+		fields[name] = text
+	}
+	addTags := stringSliceValue(args, "add_tags")
+	removeTags := stringSliceValue(args, "remove_tags")
+	if len(fields) == 0 && len(addTags) == 0 && len(removeTags) == 0 {
+		return a.errorf("Nothing to update: pass fields, add_tags or remove_tags"), nil
+	}
+	for _, tag := range slices.Concat(addTags, removeTags) {
+		if !validTag(tag) {
+			return a.errorf("Invalid tag %q: tags can't be empty or contain spaces", tag), nil
+		}
+	}
+
+	infos, err := a.ankiClient.GetNotesInfo([]int64{noteID})
+	if err != nil {
+		return a.errorf("Failed to get note info: %v", err), nil
+	}
+	if len(infos) == 0 || infos[0].NoteID == 0 {
+		return a.errorf("Note not found: %d", noteID), nil
+	}
+
+	var changed, unknown []string
+	for name, value := range fields {
+		current, ok := noteField(infos[0], name)
+		switch {
+		case !ok:
+			unknown = append(unknown, name)
+		case current != value:
+			changed = append(changed, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return a.errorf("Unknown field(s) for note type %s: %s. Fields: %s", infos[0].ModelName, strings.Join(unknown, ", "), strings.Join(noteFieldNames(infos[0]), ", ")), nil
+	}
+	sort.Strings(changed)
+
+	if len(changed) > 0 {
+		if err := a.ankiClient.UpdateNoteFields(noteID, fields); err != nil {
+			return a.errorf("Failed to update note %d: %v", noteID, err), nil
+		}
+	}
+	if len(addTags) > 0 {
+		if err := a.ankiClient.AddTags([]int64{noteID}, addTags); err != nil {
+			return a.errorf("Failed to add tags: %v", err), nil
+		}
+	}
+	if len(removeTags) > 0 {
+		if err := a.ankiClient.RemoveTags([]int64{noteID}, removeTags); err != nil {
+			return a.errorf("Failed to remove tags: %v", err), nil
+		}
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Updated note %d", noteID))
+	if len(changed) > 0 {
+		out.Item(a.t("Fields changed: %s", strings.Join(changed, ", ")))
+	} else if len(fields) > 0 {
+		out.Item(a.t("Fields already had these values"))
+	}
+	if len(addTags) > 0 {
+		out.Item(a.t("Tags added: %s", strings.Join(addTags, ", ")))
+	}
+	if len(removeTags) > 0 {
+		out.Item(a.t("Tags removed: %s", strings.Join(removeTags, ", ")))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleDeleteNotes previews or deletes notes
+func (a *AnkiMCPServer) handleDeleteNotes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	noteIDs, errResult := a.selectNotes(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	infos, err := a.ankiClient.GetNotesInfo(noteIDs)
+	if err != nil {
+		return a.errorf("Failed to get note info: %v", err), nil
+	}
+	var existing []NoteInfo
+	var ids []int64
+	cards := 0
+	for i, info := range infos {
+		if info.NoteID == 0 {
+			continue
+		}
+		existing = append(existing, info)
+		ids = append(ids, noteIDs[i])
+		cards += len(info.Cards)
+	}
+	if len(ids) == 0 {
+		return a.errorf("No notes found to delete"), nil
+	}
+
+	confirm, _ := args["confirm"].(bool)
+	if confirm {
+		if err := a.ankiClient.DeleteNotes(ids); err != nil {
+			return a.errorf("Failed to delete notes: %v", err), nil
+		}
+	}
+
+	out := a.newOutput()
+	if confirm {
+		out.Heading(a.t("Deleted %d note(s) with %d card(s)", len(ids), cards))
+	} else {
+		out.Heading(a.t("Would delete %d note(s) with %d card(s)", len(ids), cards))
+	}
+	for i, info := range existing[:min(len(existing), maxListedNotes)] {
+		out.Item(a.t("%d [%s]: %s", ids[i], info.ModelName, noteSummary(info)))
+	}
+	if len(existing) > maxListedNotes {
+		out.Item(a.t("... and %d more", len(existing)-maxListedNotes))
+	}
+	if !confirm {
+		out.Line(a.t("Nothing was deleted. Show this list to the user and call again with confirm=true once they agree."))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// noteFieldNames returns the field names of a notesInfo entry in field order
+func noteFieldNames(note NoteInfo) []string {
+	names := make([]string, 0, len(note.Fields))
+	for name := range note.Fields {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return note.Fields[names[i]].Order < note.Fields[names[j]].Order
+	})
+	return names
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestUpdateNote(t *testing.T) {
+	server, mock := newMockServer(t)
+	id, err := mock.addNote(Note{DeckName: "Default", ModelName: "Basic", Fields: map[string]string{"Front": "perro", "Back": "cat"}, Tags: []string{"draft"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := map[string]interface{}{
+		"note_id":     float64(id),
+		"fields":      map[string]interface{}{"Front": "perro", "Back": "dog"},
+		"add_tags":    []interface{}{"spanish"},
+		"remove_tags": []interface{}{"draft"},
+	}
+	text, isErr := callTool(t, server.handleUpdateNote, args)
+	if isErr || !strings.Contains(text, "Fields changed: Back") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	note := mock.notes[id]
+	if note.Fields["Back"] != "dog" || !slices.Equal(note.Tags, []string{"spanish"}) {
+		t.Errorf("Unexpected note after update: %+v", note)
+	}
+
+	args = map[string]interface{}{"note_id": float64(id), "fields": map[string]interface{}{"Meaning": "dog"}}
+	if text, isErr := callTool(t, server.handleUpdateNote, args); !isErr || !strings.Contains(text, "Fields: Front, Back") {
+		t.Errorf("Expected an unknown field error, got: %s", text)
+	}
+	args = map[string]interface{}{"note_id": float64(id), "add_tags": []interface{}{"two words"}}
+	if text, isErr := callTool(t, server.handleUpdateNote, args); !isErr {
+		t.Errorf("Expected an invalid tag error, got: %s", text)
+	}
+}
+
+func TestDeleteNotes(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	args := map[string]interface{}{"query": "tag:grammar"}
+	text, isErr := callTool(t, server.handleDeleteNotes, args)
+	if isErr || !strings.Contains(text, "Would delete 1 note(s) with 2 card(s)") || len(mock.notes) != 7 {
+		t.Fatalf("Unexpected preview: %s", text)
+	}
+
+	args["confirm"] = true
+	text, isErr = callTool(t, server.handleDeleteNotes, args)
+	if isErr || !strings.Contains(text, "Deleted 1 note(s)") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	if len(mock.notes) != 6 || len(mock.cards) != 12 {
+		t.Errorf("Expected 6 notes and 12 cards left, got %d and %d", len(mock.notes), len(mock.cards))
+	}
+
+	if text, isErr := callTool(t, server.handleDeleteNotes, args); !isErr {
+		t.Errorf("Expected an error when nothing matches, got: %s", text)
+	}
+}
@@ -0,0 +1,479 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Image occlusion note types
+const (
+	// occlusionModel is the note type built into Anki 23.10 and later
+	occlusionModel = "Image Occlusion"
+	// occlusionEnhancedModel is the note type of the Image Occlusion
+	// Enhanced add-on, which creates one note per card with SVG masks
+	occlusionEnhancedModel = "Image Occlusion Enhanced"
+)
+
+// Occlusion modes
+const (
+	// occludeAll keeps every other shape hidden while one is asked
+	occludeAll = "all"
+	// occludeOne hides only the shape that is asked
+	occludeOne = "one"
+)
+
+// Occlusion coordinate units
+const (
+	unitsPixels   = "pixels"
+	unitsFraction = "fraction"
+)
+
+// Mask colors used by Image Occlusion Enhanced
+const (
+	occlusionShapeColor    = "#FFEBA2"
+	occlusionQuestionColor = "#FF7E7E"
+	occlusionStrokeColor   = "#2D2D2D"
+)
+
+// occlusion is a rectangle to hide, as fractions of the image size
+type occlusion struct {
+	Left, Top, Width, Height float64
+	// Card is the number of the card asking for the shape; shapes with the
+	// same number are asked together
+	Card int
+}
+
+// parseOcclusions reads the occlusions argument. Pixel coordinates are
+// converted to fractions of the image size, which must then be known. Shapes
+// are numbered by their group in order of first appearance, each shape
+// without a group getting its own card. It returns the shapes and the number
+// of cards.
+func parseOcclusions(items []interface{}, units string, width, height int) ([]occlusion, int, error) {
+	if len(items) == 0 {
+		return nil, 0, fmt.Errorf("occlusions is required")
+	}
+	if units == "" {
+		units = unitsPixels
+	}
+	if units != unitsPixels && units != unitsFraction {
+		return nil, 0, fmt.Errorf("unknown units %q: use pixels or fraction", units)
+	}
+	if units == unitsPixels && (width <= 0 || height <= 0) {
+		return nil, 0, fmt.Errorf("the image size is unknown; give the occlusions as fractions with units fraction")
+	}
+
+	var shapes []occlusion
+	groups := make(map[int]int)
+	cards := 0
+	for i, item := range items {
+		rect, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, 0, fmt.Errorf("occlusion #%d must be an object with left, top, width and height", i+1)
+		}
+		s := occlusion{
+			Left:   numberValue(rect, "left"),
+			Top:    numberValue(rect, "top"),
+			Width:  numberValue(rect, "width"),
+			Height: numberValue(rect, "height"),
+		}
+		if units == unitsPixels {
+			s.Left /= float64(width)
+			s.Width /= float64(width)
+			s.Top /= float64(height)
+			s.Height /= float64(height)
+		}
+		const slack = 1e-9
+		if s.Width <= 0 || s.Height <= 0 || s.Left < 0 || s.Top < 0 || s.Left+s.Width > 1+slack || s.Top+s.Height > 1+slack {
+			return nil, 0, fmt.Errorf("occlusion #%d lies outside the image", i+1)
+		}
+
+		if group, ok := rect["group"].(float64); ok {
+			if n, ok := groups[int(group)]; ok {
+				s.Card = n
+			} else {
+				cards++
+				s.Card = cards
+				groups[int(group)] = cards
+			}
+		} else {
+			cards++
+			s.Card = cards
+		}
+		shapes = append(shapes, s)
+	}
+	return shapes, cards, nil
+}
+
+// occlusionNumber formats a coordinate the way Anki stores it, with at most
+// four decimals
+func occlusionNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*10000)/10000, 'f', -1, 64)
+}
+
+// occlusionText returns the content of the Occlusion field of Anki's built-in
+// note type: one cloze deletion per shape
+func occlusionText(shapes []occlusion, mode string) string {
+	clozes := make([]string, len(shapes))
+	for i, s := range shapes {
+		props := fmt.Sprintf("rect:left=%s:top=%s:width=%s:height=%s",
+			occlusionNumber(s.Left), occlusionNumber(s.Top), occlusionNumber(s.Width), occlusionNumber(s.Height))
+		if mode != occludeOne {
+			props += ":oi=1"
+		}
+		clozes[i] = fmt.Sprintf("{{c%d::image-occlusion:%s}}", s.Card, props)
+	}
+	return strings.Join(clozes, "<br>")
+}
+
+// occlusionMask draws shapes as an SVG mask the size of the image. Shapes of
+// the asked card are drawn in the question color when question is set.
+func occlusionMask(shapes []occlusion, width, height, card int, question bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d">`, width, height)
+	b.WriteString("<g><title>Masks</title>")
+	for _, s := range shapes {
+		fill := occlusionShapeColor
+		class := "shape"
+		if s.Card == card {
+			if !question {
+				continue
+			}
+			fill, class = occlusionQuestionColor, "qshape"
+		}
+		fmt.Fprintf(&b, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s" stroke="%s" class="%s"/>`,
+			occlusionNumber(s.Left*float64(width)), occlusionNumber(s.Top*float64(height)),
+			occlusionNumber(s.Width*float64(width)), occlusionNumber(s.Height*float64(height)),
+			fill, occlusionStrokeColor, class)
+	}
+	b.WriteString("</g></svg>")
+	return b.String()
+}
+
+// cardShapes returns the shapes shown on a card of an Image Occlusion
+// Enhanced note: the asked ones, and the others when they stay hidden
+func cardShapes(shapes []occlusion, card int, mode string) []occlusion {
+	if mode != occludeOne {
+		return shapes
+	}
+	return slices.DeleteFunc(slices.Clone(shapes), func(s occlusion) bool { return s.Card != card })
+}
+
+// registerOcclusionTools registers image occlusion tools with the MCP server
+func (a *AnkiMCPServer) registerOcclusionTools(s *server.MCPServer) {
+	// Tool: Create Image Occlusion Card
+	createOcclusionTool := mcp.NewTool("create_image_occlusion_card",
+		mcp.WithDescription("Create image occlusion cards: an image with rectangles hidden on the front and revealed on the back, e.g. to learn the labels of a diagram. "+
+			"Uses the Image Occlusion note type built into Anki 23.10 and later, or the Image Occlusion Enhanced add-on, and builds the occlusion fields or SVG masks itself. "+
+			"Each rectangle gets its own card unless rectangles share a group."),
+		mcp.WithString("deck",
+			mcp.Required(),
+			mcp.Description("Name of the deck"),
+		),
+		mcp.WithString("image_path",
+			mcp.Description("Path to the image (PNG, JPEG or GIF), on the machine running this server"),
+		),
+		mcp.WithString("image_url",
+			mcp.Description("URL of the image to download, instead of image_path"),
+		),
+		mcp.WithArray("occlusions",
+			mcp.Required(),
+			mcp.Description("Rectangles to hide, measured from the top left corner of the image"),
+			mcp.Items(map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"left":   map[string]interface{}{"type": "number", "description": "Distance of the left edge from the left of the image"},
+					"top":    map[string]interface{}{"type": "number", "description": "Distance of the top edge from the top of the image"},
+					"width":  map[string]interface{}{"type": "number", "description": "Width of the rectangle"},
+					"height": map[string]interface{}{"type": "number", "description": "Height of the rectangle"},
+					"group":  map[string]interface{}{"type": "number", "description": "Rectangles with the same group are asked on the same card"},
+				},
+				"required": []string{"left", "top", "width", "height"},
+			}),
+		),
+		mcp.WithString("units",
+			mcp.Description("Optional: pixels (default) or fraction, for coordinates between 0 and 1 relative to the image size"),
+			mcp.Enum(unitsPixels, unitsFraction),
+		),
+		mcp.WithString("hide",
+			mcp.Description("Optional: all (default) keeps the other rectangles hidden while one is asked; one hides only the asked rectangle"),
+			mcp.Enum(occludeAll, occludeOne),
+		),
+		mcp.WithString("header",
+			mcp.Description("Optional: Text shown above the image"),
+		),
+		mcp.WithString("back_extra",
+			mcp.Description("Optional: Extra information shown on the back of the cards"),
+		),
+		mcp.WithArray("tags",
+			mcp.Description("Optional: Tags for the notes"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("model_name",
+			mcp.Description("Optional: Image occlusion note type to use (default: Image Occlusion if the collection has it, otherwise Image Occlusion Enhanced)"),
+		),
+		withDuplicateOptions(),
+	)
+	a.addChangingTool(s, createOcclusionTool, (*AnkiMCPServer).handleCreateImageOcclusionCard)
+}
+
+// occlusionKind tells how an image occlusion note type stores its shapes: in
+// the Occlusion field of Anki's built-in type or as SVG masks of Image
+// Occlusion Enhanced. It returns an empty string for other note types.
+func occlusionKind(fields []string) string {
+	switch {
+	case slices.Contains(fields, "Occlusion") && slices.Contains(fields, "Image"):
+		return occlusionModel
+	case slices.Contains(fields, "Question Mask") && slices.Contains(fields, "Answer Mask") && slices.Contains(fields, "Image"):
+		return occlusionEnhancedModel
+	}
+	return ""
+}
+
+// readOcclusionImage reads the image from image_path or image_url and
+// returns its name and contents
+func (a *AnkiMCPServer) readOcclusionImage(args map[string]interface{}) (string, []byte, *mcp.CallToolResult) {
+	imagePath, _ := args["image_path"].(string)
+	imageURL, _ := args["image_url"].(string)
+	switch {
+	case imagePath != "" && imageURL != "":
+		return "", nil, a.errorf("Pass either image_path or image_url, not both")
+	case imagePath != "":
+		data, err := os.ReadFile(imagePath)
+		if err != nil {
+			return "", nil, a.errorf("Failed to read image file: %v", err)
+		}
+		return filepath.Base(imagePath), data, nil
+	case imageURL != "":
+		name, body, err := openMediaURL(imageURL, "image/")
+		if err != nil {
+			return "", nil, a.errorf("Failed to download %s: %v", imageURL, err)
+		}
+		defer body.Close()
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return "", nil, a.errorf("Failed to download %s: %v", imageURL, err)
+		}
+		return name, data, nil
+	}
+	return "", nil, a.errorf("image_path or image_url is required")
+}
+
+// handleCreateImageOcclusionCard stores an image and creates image occlusion
+// notes hiding the given rectangles
+func (a *AnkiMCPServer) handleCreateImageOcclusionCard(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	deckName, ok := args["deck"].(string)
+	if !ok || strings.TrimSpace(deckName) == "" {
+		return a.errorf("deck is required"), nil
+	}
+	tags := stringSliceValue(args, "tags")
+	for _, tag := range tags {
+		if !validTag(tag) {
+			return a.errorf("Invalid tag %q: tags can't be empty or contain spaces", tag), nil
+		}
+	}
+	options, errResult := a.noteOptions(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+	mode, _ := args["hide"].(string)
+	if mode == "" {
+		mode = occludeAll
+	}
+	if mode != occludeAll && mode != occludeOne {
+		return a.errorf("Invalid hide %q: use all or one", mode), nil
+	}
+
+	// Find the note type before storing the image
+	modelName, _ := args["model_name"].(string)
+	if strings.TrimSpace(modelName) == "" {
+		models, err := a.ankiClient.GetModelNames()
+		if err != nil {
+			return a.errorf("Failed to get note types: %v", err), nil
+		}
+		switch {
+		case slices.Contains(models, occlusionModel):
+			modelName = occlusionModel
+		case slices.Contains(models, occlusionEnhancedModel):
+			modelName = occlusionEnhancedModel
+		default:
+			return a.errorf("No image occlusion note type found. Use Anki 23.10 or later, install the Image Occlusion Enhanced add-on, or pass model_name."), nil
+		}
+	} else if errResult := a.checkModel(modelName); errResult != nil {
+		return errResult, nil
+	}
+	modelFields, err := a.ankiClient.GetModelFieldNames(modelName)
+	if err != nil {
+		return a.errorf("Failed to get fields: %v", err), nil
+	}
+	kind := occlusionKind(modelFields)
+	if kind == "" {
+		return a.errorf("Note type %s is not an image occlusion note type", modelName), nil
+	}
+
+	imageName, data, errResult := a.readOcclusionImage(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+	width, height := 0, 0
+	if config, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		width, height = config.Width, config.Height
+	}
+	items, _ := args["occlusions"].([]interface{})
+	units, _ := args["units"].(string)
+	shapes, cards, err := parseOcclusions(items, units, width, height)
+	if err != nil {
+		return a.errorf("Invalid occlusions: %v", err), nil
+	}
+	if kind == occlusionEnhancedModel && (width <= 0 || height <= 0) {
+		return a.errorf("Image Occlusion Enhanced needs a PNG, JPEG or GIF image to draw its masks"), nil
+	}
+
+	// Send the cards to the deck chosen by the tag routing rules
+	var route *RoutingRule
+	if a.routing.Auto {
+		if rule, ok := a.routing.deckFor(tags); ok && rule.Deck != deckName {
+			if err := a.ankiClient.CreateDeck(rule.Deck); err != nil {
+				return a.errorf("Failed to create deck: %v", err), nil
+			}
+			deckName = rule.Deck
+			route = &rule
+		}
+	}
+
+	stored, err := a.ankiClient.StoreMediaFile(imageName, data)
+	if err != nil {
+		return a.errorf("Failed to store image: %v", err), nil
+	}
+	if err := a.ankiClient.VerifyMedia(stored); err != nil {
+		return a.errorf("Failed to verify stored image: %v", err), nil
+	}
+	header, _ := args["header"].(string)
+	backExtra, _ := args["back_extra"].(string)
+	setField := func(fields map[string]string, name, value string) {
+		if value != "" && slices.Contains(modelFields, name) {
+			fields[name] = value
+		}
+	}
+
+	var noteIDs []int64
+	if kind == occlusionModel {
+		fields := map[string]string{
+			"Occlusion": occlusionText(shapes, mode),
+			"Image":     fmt.Sprintf(`<img src="%s">`, stored.Filename),
+		}
+		setField(fields, "Header", header)
+		setField(fields, "Back Extra", backExtra)
+		noteID, err := a.ankiClient.AddNote(Note{
+			DeckName:  deckName,
+			ModelName: modelName,
+			Fields:    fields,
+			Tags:      tags,
+			Options:   options,
+		})
+		if err != nil {
+			return a.errorf("Failed to create card: %v", err), nil
+		}
+		noteIDs = append(noteIDs, noteID)
+	} else {
+		// Image Occlusion Enhanced ties the notes of an image together by an
+		// ID telling the mode: ao hides all, oa hides one
+		random := make([]byte, 16)
+		_, _ = rand.Read(random)
+		prefix := "oa"
+		if mode == occludeAll {
+			prefix = "ao"
+		}
+		id := hex.EncodeToString(random) + "-" + prefix
+		base := strings.TrimSuffix(stored.Filename, path.Ext(stored.Filename))
+
+		storeMask := func(name, svg string) (string, error) {
+			media, err := a.ankiClient.StoreMediaFile(name, []byte(svg))
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf(`<img src="%s">`, media.Filename), nil
+		}
+		original, err := storeMask(fmt.Sprintf("%s-%s-O.svg", base, id), occlusionMask(shapes, width, height, 0, false))
+		if err != nil {
+			return a.errorf("Failed to store mask: %v", err), nil
+		}
+		for card := 1; card <= cards; card++ {
+			if ctx.Err() != nil {
+				return a.errorf("Cancelled after creating %d of %d note(s)", len(noteIDs), cards), nil
+			}
+			shown := cardShapes(shapes, card, mode)
+			question, err := storeMask(fmt.Sprintf("%s-%s-%d-Q.svg", base, id, card), occlusionMask(shown, width, height, card, true))
+			if err != nil {
+				return a.errorf("Failed to store mask: %v", err), nil
+			}
+			answer, err := storeMask(fmt.Sprintf("%s-%s-%d-A.svg", base, id, card), occlusionMask(shown, width, height, card, false))
+			if err != nil {
+				return a.errorf("Failed to store mask: %v", err), nil
+			}
+			fields := map[string]string{
+				"Image":         fmt.Sprintf(`<img src="%s">`, stored.Filename),
+				"Question Mask": question,
+				"Answer Mask":   answer,
+			}
+			setField(fields, "ID (hidden)", fmt.Sprintf("%s-%d", id, card))
+			setField(fields, "Original Mask", original)
+			setField(fields, "Header", header)
+			setField(fields, "Remarks", backExtra)
+			noteID, err := a.ankiClient.AddNote(Note{
+				DeckName:  deckName,
+				ModelName: modelName,
+				Fields:    fields,
+				Tags:      tags,
+				Options:   options,
+			})
+			if err != nil {
+				return a.errorf("Failed to create card: %v", err), nil
+			}
+			noteIDs = append(noteIDs, noteID)
+		}
+	}
+
+	out := a.newOutput()
+	if kind == occlusionModel {
+		out.Line(a.t("Created image occlusion note (ID: %d) with %d card(s)", noteIDs[0], cards))
+	} else {
+		ids := make([]string, len(noteIDs))
+		for i, id := range noteIDs {
+			ids[i] = strconv.FormatInt(id, 10)
+		}
+		out.Line(a.t("Created %d image occlusion note(s) with %s: %s", len(noteIDs), modelName, strings.Join(ids, ", ")))
+	}
+	out.Line(a.t("Image: %s, %d rectangle(s)", stored.Filename, len(shapes)))
+	if route != nil {
+		out.Line(a.t("Routed to deck %s by the tag rule %s", route.Deck, route.Tag))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestPNG writes a blank PNG of the given size and returns its path
+func writeTestPNG(t *testing.T, width, height int) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "heart.png")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseOcclusions(t *testing.T) {
+	items := []interface{}{
+		map[string]interface{}{"left": float64(20), "top": float64(10), "width": float64(40), "height": float64(20)},
+		map[string]interface{}{"left": float64(0), "top": float64(50), "width": float64(10), "height": float64(10), "group": float64(7)},
+		map[string]interface{}{"left": float64(90), "top": float64(80), "width": float64(10), "height": float64(20), "group": float64(7)},
+	}
+	shapes, cards, err := parseOcclusions(items, "", 200, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cards != 2 || shapes[0].Card != 1 || shapes[1].Card != 2 || shapes[2].Card != 2 {
+		t.Errorf("Unexpected numbering: %d card(s), %+v", cards, shapes)
+	}
+	if shapes[0].Left != 0.1 || shapes[0].Top != 0.1 || shapes[0].Width != 0.2 || shapes[0].Height != 0.2 {
+		t.Errorf("Expected pixels converted to fractions, got %+v", shapes[0])
+	}
+
+	want := "{{c1::image-occlusion:rect:left=0.1:top=0.1:width=0.2:height=0.2:oi=1}}<br>" +
+		"{{c2::image-occlusion:rect:left=0:top=0.5:width=0.05:height=0.1:oi=1}}<br>" +
+		"{{c2::image-occlusion:rect:left=0.45:top=0.8:width=0.05:height=0.2:oi=1}}"
+	if got := occlusionText(shapes, occludeAll); got != want {
+		t.Errorf("occlusionText() = %q, want %q", got, want)
+	}
+
+	outside := []interface{}{map[string]interface{}{"left": 0.5, "top": 0.5, "width": 0.6, "height": 0.1}}
+	if _, _, err := parseOcclusions(outside, unitsFraction, 0, 0); err == nil {
+		t.Error("Expected an error for a rectangle outside the image")
+	}
+	if _, _, err := parseOcclusions(items, unitsPixels, 0, 0); err == nil {
+		t.Error("Expected an error for pixels without the image size")
+	}
+}
+
+func TestOcclusionMask(t *testing.T) {
+	shapes := []occlusion{{Left: 0.1, Top: 0.1, Width: 0.2, Height: 0.2, Card: 1}, {Left: 0.5, Top: 0.5, Width: 0.1, Height: 0.1, Card: 2}}
+
+	question := occlusionMask(cardShapes(shapes, 1, occludeAll), 200, 100, 1, true)
+	if !strings.Contains(question, `<rect x="20" y="10" width="40" height="20" fill="#FF7E7E"`) || strings.Count(question, "<rect") != 2 {
+		t.Errorf("Unexpected question mask: %s", question)
+	}
+	if answer := occlusionMask(cardShapes(shapes, 1, occludeAll), 200, 100, 1, false); strings.Count(answer, "<rect") != 1 {
+		t.Errorf("Expected the answer mask to keep only the other rectangle, got %s", answer)
+	}
+	if question := occlusionMask(cardShapes(shapes, 1, occludeOne), 200, 100, 1, true); strings.Count(question, "<rect") != 1 {
+		t.Errorf("Expected hide one to show only the asked rectangle, got %s", question)
+	}
+}
+
+func TestCreateImageOcclusionCard(t *testing.T) {
+	server, mock := newMockServer(t)
+	imagePath := writeTestPNG(t, 200, 100)
+	occlusions := []interface{}{
+		map[string]interface{}{"left": float64(20), "top": float64(10), "width": float64(40), "height": float64(20)},
+		map[string]interface{}{"left": float64(100), "top": float64(50), "width": float64(20), "height": float64(10)},
+	}
+
+	args := map[string]interface{}{"deck": "Default", "image_path": imagePath, "occlusions": occlusions}
+	if text, isErr := callTool(t, server.handleCreateImageOcclusionCard, args); !isErr || !strings.Contains(text, "No image occlusion note type") {
+		t.Errorf("Expected an error without an image occlusion note type, got %s", text)
+	}
+
+	mock.models[occlusionModel] = &mockModel{
+		Fields: []string{"Occlusion", "Image", "Header", "Back Extra", "Comments"},
+		Templates: []CardTemplate{
+			{Name: "Image Occlusion", Front: "{{cloze:Occlusion}}", Back: "{{cloze:Occlusion}}"},
+		},
+		Cloze: true,
+	}
+	args["header"] = "Heart"
+	text, isErr := callTool(t, server.handleCreateImageOcclusionCard, args)
+	if isErr || !strings.Contains(text, "with 2 card(s)") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	noteIDs, _ := server.ankiClient.FindNotes(`"note:Image Occlusion"`)
+	infos, _ := server.ankiClient.GetNotesInfo(noteIDs)
+	if len(infos) != 1 {
+		t.Fatalf("Expected one note, got %d", len(infos))
+	}
+	if occ, _ := noteField(infos[0], "Occlusion"); !strings.Contains(occ, "{{c2::image-occlusion:rect:left=0.5:top=0.5") {
+		t.Errorf("Unexpected Occlusion field: %s", occ)
+	}
+	if img, _ := noteField(infos[0], "Image"); img != `<img src="heart.png">` {
+		t.Errorf("Unexpected Image field: %s", img)
+	}
+	if _, ok := mock.media["heart.png"]; !ok {
+		t.Error("Expected the image to be stored")
+	}
+
+	mock.models[occlusionEnhancedModel] = &mockModel{
+		Fields: []string{"ID (hidden)", "Header", "Image", "Question Mask", "Footer", "Remarks", "Sources", "Extra 1", "Extra 2", "Answer Mask", "Original Mask"},
+		Templates: []CardTemplate{
+			{Name: "IO Card", Front: "{{Image}}{{Question Mask}}", Back: "{{Image}}{{Answer Mask}}"},
+		},
+	}
+	args["model_name"] = occlusionEnhancedModel
+	args["hide"] = occludeOne
+	text, isErr = callTool(t, server.handleCreateImageOcclusionCard, args)
+	if isErr || !strings.Contains(text, "Created 2 image occlusion note(s)") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	masks := 0
+	for name := range mock.media {
+		if strings.HasSuffix(name, ".svg") {
+			masks++
+		}
+	}
+	if masks != 5 {
+		t.Errorf("Expected an original, two question and two answer masks, got %d", masks)
+	}
+
+	args["model_name"] = "Basic"
+	if text, isErr := callTool(t, server.handleCreateImageOcclusionCard, args); !isErr || !strings.Contains(text, "not an image occlusion note type") {
+		t.Errorf("Expected an error for Basic, got %s", text)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Output styles selectable with ANKI_MCP_OUTPUT
+const (
+	outputMarkdown = "markdown"
+	outputPlain    = "plain"
+)
+
+// textOutput builds tool output text. In markdown style headings and list items
+// are decorated; in plain style every entry is written as a bare line, for
+// clients that show tool output verbatim or pipe it into other programs.
+type textOutput struct {
+	plain bool
+	lines []string
+}
+
+// newOutput creates an output builder using the configured output style
+func (a *AnkiMCPServer) newOutput() *textOutput {
+	return &textOutput{plain: a.plainOutput}
+}
+
+// Heading adds a section heading
+func (o *textOutput) Heading(text string) {
+	if o.plain {
+		o.lines = append(o.lines, text)
+		return
+	}
+	if len(o.lines) > 0 {
+		o.lines = append(o.lines, "")
+	}
+	o.lines = append(o.lines, "## "+text)
+}
+
+// Item adds a list entry
+func (o *textOutput) Item(text string) {
+	if o.plain {
+		o.lines = append(o.lines, text)
+		return
+	}
+	o.lines = append(o.lines, "- "+text)
+}
+
+// NestedItem adds a list entry indented by depth levels, for trees. Plain
+// style keeps the indentation, which carries the structure.
+func (o *textOutput) NestedItem(depth int, text string) {
+	indent := strings.Repeat("  ", depth)
+	if o.plain {
+		o.lines = append(o.lines, indent+text)
+		return
+	}
+	o.lines = append(o.lines, indent+"- "+text)
+}
+
+// Line adds a line of text
+func (o *textOutput) Line(text string) {
+	o.lines = append(o.lines, text)
+}
+
+// String returns the collected output
+func (o *textOutput) String() string {
+	return strings.Join(o.lines, "\n")
+}
+
+// withFormat adds the format parameter accepted by read tools
+func withFormat() mcp.ToolOption {
+	return mcp.WithString("format",
+		mcp.Description("Optional: Output format: text (default, human-readable summary) or json (compact JSON document)"),
+		mcp.Enum("text", "json"),
+	)
+}
+
+// wantsJSON reports whether the caller asked for JSON output
+func wantsJSON(request mcp.CallToolRequest) bool {
+	format, _ := request.GetArguments()["format"].(string)
+	return strings.EqualFold(format, "json")
+}
+
+// jsonResult creates a result holding data as a compact JSON document
+func (a *AnkiMCPServer) jsonResult(data interface{}) *mcp.CallToolResult {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return a.errorf("Failed to encode JSON: %v", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: string(encoded),
+			},
+		},
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTextOutputStyles(t *testing.T) {
+	build := func(plain bool) string {
+		out := &textOutput{plain: plain}
+		out.Heading("Decks (2)")
+		out.Item("Default")
+		out.Item("Spanish")
+		out.Heading("Notes")
+		out.Line("Done")
+		return out.String()
+	}
+
+	markdown := "## Decks (2)\n- Default\n- Spanish\n\n## Notes\nDone"
+	if got := build(false); got != markdown {
+		t.Errorf("Unexpected markdown output:\n%s", got)
+	}
+
+	plain := "Decks (2)\nDefault\nSpanish\nNotes\nDone"
+	if got := build(true); got != plain {
+		t.Errorf("Unexpected plain output:\n%s", got)
+	}
+}
+
+func TestPlainOutputConfig(t *testing.T) {
+	t.Setenv("ANKI_MCP_OUTPUT", "PLAIN")
+	if !NewAnkiMCPServer().plainOutput {
+		t.Error("Expected plain output to be enabled")
+	}
+
+	t.Setenv("ANKI_MCP_OUTPUT", "")
+	if NewAnkiMCPServer().plainOutput {
+		t.Error("Expected markdown output by default")
+	}
+}
+
+func TestFormatParameter(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	markdown, isErr := callTool(t, server.handleTagStats, map[string]interface{}{"deck": "Spanish"})
+	if isErr || !strings.HasPrefix(markdown, "## Tag statistics for Spanish") || !strings.Contains(markdown, "\n- spanish: ") {
+		t.Errorf("Unexpected markdown output: %s", markdown)
+	}
+
+	server.plainOutput = true
+	plain, _ := callTool(t, server.handleTagStats, map[string]interface{}{"deck": "Spanish"})
+	if !strings.HasPrefix(plain, "Tag statistics for Spanish") || !strings.Contains(plain, "\nspanish: ") || strings.Contains(plain, "- ") {
+		t.Errorf("Unexpected plain output: %s", plain)
+	}
+
+	// JSON output doesn't depend on the output style
+	text, _ := callTool(t, server.handleTagStats, map[string]interface{}{"deck": "Spanish", "format": "json"})
+	var stats struct {
+		Deck      string     `json:"deck"`
+		TotalTags int        `json:"total_tags"`
+		Tags      []tagStats `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(text), &stats); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", text, err)
+	}
+	if stats.Deck != "Spanish" || stats.TotalTags == 0 || len(stats.Tags) != stats.TotalTags || stats.Tags[0].Cards == 0 {
+		t.Errorf("Unexpected JSON output: %s", text)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// cardWritingRules are the authoring guidelines shared by the card prompts
+const cardWritingRules = `Follow these rules for every card:
+- Test one fact per card, and keep answers short.
+- Make the question unambiguous, so only one answer is right.
+- Add context (an example sentence, a hint or the topic) when the question could be read in several ways.
+- Write the cards in the language of the source material unless the user asks otherwise.`
+
+// registerPrompts registers the card authoring prompts with the MCP server
+func (a *AnkiMCPServer) registerPrompts(s *server.MCPServer) {
+	s.AddPrompt(mcp.NewPrompt("generate-flashcards-from-text",
+		mcp.WithPromptDescription("Turn a text into a set of question and answer flashcards"),
+		mcp.WithArgument("text",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("Text to make flashcards from"),
+		),
+		mcp.WithArgument("deck",
+			mcp.ArgumentDescription("Deck to add the cards to (default: chosen from the existing decks)"),
+		),
+		mcp.WithArgument("model",
+			mcp.ArgumentDescription("Note type of the cards (default: Basic)"),
+		),
+		mcp.WithArgument("count",
+			mcp.ArgumentDescription("Approximate number of cards to create"),
+		),
+	), a.handleGenerateFlashcardsPrompt)
+
+	s.AddPrompt(mcp.NewPrompt("improve-card-wording",
+		mcp.WithPromptDescription("Review an existing note and suggest clearer wording, e.g. for a leech"),
+		mcp.WithArgument("note_id",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("ID of the note to improve"),
+		),
+	), a.handleImproveCardPrompt)
+
+	s.AddPrompt(mcp.NewPrompt("make-cloze-from-passage",
+		mcp.WithPromptDescription("Turn a passage into cloze deletion cards hiding its key terms"),
+		mcp.WithArgument("passage",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("Passage to turn into cloze deletions"),
+		),
+		mcp.WithArgument("deck",
+			mcp.ArgumentDescription("Deck to add the note to (default: chosen from the existing decks)"),
+		),
+		mcp.WithArgument("model",
+			mcp.ArgumentDescription("Cloze note type to use (default: Cloze)"),
+		),
+	), a.handleMakeClozePrompt)
+}
+
+// handleGenerateFlashcardsPrompt builds the generate-flashcards-from-text prompt
+func (a *AnkiMCPServer) handleGenerateFlashcardsPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	args := request.Params.Arguments
+
+	text := strings.TrimSpace(args["text"])
+	if text == "" {
+		return nil, fmt.Errorf("text is required")
+	}
+	model := strings.TrimSpace(args["model"])
+	if model == "" {
+		model = "Basic"
+	}
+
+	var b strings.Builder
+	b.WriteString("Create Anki flashcards from the text below.\n\n")
+	b.WriteString(a.promptDeckHint(args["deck"]) + "\n")
+	fmt.Fprintf(&b, "Use the note type %q.\n", model)
+	if count, err := strconv.Atoi(strings.TrimSpace(args["count"])); err == nil && count > 0 {
+		fmt.Fprintf(&b, "Aim for about %d cards, covering the most important facts first.\n", count)
+	}
+	b.WriteString("First run check_duplicates on the cards to skip ones the collection already has, then add the rest in one create_cards_bulk call.\n\n")
+	b.WriteString(cardWritingRules + "\n\n")
+	b.WriteString("Text:\n" + text)
+
+	return mcp.NewGetPromptResult("Flashcards from text", []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(b.String())),
+	}), nil
+}
+
+// handleImproveCardPrompt builds the improve-card-wording prompt, including
+// the note's current content and review state
+func (a *AnkiMCPServer) handleImproveCardPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	noteID, err := strconv.ParseInt(strings.TrimSpace(request.Params.Arguments["note_id"]), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("note_id must be a note ID")
+	}
+	note, err := a.noteResource(noteID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(note, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Improve the wording of Anki note %d, shown below with its fields and the review state of its cards.\n\n", noteID)
+	b.WriteString("Explain what makes the note hard to remember, judging from its wording and its lapses, and propose new field values. ")
+	b.WriteString("If it tests several facts, propose splitting it into several notes instead. ")
+	b.WriteString("Only call update_note once the user agrees; with many lapses, offer forget_cards afterwards so the reworded cards start fresh.\n\n")
+	b.WriteString(cardWritingRules + "\n\n")
+	b.WriteString("Note:\n" + string(data))
+
+	return mcp.NewGetPromptResult(fmt.Sprintf("Improve note %d", noteID), []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(b.String())),
+	}), nil
+}
+
+// handleMakeClozePrompt builds the make-cloze-from-passage prompt
+func (a *AnkiMCPServer) handleMakeClozePrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	args := request.Params.Arguments
+
+	passage := strings.TrimSpace(args["passage"])
+	if passage == "" {
+		return nil, fmt.Errorf("passage is required")
+	}
+	model := strings.TrimSpace(args["model"])
+	if model == "" {
+		model = "Cloze"
+	}
+
+	var b strings.Builder
+	b.WriteString("Turn the passage below into an Anki cloze note.\n\n")
+	b.WriteString(a.promptDeckHint(args["deck"]) + "\n")
+	fmt.Fprintf(&b, "Call create_cloze_card with model_name %q, passing the passage as text and the terms to hide as targets. ", model)
+	b.WriteString("Hide the key terms a learner should recall (names, numbers, definitions), not filler words, and add a hint as \"term::hint\" where a blank is ambiguous. ")
+	b.WriteString("Keep the passage short enough to read in one go; split long passages into several notes.\n\n")
+	b.WriteString("Passage:\n" + passage)
+
+	return mcp.NewGetPromptResult("Cloze deletions from a passage", []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(b.String())),
+	}), nil
+}
+
+// promptDeckHint tells the model which deck to use: the given one, or else the
+// best fit among the existing decks
+func (a *AnkiMCPServer) promptDeckHint(deck string) string {
+	if deck = strings.TrimSpace(deck); deck != "" {
+		return fmt.Sprintf("Add the cards to the deck %q.", deck)
+	}
+	decks, err := a.ankiClient.GetDeckNames()
+	if err != nil || len(decks) == 0 {
+		return "Ask the user which deck to add the cards to."
+	}
+	return fmt.Sprintf("Add the cards to the best fitting of the existing decks (%s), or ask the user if none fits.", strings.Join(decks, ", "))
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// promptText gets a prompt with the given arguments and returns its message text
+func promptText(t *testing.T, handler func(context.Context, mcp.GetPromptRequest) (*mcp.GetPromptResult, error), args map[string]string) string {
+	t.Helper()
+	var request mcp.GetPromptRequest
+	request.Params.Arguments = args
+	result, err := handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("prompt failed: %v", err)
+	}
+	text, ok := result.Messages[0].Content.(mcp.TextContent)
+	if !ok || result.Messages[0].Role != mcp.RoleUser {
+		t.Fatalf("Unexpected message %#v", result.Messages[0])
+	}
+	return text.Text
+}
+
+func TestCardAuthoringPrompts(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	text := promptText(t, server.handleGenerateFlashcardsPrompt, map[string]string{"text": "Water boils at 100 °C.", "count": "3"})
+	if !strings.Contains(text, "existing decks (Default, Spanish, Spanish::Grammar, Spanish::Vocabulary)") || !strings.Contains(text, `note type "Basic"`) ||
+		!strings.Contains(text, "about 3 cards") || !strings.HasSuffix(text, "Water boils at 100 °C.") {
+		t.Errorf("Unexpected prompt: %s", text)
+	}
+
+	text = promptText(t, server.handleMakeClozePrompt, map[string]string{"passage": "Berlin is the capital of Germany.", "deck": "Geography"})
+	if !strings.Contains(text, `deck "Geography"`) || !strings.Contains(text, `model_name "Cloze"`) {
+		t.Errorf("Unexpected prompt: %s", text)
+	}
+
+	var noteID int64
+	for id, note := range mock.notes {
+		if note.Fields["Front"] == "la casa" {
+			noteID = id
+		}
+	}
+	text = promptText(t, server.handleImproveCardPrompt, map[string]string{"note_id": fmt.Sprint(noteID)})
+	if !strings.Contains(text, fmt.Sprintf("Anki note %d", noteID)) || !strings.Contains(text, `"value": "la casa"`) {
+		t.Errorf("Unexpected prompt: %s", text)
+	}
+
+	var request mcp.GetPromptRequest
+	request.Params.Arguments = map[string]string{"note_id": "1"}
+	if _, err := server.handleImproveCardPrompt(context.Background(), request); err == nil {
+		t.Error("Expected an error for an unknown note")
+	}
+}
@@ -0,0 +1,478 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RecipeConfig is a named preset for creating cards of one kind, so cards
+// made over many sessions share their deck, note type, tags and layout
+type RecipeConfig struct {
+	// Name is passed as recipe to create_from_recipe
+	Name string `json:"name"`
+	// Description tells what the recipe is for
+	Description string `json:"description"`
+	// Deck receives the cards unless a call names another one
+	Deck string `json:"deck"`
+	// Model is the note type of the cards
+	Model string `json:"model"`
+	// Tags are added to every note
+	Tags []string `json:"tags"`
+	// Fields maps note fields to templates in which {{name}} is replaced by
+	// the value of the input name, and {{#name}}...{{/name}} is kept only
+	// when name has a value
+	Fields map[string]string `json:"fields"`
+	// Media places and names the media files of the cards
+	Media RecipeMedia `json:"media"`
+}
+
+// RecipeMedia are the media conventions of a recipe
+type RecipeMedia struct {
+	// ImageField gets the image, above its text (default: the first field)
+	ImageField string `json:"image_field"`
+	// AudioField gets the audio, below its text (default: the first field)
+	AudioField string `json:"audio_field"`
+	// Prefix is put in front of the names of stored files, e.g. es_
+	Prefix string `json:"prefix"`
+	// MaxDimension and Quality shrink images as in add_media
+	MaxDimension int `json:"max_dimension"`
+	Quality      int `json:"quality"`
+}
+
+// recipePlaceholderPattern matches {{name}}, {{#name}} and {{/name}}. Names
+// can't contain ::, so cloze deletions written in a template stay text.
+var recipePlaceholderPattern = regexp.MustCompile(`\{\{\s*([#/]?)\s*([A-Za-z0-9_-]+)\s*\}\}`)
+
+// recipeToken is a piece of a field template: text, an input, or the start
+// or end of a section
+type recipeToken struct {
+	kind string // "", "#" or "/" for text or inputs, section starts and ends
+	text string
+	name string
+}
+
+// parseRecipeTemplate splits a field template into tokens, checking that
+// sections are closed in order
+func parseRecipeTemplate(tmpl string) ([]recipeToken, error) {
+	var tokens []recipeToken
+	var open []string
+	last := 0
+	for _, m := range recipePlaceholderPattern.FindAllStringSubmatchIndex(tmpl, -1) {
+		if m[0] > last {
+			tokens = append(tokens, recipeToken{text: tmpl[last:m[0]]})
+		}
+		last = m[1]
+		kind, name := tmpl[m[2]:m[3]], tmpl[m[4]:m[5]]
+		switch kind {
+		case "#":
+			open = append(open, name)
+		case "/":
+			if len(open) == 0 || open[len(open)-1] != name {
+				return nil, fmt.Errorf("{{/%s}} closes no {{#%s}}", name, name)
+			}
+			open = open[:len(open)-1]
+		}
+		tokens = append(tokens, recipeToken{kind: kind, name: name})
+	}
+	if len(open) > 0 {
+		return nil, fmt.Errorf("{{#%s}} is not closed", open[len(open)-1])
+	}
+	if last < len(tmpl) {
+		tokens = append(tokens, recipeToken{text: tmpl[last:]})
+	}
+	return tokens, nil
+}
+
+// renderRecipeTemplate fills a field template with input values
+func renderRecipeTemplate(tokens []recipeToken, values map[string]string) string {
+	var b strings.Builder
+	hidden := 0
+	for _, token := range tokens {
+		switch {
+		case token.kind == "#":
+			if hidden > 0 || strings.TrimSpace(values[token.name]) == "" {
+				hidden++
+			}
+		case token.kind == "/":
+			if hidden > 0 {
+				hidden--
+			}
+		case hidden > 0:
+		case token.name != "":
+			b.WriteString(values[token.name])
+		default:
+			b.WriteString(token.text)
+		}
+	}
+	return b.String()
+}
+
+// inputs returns the inputs of a recipe: required ones are used outside of
+// sections, optional ones only inside
+func (r RecipeConfig) inputs() (required, optional []string) {
+	seenRequired, seenOptional := make(map[string]bool), make(map[string]bool)
+	for _, tmpl := range r.Fields {
+		tokens, _ := parseRecipeTemplate(tmpl)
+		depth := 0
+		for _, token := range tokens {
+			switch token.kind {
+			case "#":
+				seenOptional[token.name] = true
+				depth++
+			case "/":
+				depth--
+			default:
+				if token.name == "" {
+					continue
+				}
+				if depth == 0 {
+					seenRequired[token.name] = true
+				} else {
+					seenOptional[token.name] = true
+				}
+			}
+		}
+	}
+	for name := range seenRequired {
+		required = append(required, name)
+	}
+	for name := range seenOptional {
+		if !seenRequired[name] {
+			optional = append(optional, name)
+		}
+	}
+	sort.Strings(required)
+	sort.Strings(optional)
+	return required, optional
+}
+
+// validateRecipes checks that every recipe has a unique name, a note type and
+// valid field templates
+func validateRecipes(recipes []RecipeConfig) error {
+	seen := make(map[string]bool)
+	for i, recipe := range recipes {
+		name := strings.TrimSpace(recipe.Name)
+		if name == "" {
+			return fmt.Errorf("recipe %d needs a name", i+1)
+		}
+		if seen[name] {
+			return fmt.Errorf("recipe %d: the name %q is already taken", i+1, name)
+		}
+		seen[name] = true
+		if strings.TrimSpace(recipe.Model) == "" {
+			return fmt.Errorf("recipe %s needs a model", name)
+		}
+		if len(recipe.Fields) == 0 {
+			return fmt.Errorf("recipe %s needs fields", name)
+		}
+		for field, tmpl := range recipe.Fields {
+			if _, err := parseRecipeTemplate(tmpl); err != nil {
+				return fmt.Errorf("recipe %s, field %s: %w", name, field, err)
+			}
+		}
+		for _, tag := range recipe.Tags {
+			if !validTag(tag) {
+				return fmt.Errorf("recipe %s: invalid tag %q", name, tag)
+			}
+		}
+		if d := recipe.Media.MaxDimension; d < 0 || d > maxImageDimension {
+			return fmt.Errorf("recipe %s: max_dimension must be between 1 and %d", name, maxImageDimension)
+		}
+		if q := recipe.Media.Quality; q < 0 || q > 100 {
+			return fmt.Errorf("recipe %s: quality must be between 1 and 100", name)
+		}
+	}
+	return nil
+}
+
+// recipe returns the recipe with the given name
+func (a *AnkiMCPServer) recipe(name string) (RecipeConfig, bool) {
+	for _, recipe := range a.recipes {
+		if recipe.Name == name {
+			return recipe, true
+		}
+	}
+	return RecipeConfig{}, false
+}
+
+// registerRecipeTools registers the card recipe tools with the MCP server
+func (a *AnkiMCPServer) registerRecipeTools(s *server.MCPServer) {
+	// Tool: List Recipes
+	listRecipesTool := mcp.NewTool("list_recipes",
+		mcp.WithDescription("List the card recipes from the config file: named presets with a deck, note type, tags, field layout and media conventions. "+
+			"Use a recipe with create_from_recipe whenever one fits, so cards stay consistent across sessions."),
+		withFormat(),
+	)
+	a.addTool(s, listRecipesTool, (*AnkiMCPServer).handleListRecipes)
+
+	// Tool: Create From Recipe
+	createFromRecipeTool := mcp.NewTool("create_from_recipe",
+		mcp.WithDescription("Create a card from a recipe listed by list_recipes. The recipe decides the note type, deck, tags and how the inputs are laid out in the fields; "+
+			"the call only passes the inputs and optional media."),
+		mcp.WithString("recipe",
+			mcp.Required(),
+			mcp.Description("Name of the recipe"),
+		),
+		mcp.WithObject("values",
+			mcp.Required(),
+			mcp.Description("Input values by name, e.g. {\"word\": \"perro\", \"meaning\": \"dog\"}; list_recipes shows the inputs of each recipe"),
+		),
+		mcp.WithString("deck",
+			mcp.Description("Optional: Deck to use instead of the recipe's"),
+		),
+		mcp.WithArray("tags",
+			mcp.Description("Optional: Tags added to the recipe's tags"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("image_path",
+			mcp.Description("Optional: Path to an image, placed in the recipe's image field"),
+		),
+		mcp.WithString("image_url",
+			mcp.Description("Optional: URL of an image to download instead of image_path"),
+		),
+		mcp.WithString("audio_path",
+			mcp.Description("Optional: Path to an audio file, placed in the recipe's audio field"),
+		),
+		mcp.WithString("audio_url",
+			mcp.Description("Optional: URL of an audio file to download instead of audio_path"),
+		),
+		withDuplicateOptions(),
+	)
+	a.addChangingTool(s, createFromRecipeTool, (*AnkiMCPServer).handleCreateFromRecipe)
+}
+
+// handleListRecipes lists the configured recipes and their inputs
+func (a *AnkiMCPServer) handleListRecipes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if wantsJSON(request) {
+		type recipeJSON struct {
+			RecipeConfig
+			Required []string `json:"required_inputs"`
+			Optional []string `json:"optional_inputs"`
+		}
+		recipes := make([]recipeJSON, len(a.recipes))
+		for i, recipe := range a.recipes {
+			required, optional := recipe.inputs()
+			recipes[i] = recipeJSON{recipe, required, optional}
+		}
+		return a.jsonResult(map[string]interface{}{"recipes": recipes}), nil
+	}
+
+	out := a.newOutput()
+	if len(a.recipes) == 0 {
+		out.Line(a.t("No recipes configured. Add recipes to the config file: %s", a.configFile))
+	}
+	for _, recipe := range a.recipes {
+		out.Heading(recipe.Name)
+		if recipe.Description != "" {
+			out.Line(recipe.Description)
+		}
+		deck := recipe.Deck
+		if deck == "" {
+			deck = a.t("(given by each call)")
+		}
+		out.Item(a.t("Deck: %s", deck))
+		out.Item(a.t("Note type: %s", recipe.Model))
+		if len(recipe.Tags) > 0 {
+			out.Item(a.t("Tags: %s", strings.Join(recipe.Tags, " ")))
+		}
+		required, optional := recipe.inputs()
+		if len(required) > 0 {
+			out.Item(a.t("Required inputs: %s", strings.Join(required, ", ")))
+		}
+		if len(optional) > 0 {
+			out.Item(a.t("Optional inputs: %s", strings.Join(optional, ", ")))
+		}
+		imageField, audioField := recipe.Media.ImageField, recipe.Media.AudioField
+		if imageField == "" {
+			imageField = a.t("the first field")
+		}
+		if audioField == "" {
+			audioField = a.t("the first field")
+		}
+		out.Item(a.t("Image in %s, audio in %s", imageField, audioField))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleCreateFromRecipe fills a recipe's field templates with the given
+// values and creates the note
+func (a *AnkiMCPServer) handleCreateFromRecipe(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	name, _ := args["recipe"].(string)
+	recipe, ok := a.recipe(name)
+	if !ok {
+		names := make([]string, len(a.recipes))
+		for i, r := range a.recipes {
+			names[i] = r.Name
+		}
+		return a.errorf("Recipe not found: %s. Recipes: %s", name, strings.Join(names, ", ")), nil
+	}
+
+	values := make(map[string]string)
+	for input, value := range objectValue(args, "values") {
+		text, ok := value.(string)
+		if !ok {
+			return a.errorf("Value %s must be a string", input), nil
+		}
+		values[input] = text
+	}
+	required, optional := recipe.inputs()
+	var missing, unknown []string
+	for _, input := range required {
+		if strings.TrimSpace(values[input]) == "" {
+			missing = append(missing, input)
+		}
+	}
+	for input := range values {
+		if !slices.Contains(required, input) && !slices.Contains(optional, input) {
+			unknown = append(unknown, input)
+		}
+	}
+	if len(missing) > 0 {
+		return a.errorf("Recipe %s needs the input(s): %s", recipe.Name, strings.Join(missing, ", ")), nil
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return a.errorf("Unknown input(s) for recipe %s: %s. Inputs: %s", recipe.Name, strings.Join(unknown, ", "),
+			strings.Join(append(required, optional...), ", ")), nil
+	}
+
+	deckName := recipe.Deck
+	if deck, ok := args["deck"].(string); ok && strings.TrimSpace(deck) != "" {
+		deckName = deck
+	}
+	if strings.TrimSpace(deckName) == "" {
+		return a.errorf("Recipe %s has no deck, so deck is required", recipe.Name), nil
+	}
+	tags := slices.Clone(recipe.Tags)
+	for _, tag := range stringSliceValue(args, "tags") {
+		if !validTag(tag) {
+			return a.errorf("Invalid tag %q: tags can't be empty or contain spaces", tag), nil
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	options, errResult := a.noteOptions(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	// Check the fields before storing any media
+	modelFields, err := a.ankiClient.GetModelFieldNames(recipe.Model)
+	if err != nil {
+		return a.errorf("Failed to get fields: %v", err), nil
+	}
+	if len(modelFields) == 0 {
+		return a.errorf("Note type not found: %s", recipe.Model), nil
+	}
+	imageField, audioField := recipe.Media.ImageField, recipe.Media.AudioField
+	if imageField == "" {
+		imageField = modelFields[0]
+	}
+	if audioField == "" {
+		audioField = modelFields[0]
+	}
+	var unknownFields []string
+	for field := range recipe.Fields {
+		if !slices.Contains(modelFields, field) {
+			unknownFields = append(unknownFields, field)
+		}
+	}
+	for _, field := range []string{imageField, audioField} {
+		if !slices.Contains(modelFields, field) && !slices.Contains(unknownFields, field) {
+			unknownFields = append(unknownFields, field)
+		}
+	}
+	if len(unknownFields) > 0 {
+		sort.Strings(unknownFields)
+		return a.errorf("Recipe %s uses unknown field(s) of note type %s: %s. Fields: %s", recipe.Name, recipe.Model,
+			strings.Join(unknownFields, ", "), strings.Join(modelFields, ", ")), nil
+	}
+
+	fields := make(map[string]string, len(recipe.Fields))
+	for field, tmpl := range recipe.Fields {
+		tokens, _ := parseRecipeTemplate(tmpl)
+		fields[field] = renderRecipeTemplate(tokens, values)
+	}
+	if strings.TrimSpace(fields[modelFields[0]]) == "" {
+		return a.errorf("The first field of note type %s must not be empty", recipe.Model), nil
+	}
+
+	// Send the card to the deck chosen by the tag routing rules. The decks of
+	// recipes and rules are created on first use; a deck named by the call
+	// must exist, so a typo doesn't create a new one.
+	var route *RoutingRule
+	createDeck := deckName == recipe.Deck
+	if a.routing.Auto {
+		if rule, ok := a.routing.deckFor(tags); ok && rule.Deck != deckName {
+			deckName = rule.Deck
+			route = &rule
+			createDeck = true
+		}
+	}
+	if createDeck {
+		if err := a.ankiClient.CreateDeck(deckName); err != nil {
+			return a.errorf("Failed to create deck: %v", err), nil
+		}
+	}
+
+	imageOpts := imageOptions{MaxDimension: recipe.Media.MaxDimension, Quality: recipe.Media.Quality}
+	names, storedMedia, errResult := a.storeCardMedia(ctx, []cardMedia{
+		{stringValue(args, "image_path"), stringValue(args, "image_url"), "image/", imageOpts},
+		{stringValue(args, "audio_path"), stringValue(args, "audio_url"), "audio/", imageOptions{}},
+	}, recipe.Media.Prefix)
+	if errResult != nil {
+		return errResult, nil
+	}
+	imageName, audioName := names[0], names[1]
+	if imageField == audioField {
+		fields[imageField] = formatContent(fields[imageField], imageName, audioName)
+	} else {
+		fields[imageField] = formatContent(fields[imageField], imageName, "")
+		fields[audioField] = formatContent(fields[audioField], "", audioName)
+	}
+
+	noteID, err := a.ankiClient.AddNote(Note{
+		DeckName:  deckName,
+		ModelName: recipe.Model,
+		Fields:    fields,
+		Tags:      tags,
+		Options:   options,
+	})
+	if err != nil {
+		return a.errorf("Failed to create card: %v", err), nil
+	}
+
+	out := a.newOutput()
+	out.Line(a.t("Created card (ID: %d) from recipe %s in %s", noteID, recipe.Name, deckName))
+	if route != nil {
+		out.Line(a.t("Routed to deck %s by the tag rule %s", route.Deck, route.Tag))
+	}
+	a.describeStoredMedia(out, storedMedia)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRecipeTemplate(t *testing.T) {
+	recipe := RecipeConfig{Fields: map[string]string{
+		"Front": "{{word}}{{#reading}} ({{reading}}){{/reading}}",
+		"Back":  "{{c1::{{meaning}}}}{{#example}}<br><i>{{ example }}</i>{{/example}}",
+	}}
+	required, optional := recipe.inputs()
+	if strings.Join(required, ",") != "meaning,word" || strings.Join(optional, ",") != "example,reading" {
+		t.Errorf("inputs() = %v, %v", required, optional)
+	}
+
+	tokens, err := parseRecipeTemplate(recipe.Fields["Back"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := renderRecipeTemplate(tokens, map[string]string{"meaning": "dog"}); got != "{{c1::dog}}" {
+		t.Errorf("Expected the empty section to be left out, got %q", got)
+	}
+	if got := renderRecipeTemplate(tokens, map[string]string{"meaning": "dog", "example": "El perro ladra"}); got != "{{c1::dog}}<br><i>El perro ladra</i>" {
+		t.Errorf("Unexpected rendering %q", got)
+	}
+
+	for _, bad := range []string{"{{#a}}x", "{{/a}}", "{{#a}}{{#b}}{{/a}}{{/b}}"} {
+		if _, err := parseRecipeTemplate(bad); err == nil {
+			t.Errorf("Expected %q to be refused", bad)
+		}
+	}
+}
+
+func TestRecipeConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"recipes": [{"name": "vocab", "model": "Basic", "fields": {"Front": "{{#word}}"}}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var config Config
+	if err := loadConfigFile(path, &config); err == nil || !strings.Contains(err.Error(), "recipe vocab, field Front") {
+		t.Errorf("Expected the unclosed section to be reported, got %v", err)
+	}
+	if err := validateRecipes([]RecipeConfig{{Name: "a", Model: "Basic", Fields: map[string]string{"Front": "x"}}, {Name: "a"}}); err == nil {
+		t.Error("Expected duplicate names to be refused")
+	}
+}
+
+func TestCreateFromRecipe(t *testing.T) {
+	server, mock := newMockServer(t)
+	server.recipes = []RecipeConfig{{
+		Name:  "spanish",
+		Deck:  "Spanish::Vocabulary",
+		Model: "Basic",
+		Tags:  []string{"spanish"},
+		Fields: map[string]string{
+			"Front": "{{word}}",
+			"Back":  "{{meaning}}{{#example}}<br><i>{{example}}</i>{{/example}}",
+		},
+		Media: RecipeMedia{AudioField: "Back", Prefix: "es_"},
+	}}
+	audio := filepath.Join(t.TempDir(), "perro.mp3")
+	if err := os.WriteFile(audio, []byte("ID3 perro"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	args := map[string]interface{}{
+		"recipe":     "spanish",
+		"values":     map[string]interface{}{"word": "perro", "meaning": "dog"},
+		"tags":       []interface{}{"animals"},
+		"audio_path": audio,
+	}
+	text, isErr := callTool(t, server.handleCreateFromRecipe, args)
+	if isErr || !strings.Contains(text, "from recipe spanish in Spanish::Vocabulary") || !strings.Contains(text, "es_perro.mp3") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	for _, note := range mock.notes {
+		if note.Fields["Front"] != "perro" || note.Fields["Back"] != "dog<br><br>[sound:es_perro.mp3]" ||
+			strings.Join(note.Tags, " ") != "spanish animals" {
+			t.Errorf("Unexpected note: %+v", note)
+		}
+	}
+
+	args = map[string]interface{}{"recipe": "spanish", "values": map[string]interface{}{"word": "gato", "meening": "cat"}}
+	if text, isErr := callTool(t, server.handleCreateFromRecipe, args); !isErr || !strings.Contains(text, "needs the input(s): meaning") {
+		t.Errorf("Expected the missing input to be reported, got %s", text)
+	}
+	args["values"] = map[string]interface{}{"word": "gato", "meaning": "cat", "meening": "cat"}
+	if text, isErr := callTool(t, server.handleCreateFromRecipe, args); !isErr || !strings.Contains(text, "Unknown input(s) for recipe spanish: meening") {
+		t.Errorf("Expected the unknown input to be reported, got %s", text)
+	}
+
+	// Dry runs use a copy of the server, which must know the recipes too
+	dryRun := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return server.dryRunTool(ctx, request, (*AnkiMCPServer).handleCreateFromRecipe)
+	}
+	args = map[string]interface{}{"recipe": "spanish", "values": map[string]interface{}{"word": "gato", "meaning": "cat"}}
+	if text, isErr := callTool(t, dryRun, args); isErr || !strings.Contains(text, "Dry run") {
+		t.Errorf("Expected a dry run of the recipe, got %s", text)
+	}
+
+	text, _ = callTool(t, server.handleListRecipes, map[string]interface{}{})
+	if !strings.Contains(text, "Required inputs: meaning, word") || !strings.Contains(text, "Optional inputs: example") {
+		t.Errorf("Unexpected list: %s", text)
+	}
+}
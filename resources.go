@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// noteResourcePrefix starts the URI of a note resource, followed by the note ID
+const noteResourcePrefix = "anki://note/"
+
+// noteResource is the JSON document served for a note resource
+type noteResource struct {
+	NoteID int64               `json:"note_id"`
+	Model  string              `json:"model"`
+	Tags   []string            `json:"tags"`
+	Fields []noteResourceField `json:"fields"`
+	Cards  []noteResourceCard  `json:"cards"`
+}
+
+// noteResourceField is a note field in field order
+type noteResourceField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// noteResourceCard is the scheduling state of one of a note's cards
+type noteResourceCard struct {
+	CardID    int64  `json:"card_id"`
+	Deck      string `json:"deck"`
+	Template  int    `json:"template"`
+	State     string `json:"state"`
+	Suspended bool   `json:"suspended"`
+	DueInDays *int   `json:"due_in_days,omitempty"`
+	Interval  int    `json:"interval"`
+	Ease      int    `json:"ease"`
+	Reviews   int    `json:"reviews"`
+	Lapses    int    `json:"lapses"`
+}
+
+// registerResources registers the MCP resources served by the server
+func (a *AnkiMCPServer) registerResources(s *server.MCPServer) {
+	noteTemplate := mcp.NewResourceTemplate(noteResourcePrefix+"{id}", "Anki note",
+		mcp.WithTemplateDescription("An Anki note with its fields, tags and the scheduling state of its cards, as JSON"),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+	s.AddResourceTemplate(noteTemplate, a.handleReadNoteResource)
+}
+
+// handleReadNoteResource serves a note as a JSON resource
+func (a *AnkiMCPServer) handleReadNoteResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri := request.Params.URI
+	noteID, err := strconv.ParseInt(strings.TrimPrefix(uri, noteResourcePrefix), 10, 64)
+	if err != nil || !strings.HasPrefix(uri, noteResourcePrefix) {
+		return nil, fmt.Errorf("invalid note URI %q, expected %s<note ID>", uri, noteResourcePrefix)
+	}
+
+	note, err := a.noteResource(noteID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(note)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
+
+// noteResource collects a note's fields, tags and card scheduling state
+func (a *AnkiMCPServer) noteResource(noteID int64) (*noteResource, error) {
+	notes, err := a.ankiClient.GetNotesInfo([]int64{noteID})
+	if err != nil {
+		return nil, err
+	}
+	// AnkiConnect returns an empty object for unknown notes
+	if len(notes) == 0 || notes[0].NoteID == 0 {
+		return nil, fmt.Errorf("note %d not found", noteID)
+	}
+	info := notes[0]
+
+	note := &noteResource{
+		NoteID: noteID,
+		Model:  info.ModelName,
+		Tags:   info.Tags,
+		Fields: []noteResourceField{},
+		Cards:  []noteResourceCard{},
+	}
+	if note.Tags == nil {
+		note.Tags = []string{}
+	}
+
+	for _, name := range noteFieldNames(info) {
+		note.Fields = append(note.Fields, noteResourceField{name, info.Fields[name].Value})
+	}
+
+	if len(info.Cards) == 0 {
+		return note, nil
+	}
+	cards, err := a.ankiClient.GetCardsInfo(info.Cards)
+	if err != nil {
+		return nil, err
+	}
+	for _, card := range cards {
+		if card.CardID == 0 {
+			continue
+		}
+		c := noteResourceCard{
+			CardID:    card.CardID,
+			Deck:      card.DeckName,
+			Template:  card.Ord,
+			State:     cardStateName(card),
+			Suspended: card.Queue == -1,
+			Interval:  card.Interval,
+			Ease:      card.Factor / 10,
+			Reviews:   card.Reps,
+			Lapses:    card.Lapses,
+		}
+		if c.State == "review" || c.State == "relearning" {
+			if days, err := a.daysUntilDue(card.CardID); err == nil {
+				c.DueInDays = &days
+			}
+		}
+		note.Cards = append(note.Cards, c)
+	}
+	sort.Slice(note.Cards, func(i, j int) bool { return note.Cards[i].Template < note.Cards[j].Template })
+
+	return note, nil
+}
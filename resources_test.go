@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestNoteResource(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	var noteID int64
+	for id, note := range mock.notes {
+		if note.Fields["Front"] == "el perro" {
+			noteID = id
+		}
+	}
+
+	var request mcp.ReadResourceRequest
+	request.Params.URI = fmt.Sprintf("anki://note/%d", noteID)
+	contents, err := server.handleReadNoteResource(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, ok := contents[0].(mcp.TextResourceContents)
+	if !ok || text.MIMEType != "application/json" {
+		t.Fatalf("Unexpected contents %#v", contents)
+	}
+
+	var note noteResource
+	if err := json.Unmarshal([]byte(text.Text), &note); err != nil {
+		t.Fatal(err)
+	}
+	if note.NoteID != noteID || note.Model != "Basic (and reversed card)" || len(note.Fields) != 2 ||
+		note.Fields[0] != (noteResourceField{"Front", "el perro"}) || !strings.Contains(strings.Join(note.Tags, " "), "vocabulary") {
+		t.Errorf("Unexpected note %+v", note)
+	}
+	if len(note.Cards) != 2 || note.Cards[0].Template != 0 || note.Cards[1].Template != 1 {
+		t.Fatalf("Unexpected cards %+v", note.Cards)
+	}
+	for _, card := range note.Cards {
+		mockCard := mock.cards[card.CardID]
+		if (mockCard.Type == 2) != (card.DueInDays != nil) {
+			t.Errorf("Card %d: due_in_days %v for card type %d", card.CardID, card.DueInDays, mockCard.Type)
+		}
+		if card.DueInDays != nil && int64(*card.DueInDays) != mockCard.Due-mockToday {
+			t.Errorf("Card %d: due in %d days, want %d", card.CardID, *card.DueInDays, mockCard.Due-mockToday)
+		}
+	}
+
+	request.Params.URI = "anki://note/1"
+	if _, err := server.handleReadNoteResource(context.Background(), request); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+	request.Params.URI = "anki://note/perro"
+	if _, err := server.handleReadNoteResource(context.Background(), request); err == nil {
+		t.Error("Expected an invalid URI error")
+	}
+}
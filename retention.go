@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerRetentionTools registers the retention analysis tool
+func (a *AnkiMCPServer) registerRetentionTools(s *server.MCPServer) {
+	// Tool: Analyze Retention
+	analyzeRetentionTool := mcp.NewTool("analyze_retention",
+		mcp.WithDescription("Analyze how well cards are remembered, from the review log of a deck or tag over a date range: true retention "+
+			"(share of review answers that weren't Again), the Again rate of learning, young and mature cards, and how much a passed review "+
+			"grows the interval on average. Use it to judge whether the settings or the cards need attention."),
+		mcp.WithString("deck",
+			mcp.Description("Optional: Only include cards from this deck (and its subdecks)"),
+		),
+		mcp.WithString("tag",
+			mcp.Description("Optional: Only include cards of notes with this tag (and its child tags)"),
+		),
+		mcp.WithString("start_date",
+			mcp.Description("Optional: First day, YYYY-MM-DD (default: 30 days before end_date)"),
+		),
+		mcp.WithString("end_date",
+			mcp.Description("Optional: Last day, YYYY-MM-DD (default: today)"),
+		),
+		withFormat(),
+	)
+	a.addTool(s, analyzeRetentionTool, (*AnkiMCPServer).handleAnalyzeRetention)
+}
+
+// answerGroup counts the answers given to cards of one maturity
+type answerGroup struct {
+	Answers   int     `json:"answers"`
+	Again     int     `json:"again"`
+	AgainRate float64 `json:"again_rate"`
+}
+
+// add counts an answer
+func (g *answerGroup) add(again bool) {
+	g.Answers++
+	if again {
+		g.Again++
+	}
+}
+
+// retentionReport holds the retention metrics of a date range
+type retentionReport struct {
+	Answers       int         `json:"answers"`
+	ReviewAnswers int         `json:"review_answers"`
+	TrueRetention float64     `json:"true_retention"`
+	Learning      answerGroup `json:"learning"`
+	Young         answerGroup `json:"young"`
+	Mature        answerGroup `json:"mature"`
+	// IntervalGrowth is the average factor by which passed reviews of
+	// graduated cards multiplied the interval
+	IntervalGrowth float64 `json:"interval_growth"`
+	GrowthSamples  int     `json:"growth_samples"`
+}
+
+// computeRetention derives retention metrics from review log entries with
+// review times in [startMs, endMs). Learning and relearning steps count as
+// learning; reviews are young or mature by the interval the card had.
+// Filtered deck and manual entries are left out, since they don't follow the
+// card's schedule.
+func computeRetention(entries []ReviewEntry, startMs, endMs int64) retentionReport {
+	var report retentionReport
+	growthSum := 0.0
+	for _, e := range entries {
+		if e.ReviewTime < startMs || e.ReviewTime >= endMs {
+			continue
+		}
+		again := e.ButtonPressed == 1
+		switch e.ReviewType {
+		case 0, 2:
+			report.Learning.add(again)
+		case 1:
+			if e.PreviousInterval >= matureInterval {
+				report.Mature.add(again)
+			} else {
+				report.Young.add(again)
+			}
+			if !again && e.PreviousInterval > 0 && e.NewInterval > 0 {
+				growthSum += float64(e.NewInterval) / float64(e.PreviousInterval)
+				report.GrowthSamples++
+			}
+		default:
+			continue
+		}
+		report.Answers++
+	}
+
+	for _, g := range []*answerGroup{&report.Learning, &report.Young, &report.Mature} {
+		if g.Answers > 0 {
+			g.AgainRate = float64(g.Again) / float64(g.Answers)
+		}
+	}
+	report.ReviewAnswers = report.Young.Answers + report.Mature.Answers
+	if report.ReviewAnswers > 0 {
+		passed := report.ReviewAnswers - report.Young.Again - report.Mature.Again
+		report.TrueRetention = float64(passed) / float64(report.ReviewAnswers)
+	}
+	if report.GrowthSamples > 0 {
+		report.IntervalGrowth = growthSum / float64(report.GrowthSamples)
+	}
+	return report
+}
+
+// handleAnalyzeRetention reports retention metrics for the cards of a deck or
+// tag, based on their review log
+func (a *AnkiMCPServer) handleAnalyzeRetention(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	start, end, err := parseDateRange(args, 30)
+	if err != nil {
+		return a.errorf("%v", err), nil
+	}
+	deckName, _ := args["deck"].(string)
+	tag, _ := args["tag"].(string)
+
+	var terms []string
+	if deckName != "" {
+		terms = append(terms, deckQuery(deckName))
+	}
+	if tag != "" {
+		terms = append(terms, tagQuery(tag))
+	}
+	if len(terms) == 0 {
+		terms = append(terms, "deck:*")
+	}
+	cardIDs, err := a.ankiClient.FindCards(strings.Join(terms, " "))
+	if err != nil {
+		return a.errorf("Failed to find cards: %v", err), nil
+	}
+
+	var entries []ReviewEntry
+	if len(cardIDs) > 0 {
+		reviews, err := a.ankiClient.GetReviewsOfCards(cardIDs)
+		if err != nil {
+			return a.errorf("Failed to get review log: %v", err), nil
+		}
+		for _, cardReviews := range reviews {
+			entries = append(entries, cardReviews...)
+		}
+	}
+	report := computeRetention(entries, start.UnixMilli(), end.UnixMilli())
+
+	if wantsJSON(request) {
+		return a.jsonResult(map[string]interface{}{
+			"deck":       deckName,
+			"tag":        tag,
+			"cards":      len(cardIDs),
+			"start_date": start.Format(dateLayout),
+			"end_date":   end.AddDate(0, 0, -1).Format(dateLayout),
+			"retention":  report,
+		}), nil
+	}
+
+	var scopes []string
+	if deckName != "" {
+		scopes = append(scopes, deckName)
+	}
+	if tag != "" {
+		scopes = append(scopes, a.t("tag %s", tag))
+	}
+	scope := a.t("all decks")
+	if len(scopes) > 0 {
+		scope = strings.Join(scopes, ", ")
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Retention for %s from %s to %s", scope,
+		a.loc.FormatDate(start), a.loc.FormatDate(end.AddDate(0, 0, -1))))
+	if report.Answers == 0 {
+		out.Line(a.t("No cards were studied in this period."))
+	} else {
+		if report.ReviewAnswers > 0 {
+			out.Item(a.t("True retention: %.1f%% of %d review answers", report.TrueRetention*100, report.ReviewAnswers))
+		} else {
+			out.Item(a.t("True retention: no reviews of graduated cards in this period"))
+		}
+		groups := []struct {
+			label string
+			group answerGroup
+		}{
+			{a.t("learning and relearning"), report.Learning},
+			{a.t("young cards (interval under %d days)", matureInterval), report.Young},
+			{a.t("mature cards"), report.Mature},
+		}
+		for _, g := range groups {
+			if g.group.Answers > 0 {
+				out.Item(a.t("Again rate, %s: %.1f%% of %d answers", g.label, g.group.AgainRate*100, g.group.Answers))
+			}
+		}
+		if report.GrowthSamples > 0 {
+			out.Item(a.t("Interval growth: a passed review multiplied the interval by %.2f on average", report.IntervalGrowth))
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComputeRetention(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local).UnixMilli()
+	end := time.Date(2024, 4, 1, 0, 0, 0, 0, time.Local).UnixMilli()
+	during := start + 1000
+
+	entries := []ReviewEntry{
+		// Learning: one Again, one Good
+		{ReviewTime: during, CardID: 1, ButtonPressed: 1, NewInterval: -60, ReviewType: 0},
+		{ReviewTime: during, CardID: 1, ButtonPressed: 3, NewInterval: 1, PreviousInterval: -60, ReviewType: 0},
+		// Young: passed, growing the interval from 4 to 10 days
+		{ReviewTime: during, CardID: 2, ButtonPressed: 3, NewInterval: 10, PreviousInterval: 4, ReviewType: 1},
+		// Mature: one lapse, one pass growing 30 to 75 days
+		{ReviewTime: during, CardID: 3, ButtonPressed: 1, NewInterval: -600, PreviousInterval: 30, ReviewType: 1},
+		{ReviewTime: during, CardID: 4, ButtonPressed: 4, NewInterval: 75, PreviousInterval: 30, ReviewType: 1},
+		// Relearning counts as learning
+		{ReviewTime: during, CardID: 3, ButtonPressed: 3, NewInterval: 3, PreviousInterval: -600, ReviewType: 2},
+		// Filtered deck reviews and entries outside the range are left out
+		{ReviewTime: during, CardID: 5, ButtonPressed: 1, NewInterval: 5, PreviousInterval: 5, ReviewType: 3},
+		{ReviewTime: end, CardID: 2, ButtonPressed: 1, NewInterval: -600, PreviousInterval: 10, ReviewType: 1},
+	}
+
+	report := computeRetention(entries, start, end)
+	if report.Answers != 6 || report.ReviewAnswers != 3 {
+		t.Errorf("Expected 6 answers, 3 of them reviews, got %+v", report)
+	}
+	if report.Learning.Answers != 3 || report.Learning.Again != 1 {
+		t.Errorf("Unexpected learning answers %+v", report.Learning)
+	}
+	if report.Young.AgainRate != 0 || report.Mature.AgainRate != 0.5 {
+		t.Errorf("Unexpected again rates: young %+v, mature %+v", report.Young, report.Mature)
+	}
+	if got, want := report.TrueRetention, 2.0/3; got != want {
+		t.Errorf("Expected true retention %.3f, got %.3f", want, got)
+	}
+	if report.GrowthSamples != 2 || report.IntervalGrowth != 2.5 {
+		t.Errorf("Expected an interval growth of 2.5 from 2 reviews, got %.2f from %d", report.IntervalGrowth, report.GrowthSamples)
+	}
+}
+
+func TestAnalyzeRetention(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	text, isErr := callTool(t, server.handleAnalyzeRetention, map[string]interface{}{"deck": "Spanish"})
+	if isErr || !strings.Contains(text, "True retention: 100.0% of 21 review answers") || !strings.Contains(text, "Again rate, young cards") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+
+	text, _ = callTool(t, server.handleAnalyzeRetention, map[string]interface{}{"tag": "grammar", "format": "json"})
+	var result struct {
+		Cards     int             `json:"cards"`
+		Retention retentionReport `json:"retention"`
+	}
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Cards != 2 {
+		t.Errorf("Expected the 2 cards of the grammar note, got %s", text)
+	}
+}
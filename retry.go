@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how AnkiConnect requests are retried when Anki is not
+// reachable, e.g. while it is starting up or busy syncing
+type RetryPolicy struct {
+	// Attempts is the total number of tries; 1 disables retrying
+	Attempts int
+	// Backoff is the wait before the first retry, doubled for each further
+	// retry up to maxRetryBackoff
+	Backoff time.Duration
+	// Jitter randomizes each wait by up to this fraction, from 0 to 1
+	Jitter float64
+}
+
+// defaultRetryPolicy tries a request three times, waiting about 0.5s and 1s
+// between tries
+var defaultRetryPolicy = RetryPolicy{Attempts: 3, Backoff: 500 * time.Millisecond, Jitter: 0.2}
+
+// maxRetryBackoff caps the wait between two tries
+const maxRetryBackoff = 10 * time.Second
+
+// unrepeatableActions would have their effect twice, or fail because their
+// effect is already there, if a request that timed out or whose connection
+// was reset had reached Anki after all. They are only retried when the
+// connection was refused, since then the request never arrived.
+var unrepeatableActions = map[string]bool{
+	"addNote":                true,
+	"addNotes":               true,
+	"cloneDeckConfigId":      true,
+	"createModel":            true,
+	"findAndReplaceInModels": true,
+	"guiAddCards":            true,
+	"guiAnswerCard":          true,
+	"guiUndo":                true,
+	"importPackage":          true,
+	"insertReviews":          true,
+	"modelFieldRename":       true,
+	"removeDeckConfigId":     true,
+	"storeMediaFile":         true,
+}
+
+// repeatable reports whether a request can be sent again after a timeout or
+// a reset connection. A multi request can when all of its actions can.
+func repeatable(action string, params interface{}) bool {
+	if action != "multi" {
+		return !unrepeatableActions[action]
+	}
+	p, _ := params.(map[string]interface{})
+	actions, ok := p["actions"].([]ankiRequest)
+	if !ok {
+		return false
+	}
+	for _, a := range actions {
+		if !repeatable(a.Action, a.Params) {
+			return false
+		}
+	}
+	return true
+}
+
+// wait returns how long to wait before the given retry, counting from 1
+func (p RetryPolicy) wait(retry int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < retry && d < maxRetryBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, maxRetryBackoff)
+	if p.Jitter > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(d))
+	}
+	return d
+}
+
+// shouldRetry reports whether a failed request is worth trying again;
+// canRepeat tells whether it may be sent again if it might have arrived
+func shouldRetry(canRepeat bool, err error) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	if !canRepeat {
+		return false
+	}
+	var netErr net.Error
+	return errors.Is(err, syscall.ECONNRESET) || errors.As(err, &netErr) && netErr.Timeout()
+}
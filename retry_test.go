@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// flakyTransport refuses the first failures connections, then passes requests
+// on to next
+type flakyTransport struct {
+	failures int
+	calls    int
+	next     http.RoundTripper
+}
+
+func (f *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	}
+	return f.next.RoundTrip(req)
+}
+
+func TestRetryConnectionErrors(t *testing.T) {
+	mock := newMockAnkiConnect()
+	client := mockClient(t, mock)
+	flaky := &flakyTransport{failures: 2, next: http.DefaultTransport}
+	client.client.Transport = flaky
+	client.Retry = RetryPolicy{Attempts: 3, Backoff: time.Millisecond}
+
+	if err := client.Ping(); err != nil || flaky.calls != 3 {
+		t.Errorf("Ping() = %v after %d calls, want success after 3", err, flaky.calls)
+	}
+
+	flaky.calls = 0
+	client.Retry.Attempts = 2
+	if err := client.Ping(); !errors.Is(err, syscall.ECONNREFUSED) || flaky.calls != 2 {
+		t.Errorf("Ping() = %v after %d calls, want a connection error after 2", err, flaky.calls)
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	refused := fmt.Errorf("failed to connect to AnkiConnect: %w", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED})
+	timeout := fmt.Errorf("failed to connect to AnkiConnect: %w", &net.DNSError{IsTimeout: true})
+	reset := fmt.Errorf("failed to connect to AnkiConnect: %w", &net.OpError{Op: "read", Err: syscall.ECONNRESET})
+	tests := []struct {
+		action string
+		err    error
+		want   bool
+	}{
+		{"addNote", refused, true},
+		{"deckNames", timeout, true},
+		{"addNote", timeout, false},
+		{"deckNames", reset, true},
+		{"addNotes", reset, false},
+		{"guiAddCards", timeout, false},
+		{"guiUndo", timeout, false},
+		{"modelFieldRename", timeout, false},
+		{"findAndReplaceInModels", reset, false},
+		{"deckNames", errors.New("AnkiConnect error: deck was not found"), false},
+	}
+	for _, tt := range tests {
+		if got := shouldRetry(repeatable(tt.action, nil), tt.err); got != tt.want {
+			t.Errorf("shouldRetry(%s, %v) = %v, want %v", tt.action, tt.err, got, tt.want)
+		}
+	}
+
+	// A multi request is as repeatable as its actions
+	ac := NewAnkiConnect()
+	reads := map[string]interface{}{"actions": []ankiRequest{ac.action("findCards", nil), ac.action("notesInfo", nil)}}
+	writes := map[string]interface{}{"actions": []ankiRequest{ac.action("findCards", nil), ac.action("addNote", nil)}}
+	if !shouldRetry(repeatable("multi", reads), timeout) {
+		t.Error("Expected a multi request that only reads to be retried after a timeout")
+	}
+	if shouldRetry(repeatable("multi", writes), timeout) {
+		t.Error("Expected a multi request adding a note not to be retried after a timeout")
+	}
+}
+
+func TestRetryWait(t *testing.T) {
+	policy := RetryPolicy{Attempts: 5, Backoff: 100 * time.Millisecond}
+	for retry, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 20: maxRetryBackoff} {
+		if got := policy.wait(retry); got != want {
+			t.Errorf("wait(%d) = %v, want %v", retry, got, want)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for range 100 {
+		if got := policy.wait(1); got < 50*time.Millisecond || got > 150*time.Millisecond {
+			t.Fatalf("wait(1) with jitter = %v, want 50ms to 150ms", got)
+		}
+	}
+}
+
+func TestRetryPolicyFromEnv(t *testing.T) {
+	t.Setenv("ANKI_CONNECT_RETRY_ATTEMPTS", "5")
+	t.Setenv("ANKI_CONNECT_RETRY_BACKOFF", "2s")
+	t.Setenv("ANKI_CONNECT_RETRY_JITTER", "")
+	policy, err := retryPolicyFromEnv()
+	if err != nil || policy != (RetryPolicy{Attempts: 5, Backoff: 2 * time.Second, Jitter: defaultRetryPolicy.Jitter}) {
+		t.Errorf("retryPolicyFromEnv() = %+v, %v", policy, err)
+	}
+
+	t.Setenv("ANKI_CONNECT_RETRY_JITTER", "2")
+	if _, err := retryPolicyFromEnv(); err == nil {
+		t.Error("Expected an error for a jitter above 1")
+	}
+}
+
+func TestRetryMediaUpload(t *testing.T) {
+	mock := newMockAnkiConnect()
+	client := mockClient(t, mock)
+	flaky := &flakyTransport{failures: 1, next: http.DefaultTransport}
+	client.client.Transport = flaky
+	client.Retry = RetryPolicy{Attempts: 3, Backoff: time.Millisecond}
+
+	// The upload is sent again from the start of the data
+	data := []byte("ID3 hola")
+	stored, err := client.StoreMediaFileFrom("hola.mp3", bytes.NewReader(data))
+	if err != nil || flaky.calls != 2 || stored.Size != int64(len(data)) || string(mock.media["hola.mp3"]) != string(data) {
+		t.Fatalf("StoreMediaFileFrom() = %+v, %v after %d calls, stored %q", stored, err, flaky.calls, mock.media["hola.mp3"])
+	}
+
+	// Data that can't be read again isn't retried, and the connection
+	// error is reported rather than the closed pipe
+	flaky.calls = 0
+	_, err = client.StoreMediaFileFrom("adios.mp3", io.MultiReader(bytes.NewReader(data)))
+	if !errors.Is(err, syscall.ECONNREFUSED) || strings.Contains(err.Error(), "pipe") || flaky.calls != 1 {
+		t.Errorf("StoreMediaFileFrom() = %v after %d calls, want the connection error after 1", err, flaky.calls)
+	}
+}
@@ -0,0 +1,561 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// reviewLogCSVHeader is the column layout used when exporting the review log
+var reviewLogCSVHeader = []string{
+	"card_id", "review_time", "ease", "interval", "previous_interval", "factor", "time_taken_ms", "review_type",
+}
+
+// registerReviewLogTools registers review log tools with the MCP server
+func (a *AnkiMCPServer) registerReviewLogTools(s *server.MCPServer) {
+	// Tool: Export Review Log
+	exportReviewLogTool := mcp.NewTool("export_review_log",
+		mcp.WithDescription("Export the review log of cards matching a search query as CSV (columns: "+
+			strings.Join(reviewLogCSVHeader, ", ")+") for analysis in spreadsheets or pandas. "+
+			"Intervals are in days when positive and seconds when negative."),
+		mcp.WithString("query",
+			mcp.Description("Optional: Anki search query selecting the cards (default: all cards)"),
+		),
+		mcp.WithString("start_date",
+			mcp.Description("Optional: Only include reviews on or after this day, YYYY-MM-DD"),
+		),
+		mcp.WithString("end_date",
+			mcp.Description("Optional: Only include reviews on or before this day, YYYY-MM-DD"),
+		),
+		mcp.WithString("output_path",
+			mcp.Description("Optional: Write the CSV to this file instead of returning it"),
+		),
+	)
+	a.addTool(s, exportReviewLogTool, (*AnkiMCPServer).handleExportReviewLog)
+
+	// Tool: Import Reviews
+	importReviewsTool := mcp.NewTool("import_reviews",
+		mcp.WithDescription("Import review history into Anki's review log, e.g. when migrating from Mnemosyne or SuperMemo. "+
+			"The cards must already exist in Anki. Input is CSV with a header row or a JSON array of objects, using the same "+
+			"columns as export_review_log: card_id (required), review_time (required; RFC 3339, 'YYYY-MM-DD HH:MM:SS', "+
+			"'YYYY-MM-DD' or Unix seconds or milliseconds), ease (required; 1-4), interval (required; days, negative for seconds), "+
+			"previous_interval, factor (permille, default 2500), time_taken_ms, review_type (0=learn, 1=review, 2=relearn; default 1)."),
+		mcp.WithString("data",
+			mcp.Description("Optional: CSV or JSON content to import (either data or path is required)"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Optional: Path to a CSV or JSON file to import"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Optional: Input format (default: detected from content)"),
+			mcp.Enum("csv", "json"),
+		),
+	)
+	a.addChangingTool(s, importReviewsTool, (*AnkiMCPServer).handleImportReviews)
+
+	// Tool: Get Card Reviews
+	getCardReviewsTool := mcp.NewTool("get_card_reviews",
+		mcp.WithDescription("Get the full review history of cards: when each review happened, the button pressed and how the interval and ease changed. "+
+			"Useful to explain why a card keeps lapsing."),
+		mcp.WithArray("card_ids",
+			mcp.Required(),
+			mcp.Description("IDs of the cards"),
+			mcp.WithNumberItems(),
+		),
+		withFormat(),
+	)
+	a.addTool(s, getCardReviewsTool, (*AnkiMCPServer).handleGetCardReviews)
+}
+
+// handleExportReviewLog exports review log entries as CSV
+func (a *AnkiMCPServer) handleExportReviewLog(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	query, _ := args["query"].(string)
+	if query == "" {
+		query = "deck:*"
+	}
+
+	// Without a date range the full history is exported
+	var start, end time.Time
+	startStr, _ := args["start_date"].(string)
+	endStr, _ := args["end_date"].(string)
+	if startStr != "" || endStr != "" {
+		var err error
+		start, end, err = parseDateRange(args, 36500)
+		if err != nil {
+			return a.errorf("%v", err), nil
+		}
+	}
+
+	cardIDs, err := a.ankiClient.FindCards(query)
+	if err != nil {
+		return a.errorf("Failed to find cards: %v", err), nil
+	}
+
+	reviews, err := a.ankiClient.GetReviewsOfCards(cardIDs)
+	if err != nil {
+		return a.errorf("Failed to get reviews: %v", err), nil
+	}
+
+	var entries []ReviewEntry
+	for _, cardReviews := range reviews {
+		for _, e := range cardReviews {
+			if !start.IsZero() && (e.ReviewTime < start.UnixMilli() || e.ReviewTime >= end.UnixMilli()) {
+				continue
+			}
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ReviewTime < entries[j].ReviewTime
+	})
+
+	var buf strings.Builder
+	if err := writeReviewLogCSV(&buf, entries); err != nil {
+		return a.errorf("Failed to write CSV: %v", err), nil
+	}
+
+	text := buf.String()
+	if outputPath, ok := args["output_path"].(string); ok && outputPath != "" {
+		if err := os.WriteFile(outputPath, []byte(text), 0644); err != nil {
+			return a.errorf("Failed to write %s: %v", outputPath, err), nil
+		}
+		text = a.t("Exported %d reviews of %d cards to %s", len(entries), len(cardIDs), outputPath)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}, nil
+}
+
+// cardReviewRecord is the JSON representation of a review in a get_card_reviews result
+type cardReviewRecord struct {
+	ReviewTime       string `json:"review_time"`
+	Ease             int    `json:"ease"`
+	ReviewType       string `json:"review_type"`
+	Interval         int64  `json:"interval"`
+	PreviousInterval int64  `json:"previous_interval"`
+	Factor           int64  `json:"factor"`
+	TimeTaken        int64  `json:"time_taken_ms"`
+}
+
+// cardReviewHistory is the review history of one card in a get_card_reviews result
+type cardReviewHistory struct {
+	CardID   int64              `json:"card_id"`
+	Deck     string             `json:"deck"`
+	Question string             `json:"question"`
+	Lapses   int                `json:"lapses"`
+	Reviews  []cardReviewRecord `json:"reviews"`
+
+	entries []ReviewEntry
+}
+
+// handleGetCardReviews returns the review history of cards
+func (a *AnkiMCPServer) handleGetCardReviews(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	var cardIDs []int64
+	for _, id := range numberSliceValue(args, "card_ids") {
+		cardIDs = append(cardIDs, int64(id))
+	}
+	if len(cardIDs) == 0 {
+		return a.errorf("card_ids is required"), nil
+	}
+
+	infos, err := a.ankiClient.GetCardsInfo(cardIDs)
+	if err != nil {
+		return a.errorf("Failed to get card info: %v", err), nil
+	}
+	reviews, err := a.ankiClient.GetReviewsOfCards(cardIDs)
+	if err != nil {
+		return a.errorf("Failed to get reviews: %v", err), nil
+	}
+
+	var histories []cardReviewHistory
+	var missing []int64
+	for i, info := range infos {
+		if info.CardID == 0 {
+			missing = append(missing, cardIDs[i])
+			continue
+		}
+		entries := reviews[cardIDs[i]]
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].ReviewTime < entries[j].ReviewTime
+		})
+		history := cardReviewHistory{
+			CardID:   cardIDs[i],
+			Deck:     info.DeckName,
+			Question: cardText(info.Question),
+			Reviews:  []cardReviewRecord{},
+			entries:  entries,
+		}
+		for _, e := range entries {
+			if e.ReviewType == 1 && e.ButtonPressed == 1 {
+				history.Lapses++
+			}
+			history.Reviews = append(history.Reviews, cardReviewRecord{
+				ReviewTime:       time.UnixMilli(e.ReviewTime).Format(time.RFC3339),
+				Ease:             e.ButtonPressed,
+				ReviewType:       reviewTypeName(e.ReviewType),
+				Interval:         e.NewInterval,
+				PreviousInterval: e.PreviousInterval,
+				Factor:           e.NewFactor,
+				TimeTaken:        e.ReviewDuration,
+			})
+		}
+		histories = append(histories, history)
+	}
+
+	if wantsJSON(request) {
+		return a.jsonResult(map[string]interface{}{
+			"cards":   histories,
+			"missing": missing,
+		}), nil
+	}
+
+	out := a.newOutput()
+	for _, h := range histories {
+		out.Heading(a.t("Card %d (%s)", h.CardID, h.Deck))
+		out.Line(a.t("Question: %s", h.Question))
+		out.Line(a.t("Reviews: %d, lapses: %d.", len(h.Reviews), h.Lapses))
+		for _, e := range h.entries {
+			when := a.loc.FormatDateTime(time.UnixMilli(e.ReviewTime))
+			if e.ReviewType == 4 {
+				out.Item(a.t("%s: rescheduled to %s", when, a.formatReviewInterval(e.NewInterval)))
+				continue
+			}
+			out.Item(a.t("%s: %s (%s), interval %s → %s, ease %d%%, %.0fs", when, a.easeLabel(e.ButtonPressed), a.reviewTypeLabel(reviewTypeName(e.ReviewType)),
+				a.formatReviewInterval(e.PreviousInterval), a.formatReviewInterval(e.NewInterval), e.NewFactor/10, float64(e.ReviewDuration)/1000))
+		}
+	}
+	if len(missing) > 0 {
+		ids := make([]string, len(missing))
+		for i, id := range missing {
+			ids[i] = strconv.FormatInt(id, 10)
+		}
+		out.Line(a.t("Cards not found: %s", strings.Join(ids, ", ")))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// reviewTypeName returns the English name of a review log entry type
+func reviewTypeName(reviewType int) string {
+	switch reviewType {
+	case 0:
+		return "learning"
+	case 2:
+		return "relearning"
+	case 3:
+		return "filtered"
+	case 4:
+		return "manual"
+	default:
+		return "review"
+	}
+}
+
+// reviewTypeLabel returns the localized name of a review log entry type
+func (a *AnkiMCPServer) reviewTypeLabel(reviewType string) string {
+	if reviewType == "filtered" {
+		return a.t("filtered deck")
+	}
+	return a.cardStateLabel(reviewType)
+}
+
+// formatReviewInterval formats a review log interval, given in days when
+// positive and in seconds when negative
+func (a *AnkiMCPServer) formatReviewInterval(interval int64) string {
+	if interval < 0 {
+		return formatMinutes(a.loc, float64(-interval)/60)
+	}
+	return formatDays(a.loc, float64(interval))
+}
+
+// handleImportReviews imports review history from CSV or JSON
+func (a *AnkiMCPServer) handleImportReviews(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	data, _ := args["data"].(string)
+	if path, ok := args["path"].(string); ok && path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return a.errorf("Failed to read %s: %v", path, err), nil
+		}
+		data = string(content)
+	}
+	if strings.TrimSpace(data) == "" {
+		return a.errorf("data or path is required"), nil
+	}
+
+	format, _ := args["format"].(string)
+	if format == "" {
+		format = "csv"
+		if strings.HasPrefix(strings.TrimSpace(data), "[") {
+			format = "json"
+		}
+	}
+
+	var entries []ReviewEntry
+	var err error
+	switch format {
+	case "csv":
+		entries, err = parseReviewsCSV(strings.NewReader(data))
+	case "json":
+		entries, err = parseReviewsJSON([]byte(data))
+	default:
+		return a.errorf("unsupported format: %s", format), nil
+	}
+	if err != nil {
+		return a.errorf("Failed to parse reviews: %v", err), nil
+	}
+	if len(entries) == 0 {
+		return a.errorf("no reviews found in input"), nil
+	}
+
+	if err := a.ankiClient.InsertReviews(entries); err != nil {
+		return a.errorf("Failed to import reviews: %v", err), nil
+	}
+
+	cards := make(map[int64]bool)
+	for _, e := range entries {
+		cards[e.CardID] = true
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: a.t("Imported %d reviews for %d cards", len(entries), len(cards)),
+			},
+		},
+	}, nil
+}
+
+// reviewRecord is the JSON representation of an imported review
+type reviewRecord struct {
+	CardID           int64       `json:"card_id"`
+	ReviewTime       interface{} `json:"review_time"`
+	Ease             int         `json:"ease"`
+	Interval         *int64      `json:"interval"`
+	PreviousInterval int64       `json:"previous_interval"`
+	Factor           int64       `json:"factor"`
+	TimeTakenMs      int64       `json:"time_taken_ms"`
+	ReviewType       *int        `json:"review_type"`
+}
+
+// parseReviewsJSON parses a JSON array of review records
+func parseReviewsJSON(data []byte) ([]ReviewEntry, error) {
+	var records []reviewRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+
+	entries := make([]ReviewEntry, 0, len(records))
+	for i, r := range records {
+		var reviewTime string
+		switch v := r.ReviewTime.(type) {
+		case string:
+			reviewTime = v
+		case float64:
+			reviewTime = strconv.FormatInt(int64(v), 10)
+		}
+		if r.Interval == nil {
+			return nil, fmt.Errorf("review %d: interval is required", i+1)
+		}
+		reviewType := 1
+		if r.ReviewType != nil {
+			reviewType = *r.ReviewType
+		}
+		entry, err := newImportedReview(r.CardID, reviewTime, r.Ease, *r.Interval, r.PreviousInterval, r.Factor, r.TimeTakenMs, reviewType)
+		if err != nil {
+			return nil, fmt.Errorf("review %d: %w", i+1, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return uniqueReviewTimes(entries), nil
+}
+
+// parseReviewsCSV parses CSV review records with a header row naming the columns
+func parseReviewsCSV(r io.Reader) ([]ReviewEntry, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"card_id", "review_time", "ease", "interval"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing required column %q", required)
+		}
+	}
+
+	entries := make([]ReviewEntry, 0, len(records)-1)
+	for line, record := range records[1:] {
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		parseInt := func(name string, def int64) (int64, error) {
+			value := get(name)
+			if value == "" {
+				return def, nil
+			}
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid %s %q", name, value)
+			}
+			return n, nil
+		}
+
+		var nums [6]int64
+		for i, name := range []string{"card_id", "ease", "interval", "previous_interval", "factor", "time_taken_ms"} {
+			if nums[i], err = parseInt(name, 0); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line+2, err)
+			}
+		}
+		reviewType, err := parseInt("review_type", 1)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line+2, err)
+		}
+
+		entry, err := newImportedReview(nums[0], get("review_time"), int(nums[1]), nums[2], nums[3], nums[4], nums[5], int(reviewType))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line+2, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return uniqueReviewTimes(entries), nil
+}
+
+// newImportedReview validates imported review values and builds a review log entry
+func newImportedReview(cardID int64, reviewTime string, ease int, interval, previousInterval, factor, timeTaken int64, reviewType int) (ReviewEntry, error) {
+	if cardID <= 0 {
+		return ReviewEntry{}, fmt.Errorf("card_id is required")
+	}
+	if ease < 1 || ease > 4 {
+		return ReviewEntry{}, fmt.Errorf("ease must be between 1 and 4, got %d", ease)
+	}
+	if reviewType < 0 || reviewType > 4 {
+		return ReviewEntry{}, fmt.Errorf("review_type must be between 0 and 4, got %d", reviewType)
+	}
+	ts, err := parseReviewTime(reviewTime)
+	if err != nil {
+		return ReviewEntry{}, err
+	}
+	if factor == 0 {
+		factor = 2500
+	}
+
+	return ReviewEntry{
+		ReviewTime:       ts,
+		CardID:           cardID,
+		USN:              -1,
+		ButtonPressed:    ease,
+		NewInterval:      interval,
+		PreviousInterval: previousInterval,
+		NewFactor:        factor,
+		ReviewDuration:   timeTaken,
+		ReviewType:       reviewType,
+	}, nil
+}
+
+// maxUnixSeconds tells Unix seconds from milliseconds: as milliseconds,
+// smaller numbers would be reviews from before 1973
+const maxUnixSeconds = 100_000_000_000
+
+// parseReviewTime parses a review timestamp into Unix milliseconds. Numbers
+// are read as Unix seconds or milliseconds depending on their size.
+func parseReviewTime(value string) (int64, error) {
+	if value == "" {
+		return 0, fmt.Errorf("review_time is required")
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if n < maxUnixSeconds {
+			return n * 1000, nil
+		}
+		return n, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UnixMilli(), nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", dateLayout} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t.UnixMilli(), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid review_time %q", value)
+}
+
+// uniqueReviewTimes shifts colliding review timestamps by a millisecond, since
+// Anki uses the timestamp as the review ID
+func uniqueReviewTimes(entries []ReviewEntry) []ReviewEntry {
+	used := make(map[int64]bool, len(entries))
+	for i := range entries {
+		for used[entries[i].ReviewTime] {
+			entries[i].ReviewTime++
+		}
+		used[entries[i].ReviewTime] = true
+	}
+	return entries
+}
+
+// writeReviewLogCSV writes review log entries as CSV including a header row.
+// Review times are written to the millisecond so the CSV imports again as the
+// same reviews.
+func writeReviewLogCSV(w io.Writer, entries []ReviewEntry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(reviewLogCSVHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		record := []string{
+			strconv.FormatInt(e.CardID, 10),
+			time.UnixMilli(e.ReviewTime).Format(time.RFC3339Nano),
+			strconv.Itoa(e.ButtonPressed),
+			strconv.FormatInt(e.NewInterval, 10),
+			strconv.FormatInt(e.PreviousInterval, 10),
+			strconv.FormatInt(e.NewFactor, 10),
+			strconv.FormatInt(e.ReviewDuration, 10),
+			strconv.Itoa(e.ReviewType),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReviewLogCSVRoundTrip(t *testing.T) {
+	entries := []ReviewEntry{
+		{ReviewTime: 1700000000123, CardID: 42, USN: -1, ButtonPressed: 3, NewInterval: 5, PreviousInterval: 2, NewFactor: 2500, ReviewDuration: 6000, ReviewType: 1},
+		{ReviewTime: 1700000000123, CardID: 43, USN: -1, ButtonPressed: 1, NewInterval: -600, PreviousInterval: 10, NewFactor: 2300, ReviewDuration: 9000, ReviewType: 1},
+	}
+
+	var buf strings.Builder
+	if err := writeReviewLogCSV(&buf, entries); err != nil {
+		t.Fatalf("writeReviewLogCSV failed: %v", err)
+	}
+
+	parsed, err := parseReviewsCSV(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("parseReviewsCSV failed: %v", err)
+	}
+	if len(parsed) != 2 {
+		t.Fatalf("Expected 2 reviews, got %d", len(parsed))
+	}
+	if parsed[0] != entries[0] {
+		t.Errorf("Expected %+v, got %+v", entries[0], parsed[0])
+	}
+	// Colliding timestamps must be made unique since they are review IDs
+	if parsed[1].ReviewTime != entries[1].ReviewTime+1 {
+		t.Errorf("Expected shifted review time, got %d", parsed[1].ReviewTime)
+	}
+}
+
+func TestParseReviewTime(t *testing.T) {
+	date := time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local).UnixMilli()
+	tests := []struct {
+		value string
+		want  int64
+	}{
+		{"1700000000123", 1700000000123},
+		// Unix seconds are told from milliseconds by their size
+		{"1700000000", 1700000000000},
+		{"2023-11-14T22:13:20Z", 1700000000000},
+		{"2023-11-14T22:13:20.123Z", 1700000000123},
+		{"2024-01-02", date},
+		{"2024-01-02 00:00:01", date + 1000},
+	}
+	for _, tt := range tests {
+		if got, err := parseReviewTime(tt.value); err != nil || got != tt.want {
+			t.Errorf("parseReviewTime(%q) = %d, %v, want %d", tt.value, got, err, tt.want)
+		}
+	}
+	for _, value := range []string{"", "yesterday"} {
+		if _, err := parseReviewTime(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestExportReviewLog(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	text, isErr := callTool(t, server.handleExportReviewLog, map[string]interface{}{"query": "deck:Spanish"})
+	if isErr {
+		t.Fatalf("export_review_log: %s", text)
+	}
+	rows, err := csv.NewReader(strings.NewReader(text)).ReadAll()
+	if err != nil || len(rows) < 2 || strings.Join(rows[0], ",") != strings.Join(reviewLogCSVHeader, ",") {
+		t.Fatalf("Unexpected CSV (%v): %s", err, text)
+	}
+	reviews := len(rows) - 1
+
+	// A range without reviews leaves only the header
+	text, _ = callTool(t, server.handleExportReviewLog, map[string]interface{}{"start_date": "2000-01-01", "end_date": "2000-01-02"})
+	if strings.TrimSpace(text) != strings.Join(reviewLogCSVHeader, ",") {
+		t.Errorf("Expected only the header, got %s", text)
+	}
+
+	path := filepath.Join(t.TempDir(), "reviews.csv")
+	text, isErr = callTool(t, server.handleExportReviewLog, map[string]interface{}{"query": "deck:Spanish", "output_path": path})
+	if isErr || !strings.Contains(text, "Exported") || !strings.Contains(text, path) {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != reviews+1 {
+		t.Errorf("Expected %d lines in %s, got %d", reviews+1, path, lines)
+	}
+}
+
+func TestParseReviewsJSON(t *testing.T) {
+	data := `[{"card_id": 7, "review_time": "2024-01-02", "ease": 4, "interval": 3}]`
+	entries, err := parseReviewsJSON([]byte(data))
+	if err != nil {
+		t.Fatalf("parseReviewsJSON failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 review, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.CardID != 7 || e.ButtonPressed != 4 || e.NewInterval != 3 || e.NewFactor != 2500 || e.ReviewType != 1 {
+		t.Errorf("Unexpected entry: %+v", e)
+	}
+
+	if _, err := parseReviewsJSON([]byte(`[{"card_id": 7, "review_time": "2024-01-02", "ease": 5, "interval": 3}]`)); err == nil {
+		t.Error("Expected error for invalid ease")
+	}
+}
+
+func TestGetCardReviews(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	cards, _ := server.ankiClient.FindCards("is:review")
+	ids := []interface{}{float64(cards[0]), float64(42)}
+	text, isErr := callTool(t, server.handleGetCardReviews, map[string]interface{}{"card_ids": ids})
+	if isErr || !strings.Contains(text, "Reviews: 3, lapses: 0.") || !strings.Contains(text, "Good (review)") || !strings.Contains(text, "Cards not found: 42") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+
+	text, _ = callTool(t, server.handleGetCardReviews, map[string]interface{}{"card_ids": ids[:1], "format": "json"})
+	var result struct {
+		Cards []cardReviewHistory `json:"cards"`
+	}
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", text, err)
+	}
+	reviews := result.Cards[0].Reviews
+	if len(reviews) != 3 || reviews[0].ReviewTime > reviews[2].ReviewTime || reviews[2].Ease != 3 {
+		t.Errorf("Unexpected reviews: %+v", reviews)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RoutingConfig holds rules that send cards to decks based on their tags
+type RoutingConfig struct {
+	// Auto applies the rules to notes created through this server
+	Auto bool `json:"auto"`
+	// Rules are checked in order; the first rule matching one of a note's
+	// tags decides its deck
+	Rules []RoutingRule `json:"rules"`
+}
+
+// RoutingRule sends cards whose notes carry a matching tag to a deck
+type RoutingRule struct {
+	// Tag is a tag pattern using Anki's search syntax: * matches any text,
+	// _ a single character, and child tags match too
+	Tag  string `json:"tag"`
+	Deck string `json:"deck"`
+}
+
+// validate checks that every rule is complete and its pattern is valid
+func (c RoutingConfig) validate() error {
+	for i, rule := range c.Rules {
+		if strings.TrimSpace(rule.Tag) == "" || strings.TrimSpace(rule.Deck) == "" {
+			return fmt.Errorf("routing rule %d needs both tag and deck", i+1)
+		}
+		if _, err := path.Match(rule.Tag, ""); err != nil {
+			return fmt.Errorf("routing rule %d: invalid tag pattern %q", i+1, rule.Tag)
+		}
+	}
+	return nil
+}
+
+// deckFor returns the first rule matching one of the tags
+func (c RoutingConfig) deckFor(tags []string) (RoutingRule, bool) {
+	for _, rule := range c.Rules {
+		for _, tag := range tags {
+			if matchTag(rule.Tag, tag) {
+				return rule, true
+			}
+		}
+	}
+	return RoutingRule{}, false
+}
+
+// globMatch matches text against a case-insensitive pattern where * is a wildcard
+func globMatch(pattern, text string) bool {
+	pattern = strings.ReplaceAll(strings.ToLower(pattern), "_", "?")
+	ok, _ := path.Match(pattern, strings.ToLower(text))
+	return ok
+}
+
+// matchTag reports whether a tag matches a pattern the way Anki's tag: search
+// does, including child tags
+func matchTag(pattern, tag string) bool {
+	return globMatch(pattern, tag) || globMatch(pattern+"::*", tag)
+}
+
+// registerRoutingTools registers tag-based deck routing tools with the MCP server
+func (a *AnkiMCPServer) registerRoutingTools(s *server.MCPServer) {
+	// Tool: Route Cards
+	routeCardsTool := mcp.NewTool("route_cards",
+		mcp.WithDescription("Move cards into decks according to the tag routing rules from the config file (tag pattern → deck). "+
+			"Rules are checked in order and the first rule matching a note's tags decides the deck. Cards already in the target deck or its subdecks stay where they are."),
+		mcp.WithString("query",
+			mcp.Description("Optional: Anki search query limiting which cards are routed (default: all cards)"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Optional: Only report which cards would be moved"),
+		),
+	)
+	a.addChangingTool(s, routeCardsTool, (*AnkiMCPServer).handleRouteCards)
+}
+
+// handleRouteCards moves cards to the decks chosen by the routing rules
+func (a *AnkiMCPServer) handleRouteCards(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	if len(a.routing.Rules) == 0 {
+		return a.errorf("No routing rules configured. Add rules to the config file: %s", a.configFile), nil
+	}
+
+	filter := ""
+	if query, ok := args["query"].(string); ok && strings.TrimSpace(query) != "" {
+		filter = " (" + query + ")"
+	}
+	dryRun, _ := args["dry_run"].(bool)
+
+	type routeResult struct {
+		rule  RoutingRule
+		cards []int64
+	}
+	var results []routeResult
+	claimed := make(map[int64]bool)
+	total := 0
+
+	for _, rule := range a.routing.Rules {
+		matched, err := a.ankiClient.FindCards(tagQuery(rule.Tag) + filter)
+		if err != nil {
+			return a.errorf("Failed to find cards: %v", err), nil
+		}
+		inTarget, err := a.ankiClient.FindCards(tagQuery(rule.Tag) + filter + " " + deckQuery(rule.Deck))
+		if err != nil {
+			return a.errorf("Failed to find cards: %v", err), nil
+		}
+		skip := make(map[int64]bool, len(inTarget))
+		for _, id := range inTarget {
+			skip[id] = true
+		}
+
+		// Cards matched by an earlier rule belong to that rule
+		var move []int64
+		for _, id := range matched {
+			if !claimed[id] && !skip[id] {
+				move = append(move, id)
+			}
+			claimed[id] = true
+		}
+		if len(move) == 0 {
+			continue
+		}
+
+		if !dryRun {
+			if err := a.ankiClient.ChangeDeck(move, rule.Deck); err != nil {
+				return a.errorf("Failed to move cards to %s: %v", rule.Deck, err), nil
+			}
+		}
+		results = append(results, routeResult{rule, move})
+		total += len(move)
+	}
+
+	out := a.newOutput()
+	switch {
+	case total == 0:
+		out.Line(a.t("All cards are already in the decks chosen by the routing rules"))
+	case dryRun:
+		out.Heading(a.t("Would move %d card(s)", total))
+	default:
+		out.Heading(a.t("Moved %d card(s)", total))
+	}
+	for _, r := range results {
+		out.Item(a.t("tag %s → %s: %d card(s)", r.rule.Tag, r.rule.Deck, len(r.cards)))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
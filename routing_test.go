@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRoutingConfigDeckFor(t *testing.T) {
+	routing := RoutingConfig{Rules: []RoutingRule{
+		{Tag: "spanish::verb*", Deck: "Spanish::Verbs"},
+		{Tag: "spanish", Deck: "Spanish"},
+	}}
+
+	tests := []struct {
+		tags []string
+		want string
+	}{
+		{[]string{"Spanish::Verbs::Irregular"}, "Spanish::Verbs"},
+		{[]string{"spanish::nouns"}, "Spanish"},
+		{[]string{"french", "spanish"}, "Spanish"},
+		{[]string{"spanishish"}, ""},
+	}
+	for _, tt := range tests {
+		rule, ok := routing.deckFor(tt.tags)
+		if ok != (tt.want != "") || rule.Deck != tt.want {
+			t.Errorf("deckFor(%v) = %q, want %q", tt.tags, rule.Deck, tt.want)
+		}
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"routing": {"auto": true, "rules": [{"tag": "grammar", "deck": "Spanish::Grammar"}]}}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	if err := loadConfigFile(path, &config); err != nil {
+		t.Fatal(err)
+	}
+	if !config.Routing.Auto || len(config.Routing.Rules) != 1 {
+		t.Errorf("Unexpected routing config: %+v", config.Routing)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"routing": {"rules": [{"tag": "[", "deck": "X"}]}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadConfigFile(path, &config); err == nil {
+		t.Error("Expected an error for an invalid tag pattern")
+	}
+	if err := loadConfigFile(filepath.Join(t.TempDir(), "missing.json"), &config); err != nil {
+		t.Errorf("Missing config file should be ignored, got %v", err)
+	}
+}
+
+func TestRouteCards(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+	server.routing = RoutingConfig{Auto: true, Rules: []RoutingRule{
+		{Tag: "ser-estar", Deck: "Spanish::Grammar::Ser y estar"},
+		{Tag: "vocab*", Deck: "Spanish::Words"},
+	}}
+
+	text, isErr := callTool(t, server.handleRouteCards, map[string]interface{}{"dry_run": true})
+	if isErr || !strings.Contains(text, "Would move 14 card(s)") {
+		t.Fatalf("Unexpected dry run output: %s", text)
+	}
+	if cards, _ := mock.search(`deck:Spanish::Words`); len(cards) != 0 {
+		t.Fatal("Dry run moved cards")
+	}
+
+	text, _ = callTool(t, server.handleRouteCards, map[string]interface{}{"query": "deck:Spanish"})
+	if !strings.Contains(text, "tag ser-estar → Spanish::Grammar::Ser y estar: 2 card(s)") || !strings.Contains(text, "tag vocab* → Spanish::Words: 12 card(s)") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	text, _ = callTool(t, server.handleRouteCards, map[string]interface{}{})
+	if !strings.Contains(text, "already in the decks") {
+		t.Errorf("Expected nothing left to route, got: %s", text)
+	}
+
+	// New cards are routed automatically
+	args := map[string]interface{}{"deck": "Default", "front": "ir", "back": "to go", "tags": []interface{}{"vocabulary"}}
+	text, isErr = callTool(t, server.handleCreateCard, args)
+	if isErr || !strings.Contains(text, "Routed to deck Spanish::Words") {
+		t.Errorf("Unexpected create_card output: %s", text)
+	}
+	if cards, _ := mock.search(`deck:Spanish::Words ir`); len(cards) != 1 {
+		t.Errorf("Expected the new card in Spanish::Words, found %d", len(cards))
+	}
+}
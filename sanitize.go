@@ -0,0 +1,290 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// SanitizeConfig cleans the field HTML of notes before it is sent to Anki,
+// so markup written by a model can't run scripts or load trackers when the
+// cards are shown
+type SanitizeConfig struct {
+	// Enabled turns sanitizing on; ANKI_MCP_SANITIZE overrides it
+	Enabled bool `json:"enabled"`
+	// Tags replaces the default list of allowed HTML elements
+	Tags []string `json:"tags"`
+	// Attributes replaces the default list of allowed attributes
+	Attributes []string `json:"attributes"`
+	// AllowRemote keeps images and other resources loaded from other hosts;
+	// by default only files from the media folder and data images are kept
+	AllowRemote bool `json:"allow_remote"`
+}
+
+// defaultSanitizeTags are the HTML elements Anki's editor produces and cards
+// commonly use
+var defaultSanitizeTags = []string{
+	"a", "b", "big", "blockquote", "br", "caption", "center", "code", "col", "colgroup", "dd", "del", "div", "dl", "dt",
+	"em", "font", "h1", "h2", "h3", "h4", "h5", "h6", "hr", "i", "img", "ins", "kbd", "li", "mark", "ol", "p", "pre",
+	"rp", "rt", "ruby", "s", "small", "span", "strike", "strong", "sub", "sup", "table", "tbody", "td", "tfoot", "th",
+	"thead", "tr", "u", "ul",
+}
+
+// defaultSanitizeAttributes are the attributes kept on allowed elements
+var defaultSanitizeAttributes = []string{
+	"align", "alt", "class", "color", "colspan", "dir", "face", "height", "href", "lang", "rowspan", "size", "src",
+	"style", "title", "valign", "width",
+}
+
+// sanitizeDropContent are elements removed together with their content,
+// which is code or markup rather than text. They can't be allowed.
+var sanitizeDropContent = []string{"embed", "iframe", "noscript", "object", "script", "style", "svg", "template", "math"}
+
+// sanitizeURLAttributes are attributes holding a URL
+var sanitizeURLAttributes = []string{"action", "background", "formaction", "href", "poster", "src", "xlink:href"}
+
+// sanitizeLoadAttributes are URL attributes the card loads on its own when
+// shown, unlike links, which need a click
+var sanitizeLoadAttributes = []string{"background", "poster", "src"}
+
+var sanitizeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9:-]*$`)
+
+// validate checks that the allowlists name elements and attributes, and that
+// they don't allow code
+func (c SanitizeConfig) validate() error {
+	for _, tag := range c.Tags {
+		tag = strings.ToLower(tag)
+		if !sanitizeNamePattern.MatchString(tag) {
+			return fmt.Errorf("sanitize: invalid tag %q", tag)
+		}
+		if slices.Contains(sanitizeDropContent, tag) {
+			return fmt.Errorf("sanitize: %s can't be allowed", tag)
+		}
+	}
+	for _, attr := range c.Attributes {
+		attr = strings.ToLower(attr)
+		if !sanitizeNamePattern.MatchString(attr) {
+			return fmt.Errorf("sanitize: invalid attribute %q", attr)
+		}
+		if strings.HasPrefix(attr, "on") {
+			return fmt.Errorf("sanitize: event handler %s can't be allowed", attr)
+		}
+	}
+	return nil
+}
+
+// htmlSanitizer removes everything but allowed elements and attributes from
+// field HTML
+type htmlSanitizer struct {
+	tags        map[string]bool
+	attributes  map[string]bool
+	allowRemote bool
+}
+
+// newHTMLSanitizer creates the sanitizer of a configuration, or returns nil
+// when sanitizing is off
+func newHTMLSanitizer(c SanitizeConfig) *htmlSanitizer {
+	if !c.Enabled {
+		return nil
+	}
+	tags, attributes := c.Tags, c.Attributes
+	if len(tags) == 0 {
+		tags = defaultSanitizeTags
+	}
+	if len(attributes) == 0 {
+		attributes = defaultSanitizeAttributes
+	}
+	s := &htmlSanitizer{
+		tags:        make(map[string]bool, len(tags)),
+		attributes:  make(map[string]bool, len(attributes)),
+		allowRemote: c.AllowRemote,
+	}
+	for _, tag := range tags {
+		s.tags[strings.ToLower(tag)] = true
+	}
+	for _, attr := range attributes {
+		s.attributes[strings.ToLower(attr)] = true
+	}
+	return s
+}
+
+var (
+	// sanitizeTagPattern matches a start or end tag; quoted attribute values
+	// may contain >
+	sanitizeTagPattern = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9:-]*)((?:"[^"]*"|'[^']*'|[^'">])*)>`)
+	// sanitizeAttrPattern matches an attribute with an optional value
+	sanitizeAttrPattern = regexp.MustCompile(`([^\s"'>/=]+)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+)))?`)
+	// sanitizeSchemePattern finds the scheme of a URL
+	sanitizeSchemePattern = regexp.MustCompile(`^([a-z][a-z0-9+.-]*):`)
+	// sanitizeEndTagPatterns match the end tag of each element in
+	// sanitizeDropContent
+	sanitizeEndTagPatterns = func() map[string]*regexp.Regexp {
+		patterns := make(map[string]*regexp.Regexp, len(sanitizeDropContent))
+		for _, name := range sanitizeDropContent {
+			patterns[name] = regexp.MustCompile(`(?i)</` + regexp.QuoteMeta(name) + `\s*>`)
+		}
+		return patterns
+	}()
+)
+
+// Sanitize returns the HTML with disallowed elements, attributes and URLs
+// removed, along with a description of each removal. Disallowed elements
+// keep their text, except for scripts, styles and embedded objects, which
+// are dropped whole. Text, including cloze deletions, [sound:...] references
+// and formulas, is left as it is.
+func (s *htmlSanitizer) Sanitize(fieldHTML string) (string, []string) {
+	var b strings.Builder
+	var removed []string
+	for i := 0; i < len(fieldHTML); {
+		rest := fieldHTML[i:]
+		if !strings.HasPrefix(rest, "<") {
+			next := strings.IndexByte(rest, '<')
+			if next < 0 {
+				next = len(rest)
+			}
+			b.WriteString(rest[:next])
+			i += next
+			continue
+		}
+
+		if strings.HasPrefix(rest, "<!--") {
+			end := strings.Index(rest[4:], "-->")
+			if end < 0 {
+				i = len(fieldHTML)
+			} else {
+				i += 4 + end + 3
+			}
+			removed = append(removed, "comment")
+			continue
+		}
+		m := sanitizeTagPattern.FindStringSubmatch(rest)
+		if m == nil {
+			// A < that starts no tag is text
+			b.WriteString("&lt;")
+			i++
+			continue
+		}
+		i += len(m[0])
+		closing, name := m[1] == "/", strings.ToLower(m[2])
+
+		if endTag, ok := sanitizeEndTagPatterns[name]; ok {
+			removed = append(removed, "<"+name+">")
+			if closing {
+				continue
+			}
+			end := endTag.FindStringIndex(fieldHTML[i:])
+			if end == nil {
+				i = len(fieldHTML)
+			} else {
+				i += end[1]
+			}
+			continue
+		}
+		if !s.tags[name] {
+			if !closing {
+				removed = append(removed, "<"+name+">")
+			}
+			continue
+		}
+		if closing {
+			b.WriteString("</" + name + ">")
+			continue
+		}
+
+		attrs, attrRemoved := s.sanitizeAttributes(name, m[3])
+		removed = append(removed, attrRemoved...)
+		if name == "img" && !strings.Contains(attrs, ` src="`) {
+			// An image whose source was removed shows nothing
+			removed = append(removed, "<img>")
+			continue
+		}
+		b.WriteString("<" + name + attrs)
+		if strings.HasSuffix(strings.TrimSpace(m[3]), "/") {
+			b.WriteString(" /")
+		}
+		b.WriteString(">")
+	}
+	return b.String(), removed
+}
+
+// sanitizeAttributes returns the allowed attributes of an element, quoted and
+// escaped, and a description of the removed ones
+func (s *htmlSanitizer) sanitizeAttributes(tag, raw string) (string, []string) {
+	var b strings.Builder
+	var removed []string
+	for _, m := range sanitizeAttrPattern.FindAllStringSubmatch(raw, -1) {
+		name := strings.ToLower(m[1])
+		if name == "/" {
+			continue
+		}
+		value := html.UnescapeString(m[2] + m[3] + m[4])
+		switch {
+		case strings.HasPrefix(name, "on"):
+			removed = append(removed, name)
+			continue
+		case !s.attributes[name]:
+			removed = append(removed, tag+" "+name)
+			continue
+		case slices.Contains(sanitizeURLAttributes, name) && !s.safeURL(name, value):
+			removed = append(removed, fmt.Sprintf("%s %s=%q", tag, name, value))
+			continue
+		case name == "style" && unsafeStyle(value):
+			removed = append(removed, tag+" style")
+			continue
+		}
+		fmt.Fprintf(&b, ` %s="%s"`, name, html.EscapeString(value))
+	}
+	return b.String(), removed
+}
+
+// safeURL reports whether a URL attribute may stay: scripts never, remote
+// resources loaded with the card only when allowed
+func (s *htmlSanitizer) safeURL(attr, value string) bool {
+	// Browsers ignore whitespace and control characters in schemes
+	url := strings.ToLower(strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, value))
+	scheme := ""
+	if m := sanitizeSchemePattern.FindStringSubmatch(url); m != nil {
+		scheme = m[1]
+	}
+	switch scheme {
+	case "javascript", "vbscript":
+		return false
+	case "data":
+		return attr == "src" && strings.HasPrefix(url, "data:image/") && !strings.HasPrefix(url, "data:image/svg")
+	}
+	if !slices.Contains(sanitizeLoadAttributes, attr) || s.allowRemote {
+		return true
+	}
+	return scheme == "" && !strings.HasPrefix(url, "//")
+}
+
+// unsafeStyle reports whether inline CSS loads resources or runs code
+func unsafeStyle(css string) bool {
+	css = strings.ToLower(strings.NewReplacer(" ", "", "\t", "", "\n", "", "\\", "").Replace(css))
+	return strings.Contains(css, "url(") || strings.Contains(css, "expression(") ||
+		strings.Contains(css, "javascript:") || strings.Contains(css, "@import")
+}
+
+// sanitizeFields returns the fields with their HTML sanitized, logging what
+// was removed. Without a sanitizer the fields are returned as they are.
+func (ac *AnkiConnect) sanitizeFields(fields map[string]string) map[string]string {
+	if ac.sanitizer == nil || len(fields) == 0 {
+		return fields
+	}
+	clean := make(map[string]string, len(fields))
+	for name, value := range fields {
+		sanitized, removed := ac.sanitizer.Sanitize(value)
+		if len(removed) > 0 {
+			ac.logger().Info("removed HTML from field", "field", name, "removed", removed)
+		}
+		clean[name] = sanitized
+	}
+	return clean
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	s := newHTMLSanitizer(SanitizeConfig{Enabled: true})
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", `<b>perro</b><br>[sound:perro.mp3] {{c1::dog}}`, `<b>perro</b><br>[sound:perro.mp3] {{c1::dog}}`},
+		{"script", `hola<script>alert(1)</script> mundo`, `hola mundo`},
+		{"event handler", `<div onclick="steal()" class="x">hi</div>`, `<div class="x">hi</div>`},
+		{"javascript link", `<a href=" JavaScript:alert(1)">x</a> <a href="https://es.wikipedia.org">y</a>`, `<a>x</a> <a href="https://es.wikipedia.org">y</a>`},
+		{"tracker", `<img src="https://tracker.example/p.gif"><img src="perro.jpg">`, `<img src="perro.jpg">`},
+		{"unknown tag", `<marquee>moving</marquee> <iframe src="x"></iframe>`, `moving `},
+		{"style", `<span style="background: url(http://x/y)">a</span><span style="color: red">b</span>`, `<span>a</span><span style="color: red">b</span>`},
+		{"comment and text", `a < b<!-- hidden -->`, `a &lt; b`},
+		{"quoted >", `<img alt="a > b" src="x.png"/>`, `<img alt="a &gt; b" src="x.png" />`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := s.Sanitize(tt.in)
+			if got != tt.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	remote := newHTMLSanitizer(SanitizeConfig{Enabled: true, AllowRemote: true, Tags: []string{"img"}})
+	if got, removed := remote.Sanitize(`<b>x</b><img src="https://example.com/a.png">`); got != `x<img src="https://example.com/a.png">` || len(removed) != 1 {
+		t.Errorf("Unexpected result with remote images allowed: %q, removed %v", got, removed)
+	}
+	if newHTMLSanitizer(SanitizeConfig{}) != nil {
+		t.Error("Expected no sanitizer when sanitizing is off")
+	}
+}
+
+func TestSanitizeConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"sanitize": {"enabled": true, "tags": ["b", "script"]}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var config Config
+	if err := loadConfigFile(path, &config); err == nil || !strings.Contains(err.Error(), "script can't be allowed") {
+		t.Errorf("Expected script to be refused, got %v", err)
+	}
+	if err := (SanitizeConfig{Attributes: []string{"onload"}}).validate(); err == nil {
+		t.Error("Expected event handlers to be refused")
+	}
+}
+
+func TestSanitizeNotes(t *testing.T) {
+	server, mock := newMockServer(t)
+	server.ankiClient.sanitizer = newHTMLSanitizer(SanitizeConfig{Enabled: true})
+
+	args := map[string]interface{}{"deck": "Default", "front": `hola<img src=x onerror="alert(1)">`, "back": "hello<script>x()</script>"}
+	if text, isErr := callTool(t, server.handleCreateCard, args); isErr {
+		t.Fatalf("create_card failed: %s", text)
+	}
+	for _, note := range mock.notes {
+		if note.Fields["Front"] != `hola<img src="x">` || note.Fields["Back"] != "hello" {
+			t.Errorf("Expected sanitized fields, got %v", note.Fields)
+		}
+		if err := server.ankiClient.UpdateNoteFields(note.ID, map[string]string{"Back": `<a href="javascript:x()">hi</a>`}); err != nil {
+			t.Fatal(err)
+		}
+		if note.Fields["Back"] != "<a>hi</a>" {
+			t.Errorf("Expected the update to be sanitized, got %q", note.Fields["Back"])
+		}
+	}
+}
@@ -0,0 +1,868 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// defaultSearchLimit is the number of notes returned when no limit is given
+const defaultSearchLimit = 10
+
+// Sort keys of search_cards
+const (
+	sortCreated  = "created"
+	sortModified = "modified"
+	sortDue      = "due"
+	sortInterval = "interval"
+)
+
+// Sort orders of search_cards
+const (
+	orderAsc  = "asc"
+	orderDesc = "desc"
+)
+
+// registerSearchTools registers the search tools with the MCP server
+func (a *AnkiMCPServer) registerSearchTools(s *server.MCPServer) {
+	// Tool: Search Cards
+	searchCardsTool := mcp.NewTool("search_cards",
+		mcp.WithDescription("Search the collection with Anki's search syntax, e.g. deck:\"Spanish\" tag:difficult or is:due, and list the matching notes with their deck and tags. "+
+			"Results come in pages with the total number of matches; pass the returned cursor to get the next page. "+
+			"Without sort_by only the notes of the requested page are fetched, so paging through large searches stays fast."),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Anki search query"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Optional: Maximum number of notes to return (default: 10)"),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Optional: Number of matching notes to skip (default: 0)"),
+		),
+		mcp.WithString("cursor",
+			mcp.Description("Optional: Cursor from the previous page to continue after, instead of offset; it stays on track when notes are added or deleted in between"),
+		),
+		mcp.WithString("sort_by",
+			mcp.Description("Optional: Sort the notes by when they were created, last modified, when their first matching card is due, "+
+				"or by the shortest interval of their matching cards (default: Anki's search order). Sorting fetches every matching note, so it is slower for large searches"),
+			mcp.Enum(sortCreated, sortModified, sortDue, sortInterval),
+		),
+		mcp.WithString("order",
+			mcp.Description("Optional: Sort order: asc or desc (default: desc for created and modified, so the newest come first; asc for due and interval)"),
+			mcp.Enum(orderAsc, orderDesc),
+		),
+		withFormat(),
+	)
+	a.addTool(s, searchCardsTool, (*AnkiMCPServer).handleSearchCards)
+
+	// Tool: Validate Query
+	validateQueryTool := mcp.NewTool("validate_query",
+		mcp.WithDescription("Check an Anki search query for common mistakes before using it: unbalanced quotes and parentheses, deck= instead of deck:, "+
+			"unknown prefixes, && or NOT, invalid is:, flag:, prop: and date values, and decks or note types that don't exist. "+
+			"Suggests a corrected query and counts the cards the query matches, without returning them."),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Anki search query to check"),
+		),
+		withFormat(),
+	)
+	a.addTool(s, validateQueryTool, (*AnkiMCPServer).handleValidateQuery)
+}
+
+// searchResult is a note found by search_cards
+type searchResult struct {
+	Note NoteInfo
+	// Deck is the deck of the note's first matching card
+	Deck string
+	// Cards are the note's cards that match the search
+	Cards []CardInfo
+}
+
+// searchNotes runs a search and returns the matching notes in the order of
+// their first matching card
+func (a *AnkiMCPServer) searchNotes(query string) ([]searchResult, error) {
+	notes, cards, err := a.ankiClient.SearchNotes(query)
+	if err != nil {
+		return nil, err
+	}
+	return groupSearchResults(notes, cards), nil
+}
+
+// searchNotePage returns the notes with the given IDs that match a search,
+// in the order of the IDs
+func (a *AnkiMCPServer) searchNotePage(query string, noteIDs []int64) ([]searchResult, error) {
+	if len(noteIDs) == 0 {
+		return nil, nil
+	}
+	notes, cards, err := a.ankiClient.SearchNotesByID(query, noteIDs)
+	if err != nil {
+		return nil, err
+	}
+	results := groupSearchResults(notes, cards)
+	order := make(map[int64]int, len(noteIDs))
+	for i, id := range noteIDs {
+		order[id] = i
+	}
+	slices.SortFunc(results, func(x, y searchResult) int {
+		return cmp.Compare(order[x.Note.NoteID], order[y.Note.NoteID])
+	})
+	return results, nil
+}
+
+// groupSearchResults pairs notes with their matching cards, in the order of
+// their first matching card. Notes without one are left out.
+func groupSearchResults(notes []NoteInfo, cards []CardInfo) []searchResult {
+	byID := make(map[int64]NoteInfo, len(notes))
+	for _, note := range notes {
+		byID[note.NoteID] = note
+	}
+	results := make([]searchResult, 0, len(notes))
+	index := make(map[int64]int, len(notes))
+	for _, card := range cards {
+		if i, ok := index[card.NoteID]; ok {
+			results[i].Cards = append(results[i].Cards, card)
+			continue
+		}
+		note, ok := byID[card.NoteID]
+		if !ok {
+			continue
+		}
+		index[card.NoteID] = len(results)
+		results = append(results, searchResult{Note: note, Deck: card.DeckName, Cards: []CardInfo{card}})
+	}
+	return results
+}
+
+// sortSearchResults sorts search results by one of the sort keys. Notes that
+// compare equal keep their search order.
+func sortSearchResults(results []searchResult, by string, desc bool) {
+	compare := func(x, y searchResult) int {
+		switch by {
+		case sortCreated:
+			// Note IDs are creation times
+			return cmp.Compare(x.Note.NoteID, y.Note.NoteID)
+		case sortModified:
+			return cmp.Compare(x.Note.Mod, y.Note.Mod)
+		case sortDue:
+			return compareDue(firstDue(x.Cards), firstDue(y.Cards))
+		default:
+			return cmp.Compare(shortestInterval(x.Cards), shortestInterval(y.Cards))
+		}
+	}
+	slices.SortStableFunc(results, func(x, y searchResult) int {
+		if desc {
+			return compare(y, x)
+		}
+		return compare(x, y)
+	})
+}
+
+// dueGroup orders cards by how their due value is counted: learning cards
+// (due at a time) come before cards due on a day, which come before new
+// cards (due in queue order)
+func dueGroup(card CardInfo) int {
+	switch {
+	case card.Queue == 1:
+		return 0
+	case hasDayDue(card):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// compareDue compares when two cards are due
+func compareDue(x, y CardInfo) int {
+	if c := cmp.Compare(dueGroup(x), dueGroup(y)); c != 0 {
+		return c
+	}
+	return cmp.Compare(x.Due, y.Due)
+}
+
+// firstDue returns the card that is due first
+func firstDue(cards []CardInfo) CardInfo {
+	return slices.MinFunc(cards, compareDue)
+}
+
+// shortestInterval returns the shortest interval of the cards, in days
+func shortestInterval(cards []CardInfo) int {
+	shortest := cards[0].Interval
+	for _, card := range cards[1:] {
+		shortest = min(shortest, card.Interval)
+	}
+	return shortest
+}
+
+// searchNote is a note in the JSON output of search_cards
+type searchNote struct {
+	NoteID int64             `json:"note_id"`
+	Model  string            `json:"model"`
+	Fields map[string]string `json:"fields"`
+	Tags   []string          `json:"tags"`
+	Deck   string            `json:"deck"`
+}
+
+// searchCursor marks where a page of search results ended. It is handed to
+// clients as an opaque string.
+type searchCursor struct {
+	Query string `json:"q"`
+	// Sort is the sort key and order the page was sorted by, if any
+	Sort   string `json:"s,omitempty"`
+	Offset int    `json:"o"`
+	// After is the last note of the page, so the next page starts right
+	// after it even when notes before it were added or deleted
+	After int64 `json:"a"`
+}
+
+// String encodes the cursor for clients
+func (c searchCursor) String() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// parseSearchCursor decodes a cursor returned by an earlier search
+func parseSearchCursor(s string) (searchCursor, error) {
+	var c searchCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil || c.Offset < 0 {
+		return searchCursor{}, fmt.Errorf("invalid cursor")
+	}
+	return c, nil
+}
+
+// pageStart returns the index in the matching note IDs where the page after
+// the cursor starts: after the cursor's last note if it still matches, else
+// at its offset
+func (c searchCursor) pageStart(noteIDs []int64) int {
+	if i := slices.Index(noteIDs, c.After); i >= 0 {
+		return i + 1
+	}
+	return min(c.Offset, len(noteIDs))
+}
+
+// handleSearchCards lists a page of the notes matching a search query
+func (a *AnkiMCPServer) handleSearchCards(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	query, ok := args["query"].(string)
+	if !ok || strings.TrimSpace(query) == "" {
+		return a.errorf("query is required"), nil
+	}
+	limit := defaultSearchLimit
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+	offset := 0
+	if o, ok := args["offset"].(float64); ok && o > 0 {
+		offset = int(o)
+	}
+	sortBy, _ := args["sort_by"].(string)
+	order, _ := args["order"].(string)
+	switch sortBy {
+	case "":
+		if order != "" {
+			return a.errorf("order needs sort_by"), nil
+		}
+	case sortCreated, sortModified, sortDue, sortInterval:
+		if order == "" {
+			order = orderAsc
+			if sortBy == sortCreated || sortBy == sortModified {
+				order = orderDesc
+			}
+		}
+		if order != orderAsc && order != orderDesc {
+			return a.errorf("order must be asc or desc"), nil
+		}
+	default:
+		return a.errorf("sort_by must be created, modified, due or interval"), nil
+	}
+	sortKey := strings.TrimSpace(sortBy + " " + order)
+	var cursor *searchCursor
+	if s, ok := args["cursor"].(string); ok && s != "" {
+		if offset > 0 {
+			return a.errorf("Pass either offset or cursor, not both"), nil
+		}
+		c, err := parseSearchCursor(s)
+		if err != nil {
+			return a.errorf("Invalid cursor; start again without one"), nil
+		}
+		if c.Query != query {
+			return a.errorf("The cursor belongs to the search %s; pass the same query", c.Query), nil
+		}
+		if c.Sort != sortKey {
+			return a.errorf("The cursor belongs to a differently sorted search; pass the same sort_by and order"), nil
+		}
+		cursor = &c
+	}
+
+	// Sorting needs the cards of every matching note. Without it only the
+	// note IDs are needed to find the page, and only its notes are fetched.
+	var (
+		noteIDs []int64
+		results []searchResult
+		err     error
+	)
+	if sortBy != "" {
+		results, err = a.searchNotes(query)
+		if err == nil {
+			sortSearchResults(results, sortBy, order == orderDesc)
+			noteIDs = make([]int64, len(results))
+			for i, r := range results {
+				noteIDs[i] = r.Note.NoteID
+			}
+		}
+	} else {
+		noteIDs, err = a.ankiClient.FindNotes(query)
+	}
+	if err != nil {
+		return a.errorf("Failed to search: %v", err), nil
+	}
+	total := len(noteIDs)
+	start := min(offset, total)
+	if cursor != nil {
+		start = cursor.pageStart(noteIDs)
+	}
+	end := min(start+limit, total)
+	var page []searchResult
+	if sortBy != "" {
+		page = results[start:end]
+	} else if page, err = a.searchNotePage(query, noteIDs[start:end]); err != nil {
+		return a.errorf("Failed to search: %v", err), nil
+	}
+	var next string
+	if end < total {
+		next = searchCursor{Query: query, Sort: sortKey, Offset: end, After: noteIDs[end-1]}.String()
+	}
+
+	if wantsJSON(request) {
+		notes := make([]searchNote, len(page))
+		for i, r := range page {
+			fields := make(map[string]string, len(r.Note.Fields))
+			for name, field := range r.Note.Fields {
+				fields[name] = field.Value
+			}
+			tags := r.Note.Tags
+			if tags == nil {
+				tags = []string{}
+			}
+			notes[i] = searchNote{NoteID: r.Note.NoteID, Model: r.Note.ModelName, Fields: fields, Tags: tags, Deck: r.Deck}
+		}
+		result := map[string]interface{}{
+			"total":  total,
+			"offset": start,
+			"notes":  notes,
+		}
+		if next != "" {
+			result["next_cursor"] = next
+		}
+		return a.jsonResult(result), nil
+	}
+
+	out := a.newOutput()
+	switch {
+	case total == 0:
+		out.Line(a.t("No notes match %s", query))
+	case len(page) == 0:
+		out.Line(a.t("No more notes: %s matches %d note(s)", query, total))
+	default:
+		out.Heading(a.t("Notes matching %s: %d-%d of %d", query, start+1, end, total))
+		for _, r := range page {
+			if len(r.Note.Tags) > 0 {
+				out.Item(a.t("%d [%s, %s]: %s (tags: %s)", r.Note.NoteID, r.Deck, r.Note.ModelName, noteFieldsText(r.Note), strings.Join(r.Note.Tags, ", ")))
+			} else {
+				out.Item(a.t("%d [%s, %s]: %s", r.Note.NoteID, r.Deck, r.Note.ModelName, noteFieldsText(r.Note)))
+			}
+		}
+		if next != "" {
+			out.Line("")
+			out.Line(a.t("More notes follow: pass cursor %s for the next page", next))
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// noteFieldsText lists every field of a note as plain text in the order of
+// its note type, e.g. "Front: el perro; Back: the dog". Empty fields are left
+// out.
+func noteFieldsText(note NoteInfo) string {
+	var parts []string
+	for _, name := range noteFieldNames(note) {
+		if value := plainText(note.Fields[name].Value, 80); value != "" {
+			parts = append(parts, name+": "+value)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// searchKeywords are the prefixes of Anki's search syntax, such as deck: in
+// deck:Spanish. Other prefixes search a field of that name.
+var searchKeywords = []string{
+	"added", "card", "cid", "deck", "did", "dupe", "edited", "flag", "introduced", "is", "mid",
+	"nc", "nid", "note", "preset", "prop", "rated", "re", "resched", "tag", "w",
+}
+
+// Values accepted by the is: and prop: search keywords
+var (
+	searchStates     = []string{"due", "new", "learn", "review", "suspended", "buried", "buried-manually", "buried-sibling"}
+	searchProperties = []string{"ivl", "due", "reps", "lapses", "ease", "pos", "rated", "resched", "s", "d", "r"}
+)
+
+var (
+	searchPropPattern = regexp.MustCompile(`(?i)^([a-z]+(?::\w+)?)(<=|>=|!=|=|<|>)(.+)$`)
+	searchDaysPattern = regexp.MustCompile(`^\d+$`)
+	// rated: also takes the answer button, e.g. rated:7:1
+	searchRatedPattern = regexp.MustCompile(`^\d+(?::[1-4])?$`)
+)
+
+// queryToken is a search term of a query, without the parentheses around
+// it. Start and end are byte offsets in the query.
+type queryToken struct {
+	text       string
+	start, end int
+}
+
+// queryScan is the result of splitting a search query into terms
+type queryScan struct {
+	tokens []queryToken
+	// openQuote is set when the last quotation mark is never closed
+	openQuote bool
+	// strayParens are the offsets of closing parentheses without an
+	// opening one
+	strayParens []int
+	openParens  int
+}
+
+// scanQuery splits a search query into its terms the way Anki does: on
+// whitespace and parentheses outside quotes, with backslash escapes
+func scanQuery(query string) queryScan {
+	var scan queryScan
+	start := -1
+	end := func(i int) {
+		if start >= 0 {
+			scan.tokens = append(scan.tokens, queryToken{text: query[start:i], start: start, end: i})
+			start = -1
+		}
+	}
+	inQuote := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\\':
+			if start < 0 {
+				start = i
+			}
+			i++
+		case c == '"':
+			if start < 0 {
+				start = i
+			}
+			inQuote = !inQuote
+		case inQuote:
+		case c == ' ' || c == '\t' || c == '\n':
+			end(i)
+		case c == '(':
+			end(i)
+			scan.openParens++
+		case c == ')':
+			end(i)
+			if scan.openParens == 0 {
+				scan.strayParens = append(scan.strayParens, i)
+			} else {
+				scan.openParens--
+			}
+		default:
+			if start < 0 {
+				start = i
+			}
+		}
+	}
+	end(len(query))
+	scan.openQuote = inQuote
+	return scan
+}
+
+// searchTerm splits a search term at its first colon, or at an = that comes
+// before any colon, into its prefix and value, without a leading "-" and
+// without quotes. The separator is 0 for plain text.
+func searchTerm(text string) (prefix, value string, sep byte) {
+	text = strings.TrimPrefix(text, "-")
+	var b strings.Builder
+	at := -1
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '\\' && i+1 < len(text):
+			b.WriteByte(c)
+			i++
+			b.WriteByte(text[i])
+		case c == '"':
+		case (c == ':' || c == '=') && at < 0:
+			at, sep = b.Len(), c
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	if at < 0 {
+		return "", b.String(), 0
+	}
+	return b.String()[:at], b.String()[at+1:], sep
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	x, y := []rune(a), []rune(b)
+	prev := make([]int, len(y)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(x); i++ {
+		cur := make([]int, len(y)+1)
+		cur[0] = i
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(y)]
+}
+
+// closestMatch returns the candidate closest to s, ignoring case, if it is at
+// most maxDistance edits away
+func closestMatch(s string, candidates []string, maxDistance int) (string, bool) {
+	best, bestDistance := "", maxDistance+1
+	for _, c := range candidates {
+		if d := editDistance(strings.ToLower(s), strings.ToLower(c)); d < bestDistance {
+			best, bestDistance = c, d
+		}
+	}
+	return best, bestDistance <= maxDistance
+}
+
+// queryEdit replaces query[start:end] with text
+type queryEdit struct {
+	start, end int
+	text       string
+}
+
+// applyQueryEdits applies non-overlapping edits to a query
+func applyQueryEdits(query string, edits []queryEdit) string {
+	slices.SortFunc(edits, func(x, y queryEdit) int { return cmp.Compare(y.start, x.start) })
+	for _, e := range edits {
+		query = query[:e.start] + e.text + query[e.end:]
+	}
+	return query
+}
+
+// queryCheck collects the problems found in a search query and the edits
+// that correct them
+type queryCheck struct {
+	problems []string
+	edits    []queryEdit
+}
+
+// add records a problem and the edits correcting it, if any
+func (c *queryCheck) add(problem string, edits ...queryEdit) {
+	c.problems = append(c.problems, problem)
+	c.edits = append(c.edits, edits...)
+}
+
+// checkQuery looks for common mistakes in a search query: unbalanced quotes
+// and parentheses, = instead of :, unknown prefixes, operators Anki doesn't
+// have, invalid keyword values and unknown decks and note types. Field names
+// are only fetched when a prefix isn't a search keyword.
+func (a *AnkiMCPServer) checkQuery(query string, decks, models []string, fieldNames func() ([]string, error)) (*queryCheck, error) {
+	check := &queryCheck{}
+	scan := scanQuery(query)
+	if scan.openQuote {
+		check.add(a.t("A quotation mark is never closed"), queryEdit{len(query), len(query), `"`})
+	}
+	for _, i := range scan.strayParens {
+		check.add(a.t("The closing parenthesis at position %d has no opening one", i+1), queryEdit{i, i + 1, ""})
+	}
+	if scan.openParens > 0 {
+		check.add(a.t("%d parenthesis(es) are never closed", scan.openParens), queryEdit{len(query), len(query), strings.Repeat(")", scan.openParens)})
+	}
+
+	replace := func(t queryToken, text string) queryEdit {
+		return queryEdit{t.start, t.end, text}
+	}
+	negation := func(t queryToken) string {
+		if strings.HasPrefix(t.text, "-") {
+			return "-"
+		}
+		return ""
+	}
+	// term writes a term with the negation of t, quoted if it holds spaces
+	term := func(t queryToken, prefix, value string) string {
+		text := prefix + ":" + value
+		if strings.ContainsAny(text, " \t") {
+			text = `"` + strings.ReplaceAll(text, `"`, `\"`) + `"`
+		}
+		return negation(t) + text
+	}
+
+	for i := 0; i < len(scan.tokens); i++ {
+		t := scan.tokens[i]
+		switch t.text {
+		case "&&", "||":
+			or := map[string]string{"&&": "and", "||": "or"}[t.text]
+			check.add(a.t("Anki doesn't support %s; use %s", t.text, or), replace(t, or))
+			continue
+		case "NOT":
+			edit := replace(t, "")
+			if i+1 < len(scan.tokens) {
+				edit = queryEdit{t.start, scan.tokens[i+1].start, "-"}
+			}
+			check.add(a.t("Anki doesn't support %s; put - before a term to exclude it", t.text), edit)
+			continue
+		}
+
+		prefix, value, sep := searchTerm(t.text)
+		if sep == '=' {
+			// deck=Spanish instead of deck:Spanish
+			if slices.Contains(searchKeywords, strings.ToLower(prefix)) {
+				check.add(a.t("%s: search keywords are followed by a colon, not =", t.text), replace(t, term(t, prefix, value)))
+			}
+			continue
+		}
+		if sep == 0 {
+			continue
+		}
+
+		keyword := strings.ToLower(prefix)
+		switch keyword {
+		case "is":
+			if !slices.Contains(searchStates, strings.ToLower(value)) {
+				problem := a.t("Unknown state is:%s. Known states: %s", value, strings.Join(searchStates, ", "))
+				if state, ok := closestMatch(value, searchStates, 2); ok {
+					check.add(problem, replace(t, term(t, "is", state)))
+				} else {
+					check.add(problem)
+				}
+			}
+		case "flag":
+			if n, err := strconv.Atoi(value); err != nil || n < 0 || n > 7 {
+				check.add(a.t("%s: flag: takes a number from 0 (no flag) to 7", t.text))
+			}
+		case "prop":
+			m := searchPropPattern.FindStringSubmatch(value)
+			if m == nil {
+				check.add(a.t("%s: prop: needs a property, a comparison and a value, e.g. prop:ivl>=10", t.text))
+				break
+			}
+			name := strings.ToLower(m[1])
+			if !slices.Contains(searchProperties, name) && !strings.HasPrefix(name, "cdn:") && !strings.HasPrefix(name, "cds:") {
+				check.add(a.t("Unknown property %s. Known properties: %s", m[1], strings.Join(searchProperties, ", ")))
+			}
+		case "added", "edited", "introduced":
+			if !searchDaysPattern.MatchString(value) {
+				check.add(a.t("%s: %s: takes a number of days, e.g. %s:7", t.text, keyword, keyword))
+			}
+		case "rated":
+			if !searchRatedPattern.MatchString(value) {
+				check.add(a.t("%s: %s: takes a number of days, e.g. %s:7", t.text, keyword, keyword))
+			}
+		case "deck":
+			if value == "" || strings.Contains(value, "*") || slices.Contains([]string{"current", "filtered"}, strings.ToLower(value)) ||
+				slices.ContainsFunc(decks, func(d string) bool { return strings.EqualFold(d, value) }) {
+				break
+			}
+			// An unquoted deck name with spaces is cut at the first space
+			joined, found := value, false
+			for j := i + 1; j < len(scan.tokens) && j <= i+3 && !found; j++ {
+				joined += " " + scan.tokens[j].text
+				for _, d := range decks {
+					if strings.EqualFold(d, joined) {
+						suggestion := term(t, prefix, d)
+						check.add(a.t("Deck names with spaces must be quoted: %s", suggestion), queryEdit{t.start, scan.tokens[j].end, suggestion})
+						i, found = j, true
+						break
+					}
+				}
+			}
+			if found {
+				break
+			}
+			if deck, ok := closestMatch(value, decks, 3); ok {
+				check.add(a.t("No deck named %s; did you mean %s?", value, deck), replace(t, term(t, prefix, deck)))
+			} else {
+				check.add(a.t("No deck named %s", value))
+			}
+		case "note":
+			if value == "" || strings.Contains(value, "*") || slices.ContainsFunc(models, func(m string) bool { return strings.EqualFold(m, value) }) {
+				break
+			}
+			if model, ok := closestMatch(value, models, 3); ok {
+				check.add(a.t("No note type named %s; did you mean %s?", value, model), replace(t, term(t, prefix, model)))
+			} else {
+				check.add(a.t("No note type named %s", value))
+			}
+		default:
+			if slices.Contains(searchKeywords, keyword) {
+				break
+			}
+			fields, err := fieldNames()
+			if err != nil {
+				return nil, err
+			}
+			if slices.ContainsFunc(fields, func(f string) bool { return strings.EqualFold(f, prefix) }) {
+				break
+			}
+			if kw, ok := closestMatch(keyword, searchKeywords, 2); ok && len(keyword) > 2 {
+				check.add(a.t("Unknown prefix %s:; did you mean %s:?", prefix, kw), replace(t, term(t, kw, value)))
+			} else if field, ok := closestMatch(prefix, fields, 2); ok {
+				check.add(a.t("Unknown prefix %s:; did you mean %s:?", prefix, field), replace(t, term(t, field, value)))
+			} else {
+				// Escaping the colon searches for the text instead
+				colon := t.start + strings.Index(t.text, ":")
+				check.add(a.t("%s: is neither a search keyword nor a field name, so the term matches nothing; write \\: to search for a colon", prefix),
+					queryEdit{colon, colon, `\`})
+			}
+		}
+	}
+	return check, nil
+}
+
+// handleValidateQuery checks a search query for mistakes and counts the cards
+// it matches
+func (a *AnkiMCPServer) handleValidateQuery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	query, _ := args["query"].(string)
+	if strings.TrimSpace(query) == "" {
+		return a.errorf("query is required"), nil
+	}
+
+	var decks, models []string
+	b := a.ankiClient.newBatch()
+	b.add("deckNames", nil, &decks)
+	b.add("modelNames", nil, &models)
+	if err := b.send(); err != nil {
+		return a.errorf("Failed to get decks: %v", err), nil
+	}
+	var fields []string
+	fieldNames := func() ([]string, error) {
+		if fields != nil {
+			return fields, nil
+		}
+		modelFields := make([][]string, len(models))
+		b := a.ankiClient.newBatch()
+		for i, model := range models {
+			b.add("modelFieldNames", map[string]string{"modelName": model}, &modelFields[i])
+		}
+		if err := b.send(); err != nil {
+			return nil, err
+		}
+		fields = []string{}
+		for _, names := range modelFields {
+			for _, name := range names {
+				if !slices.Contains(fields, name) {
+					fields = append(fields, name)
+				}
+			}
+		}
+		return fields, nil
+	}
+
+	check, err := a.checkQuery(query, decks, models, fieldNames)
+	if err != nil {
+		return a.errorf("Failed to get fields: %v", err), nil
+	}
+	suggested := ""
+	if len(check.edits) > 0 {
+		suggested = applyQueryEdits(query, check.edits)
+	}
+
+	// Count-only probes of the query and the suggested correction
+	probe := func(q string) (int, string) {
+		cardIDs, err := a.ankiClient.FindCards(q)
+		if err != nil {
+			return 0, err.Error()
+		}
+		return len(cardIDs), ""
+	}
+	cards, probeErr := probe(query)
+	suggestedCards, suggestedErr := 0, ""
+	if suggested != "" {
+		suggestedCards, suggestedErr = probe(suggested)
+	}
+
+	if wantsJSON(request) {
+		result := map[string]interface{}{
+			"query":    query,
+			"valid":    len(check.problems) == 0 && probeErr == "",
+			"problems": append([]string{}, check.problems...),
+		}
+		if probeErr != "" {
+			result["error"] = probeErr
+		} else {
+			result["cards"] = cards
+		}
+		if suggested != "" {
+			result["suggested_query"] = suggested
+			if suggestedErr != "" {
+				result["suggested_error"] = suggestedErr
+			} else {
+				result["suggested_cards"] = suggestedCards
+			}
+		}
+		return a.jsonResult(result), nil
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Query: %s", query))
+	if len(check.problems) == 0 {
+		out.Line(a.t("No problems found."))
+	}
+	for _, problem := range check.problems {
+		out.Item(problem)
+	}
+	if probeErr != "" {
+		out.Line(a.t("Anki rejected the query: %s", probeErr))
+	} else {
+		out.Line(a.t("The query matches %d card(s).", cards))
+	}
+	if suggested != "" {
+		out.Line("")
+		out.Line(a.t("Suggested query: %s", suggested))
+		if suggestedErr != "" {
+			out.Line(a.t("Anki rejected the suggested query too: %s", suggestedErr))
+		} else {
+			out.Line(a.t("The suggested query matches %d card(s).", suggestedCards))
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// nextCursor returns the cursor for the next page from search_cards output
+func nextCursor(text string) string {
+	m := regexp.MustCompile(`pass cursor (\S+)`).FindStringSubmatch(text)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// listItems returns the list entries of tool output
+func listItems(text string) []string {
+	var items []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, "- ") {
+			items = append(items, line)
+		}
+	}
+	return items
+}
+
+func TestSearchCards(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	text, isErr := callTool(t, server.handleSearchCards, map[string]interface{}{"query": "deck:Spanish", "limit": float64(3)})
+	if isErr {
+		t.Fatalf("search_cards failed: %s", text)
+	}
+	if !strings.Contains(text, "Notes matching deck:Spanish: 1-3 of 7") {
+		t.Errorf("Unexpected heading: %s", text)
+	}
+	if got := strings.Count(text, "\n- "); got != 3 {
+		t.Errorf("Expected 3 notes, got %d: %s", got, text)
+	}
+	if !strings.Contains(text, "[Spanish::Vocabulary, ") {
+		t.Errorf("Expected the deck of each note: %s", text)
+	}
+
+	text, _ = callTool(t, server.handleSearchCards, map[string]interface{}{"query": "deck:Missing"})
+	if text != "No notes match deck:Missing" {
+		t.Errorf("Unexpected output for no matches: %s", text)
+	}
+	if _, isErr := callTool(t, server.handleSearchCards, map[string]interface{}{}); !isErr {
+		t.Error("Expected an error without a query")
+	}
+}
+
+func TestSearchCardsPagination(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+	args := map[string]interface{}{"query": "deck:Spanish", "limit": float64(3)}
+
+	first, _ := callTool(t, server.handleSearchCards, args)
+	byOffset, _ := callTool(t, server.handleSearchCards, map[string]interface{}{"query": "deck:Spanish", "limit": float64(3), "offset": float64(3)})
+	if !strings.Contains(byOffset, "4-6 of 7") || nextCursor(byOffset) == "" {
+		t.Errorf("Unexpected page at offset 3: %s", byOffset)
+	}
+
+	// The cursor continues after the last note shown, even when an earlier
+	// note is deleted in between
+	cursor := nextCursor(first)
+	if cursor == "" {
+		t.Fatalf("Expected a cursor: %s", first)
+	}
+	notes, _ := mock.search("deck:Spanish")
+	if err := server.ankiClient.DeleteNotes([]int64{notes[0].NoteID}); err != nil {
+		t.Fatal(err)
+	}
+	second, _ := callTool(t, server.handleSearchCards, map[string]interface{}{"query": "deck:Spanish", "limit": float64(3), "cursor": cursor})
+	if !strings.Contains(second, "3-5 of 6") || !slices.Equal(listItems(second), listItems(byOffset)) {
+		t.Errorf("Expected the cursor to continue with the same notes as offset 3 before the deletion:\n%s\n%s", second, byOffset)
+	}
+
+	last, _ := callTool(t, server.handleSearchCards, map[string]interface{}{"query": "deck:Spanish", "limit": float64(3), "cursor": nextCursor(second)})
+	if !strings.Contains(last, "6-6 of 6") || nextCursor(last) != "" {
+		t.Errorf("Expected a last page without cursor: %s", last)
+	}
+
+	for _, bad := range []map[string]interface{}{
+		{"query": "deck:Spanish", "cursor": cursor, "offset": float64(3)},
+		{"query": "deck:Spanish", "cursor": "not a cursor"},
+		{"query": "tag:spanish", "cursor": cursor},
+	} {
+		if text, isErr := callTool(t, server.handleSearchCards, bad); !isErr {
+			t.Errorf("Expected an error for %v, got %s", bad, text)
+		}
+	}
+	text, _ := callTool(t, server.handleSearchCards, map[string]interface{}{"query": "deck:Spanish", "offset": float64(50)})
+	if text != "No more notes: deck:Spanish matches 6 note(s)" {
+		t.Errorf("Unexpected output past the end: %s", text)
+	}
+}
+
+// actionLog records the AnkiConnect actions sent, with the actions inside
+// multi requests
+type actionLog struct {
+	next    http.RoundTripper
+	actions []ankiRequest
+}
+
+func (l *actionLog) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	var r struct {
+		Action string          `json:"action"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, err
+	}
+	if r.Action == "multi" {
+		var p struct {
+			Actions []ankiRequest `json:"actions"`
+		}
+		if err := json.Unmarshal(r.Params, &p); err != nil {
+			return nil, err
+		}
+		l.actions = append(l.actions, p.Actions...)
+	} else {
+		var params interface{}
+		_ = json.Unmarshal(r.Params, &params)
+		l.actions = append(l.actions, ankiRequest{Action: r.Action, Params: params})
+	}
+	return l.next.RoundTrip(req)
+}
+
+func TestSearchCardsFetchesOnlyThePage(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+	log := &actionLog{next: http.DefaultTransport}
+	server.ankiClient.client.Transport = log
+
+	text, isErr := callTool(t, server.handleSearchCards, map[string]interface{}{"query": "deck:Spanish", "limit": float64(2), "offset": float64(2)})
+	if isErr || !strings.Contains(text, "3-4 of 7") || len(listItems(text)) != 2 {
+		t.Fatalf("Unexpected page: %s", text)
+	}
+	fetched := 0
+	for _, action := range log.actions {
+		params, _ := action.Params.(map[string]interface{})
+		switch action.Action {
+		case "notesInfo":
+			fetched++
+			if notes, _ := params["notes"].([]interface{}); len(notes) != 2 {
+				t.Errorf("Expected notesInfo for the 2 notes of the page, got %v", params)
+			}
+		case "cardsInfo":
+			if cards, _ := params["cards"].([]interface{}); len(cards) > 4 {
+				t.Errorf("Expected cardsInfo only for cards of the page, got %v", params)
+			}
+		}
+	}
+	if fetched != 1 {
+		t.Errorf("Expected one notesInfo request, got %d", fetched)
+	}
+
+	// The page matches the same page of the full search
+	ids, _ := server.ankiClient.FindNotes("deck:Spanish")
+	for _, id := range ids[2:4] {
+		if !strings.Contains(text, strconv.FormatInt(id, 10)) {
+			t.Errorf("Expected note %d on the page: %s", id, text)
+		}
+	}
+}
+
+func TestSearchCardsJSON(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	text, isErr := callTool(t, server.handleSearchCards, map[string]interface{}{"query": "deck:Spanish::Grammar", "format": "json"})
+	if isErr {
+		t.Fatalf("search_cards failed: %s", text)
+	}
+	var result struct {
+		Total      int          `json:"total"`
+		Offset     int          `json:"offset"`
+		NextCursor string       `json:"next_cursor"`
+		Notes      []searchNote `json:"notes"`
+	}
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		t.Fatalf("Expected JSON, got %s: %v", text, err)
+	}
+	if result.Total != 1 || result.Offset != 0 || result.NextCursor != "" || len(result.Notes) != 1 {
+		t.Fatalf("Unexpected result: %s", text)
+	}
+	note := result.Notes[0]
+	if note.NoteID == 0 || note.Model != "Cloze" || note.Deck != "Spanish::Grammar" || note.Tags == nil || !strings.Contains(note.Fields["Text"], "{{c1::") {
+		t.Errorf("Unexpected note: %+v", note)
+	}
+
+	text, _ = callTool(t, server.handleSearchCards, map[string]interface{}{"query": "deck:Spanish", "limit": float64(2), "format": "json"})
+	if err := json.Unmarshal([]byte(text), &result); err != nil || result.Total != 7 || len(result.Notes) != 2 || result.NextCursor == "" {
+		t.Errorf("Unexpected first page: %s", text)
+	}
+}
+
+func TestSearchCardsShowsAllFields(t *testing.T) {
+	server, _ := newMockServer(t)
+	if err := server.ankiClient.CreateDeck("Words"); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.ankiClient.CreateModel("Vocab", []string{"Word", "Meaning", "Example", "Notes"}, []CardTemplate{{Name: "Card 1", Front: "{{Word}}", Back: "{{Meaning}}"}}, ""); err != nil {
+		t.Fatal(err)
+	}
+	_, err := server.ankiClient.AddNote(Note{DeckName: "Words", ModelName: "Vocab", Fields: map[string]string{
+		"Word": "perro", "Meaning": "<b>dog</b>", "Example": "El perro ladra.",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text, _ := callTool(t, server.handleSearchCards, map[string]interface{}{"query": "deck:Words"})
+	if !strings.Contains(text, "[Words, Vocab]: Word: perro; Meaning: dog; Example: El perro ladra.") {
+		t.Errorf("Expected every field in note type order: %s", text)
+	}
+	if strings.Contains(text, "Notes:") {
+		t.Errorf("Expected empty fields to be left out: %s", text)
+	}
+}
+
+func TestSearchCardsSorting(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+	search := func(args map[string]interface{}) []searchNote {
+		t.Helper()
+		args["query"] = "deck:Spanish"
+		args["format"] = "json"
+		text, isErr := callTool(t, server.handleSearchCards, args)
+		var result struct {
+			Notes []searchNote `json:"notes"`
+		}
+		if isErr || json.Unmarshal([]byte(text), &result) != nil {
+			t.Fatalf("search_cards %v: %s", args, text)
+		}
+		return result.Notes
+	}
+
+	newest := search(map[string]interface{}{"sort_by": "created", "limit": float64(20)})
+	for i := 1; i < len(newest); i++ {
+		if newest[i-1].NoteID < newest[i].NoteID {
+			t.Fatalf("Expected the newest notes first, got %d before %d", newest[i-1].NoteID, newest[i].NoteID)
+		}
+	}
+	oldest := search(map[string]interface{}{"sort_by": "created", "order": "asc", "limit": float64(1)})
+	if len(oldest) != 1 || oldest[0].NoteID != newest[len(newest)-1].NoteID {
+		t.Errorf("Expected the oldest note with order=asc, got %v", oldest)
+	}
+
+	results, err := server.searchNotes("deck:Spanish")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sortSearchResults(results, sortDue, false)
+	for i := 1; i < len(results); i++ {
+		if compareDue(firstDue(results[i-1].Cards), firstDue(results[i].Cards)) > 0 {
+			t.Errorf("Note %d is due after note %d", results[i-1].Note.NoteID, results[i].Note.NoteID)
+		}
+	}
+	sortSearchResults(results, sortInterval, true)
+	for i := 1; i < len(results); i++ {
+		if shortestInterval(results[i-1].Cards) < shortestInterval(results[i].Cards) {
+			t.Errorf("Note %d has a shorter interval than note %d", results[i-1].Note.NoteID, results[i].Note.NoteID)
+		}
+	}
+
+	// A cursor only continues the sort it was made with
+	text, _ := callTool(t, server.handleSearchCards, map[string]interface{}{"query": "deck:Spanish", "sort_by": "due", "limit": float64(2)})
+	cursor := nextCursor(text)
+	if _, isErr := callTool(t, server.handleSearchCards, map[string]interface{}{"query": "deck:Spanish", "sort_by": "due", "limit": float64(2), "cursor": cursor}); isErr {
+		t.Error("Expected the cursor to continue the same sort")
+	}
+	if _, isErr := callTool(t, server.handleSearchCards, map[string]interface{}{"query": "deck:Spanish", "limit": float64(2), "cursor": cursor}); !isErr {
+		t.Error("Expected an error for a cursor of a differently sorted search")
+	}
+	for _, bad := range []map[string]interface{}{{"order": "asc"}, {"sort_by": "size"}, {"sort_by": "due", "order": "up"}} {
+		bad["query"] = "deck:Spanish"
+		if text, isErr := callTool(t, server.handleSearchCards, bad); !isErr {
+			t.Errorf("Expected an error for %v, got %s", bad, text)
+		}
+	}
+}
+
+func TestCompareDue(t *testing.T) {
+	learning := CardInfo{Queue: 1, Type: 1, Due: 1_700_000_000}
+	review := CardInfo{Queue: 2, Type: 2, Due: 5}
+	later := CardInfo{Queue: 2, Type: 2, Due: 9}
+	newCard := CardInfo{Queue: 0, Type: 0, Due: 1}
+	cards := []CardInfo{newCard, later, review, learning}
+	slices.SortFunc(cards, compareDue)
+	var dues []int64
+	for _, card := range cards {
+		dues = append(dues, card.Due)
+	}
+	if !slices.Equal(dues, []int64{learning.Due, review.Due, later.Due, newCard.Due}) {
+		t.Errorf("Unexpected due order: %+v", cards)
+	}
+}
+
+func TestScanQuery(t *testing.T) {
+	scan := scanQuery(`(deck:"My Deck" -tag:a\ b) or "front:x`)
+	var terms []string
+	for _, token := range scan.tokens {
+		terms = append(terms, token.text)
+	}
+	want := []string{`deck:"My Deck"`, `-tag:a\ b`, "or", `"front:x`}
+	if !slices.Equal(terms, want) || !scan.openQuote || scan.openParens != 0 || len(scan.strayParens) != 0 {
+		t.Errorf("Unexpected scan %q %+v", terms, scan)
+	}
+
+	if scan := scanQuery("a) (b"); len(scan.strayParens) != 1 || scan.strayParens[0] != 1 || scan.openParens != 1 {
+		t.Errorf("Expected one stray and one open parenthesis, got %+v", scan)
+	}
+}
+
+func TestValidateQuery(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+	if err := server.ankiClient.CreateDeck("Word Lists"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query     string
+		problems  []string
+		suggested string
+	}{
+		{"Front:perro tag:spanish", nil, ""},
+		{`deck="Word Lists" tag:spanish`, []string{"followed by a colon"}, `"deck:Word Lists" tag:spanish`},
+		{"deck=Spanish tag:spanish", []string{"followed by a colon"}, "deck:Spanish tag:spanish"},
+		{"dek:Spanish", []string{"Unknown prefix dek:; did you mean deck:?"}, "deck:Spanish"},
+		{"Frnt:perro", []string{"did you mean Front:?"}, "Front:perro"},
+		{"deck:Spanisch", []string{"No deck named Spanisch; did you mean Spanish?"}, "deck:Spanish"},
+		{"-deck:Word Lists", []string{"must be quoted"}, `-"deck:Word Lists"`},
+		{"note:Clozee", []string{"did you mean Cloze?"}, "note:Cloze"},
+		{"(is:suspend tag:grammar", []string{"1 parenthesis(es) are never closed", "Unknown state is:suspend"}, "(is:suspended tag:grammar)"},
+		{`"deck:Spanish`, []string{"quotation mark is never closed"}, `"deck:Spanish"`},
+		{"NOT tag:grammar && is:due)", []string{"closing parenthesis at position 26", "doesn't support NOT", "doesn't support &&"}, "-tag:grammar and is:due"},
+		{"flag:9 prop:ivl rated:x added:3", []string{"flag: takes a number", "prop: needs a property", "rated: takes a number of days"}, ""},
+		{"http://example.com", []string{"neither a search keyword nor a field name"}, `http\://example.com`},
+	}
+	for _, tt := range tests {
+		text, isErr := callTool(t, server.handleValidateQuery, map[string]interface{}{"query": tt.query, "format": "json"})
+		var result struct {
+			Valid     bool     `json:"valid"`
+			Problems  []string `json:"problems"`
+			Suggested string   `json:"suggested_query"`
+		}
+		if err := json.Unmarshal([]byte(text), &result); isErr || err != nil {
+			t.Fatalf("Unexpected output for %q: %s", tt.query, text)
+		}
+		if len(result.Problems) != len(tt.problems) || result.Suggested != tt.suggested {
+			t.Errorf("%q: expected %d problem(s) and suggestion %q, got %s", tt.query, len(tt.problems), tt.suggested, text)
+			continue
+		}
+		for i, want := range tt.problems {
+			if !strings.Contains(result.Problems[i], want) {
+				t.Errorf("%q: expected %q in problem %q", tt.query, want, result.Problems[i])
+			}
+		}
+		if result.Valid != (len(tt.problems) == 0) {
+			t.Errorf("%q: expected valid to be %v", tt.query, len(tt.problems) == 0)
+		}
+	}
+
+	text, isErr := callTool(t, server.handleValidateQuery, map[string]interface{}{"query": "deck=Spanish::Grammar"})
+	for _, want := range []string{"Query: deck=Spanish::Grammar", "Suggested query: deck:Spanish::Grammar", "The suggested query matches 2 card(s)."} {
+		if isErr || !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the output, got %s", want, text)
+		}
+	}
+	text, _ = callTool(t, server.handleValidateQuery, map[string]interface{}{"query": "is:foo"})
+	if !strings.Contains(text, "Unknown state is:foo") || !strings.Contains(text, "Anki rejected the query") {
+		t.Errorf("Expected the state problem and the rejected probe, got %s", text)
+	}
+}
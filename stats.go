@@ -0,0 +1,664 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// tagStats holds aggregated statistics for a single tag
+type tagStats struct {
+	Tag        string  `json:"tag"`
+	Notes      int     `json:"notes"`
+	Cards      int     `json:"cards"`
+	Due        int     `json:"due"`
+	AvgEase    float64 `json:"avg_ease"`
+	AvgLapses  float64 `json:"avg_lapses"`
+	easeSum    float64
+	easeCount  int
+	lapsesSum  float64
+	noteIDSeen map[int64]bool
+}
+
+// registerStatsTools registers statistics and reporting tools with the MCP server
+func (a *AnkiMCPServer) registerStatsTools(s *server.MCPServer) {
+	// Tool: Tag Statistics
+	tagStatsTool := mcp.NewTool("tag_stats",
+		mcp.WithDescription("Aggregate note counts, due counts, average ease and average lapses grouped by tag, ranked by difficulty. Useful when tags are used as topics."),
+		mcp.WithString("deck",
+			mcp.Description("Optional: Only include cards from this deck (and its subdecks)"),
+		),
+		mcp.WithString("sort_by",
+			mcp.Description("Optional: Ranking order (default: ease, hardest first)"),
+			mcp.Enum("ease", "lapses", "due", "notes"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Optional: Maximum number of tags to return (default: 50)"),
+		),
+		withFormat(),
+	)
+	a.addTool(s, tagStatsTool, (*AnkiMCPServer).handleTagStats)
+
+	// Tool: Progress Report
+	progressReportTool := mcp.NewTool("progress_report",
+		mcp.WithDescription("Compare learning progress between two dates using the review log: reviews done, cards learned, cards matured, retention and study time, plus the number of mature cards at the start and end of the period."),
+		mcp.WithString("start_date",
+			mcp.Description("Optional: First day of the period, YYYY-MM-DD (default: 30 days before end_date)"),
+		),
+		mcp.WithString("end_date",
+			mcp.Description("Optional: Last day of the period, YYYY-MM-DD (default: today)"),
+		),
+		mcp.WithString("deck",
+			mcp.Description("Optional: Only include reviews from this deck (and its subdecks)"),
+		),
+		withFormat(),
+	)
+	a.addTool(s, progressReportTool, (*AnkiMCPServer).handleProgressReport)
+
+	// Tool: Collection Statistics
+	collectionStatsTool := mcp.NewTool("get_collection_stats",
+		mcp.WithDescription("Get key metrics from Anki's statistics report for the whole collection: card and note totals, mature cards, average ease and interval, retention over the last month and today's study time"),
+		withFormat(),
+	)
+	a.addTool(s, collectionStatsTool, (*AnkiMCPServer).handleCollectionStats)
+
+	// Tool: Review History
+	reviewHistoryTool := mcp.NewTool("review_history",
+		mcp.WithDescription("Number of reviews per day, week or month, with the number of days studied. Useful to discuss study habits and consistency."),
+		mcp.WithString("start_date",
+			mcp.Description("Optional: First day, YYYY-MM-DD (default: 30 days before end_date)"),
+		),
+		mcp.WithString("end_date",
+			mcp.Description("Optional: Last day, YYYY-MM-DD (default: today)"),
+		),
+		mcp.WithString("group_by",
+			mcp.Description("Optional: Period to add the reviews up by (default: day)"),
+			mcp.Enum("day", "week", "month"),
+		),
+		withFormat(),
+	)
+	a.addTool(s, reviewHistoryTool, (*AnkiMCPServer).handleReviewHistory)
+}
+
+// handleTagStats aggregates scheduling statistics per tag
+func (a *AnkiMCPServer) handleTagStats(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	query := "deck:*"
+	deckName, _ := args["deck"].(string)
+	if deckName != "" {
+		query = deckQuery(deckName)
+	}
+
+	sortBy, _ := args["sort_by"].(string)
+	if sortBy == "" {
+		sortBy = "ease"
+	}
+
+	limit := 50
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	// The searches and the notes behind the cards, whose tags are needed,
+	// are fetched in one request
+	var (
+		cardIDs, dueIDs []int64
+		notes           []NoteInfo
+	)
+	b := a.ankiClient.newBatch()
+	b.add("findCards", map[string]string{"query": query}, &cardIDs)
+	b.add("findCards", map[string]string{"query": query + " is:due"}, &dueIDs)
+	b.add("notesInfo", map[string]string{"query": query}, &notes)
+	if err := b.send(); err != nil {
+		return a.errorf("Failed to find cards: %v", err), nil
+	}
+	if len(cardIDs) == 0 {
+		return a.errorf("No cards found for query: %s", query), nil
+	}
+
+	due := make(map[int64]bool, len(dueIDs))
+	for _, id := range dueIDs {
+		due[id] = true
+	}
+
+	cards, err := a.ankiClient.GetCardsInfo(cardIDs)
+	if err != nil {
+		return a.errorf("Failed to get card info: %v", err), nil
+	}
+
+	noteTags := make(map[int64][]string, len(notes))
+	for _, note := range notes {
+		noteTags[note.NoteID] = note.Tags
+	}
+
+	stats := aggregateTagStats(cards, noteTags, due)
+	sortTagStats(stats, sortBy)
+
+	total := len(stats)
+	if len(stats) > limit {
+		stats = stats[:limit]
+	}
+
+	if wantsJSON(request) {
+		return a.jsonResult(map[string]interface{}{
+			"deck":       deckName,
+			"sort_by":    sortBy,
+			"total_tags": total,
+			"tags":       stats,
+		}), nil
+	}
+
+	scope := a.t("all decks")
+	if deckName != "" {
+		scope = deckName
+	}
+	out := a.newOutput()
+	out.Heading(a.t("Tag statistics for %s (%d of %d tags, sorted by %s)", scope, len(stats), total, sortBy))
+	for _, st := range stats {
+		out.Item(a.t("%s: %d notes, %d cards, %d due, avg ease %.0f%%, avg lapses %.2f",
+			st.Tag, st.Notes, st.Cards, st.Due, st.AvgEase, st.AvgLapses))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// aggregateTagStats groups card scheduling data by the tags of each card's note.
+// Ease is only averaged over cards that have been reviewed (new cards have no ease).
+func aggregateTagStats(cards []CardInfo, noteTags map[int64][]string, due map[int64]bool) []*tagStats {
+	byTag := make(map[string]*tagStats)
+	for _, card := range cards {
+		noteID := card.NoteID
+		for _, tag := range noteTags[noteID] {
+			st, ok := byTag[tag]
+			if !ok {
+				st = &tagStats{Tag: tag, noteIDSeen: make(map[int64]bool)}
+				byTag[tag] = st
+			}
+			if !st.noteIDSeen[noteID] {
+				st.noteIDSeen[noteID] = true
+				st.Notes++
+			}
+			st.Cards++
+			if due[card.CardID] {
+				st.Due++
+			}
+			if card.Factor > 0 {
+				st.easeSum += float64(card.Factor) / 10
+				st.easeCount++
+			}
+			st.lapsesSum += float64(card.Lapses)
+		}
+	}
+
+	stats := make([]*tagStats, 0, len(byTag))
+	for _, st := range byTag {
+		if st.easeCount > 0 {
+			st.AvgEase = st.easeSum / float64(st.easeCount)
+		}
+		st.AvgLapses = st.lapsesSum / float64(st.Cards)
+		stats = append(stats, st)
+	}
+	return stats
+}
+
+// sortTagStats orders tag statistics so the most difficult or most relevant tags come first
+func sortTagStats(stats []*tagStats, sortBy string) {
+	sort.Slice(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		switch sortBy {
+		case "lapses":
+			if a.AvgLapses != b.AvgLapses {
+				return a.AvgLapses > b.AvgLapses
+			}
+		case "due":
+			if a.Due != b.Due {
+				return a.Due > b.Due
+			}
+		case "notes":
+			if a.Notes != b.Notes {
+				return a.Notes > b.Notes
+			}
+		default:
+			// Tags without reviewed cards have no ease and are ranked last
+			if (a.AvgEase == 0) != (b.AvgEase == 0) {
+				return b.AvgEase == 0
+			}
+			if a.AvgEase != b.AvgEase {
+				return a.AvgEase < b.AvgEase
+			}
+		}
+		return a.Tag < b.Tag
+	})
+}
+
+// matureInterval is the interval in days from which Anki considers a card mature
+const matureInterval = 21
+
+// progressReport summarizes review activity within a date range
+type progressReport struct {
+	Start         time.Time `json:"-"`
+	End           time.Time `json:"-"`
+	Reviews       int       `json:"reviews"`
+	CardsStudied  int       `json:"cards_studied"`
+	CardsLearned  int       `json:"cards_learned"`
+	CardsMatured  int       `json:"cards_matured"`
+	ReviewAnswers int       `json:"review_answers"`
+	Retention     float64   `json:"retention"`
+	StudyTime     int64     `json:"study_time_seconds"`
+	MatureAtStart int       `json:"mature_at_start"`
+	MatureAtEnd   int       `json:"mature_at_end"`
+}
+
+// handleProgressReport reports progress between two dates based on the review log
+func (a *AnkiMCPServer) handleProgressReport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	start, end, err := parseDateRange(args, 30)
+	if err != nil {
+		return a.errorf("%v", err), nil
+	}
+
+	deckName, _ := args["deck"].(string)
+
+	// The full history is needed to tell first reviews and maturity at the start of the period
+	entries, err := a.collectReviews(deckName, 0)
+	if err != nil {
+		return a.errorf("Failed to get review log: %v", err), nil
+	}
+
+	report := computeProgress(entries, start, end)
+
+	if wantsJSON(request) {
+		return a.jsonResult(map[string]interface{}{
+			"deck":       deckName,
+			"start_date": start.Format(dateLayout),
+			"end_date":   end.AddDate(0, 0, -1).Format(dateLayout),
+			"progress":   report,
+		}), nil
+	}
+
+	scope := a.t("all decks")
+	if deckName != "" {
+		scope = deckName
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Progress for %s from %s to %s", scope,
+		a.loc.FormatDate(start), a.loc.FormatDate(end.AddDate(0, 0, -1))))
+	out.Item(a.t("Reviews done: %d (%d distinct cards)", report.Reviews, report.CardsStudied))
+	out.Item(a.t("Cards learned: %d", report.CardsLearned))
+	out.Item(a.t("Cards matured: %d", report.CardsMatured))
+	out.Item(a.t("Mature cards: %d -> %d", report.MatureAtStart, report.MatureAtEnd))
+	if report.ReviewAnswers > 0 {
+		out.Item(a.t("Retention: %.1f%% of %d review answers", report.Retention*100, report.ReviewAnswers))
+	} else {
+		out.Item(a.t("Retention: no reviews of graduated cards in this period"))
+	}
+	out.Item(a.t("Study time: %s", (time.Duration(report.StudyTime) * time.Second).String()))
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// collectReviews gathers review log entries for a deck including its subdecks,
+// or for the whole collection when deckName is empty
+func (a *AnkiMCPServer) collectReviews(deckName string, startID int64) ([]ReviewEntry, error) {
+	decks, err := a.ankiClient.GetDeckNames()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []ReviewEntry
+	for _, deck := range decks {
+		if deckName != "" && deck != deckName && !strings.HasPrefix(deck, deckName+"::") {
+			continue
+		}
+		deckEntries, err := a.ankiClient.CardReviews(deck, startID)
+		if err != nil {
+			return nil, fmt.Errorf("deck %s: %w", deck, err)
+		}
+		entries = append(entries, deckEntries...)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ReviewTime < entries[j].ReviewTime
+	})
+	return entries, nil
+}
+
+// computeProgress derives progress metrics for [start, end) from a review log sorted by time
+func computeProgress(entries []ReviewEntry, start, end time.Time) progressReport {
+	report := progressReport{Start: start, End: end}
+	startMs, endMs := start.UnixMilli(), end.UnixMilli()
+
+	firstReview := make(map[int64]int64)
+	studied := make(map[int64]bool)
+	matured := make(map[int64]bool)
+	intervalAtStart := make(map[int64]int64)
+	intervalAtEnd := make(map[int64]int64)
+	passed := 0
+
+	for _, e := range entries {
+		if _, ok := firstReview[e.CardID]; !ok {
+			firstReview[e.CardID] = e.ReviewTime
+		}
+		if e.ReviewTime < startMs {
+			intervalAtStart[e.CardID] = e.NewInterval
+		}
+		if e.ReviewTime >= endMs {
+			continue
+		}
+		intervalAtEnd[e.CardID] = e.NewInterval
+		if e.ReviewTime < startMs {
+			continue
+		}
+
+		report.Reviews++
+		report.StudyTime += e.ReviewDuration
+		studied[e.CardID] = true
+		if e.NewInterval >= matureInterval && e.PreviousInterval < matureInterval {
+			matured[e.CardID] = true
+		}
+		if e.ReviewType == 1 {
+			report.ReviewAnswers++
+			if e.ButtonPressed > 1 {
+				passed++
+			}
+		}
+	}
+
+	for _, first := range firstReview {
+		if first >= startMs && first < endMs {
+			report.CardsLearned++
+		}
+	}
+	for _, ivl := range intervalAtStart {
+		if ivl >= matureInterval {
+			report.MatureAtStart++
+		}
+	}
+	for _, ivl := range intervalAtEnd {
+		if ivl >= matureInterval {
+			report.MatureAtEnd++
+		}
+	}
+
+	report.CardsStudied = len(studied)
+	report.CardsMatured = len(matured)
+	report.StudyTime /= 1000
+	if report.ReviewAnswers > 0 {
+		report.Retention = float64(passed) / float64(report.ReviewAnswers)
+	}
+	return report
+}
+
+// collectionStats holds the metrics read from Anki's statistics report
+type collectionStats struct {
+	TotalCards       int     `json:"total_cards"`
+	TotalNotes       int     `json:"total_notes"`
+	MatureCards      int     `json:"mature_cards"`
+	MaturePercent    float64 `json:"mature_percent"`
+	AverageEase      float64 `json:"average_ease,omitempty"`
+	AverageInterval  string  `json:"average_interval,omitempty"`
+	Retention        float64 `json:"retention,omitempty"`
+	RetentionAnswers int     `json:"retention_answers"`
+	StudiedToday     int     `json:"studied_today"`
+	StudyTimeToday   int64   `json:"study_time_today_seconds"`
+}
+
+var (
+	statsBreakPattern        = regexp.MustCompile(`(?i)<br\s*/?>|</(?:tr|td|div|p|h\d|center|table)>`)
+	statsSpacePattern        = regexp.MustCompile(`[ \t]+`)
+	statsTotalCardsPattern   = regexp.MustCompile(`Total cards:\s*([\d,]+)`)
+	statsTotalNotesPattern   = regexp.MustCompile(`Total notes:\s*([\d,]+)`)
+	statsMaturePattern       = regexp.MustCompile(`\bMature:\s*([\d,]+)`)
+	statsAverageEasePattern  = regexp.MustCompile(`Average ease:\s*([\d.]+)%`)
+	statsAverageIvlPattern   = regexp.MustCompile(`Average interval:\s*([^\n]+)`)
+	statsStudiedTodayPattern = regexp.MustCompile(`Studied\s+([\d,]+)\s+cards?\s+in\s+([\d.]+)\s+(second|minute|hour|day)s?\s+today`)
+	statsCorrectPattern      = regexp.MustCompile(`Correct:\s*[\d.]+%\s*\(([\d,]+) of ([\d,]+)\)`)
+)
+
+// handleCollectionStats reports key metrics from Anki's statistics report
+func (a *AnkiMCPServer) handleCollectionStats(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	report, err := a.ankiClient.GetCollectionStatsHTML()
+	if err != nil {
+		return a.errorf("Failed to get collection statistics: %v", err), nil
+	}
+	stats, ok := parseCollectionStats(report)
+	if !ok {
+		return a.errorf("Could not read any metrics from Anki's statistics report. They are only recognized when Anki's interface language is English."), nil
+	}
+
+	if wantsJSON(request) {
+		return a.jsonResult(stats), nil
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Collection statistics"))
+	out.Item(a.t("Cards: %d in %d note(s)", stats.TotalCards, stats.TotalNotes))
+	out.Item(a.t("Mature cards: %d (%.1f%%)", stats.MatureCards, stats.MaturePercent))
+	if stats.AverageEase > 0 {
+		out.Item(a.t("Average ease: %.0f%%", stats.AverageEase))
+	}
+	if stats.AverageInterval != "" {
+		out.Item(a.t("Average interval: %s", stats.AverageInterval))
+	}
+	if stats.RetentionAnswers > 0 {
+		out.Item(a.t("Retention over the last month: %.1f%% of %d answers", stats.Retention, stats.RetentionAnswers))
+	}
+	out.Item(a.t("Studied today: %d card(s) in %s", stats.StudiedToday, (time.Duration(stats.StudyTimeToday) * time.Second).String()))
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// parseCollectionStats reads the metrics from the HTML of Anki's statistics
+// report. It reports false when none of them were found, e.g. because Anki's
+// interface is not in English.
+func parseCollectionStats(report string) (collectionStats, bool) {
+	text := statsBreakPattern.ReplaceAllString(report, "\n")
+	text = html.UnescapeString(htmlTagPattern.ReplaceAllString(text, ""))
+	text = statsSpacePattern.ReplaceAllString(text, " ")
+
+	var stats collectionStats
+	found := false
+	number := func(pattern *regexp.Regexp) int {
+		m := pattern.FindStringSubmatch(text)
+		if m == nil {
+			return 0
+		}
+		found = true
+		n, _ := strconv.Atoi(strings.ReplaceAll(m[1], ",", ""))
+		return n
+	}
+	stats.TotalCards = number(statsTotalCardsPattern)
+	stats.TotalNotes = number(statsTotalNotesPattern)
+	stats.MatureCards = number(statsMaturePattern)
+	if stats.TotalCards > 0 {
+		stats.MaturePercent = float64(stats.MatureCards) * 100 / float64(stats.TotalCards)
+	}
+	if m := statsAverageEasePattern.FindStringSubmatch(text); m != nil {
+		stats.AverageEase, _ = strconv.ParseFloat(m[1], 64)
+		found = true
+	}
+	if m := statsAverageIvlPattern.FindStringSubmatch(text); m != nil {
+		stats.AverageInterval = strings.TrimSpace(m[1])
+		found = true
+	}
+
+	// The answer buttons section reports correct answers separately for
+	// learning, young and mature cards
+	good := 0
+	for _, m := range statsCorrectPattern.FindAllStringSubmatch(text, -1) {
+		g, _ := strconv.Atoi(strings.ReplaceAll(m[1], ",", ""))
+		t, _ := strconv.Atoi(strings.ReplaceAll(m[2], ",", ""))
+		good += g
+		stats.RetentionAnswers += t
+		found = true
+	}
+	if stats.RetentionAnswers > 0 {
+		stats.Retention = float64(good) * 100 / float64(stats.RetentionAnswers)
+	}
+
+	if m := statsStudiedTodayPattern.FindStringSubmatch(text); m != nil {
+		stats.StudiedToday, _ = strconv.Atoi(strings.ReplaceAll(m[1], ",", ""))
+		amount, _ := strconv.ParseFloat(m[2], 64)
+		unit := map[string]float64{"second": 1, "minute": 60, "hour": 3600, "day": 86400}[m[3]]
+		stats.StudyTimeToday = int64(math.Round(amount * unit))
+		found = true
+	} else if strings.Contains(text, "No cards have been studied today") {
+		found = true
+	}
+
+	return stats, found
+}
+
+// reviewPeriod holds the reviews done in a day, week or month
+type reviewPeriod struct {
+	Start       time.Time `json:"-"`
+	Date        string    `json:"start_date"`
+	Reviews     int       `json:"reviews"`
+	DaysStudied int       `json:"days_studied"`
+}
+
+// handleReviewHistory reports the number of reviews per period
+func (a *AnkiMCPServer) handleReviewHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	start, end, err := parseDateRange(args, 30)
+	if err != nil {
+		return a.errorf("%v", err), nil
+	}
+	groupBy, _ := args["group_by"].(string)
+	if groupBy == "" {
+		groupBy = "day"
+	}
+
+	days, err := a.ankiClient.GetNumCardsReviewedByDay()
+	if err != nil {
+		return a.errorf("Failed to get review history: %v", err), nil
+	}
+	periods := groupReviewDays(days, start, end, groupBy)
+
+	total, studied := 0, 0
+	for _, p := range periods {
+		total += p.Reviews
+		studied += p.DaysStudied
+	}
+	dayCount := int(math.Round(end.Sub(start).Hours() / 24))
+
+	if wantsJSON(request) {
+		return a.jsonResult(map[string]interface{}{
+			"start_date":   start.Format(dateLayout),
+			"end_date":     end.AddDate(0, 0, -1).Format(dateLayout),
+			"group_by":     groupBy,
+			"reviews":      total,
+			"days":         dayCount,
+			"days_studied": studied,
+			"periods":      periods,
+		}), nil
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Review history from %s to %s", a.loc.FormatDate(start), a.loc.FormatDate(end.AddDate(0, 0, -1))))
+	out.Item(a.t("Reviews: %d", total))
+	out.Item(a.t("Days studied: %d of %d", studied, dayCount))
+	if studied > 0 {
+		out.Item(a.t("Average per day studied: %.1f reviews", float64(total)/float64(studied)))
+	}
+	switch groupBy {
+	case "week":
+		out.Heading(a.t("Reviews per week"))
+	case "month":
+		out.Heading(a.t("Reviews per month"))
+	default:
+		out.Heading(a.t("Reviews per day"))
+	}
+	for _, p := range periods {
+		var label string
+		switch groupBy {
+		case "week":
+			label = a.t("Week of %s", a.loc.FormatDate(p.Start))
+		case "month":
+			label = p.Start.Format("2006-01")
+		default:
+			label = a.loc.FormatDate(p.Start)
+		}
+		out.Item(a.t("%s: %d review(s)", label, p.Reviews))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// groupReviewDays adds up daily review counts in [start, end) by day, week
+// (starting on Monday) or month. Every period in the range is included, also
+// those without reviews.
+func groupReviewDays(days []DayReviews, start, end time.Time, groupBy string) []reviewPeriod {
+	periodStart := func(t time.Time) time.Time {
+		switch groupBy {
+		case "week":
+			return t.AddDate(0, 0, -(int(t.Weekday())+6)%7)
+		case "month":
+			return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		default:
+			return t
+		}
+	}
+
+	var periods []reviewPeriod
+	index := make(map[time.Time]int)
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		p := periodStart(day)
+		if _, ok := index[p]; !ok {
+			index[p] = len(periods)
+			periods = append(periods, reviewPeriod{Start: p, Date: p.Format(dateLayout)})
+		}
+	}
+
+	for _, d := range days {
+		day, err := time.ParseInLocation(dateLayout, d.Date, start.Location())
+		if err != nil || day.Before(start) || !day.Before(end) || d.Reviews == 0 {
+			continue
+		}
+		p := &periods[index[periodStart(day)]]
+		p.Reviews += d.Reviews
+		p.DaysStudied++
+	}
+	return periods
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAggregateTagStats(t *testing.T) {
+	cards := []CardInfo{
+		{CardID: 1, NoteID: 10, Factor: 2500, Lapses: 0},
+		{CardID: 2, NoteID: 10, Factor: 1300, Lapses: 4},
+		{CardID: 3, NoteID: 20, Factor: 0, Lapses: 0},
+	}
+	noteTags := map[int64][]string{
+		10: {"verbs", "hard"},
+		20: {"verbs"},
+	}
+	due := map[int64]bool{2: true}
+
+	stats := aggregateTagStats(cards, noteTags, due)
+	sortTagStats(stats, "ease")
+
+	if len(stats) != 2 {
+		t.Fatalf("Expected 2 tags, got %d", len(stats))
+	}
+
+	hard := stats[0]
+	if hard.Tag != "hard" {
+		t.Fatalf("Expected hardest tag first, got %s", hard.Tag)
+	}
+	if hard.Notes != 1 || hard.Cards != 2 || hard.Due != 1 {
+		t.Errorf("Unexpected counts for hard: %+v", hard)
+	}
+	if hard.AvgEase != 190 {
+		t.Errorf("Expected avg ease 190, got %.2f", hard.AvgEase)
+	}
+	if hard.AvgLapses != 2 {
+		t.Errorf("Expected avg lapses 2, got %.2f", hard.AvgLapses)
+	}
+
+	verbs := stats[1]
+	if verbs.Notes != 2 || verbs.Cards != 3 {
+		t.Errorf("Unexpected counts for verbs: %+v", verbs)
+	}
+	// The new card has no ease and must not drag the average down
+	if verbs.AvgEase != 190 {
+		t.Errorf("Expected avg ease 190 for verbs, got %.2f", verbs.AvgEase)
+	}
+}
+
+func TestComputeProgress(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(2024, 4, 1, 0, 0, 0, 0, time.Local)
+	before := start.AddDate(0, 0, -10).UnixMilli()
+	during := start.AddDate(0, 0, 5).UnixMilli()
+	after := end.AddDate(0, 0, 1).UnixMilli()
+
+	entries := []ReviewEntry{
+		// Card 1 was mature before the period and lapsed during it
+		{ReviewTime: before, CardID: 1, ButtonPressed: 3, NewInterval: 30, PreviousInterval: 10, ReviewType: 1},
+		{ReviewTime: during, CardID: 1, ButtonPressed: 1, NewInterval: -600, PreviousInterval: 30, ReviewType: 1, ReviewDuration: 4000},
+		// Card 2 was learned and matured during the period
+		{ReviewTime: during, CardID: 2, ButtonPressed: 3, NewInterval: 1, PreviousInterval: -600, ReviewType: 0, ReviewDuration: 3000},
+		{ReviewTime: during + 1, CardID: 2, ButtonPressed: 3, NewInterval: 25, PreviousInterval: 10, ReviewType: 1, ReviewDuration: 3000},
+		// Reviews after the period are ignored
+		{ReviewTime: after, CardID: 3, ButtonPressed: 3, NewInterval: 40, PreviousInterval: 15, ReviewType: 1},
+	}
+
+	report := computeProgress(entries, start, end)
+
+	if report.Reviews != 3 {
+		t.Errorf("Expected 3 reviews, got %d", report.Reviews)
+	}
+	if report.CardsStudied != 2 {
+		t.Errorf("Expected 2 cards studied, got %d", report.CardsStudied)
+	}
+	if report.CardsLearned != 1 {
+		t.Errorf("Expected 1 card learned, got %d", report.CardsLearned)
+	}
+	if report.CardsMatured != 1 {
+		t.Errorf("Expected 1 card matured, got %d", report.CardsMatured)
+	}
+	if report.MatureAtStart != 1 || report.MatureAtEnd != 1 {
+		t.Errorf("Expected 1 mature card at start and end, got %d and %d", report.MatureAtStart, report.MatureAtEnd)
+	}
+	if report.Retention != 0.5 {
+		t.Errorf("Expected retention 0.5, got %.2f", report.Retention)
+	}
+	if report.StudyTime != 10 {
+		t.Errorf("Expected 10s study time, got %d", report.StudyTime)
+	}
+}
+
+func TestParseCollectionStats(t *testing.T) {
+	report := `<center><h1>Today</h1>Studied <b>1,204 cards</b> in <b>1.5 hours</b> today (4.49s/card)<br>Again count: <b>12</b></center>
+<script>$.plot($("#cards"), [{"data": [[0, 830]], "label": "Mature: 830", "color": "#070"}]);</script>
+<table><tr><td>Correct: <b>90.00%</b><br>(90 of 100)</td><td>Correct: <b>80.00%</b><br>(240 of 300)</td></tr></table>
+<table><tr><td width=200 align=end>Total cards:</td><td align=start><b>2,000</b></td></tr>
+<tr><td width=200 align=end>Total notes:</td><td align=start><b>1,000</b></td></tr>
+<tr><td width=200 align=end>Average ease:</td><td align=start><b>245%</b></td></tr>
+<tr><td width=200 align=end>Average interval:</td><td align=start><b>1.2 months</b></td></tr></table>`
+
+	stats, ok := parseCollectionStats(report)
+	if !ok {
+		t.Fatal("Expected metrics to be found")
+	}
+	want := collectionStats{
+		TotalCards:       2000,
+		TotalNotes:       1000,
+		MatureCards:      830,
+		MaturePercent:    41.5,
+		AverageEase:      245,
+		AverageInterval:  "1.2 months",
+		Retention:        82.5,
+		RetentionAnswers: 400,
+		StudiedToday:     1204,
+		StudyTimeToday:   5400,
+	}
+	if stats != want {
+		t.Errorf("Unexpected stats:\n got %+v\nwant %+v", stats, want)
+	}
+
+	if _, ok := parseCollectionStats("<h1>Heute</h1>Keine Karten gelernt."); ok {
+		t.Error("Expected no metrics in a report in another language")
+	}
+}
+
+func TestCollectionStats(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	text, isErr := callTool(t, server.handleCollectionStats, nil)
+	if isErr || !strings.Contains(text, "Cards: 14 in 7 note(s)") || !strings.Contains(text, "Studied today: 0 card(s)") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	text, _ = callTool(t, server.handleCollectionStats, map[string]interface{}{"format": "json"})
+	if !strings.Contains(text, `"retention_answers":21`) {
+		t.Errorf("Expected all 21 reviews in the retention, got: %s", text)
+	}
+}
+
+func TestGroupReviewDays(t *testing.T) {
+	days := []DayReviews{
+		{Date: "2024-05-14", Reviews: 5},
+		{Date: "2024-05-13", Reviews: 10},
+		{Date: "2024-05-06", Reviews: 20},
+		{Date: "2024-04-30", Reviews: 99},
+	}
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(2024, 5, 15, 0, 0, 0, 0, time.Local)
+
+	daily := groupReviewDays(days, start, end, "day")
+	if len(daily) != 14 || daily[12].Reviews != 10 || daily[0].Reviews != 0 {
+		t.Errorf("Unexpected daily periods: %+v", daily)
+	}
+
+	weekly := groupReviewDays(days, start, end, "week")
+	want := []reviewPeriod{
+		{Date: "2024-04-29", Reviews: 0},
+		{Date: "2024-05-06", Reviews: 20, DaysStudied: 1},
+		{Date: "2024-05-13", Reviews: 15, DaysStudied: 2},
+	}
+	if len(weekly) != len(want) {
+		t.Fatalf("Expected %d weeks, got %+v", len(want), weekly)
+	}
+	for i, w := range want {
+		if weekly[i].Date != w.Date || weekly[i].Reviews != w.Reviews || weekly[i].DaysStudied != w.DaysStudied {
+			t.Errorf("Week %d: expected %+v, got %+v", i, w, weekly[i])
+		}
+	}
+
+	if monthly := groupReviewDays(days, start, end, "month"); len(monthly) != 1 || monthly[0].Reviews != 35 {
+		t.Errorf("Unexpected monthly periods: %+v", monthly)
+	}
+}
+
+func TestReviewHistory(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	args := map[string]interface{}{
+		"start_date": time.Now().AddDate(0, 0, -40).Format(dateLayout),
+		"group_by":   "week",
+		"format":     "json",
+	}
+	text, isErr := callTool(t, server.handleReviewHistory, args)
+	if isErr || !strings.Contains(text, `"reviews":21`) || !strings.Contains(text, `"days":41`) {
+		t.Errorf("Unexpected output: %s", text)
+	}
+
+	delete(args, "format")
+	text, _ = callTool(t, server.handleReviewHistory, args)
+	if !strings.Contains(text, "Reviews per week") || !strings.Contains(text, "Week of ") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+}
@@ -0,0 +1,418 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// defaultDueLimit is the number of due cards returned when no limit is given
+	defaultDueLimit = 20
+	// cardTextLength is the maximum length of question and answer text in
+	// tool output
+	cardTextLength = 200
+)
+
+var (
+	styleBlockPattern = regexp.MustCompile(`(?is)<style.*?</style>`)
+	answerSeparator   = regexp.MustCompile(`(?i)<hr id=["']?answer["']?>`)
+)
+
+// dueCard is the JSON representation of a card in a get_due_cards result
+type dueCard struct {
+	CardID   int64   `json:"card_id"`
+	NoteID   int64   `json:"note_id"`
+	Deck     string  `json:"deck"`
+	Question string  `json:"question"`
+	Answer   string  `json:"answer"`
+	State    string  `json:"state"`
+	Interval int     `json:"interval"`
+	Ease     float64 `json:"ease"`
+	Reps     int     `json:"reps"`
+	Lapses   int     `json:"lapses"`
+}
+
+// registerStudyTools registers study session tools with the MCP server
+func (a *AnkiMCPServer) registerStudyTools(s *server.MCPServer) {
+	// Tool: Get Due Cards
+	getDueCardsTool := mcp.NewTool("get_due_cards",
+		mcp.WithDescription("Get the cards due for study today with their question and answer text and scheduling info, to run a study session in the chat. "+
+			"Review cards are capped by each deck's daily review limit minus the cards already reviewed today; learning cards are always included."),
+		mcp.WithString("deck",
+			mcp.Description("Optional: Only cards in this deck and its subdecks"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Optional: Maximum number of cards to return (default: 20)"),
+		),
+		withFormat(),
+	)
+	a.addTool(s, getDueCardsTool, (*AnkiMCPServer).handleGetDueCards)
+
+	// Tool: Start Review
+	startReviewTool := mcp.NewTool("start_review",
+		mcp.WithDescription("Open the review screen of a deck in the Anki window and show its first card's question. "+
+			"Reviews done this way are scheduled by Anki itself, exactly as if the user studied in Anki. "+
+			"Continue with show_answer and answer_card."),
+		mcp.WithString("deck",
+			mcp.Required(),
+			mcp.Description("Name of the deck to review"),
+		),
+	)
+	a.addTool(s, startReviewTool, (*AnkiMCPServer).handleStartReview)
+
+	// Tool: Review Current Card
+	reviewCurrentCardTool := mcp.NewTool("review_current_card",
+		mcp.WithDescription("Show the question of the card currently in Anki's review screen, with the answer buttons and their next intervals."),
+	)
+	a.addTool(s, reviewCurrentCardTool, (*AnkiMCPServer).handleReviewCurrentCard)
+
+	// Tool: Show Answer
+	showAnswerTool := mcp.NewTool("show_answer",
+		mcp.WithDescription("Reveal the answer of the card currently in Anki's review screen."),
+	)
+	a.addTool(s, showAnswerTool, (*AnkiMCPServer).handleShowAnswer)
+
+	// Tool: Answer Card
+	answerCardTool := mcp.NewTool("answer_card",
+		mcp.WithDescription("Answer the card currently in Anki's review screen and move on to the next card. "+
+			"Grade honestly from the user's response: 1 = Again (forgot), 2 = Hard, 3 = Good, 4 = Easy. The answer is revealed first if needed."),
+		mcp.WithNumber("ease",
+			mcp.Required(),
+			mcp.Description("Answer button: 1 = Again, 2 = Hard, 3 = Good, 4 = Easy"),
+			mcp.Min(1),
+			mcp.Max(4),
+		),
+	)
+	a.addChangingTool(s, answerCardTool, (*AnkiMCPServer).handleAnswerCard)
+}
+
+// handleGetDueCards lists the cards due today within the decks' limits
+func (a *AnkiMCPServer) handleGetDueCards(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	query := "is:due -is:suspended -is:buried"
+	if deck, ok := args["deck"].(string); ok && deck != "" {
+		query += " " + deckQuery(deck)
+	}
+	limit := defaultDueLimit
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	cardIDs, err := a.ankiClient.FindCards(query)
+	if err != nil {
+		return a.errorf("Failed to find cards: %v", err), nil
+	}
+	infos, err := a.ankiClient.GetCardsInfo(cardIDs)
+	if err != nil {
+		return a.errorf("Failed to get card info: %v", err), nil
+	}
+
+	// Learning cards come first, then reviews, each in due order
+	sort.SliceStable(infos, func(i, j int) bool {
+		li, lj := isLearning(infos[i]), isLearning(infos[j])
+		if li != lj {
+			return li
+		}
+		return infos[i].Due < infos[j].Due
+	})
+
+	remaining, err := a.remainingReviews(infos)
+	if err != nil {
+		return a.errorf("Failed to get deck options: %v", err), nil
+	}
+
+	var cards []dueCard
+	for _, info := range infos {
+		deck := info.DeckName
+		if !isLearning(info) {
+			if remaining[deck] <= 0 {
+				continue
+			}
+			remaining[deck]--
+		}
+		cards = append(cards, dueCard{
+			CardID:   info.CardID,
+			NoteID:   info.NoteID,
+			Deck:     deck,
+			Question: cardText(info.Question),
+			Answer:   cardText(answerText(info.Answer)),
+			State:    cardStateName(info),
+			Interval: info.Interval,
+			Ease:     float64(info.Factor) / 10,
+			Reps:     info.Reps,
+			Lapses:   info.Lapses,
+		})
+	}
+	total := len(cards)
+	cards = cards[:min(total, limit)]
+
+	if wantsJSON(request) {
+		return a.jsonResult(map[string]interface{}{
+			"due":   total,
+			"cards": cards,
+		}), nil
+	}
+
+	out := a.newOutput()
+	if total == 0 {
+		out.Line(a.t("No cards are due"))
+	} else {
+		out.Heading(a.t("Due cards: showing %d of %d", len(cards), total))
+		for _, c := range cards {
+			out.Item(a.t("%d [%s, %s]: %s → %s (interval: %d day(s), reps: %d, lapses: %d)",
+				c.CardID, c.Deck, a.cardStateLabel(c.State), c.Question, c.Answer, c.Interval, c.Reps, c.Lapses))
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleStartReview opens a deck's review screen
+func (a *AnkiMCPServer) handleStartReview(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	deck, ok := args["deck"].(string)
+	if !ok || deck == "" {
+		return a.errorf("deck is required"), nil
+	}
+
+	started, err := a.ankiClient.GuiDeckReview(deck)
+	if err != nil {
+		return a.errorf("Failed to start review: %v", err), nil
+	}
+	if !started {
+		return a.errorf("Deck not found: %s", deck), nil
+	}
+
+	out := a.newOutput()
+	out.Line(a.t("Started reviewing %s", deck))
+	if err := a.writeCurrentCard(out, false); err != nil {
+		return a.errorf("Failed to get the current card: %v", err), nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleReviewCurrentCard shows the question of the card being reviewed
+func (a *AnkiMCPServer) handleReviewCurrentCard(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	out := a.newOutput()
+	if err := a.writeCurrentCard(out, false); err != nil {
+		return a.errorf("Failed to get the current card: %v", err), nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleShowAnswer reveals the answer of the card being reviewed
+func (a *AnkiMCPServer) handleShowAnswer(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	shown, err := a.ankiClient.GuiShowAnswer()
+	if err != nil {
+		return a.errorf("Failed to show the answer: %v", err), nil
+	}
+	if !shown {
+		return a.errorf("No card is being reviewed. Call start_review first."), nil
+	}
+
+	out := a.newOutput()
+	if err := a.writeCurrentCard(out, true); err != nil {
+		return a.errorf("Failed to get the current card: %v", err), nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// handleAnswerCard answers the card being reviewed and shows the next one
+func (a *AnkiMCPServer) handleAnswerCard(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	value, ok := args["ease"].(float64)
+	ease := int(value)
+	if !ok || ease < 1 || ease > 4 || float64(ease) != value {
+		return a.errorf("ease must be 1 (Again), 2 (Hard), 3 (Good) or 4 (Easy)"), nil
+	}
+
+	card, err := a.ankiClient.GuiCurrentCard()
+	if err != nil {
+		return a.errorf("Failed to get the current card: %v", err), nil
+	}
+	if card == nil {
+		return a.errorf("No card is being reviewed. Call start_review first."), nil
+	}
+	// Anki only accepts an answer once the answer side is shown
+	if _, err := a.ankiClient.GuiShowAnswer(); err != nil {
+		return a.errorf("Failed to show the answer: %v", err), nil
+	}
+	answered, err := a.ankiClient.GuiAnswerCard(ease)
+	if err != nil {
+		return a.errorf("Failed to answer card: %v", err), nil
+	}
+	if !answered {
+		return a.errorf("Anki did not accept the answer %d for this card", ease), nil
+	}
+
+	out := a.newOutput()
+	out.Line(a.t("Answered card %d with %s", card.CardID, a.easeLabel(ease)))
+	if err := a.writeCurrentCard(out, false); err != nil {
+		return a.errorf("Failed to get the current card: %v", err), nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// writeCurrentCard describes the card in the review screen, with its answer
+// when withAnswer is set
+func (a *AnkiMCPServer) writeCurrentCard(out *textOutput, withAnswer bool) error {
+	card, err := a.ankiClient.GuiCurrentCard()
+	if err != nil {
+		return err
+	}
+	if card == nil {
+		out.Line(a.t("No card is being reviewed: the session is finished or no review was started."))
+		return nil
+	}
+
+	out.Heading(a.t("Card %d (%s)", card.CardID, card.DeckName))
+	out.Item(a.t("Question: %s", cardText(card.Question)))
+	if withAnswer {
+		out.Item(a.t("Answer: %s", cardText(answerText(card.Answer))))
+		var buttons []string
+		for i, b := range card.Buttons {
+			label := fmt.Sprintf("%d = %s", b, a.easeLabel(b))
+			if i < len(card.NextReviews) {
+				label = fmt.Sprintf("%d = %s (%s)", b, a.easeLabel(b), card.NextReviews[i])
+			}
+			buttons = append(buttons, label)
+		}
+		if len(buttons) > 0 {
+			out.Item(a.t("Buttons: %s", strings.Join(buttons, ", ")))
+		}
+	}
+	return nil
+}
+
+// easeLabel returns the localized name of an answer button
+func (a *AnkiMCPServer) easeLabel(ease int) string {
+	switch ease {
+	case 1:
+		return a.t("Again")
+	case 2:
+		return a.t("Hard")
+	case 3:
+		return a.t("Good")
+	default:
+		return a.t("Easy")
+	}
+}
+
+// remainingReviews returns how many more review cards each deck of the given
+// cards may show today, from its preset's review limit and today's reviews
+func (a *AnkiMCPServer) remainingReviews(cards []CardInfo) (map[string]int, error) {
+	var decks []string
+	for _, card := range cards {
+		if !slices.Contains(decks, card.DeckName) {
+			decks = append(decks, card.DeckName)
+		}
+	}
+	if len(decks) == 0 {
+		return map[string]int{}, nil
+	}
+
+	configs, err := a.ankiClient.GetDeckConfigs(decks)
+	if err != nil {
+		return nil, err
+	}
+	remaining := make(map[string]int, len(decks))
+	for _, deck := range decks {
+		reviewed, err := a.ankiClient.FindCards(deckQuery(deck) + " -" + deckQuery(deck+"::*") + " rated:1")
+		if err != nil {
+			return nil, err
+		}
+		remaining[deck] = configLimits(configs[deck]).Reviews - len(reviewed)
+	}
+	return remaining, nil
+}
+
+// isLearning reports whether a cardsInfo entry is in (re)learning
+func isLearning(card CardInfo) bool {
+	return card.Queue == 1 || card.Queue == 3
+}
+
+// cardStateName returns the English name of a card's state
+func cardStateName(card CardInfo) string {
+	switch card.Type {
+	case 0:
+		return "new"
+	case 1:
+		return "learning"
+	case 3:
+		return "relearning"
+	default:
+		return "review"
+	}
+}
+
+// cardStateLabel returns the localized name of a card state
+func (a *AnkiMCPServer) cardStateLabel(state string) string {
+	switch state {
+	case "new":
+		return a.t("new")
+	case "learning":
+		return a.t("learning")
+	case "relearning":
+		return a.t("relearning")
+	default:
+		return a.t("review")
+	}
+}
+
+// answerText returns the part of a rendered answer below the question
+func answerText(answerHTML string) string {
+	if loc := answerSeparator.FindStringIndex(answerHTML); loc != nil {
+		return answerHTML[loc[1]:]
+	}
+	return answerHTML
+}
+
+// cardText converts a rendered card side into plain text
+func cardText(cardHTML string) string {
+	return plainText(styleBlockPattern.ReplaceAllString(cardHTML, ""), cardTextLength)
+}
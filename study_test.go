@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnswerText(t *testing.T) {
+	html := `<style>.card { color: black; }</style>perro<hr id=answer>dog`
+	if got := cardText(answerText(html)); got != "dog" {
+		t.Errorf("Unexpected answer text: %q", got)
+	}
+	if got := cardText(html); got != "perro dog" {
+		t.Errorf("Unexpected card text: %q", got)
+	}
+}
+
+func TestGetDueCards(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	text, isErr := callTool(t, server.handleGetDueCards, map[string]interface{}{"deck": "Spanish"})
+	if isErr || !strings.Contains(text, "showing 4 of 4") || !strings.Contains(text, "el perro → the dog") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+
+	text, _ = callTool(t, server.handleGetDueCards, map[string]interface{}{"limit": float64(1)})
+	if !strings.Contains(text, "showing 1 of 4") {
+		t.Errorf("Expected the limit to apply, got: %s", text)
+	}
+
+	// The deck's review limit caps the review cards
+	mock.deckConfigs[1]["rev"].(map[string]interface{})["perDay"] = float64(3)
+	text, _ = callTool(t, server.handleGetDueCards, map[string]interface{}{"format": "json"})
+	if !strings.Contains(text, `"due":3`) {
+		t.Errorf("Expected the review limit to apply, got: %s", text)
+	}
+}
+
+func TestReviewSession(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	if text, isErr := callTool(t, server.handleReviewCurrentCard, nil); isErr || !strings.Contains(text, "No card is being reviewed") {
+		t.Errorf("Expected no card before starting, got: %s", text)
+	}
+	if text, isErr := callTool(t, server.handleStartReview, map[string]interface{}{"deck": "Missing"}); !isErr {
+		t.Errorf("Expected an error for a missing deck, got: %s", text)
+	}
+
+	text, isErr := callTool(t, server.handleStartReview, map[string]interface{}{"deck": "Spanish::Vocabulary"})
+	if isErr || !strings.Contains(text, "Question:") || strings.Contains(text, "Answer:") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	text, isErr = callTool(t, server.handleShowAnswer, nil)
+	if isErr || !strings.Contains(text, "Answer:") || !strings.Contains(text, "3 = Good") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+
+	for i := 0; i < 4; i++ {
+		text, isErr = callTool(t, server.handleAnswerCard, map[string]interface{}{"ease": float64(3)})
+		if isErr || !strings.Contains(text, "with Good") {
+			t.Fatalf("Unexpected output: %s", text)
+		}
+	}
+	if !strings.Contains(text, "session is finished") {
+		t.Errorf("Expected the session to end after the due cards, got: %s", text)
+	}
+	if len(mock.reviews) != 25 {
+		t.Errorf("Expected 4 new reviews, got %d in total", len(mock.reviews))
+	}
+
+	if text, isErr := callTool(t, server.handleAnswerCard, map[string]interface{}{"ease": float64(5)}); !isErr {
+		t.Errorf("Expected an invalid ease error, got: %s", text)
+	}
+}
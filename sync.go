@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// syncState records when a sync with AnkiWeb was last started through this
+// server. AnkiConnect only starts syncs and doesn't expose Anki's own sync
+// time, so neither their outcome nor syncs started from the Anki window are
+// known.
+type syncState struct {
+	LastSync time.Time `json:"last_sync"`
+}
+
+// registerSyncTools registers AnkiWeb sync tools with the MCP server
+func (a *AnkiMCPServer) registerSyncTools(s *server.MCPServer) {
+	// Tool: Sync
+	syncTool := mcp.NewTool("sync",
+		mcp.WithDescription("Start syncing the collection with AnkiWeb, as with the Sync button in Anki. Anki syncs in the background, so the tool returns before the sync is done. "+
+			"Call it at the end of a session that changed the collection."),
+	)
+	a.addChangingTool(s, syncTool, (*AnkiMCPServer).handleSync)
+
+	// Tool: Sync Status
+	syncStatusTool := mcp.NewTool("sync_status",
+		mcp.WithDescription("Report when a sync was last started through this server's sync tool and how many notes were added or edited and cards reviewed since then, "+
+			"to decide whether to call sync at the end of a session. Only syncs started by this server are known: syncs from the Anki window or other tools are not, "+
+			"and whether a started sync succeeded isn't known either."),
+		withFormat(),
+	)
+	a.addTool(s, syncStatusTool, (*AnkiMCPServer).handleSyncStatus)
+}
+
+// handleSync starts a sync and records the time
+func (a *AnkiMCPServer) handleSync(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := a.ankiClient.Sync(); err != nil {
+		return a.errorf("Failed to sync: %v", err), nil
+	}
+
+	text := a.t("Sync with AnkiWeb started. Anki syncs in the background and shows in its window when it is done or if it failed.")
+	if err := a.saveSyncState(syncState{LastSync: time.Now()}); err != nil {
+		text += "\n" + a.t("Warning: could not record the sync time: %v", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}, nil
+}
+
+// handleSyncStatus reports changes made since the last recorded sync
+func (a *AnkiMCPServer) handleSyncStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	state, err := a.loadSyncState()
+	if err != nil {
+		return a.errorf("Failed to read sync state: %v", err), nil
+	}
+
+	// Without a recorded sync, report today's changes instead
+	since := state.LastSync
+	if since.IsZero() {
+		since = time.Now().Add(-24 * time.Hour)
+	}
+	days := int(time.Since(since).Hours()/24) + 1
+
+	// Both searches go out in one request
+	var (
+		notes   []NoteInfo
+		cardIDs []int64
+	)
+	b := a.ankiClient.newBatch()
+	b.add("notesInfo", map[string]string{"query": fmt.Sprintf("edited:%d", days)}, &notes)
+	b.add("findCards", map[string]string{"query": fmt.Sprintf("rated:%d", days)}, &cardIDs)
+	if err := b.send(); err != nil {
+		return a.errorf("Failed to find changes: %v", err), nil
+	}
+	editedNotes := 0
+	for _, note := range notes {
+		if note.Mod >= since.Unix() {
+			editedNotes++
+		}
+	}
+
+	cards, err := a.ankiClient.GetCardsInfo(cardIDs)
+	if err != nil {
+		return a.errorf("Failed to get card info: %v", err), nil
+	}
+	reviewedCards := 0
+	for _, card := range cards {
+		if card.Mod >= since.Unix() {
+			reviewedCards++
+		}
+	}
+
+	syncNeeded := editedNotes > 0 || reviewedCards > 0
+
+	if wantsJSON(request) {
+		var lastSync interface{}
+		if !state.LastSync.IsZero() {
+			lastSync = state.LastSync.Format(time.RFC3339)
+		}
+		return a.jsonResult(map[string]interface{}{
+			"last_sync":      lastSync,
+			"changes_since":  since.Format(time.RFC3339),
+			"edited_notes":   editedNotes,
+			"reviewed_cards": reviewedCards,
+			"sync_needed":    syncNeeded,
+		}), nil
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Sync status"))
+	if state.LastSync.IsZero() {
+		out.Item(a.t("Last sync started through this server: none recorded; showing changes from the last 24 hours"))
+	} else {
+		out.Item(a.t("Last sync started through this server: %s", state.LastSync.Format("2006-01-02 15:04")))
+	}
+	out.Item(a.t("Notes added or edited: %d", editedNotes))
+	out.Item(a.t("Cards reviewed: %d", reviewedCards))
+	if syncNeeded {
+		out.Line(a.t("There are local changes; call sync to upload them to AnkiWeb."))
+	} else {
+		out.Line(a.t("No local changes found."))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
+
+// syncStatePath returns the file recording the last sync
+func (a *AnkiMCPServer) syncStatePath() string {
+	return filepath.Join(a.stateDir, "sync.json")
+}
+
+// loadSyncState reads the recorded sync state
+func (a *AnkiMCPServer) loadSyncState() (syncState, error) {
+	var state syncState
+	data, err := os.ReadFile(a.syncStatePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("invalid state file %s: %w", a.syncStatePath(), err)
+	}
+	return state, nil
+}
+
+// saveSyncState records the sync state, except in a dry run
+func (a *AnkiMCPServer) saveSyncState(state syncState) error {
+	if a.dryRun {
+		return nil
+	}
+	if err := os.MkdirAll(a.stateDir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(a.syncStatePath(), data, 0o644)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSyncStatus(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	text, isErr := callTool(t, server.handleSyncStatus, map[string]interface{}{})
+	if isErr || !strings.Contains(text, "none recorded") || !strings.Contains(text, "Notes added or edited: 7") {
+		t.Fatalf("Unexpected output before syncing: %s", text)
+	}
+
+	if text, isErr := callTool(t, server.handleSync, map[string]interface{}{}); isErr || !strings.Contains(text, "Sync with AnkiWeb started") {
+		t.Fatalf("sync failed: %s", text)
+	}
+	// Modification times have a resolution of one second
+	for _, note := range mock.notes {
+		note.Mod--
+	}
+	for _, card := range mock.cards {
+		card.Mod--
+	}
+
+	text, isErr = callTool(t, server.handleSyncStatus, map[string]interface{}{"format": "json"})
+	if isErr || !strings.Contains(text, `"sync_needed":false`) || !strings.Contains(text, `"edited_notes":0`) {
+		t.Errorf("Unexpected output after syncing: %s", text)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerTagTools registers tag management tools with the MCP server
+func (a *AnkiMCPServer) registerTagTools(s *server.MCPServer) {
+	// Tool: Add Tags
+	addTagsTool := mcp.NewTool("add_tags",
+		mcp.WithDescription("Add tags to notes selected by ID or by an Anki search query"),
+		mcp.WithArray("tags",
+			mcp.Required(),
+			mcp.Description("Tags to add; tags can't contain spaces, use :: for hierarchical tags"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithArray("note_ids",
+			mcp.Description("IDs of the notes to tag"),
+			mcp.WithNumberItems(),
+		),
+		mcp.WithString("query",
+			mcp.Description("Anki search query selecting the notes to tag, instead of note_ids"),
+		),
+	)
+	a.addChangingTool(s, addTagsTool, (*AnkiMCPServer).handleAddTags)
+
+	// Tool: Remove Tags
+	removeTagsTool := mcp.NewTool("remove_tags",
+		mcp.WithDescription("Remove tags from notes selected by ID or by an Anki search query"),
+		mcp.WithArray("tags",
+			mcp.Required(),
+			mcp.Description("Tags to remove"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithArray("note_ids",
+			mcp.Description("IDs of the notes to untag"),
+			mcp.WithNumberItems(),
+		),
+		mcp.WithString("query",
+			mcp.Description("Anki search query selecting the notes to untag, instead of note_ids"),
+		),
+	)
+	a.addChangingTool(s, removeTagsTool, (*AnkiMCPServer).handleRemoveTags)
+
+	// Tool: Replace Tag
+	replaceTagTool := mcp.NewTool("replace_tag",
+		mcp.WithDescription("Replace a tag with another one, e.g. to fix a typo or merge two tags. Applies to every note with the tag unless note_ids or query narrows it down."),
+		mcp.WithString("tag",
+			mcp.Required(),
+			mcp.Description("Tag to replace"),
+		),
+		mcp.WithString("new_tag",
+			mcp.Required(),
+			mcp.Description("Tag to use instead"),
+		),
+		mcp.WithArray("note_ids",
+			mcp.Description("Optional: Only replace the tag on these notes"),
+			mcp.WithNumberItems(),
+		),
+		mcp.WithString("query",
+			mcp.Description("Optional: Only replace the tag on notes matching this Anki search query"),
+		),
+	)
+	a.addChangingTool(s, replaceTagTool, (*AnkiMCPServer).handleReplaceTag)
+
+	// Tool: List Tags
+	listTagsTool := mcp.NewTool("list_tags",
+		mcp.WithDescription("List the tags used in the collection"),
+		mcp.WithString("filter",
+			mcp.Description("Optional: Only list tags containing this text"),
+		),
+		withFormat(),
+	)
+	a.addTool(s, listTagsTool, (*AnkiMCPServer).handleListTags)
+}
+
+// handleAddTags adds tags to notes
+func (a *AnkiMCPServer) handleAddTags(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return a.changeTags(request, true), nil
+}
+
+// handleRemoveTags removes tags from notes
+func (a *AnkiMCPServer) handleRemoveTags(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return a.changeTags(request, false), nil
+}
+
+// changeTags adds or removes the requested tags on the selected notes
+func (a *AnkiMCPServer) changeTags(request mcp.CallToolRequest, add bool) *mcp.CallToolResult {
+	args := request.GetArguments()
+
+	tags := stringSliceValue(args, "tags")
+	if len(tags) == 0 {
+		return a.errorf("tags is required")
+	}
+	for _, tag := range tags {
+		if !validTag(tag) {
+			return a.errorf("Invalid tag %q: tags can't be empty or contain spaces", tag)
+		}
+	}
+	noteIDs, errResult := a.selectNotes(args)
+	if errResult != nil {
+		return errResult
+	}
+	if len(noteIDs) == 0 {
+		return a.errorf("No notes found")
+	}
+
+	var text string
+	if add {
+		if err := a.ankiClient.AddTags(noteIDs, tags); err != nil {
+			return a.errorf("Failed to add tags: %v", err)
+		}
+		text = a.t("Added %s to %d note(s)", strings.Join(tags, ", "), len(noteIDs))
+	} else {
+		if err := a.ankiClient.RemoveTags(noteIDs, tags); err != nil {
+			return a.errorf("Failed to remove tags: %v", err)
+		}
+		text = a.t("Removed %s from %d note(s)", strings.Join(tags, ", "), len(noteIDs))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}
+}
+
+// handleReplaceTag replaces a tag with another on the notes that have it
+func (a *AnkiMCPServer) handleReplaceTag(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	tag, _ := args["tag"].(string)
+	if strings.TrimSpace(tag) == "" {
+		return a.errorf("tag is required"), nil
+	}
+	newTag, _ := args["new_tag"].(string)
+	if !validTag(newTag) {
+		return a.errorf("Invalid tag %q: tags can't be empty or contain spaces", newTag), nil
+	}
+
+	query, _ := args["query"].(string)
+	var noteIDs []int64
+	if len(numberSliceValue(args, "note_ids")) > 0 || strings.TrimSpace(query) != "" {
+		ids, errResult := a.selectNotes(args)
+		if errResult != nil {
+			return errResult, nil
+		}
+		noteIDs = ids
+	} else {
+		ids, err := a.ankiClient.FindNotes(tagQuery(tag))
+		if err != nil {
+			return a.errorf("Failed to find notes: %v", err), nil
+		}
+		noteIDs = ids
+	}
+
+	// A tag search also matches child tags, so count only the notes that
+	// carry the tag itself
+	infos, err := a.ankiClient.GetNotesInfo(noteIDs)
+	if err != nil {
+		return a.errorf("Failed to get note info: %v", err), nil
+	}
+	var tagged []int64
+	for i, info := range infos {
+		for _, t := range info.Tags {
+			if strings.EqualFold(t, tag) {
+				tagged = append(tagged, noteIDs[i])
+				break
+			}
+		}
+	}
+
+	if len(tagged) > 0 {
+		if err := a.ankiClient.ReplaceTags(tagged, tag, newTag); err != nil {
+			return a.errorf("Failed to replace tag: %v", err), nil
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: a.t("Replaced tag %s with %s on %d note(s)", tag, newTag, len(tagged)),
+			},
+		},
+	}, nil
+}
+
+// handleListTags lists the tags in the collection
+func (a *AnkiMCPServer) handleListTags(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	filter, _ := args["filter"].(string)
+
+	all, err := a.ankiClient.GetTags()
+	if err != nil {
+		return a.errorf("Failed to get tags: %v", err), nil
+	}
+	tags := []string{}
+	for _, tag := range all {
+		if strings.Contains(strings.ToLower(tag), strings.ToLower(filter)) {
+			tags = append(tags, tag)
+		}
+	}
+
+	if wantsJSON(request) {
+		return a.jsonResult(map[string]interface{}{"tags": tags}), nil
+	}
+
+	out := a.newOutput()
+	out.Heading(a.t("Tags (%d)", len(tags)))
+	for _, tag := range tags {
+		out.Item(tag)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
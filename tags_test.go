@@ -0,0 +1,73 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTagTools(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	text, isErr := callTool(t, server.handleAddTags, map[string]interface{}{
+		"tags":  []interface{}{"verbs", "level::a1"},
+		"query": "deck:Spanish::Vocabulary",
+	})
+	if isErr || text != "Added verbs, level::a1 to 6 note(s)" {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	if text, isErr := callTool(t, server.handleAddTags, map[string]interface{}{"tags": []interface{}{"two words"}, "query": "*"}); !isErr {
+		t.Errorf("Expected an invalid tag error, got: %s", text)
+	}
+
+	text, isErr = callTool(t, server.handleReplaceTag, map[string]interface{}{"tag": "verbs", "new_tag": "vocab"})
+	if isErr || text != "Replaced tag verbs with vocab on 6 note(s)" {
+		t.Errorf("Unexpected output: %s", text)
+	}
+
+	notes, _ := server.ankiClient.FindNotes("deck:Spanish::Vocabulary")
+	text, isErr = callTool(t, server.handleRemoveTags, map[string]interface{}{
+		"tags":     []interface{}{"vocab"},
+		"note_ids": []interface{}{float64(notes[0])},
+	})
+	if isErr || text != "Removed vocab from 1 note(s)" {
+		t.Errorf("Unexpected output: %s", text)
+	}
+
+	text, _ = callTool(t, server.handleListTags, nil)
+	for _, tag := range []string{"grammar", "level::a1", "vocab"} {
+		if !strings.Contains(text, tag) {
+			t.Errorf("Expected %s in the tag list, got: %s", tag, text)
+		}
+	}
+	text, _ = callTool(t, server.handleListTags, map[string]interface{}{"filter": "LEVEL", "format": "json"})
+	if text != `{"tags":["level::a1"]}` {
+		t.Errorf("Unexpected JSON: %s", text)
+	}
+}
+
+func TestClearUnusedTags(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	if text, _ := callTool(t, server.handleClearUnusedTags, nil); text != "No unused tags found" {
+		t.Errorf("Unexpected output: %s", text)
+	}
+
+	notes, _ := server.ankiClient.FindNotes("tag:ser-estar")
+	if err := server.ankiClient.RemoveTags(notes, []string{"ser-estar"}); err != nil {
+		t.Fatal(err)
+	}
+	if tags, _ := server.ankiClient.GetTags(); !slices.Contains(tags, "ser-estar") {
+		t.Fatalf("Expected the removed tag to stay in the tag list, got %v", tags)
+	}
+
+	text, isErr := callTool(t, server.handleClearUnusedTags, nil)
+	if isErr || !strings.Contains(text, "Removed 1 unused tag(s)") || !strings.Contains(text, "ser-estar") {
+		t.Errorf("Unexpected output: %s", text)
+	}
+	if tags, _ := server.ankiClient.GetTags(); slices.Contains(tags, "ser-estar") || !slices.Contains(tags, "grammar") {
+		t.Errorf("Expected only the unused tag to be cleared, got %v", tags)
+	}
+}
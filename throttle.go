@@ -0,0 +1,128 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultMaxConcurrent is the number of AnkiConnect requests in flight at
+// once unless ANKI_CONNECT_MAX_CONCURRENT says otherwise
+const defaultMaxConcurrent = 4
+
+// throttleNoteThreshold is how long the requests of a tool call must have
+// waited in total before its response says it was throttled
+const throttleNoteThreshold = 500 * time.Millisecond
+
+// RateLimit caps how hard AnkiConnect is driven, so agents issuing many tool
+// calls in parallel don't freeze the Anki window. Requests over the limit
+// queue until they may start.
+type RateLimit struct {
+	// MaxConcurrent is the number of requests in flight at once; 0 means no
+	// cap
+	MaxConcurrent int
+	// PerSecond is the number of requests started per second; 0 means no
+	// limit
+	PerSecond float64
+}
+
+// rateLimiter queues AnkiConnect requests according to a RateLimit
+type rateLimiter struct {
+	// slots holds a token for every request in flight
+	slots chan struct{}
+	// interval is the time between two request starts
+	interval time.Duration
+
+	mu sync.Mutex
+	// next is when the next request may start
+	next time.Time
+}
+
+// newRateLimiter creates a rate limiter, or returns nil if the limit doesn't
+// limit anything
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	if limit.MaxConcurrent <= 0 && limit.PerSecond <= 0 {
+		return nil
+	}
+	l := &rateLimiter{}
+	if limit.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, limit.MaxConcurrent)
+	}
+	if limit.PerSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / limit.PerSecond)
+	}
+	return l
+}
+
+// wait blocks until a request may start. It returns how long it waited and a
+// function to call once the request is done.
+func (l *rateLimiter) wait() (time.Duration, func()) {
+	start := time.Now()
+	if l.slots != nil {
+		l.slots <- struct{}{}
+	}
+	if l.interval > 0 {
+		l.mu.Lock()
+		now := time.Now()
+		at := l.next
+		if at.Before(now) {
+			at = now
+		}
+		l.next = at.Add(l.interval)
+		l.mu.Unlock()
+		time.Sleep(at.Sub(now))
+	}
+	return time.Since(start), func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+}
+
+// throttleLog adds up how long the requests of one tool call waited for the
+// rate limiter
+type throttleLog struct {
+	waited atomic.Int64
+}
+
+// add records a wait
+func (t *throttleLog) add(d time.Duration) {
+	t.waited.Add(int64(d))
+}
+
+// total returns the total wait
+func (t *throttleLog) total() time.Duration {
+	return time.Duration(t.waited.Load())
+}
+
+// withThrottleLog returns a copy of the client that records in log how long
+// its requests waited
+func (ac *AnkiConnect) withThrottleLog(log *throttleLog) *AnkiConnect {
+	client := *ac
+	client.throttled = log
+	return &client
+}
+
+// noteThrottled tells the client that a tool call was slowed down by the rate
+// limit: in a line after the text output, or in the result's _meta for JSON
+// output
+func (a *AnkiMCPServer) noteThrottled(request mcp.CallToolRequest, result *mcp.CallToolResult, waited time.Duration) {
+	if wantsJSON(request) {
+		if result.Meta == nil {
+			result.Meta = make(map[string]any)
+		}
+		result.Meta["throttled_ms"] = waited.Milliseconds()
+		return
+	}
+	note := a.t("Throttled: this call waited %s for AnkiConnect, which takes only so many requests at once", waited.Round(100*time.Millisecond))
+	for i := len(result.Content) - 1; i >= 0; i-- {
+		if text, ok := result.Content[i].(mcp.TextContent); ok {
+			text.Text += "\n\n" + note
+			result.Content[i] = text
+			return
+		}
+	}
+	result.Content = append(result.Content, mcp.TextContent{Type: "text", Text: note})
+}
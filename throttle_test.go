@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterConcurrency(t *testing.T) {
+	if newRateLimiter(RateLimit{}) != nil {
+		t.Error("Expected no limiter without a limit")
+	}
+
+	limiter := newRateLimiter(RateLimit{MaxConcurrent: 2})
+	var inFlight, most atomic.Int32
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, done := limiter.wait()
+			defer done()
+			n := inFlight.Add(1)
+			for {
+				m := most.Load()
+				if n <= m || most.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			inFlight.Add(-1)
+		}()
+	}
+	wg.Wait()
+	if got := most.Load(); got != 2 {
+		t.Errorf("Expected at most 2 requests in flight, got %d", got)
+	}
+}
+
+func TestRateLimiterSpacing(t *testing.T) {
+	limiter := newRateLimiter(RateLimit{PerSecond: 20})
+	start := time.Now()
+	var waited time.Duration
+	for range 3 {
+		w, done := limiter.wait()
+		done()
+		waited += w
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected 3 requests at 20 per second to take 100ms, took %s", elapsed)
+	}
+	if waited < 90*time.Millisecond {
+		t.Errorf("Expected the waits to add up to 100ms, got %s", waited)
+	}
+}
+
+func TestThrottledNote(t *testing.T) {
+	c, _ := newIntegrationClientWith(t, func(config *Config) {
+		config.RateLimit = RateLimit{PerSecond: 1}
+	})
+
+	text, isErr := callMCPTool(t, c, "list_tags", map[string]interface{}{})
+	if isErr || strings.Contains(text, "Throttled") {
+		t.Errorf("Expected the first call to go through, got %s", text)
+	}
+	text, isErr = callMCPTool(t, c, "list_tags", map[string]interface{}{})
+	if isErr || !strings.Contains(text, "spanish") || !strings.Contains(text, "Throttled: this call waited") {
+		t.Errorf("Expected the tags and a throttled note, got %s", text)
+	}
+}
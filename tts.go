@@ -0,0 +1,382 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Text-to-speech backends
+const (
+	ttsGoogle = "google"
+	ttsAzure  = "azure"
+	ttsLocal  = "local"
+)
+
+// maxTTSText is the longest text, in characters, that is synthesized
+const maxTTSText = 1000
+
+// TTSConfig selects and configures the text-to-speech backend
+type TTSConfig struct {
+	// Backend is google, azure or local; empty disables generate_tts
+	Backend string `json:"backend"`
+	// APIKey authenticates with Google or Azure. ANKI_MCP_TTS_API_KEY
+	// overrides it, so the key doesn't have to be stored in the file.
+	APIKey string `json:"api_key"`
+	// Region is the Azure Speech resource region, e.g. westeurope
+	Region string `json:"region"`
+	// Endpoint replaces the backend's default URL
+	Endpoint string `json:"endpoint"`
+	// Voices maps language codes to the default voice for that language,
+	// e.g. {"es-ES": "es-ES-ElviraNeural"}
+	Voices map[string]string `json:"voices"`
+	// Command is the local synthesizer command. The arguments {text},
+	// {lang}, {voice} and {output} are replaced; the command must write the
+	// audio to {output}. The text is also written to the command's stdin.
+	Command []string `json:"command"`
+	// Format is the extension of the audio the local command writes
+	// (default: wav)
+	Format string `json:"format"`
+}
+
+// validate checks that the selected backend has the settings it needs
+func (c TTSConfig) validate() error {
+	switch c.Backend {
+	case "", ttsGoogle:
+	case ttsAzure:
+		if c.Region == "" && c.Endpoint == "" {
+			return fmt.Errorf("tts: azure needs region or endpoint")
+		}
+	case ttsLocal:
+		if len(c.Command) == 0 {
+			return fmt.Errorf("tts: local needs command")
+		}
+	default:
+		return fmt.Errorf("tts: unknown backend %q, use google, azure or local", c.Backend)
+	}
+	return nil
+}
+
+// voiceFor returns the configured voice for a language, trying the language
+// without its region too
+func (c TTSConfig) voiceFor(lang string) string {
+	if voice, ok := c.Voices[lang]; ok {
+		return voice
+	}
+	base, _, _ := strings.Cut(lang, "-")
+	return c.Voices[base]
+}
+
+// ttsBackend turns text into audio
+type ttsBackend interface {
+	// Synthesize returns the audio for text spoken in lang, a BCP 47 code
+	// such as es-ES, and the file extension of its format
+	Synthesize(ctx context.Context, text, lang, voice string) ([]byte, string, error)
+}
+
+// newTTSBackend creates the backend selected by the config
+func newTTSBackend(c TTSConfig) (ttsBackend, error) {
+	apiKey := c.APIKey
+	if key := os.Getenv("ANKI_MCP_TTS_API_KEY"); key != "" {
+		apiKey = key
+	}
+	client := &http.Client{Timeout: 60 * time.Second}
+
+	switch c.Backend {
+	case ttsGoogle:
+		if apiKey == "" {
+			return nil, fmt.Errorf("google needs an API key")
+		}
+		endpoint := c.Endpoint
+		if endpoint == "" {
+			endpoint = "https://texttospeech.googleapis.com/v1/text:synthesize"
+		}
+		return googleTTS{client: client, endpoint: endpoint, apiKey: apiKey}, nil
+	case ttsAzure:
+		if apiKey == "" {
+			return nil, fmt.Errorf("azure needs an API key")
+		}
+		endpoint := c.Endpoint
+		if endpoint == "" {
+			endpoint = fmt.Sprintf("https://%s.tts.speech.microsoft.com/cognitiveservices/v1", c.Region)
+		}
+		return azureTTS{client: client, endpoint: endpoint, apiKey: apiKey}, nil
+	case ttsLocal:
+		format := c.Format
+		if format == "" {
+			format = "wav"
+		}
+		return localTTS{command: c.Command, format: strings.TrimPrefix(format, ".")}, nil
+	}
+	return nil, fmt.Errorf("no text-to-speech backend is configured")
+}
+
+// googleTTS uses the Google Cloud Text-to-Speech REST API
+type googleTTS struct {
+	client   *http.Client
+	endpoint string
+	apiKey   string
+}
+
+func (g googleTTS) Synthesize(ctx context.Context, text, lang, voice string) ([]byte, string, error) {
+	voiceParams := map[string]string{"languageCode": lang}
+	if voice != "" {
+		voiceParams["name"] = voice
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"input":       map[string]string{"text": text},
+		"voice":       voiceParams,
+		"audioConfig": map[string]string{"audioEncoding": "MP3"},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Goog-Api-Key", g.apiKey)
+
+	data, err := ttsRequest(g.client, req)
+	if err != nil {
+		return nil, "", err
+	}
+	var result struct {
+		AudioContent string `json:"audioContent"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, "", fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	audio, err := base64.StdEncoding.DecodeString(result.AudioContent)
+	if err != nil || len(audio) == 0 {
+		return nil, "", fmt.Errorf("response has no audio")
+	}
+	return audio, "mp3", nil
+}
+
+// azureTTS uses the Azure AI Speech REST API, which needs a voice name
+type azureTTS struct {
+	client   *http.Client
+	endpoint string
+	apiKey   string
+}
+
+func (az azureTTS) Synthesize(ctx context.Context, text, lang, voice string) ([]byte, string, error) {
+	if voice == "" {
+		return nil, "", fmt.Errorf("azure needs a voice; pass voice or configure one for %s", lang)
+	}
+	ssml := fmt.Sprintf(`<speak version="1.0" xml:lang="%s"><voice name="%s">%s</voice></speak>`,
+		html.EscapeString(lang), html.EscapeString(voice), html.EscapeString(text))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, az.endpoint, strings.NewReader(ssml))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Content-Type", "application/ssml+xml")
+	req.Header.Set("Ocp-Apim-Subscription-Key", az.apiKey)
+	req.Header.Set("X-Microsoft-OutputFormat", "audio-24khz-96kbitrate-mono-mp3")
+	req.Header.Set("User-Agent", "anki-mcp")
+
+	audio, err := ttsRequest(az.client, req)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(audio) == 0 {
+		return nil, "", fmt.Errorf("response has no audio")
+	}
+	return audio, "mp3", nil
+}
+
+// ttsRequest sends a request to a TTS service and returns the response body
+func ttsRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxMediaDownload))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+// localTTS runs a synthesizer installed on this machine, such as espeak-ng
+// or piper
+type localTTS struct {
+	command []string
+	format  string
+}
+
+func (l localTTS) Synthesize(ctx context.Context, text, lang, voice string) ([]byte, string, error) {
+	dir, err := os.MkdirTemp("", "anki-mcp-tts-")
+	if err != nil {
+		return nil, "", err
+	}
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "speech."+l.format)
+
+	replacer := strings.NewReplacer("{text}", text, "{lang}", lang, "{voice}", voice, "{output}", output)
+	args := make([]string, 0, len(l.command)+1)
+	for i, arg := range l.command {
+		// Text starting with - would be read as an option, so end the
+		// options before it
+		if i > 0 && arg == "{text}" && strings.HasPrefix(text, "-") && args[len(args)-1] != "--" {
+			args = append(args, "--")
+		}
+		args = append(args, replacer.Replace(arg))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, "", fmt.Errorf("%s: %v: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	audio, err := os.ReadFile(output)
+	if err != nil || len(audio) == 0 {
+		return nil, "", fmt.Errorf("%s wrote no audio to {output}", args[0])
+	}
+	return audio, l.format, nil
+}
+
+// registerTTSTools registers text-to-speech tools with the MCP server
+func (a *AnkiMCPServer) registerTTSTools(s *server.MCPServer) {
+	// Tool: Generate TTS
+	generateTTSTool := mcp.NewTool("generate_tts",
+		mcp.WithDescription("Generate pronunciation audio for a text with the text-to-speech backend from the config file, store it in Anki's media folder "+
+			"and optionally add it to a note field as [sound:...]."),
+		mcp.WithString("text",
+			mcp.Required(),
+			mcp.Description("Text to speak; HTML is removed"),
+		),
+		mcp.WithString("language",
+			mcp.Required(),
+			mcp.Description("Language of the text as a BCP 47 code, e.g. es-ES, de-DE or fr-FR"),
+		),
+		mcp.WithString("voice",
+			mcp.Description("Optional: Voice name of the backend (default: the voice configured for the language)"),
+		),
+		mcp.WithString("filename",
+			mcp.Description("Optional: Name to store the audio under (default: derived from the text, so the same text reuses its file)"),
+		),
+		mcp.WithNumber("note_id",
+			mcp.Description("Optional: Note to add the audio to"),
+		),
+		mcp.WithString("field",
+			mcp.Description("Optional: Field of the note to append [sound:...] to (default: the first field)"),
+		),
+	)
+	a.addChangingTool(s, generateTTSTool, (*AnkiMCPServer).handleGenerateTTS)
+}
+
+// handleGenerateTTS synthesizes speech, stores it and optionally adds it to a note
+func (a *AnkiMCPServer) handleGenerateTTS(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	text, _ := args["text"].(string)
+	text = plainText(text, maxTTSText+1)
+	if text == "" {
+		return a.errorf("text is required"), nil
+	}
+	if len([]rune(text)) > maxTTSText {
+		return a.errorf("text is longer than %d characters", maxTTSText), nil
+	}
+	lang, _ := args["language"].(string)
+	if strings.TrimSpace(lang) == "" {
+		return a.errorf("language is required"), nil
+	}
+	voice, _ := args["voice"].(string)
+	if voice == "" {
+		voice = a.tts.voiceFor(lang)
+	}
+
+	// Check the note before paying for the synthesis
+	var noteID int64
+	var field, current string
+	if id, ok := args["note_id"].(float64); ok {
+		noteID = int64(id)
+		infos, err := a.ankiClient.GetNotesInfo([]int64{noteID})
+		if err != nil {
+			return a.errorf("Failed to get note info: %v", err), nil
+		}
+		if len(infos) == 0 || infos[0].NoteID == 0 {
+			return a.errorf("Note not found: %d", noteID), nil
+		}
+		names := noteFieldNames(infos[0])
+		field, _ = args["field"].(string)
+		if field == "" && len(names) > 0 {
+			field = names[0]
+		}
+		value, ok := noteField(infos[0], field)
+		if !ok {
+			return a.errorf("Note %d has no field %s. Fields: %s", noteID, field, strings.Join(names, ", ")), nil
+		}
+		current = value
+	}
+
+	backend, err := newTTSBackend(a.tts)
+	if err != nil {
+		return a.errorf("Text-to-speech is not available: %v. Configure the tts section of the config file.", err), nil
+	}
+	audio, ext, err := backend.Synthesize(ctx, text, lang, voice)
+	if err != nil {
+		return a.errorf("Failed to generate speech: %v", err), nil
+	}
+
+	filename, _ := args["filename"].(string)
+	if filename == "" {
+		sum := sha1.Sum([]byte(a.tts.Backend + "\x00" + lang + "\x00" + voice + "\x00" + text))
+		filename = fmt.Sprintf("tts-%s-%x.%s", lang, sum[:6], ext)
+	}
+	media, err := a.ankiClient.StoreMediaFile(filename, audio)
+	if err != nil {
+		return a.errorf("Failed to store %s: %v", filename, err), nil
+	}
+
+	out := a.newOutput()
+	out.Line(a.t("Generated %s (%d bytes)", media.Filename, media.Size))
+	sound := fmt.Sprintf("[sound:%s]", media.Filename)
+	if noteID != 0 {
+		if strings.Contains(current, sound) {
+			out.Line(a.t("Field %s of note %d already plays it", field, noteID))
+		} else {
+			if err := a.ankiClient.UpdateNoteFields(noteID, map[string]string{field: current + sound}); err != nil {
+				return a.errorf("Failed to update note %d: %v", noteID, err), nil
+			}
+			out.Line(a.t("Added to field %s of note %d", field, noteID))
+		}
+	} else {
+		out.Line(a.t("Reference: %s", sound))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestTTSConfigValidate(t *testing.T) {
+	tests := []struct {
+		config TTSConfig
+		ok     bool
+	}{
+		{TTSConfig{}, true},
+		{TTSConfig{Backend: ttsGoogle, APIKey: "key"}, true},
+		{TTSConfig{Backend: ttsAzure}, false},
+		{TTSConfig{Backend: ttsAzure, Region: "westeurope"}, true},
+		{TTSConfig{Backend: ttsLocal}, false},
+		{TTSConfig{Backend: "polly"}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.config.validate(); (err == nil) != tt.ok {
+			t.Errorf("validate(%+v) = %v, want ok=%v", tt.config, err, tt.ok)
+		}
+	}
+
+	voices := TTSConfig{Voices: map[string]string{"es": "es-ES-ElviraNeural"}}
+	if got := voices.voiceFor("es-MX"); got != "es-ES-ElviraNeural" {
+		t.Errorf("voiceFor(es-MX) = %q", got)
+	}
+}
+
+func TestGoogleTTS(t *testing.T) {
+	web := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Input struct{ Text string } `json:"input"`
+			Voice map[string]string     `json:"voice"`
+			Audio map[string]string     `json:"audioConfig"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || r.Header.Get("X-Goog-Api-Key") != "secret" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		audio := "MP3 " + body.Input.Text + " " + body.Voice["languageCode"]
+		_ = json.NewEncoder(w).Encode(map[string]string{"audioContent": base64.StdEncoding.EncodeToString([]byte(audio))})
+	}))
+	defer web.Close()
+
+	backend, err := newTTSBackend(TTSConfig{Backend: ttsGoogle, APIKey: "secret", Endpoint: web.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	audio, ext, err := backend.Synthesize(context.Background(), "hola", "es-ES", "")
+	if err != nil || string(audio) != "MP3 hola es-ES" || ext != "mp3" {
+		t.Errorf("Synthesize() = %q, %q, %v", audio, ext, err)
+	}
+
+	backend, _ = newTTSBackend(TTSConfig{Backend: ttsGoogle, APIKey: "wrong", Endpoint: web.URL})
+	if _, _, err := backend.Synthesize(context.Background(), "hola", "es-ES", ""); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("Expected a request error, got %v", err)
+	}
+}
+
+func TestAzureTTS(t *testing.T) {
+	web := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ssml, _ := io.ReadAll(r.Body)
+		if r.Header.Get("Ocp-Apim-Subscription-Key") != "secret" || !strings.Contains(string(ssml), `<voice name="de-DE-KatjaNeural">Tschüss &amp; bis bald</voice>`) {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte("MP3"))
+	}))
+	defer web.Close()
+
+	backend, err := newTTSBackend(TTSConfig{Backend: ttsAzure, APIKey: "secret", Endpoint: web.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if audio, _, err := backend.Synthesize(context.Background(), "Tschüss & bis bald", "de-DE", "de-DE-KatjaNeural"); err != nil || string(audio) != "MP3" {
+		t.Errorf("Synthesize() = %q, %v", audio, err)
+	}
+	if _, _, err := backend.Synthesize(context.Background(), "Hallo", "de-DE", ""); err == nil || !strings.Contains(err.Error(), "needs a voice") {
+		t.Errorf("Expected a voice error, got %v", err)
+	}
+}
+
+func TestLocalTTS(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no sh to stand in for a synthesizer")
+	}
+	// The command writes its arguments and stdin as the audio
+	tts := localTTS{command: []string{"sh", "-c", `{ printf '%s\n' "$@"; cat; } > "$0"`, "{output}", "-v", "{lang}", "{text}"}, format: "wav"}
+
+	audio, format, err := tts.Synthesize(context.Background(), "hola", "es", "")
+	if err != nil || format != "wav" || string(audio) != "-v\nes\nhola\nhola" {
+		t.Errorf("Synthesize() = %q, %s, %v", audio, format, err)
+	}
+	// Text starting with - must not be read as an option
+	audio, _, err = tts.Synthesize(context.Background(), "-h", "es", "")
+	if err != nil || string(audio) != "-v\nes\n--\n-h\n-h" {
+		t.Errorf("Synthesize() = %q, %v", audio, err)
+	}
+}
+
+func TestGenerateTTS(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+
+	args := map[string]interface{}{"text": "hola", "language": "es-ES"}
+	if text, isErr := callTool(t, server.handleGenerateTTS, args); !isErr || !strings.Contains(text, "Text-to-speech is not available") {
+		t.Errorf("Expected a configuration error, got: %s", text)
+	}
+
+	// The local backend writes the text and language as the audio
+	server.tts = TTSConfig{Backend: ttsLocal, Command: []string{"sh", "-c", `printf '%s/%s' "$1" "$2" > "$3"`, "sh", "{text}", "{lang}", "{output}"}}
+	text, isErr := callTool(t, server.handleGenerateTTS, args)
+	if isErr || !strings.Contains(text, "(10 bytes)") || !strings.Contains(text, "Reference: [sound:tts-es-ES-") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+
+	var noteID int64
+	for id, note := range mock.notes {
+		if note.Fields["Front"] == "el perro" {
+			noteID = id
+		}
+	}
+	args = map[string]interface{}{"text": "<b>perro</b>", "language": "es-ES", "note_id": float64(noteID), "field": "Front", "filename": "perro.wav"}
+	text, isErr = callTool(t, server.handleGenerateTTS, args)
+	if isErr || !strings.Contains(text, "Added to field Front of note") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	if string(mock.media["perro.wav"]) != "perro/es-ES" || !strings.HasSuffix(mock.notes[noteID].Fields["Front"], "[sound:perro.wav]") {
+		t.Errorf("Unexpected media %q or field %q", mock.media["perro.wav"], mock.notes[noteID].Fields["Front"])
+	}
+	if text, _ := callTool(t, server.handleGenerateTTS, args); !strings.Contains(text, "already plays it") {
+		t.Errorf("Expected the sound to be added only once, got: %s", text)
+	}
+
+	args["field"] = "Pronunciation"
+	if text, isErr := callTool(t, server.handleGenerateTTS, args); !isErr || !strings.Contains(text, "has no field Pronunciation") {
+		t.Errorf("Expected a field error, got: %s", text)
+	}
+}
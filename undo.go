@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// changeHistorySize is the number of recent changes kept for undo
+const changeHistorySize = 50
+
+// notUndoableActions change things Anki's undo doesn't cover: files, AnkiWeb
+// or the whole database
+var notUndoableActions = map[string]bool{
+	"deleteMediaFile":  true,
+	"exportPackage":    true,
+	"guiCheckDatabase": true,
+	"guiUndo":          true,
+	"storeMediaFile":   true,
+	"sync":             true,
+}
+
+// changeHistory remembers the recent changes made through a client, newest
+// last, so undo can tell which one it took back. It is shared by the copies
+// of the client.
+type changeHistory struct {
+	mu      sync.Mutex
+	changes []recordedChange
+}
+
+// recordedChange is a change sent to AnkiConnect
+type recordedChange struct {
+	dryRunChange
+	At time.Time
+}
+
+// record adds the undoable changes of a successful request. The actions of a
+// multi request are recorded one by one, leaving out those that failed.
+func (h *changeHistory) record(action string, params interface{}, result json.RawMessage) {
+	now := time.Now()
+	var changes []recordedChange
+	if action == "multi" {
+		actions, _ := params.(map[string]interface{})["actions"].([]ankiRequest)
+		var responses []ankiResponse
+		_ = decodeResult(result, &responses)
+		for i, inner := range actions {
+			if i < len(responses) && responses[i].Error == "" && changingActions[inner.Action] && !notUndoableActions[inner.Action] {
+				changes = append(changes, recordedChange{dryRunChange{inner.Action, inner.Params}, now})
+			}
+		}
+	} else if changingActions[action] && !notUndoableActions[action] {
+		changes = append(changes, recordedChange{dryRunChange{action, params}, now})
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.changes = append(h.changes, changes...)
+	if extra := len(h.changes) - changeHistorySize; extra > 0 {
+		h.changes = append([]recordedChange(nil), h.changes[extra:]...)
+	}
+}
+
+// pop removes and returns the newest change. A nil history has none.
+func (h *changeHistory) pop() (recordedChange, bool) {
+	if h == nil {
+		return recordedChange{}, false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.changes) == 0 {
+		return recordedChange{}, false
+	}
+	last := h.changes[len(h.changes)-1]
+	h.changes = h.changes[:len(h.changes)-1]
+	return last, true
+}
+
+// registerUndoTools registers the tool that undoes the last change
+func (a *AnkiMCPServer) registerUndoTools(s *server.MCPServer) {
+	// Tool: Undo
+	undoTool := mcp.NewTool("undo",
+		mcp.WithDescription("Undo the last operation in Anki, as with Edit > Undo, and report the change made through this server that it most likely took back. "+
+			"Each call undoes one step; a tool call that made several changes, such as creating a card with tags, needs several undos. "+
+			"Media files, exports and syncs can't be undone."),
+	)
+	a.addChangingTool(s, undoTool, (*AnkiMCPServer).handleUndo)
+}
+
+// handleUndo undoes the last operation in Anki and reports the newest change
+// this server made, which is the one undone unless the user changed something
+// in Anki since then
+func (a *AnkiMCPServer) handleUndo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	undone, err := a.ankiClient.GuiUndo()
+	if err != nil {
+		return a.errorf("Failed to undo: %v", err), nil
+	}
+	if !undone {
+		return a.errorf("Anki has nothing to undo"), nil
+	}
+
+	out := a.newOutput()
+	out.Line(a.t("Undid the last operation in Anki."))
+	if change, ok := a.ankiClient.history.pop(); ok {
+		description := a.describeChanges([]dryRunChange{change.dryRunChange})[0]
+		out.Line(a.t("The last change made through this server, %s ago, was: %s", time.Since(change.At).Round(time.Second), description))
+		out.Line(a.t("That change was undone, unless the user changed something in Anki after it."))
+	} else {
+		out.Line(a.t("No change made through this server is left to undo, so the operation undone was made in Anki itself."))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
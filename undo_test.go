@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestUndo(t *testing.T) {
+	server, mock := newMockServer(t)
+	mock.seedDemo()
+	server.ankiClient.history = &changeHistory{}
+
+	if text, isErr := callTool(t, server.handleCreateDeck, map[string]interface{}{"name": "Italian"}); isErr {
+		t.Fatalf("Failed to create the deck: %s", text)
+	}
+	// Reads are not recorded
+	callTool(t, server.handleGetDeckTree, map[string]interface{}{})
+
+	text, isErr := callTool(t, server.handleUndo, map[string]interface{}{})
+	if isErr || !strings.Contains(text, "Undid the last operation") || !strings.Contains(text, "Italian") {
+		t.Errorf("Expected the deck creation to be reported, got %s", text)
+	}
+
+	text, isErr = callTool(t, server.handleUndo, map[string]interface{}{})
+	if isErr || !strings.Contains(text, "made in Anki itself") {
+		t.Errorf("Expected no change of this server to be left, got %s", text)
+	}
+}
+
+func TestChangeHistoryRecord(t *testing.T) {
+	var h changeHistory
+	params := map[string]interface{}{"actions": []ankiRequest{
+		{Action: "addTags", Params: map[string]interface{}{"notes": []int64{1}, "tags": "verb"}},
+		{Action: "deleteDecks", Params: map[string]interface{}{"decks": []string{"Missing"}}},
+		{Action: "storeMediaFile", Params: map[string]interface{}{"filename": "a.mp3"}},
+		{Action: "findNotes", Params: map[string]interface{}{"query": "deck:*"}},
+	}}
+	result, _ := json.Marshal([]ankiResponse{
+		{Result: json.RawMessage("null")},
+		{Error: "deck was not found"},
+		{Result: json.RawMessage(`"a.mp3"`)},
+		{Result: json.RawMessage("[1]")},
+	})
+	h.record("multi", params, result)
+	h.record("sync", nil, json.RawMessage("null"))
+
+	change, ok := h.pop()
+	if !ok || change.Action != "addTags" {
+		t.Errorf("Expected only the tags that were added to be recorded, got %+v", change)
+	}
+	if _, ok := h.pop(); ok {
+		t.Error("Expected the history to be empty")
+	}
+
+	for range changeHistorySize + 5 {
+		h.record("createDeck", map[string]interface{}{"deck": "Italian"}, json.RawMessage("1"))
+	}
+	if len(h.changes) != changeHistorySize {
+		t.Errorf("Expected the history to keep %d changes, got %d", changeHistorySize, len(h.changes))
+	}
+}
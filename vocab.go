@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// vocabModel is the note type create_vocab_card creates and uses when the
+// config file names none
+const vocabModel = "Vocabulary (anki-mcp)"
+
+// vocabInputs are the inputs of create_vocab_card in the order they are
+// joined when several go into the same field
+var vocabInputs = []string{"word", "reading", "translation", "example", "image", "audio"}
+
+// vocabModelFields are the fields of vocabModel for each input
+var vocabModelFields = map[string]string{
+	"word":        "Word",
+	"reading":     "Reading",
+	"translation": "Translation",
+	"example":     "Example",
+	"image":       "Image",
+	"audio":       "Audio",
+}
+
+// vocabModelReverseField makes vocabModel add the translation → word card
+// when it isn't empty
+const vocabModelReverseField = "Add Reverse"
+
+// vocabModelFieldNames are the fields of vocabModel in order
+var vocabModelFieldNames = []string{"Word", "Reading", "Translation", "Example", "Image", "Audio", vocabModelReverseField}
+
+// VocabConfig maps the inputs of create_vocab_card onto a note type of the
+// user's. Without a model the built-in vocabModel is used.
+type VocabConfig struct {
+	// Model is the note type of vocabulary cards
+	Model string `json:"model"`
+	// Fields maps the inputs word, reading, translation, example, image and
+	// audio to fields of the model; word and translation are needed
+	Fields map[string]string `json:"fields"`
+	// ReverseField is the field that makes the model add the translation →
+	// word card when it isn't empty, as in "Basic (optional reversed card)"
+	ReverseField string `json:"reverse_field"`
+}
+
+// validate checks that a custom note type maps the needed inputs
+func (c VocabConfig) validate() error {
+	if c.Model == "" {
+		if len(c.Fields) > 0 || c.ReverseField != "" {
+			return fmt.Errorf("vocab: fields and reverse_field need a model")
+		}
+		return nil
+	}
+	for input, field := range c.Fields {
+		if !slices.Contains(vocabInputs, input) {
+			return fmt.Errorf("vocab: unknown input %q, use %s", input, strings.Join(vocabInputs, ", "))
+		}
+		if strings.TrimSpace(field) == "" {
+			return fmt.Errorf("vocab: input %s needs a field", input)
+		}
+	}
+	if c.Fields["word"] == "" || c.Fields["translation"] == "" {
+		return fmt.Errorf("vocab: model %s needs fields for word and translation", c.Model)
+	}
+	return nil
+}
+
+// layout returns the note type, the field of each input and the reverse
+// field of vocabulary cards
+func (c VocabConfig) layout() (string, map[string]string, string) {
+	if c.Model == "" {
+		return vocabModel, vocabModelFields, vocabModelReverseField
+	}
+	return c.Model, c.Fields, c.ReverseField
+}
+
+// ensureVocabModel creates vocabModel if the collection doesn't have it, and
+// reports whether it had to be created. Its second card is only made when
+// the Add Reverse field isn't empty.
+func (a *AnkiMCPServer) ensureVocabModel() (bool, error) {
+	models, err := a.ankiClient.GetModelNames()
+	if err != nil {
+		return false, err
+	}
+	if slices.Contains(models, vocabModel) {
+		return false, nil
+	}
+
+	word := "<div class=word>{{Word}}</div>\n{{#Reading}}<div class=reading>{{Reading}}</div>{{/Reading}}\n{{Audio}}"
+	translation := "<div class=translation>{{Translation}}</div>\n{{#Image}}<div class=image>{{Image}}</div>{{/Image}}"
+	example := "{{#Example}}<div class=example>{{Example}}</div>{{/Example}}"
+	templates := []CardTemplate{
+		{Name: "Recognition", Front: word, Back: "{{FrontSide}}\n\n<hr id=answer>\n\n" + translation + "\n" + example},
+		{Name: "Production", Front: "{{#Add Reverse}}" + translation + "{{/Add Reverse}}", Back: "{{FrontSide}}\n\n<hr id=answer>\n\n" + word + "\n" + example},
+	}
+	css := ".card {\n    font-family: arial;\n    font-size: 20px;\n    text-align: center;\n    color: black;\n    background-color: white;\n}\n" +
+		".word {\n    font-size: 32px;\n}\n.reading, .example {\n    color: gray;\n}\n.example {\n    font-style: italic;\n    margin-top: 1em;\n}\n"
+	if err := a.ankiClient.CreateModel(vocabModel, vocabModelFieldNames, templates, css); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// registerVocabTools registers the vocabulary card tool with the MCP server
+func (a *AnkiMCPServer) registerVocabTools(s *server.MCPServer) {
+	// Tool: Create Vocab Card
+	createVocabCardTool := mcp.NewTool("create_vocab_card",
+		mcp.WithDescription("Create a vocabulary card for language learning from a word and its translation, with optional reading, example sentence, image and pronunciation audio. "+
+			"Makes a word → translation card and, unless bidirectional is false, a translation → word card. "+
+			"Uses the vocab note type from the config file, or the built-in \""+vocabModel+"\" note type, which is created if missing."),
+		mcp.WithString("deck",
+			mcp.Required(),
+			mcp.Description("Name of the deck"),
+		),
+		mcp.WithString("word",
+			mcp.Required(),
+			mcp.Description("Word or phrase in the language being learned"),
+		),
+		mcp.WithString("translation",
+			mcp.Required(),
+			mcp.Description("Meaning in the learner's language"),
+		),
+		mcp.WithString("reading",
+			mcp.Description("Optional: Pronunciation or reading, e.g. furigana, pinyin or IPA"),
+		),
+		mcp.WithString("example",
+			mcp.Description("Optional: Example sentence using the word"),
+		),
+		mcp.WithString("image_path",
+			mcp.Description("Optional: Path to an image illustrating the word"),
+		),
+		mcp.WithString("image_url",
+			mcp.Description("Optional: URL of an image to download instead of image_path"),
+		),
+		withImageOptions(),
+		mcp.WithString("audio_path",
+			mcp.Description("Optional: Path to pronunciation audio"),
+		),
+		mcp.WithString("audio_url",
+			mcp.Description("Optional: URL of pronunciation audio to download instead of audio_path"),
+		),
+		mcp.WithBoolean("bidirectional",
+			mcp.Description("Optional: Also make a translation → word card (default: true)"),
+		),
+		mcp.WithArray("tags",
+			mcp.Description("Optional: Tags for the note"),
+			mcp.WithStringItems(),
+		),
+		withDuplicateOptions(),
+	)
+	a.addChangingTool(s, createVocabCardTool, (*AnkiMCPServer).handleCreateVocabCard)
+}
+
+// handleCreateVocabCard maps a word and its details onto the vocab note type
+// and creates the note
+func (a *AnkiMCPServer) handleCreateVocabCard(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	deckName, ok := args["deck"].(string)
+	if !ok || strings.TrimSpace(deckName) == "" {
+		return a.errorf("deck is required"), nil
+	}
+	values := map[string]string{
+		"word":        strings.TrimSpace(stringValue(args, "word")),
+		"translation": strings.TrimSpace(stringValue(args, "translation")),
+		"reading":     strings.TrimSpace(stringValue(args, "reading")),
+		"example":     strings.TrimSpace(stringValue(args, "example")),
+	}
+	if values["word"] == "" || values["translation"] == "" {
+		return a.errorf("word and translation are required"), nil
+	}
+	tags := stringSliceValue(args, "tags")
+	for _, tag := range tags {
+		if !validTag(tag) {
+			return a.errorf("Invalid tag %q: tags can't be empty or contain spaces", tag), nil
+		}
+	}
+	options, errResult := a.noteOptions(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+	imageOpts, errResult := a.imageOptions(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	modelName, inputFields, reverseField := a.vocab.layout()
+	bidirectional, given := args["bidirectional"].(bool)
+	if !given {
+		bidirectional = true
+	}
+	if given && reverseField == "" {
+		return a.errorf("Note type %s decides itself which cards it makes. Set reverse_field in the vocab section of the config file to choose with bidirectional.", modelName), nil
+	}
+
+	// Check the fields before storing any media
+	createdModel := false
+	if modelName == vocabModel {
+		created, err := a.ensureVocabModel()
+		if err != nil {
+			return a.errorf("Failed to create note type %s: %v", vocabModel, err), nil
+		}
+		createdModel = created
+	}
+	// A dry run only records creating the note type, so its fields can't be
+	// asked for
+	modelFields := vocabModelFieldNames
+	if !createdModel {
+		var err error
+		if modelFields, err = a.ankiClient.GetModelFieldNames(modelName); err != nil {
+			return a.errorf("Failed to get fields: %v", err), nil
+		}
+	}
+	if len(modelFields) == 0 {
+		return a.errorf("Note type not found: %s", modelName), nil
+	}
+	var unknown []string
+	for _, field := range inputFields {
+		if !slices.Contains(modelFields, field) && !slices.Contains(unknown, field) {
+			unknown = append(unknown, field)
+		}
+	}
+	if reverseField != "" && !slices.Contains(modelFields, reverseField) {
+		unknown = append(unknown, reverseField)
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return a.errorf("Unknown field(s) for note type %s: %s. Fields: %s", modelName, strings.Join(unknown, ", "), strings.Join(modelFields, ", ")), nil
+	}
+	for _, input := range []string{"reading", "example"} {
+		if values[input] != "" && inputFields[input] == "" {
+			return a.errorf("The vocab note type %s has no field for %s", modelName, input), nil
+		}
+	}
+
+	// Send the card to the deck chosen by the tag routing rules
+	var route *RoutingRule
+	if a.routing.Auto {
+		if rule, ok := a.routing.deckFor(tags); ok && rule.Deck != deckName {
+			if err := a.ankiClient.CreateDeck(rule.Deck); err != nil {
+				return a.errorf("Failed to create deck: %v", err), nil
+			}
+			deckName = rule.Deck
+			route = &rule
+		}
+	}
+
+	names, storedMedia, errResult := a.storeCardMedia(ctx, []cardMedia{
+		{stringValue(args, "image_path"), stringValue(args, "image_url"), "image/", imageOpts},
+		{stringValue(args, "audio_path"), stringValue(args, "audio_url"), "audio/", imageOptions{}},
+	}, "")
+	if errResult != nil {
+		return errResult, nil
+	}
+	imageName, audioName := names[0], names[1]
+
+	// Inputs sharing a field are joined in input order; media without a
+	// field of its own goes above the translation (image) or below the word
+	// (audio), as in create_card
+	fields := make(map[string]string)
+	add := func(field, value string) {
+		if fields[field] != "" {
+			fields[field] += "<br>"
+		}
+		fields[field] += value
+	}
+	for _, input := range []string{"word", "reading", "translation", "example"} {
+		if values[input] != "" {
+			add(inputFields[input], values[input])
+		}
+	}
+	switch field := inputFields["image"]; {
+	case imageName == "":
+	case field != "":
+		add(field, fmt.Sprintf(`<img src="%s">`, imageName))
+	default:
+		fields[inputFields["translation"]] = formatContent(fields[inputFields["translation"]], imageName, "")
+	}
+	switch field := inputFields["audio"]; {
+	case audioName == "":
+	case field != "":
+		add(field, fmt.Sprintf("[sound:%s]", audioName))
+	default:
+		fields[inputFields["word"]] = formatContent(fields[inputFields["word"]], "", audioName)
+	}
+	if reverseField != "" {
+		fields[reverseField] = ""
+		if bidirectional {
+			fields[reverseField] = "y"
+		}
+	}
+
+	noteID, err := a.ankiClient.AddNote(Note{
+		DeckName:  deckName,
+		ModelName: modelName,
+		Fields:    fields,
+		Tags:      tags,
+		Options:   options,
+	})
+	if err != nil {
+		return a.errorf("Failed to create card: %v", err), nil
+	}
+
+	out := a.newOutput()
+	out.Line(a.t("Created vocabulary note (ID: %d) with the note type %s", noteID, modelName))
+	if createdModel {
+		out.Line(a.t("Created the missing note type %s", vocabModel))
+	}
+	if route != nil {
+		out.Line(a.t("Routed to deck %s by the tag rule %s", route.Deck, route.Tag))
+	}
+	out.Item(a.t("%s → %s", values["word"], values["translation"]))
+	if reverseField != "" && bidirectional {
+		out.Item(a.t("%s → %s", values["translation"], values["word"]))
+	}
+	a.describeStoredMedia(out, storedMedia)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: out.String(),
+			},
+		},
+	}, nil
+}
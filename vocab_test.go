@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCreateVocabCard(t *testing.T) {
+	server, mock := newMockServer(t)
+	audio := filepath.Join(t.TempDir(), "perro.mp3")
+	if err := os.WriteFile(audio, []byte("ID3 perro"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	args := map[string]interface{}{
+		"deck":        "Default",
+		"word":        "perro",
+		"translation": "dog",
+		"example":     "El perro ladra.",
+		"audio_path":  audio,
+	}
+	text, isErr := callTool(t, server.handleCreateVocabCard, args)
+	if isErr || !strings.Contains(text, "Created the missing note type "+vocabModel) || !strings.Contains(text, "dog → perro") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	noteID := latestNote(mock)
+	note := mock.notes[noteID]
+	if note.Fields["Word"] != "perro" || note.Fields["Audio"] != "[sound:perro.mp3]" || note.Fields["Add Reverse"] != "y" {
+		t.Errorf("Unexpected fields: %v", note.Fields)
+	}
+	if cards := mock.noteCards(noteID); len(cards) != 2 {
+		t.Errorf("Expected a card in each direction, got %d", len(cards))
+	}
+
+	args = map[string]interface{}{"deck": "Default", "word": "gato", "translation": "cat", "bidirectional": false}
+	if text, isErr := callTool(t, server.handleCreateVocabCard, args); isErr || strings.Contains(text, "cat → gato") {
+		t.Fatalf("Unexpected output: %s", text)
+	}
+	if cards := mock.noteCards(latestNote(mock)); len(cards) != 1 {
+		t.Errorf("Expected only the word → translation card, got %d", len(cards))
+	}
+}
+
+func TestCreateVocabCardCustomModel(t *testing.T) {
+	server, mock := newMockServer(t)
+	if err := server.ankiClient.CreateModel("Japanese", []string{"Expression", "Meaning", "Notes"}, []CardTemplate{{Name: "Card 1", Front: "{{Expression}}", Back: "{{Meaning}}"}}, ""); err != nil {
+		t.Fatal(err)
+	}
+	server.vocab = VocabConfig{Model: "Japanese", Fields: map[string]string{"word": "Expression", "reading": "Expression", "translation": "Meaning", "example": "Notes"}}
+
+	args := map[string]interface{}{"deck": "Default", "word": "犬", "reading": "いぬ", "translation": "dog"}
+	if text, isErr := callTool(t, server.handleCreateVocabCard, args); isErr {
+		t.Fatalf("create_vocab_card failed: %s", text)
+	}
+	if note := mock.notes[latestNote(mock)]; note.Fields["Expression"] != "犬<br>いぬ" || note.Fields["Meaning"] != "dog" {
+		t.Errorf("Unexpected fields: %v", note.Fields)
+	}
+
+	args["bidirectional"] = true
+	if text, isErr := callTool(t, server.handleCreateVocabCard, args); !isErr || !strings.Contains(text, "reverse_field") {
+		t.Errorf("Expected bidirectional to need a reverse field, got %s", text)
+	}
+	if err := (VocabConfig{Model: "Japanese", Fields: map[string]string{"word": "Expression"}}).validate(); err == nil {
+		t.Error("Expected a model without a translation field to be refused")
+	}
+}
+
+// latestNote returns the ID of the most recently added note
+func latestNote(mock *mockAnkiConnect) int64 {
+	var latest int64
+	for id := range mock.notes {
+		latest = max(latest, id)
+	}
+	return latest
+}